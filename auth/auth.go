@@ -0,0 +1,98 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package auth defines the authorization extension point of Trillian, and a
+// default, policy based, implementation of it.
+package auth
+
+import (
+	"context"
+	"crypto/x509"
+	"strings"
+
+	"github.com/google/trillian/trees"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// Authorizer decides whether the caller of an RPC may perform an operation on
+// a tree.
+type Authorizer interface {
+	// Authorize returns nil if the caller, as identified by ctx, is allowed to
+	// perform op on the tree identified by treeID. treeID is zero for requests
+	// that don't address an existing tree, such as CreateTree or ListTrees.
+	//
+	// Authorize returns an Unauthenticated status error if the caller could
+	// not be identified, and a PermissionDenied status error if the caller is
+	// not allowed to perform the operation.
+	Authorize(ctx context.Context, treeID int64, op trees.OpType) error
+}
+
+type allowAll struct{}
+
+// AllowAll returns an Authorizer that allows all requests without restriction.
+func AllowAll() Authorizer {
+	return allowAll{}
+}
+
+func (allowAll) Authorize(ctx context.Context, treeID int64, op trees.OpType) error {
+	return nil
+}
+
+// authorizationKey is the metadata key holding bearer tokens, as in
+// "authorization: Bearer <token>".
+const authorizationKey = "authorization"
+
+// CertSubject returns the subject of the verified TLS client certificate the
+// caller presented, as formatted by pkix.Name.String. It returns false if the
+// connection isn't using TLS, or the caller's certificate wasn't verified.
+func CertSubject(ctx context.Context) (string, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return "", false
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return "", false
+	}
+	cert := leafCert(info.State.VerifiedChains)
+	if cert == nil {
+		return "", false
+	}
+	return cert.Subject.String(), true
+}
+
+func leafCert(chains [][]*x509.Certificate) *x509.Certificate {
+	if len(chains) == 0 || len(chains[0]) == 0 {
+		return nil
+	}
+	return chains[0][0]
+}
+
+// BearerToken returns the token of the first "Bearer" authorization found in
+// the incoming metadata of ctx, if any.
+func BearerToken(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", false
+	}
+	const prefix = "bearer "
+	for _, v := range md.Get(authorizationKey) {
+		if len(v) > len(prefix) && strings.EqualFold(v[:len(prefix)], prefix) {
+			return strings.TrimSpace(v[len(prefix):]), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,96 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// certContext returns a context whose peer presented a verified client
+// certificate with the given subject.
+func certContext(ctx context.Context, subject pkix.Name) context.Context {
+	cert := &x509.Certificate{Subject: subject}
+	return peer.NewContext(ctx, &peer.Peer{AuthInfo: credentials.TLSInfo{
+		State: tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{cert},
+			VerifiedChains:   [][]*x509.Certificate{{cert}},
+		},
+	}})
+}
+
+func tokenContext(ctx context.Context, authorization string) context.Context {
+	return metadata.NewIncomingContext(ctx, metadata.Pairs(authorizationKey, authorization))
+}
+
+func TestCertSubject(t *testing.T) {
+	ctx := context.Background()
+	unverified := peer.NewContext(ctx, &peer.Peer{AuthInfo: credentials.TLSInfo{
+		State: tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: "mallory"}}}},
+	}})
+	for _, test := range []struct {
+		desc   string
+		ctx    context.Context
+		want   string
+		wantOK bool
+	}{
+		{desc: "noPeer", ctx: ctx},
+		{desc: "noTLS", ctx: peer.NewContext(ctx, &peer.Peer{})},
+		{desc: "unverified", ctx: unverified},
+		{
+			desc:   "verified",
+			ctx:    certContext(ctx, pkix.Name{CommonName: "alice", Organization: []string{"Example"}}),
+			want:   "CN=alice,O=Example",
+			wantOK: true,
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			got, ok := CertSubject(test.ctx)
+			if got != test.want || ok != test.wantOK {
+				t.Errorf("CertSubject() = (%q, %v), want (%q, %v)", got, ok, test.want, test.wantOK)
+			}
+		})
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	ctx := context.Background()
+	for _, test := range []struct {
+		desc   string
+		ctx    context.Context
+		want   string
+		wantOK bool
+	}{
+		{desc: "noMetadata", ctx: ctx},
+		{desc: "basic", ctx: tokenContext(ctx, "Basic YWxpY2U6cGFzcw==")},
+		{desc: "emptyBearer", ctx: tokenContext(ctx, "Bearer ")},
+		{desc: "bearer", ctx: tokenContext(ctx, "Bearer s3cr3t"), want: "s3cr3t", wantOK: true},
+		{desc: "lowercase", ctx: tokenContext(ctx, "bearer s3cr3t"), want: "s3cr3t", wantOK: true},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			got, ok := BearerToken(test.ctx)
+			if got != test.want || ok != test.wantOK {
+				t.Errorf("BearerToken() = (%q, %v), want (%q, %v)", got, ok, test.want, test.wantOK)
+			}
+		})
+	}
+}
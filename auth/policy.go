@@ -0,0 +1,193 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/google/trillian/trees"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AnyIdentity is the Grant identity that matches all callers, including
+// unauthenticated ones.
+const AnyIdentity = "*"
+
+// Policy maps the identities of callers to the operations they are allowed to
+// perform on each tree. Callers are identified by the subject of their
+// verified TLS client certificate, or by a bearer token.
+//
+// A Policy is usually read from a JSON file, e.g.:
+//
+//	{
+//	  "tokens": {"s3cr3t": "frontend"},
+//	  "grants": [
+//	    {"identity": "*", "ops": ["Query"]},
+//	    {"identity": "frontend", "tree_ids": [123], "ops": ["QueueLog"]},
+//	    {"identity": "CN=admin,O=Example", "ops": ["Admin"]}
+//	  ]
+//	}
+type Policy struct {
+	// Tokens maps bearer tokens to the identities they authenticate.
+	Tokens map[string]string `json:"tokens"`
+	// Grants lists the operations each identity is allowed to perform.
+	Grants []Grant `json:"grants"`
+}
+
+// Grant allows an identity to perform some operations on some trees.
+type Grant struct {
+	// Identity is the certificate subject, the name of a bearer token
+	// identity, or AnyIdentity.
+	Identity string `json:"identity"`
+	// TreeIDs restricts the grant to the given trees. If empty, the grant
+	// covers all trees, as well as requests that don't address a tree.
+	TreeIDs []int64 `json:"tree_ids"`
+	// Ops are the names of the allowed trees.OpType values, e.g. "Query".
+	Ops []string `json:"ops"`
+}
+
+// LoadPolicy reads a JSON encoded Policy from path.
+func LoadPolicy(path string) (*Policy, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	var p Policy
+	if err := dec.Decode(&p); err != nil {
+		return nil, fmt.Errorf("failed to parse policy %q: %v", path, err)
+	}
+	return &p, nil
+}
+
+// opTypes maps OpType names to their values.
+var opTypes = func() map[string]trees.OpType {
+	m := make(map[string]trees.OpType)
+	for op := trees.Admin; op <= trees.UpdateMap; op++ {
+		m[op.String()] = op
+	}
+	return m
+}()
+
+// grant is a parsed Grant.
+type grant struct {
+	// trees holds the IDs of the trees covered by the grant, or nil for all
+	// trees.
+	trees map[int64]bool
+	ops   map[trees.OpType]bool
+}
+
+func (g grant) allows(treeID int64, op trees.OpType) bool {
+	return g.ops[op] && (g.trees == nil || g.trees[treeID])
+}
+
+type policyAuthorizer struct {
+	tokens []tokenIdentity
+	grants map[string][]grant
+}
+
+type tokenIdentity struct {
+	token    []byte
+	identity string
+}
+
+// NewPolicyAuthorizer returns an Authorizer that enforces p.
+func NewPolicyAuthorizer(p *Policy) (Authorizer, error) {
+	a := &policyAuthorizer{grants: make(map[string][]grant)}
+	for token, identity := range p.Tokens {
+		if token == "" || identity == "" || identity == AnyIdentity {
+			return nil, fmt.Errorf("invalid token identity %q", identity)
+		}
+		a.tokens = append(a.tokens, tokenIdentity{token: []byte(token), identity: identity})
+	}
+	for _, g := range p.Grants {
+		if g.Identity == "" {
+			return nil, fmt.Errorf("grant with no identity: %+v", g)
+		}
+		if len(g.Ops) == 0 {
+			return nil, fmt.Errorf("grant to %q has no ops", g.Identity)
+		}
+		pg := grant{ops: make(map[trees.OpType]bool)}
+		for _, name := range g.Ops {
+			op, ok := opTypes[name]
+			if !ok {
+				return nil, fmt.Errorf("grant to %q has unknown op %q", g.Identity, name)
+			}
+			pg.ops[op] = true
+		}
+		if len(g.TreeIDs) > 0 {
+			pg.trees = make(map[int64]bool)
+			for _, id := range g.TreeIDs {
+				pg.trees[id] = true
+			}
+		}
+		a.grants[g.Identity] = append(a.grants[g.Identity], pg)
+	}
+	return a, nil
+}
+
+func (a *policyAuthorizer) Authorize(ctx context.Context, treeID int64, op trees.OpType) error {
+	identities, err := a.identities(ctx)
+	if err != nil {
+		return err
+	}
+	for _, id := range append(identities, AnyIdentity) {
+		for _, g := range a.grants[id] {
+			if g.allows(treeID, op) {
+				return nil
+			}
+		}
+	}
+	if len(identities) == 0 {
+		return status.Errorf(codes.Unauthenticated, "%v on tree %v requires authentication", op, treeID)
+	}
+	return status.Errorf(codes.PermissionDenied, "%v is not allowed %v on tree %v", identities, op, treeID)
+}
+
+// identities returns the identities of the caller. It fails if the caller
+// presents a bearer token that isn't part of the policy.
+func (a *policyAuthorizer) identities(ctx context.Context) ([]string, error) {
+	var ids []string
+	if subject, ok := CertSubject(ctx); ok {
+		ids = append(ids, subject)
+	}
+	if token, ok := BearerToken(ctx); ok {
+		id, ok := a.tokenIdentity([]byte(token))
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "unknown bearer token")
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (a *policyAuthorizer) tokenIdentity(token []byte) (string, bool) {
+	// Compare every token in constant time, so the response time doesn't
+	// reveal how much of a token was guessed right.
+	var identity string
+	for _, t := range a.tokens {
+		if subtle.ConstantTimeCompare(t.token, token) == 1 {
+			identity = t.identity
+		}
+	}
+	return identity, identity != ""
+}
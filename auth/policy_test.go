@@ -0,0 +1,132 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/trillian/trees"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const testPolicy = `{
+  "tokens": {"s3cr3t": "frontend", "t0k3n": "sequencer"},
+  "grants": [
+    {"identity": "*", "tree_ids": [1], "ops": ["Query"]},
+    {"identity": "frontend", "tree_ids": [1, 2], "ops": ["Query", "QueueLog"]},
+    {"identity": "CN=admin", "ops": ["Admin", "Query"]}
+  ]
+}`
+
+func writePolicy(t *testing.T, contents string) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "auth")
+	if err != nil {
+		t.Fatalf("TempDir(): %v", err)
+	}
+	path := filepath.Join(dir, "policy.json")
+	if err := ioutil.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatalf("WriteFile(): %v", err)
+	}
+	return path
+}
+
+func TestPolicyAuthorizer(t *testing.T) {
+	path := writePolicy(t, testPolicy)
+	defer os.RemoveAll(filepath.Dir(path))
+	p, err := LoadPolicy(path)
+	if err != nil {
+		t.Fatalf("LoadPolicy(): %v", err)
+	}
+	authz, err := NewPolicyAuthorizer(p)
+	if err != nil {
+		t.Fatalf("NewPolicyAuthorizer(): %v", err)
+	}
+
+	ctx := context.Background()
+	frontend := tokenContext(ctx, "Bearer s3cr3t")
+	admin := certContext(ctx, pkix.Name{CommonName: "admin"})
+	for _, test := range []struct {
+		desc     string
+		ctx      context.Context
+		treeID   int64
+		op       trees.OpType
+		wantCode codes.Code
+	}{
+		{desc: "anonymousQuery", ctx: ctx, treeID: 1, op: trees.Query},
+		{desc: "anonymousOtherTree", ctx: ctx, treeID: 2, op: trees.Query, wantCode: codes.Unauthenticated},
+		{desc: "anonymousQueue", ctx: ctx, treeID: 1, op: trees.QueueLog, wantCode: codes.Unauthenticated},
+		{desc: "tokenQueue", ctx: frontend, treeID: 2, op: trees.QueueLog},
+		{desc: "tokenAnyIdentityGrant", ctx: frontend, treeID: 1, op: trees.Query},
+		{desc: "tokenOtherTree", ctx: frontend, treeID: 3, op: trees.QueueLog, wantCode: codes.PermissionDenied},
+		{desc: "tokenAdmin", ctx: frontend, treeID: 0, op: trees.Admin, wantCode: codes.PermissionDenied},
+		{desc: "tokenNoGrants", ctx: tokenContext(ctx, "Bearer t0k3n"), treeID: 2, op: trees.Query, wantCode: codes.PermissionDenied},
+		{desc: "unknownToken", ctx: tokenContext(ctx, "Bearer s3cr3"), treeID: 1, op: trees.Query, wantCode: codes.Unauthenticated},
+		{desc: "certAdmin", ctx: admin, treeID: 0, op: trees.Admin},
+		{desc: "certAnyTree", ctx: admin, treeID: 42, op: trees.Query},
+		{desc: "certUpdateMap", ctx: admin, treeID: 42, op: trees.UpdateMap, wantCode: codes.PermissionDenied},
+		{desc: "certAndToken", ctx: tokenContext(admin, "Bearer s3cr3t"), treeID: 2, op: trees.QueueLog},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			err := authz.Authorize(test.ctx, test.treeID, test.op)
+			if got := status.Code(err); got != test.wantCode {
+				t.Errorf("Authorize(%v, %v) returned err = %v, want code %v", test.treeID, test.op, err, test.wantCode)
+			}
+		})
+	}
+}
+
+func TestNewPolicyAuthorizerErrors(t *testing.T) {
+	for _, test := range []struct {
+		desc   string
+		policy Policy
+	}{
+		{desc: "emptyTokenIdentity", policy: Policy{Tokens: map[string]string{"s3cr3t": ""}}},
+		{desc: "anyIdentityToken", policy: Policy{Tokens: map[string]string{"s3cr3t": AnyIdentity}}},
+		{desc: "noIdentity", policy: Policy{Grants: []Grant{{Ops: []string{"Query"}}}}},
+		{desc: "noOps", policy: Policy{Grants: []Grant{{Identity: "frontend"}}}},
+		{desc: "unknownOp", policy: Policy{Grants: []Grant{{Identity: "frontend", Ops: []string{"Read"}}}}},
+		{desc: "unknownOpType", policy: Policy{Grants: []Grant{{Identity: "frontend", Ops: []string{"Unknown"}}}}},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			if _, err := NewPolicyAuthorizer(&test.policy); err == nil {
+				t.Error("NewPolicyAuthorizer() returned nil err")
+			}
+		})
+	}
+}
+
+func TestLoadPolicyErrors(t *testing.T) {
+	path := writePolicy(t, `{"grants": [{"identity": "*", "operations": ["Query"]}]}`)
+	defer os.RemoveAll(filepath.Dir(path))
+	if _, err := LoadPolicy(path); err == nil {
+		t.Error("LoadPolicy() with unknown field returned nil err")
+	}
+	if _, err := LoadPolicy(filepath.Join(filepath.Dir(path), "missing.json")); err == nil {
+		t.Error("LoadPolicy() of missing file returned nil err")
+	}
+}
+
+func TestAllowAll(t *testing.T) {
+	if err := AllowAll().Authorize(context.Background(), 1, trees.Admin); err != nil {
+		t.Errorf("Authorize() returned err = %v", err)
+	}
+}
@@ -39,7 +39,10 @@ type LogVerifier struct {
 	PubKey crypto.PublicKey
 	// SigHash computes the digest of LogRoot for signing.
 	SigHash crypto.Hash
-	v       merkle.LogVerifier
+	// PrevPubKeys are the keys that signed the log before PubKey, by key ID.
+	// They verify roots whose key hint identifies one of them.
+	PrevPubKeys map[int64]crypto.PublicKey
+	v           merkle.LogVerifier
 }
 
 // NewLogVerifier returns an object that can verify output from Trillian Logs.
@@ -78,7 +81,40 @@ func NewLogVerifierFromTree(config *trillian.Tree) (*LogVerifier, error) {
 		return nil, fmt.Errorf("client: NewLogVerifierFromTree(): Failed parsing Log signature hash: %v", err)
 	}
 
-	return NewLogVerifier(logHasher, logPubKey, sigHash), nil
+	prevPubKeys, err := previousPubKeys(config)
+	if err != nil {
+		return nil, fmt.Errorf("client: NewLogVerifierFromTree(): %v", err)
+	}
+
+	v := NewLogVerifier(logHasher, logPubKey, sigHash)
+	v.PrevPubKeys = prevPubKeys
+	return v, nil
+}
+
+// previousPubKeys parses the previous keys of a tree, indexed by key ID.
+func previousPubKeys(config *trillian.Tree) (map[int64]crypto.PublicKey, error) {
+	if len(config.PreviousKeys) == 0 {
+		return nil, nil
+	}
+	keys := make(map[int64]crypto.PublicKey, len(config.PreviousKeys))
+	for _, key := range config.PreviousKeys {
+		pubKey, err := der.UnmarshalPublicKey(key.GetPublicKey().GetDer())
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing previous public key %d: %v", key.KeyId, err)
+		}
+		keys[key.KeyId] = pubKey
+	}
+	return keys, nil
+}
+
+// pubKeyFor returns the key that verifies a root with the given key hint.
+func (c *LogVerifier) pubKeyFor(keyHint []byte) crypto.PublicKey {
+	if _, keyID, err := types.ParseKeyVersionHint(keyHint); err == nil {
+		if pubKey, ok := c.PrevPubKeys[keyID]; ok {
+			return pubKey
+		}
+	}
+	return c.PubKey
 }
 
 // VerifyRoot verifies that newRoot is a valid append-only operation from
@@ -93,7 +129,7 @@ func (c *LogVerifier) VerifyRoot(trusted *types.LogRootV1, newRoot *trillian.Sig
 	}
 
 	// Verify SignedLogRoot signature and unpack its contents.
-	r, err := tcrypto.VerifySignedLogRoot(c.PubKey, c.SigHash, newRoot)
+	r, err := tcrypto.VerifySignedLogRoot(c.pubKeyFor(newRoot.KeyHint), c.SigHash, newRoot)
 	if err != nil {
 		return nil, err
 	}
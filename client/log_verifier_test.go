@@ -16,6 +16,9 @@ package client
 
 import (
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"testing"
 
 	"github.com/google/trillian"
@@ -63,6 +66,47 @@ func TestVerifyRootErrors(t *testing.T) {
 	}
 }
 
+func TestVerifyRootKeyRotation(t *testing.T) {
+	oldKey, err := pem.UnmarshalPrivateKey(testonly.DemoPrivateKey, testonly.DemoPrivateKeyPass)
+	if err != nil {
+		t.Fatalf("Failed to open test key, err=%v", err)
+	}
+	newKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key, err=%v", err)
+	}
+
+	const treeID = 1
+	oldSigner := tcrypto.NewSigner(treeID, oldKey, crypto.SHA256)
+	newSigner := tcrypto.NewSigner(treeID, newKey, crypto.SHA256)
+	newSigner.KeyHint = types.SerializeKeyVersionHint(treeID, 1)
+	// Signed by the old key, but claiming to be signed by the new one.
+	badSigner := tcrypto.NewSigner(treeID, oldKey, crypto.SHA256)
+	badSigner.KeyHint = newSigner.KeyHint
+
+	logVerifier := NewLogVerifier(rfc6962.DefaultHasher, newKey.Public(), crypto.SHA256)
+	logVerifier.PrevPubKeys = map[int64]crypto.PublicKey{0: oldKey.Public()}
+
+	for _, test := range []struct {
+		desc    string
+		signer  *tcrypto.Signer
+		wantErr bool
+	}{
+		{desc: "oldKey", signer: oldSigner},
+		{desc: "newKey", signer: newSigner},
+		{desc: "wrongKeyHint", signer: badSigner, wantErr: true},
+	} {
+		signedRoot, err := test.signer.SignLogRoot(&types.LogRootV1{})
+		if err != nil {
+			t.Fatalf("%v: SignLogRoot(): %v", test.desc, err)
+		}
+		_, err = logVerifier.VerifyRoot(&types.LogRootV1{}, signedRoot, nil)
+		if gotErr := err != nil; gotErr != test.wantErr {
+			t.Errorf("%v: VerifyRoot(): %v, wantErr %v", test.desc, err, test.wantErr)
+		}
+	}
+}
+
 func TestVerifyInclusionAtIndexErrors(t *testing.T) {
 	logVerifier := NewLogVerifier(nil, nil, crypto.SHA256)
 	// An error is expected because the first parameter (trusted) is nil
@@ -18,6 +18,7 @@ import (
 	"crypto"
 	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/google/trillian"
 	"github.com/google/trillian/crypto/keys/der"
@@ -42,6 +43,9 @@ type MapVerifier struct {
 	PubKey crypto.PublicKey
 	// SigHash computes the digest of MapRoot for signing.
 	SigHash crypto.Hash
+	// PrevPubKeys are the keys that signed the map before PubKey, by key ID.
+	// Map roots carry no key hint, so these are tried if PubKey fails.
+	PrevPubKeys map[int64]crypto.PublicKey
 }
 
 // NewMapVerifierFromTree creates a new MapVerifier using the information
@@ -69,11 +73,17 @@ func NewMapVerifierFromTree(config *trillian.Tree) (*MapVerifier, error) {
 		return nil, fmt.Errorf("client: NewMapVerifierFromTree(): Failed parsing Map signature hash: %v", err)
 	}
 
+	prevPubKeys, err := previousPubKeys(config)
+	if err != nil {
+		return nil, fmt.Errorf("client: NewMapVerifierFromTree(): %v", err)
+	}
+
 	return &MapVerifier{
-		MapID:   config.TreeId,
-		Hasher:  mapHasher,
-		PubKey:  mapPubKey,
-		SigHash: sigHash,
+		MapID:       config.TreeId,
+		Hasher:      mapHasher,
+		PubKey:      mapPubKey,
+		SigHash:     sigHash,
+		PrevPubKeys: prevPubKeys,
 	}, nil
 }
 
@@ -91,9 +101,31 @@ func (m *MapVerifier) VerifyMapLeafInclusionHash(rootHash []byte, leafProof *tri
 	return merkle.VerifyMapInclusionProof(m.MapID, leafProof.GetLeaf(), rootHash, leafProof.GetInclusion(), m.Hasher)
 }
 
+// VerifyMapLeavesBatchInclusionHash verifies a set of leaves against a root
+// hash using a compact batch inclusion proof.
+func (m *MapVerifier) VerifyMapLeavesBatchInclusionHash(rootHash []byte, leaves []*trillian.MapLeaf, proof [][]byte) error {
+	return merkle.VerifyMapBatchInclusionProof(m.MapID, leaves, rootHash, proof, m.Hasher)
+}
+
 // VerifySignedMapRoot verifies the signature on a SignedMapRoot.
+// Roots that don't verify with PubKey are checked against PrevPubKeys, newest
+// first.
 func (m *MapVerifier) VerifySignedMapRoot(smr *trillian.SignedMapRoot) (*types.MapRootV1, error) {
-	return tcrypto.VerifySignedMapRoot(m.PubKey, m.SigHash, smr)
+	root, err := tcrypto.VerifySignedMapRoot(m.PubKey, m.SigHash, smr)
+	if err == nil || len(m.PrevPubKeys) == 0 {
+		return root, err
+	}
+	keyIDs := make([]int64, 0, len(m.PrevPubKeys))
+	for keyID := range m.PrevPubKeys {
+		keyIDs = append(keyIDs, keyID)
+	}
+	sort.Slice(keyIDs, func(i, j int) bool { return keyIDs[i] > keyIDs[j] })
+	for _, keyID := range keyIDs {
+		if prevRoot, prevErr := tcrypto.VerifySignedMapRoot(m.PrevPubKeys[keyID], m.SigHash, smr); prevErr == nil {
+			return prevRoot, nil
+		}
+	}
+	return nil, err
 }
 
 // VerifyMapLeavesResponse verifies the responses of GetMapLeaves and GetMapLeavesByRevision.
@@ -110,6 +142,18 @@ func (m *MapVerifier) VerifyMapLeavesResponse(indexes [][]byte, revision int64,
 		return nil, status.Errorf(codes.Internal, "got map revision %v, want %v", mapRoot.Revision, revision)
 	}
 
+	leaves := make([]*trillian.MapLeaf, 0, len(resp.MapLeafInclusion))
+	for _, i := range resp.MapLeafInclusion {
+		leaves = append(leaves, i.Leaf)
+	}
+
+	if len(resp.BatchInclusion) > 0 {
+		if err := m.VerifyMapLeavesBatchInclusionHash(mapRoot.RootHash, leaves, resp.BatchInclusion); err != nil {
+			return nil, status.Errorf(codes.Internal, "map: VerifyMapLeavesBatchInclusion(): %v", err)
+		}
+		return leaves, nil
+	}
+
 	var g errgroup.Group
 	for _, p := range resp.MapLeafInclusion {
 		p := p
@@ -120,10 +164,5 @@ func (m *MapVerifier) VerifyMapLeavesResponse(indexes [][]byte, revision int64,
 	if err := g.Wait(); err != nil {
 		return nil, status.Errorf(status.Code(err), "map: VerifyMapLeafInclusion(): %v", err)
 	}
-
-	leaves := make([]*trillian.MapLeaf, 0, len(resp.MapLeafInclusion))
-	for _, i := range resp.MapLeafInclusion {
-		leaves = append(leaves, i.Leaf)
-	}
 	return leaves, nil
 }
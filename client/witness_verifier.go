@@ -0,0 +1,68 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"crypto"
+	"fmt"
+
+	"github.com/google/trillian"
+
+	tcrypto "github.com/google/trillian/crypto"
+)
+
+// WitnessVerifier enforces an N-of-M witness policy on log roots: a root is
+// only accepted if at least N of the M trusted witnesses have cosigned it. It
+// is safe for concurrent use (as its contents are fixed after construction).
+type WitnessVerifier struct {
+	// Witnesses holds the public keys of the trusted witnesses, by name.
+	Witnesses map[string]crypto.PublicKey
+	// Threshold is the number of trusted witnesses that must cosign a root.
+	Threshold int
+}
+
+// NewWitnessVerifier returns a WitnessVerifier that requires roots to be
+// cosigned by threshold of the given witnesses.
+func NewWitnessVerifier(witnesses map[string]crypto.PublicKey, threshold int) (*WitnessVerifier, error) {
+	if threshold < 1 || threshold > len(witnesses) {
+		return nil, fmt.Errorf("client: NewWitnessVerifier(): threshold %d out of range [1, %d]", threshold, len(witnesses))
+	}
+	return &WitnessVerifier{Witnesses: witnesses, Threshold: threshold}, nil
+}
+
+// VerifyCosignatures verifies that slr, a root of the log with ID logID, has
+// valid cosignatures from at least Threshold trusted witnesses. Cosignatures
+// from unknown witnesses, and invalid ones, are ignored.
+func (v *WitnessVerifier) VerifyCosignatures(logID int64, slr *trillian.SignedLogRoot, cosigs []*trillian.Cosignature) error {
+	if slr == nil {
+		return fmt.Errorf("VerifyCosignatures() error: slr == nil")
+	}
+	cosigned := make(map[string]bool)
+	for _, cosig := range cosigs {
+		witness := cosig.GetWitness()
+		pubKey, ok := v.Witnesses[witness]
+		if !ok || cosigned[witness] {
+			continue
+		}
+		if err := tcrypto.VerifyCosignature(pubKey, crypto.SHA256, logID, slr, cosig.GetSignature()); err != nil {
+			continue
+		}
+		cosigned[witness] = true
+	}
+	if len(cosigned) < v.Threshold {
+		return fmt.Errorf("log root cosigned by %d trusted witnesses, want at least %d", len(cosigned), v.Threshold)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package client
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	"github.com/google/trillian"
+	"github.com/google/trillian/types"
+
+	tcrypto "github.com/google/trillian/crypto"
+)
+
+func TestNewWitnessVerifier(t *testing.T) {
+	witnesses := map[string]crypto.PublicKey{"a": nil, "b": nil}
+	for _, test := range []struct {
+		threshold int
+		wantErr   bool
+	}{
+		{threshold: 0, wantErr: true},
+		{threshold: 1},
+		{threshold: 2},
+		{threshold: 3, wantErr: true},
+	} {
+		_, err := NewWitnessVerifier(witnesses, test.threshold)
+		if gotErr := err != nil; gotErr != test.wantErr {
+			t.Errorf("NewWitnessVerifier(_, %d): %v, wantErr %v", test.threshold, err, test.wantErr)
+		}
+	}
+}
+
+func TestVerifyCosignatures(t *testing.T) {
+	const logID = 42
+	signers := make(map[string]*tcrypto.Signer)
+	witnesses := make(map[string]crypto.PublicKey)
+	for _, name := range []string{"alice", "bob", "carol", "mallory"} {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatalf("Failed to generate key, err=%v", err)
+		}
+		signers[name] = tcrypto.NewSigner(0, key, crypto.SHA256)
+		if name != "mallory" {
+			witnesses[name] = key.Public()
+		}
+	}
+	v, err := NewWitnessVerifier(witnesses, 2)
+	if err != nil {
+		t.Fatalf("NewWitnessVerifier(): %v", err)
+	}
+
+	slr, err := signers["alice"].SignLogRoot(&types.LogRootV1{TreeSize: 10, RootHash: []byte("root")})
+	if err != nil {
+		t.Fatalf("SignLogRoot(): %v", err)
+	}
+	otherRoot, err := signers["alice"].SignLogRoot(&types.LogRootV1{TreeSize: 10, RootHash: []byte("fork")})
+	if err != nil {
+		t.Fatalf("SignLogRoot(): %v", err)
+	}
+	cosign := func(name, signer string, root *trillian.SignedLogRoot) *trillian.Cosignature {
+		t.Helper()
+		sig, err := signers[signer].CosignLogRoot(logID, root)
+		if err != nil {
+			t.Fatalf("CosignLogRoot(): %v", err)
+		}
+		return &trillian.Cosignature{Witness: name, Signature: sig}
+	}
+
+	for _, test := range []struct {
+		desc    string
+		cosigs  []*trillian.Cosignature
+		wantErr bool
+	}{
+		{desc: "none", wantErr: true},
+		{desc: "one", cosigs: []*trillian.Cosignature{cosign("alice", "alice", slr)}, wantErr: true},
+		{desc: "two", cosigs: []*trillian.Cosignature{cosign("alice", "alice", slr), cosign("bob", "bob", slr)}},
+		{desc: "three", cosigs: []*trillian.Cosignature{cosign("alice", "alice", slr), cosign("bob", "bob", slr), cosign("carol", "carol", slr)}},
+		{desc: "duplicate", cosigs: []*trillian.Cosignature{cosign("alice", "alice", slr), cosign("alice", "alice", slr)}, wantErr: true},
+		{desc: "unknownWitness", cosigs: []*trillian.Cosignature{cosign("alice", "alice", slr), cosign("mallory", "mallory", slr)}, wantErr: true},
+		{desc: "forgedWitness", cosigs: []*trillian.Cosignature{cosign("alice", "alice", slr), cosign("bob", "mallory", slr)}, wantErr: true},
+		{desc: "otherRoot", cosigs: []*trillian.Cosignature{cosign("alice", "alice", slr), cosign("bob", "bob", otherRoot)}, wantErr: true},
+	} {
+		err := v.VerifyCosignatures(logID, slr, test.cosigs)
+		if gotErr := err != nil; gotErr != test.wantErr {
+			t.Errorf("%v: VerifyCosignatures(): %v, wantErr %v", test.desc, err, test.wantErr)
+		}
+	}
+
+	// Cosignatures are bound to the log they were made for.
+	cosigs := []*trillian.Cosignature{cosign("alice", "alice", slr), cosign("bob", "bob", slr)}
+	if err := v.VerifyCosignatures(logID+1, slr, cosigs); err == nil {
+		t.Errorf("VerifyCosignatures() for another log: nil, want error")
+	}
+}
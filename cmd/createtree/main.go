@@ -59,6 +59,8 @@ var (
 	displayName        = flag.String("display_name", "", "Display name of the new tree")
 	description        = flag.String("description", "", "Description of the new tree")
 	maxRootDuration    = flag.Duration("max_root_duration", 0, "Interval after which a new signed root is produced despite no submissions; zero means never")
+	queueTTL           = flag.Duration("queue_ttl", 0, "Maximum time a leaf may remain unsequenced before it's expired (LOG trees only); zero means never")
+	maxQueueDepth      = flag.Int64("max_queue_depth", 0, "Maximum number of unsequenced leaves that may be queued (LOG trees only); zero means unlimited")
 	privateKeyFormat   = flag.String("private_key_format", "", "Type of protobuf message to send the key as (PrivateKey, PEMKeyFile, or PKCS11ConfigFile). If empty, a key will be generated for you by Trillian.")
 
 	configFile = flag.String("config", "", "Config file containing flags, file contents can be overridden by command line flags")
@@ -130,7 +132,11 @@ func newRequest() (*trillian.CreateTreeRequest, error) {
 		DisplayName:        *displayName,
 		Description:        *description,
 		MaxRootDuration:    ptypes.DurationProto(*maxRootDuration),
+		MaxQueueDepth:      *maxQueueDepth,
 	}}
+	if *queueTTL > 0 {
+		ctr.Tree.QueueTtl = ptypes.DurationProto(*queueTTL)
+	}
 	glog.Infof("Creating tree %+v", ctr.Tree)
 
 	if *privateKeyFormat != "" {
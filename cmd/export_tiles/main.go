@@ -0,0 +1,86 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package main contains the implementation and entry point for the
+// export_tiles command.
+//
+// export_tiles writes the tiles of a log to --output_dir, in the layout
+// described in the tiles package, so they can be served from a CDN. The tiles
+// are read directly from storage, so the storage flags must match those of
+// the Trillian servers. If --interval is set, the export is repeated, adding
+// tiles as the log grows.
+//
+// Example usage:
+// $ ./export_tiles --log_id=logid --output_dir=/var/www/log --interval=1m
+package main
+
+import (
+	"context"
+	"flag"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/google/trillian"
+	"github.com/google/trillian/monitoring"
+	"github.com/google/trillian/server"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/tiles"
+)
+
+var (
+	logID     = flag.Int64("log_id", 0, "Trillian LogID to export")
+	outputDir = flag.String("output_dir", "", "Directory to write the tiles to")
+	interval  = flag.Duration("interval", 0, "Interval between exports; if 0, the log is exported once")
+)
+
+func main() {
+	flag.Parse()
+	defer glog.Flush()
+	ctx := context.Background()
+
+	if *outputDir == "" {
+		glog.Exit("--output_dir must be set")
+	}
+
+	sp, err := server.NewStorageProviderFromFlags(monitoring.InertMetricFactory{})
+	if err != nil {
+		glog.Exitf("Failed to get storage provider: %v", err)
+	}
+	defer sp.Close()
+
+	tree, err := storage.GetTree(ctx, sp.AdminStorage(), *logID)
+	if err != nil {
+		glog.Exitf("Failed to get tree %d: %v", *logID, err)
+	}
+	if tree.TreeType != trillian.TreeType_LOG && tree.TreeType != trillian.TreeType_PREORDERED_LOG {
+		glog.Exitf("Tree %d is a %v, not a log", *logID, tree.TreeType)
+	}
+
+	e := tiles.NewExporter(sp.LogStorage(), tree, *outputDir)
+	for {
+		root, err := e.Export(ctx)
+		if err != nil {
+			if *interval == 0 {
+				glog.Exitf("Failed to export log %d: %v", *logID, err)
+			}
+			glog.Warningf("Failed to export log %d: %v", *logID, err)
+		} else {
+			glog.Infof("Exported log %d at size %d", *logID, root.TreeSize)
+		}
+		if *interval == 0 {
+			return
+		}
+		time.Sleep(*interval)
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package main contains the implementation and entry point for the quotactl
+// command, a client of the trillian.Quota admin service.
+//
+// Example usage:
+// $ ./quotactl --quota_server=host:port list
+// $ ./quotactl --quota_server=host:port get quotas/global/write/config
+// $ ./quotactl --quota_server=host:port create --max_tokens=100 --replenish_rate=10 quotas/trees/123/write/config
+// $ ./quotactl --quota_server=host:port update --max_tokens=200 --reset_quota quotas/trees/123/write/config
+// $ ./quotactl --quota_server=host:port delete quotas/trees/123/write/config
+//
+// Configs are printed one per line, in the protobuf text format.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian"
+	"github.com/google/trillian/client/rpcflags"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc"
+)
+
+var (
+	quotaServerAddr = flag.String("quota_server", "", "Address of the gRPC Trillian Quota server (host:port)")
+	rpcDeadline     = flag.Duration("rpc_deadline", time.Second*10, "Deadline for RPC requests")
+)
+
+const usage = `Usage: quotactl [flags] <command> [command flags] [name]
+
+Commands:
+  list                 Lists all quota configs.
+  get <name>           Prints a quota config, with its current tokens.
+  create <name>        Creates a quota config. Requires --max_tokens, and
+                       --replenish_rate for time-based quotas.
+  update <name>        Updates the fields of a quota config given by flags.
+  delete <name>        Deletes a quota config.
+
+Command flags (create and update only):
+  --max_tokens=N       Maximum number of tokens of the quota.
+  --replenish_rate=R   Number of tokens replenished every second.
+  --disabled           Makes the quota unlimited (tree and user configs only).
+  --reset_quota        Refills the quota after the update (update only).
+
+Flags:
+`
+
+// command holds the arguments of a quotactl command.
+type command struct {
+	name   string
+	config *trillian.QuotaConfig
+	// paths holds the update_mask paths of the config fields set by flags.
+	paths      []string
+	resetQuota bool
+}
+
+// parseCommand parses the command line arguments that follow the global flags.
+func parseCommand(args []string) (*command, error) {
+	if len(args) == 0 {
+		return nil, errors.New("missing command")
+	}
+	cmd := &command{name: args[0], config: &trillian.QuotaConfig{}}
+
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	fs.Int64Var(&cmd.config.MaxTokens, "max_tokens", 0, "Maximum number of tokens of the quota")
+	fs.Float64Var(&cmd.config.ReplenishRate, "replenish_rate", 0, "Number of tokens replenished every second")
+	fs.BoolVar(&cmd.config.Disabled, "disabled", false, "If true, the quota is unlimited")
+	fs.BoolVar(&cmd.resetQuota, "reset_quota", false, "If true, the quota is refilled after the update")
+	if err := fs.Parse(args[1:]); err != nil {
+		return nil, err
+	}
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name != "reset_quota" {
+			cmd.paths = append(cmd.paths, f.Name)
+		}
+	})
+
+	switch cmd.name {
+	case "list":
+		if fs.NArg() != 0 {
+			return nil, errors.New("list takes no arguments")
+		}
+	case "get", "create", "update", "delete":
+		if fs.NArg() != 1 {
+			return nil, fmt.Errorf("%v takes a single config name", cmd.name)
+		}
+		cmd.config.Name = fs.Arg(0)
+	default:
+		return nil, fmt.Errorf("unknown command: %q", cmd.name)
+	}
+
+	switch {
+	case fs.NFlag() > 0 && cmd.name != "create" && cmd.name != "update":
+		return nil, fmt.Errorf("%v takes no flags", cmd.name)
+	case cmd.resetQuota && cmd.name != "update":
+		return nil, errors.New("--reset_quota is only supported by update")
+	case cmd.name == "update" && len(cmd.paths) == 0:
+		return nil, errors.New("nothing to update")
+	}
+	return cmd, nil
+}
+
+// run runs cmd against client, and prints the resulting configs to w.
+func run(ctx context.Context, client trillian.QuotaClient, cmd *command, w io.Writer) error {
+	var configs []*trillian.QuotaConfig
+	switch cmd.name {
+	case "list":
+		resp, err := client.ListQuotaConfigs(ctx, &trillian.ListQuotaConfigsRequest{})
+		if err != nil {
+			return err
+		}
+		configs = resp.Configs
+	case "get":
+		c, err := client.GetQuotaConfig(ctx, &trillian.GetQuotaConfigRequest{Name: cmd.config.Name})
+		if err != nil {
+			return err
+		}
+		configs = append(configs, c)
+	case "create":
+		c, err := client.CreateQuotaConfig(ctx, &trillian.CreateQuotaConfigRequest{Config: cmd.config})
+		if err != nil {
+			return err
+		}
+		configs = append(configs, c)
+	case "update":
+		c, err := client.UpdateQuotaConfig(ctx, &trillian.UpdateQuotaConfigRequest{
+			Config:     cmd.config,
+			UpdateMask: &field_mask.FieldMask{Paths: cmd.paths},
+			ResetQuota: cmd.resetQuota,
+		})
+		if err != nil {
+			return err
+		}
+		configs = append(configs, c)
+	case "delete":
+		if _, err := client.DeleteQuotaConfig(ctx, &trillian.DeleteQuotaConfigRequest{Name: cmd.config.Name}); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown command: %q", cmd.name)
+	}
+	for _, c := range configs {
+		if _, err := fmt.Fprintln(w, strings.TrimSpace(proto.CompactTextString(c))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	defer glog.Flush()
+
+	if *quotaServerAddr == "" {
+		glog.Exit("Empty --quota_server, please provide the Quota server host:port")
+	}
+	cmd, err := parseCommand(flag.Args())
+	if err != nil {
+		flag.Usage()
+		glog.Exitf("Invalid command: %v", err)
+	}
+
+	dialOpts, err := rpcflags.NewClientDialOptionsFromFlags()
+	if err != nil {
+		glog.Exitf("Failed to determine dial options: %v", err)
+	}
+	conn, err := grpc.Dial(*quotaServerAddr, dialOpts...)
+	if err != nil {
+		glog.Exitf("Failed to dial %v: %v", *quotaServerAddr, err)
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *rpcDeadline)
+	defer cancel()
+	if err := run(ctx, trillian.NewQuotaClient(conn), cmd, os.Stdout); err != nil {
+		glog.Exitf("%v failed: %v", cmd.name, err)
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/trillian"
+	"github.com/google/trillian/quota/memqm"
+	"github.com/google/trillian/quota/quotaadmin"
+	"github.com/google/trillian/util/clock"
+	"github.com/kylelemons/godebug/pretty"
+	"google.golang.org/grpc"
+)
+
+func TestParseCommand(t *testing.T) {
+	for _, test := range []struct {
+		desc    string
+		args    []string
+		want    *command
+		wantErr bool
+	}{
+		{
+			desc: "list",
+			args: []string{"list"},
+			want: &command{name: "list", config: &trillian.QuotaConfig{}},
+		},
+		{
+			desc: "create",
+			args: []string{"create", "--max_tokens=10", "--replenish_rate=0.5", "quotas/global/write/config"},
+			want: &command{
+				name:   "create",
+				config: &trillian.QuotaConfig{Name: "quotas/global/write/config", MaxTokens: 10, ReplenishRate: 0.5},
+				paths:  []string{"max_tokens", "replenish_rate"},
+			},
+		},
+		{
+			desc: "update",
+			args: []string{"update", "--disabled", "--reset_quota", "quotas/trees/1/read/config"},
+			want: &command{
+				name:       "update",
+				config:     &trillian.QuotaConfig{Name: "quotas/trees/1/read/config", Disabled: true},
+				paths:      []string{"disabled"},
+				resetQuota: true,
+			},
+		},
+		{desc: "noCommand", wantErr: true},
+		{desc: "unknownCommand", args: []string{"llama"}, wantErr: true},
+		{desc: "unknownFlag", args: []string{"create", "--llama=1", "quotas/global/write/config"}, wantErr: true},
+		{desc: "listWithName", args: []string{"list", "quotas/global/write/config"}, wantErr: true},
+		{desc: "getWithoutName", args: []string{"get"}, wantErr: true},
+		{desc: "getWithFlags", args: []string{"get", "--max_tokens=1", "quotas/global/write/config"}, wantErr: true},
+		{desc: "createReset", args: []string{"create", "--max_tokens=1", "--reset_quota", "quotas/global/write/config"}, wantErr: true},
+		{desc: "emptyUpdate", args: []string{"update", "--reset_quota", "quotas/global/write/config"}, wantErr: true},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			got, err := parseCommand(test.args)
+			if gotErr := err != nil; gotErr != test.wantErr {
+				t.Fatalf("parseCommand(%v) returned err = %v, wantErr %v", test.args, err, test.wantErr)
+			}
+			if diff := pretty.Compare(got, test.want); err == nil && diff != "" {
+				t.Errorf("parseCommand(%v) diff (-got +want):\n%v", test.args, diff)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	qm, err := memqm.New(&memqm.Config{
+		Global: memqm.Buckets{Write: &memqm.Bucket{Rate: 100, Burst: 1000}},
+	}, clock.NewFake(time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)))
+	if err != nil {
+		t.Fatalf("memqm.New(): %v", err)
+	}
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("Listen(): %v", err)
+	}
+	s := grpc.NewServer()
+	trillian.RegisterQuotaServer(s, quotaadmin.NewServer(qm, quotaadmin.NewBucketStore(qm)))
+	go s.Serve(lis)
+	defer s.Stop()
+	conn, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("Dial(): %v", err)
+	}
+	defer conn.Close()
+	client := trillian.NewQuotaClient(conn)
+
+	for _, test := range []struct {
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{
+			args: []string{"create", "--max_tokens=10", "--replenish_rate=2", "quotas/trees/1/write/config"},
+			want: `name:"quotas/trees/1/write/config" max_tokens:10 replenish_rate:2 current_tokens:10` + "\n",
+		},
+		{
+			args: []string{"update", "--max_tokens=20", "quotas/trees/1/write/config"},
+			want: `name:"quotas/trees/1/write/config" max_tokens:20 replenish_rate:2 current_tokens:10` + "\n",
+		},
+		{
+			args: []string{"update", "--max_tokens=30", "--reset_quota", "quotas/trees/1/write/config"},
+			want: `name:"quotas/trees/1/write/config" max_tokens:30 replenish_rate:2 current_tokens:30` + "\n",
+		},
+		{
+			args: []string{"list"},
+			want: `name:"quotas/global/write/config" max_tokens:1000 replenish_rate:100` + "\n" +
+				`name:"quotas/trees/1/write/config" max_tokens:30 replenish_rate:2` + "\n",
+		},
+		{args: []string{"delete", "quotas/trees/1/write/config"}},
+		{args: []string{"get", "quotas/trees/1/write/config"}, wantErr: true},
+		{
+			args: []string{"get", "quotas/global/write/config"},
+			want: `name:"quotas/global/write/config" max_tokens:1000 replenish_rate:100 current_tokens:1000` + "\n",
+		},
+	} {
+		cmd, err := parseCommand(test.args)
+		if err != nil {
+			t.Fatalf("parseCommand(%v): %v", test.args, err)
+		}
+		var out bytes.Buffer
+		err = run(ctx, client, cmd, &out)
+		if gotErr := err != nil; gotErr != test.wantErr {
+			t.Errorf("run(%v) returned err = %v, wantErr %v", test.args, err, test.wantErr)
+			continue
+		}
+		if got := out.String(); got != test.want {
+			t.Errorf("run(%v) printed %q, want %q", test.args, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package main contains the implementation and entry point for the
+// rewrap_tree_keys command.
+//
+// rewrap_tree_keys envelope-encrypts the private keys of existing trees with
+// the key-encryption key given by --envelope_kek_id. Plain keyspb.PrivateKey
+// keys are encrypted, keys wrapped by a different KEK are re-wrapped, and keys
+// of other types (e.g. PEM files or PKCS#11) are left untouched.
+//
+// The private keys are read and written directly in storage, so the storage
+// and KEK provider flags must match those of the Trillian servers.
+//
+// Example usage:
+// $ ./rewrap_tree_keys --envelope_kek_dir=/etc/trillian/keks --envelope_kek_id=kek2
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/trillian"
+	"github.com/google/trillian/crypto/keys/envelope"
+	"github.com/google/trillian/monitoring"
+	"github.com/google/trillian/server"
+	"github.com/google/trillian/storage"
+
+	envproto "github.com/google/trillian/crypto/keys/envelope/proto"
+
+	// Register key ProtoHandlers
+	_ "github.com/google/trillian/crypto/keys/der/proto"
+	_ "github.com/google/trillian/crypto/keys/pem/proto"
+	_ "github.com/google/trillian/crypto/keys/pkcs11/proto"
+)
+
+var (
+	treeID = flag.Int64("tree_id", 0, "ID of the tree whose private key to re-wrap; if 0, all non-deleted trees are processed")
+	dryRun = flag.Bool("dry_run", false, "If true, only log the trees that would be updated")
+)
+
+func main() {
+	flag.Parse()
+	defer glog.Flush()
+
+	if *envproto.KEKID == "" {
+		glog.Exit("--envelope_kek_id must be set")
+	}
+	p, err := envproto.KEKProviderFromFlags()
+	if err != nil {
+		glog.Exitf("Failed to create KEK provider: %v", err)
+	}
+
+	sp, err := server.NewStorageProviderFromFlags(monitoring.InertMetricFactory{})
+	if err != nil {
+		glog.Exitf("Failed to get storage provider: %v", err)
+	}
+	defer sp.Close()
+
+	n, err := rewrapTrees(context.Background(), sp.AdminStorage(), p, *envproto.KEKID, *treeID, *dryRun)
+	if err != nil {
+		glog.Exitf("Re-wrapping failed after %d tree(s): %v", n, err)
+	}
+	if *dryRun {
+		glog.Infof("Would re-wrap the private keys of %d tree(s)", n)
+		return
+	}
+	glog.Infof("Re-wrapped the private keys of %d tree(s)", n)
+}
+
+// rewrapTrees wraps the private key of the tree with ID treeID (or of all
+// non-deleted trees, if treeID is 0) with the KEK kekID. It returns the number
+// of trees that were, or in dry-run mode would have been, updated.
+func rewrapTrees(ctx context.Context, as storage.AdminStorage, p envelope.KEKProvider, kekID string, treeID int64, dryRun bool) (int, error) {
+	var trees []*trillian.Tree
+	if treeID != 0 {
+		tree, err := storage.GetTree(ctx, as, treeID)
+		if err != nil {
+			return 0, err
+		}
+		trees = []*trillian.Tree{tree}
+	} else {
+		var err error
+		if trees, err = storage.ListTrees(ctx, as, false /* includeDeleted */); err != nil {
+			return 0, err
+		}
+	}
+
+	n := 0
+	for _, tree := range trees {
+		var keyProto ptypes.DynamicAny
+		if err := ptypes.UnmarshalAny(tree.PrivateKey, &keyProto); err != nil {
+			return n, fmt.Errorf("tree %d: failed to unmarshal private key: %v", tree.TreeId, err)
+		}
+		wrapped, err := envelope.Rewrap(ctx, p, kekID, keyProto.Message)
+		switch {
+		case err == envelope.ErrUnsupportedKey:
+			glog.Infof("Tree %d: skipping private key of type %T", tree.TreeId, keyProto.Message)
+			continue
+		case err != nil:
+			return n, fmt.Errorf("tree %d: %v", tree.TreeId, err)
+		case wrapped == keyProto.Message:
+			glog.Infof("Tree %d: private key already wrapped with KEK %q", tree.TreeId, kekID)
+			continue
+		}
+
+		if dryRun {
+			glog.Infof("Tree %d: would re-wrap private key with KEK %q", tree.TreeId, kekID)
+			n++
+			continue
+		}
+		privateKey, err := ptypes.MarshalAny(wrapped)
+		if err != nil {
+			return n, fmt.Errorf("tree %d: failed to marshal private key: %v", tree.TreeId, err)
+		}
+		if _, err := storage.UpdateTree(ctx, as, tree.TreeId, func(t *trillian.Tree) {
+			t.PrivateKey = privateKey
+		}); err != nil {
+			return n, fmt.Errorf("tree %d: failed to update tree: %v", tree.TreeId, err)
+		}
+		glog.Infof("Tree %d: re-wrapped private key with KEK %q", tree.TreeId, kekID)
+		n++
+	}
+	return n, nil
+}
@@ -0,0 +1,140 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/trillian/crypto/keys"
+	"github.com/google/trillian/crypto/keyspb"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/memory"
+	"github.com/google/trillian/storage/testonly"
+	"github.com/google/trillian/testonly/flagsaver"
+
+	envproto "github.com/google/trillian/crypto/keys/envelope/proto"
+)
+
+func TestRewrapTrees(t *testing.T) {
+	defer flagsaver.Save().MustRestore()
+	ctx := context.Background()
+
+	dir, err := ioutil.TempDir("", "rewrap")
+	if err != nil {
+		t.Fatalf("TempDir(): %v", err)
+	}
+	defer os.RemoveAll(dir)
+	for _, kekID := range []string{"kek1", "kek2"} {
+		kek := make([]byte, 32)
+		if _, err := rand.Read(kek); err != nil {
+			t.Fatalf("Read(): %v", err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, kekID), kek, 0600); err != nil {
+			t.Fatalf("WriteFile(): %v", err)
+		}
+	}
+	// The envelope ProtoHandler is used by storage to validate updated trees,
+	// so it must use the same KEKs.
+	if err := flag.Set("envelope_kek_dir", dir); err != nil {
+		t.Fatalf("flag.Set(): %v", err)
+	}
+	p, err := envproto.KEKProviderFromFlags()
+	if err != nil {
+		t.Fatalf("KEKProviderFromFlags(): %v", err)
+	}
+
+	as := memory.NewAdminStorage(memory.NewTreeStorage())
+	logTree, err := storage.CreateTree(ctx, as, testonly.LogTree)
+	if err != nil {
+		t.Fatalf("CreateTree(): %v", err)
+	}
+	mapTree, err := storage.CreateTree(ctx, as, testonly.MapTree)
+	if err != nil {
+		t.Fatalf("CreateTree(): %v", err)
+	}
+
+	for _, test := range []struct {
+		desc      string
+		kekID     string
+		treeID    int64
+		dryRun    bool
+		wantN     int
+		wantKEKID map[int64]string // empty string means not wrapped
+	}{
+		{
+			desc:      "dryRun",
+			kekID:     "kek1",
+			dryRun:    true,
+			wantN:     2,
+			wantKEKID: map[int64]string{logTree.TreeId: "", mapTree.TreeId: ""},
+		},
+		{
+			desc:      "allTrees",
+			kekID:     "kek1",
+			wantN:     2,
+			wantKEKID: map[int64]string{logTree.TreeId: "kek1", mapTree.TreeId: "kek1"},
+		},
+		{
+			desc:      "alreadyWrapped",
+			kekID:     "kek1",
+			wantN:     0,
+			wantKEKID: map[int64]string{logTree.TreeId: "kek1", mapTree.TreeId: "kek1"},
+		},
+		{
+			desc:      "singleTree",
+			kekID:     "kek2",
+			treeID:    logTree.TreeId,
+			wantN:     1,
+			wantKEKID: map[int64]string{logTree.TreeId: "kek2", mapTree.TreeId: "kek1"},
+		},
+	} {
+		n, err := rewrapTrees(ctx, as, p, test.kekID, test.treeID, test.dryRun)
+		if err != nil {
+			t.Errorf("%v: rewrapTrees() = (_, %v), want (_, nil)", test.desc, err)
+			continue
+		}
+		if n != test.wantN {
+			t.Errorf("%v: rewrapTrees() = (%v, nil), want (%v, nil)", test.desc, n, test.wantN)
+		}
+
+		for treeID, wantKEKID := range test.wantKEKID {
+			tree, err := storage.GetTree(ctx, as, treeID)
+			if err != nil {
+				t.Fatalf("%v: GetTree(%v): %v", test.desc, treeID, err)
+			}
+			var keyProto ptypes.DynamicAny
+			if err := ptypes.UnmarshalAny(tree.PrivateKey, &keyProto); err != nil {
+				t.Fatalf("%v: UnmarshalAny(): %v", test.desc, err)
+			}
+			var gotKEKID string
+			if pb, ok := keyProto.Message.(*keyspb.EncryptedPrivateKey); ok {
+				gotKEKID = pb.KekId
+			}
+			if gotKEKID != wantKEKID {
+				t.Errorf("%v: tree %v: private key wrapped with KEK %q, want %q", test.desc, treeID, gotKEKID, wantKEKID)
+			}
+			if _, err := keys.NewSigner(ctx, keyProto.Message); err != nil {
+				t.Errorf("%v: tree %v: NewSigner(): %v", test.desc, treeID, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package main contains the implementation and entry point for the verifylog
+// command, which audits a log by downloading all of its leaves and checking
+// that they match its latest signed root.
+//
+// Roots verified by previous runs are kept in --checkpoint_file, one per line
+// as "<tree size> <base64 root hash>". Each run checks that the latest root is
+// consistent with all of them, and then appends it to the file.
+//
+// Example usage:
+// $ ./verifylog --log_server=host:port --log_id=logid --checkpoint_file=log.checkpoints
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/google/trillian"
+	"github.com/google/trillian/client"
+	"github.com/google/trillian/client/rpcflags"
+	"github.com/google/trillian/types"
+	"google.golang.org/grpc"
+
+	// Load hashers
+	_ "github.com/google/trillian/merkle/rfc6962"
+)
+
+var (
+	logServerAddr   = flag.String("log_server", "", "Address of the gRPC Trillian Log Server (host:port)")
+	adminServerAddr = flag.String("admin_server", "", "Address of the gRPC Trillian Admin Server (host:port), used to read the log's configuration; defaults to --log_server")
+	logID           = flag.Int64("log_id", 0, "Trillian LogID to verify")
+	batchSize       = flag.Int64("batch_size", 1000, "Number of leaves to fetch per request")
+	checkpointFile  = flag.String("checkpoint_file", "", "File holding the roots verified by previous runs, which the latest root is appended to; optional")
+)
+
+func main() {
+	flag.Parse()
+	defer glog.Flush()
+	ctx := context.Background()
+
+	if *batchSize <= 0 {
+		glog.Exit("--batch_size must be positive")
+	}
+	var checkpoints []checkpoint
+	if *checkpointFile != "" {
+		var err error
+		if checkpoints, err = readCheckpoints(*checkpointFile); err != nil {
+			glog.Exitf("Failed to read checkpoints: %v", err)
+		}
+	}
+
+	dialOpts, err := rpcflags.NewClientDialOptionsFromFlags()
+	if err != nil {
+		glog.Exitf("Failed to determine dial options: %v", err)
+	}
+	conn, err := grpc.Dial(*logServerAddr, dialOpts...)
+	if err != nil {
+		glog.Exitf("Failed to dial %v: %v", *logServerAddr, err)
+	}
+	defer conn.Close()
+	adminConn := conn
+	if *adminServerAddr != "" {
+		if adminConn, err = grpc.Dial(*adminServerAddr, dialOpts...); err != nil {
+			glog.Exitf("Failed to dial %v: %v", *adminServerAddr, err)
+		}
+		defer adminConn.Close()
+	}
+
+	tree, err := trillian.NewTrillianAdminClient(adminConn).GetTree(ctx, &trillian.GetTreeRequest{TreeId: *logID})
+	if err != nil {
+		glog.Exitf("Failed to get tree %d: %v", *logID, err)
+	}
+	logVerifier, err := client.NewLogVerifierFromTree(tree)
+	if err != nil {
+		glog.Exitf("Failed to create log verifier: %v", err)
+	}
+
+	v := newVerifier(trillian.NewTrillianLogClient(conn), *logID, logVerifier, *batchSize)
+	root, err := v.verify(ctx, checkpoints)
+	if err != nil {
+		if mErr, ok := err.(*mismatchError); ok {
+			glog.Exitf("Log %d is corrupt: first mismatching leaf index %d: %s", *logID, mErr.Index, mErr.Reason)
+		}
+		glog.Exitf("Failed to verify log %d: %v", *logID, err)
+	}
+	glog.Infof("Verified log %d at size %d", *logID, root.TreeSize)
+
+	if *checkpointFile != "" {
+		if err := appendCheckpoint(*checkpointFile, root); err != nil {
+			glog.Exitf("Failed to save checkpoint: %v", err)
+		}
+	}
+}
+
+// readCheckpoints returns the checkpoints saved in file, or none if the file
+// doesn't exist.
+func readCheckpoints(file string) ([]checkpoint, error) {
+	f, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var checkpoints []checkpoint
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		cp, err := parseCheckpoint(text)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", file, line, err)
+		}
+		checkpoints = append(checkpoints, cp)
+	}
+	return checkpoints, scanner.Err()
+}
+
+// parseCheckpoint parses a "<tree size> <base64 root hash>" line.
+func parseCheckpoint(text string) (checkpoint, error) {
+	fields := strings.Fields(text)
+	if len(fields) != 2 {
+		return checkpoint{}, fmt.Errorf("got %d fields, want 2", len(fields))
+	}
+	size, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return checkpoint{}, fmt.Errorf("invalid tree size: %v", err)
+	}
+	hash, err := base64.StdEncoding.DecodeString(fields[1])
+	if err != nil {
+		return checkpoint{}, fmt.Errorf("invalid root hash: %v", err)
+	}
+	return checkpoint{TreeSize: size, RootHash: hash}, nil
+}
+
+// appendCheckpoint adds root to the checkpoints saved in file.
+func appendCheckpoint(file string, root *types.LogRootV1) error {
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(f, "%d %s\n", root.TreeSize, base64.StdEncoding.EncodeToString(root.RootHash)); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
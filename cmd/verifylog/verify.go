@@ -0,0 +1,239 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/golang/glog"
+	"github.com/google/trillian"
+	"github.com/google/trillian/client"
+	"github.com/google/trillian/merkle"
+	"github.com/google/trillian/merkle/compact"
+	"github.com/google/trillian/types"
+)
+
+// checkpoint is a log root saved by a previous run.
+type checkpoint struct {
+	TreeSize uint64
+	RootHash []byte
+}
+
+// mismatchError reports the first leaf at which the contents of the log
+// disagree with its signed root.
+type mismatchError struct {
+	Index  int64
+	Reason string
+}
+
+func (e *mismatchError) Error() string {
+	return fmt.Sprintf("mismatch at leaf index %d: %s", e.Index, e.Reason)
+}
+
+// verifier audits a log by downloading all of its leaves.
+type verifier struct {
+	client    trillian.TrillianLogClient
+	logID     int64
+	verifier  *client.LogVerifier
+	batchSize int64
+
+	merkle merkle.LogVerifier
+	fact   compact.RangeFactory
+}
+
+func newVerifier(cl trillian.TrillianLogClient, logID int64, v *client.LogVerifier, batchSize int64) *verifier {
+	return &verifier{
+		client:    cl,
+		logID:     logID,
+		verifier:  v,
+		batchSize: batchSize,
+		merkle:    merkle.NewLogVerifier(v.Hasher),
+		fact:      compact.RangeFactory{Hash: v.Hasher.HashChildren},
+	}
+}
+
+// verify fetches and verifies the latest root of the log, checks that it is
+// consistent with all of the checkpoints, and recomputes it from the leaves
+// of the log. It returns the verified root. If a leaf doesn't match the root,
+// the returned error is a *mismatchError identifying the first such leaf.
+func (v *verifier) verify(ctx context.Context, checkpoints []checkpoint) (*types.LogRootV1, error) {
+	resp, err := v.client.GetLatestSignedLogRoot(ctx, &trillian.GetLatestSignedLogRootRequest{LogId: v.logID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest root: %v", err)
+	}
+	root, err := v.verifier.VerifyRoot(&types.LogRootV1{}, resp.GetSignedLogRoot(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify latest root: %v", err)
+	}
+	glog.Infof("Latest root: size %d, hash %x", root.TreeSize, root.RootHash)
+
+	sort.Slice(checkpoints, func(i, j int) bool { return checkpoints[i].TreeSize < checkpoints[j].TreeSize })
+	for _, cp := range checkpoints {
+		if err := v.checkConsistency(ctx, cp.TreeSize, cp.RootHash, root); err != nil {
+			return nil, fmt.Errorf("checkpoint at size %d: %v", cp.TreeSize, err)
+		}
+	}
+
+	// Rebuild the tree from the leaves, checking at the end of each batch that
+	// the tree built so far is a prefix of the signed one.
+	cr := v.fact.NewEmptyRange(0)
+	for start := int64(0); start < int64(root.TreeSize); {
+		count := int64(root.TreeSize) - start
+		if count > v.batchSize {
+			count = v.batchSize
+		}
+		leaves, err := v.getLeaves(ctx, start, count)
+		if err != nil {
+			return nil, err
+		}
+
+		batchStart, err := v.fact.NewRange(cr.Begin(), cr.End(), append([][]byte(nil), cr.Hashes()...))
+		if err != nil {
+			return nil, err
+		}
+		for _, leaf := range leaves {
+			if err := cr.Append(leaf.MerkleLeafHash, nil); err != nil {
+				return nil, err
+			}
+		}
+		end := start + int64(len(leaves))
+		prefix, err := v.rootHash(cr)
+		if err != nil {
+			return nil, err
+		}
+		// Only a proof that fails to verify implicates the leaves. Errors
+		// fetching it are returned as is.
+		proof, err := v.consistencyProof(ctx, uint64(end), root)
+		if err != nil {
+			return nil, err
+		}
+		if err := v.merkle.VerifyConsistencyProof(end, int64(root.TreeSize), prefix, root.RootHash, proof); err != nil {
+			return nil, v.findMismatch(ctx, batchStart, leaves, root)
+		}
+		glog.V(1).Infof("Verified leaves [%d, %d)", start, end)
+		start = end
+	}
+
+	if got, err := v.rootHash(cr); err != nil {
+		return nil, err
+	} else if !bytes.Equal(got, root.RootHash) {
+		// Can't happen, as the last batch was checked against the root.
+		return nil, fmt.Errorf("recomputed root hash %x, want %x", got, root.RootHash)
+	}
+	return root, nil
+}
+
+// getLeaves fetches the leaves [start, start+count), or a prefix of them if
+// the server returns fewer, and checks that each is correctly indexed and
+// hashed.
+func (v *verifier) getLeaves(ctx context.Context, start, count int64) ([]*trillian.LogLeaf, error) {
+	resp, err := v.client.GetLeavesByRange(ctx, &trillian.GetLeavesByRangeRequest{LogId: v.logID, StartIndex: start, Count: count})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leaves [%d, %d): %v", start, start+count, err)
+	}
+	leaves := resp.GetLeaves()
+	if len(leaves) == 0 {
+		return nil, fmt.Errorf("no leaves returned from index %d", start)
+	}
+	if int64(len(leaves)) > count {
+		leaves = leaves[:count]
+	}
+	for i, leaf := range leaves {
+		index := start + int64(i)
+		if leaf.LeafIndex != index {
+			return nil, &mismatchError{Index: index, Reason: fmt.Sprintf("got leaf with index %d", leaf.LeafIndex)}
+		}
+		if want := v.verifier.Hasher.HashLeaf(leaf.LeafValue); !bytes.Equal(leaf.MerkleLeafHash, want) {
+			return nil, &mismatchError{Index: index, Reason: fmt.Sprintf("leaf hash %x, want %x", leaf.MerkleLeafHash, want)}
+		}
+	}
+	return leaves, nil
+}
+
+// checkConsistency checks that the tree of the given size and root hash is a
+// prefix of the tree with the given root.
+func (v *verifier) checkConsistency(ctx context.Context, size uint64, rootHash []byte, root *types.LogRootV1) error {
+	proof, err := v.consistencyProof(ctx, size, root)
+	if err != nil {
+		return err
+	}
+	return v.merkle.VerifyConsistencyProof(int64(size), int64(root.TreeSize), rootHash, root.RootHash, proof)
+}
+
+// consistencyProof fetches a proof that the tree of the given size is a prefix
+// of the tree with the given root.
+func (v *verifier) consistencyProof(ctx context.Context, size uint64, root *types.LogRootV1) ([][]byte, error) {
+	if size > root.TreeSize {
+		return nil, fmt.Errorf("tree size %d is larger than the latest root's %d", size, root.TreeSize)
+	}
+	if size == 0 || size == root.TreeSize {
+		return nil, nil
+	}
+	resp, err := v.client.GetConsistencyProof(ctx, &trillian.GetConsistencyProofRequest{
+		LogId:          v.logID,
+		FirstTreeSize:  int64(size),
+		SecondTreeSize: int64(root.TreeSize),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get consistency proof from %d to %d: %v", size, root.TreeSize, err)
+	}
+	return resp.GetProof().GetHashes(), nil
+}
+
+// findMismatch returns a *mismatchError for the first of leaves that makes
+// the tree inconsistent with root, given that the tree represented by cr,
+// before leaves are appended, is consistent with it.
+func (v *verifier) findMismatch(ctx context.Context, cr *compact.Range, leaves []*trillian.LogLeaf, root *types.LogRootV1) error {
+	// prefixes[i] is the root hash of the tree ending with leaves[i].
+	prefixes := make([][]byte, len(leaves))
+	for i, leaf := range leaves {
+		if err := cr.Append(leaf.MerkleLeafHash, nil); err != nil {
+			return err
+		}
+		hash, err := v.rootHash(cr)
+		if err != nil {
+			return err
+		}
+		prefixes[i] = hash
+	}
+
+	// Once a leaf breaks consistency all larger trees are inconsistent too,
+	// so binary search for it. The last tree is known to be inconsistent.
+	var fetchErr error
+	i := sort.Search(len(leaves)-1, func(i int) bool {
+		size := uint64(leaves[i].LeafIndex + 1)
+		proof, err := v.consistencyProof(ctx, size, root)
+		if err != nil {
+			fetchErr = err
+			return true
+		}
+		return v.merkle.VerifyConsistencyProof(int64(size), int64(root.TreeSize), prefixes[i], root.RootHash, proof) != nil
+	})
+	if fetchErr != nil {
+		return fetchErr
+	}
+	return &mismatchError{Index: leaves[i].LeafIndex, Reason: "tree including leaf is inconsistent with the latest root"}
+}
+
+// rootHash returns the root hash of the tree represented by cr.
+func (v *verifier) rootHash(cr *compact.Range) ([]byte, error) {
+	if cr.End() == 0 {
+		return v.verifier.Hasher.EmptyRoot(), nil
+	}
+	return cr.GetRootHash(nil)
+}
@@ -0,0 +1,270 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/trillian"
+	"github.com/google/trillian/client"
+	"github.com/google/trillian/merkle"
+	"github.com/google/trillian/merkle/rfc6962"
+	"github.com/google/trillian/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	tcrypto "github.com/google/trillian/crypto"
+)
+
+const testLogID = 42
+
+// fakeLog is a TrillianLogClient serving the roots and proofs of an in-memory
+// log, and leaves which may differ from the ones the log was built from.
+type fakeLog struct {
+	trillian.TrillianLogClient
+	signer *tcrypto.Signer
+	tree   *merkle.InMemoryMerkleTree
+	leaves []*trillian.LogLeaf
+	// proofErrs is the number of GetConsistencyProof calls left to fail.
+	proofErrs int
+}
+
+func newFakeLog(t *testing.T, signer *tcrypto.Signer, size int) *fakeLog {
+	t.Helper()
+	f := &fakeLog{signer: signer, tree: merkle.NewInMemoryMerkleTree(rfc6962.DefaultHasher)}
+	for i := 0; i < size; i++ {
+		value := []byte(fmt.Sprintf("leaf %d", i))
+		_, entry := f.tree.AddLeaf(value)
+		f.leaves = append(f.leaves, &trillian.LogLeaf{LeafIndex: int64(i), LeafValue: value, MerkleLeafHash: entry.Hash()})
+	}
+	return f
+}
+
+func (f *fakeLog) GetLatestSignedLogRoot(ctx context.Context, req *trillian.GetLatestSignedLogRootRequest, opts ...grpc.CallOption) (*trillian.GetLatestSignedLogRootResponse, error) {
+	root := &types.LogRootV1{TreeSize: uint64(f.tree.LeafCount()), RootHash: f.tree.CurrentRoot().Hash()}
+	slr, err := f.signer.SignLogRoot(root)
+	if err != nil {
+		return nil, err
+	}
+	return &trillian.GetLatestSignedLogRootResponse{SignedLogRoot: slr}, nil
+}
+
+func (f *fakeLog) GetLeavesByRange(ctx context.Context, req *trillian.GetLeavesByRangeRequest, opts ...grpc.CallOption) (*trillian.GetLeavesByRangeResponse, error) {
+	end := req.StartIndex + req.Count
+	if end > int64(len(f.leaves)) {
+		end = int64(len(f.leaves))
+	}
+	return &trillian.GetLeavesByRangeResponse{Leaves: f.leaves[req.StartIndex:end]}, nil
+}
+
+func (f *fakeLog) GetConsistencyProof(ctx context.Context, req *trillian.GetConsistencyProofRequest, opts ...grpc.CallOption) (*trillian.GetConsistencyProofResponse, error) {
+	if f.proofErrs > 0 {
+		f.proofErrs--
+		return nil, status.Error(codes.Unavailable, "unavailable")
+	}
+	proof := &trillian.Proof{}
+	for _, node := range f.tree.SnapshotConsistency(req.FirstTreeSize, req.SecondTreeSize) {
+		proof.Hashes = append(proof.Hashes, node.Value.Hash())
+	}
+	return &trillian.GetConsistencyProofResponse{Proof: proof}, nil
+}
+
+// replaceLeaf serves a different, correctly hashed, leaf at index.
+func (f *fakeLog) replaceLeaf(index int) {
+	value := []byte("replaced")
+	f.leaves[index] = &trillian.LogLeaf{LeafIndex: int64(index), LeafValue: value, MerkleLeafHash: rfc6962.DefaultHasher.HashLeaf(value)}
+}
+
+func TestVerify(t *testing.T) {
+	ctx := context.Background()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey(): %v", err)
+	}
+	signer := tcrypto.NewSigner(0, key, crypto.SHA256)
+	logVerifier := client.NewLogVerifier(rfc6962.DefaultHasher, key.Public(), crypto.SHA256)
+	root := func(size int64) checkpoint {
+		log := newFakeLog(t, signer, int(size))
+		return checkpoint{TreeSize: uint64(size), RootHash: log.tree.CurrentRoot().Hash()}
+	}
+
+	for _, test := range []struct {
+		desc        string
+		size        int
+		batchSize   int64
+		modify      func(f *fakeLog)
+		checkpoints []checkpoint
+		wantErr     bool
+		// wantIndex is the index of the first mismatching leaf, if not -1.
+		wantIndex int64
+	}{
+		{desc: "empty", size: 0, batchSize: 10, wantIndex: -1},
+		{desc: "valid", size: 95, batchSize: 10, wantIndex: -1},
+		{desc: "validSingleBatch", size: 95, batchSize: 1000, wantIndex: -1},
+		{
+			desc:        "checkpoints",
+			size:        95,
+			batchSize:   10,
+			checkpoints: []checkpoint{root(95), root(1), root(0), root(42)},
+			wantIndex:   -1,
+		},
+		{
+			desc:        "forkedCheckpoint",
+			size:        95,
+			batchSize:   10,
+			checkpoints: []checkpoint{{TreeSize: 42, RootHash: []byte("fork")}},
+			wantErr:     true,
+			wantIndex:   -1,
+		},
+		{
+			desc:        "futureCheckpoint",
+			size:        95,
+			batchSize:   10,
+			checkpoints: []checkpoint{root(96)},
+			wantErr:     true,
+			wantIndex:   -1,
+		},
+		{
+			desc:      "proofError",
+			size:      95,
+			batchSize: 10,
+			modify:    func(f *fakeLog) { f.proofErrs = 1 },
+			wantErr:   true,
+			wantIndex: -1,
+		},
+		{
+			desc:      "wrongLeafHash",
+			size:      95,
+			batchSize: 10,
+			modify:    func(f *fakeLog) { f.leaves[17].MerkleLeafHash = []byte("hash") },
+			wantErr:   true,
+			wantIndex: 17,
+		},
+		{
+			desc:      "wrongLeafIndex",
+			size:      95,
+			batchSize: 10,
+			modify:    func(f *fakeLog) { f.leaves[3].LeafIndex = 4 },
+			wantErr:   true,
+			wantIndex: 3,
+		},
+		{
+			desc:      "replacedLeaf",
+			size:      95,
+			batchSize: 10,
+			modify:    func(f *fakeLog) { f.replaceLeaf(37) },
+			wantErr:   true,
+			wantIndex: 37,
+		},
+		{
+			desc:      "replacedFirstLeaf",
+			size:      95,
+			batchSize: 10,
+			modify:    func(f *fakeLog) { f.replaceLeaf(0) },
+			wantErr:   true,
+			wantIndex: 0,
+		},
+		{
+			desc:      "replacedLastLeaf",
+			size:      95,
+			batchSize: 10,
+			modify:    func(f *fakeLog) { f.replaceLeaf(94) },
+			wantErr:   true,
+			wantIndex: 94,
+		},
+		{
+			desc:      "replacedLeaves",
+			size:      95,
+			batchSize: 1000,
+			modify:    func(f *fakeLog) { f.replaceLeaf(80); f.replaceLeaf(51) },
+			wantErr:   true,
+			wantIndex: 51,
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			log := newFakeLog(t, signer, test.size)
+			if test.modify != nil {
+				test.modify(log)
+			}
+			v := newVerifier(log, testLogID, logVerifier, test.batchSize)
+
+			got, err := v.verify(ctx, test.checkpoints)
+			if gotErr := err != nil; gotErr != test.wantErr {
+				t.Fatalf("verify(): %v, wantErr %v", err, test.wantErr)
+			}
+			mErr, isMismatch := err.(*mismatchError)
+			if wantMismatch := test.wantIndex >= 0; isMismatch != wantMismatch {
+				t.Fatalf("verify(): %v, want mismatch %v", err, wantMismatch)
+			}
+			if isMismatch && mErr.Index != test.wantIndex {
+				t.Errorf("verify(): mismatch at index %d, want %d", mErr.Index, test.wantIndex)
+			}
+			if err == nil && got.TreeSize != uint64(test.size) {
+				t.Errorf("verify(): root of size %d, want %d", got.TreeSize, test.size)
+			}
+		})
+	}
+}
+
+func TestCheckpointFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "verifylog")
+	if err != nil {
+		t.Fatalf("TempDir(): %v", err)
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "checkpoints")
+
+	if got, err := readCheckpoints(file); err != nil || len(got) != 0 {
+		t.Fatalf("readCheckpoints() of missing file: %v, %v, want no checkpoints", got, err)
+	}
+	roots := []*types.LogRootV1{
+		{TreeSize: 10, RootHash: []byte("root 10")},
+		{TreeSize: 25, RootHash: []byte("root 25")},
+	}
+	for _, root := range roots {
+		if err := appendCheckpoint(file, root); err != nil {
+			t.Fatalf("appendCheckpoint(): %v", err)
+		}
+	}
+	got, err := readCheckpoints(file)
+	if err != nil {
+		t.Fatalf("readCheckpoints(): %v", err)
+	}
+	if len(got) != len(roots) {
+		t.Fatalf("readCheckpoints(): %d checkpoints, want %d", len(got), len(roots))
+	}
+	for i, root := range roots {
+		if got[i].TreeSize != root.TreeSize || string(got[i].RootHash) != string(root.RootHash) {
+			t.Errorf("readCheckpoints()[%d]: %v, want size %d and hash %q", i, got[i], root.TreeSize, root.RootHash)
+		}
+	}
+
+	if err := ioutil.WriteFile(file, []byte("10 cm9vdA==\nnot a checkpoint\n"), 0644); err != nil {
+		t.Fatalf("WriteFile(): %v", err)
+	}
+	if _, err := readCheckpoints(file); err == nil {
+		t.Error("readCheckpoints() of malformed file: nil, want error")
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package main contains the implementation and entry point for the witness
+// command, which watches a log and cosigns its roots.
+//
+// Each verified root is saved to --state_file before it is cosigned, so that
+// the witness remembers it across restarts. The log server must list the
+// witness's public key in its --witness_keys flag for the cosignatures to be
+// accepted.
+//
+// Example usage:
+// $ ./witness --log_server=host:port --log_id=logid --witness_name=alice --private_key_file=key.pem --state_file=alice.state
+package main
+
+import (
+	"context"
+	"flag"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/google/trillian"
+	"github.com/google/trillian/client"
+	"github.com/google/trillian/client/rpcflags"
+	"github.com/google/trillian/crypto/keys/pem"
+	"github.com/google/trillian/types"
+	"github.com/google/trillian/witness"
+	"google.golang.org/grpc"
+
+	// Load hashers
+	_ "github.com/google/trillian/merkle/rfc6962"
+)
+
+var (
+	logServerAddr   = flag.String("log_server", "", "Address of the gRPC Trillian Log Server (host:port)")
+	adminServerAddr = flag.String("admin_server", "", "Address of the gRPC Trillian Admin Server (host:port), used to read the log's configuration; defaults to --log_server")
+	logID           = flag.Int64("log_id", 0, "Trillian LogID to witness")
+	witnessName     = flag.String("witness_name", "", "Name of the witness, as known to the log server")
+	privateKeyFile  = flag.String("private_key_file", "", "PEM file holding the private key used to cosign log roots")
+	privateKeyPass  = flag.String("private_key_password", "", "Password of the private key")
+	stateFile       = flag.String("state_file", "", "File holding the latest verified log root, read at startup and updated as the log grows")
+	interval        = flag.Duration("interval", 10*time.Second, "Interval between checks for new log roots")
+)
+
+func main() {
+	flag.Parse()
+	defer glog.Flush()
+	ctx := context.Background()
+
+	if *witnessName == "" || *stateFile == "" {
+		glog.Exit("--witness_name and --state_file must be set")
+	}
+	signer, err := pem.ReadPrivateKeyFile(*privateKeyFile, *privateKeyPass)
+	if err != nil {
+		glog.Exitf("Failed to read private key: %v", err)
+	}
+	trusted, err := readState(*stateFile)
+	if err != nil {
+		glog.Exitf("Failed to read state: %v", err)
+	}
+
+	dialOpts, err := rpcflags.NewClientDialOptionsFromFlags()
+	if err != nil {
+		glog.Exitf("Failed to determine dial options: %v", err)
+	}
+	conn, err := grpc.Dial(*logServerAddr, dialOpts...)
+	if err != nil {
+		glog.Exitf("Failed to dial %v: %v", *logServerAddr, err)
+	}
+	defer conn.Close()
+	adminConn := conn
+	if *adminServerAddr != "" {
+		if adminConn, err = grpc.Dial(*adminServerAddr, dialOpts...); err != nil {
+			glog.Exitf("Failed to dial %v: %v", *adminServerAddr, err)
+		}
+		defer adminConn.Close()
+	}
+
+	tree, err := trillian.NewTrillianAdminClient(adminConn).GetTree(ctx, &trillian.GetTreeRequest{TreeId: *logID})
+	if err != nil {
+		glog.Exitf("Failed to get tree %d: %v", *logID, err)
+	}
+	verifier, err := client.NewLogVerifierFromTree(tree)
+	if err != nil {
+		glog.Exitf("Failed to create log verifier: %v", err)
+	}
+
+	w := witness.New(*witnessName, *logID, trillian.NewTrillianLogClient(conn), verifier, signer, trusted)
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+	// Each new root is saved before it is cosigned, so the witness never
+	// forgets a root that it has cosigned.
+	persist := func(root types.LogRootV1) error {
+		return writeState(*stateFile, &root)
+	}
+	for {
+		root, err := w.Update(ctx, persist)
+		if err != nil {
+			glog.Warningf("Failed to witness log %d: %v", *logID, err)
+		} else {
+			glog.V(1).Infof("Cosigned root of log %d at size %d", *logID, root.TreeSize)
+		}
+		<-ticker.C
+	}
+}
+
+// readState returns the log root saved in file, or a zero root if the file
+// doesn't exist.
+func readState(file string) (types.LogRootV1, error) {
+	var root types.LogRootV1
+	data, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return root, nil
+	} else if err != nil {
+		return root, err
+	}
+	err = root.UnmarshalBinary(data)
+	return root, err
+}
+
+// writeState saves root in file, replacing it atomically. The data is synced
+// to disk before writeState returns.
+func writeState(file string, root *types.LogRootV1) error {
+	data, err := root.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	tmp := file + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
@@ -0,0 +1,160 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package envelope provides envelope encryption of private keys.
+//
+// A private key is encrypted with a random data key, which is in turn
+// encrypted ("wrapped") by a key-encryption key (KEK). Only the wrapped data
+// key is stored alongside the encrypted private key, in a
+// keyspb.EncryptedPrivateKey, so reading the stored key is not enough to
+// recover it: the KEK must also be available, through a KEKProvider.
+package envelope
+
+import (
+	"context"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian/crypto/keys/der"
+	"github.com/google/trillian/crypto/keyspb"
+)
+
+// dataKeySize is the size of data keys, in bytes. Data keys are AES-256 keys.
+const dataKeySize = 32
+
+// ErrUnsupportedKey is returned by Rewrap for key protos that can't be
+// envelope-encrypted, e.g. because the key is held by an HSM.
+var ErrUnsupportedKey = errors.New("envelope: unsupported private key proto")
+
+// KEKProvider wraps and unwraps data keys using key-encryption keys.
+type KEKProvider interface {
+	// WrapKey encrypts dataKey with the key-encryption key identified by kekID.
+	WrapKey(ctx context.Context, kekID string, dataKey []byte) ([]byte, error)
+	// UnwrapKey decrypts a data key previously wrapped by WrapKey with the same
+	// key-encryption key.
+	UnwrapKey(ctx context.Context, kekID string, wrappedKey []byte) ([]byte, error)
+}
+
+// Seal encrypts a private key with a new data key, wrapped by the
+// key-encryption key kekID.
+func Seal(ctx context.Context, p KEKProvider, kekID string, key *keyspb.PrivateKey) (*keyspb.EncryptedPrivateKey, error) {
+	if len(key.GetDer()) == 0 {
+		return nil, errors.New("envelope: private key has no DER")
+	}
+
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, fmt.Errorf("envelope: error generating data key: %v", err)
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("envelope: error generating nonce: %v", err)
+	}
+
+	wrappedKey, err := p.WrapKey(ctx, kekID, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("envelope: error wrapping data key with KEK %q: %v", kekID, err)
+	}
+
+	return &keyspb.EncryptedPrivateKey{
+		KekId:          kekID,
+		WrappedDataKey: wrappedKey,
+		Nonce:          nonce,
+		EncryptedDer:   aead.Seal(nil, nonce, key.Der, []byte(kekID)),
+	}, nil
+}
+
+// Open decrypts a private key sealed by Seal.
+func Open(ctx context.Context, p KEKProvider, pb *keyspb.EncryptedPrivateKey) (*keyspb.PrivateKey, error) {
+	dataKey, err := p.UnwrapKey(ctx, pb.GetKekId(), pb.GetWrappedDataKey())
+	if err != nil {
+		return nil, fmt.Errorf("envelope: error unwrapping data key with KEK %q: %v", pb.GetKekId(), err)
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	if got, want := len(pb.GetNonce()), aead.NonceSize(); got != want {
+		return nil, fmt.Errorf("envelope: nonce is %d bytes, want %d", got, want)
+	}
+
+	keyDER, err := aead.Open(nil, pb.Nonce, pb.GetEncryptedDer(), []byte(pb.KekId))
+	if err != nil {
+		return nil, fmt.Errorf("envelope: error decrypting private key: %v", err)
+	}
+	return &keyspb.PrivateKey{Der: keyDER}, nil
+}
+
+// FromProto decrypts an EncryptedPrivateKey and returns the private key
+// contained within.
+func FromProto(ctx context.Context, p KEKProvider, pb *keyspb.EncryptedPrivateKey) (crypto.Signer, error) {
+	key, err := Open(ctx, p, pb)
+	if err != nil {
+		return nil, err
+	}
+	return der.FromProto(key)
+}
+
+// NewProtoFromSpec creates a new private key based on a key specification.
+// It returns an EncryptedPrivateKey protobuf message that contains the private
+// key, wrapped by the key-encryption key kekID.
+func NewProtoFromSpec(ctx context.Context, p KEKProvider, kekID string, spec *keyspb.Specification) (*keyspb.EncryptedPrivateKey, error) {
+	key, err := der.NewProtoFromSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	return Seal(ctx, p, kekID, key)
+}
+
+// Rewrap returns keyProto envelope-encrypted with the key-encryption key
+// kekID. keyProto may be a PrivateKey, which is sealed, or an
+// EncryptedPrivateKey, which is re-sealed unless it's already wrapped by kekID.
+// Returns ErrUnsupportedKey for any other type of key proto.
+func Rewrap(ctx context.Context, p KEKProvider, kekID string, keyProto proto.Message) (*keyspb.EncryptedPrivateKey, error) {
+	switch pb := keyProto.(type) {
+	case *keyspb.PrivateKey:
+		return Seal(ctx, p, kekID, pb)
+	case *keyspb.EncryptedPrivateKey:
+		if pb.KekId == kekID {
+			return pb, nil
+		}
+		key, err := Open(ctx, p, pb)
+		if err != nil {
+			return nil, err
+		}
+		return Seal(ctx, p, kekID, key)
+	}
+	return nil, ErrUnsupportedKey
+}
+
+// newAEAD returns an AES-256-GCM cipher that uses key.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if got, want := len(key), dataKeySize; got != want {
+		return nil, fmt.Errorf("envelope: key is %d bytes, want %d", got, want)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("envelope: error creating cipher: %v", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,133 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian/crypto/keys/testonly"
+	"github.com/google/trillian/crypto/keyspb"
+)
+
+// newTestProvider returns a FileKEKProvider with a random KEK for each of
+// kekIDs. The returned func removes the KEK directory.
+func newTestProvider(t *testing.T, kekIDs ...string) (*FileKEKProvider, func()) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "envelope")
+	if err != nil {
+		t.Fatalf("TempDir(): %v", err)
+	}
+	for _, kekID := range kekIDs {
+		kek := make([]byte, 32)
+		if _, err := rand.Read(kek); err != nil {
+			t.Fatalf("Read(): %v", err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, kekID), kek, 0600); err != nil {
+			t.Fatalf("WriteFile(): %v", err)
+		}
+	}
+	return NewFileKEKProvider(dir), func() { os.RemoveAll(dir) }
+}
+
+func TestSealOpen(t *testing.T) {
+	ctx := context.Background()
+	p, cleanup := newTestProvider(t, "kek1", "kek2")
+	defer cleanup()
+
+	key, err := NewProtoFromSpec(ctx, p, "kek1", &keyspb.Specification{Params: &keyspb.Specification_EcdsaParams{}})
+	if err != nil {
+		t.Fatalf("NewProtoFromSpec(): %v", err)
+	}
+	signer, err := FromProto(ctx, p, key)
+	if err != nil {
+		t.Fatalf("FromProto(): %v", err)
+	}
+	if err := testonly.SignAndVerify(signer, signer.Public()); err != nil {
+		t.Errorf("SignAndVerify() = %q, want nil", err)
+	}
+
+	for _, test := range []struct {
+		desc   string
+		modify func(*keyspb.EncryptedPrivateKey)
+	}{
+		{desc: "wrongKEK", modify: func(pb *keyspb.EncryptedPrivateKey) { pb.KekId = "kek2" }},
+		{desc: "unknownKEK", modify: func(pb *keyspb.EncryptedPrivateKey) { pb.KekId = "kek3" }},
+		{desc: "escapingKEK", modify: func(pb *keyspb.EncryptedPrivateKey) { pb.KekId = "../kek1" }},
+		{desc: "wrappedDataKeyTampered", modify: func(pb *keyspb.EncryptedPrivateKey) { pb.WrappedDataKey[len(pb.WrappedDataKey)-1] ^= 1 }},
+		{desc: "encryptedDERTampered", modify: func(pb *keyspb.EncryptedPrivateKey) { pb.EncryptedDer[0] ^= 1 }},
+		{desc: "nonceMissing", modify: func(pb *keyspb.EncryptedPrivateKey) { pb.Nonce = nil }},
+	} {
+		pb := proto.Clone(key).(*keyspb.EncryptedPrivateKey)
+		test.modify(pb)
+		if _, err := Open(ctx, p, pb); err == nil {
+			t.Errorf("%v: Open() = (_, nil), want error", test.desc)
+		}
+	}
+}
+
+func TestRewrap(t *testing.T) {
+	ctx := context.Background()
+	p, cleanup := newTestProvider(t, "kek1", "kek2")
+	defer cleanup()
+
+	plainKey := &keyspb.PrivateKey{Der: []byte("not really a key")}
+	wrappedKey, err := Seal(ctx, p, "kek1", plainKey)
+	if err != nil {
+		t.Fatalf("Seal(): %v", err)
+	}
+
+	for _, test := range []struct {
+		desc      string
+		keyProto  proto.Message
+		wantErr   error
+		wantSame  bool
+		wantKEKID string
+	}{
+		{desc: "plainKey", keyProto: plainKey, wantKEKID: "kek1"},
+		{desc: "sameKEK", keyProto: wrappedKey, wantKEKID: "kek1", wantSame: true},
+		{desc: "otherKEK", keyProto: wrappedKey, wantKEKID: "kek2"},
+		{desc: "unsupported", keyProto: &keyspb.PEMKeyFile{Path: "key.pem"}, wantKEKID: "kek1", wantErr: ErrUnsupportedKey},
+	} {
+		got, err := Rewrap(ctx, p, test.wantKEKID, test.keyProto)
+		if err != test.wantErr {
+			t.Errorf("%v: Rewrap() = (_, %v), want (_, %v)", test.desc, err, test.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if got.KekId != test.wantKEKID {
+			t.Errorf("%v: Rewrap().KekId = %q, want %q", test.desc, got.KekId, test.wantKEKID)
+		}
+		if isSame := got == test.keyProto; isSame != test.wantSame {
+			t.Errorf("%v: Rewrap() returned the same key: %v, want %v", test.desc, isSame, test.wantSame)
+		}
+		opened, err := Open(ctx, p, got)
+		if err != nil {
+			t.Errorf("%v: Open(): %v", test.desc, err)
+			continue
+		}
+		if !bytes.Equal(opened.Der, plainKey.Der) {
+			t.Errorf("%v: Open().Der = %q, want %q", test.desc, opened.Der, plainKey.Der)
+		}
+	}
+}
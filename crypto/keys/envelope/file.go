@@ -0,0 +1,79 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envelope
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+)
+
+// FileKEKProvider is a KEKProvider that reads key-encryption keys from local
+// files. Each KEK is a file in Dir, named by the KEK's ID, that holds a raw
+// 32-byte AES-256 key, e.g. as created by:
+//
+//	head -c 32 /dev/urandom > "${dir}/${kek_id}"
+type FileKEKProvider struct {
+	// Dir is the directory that holds the key-encryption keys.
+	Dir string
+}
+
+// NewFileKEKProvider returns a FileKEKProvider that reads key-encryption keys
+// from dir.
+func NewFileKEKProvider(dir string) *FileKEKProvider {
+	return &FileKEKProvider{Dir: dir}
+}
+
+// WrapKey encrypts dataKey with AES-256-GCM, using the KEK read from the file
+// named kekID. The random nonce is prepended to the result.
+func (f *FileKEKProvider) WrapKey(ctx context.Context, kekID string, dataKey []byte) ([]byte, error) {
+	aead, err := f.kek(kekID)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("error generating nonce: %v", err)
+	}
+	return aead.Seal(nonce, nonce, dataKey, nil), nil
+}
+
+// UnwrapKey decrypts a data key wrapped by WrapKey.
+func (f *FileKEKProvider) UnwrapKey(ctx context.Context, kekID string, wrappedKey []byte) ([]byte, error) {
+	aead, err := f.kek(kekID)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrappedKey) < aead.NonceSize() {
+		return nil, fmt.Errorf("wrapped key is %d bytes, too short to hold a nonce", len(wrappedKey))
+	}
+	nonce, ciphertext := wrappedKey[:aead.NonceSize()], wrappedKey[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, nil)
+}
+
+func (f *FileKEKProvider) kek(kekID string) (cipher.AEAD, error) {
+	// Don't let KEK IDs escape Dir.
+	if kekID == "" || kekID != filepath.Base(kekID) || kekID == "." || kekID == ".." {
+		return nil, fmt.Errorf("invalid KEK ID %q", kekID)
+	}
+	key, err := ioutil.ReadFile(filepath.Join(f.Dir, kekID))
+	if err != nil {
+		return nil, err
+	}
+	return newAEAD(key)
+}
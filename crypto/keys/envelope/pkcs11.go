@@ -0,0 +1,169 @@
+// +build pkcs11
+
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package envelope
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/miekg/pkcs11"
+)
+
+// PKCS11KEKProvider is a KEKProvider that uses AES key-encryption keys held
+// by a PKCS#11 token. Each KEK is a secret key object on the token, whose
+// CKA_LABEL is the KEK's ID. The KEKs never leave the token.
+type PKCS11KEKProvider struct {
+	ctx *pkcs11.Ctx
+
+	// mu serializes use of session, as PKCS#11 sessions are single-threaded.
+	mu      sync.Mutex
+	session pkcs11.SessionHandle
+}
+
+// NewPKCS11KEKProvider returns a PKCS11KEKProvider that uses the keys on the
+// token labelled tokenLabel, accessed through the PKCS#11 module at
+// modulePath.
+func NewPKCS11KEKProvider(modulePath, tokenLabel, pin string) (*PKCS11KEKProvider, error) {
+	if modulePath == "" {
+		return nil, errors.New("pkcs11: No module path")
+	}
+	ctx := pkcs11.New(modulePath)
+	if ctx == nil {
+		return nil, fmt.Errorf("pkcs11: error loading module %q", modulePath)
+	}
+	if err := ctx.Initialize(); err != nil {
+		return nil, fmt.Errorf("pkcs11: error initializing module: %v", err)
+	}
+
+	slot, err := findSlot(ctx, tokenLabel)
+	if err != nil {
+		return nil, err
+	}
+	session, err := ctx.OpenSession(slot, pkcs11.CKF_SERIAL_SESSION)
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11: error opening session: %v", err)
+	}
+	if err := ctx.Login(session, pkcs11.CKU_USER, pin); err != nil && err != pkcs11.Error(pkcs11.CKR_USER_ALREADY_LOGGED_IN) {
+		ctx.CloseSession(session)
+		return nil, fmt.Errorf("pkcs11: error logging in: %v", err)
+	}
+	return &PKCS11KEKProvider{ctx: ctx, session: session}, nil
+}
+
+// WrapKey encrypts dataKey with AES-CBC (with PKCS#7 padding), using the
+// token key labelled kekID. The random IV is prepended to the result.
+func (p *PKCS11KEKProvider) WrapKey(ctx context.Context, kekID string, dataKey []byte) ([]byte, error) {
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, fmt.Errorf("pkcs11: error generating IV: %v", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	kek, err := p.findKey(kekID)
+	if err != nil {
+		return nil, err
+	}
+	mechanism := []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_CBC_PAD, iv)}
+	if err := p.ctx.EncryptInit(p.session, mechanism, kek); err != nil {
+		return nil, fmt.Errorf("pkcs11: error initializing encryption: %v", err)
+	}
+	ciphertext, err := p.ctx.Encrypt(p.session, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11: error encrypting: %v", err)
+	}
+	return append(iv, ciphertext...), nil
+}
+
+// UnwrapKey decrypts a data key wrapped by WrapKey.
+func (p *PKCS11KEKProvider) UnwrapKey(ctx context.Context, kekID string, wrappedKey []byte) ([]byte, error) {
+	if len(wrappedKey) < aes.BlockSize {
+		return nil, fmt.Errorf("pkcs11: wrapped key is %d bytes, too short to hold an IV", len(wrappedKey))
+	}
+	iv, ciphertext := wrappedKey[:aes.BlockSize], wrappedKey[aes.BlockSize:]
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	kek, err := p.findKey(kekID)
+	if err != nil {
+		return nil, err
+	}
+	mechanism := []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_CBC_PAD, iv)}
+	if err := p.ctx.DecryptInit(p.session, mechanism, kek); err != nil {
+		return nil, fmt.Errorf("pkcs11: error initializing decryption: %v", err)
+	}
+	dataKey, err := p.ctx.Decrypt(p.session, ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11: error decrypting: %v", err)
+	}
+	return dataKey, nil
+}
+
+// Close logs out of the token and releases the PKCS#11 module.
+func (p *PKCS11KEKProvider) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ctx.Logout(p.session)
+	p.ctx.CloseSession(p.session)
+	err := p.ctx.Finalize()
+	p.ctx.Destroy()
+	return err
+}
+
+// findKey returns the secret key labelled kekID. Must be called with mu held.
+func (p *PKCS11KEKProvider) findKey(kekID string) (pkcs11.ObjectHandle, error) {
+	template := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_SECRET_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, kekID),
+	}
+	if err := p.ctx.FindObjectsInit(p.session, template); err != nil {
+		return 0, fmt.Errorf("pkcs11: error searching for key %q: %v", kekID, err)
+	}
+	objects, _, err := p.ctx.FindObjects(p.session, 1)
+	if finalErr := p.ctx.FindObjectsFinal(p.session); err == nil {
+		err = finalErr
+	}
+	if err != nil {
+		return 0, fmt.Errorf("pkcs11: error searching for key %q: %v", kekID, err)
+	}
+	if len(objects) == 0 {
+		return 0, fmt.Errorf("pkcs11: no secret key labelled %q", kekID)
+	}
+	return objects[0], nil
+}
+
+// findSlot returns the slot holding the token labelled tokenLabel.
+func findSlot(ctx *pkcs11.Ctx, tokenLabel string) (uint, error) {
+	slots, err := ctx.GetSlotList(true /* tokenPresent */)
+	if err != nil {
+		return 0, fmt.Errorf("pkcs11: error listing slots: %v", err)
+	}
+	for _, slot := range slots {
+		info, err := ctx.GetTokenInfo(slot)
+		if err != nil {
+			return 0, fmt.Errorf("pkcs11: error reading token info: %v", err)
+		}
+		if info.Label == tokenLabel {
+			return slot, nil
+		}
+	}
+	return 0, fmt.Errorf("pkcs11: no token labelled %q", tokenLabel)
+}
@@ -0,0 +1,39 @@
+// +build pkcs11
+
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proto
+
+import (
+	"flag"
+
+	"github.com/google/trillian/crypto/keys/envelope"
+)
+
+var (
+	kekModulePath = flag.String("envelope_pkcs11_module_path", "", "Path to the PKCS#11 module holding the key-encryption keys used for envelope encryption; takes precedence over --envelope_kek_dir")
+	kekTokenLabel = flag.String("envelope_pkcs11_token_label", "", "Label of the PKCS#11 token holding the key-encryption keys")
+	kekPIN        = flag.String("envelope_pkcs11_pin", "", "PIN of the PKCS#11 token holding the key-encryption keys")
+)
+
+func init() {
+	fileProvider := newProvider
+	newProvider = func() (envelope.KEKProvider, error) {
+		if *kekModulePath == "" {
+			return fileProvider()
+		}
+		return envelope.NewPKCS11KEKProvider(*kekModulePath, *kekTokenLabel, *kekPIN)
+	}
+}
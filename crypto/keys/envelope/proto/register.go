@@ -0,0 +1,94 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package proto registers an envelope encryption keys.ProtoHandler using
+// keys.RegisterHandler. This handler will decrypt a keyspb.EncryptedPrivateKey
+// protobuf message, using the KEK provider configured by flags, to get a
+// crypto.Signer.
+package proto
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"flag"
+	"fmt"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian/crypto/keys"
+	"github.com/google/trillian/crypto/keys/der"
+	"github.com/google/trillian/crypto/keys/envelope"
+	"github.com/google/trillian/crypto/keyspb"
+)
+
+var (
+	kekDir = flag.String("envelope_kek_dir", "", "Directory holding the key-encryption keys used for envelope encryption of private keys, one file per KEK named by its ID")
+	// KEKID is the ID of the key-encryption key used to wrap new private keys.
+	KEKID = flag.String("envelope_kek_id", "", "If set, private keys generated by Trillian are envelope-encrypted with the key-encryption key with this ID")
+
+	providerMu sync.Mutex
+	provider   envelope.KEKProvider
+
+	// newProvider creates the KEKProvider configured by flags.
+	newProvider = func() (envelope.KEKProvider, error) {
+		if *kekDir == "" {
+			return nil, errors.New("envelope: no KEK provider configured, see --envelope_kek_dir")
+		}
+		return envelope.NewFileKEKProvider(*kekDir), nil
+	}
+)
+
+func init() {
+	keys.RegisterHandler(&keyspb.EncryptedPrivateKey{}, func(ctx context.Context, pb proto.Message) (crypto.Signer, error) {
+		if pb, ok := pb.(*keyspb.EncryptedPrivateKey); ok {
+			p, err := KEKProviderFromFlags()
+			if err != nil {
+				return nil, err
+			}
+			return envelope.FromProto(ctx, p, pb)
+		}
+		return nil, fmt.Errorf("envelope: got %T, want *keyspb.EncryptedPrivateKey", pb)
+	})
+}
+
+// KEKProviderFromFlags returns the KEKProvider configured by flags. The
+// provider is created on first use, so flags must have been parsed by then.
+func KEKProviderFromFlags() (envelope.KEKProvider, error) {
+	providerMu.Lock()
+	defer providerMu.Unlock()
+	if provider == nil {
+		p, err := newProvider()
+		if err != nil {
+			return nil, err
+		}
+		provider = p
+	}
+	return provider, nil
+}
+
+// NewProtoFromSpec creates a new private key based on a key specification.
+// If --envelope_kek_id is set, it returns the key as a
+// keyspb.EncryptedPrivateKey wrapped by that KEK, otherwise as a plain
+// keyspb.PrivateKey. It can be used as an extension.Registry's NewKeyProto.
+func NewProtoFromSpec(ctx context.Context, spec *keyspb.Specification) (proto.Message, error) {
+	if *KEKID == "" {
+		return der.NewProtoFromSpec(spec)
+	}
+	p, err := KEKProviderFromFlags()
+	if err != nil {
+		return nil, err
+	}
+	return envelope.NewProtoFromSpec(ctx, p, *KEKID, spec)
+}
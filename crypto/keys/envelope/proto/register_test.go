@@ -0,0 +1,85 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package proto
+
+import (
+	"context"
+	"crypto/rand"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian/crypto/keys"
+	"github.com/google/trillian/crypto/keys/testonly"
+	"github.com/google/trillian/crypto/keyspb"
+	"github.com/google/trillian/testonly/flagsaver"
+
+	_ "github.com/google/trillian/crypto/keys/der/proto"
+)
+
+func TestProtoHandler(t *testing.T) {
+	defer flagsaver.Save().MustRestore()
+	dir, err := ioutil.TempDir("", "envelope")
+	if err != nil {
+		t.Fatalf("TempDir(): %v", err)
+	}
+	defer os.RemoveAll(dir)
+	kek := make([]byte, 32)
+	if _, err := rand.Read(kek); err != nil {
+		t.Fatalf("Read(): %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "kek"), kek, 0600); err != nil {
+		t.Fatalf("WriteFile(): %v", err)
+	}
+	if err := flag.Set("envelope_kek_dir", dir); err != nil {
+		t.Fatalf("flag.Set(): %v", err)
+	}
+
+	ctx := context.Background()
+	spec := &keyspb.Specification{Params: &keyspb.Specification_EcdsaParams{}}
+	for _, test := range []struct {
+		desc     string
+		kekID    string
+		wantType proto.Message
+	}{
+		{desc: "noKEK", wantType: &keyspb.PrivateKey{}},
+		{desc: "KEK", kekID: "kek", wantType: &keyspb.EncryptedPrivateKey{}},
+	} {
+		if err := flag.Set("envelope_kek_id", test.kekID); err != nil {
+			t.Fatalf("flag.Set(): %v", err)
+		}
+		keyProto, err := NewProtoFromSpec(ctx, spec)
+		if err != nil {
+			t.Errorf("%v: NewProtoFromSpec(): %v", test.desc, err)
+			continue
+		}
+		if got, want := proto.MessageName(keyProto), proto.MessageName(test.wantType); got != want {
+			t.Errorf("%v: NewProtoFromSpec() returned a %v, want %v", test.desc, got, want)
+		}
+
+		signer, err := keys.NewSigner(ctx, keyProto)
+		if err != nil {
+			t.Errorf("%v: NewSigner(): %v", test.desc, err)
+			continue
+		}
+		// Check that the returned signer can produce signatures successfully.
+		if err := testonly.SignAndVerify(signer, signer.Public()); err != nil {
+			t.Errorf("%v: SignAndVerify() = %q, want nil", test.desc, err)
+		}
+	}
+}
@@ -360,6 +360,79 @@ func (m *PrivateKey) GetDer() []byte {
 	return nil
 }
 
+// EncryptedPrivateKey is a private key protected by envelope encryption.
+// The key's DER is encrypted with a random data key, which in turn is
+// encrypted ("wrapped") with a key-encryption key (KEK) that never leaves its
+// KEK provider (e.g. a local key file or a PKCS#11 token).
+type EncryptedPrivateKey struct {
+	// Identifies the key-encryption key that wrapped data_key, in a form
+	// understood by the KEK provider (e.g. a file name or PKCS#11 key label).
+	KekId string `protobuf:"bytes,1,opt,name=kek_id,json=kekId,proto3" json:"kek_id,omitempty"`
+	// The data key, wrapped by the key-encryption key.
+	WrappedDataKey []byte `protobuf:"bytes,2,opt,name=wrapped_data_key,json=wrappedDataKey,proto3" json:"wrapped_data_key,omitempty"`
+	// The nonce used to encrypt the private key with the data key.
+	Nonce []byte `protobuf:"bytes,3,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	// The private key in DER-encoded form (see PrivateKey.der), encrypted with
+	// the data key using AES-256-GCM.
+	EncryptedDer         []byte   `protobuf:"bytes,4,opt,name=encrypted_der,json=encryptedDer,proto3" json:"encrypted_der,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EncryptedPrivateKey) Reset()         { *m = EncryptedPrivateKey{} }
+func (m *EncryptedPrivateKey) String() string { return proto.CompactTextString(m) }
+func (*EncryptedPrivateKey) ProtoMessage()    {}
+func (*EncryptedPrivateKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_c8ca2ab097770992, []int{3}
+}
+
+func (m *EncryptedPrivateKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EncryptedPrivateKey.Unmarshal(m, b)
+}
+func (m *EncryptedPrivateKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EncryptedPrivateKey.Marshal(b, m, deterministic)
+}
+func (m *EncryptedPrivateKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EncryptedPrivateKey.Merge(m, src)
+}
+func (m *EncryptedPrivateKey) XXX_Size() int {
+	return xxx_messageInfo_EncryptedPrivateKey.Size(m)
+}
+func (m *EncryptedPrivateKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_EncryptedPrivateKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EncryptedPrivateKey proto.InternalMessageInfo
+
+func (m *EncryptedPrivateKey) GetKekId() string {
+	if m != nil {
+		return m.KekId
+	}
+	return ""
+}
+
+func (m *EncryptedPrivateKey) GetWrappedDataKey() []byte {
+	if m != nil {
+		return m.WrappedDataKey
+	}
+	return nil
+}
+
+func (m *EncryptedPrivateKey) GetNonce() []byte {
+	if m != nil {
+		return m.Nonce
+	}
+	return nil
+}
+
+func (m *EncryptedPrivateKey) GetEncryptedDer() []byte {
+	if m != nil {
+		return m.EncryptedDer
+	}
+	return nil
+}
+
 // PublicKey is a public key, used for verifying signatures.
 type PublicKey struct {
 	// The key in DER-encoded PKIX form.
@@ -373,7 +446,7 @@ func (m *PublicKey) Reset()         { *m = PublicKey{} }
 func (m *PublicKey) String() string { return proto.CompactTextString(m) }
 func (*PublicKey) ProtoMessage()    {}
 func (*PublicKey) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c8ca2ab097770992, []int{3}
+	return fileDescriptor_c8ca2ab097770992, []int{4}
 }
 
 func (m *PublicKey) XXX_Unmarshal(b []byte) error {
@@ -418,7 +491,7 @@ func (m *PKCS11Config) Reset()         { *m = PKCS11Config{} }
 func (m *PKCS11Config) String() string { return proto.CompactTextString(m) }
 func (*PKCS11Config) ProtoMessage()    {}
 func (*PKCS11Config) Descriptor() ([]byte, []int) {
-	return fileDescriptor_c8ca2ab097770992, []int{4}
+	return fileDescriptor_c8ca2ab097770992, []int{5}
 }
 
 func (m *PKCS11Config) XXX_Unmarshal(b []byte) error {
@@ -468,6 +541,7 @@ func init() {
 	proto.RegisterType((*Specification_Ed25519)(nil), "keyspb.Specification.Ed25519")
 	proto.RegisterType((*PEMKeyFile)(nil), "keyspb.PEMKeyFile")
 	proto.RegisterType((*PrivateKey)(nil), "keyspb.PrivateKey")
+	proto.RegisterType((*EncryptedPrivateKey)(nil), "keyspb.EncryptedPrivateKey")
 	proto.RegisterType((*PublicKey)(nil), "keyspb.PublicKey")
 	proto.RegisterType((*PKCS11Config)(nil), "keyspb.PKCS11Config")
 }
@@ -475,32 +549,37 @@ func init() {
 func init() { proto.RegisterFile("crypto/keyspb/keyspb.proto", fileDescriptor_c8ca2ab097770992) }
 
 var fileDescriptor_c8ca2ab097770992 = []byte{
-	// 432 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x92, 0xcf, 0x6f, 0xd3, 0x30,
-	0x14, 0xc7, 0xdb, 0x65, 0x2d, 0xcd, 0x6b, 0x3b, 0x05, 0x9f, 0x58, 0x50, 0xf9, 0x91, 0xd3, 0xc4,
-	0x21, 0x55, 0x33, 0x0a, 0x03, 0x71, 0xa0, 0xcb, 0x5a, 0x4d, 0xea, 0x90, 0x22, 0x97, 0x71, 0xe0,
-	0x52, 0x9c, 0xd4, 0xcb, 0xac, 0x66, 0xb1, 0xe5, 0xb8, 0x43, 0xe1, 0xc6, 0x7f, 0x8e, 0xf2, 0x92,
-	0x0d, 0x4d, 0x1a, 0x9c, 0xf2, 0x7d, 0xce, 0xf7, 0xe3, 0xf7, 0x7d, 0xb6, 0xc1, 0x4d, 0x74, 0xa9,
-	0x8c, 0x1c, 0x6f, 0x79, 0x59, 0xa8, 0xb8, 0xf9, 0xf8, 0x4a, 0x4b, 0x23, 0x49, 0xb7, 0xae, 0xbc,
-	0xdf, 0x16, 0x0c, 0x57, 0x8a, 0x27, 0xe2, 0x4a, 0x24, 0xcc, 0x08, 0x99, 0x93, 0xcf, 0x30, 0xe0,
-	0xc9, 0xa6, 0x60, 0x6b, 0xc5, 0x34, 0xbb, 0x29, 0x9e, 0xb5, 0x5f, 0xb5, 0x8f, 0xfa, 0xc1, 0x73,
-	0xbf, 0xc1, 0x1f, 0x98, 0xfd, 0x79, 0x78, 0xb6, 0x9a, 0x9d, 0xb7, 0x68, 0x1f, 0x91, 0x08, 0x09,
-	0xf2, 0x11, 0x40, 0xff, 0xe5, 0xf7, 0x90, 0x3f, 0x7c, 0x9c, 0xa7, 0x48, 0xdb, 0xfa, 0x9e, 0x5d,
-	0xc0, 0x01, 0xdf, 0x04, 0xd3, 0xe9, 0xe4, 0xc3, 0x1d, 0x6f, 0x21, 0x3f, 0xfa, 0x47, 0xff, 0xda,
-	0x7b, 0xde, 0xa2, 0xc3, 0x06, 0xab, 0xf7, 0x71, 0x7f, 0x41, 0x07, 0xb3, 0x91, 0xf7, 0xd0, 0x49,
-	0x76, 0xfa, 0x96, 0xe3, 0x1c, 0x07, 0xc1, 0xeb, 0xff, 0xcc, 0xe1, 0x87, 0x95, 0x91, 0xd6, 0x7e,
-	0xef, 0x04, 0x3a, 0x58, 0x93, 0xa7, 0x30, 0x3c, 0x9b, 0x2f, 0x66, 0x97, 0x17, 0x5f, 0xd7, 0xe1,
-	0x25, 0xfd, 0x36, 0x77, 0x5a, 0xa4, 0x07, 0xfb, 0x51, 0x30, 0x7d, 0xe7, 0xb4, 0x51, 0x1d, 0x9f,
-	0xbc, 0x75, 0xf6, 0x50, 0x4d, 0x83, 0x89, 0x63, 0xb9, 0x87, 0x60, 0xd1, 0xd5, 0x8c, 0x10, 0xd8,
-	0x8f, 0x85, 0xa9, 0x0f, 0xb0, 0x43, 0x51, 0xbb, 0x36, 0x3c, 0x69, 0x22, 0x9f, 0xf6, 0xa0, 0x5b,
-	0x4f, 0xe8, 0x7d, 0x02, 0x88, 0xe6, 0x5f, 0x96, 0xbc, 0x5c, 0x88, 0x8c, 0x57, 0x98, 0x62, 0xe6,
-	0x1a, 0x31, 0x9b, 0xa2, 0x26, 0x2e, 0xf4, 0x14, 0x2b, 0x8a, 0x9f, 0x52, 0x6f, 0xf0, 0x3c, 0x6d,
-	0x7a, 0x5f, 0x7b, 0x2f, 0x00, 0x22, 0x2d, 0x6e, 0x99, 0xe1, 0x4b, 0x5e, 0x12, 0x07, 0xac, 0x0d,
-	0xd7, 0x08, 0x0f, 0x68, 0x25, 0xbd, 0x11, 0xd8, 0xd1, 0x2e, 0xce, 0x44, 0xf2, 0xf8, 0xef, 0x1f,
-	0x30, 0x88, 0x96, 0xe1, 0x6a, 0x32, 0x09, 0x65, 0x7e, 0x25, 0x52, 0xf2, 0x12, 0xfa, 0x46, 0x6e,
-	0x79, 0xbe, 0xce, 0x58, 0xcc, 0xb3, 0x26, 0x05, 0xe0, 0xd2, 0x45, 0xb5, 0x52, 0x6d, 0xa1, 0x44,
-	0xde, 0xc4, 0xa8, 0x24, 0x19, 0x01, 0x28, 0xec, 0xb0, 0xde, 0xf2, 0x12, 0xef, 0xcb, 0xa6, 0xb6,
-	0xba, 0xeb, 0x79, 0xfa, 0xe6, 0xfb, 0x51, 0x2a, 0xcc, 0xf5, 0x2e, 0xf6, 0x13, 0x79, 0x33, 0x4e,
-	0xa5, 0x4c, 0x33, 0x3e, 0x36, 0x5a, 0x64, 0x99, 0x60, 0xf9, 0xf8, 0xc1, 0x1b, 0x8d, 0xbb, 0xf8,
-	0x3a, 0x8f, 0xff, 0x04, 0x00, 0x00, 0xff, 0xff, 0x3a, 0x60, 0x6c, 0x48, 0xbb, 0x02, 0x00, 0x00,
+	// 508 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x93, 0x4d, 0x6f, 0xd3, 0x40,
+	0x10, 0x86, 0x9b, 0xba, 0x0e, 0xf1, 0xc4, 0x89, 0xcc, 0x02, 0x12, 0x0d, 0x0a, 0x1f, 0xe6, 0x12,
+	0x71, 0x48, 0x94, 0x94, 0x40, 0x41, 0x1c, 0x48, 0xf3, 0xa1, 0xa2, 0x14, 0xc9, 0xda, 0x50, 0x0e,
+	0x5c, 0xcc, 0xda, 0xde, 0xa6, 0x2b, 0xbb, 0xf6, 0x6a, 0xb3, 0x69, 0x15, 0x6e, 0xfc, 0x04, 0xfe,
+	0x31, 0xf2, 0xd8, 0x09, 0x54, 0x2a, 0x9c, 0xf2, 0xce, 0xec, 0x3c, 0xfb, 0xce, 0x4c, 0xbc, 0xd0,
+	0x0a, 0xd5, 0x46, 0xea, 0xac, 0x17, 0xf3, 0xcd, 0x4a, 0x06, 0xe5, 0x4f, 0x57, 0xaa, 0x4c, 0x67,
+	0xa4, 0x5a, 0x44, 0xee, 0x4f, 0x03, 0x1a, 0x0b, 0xc9, 0x43, 0x71, 0x21, 0x42, 0xa6, 0x45, 0x96,
+	0x92, 0x8f, 0x60, 0xf3, 0x30, 0x5a, 0x31, 0x5f, 0x32, 0xc5, 0xae, 0x56, 0x8f, 0x2b, 0xcf, 0x2b,
+	0x9d, 0xfa, 0xe0, 0x49, 0xb7, 0xc4, 0x6f, 0x15, 0x77, 0xa7, 0xe3, 0xc9, 0x62, 0x74, 0xba, 0x47,
+	0xeb, 0x88, 0x78, 0x48, 0x90, 0xf7, 0x00, 0xea, 0x0f, 0xbf, 0x8f, 0xfc, 0xe1, 0xdd, 0x3c, 0x45,
+	0xda, 0x52, 0x3b, 0x76, 0x06, 0x4d, 0x1e, 0x0d, 0x86, 0xc3, 0xfe, 0xbb, 0x2d, 0x6f, 0x20, 0xdf,
+	0xfe, 0x87, 0x7f, 0x51, 0x7b, 0xba, 0x47, 0x1b, 0x25, 0x56, 0xdc, 0xd3, 0xfa, 0x01, 0x26, 0xf6,
+	0x46, 0xde, 0x82, 0x19, 0xae, 0xd5, 0x35, 0xc7, 0x39, 0x9a, 0x83, 0x17, 0xff, 0x99, 0xa3, 0x3b,
+	0xce, 0x0b, 0x69, 0x51, 0xef, 0x1e, 0x83, 0x89, 0x31, 0xb9, 0x0f, 0x8d, 0xc9, 0x74, 0x36, 0x3a,
+	0x3f, 0xfb, 0xe2, 0x8f, 0xcf, 0xe9, 0xd7, 0xa9, 0xb3, 0x47, 0x6a, 0x70, 0xe0, 0x0d, 0x86, 0x6f,
+	0x9c, 0x0a, 0xaa, 0xa3, 0xe3, 0xd7, 0xce, 0x3e, 0xaa, 0xe1, 0xa0, 0xef, 0x18, 0xad, 0x43, 0x30,
+	0xe8, 0x62, 0x44, 0x08, 0x1c, 0x04, 0x42, 0x17, 0x0b, 0x34, 0x29, 0xea, 0x96, 0x05, 0xf7, 0xca,
+	0x96, 0x4f, 0x6a, 0x50, 0x2d, 0x26, 0x74, 0x3f, 0x00, 0x78, 0xd3, 0xcf, 0x73, 0xbe, 0x99, 0x89,
+	0x84, 0xe7, 0x98, 0x64, 0xfa, 0x12, 0x31, 0x8b, 0xa2, 0x26, 0x2d, 0xa8, 0x49, 0xb6, 0x5a, 0xdd,
+	0x64, 0x2a, 0xc2, 0x7d, 0x5a, 0x74, 0x17, 0xbb, 0x4f, 0x01, 0x3c, 0x25, 0xae, 0x99, 0xe6, 0x73,
+	0xbe, 0x21, 0x0e, 0x18, 0x11, 0x57, 0x08, 0xdb, 0x34, 0x97, 0xee, 0xaf, 0x0a, 0x3c, 0x98, 0xa6,
+	0xf8, 0x29, 0xf0, 0xe8, 0xaf, 0xca, 0x47, 0x50, 0x8d, 0x79, 0xec, 0x8b, 0xa8, 0x74, 0x32, 0x63,
+	0x1e, 0x7f, 0x8a, 0x48, 0x07, 0x9c, 0x1b, 0xc5, 0xa4, 0xe4, 0x91, 0x1f, 0x31, 0xcd, 0xfc, 0x98,
+	0x6f, 0xd0, 0xd2, 0xa6, 0xcd, 0x32, 0x3f, 0x61, 0x9a, 0xe5, 0x17, 0x3c, 0x04, 0x33, 0xcd, 0xd2,
+	0x90, 0xe3, 0x3f, 0x64, 0xd3, 0x22, 0x20, 0x2f, 0xa1, 0xc1, 0xb7, 0x6e, 0x7e, 0xde, 0xca, 0x01,
+	0x9e, 0xda, 0xbb, 0xe4, 0x84, 0x2b, 0xb7, 0x0d, 0x96, 0xb7, 0x0e, 0x12, 0x11, 0xde, 0xdd, 0xf2,
+	0x77, 0xb0, 0xbd, 0xf9, 0x78, 0xd1, 0xef, 0x8f, 0xb3, 0xf4, 0x42, 0x2c, 0xc9, 0x33, 0xa8, 0xeb,
+	0x2c, 0xe6, 0xa9, 0x9f, 0xb0, 0x80, 0x27, 0x65, 0xbf, 0x80, 0xa9, 0xb3, 0x3c, 0x93, 0x5f, 0x21,
+	0x45, 0x5a, 0xae, 0x26, 0x97, 0xa4, 0x0d, 0x20, 0xd1, 0x01, 0x07, 0x30, 0xf0, 0xc0, 0x92, 0x5b,
+	0xcf, 0x93, 0x57, 0xdf, 0x3a, 0x4b, 0xa1, 0x2f, 0xd7, 0x41, 0x37, 0xcc, 0xae, 0x7a, 0xcb, 0x2c,
+	0x5b, 0x26, 0xbc, 0xa7, 0x95, 0x48, 0x12, 0xc1, 0xd2, 0xde, 0xad, 0x77, 0x13, 0x54, 0xf1, 0xc5,
+	0x1c, 0xfd, 0x1e, 0x00, 0x5e, 0x85, 0x01, 0x48, 0x4f, 0x03, 0x00, 0x00,
 }
@@ -18,6 +18,7 @@ package crypto
 import (
 	"crypto"
 	"crypto/rand"
+	"encoding/binary"
 
 	"github.com/golang/glog"
 	"github.com/google/trillian"
@@ -27,6 +28,10 @@ import (
 
 const noHash = crypto.Hash(0)
 
+// cosignaturePrefix starts the data signed by witness cosignatures, so that
+// they can't be confused with signatures over other data.
+const cosignaturePrefix = "Trillian log root cosignature v1\x00"
+
 // Signer is responsible for signing log-related data and producing the appropriate
 // application specific signature objects.
 type Signer struct {
@@ -95,6 +100,26 @@ func (s *Signer) SignLogRoot(r *types.LogRootV1) (*trillian.SignedLogRoot, error
 	}, nil
 }
 
+// CosignLogRoot returns a witness cosignature of r, a log root of the log
+// with ID logID. The signature covers the log ID as well as the log root, as
+// the latter doesn't identify the log it belongs to.
+func (s *Signer) CosignLogRoot(logID int64, r *trillian.SignedLogRoot) ([]byte, error) {
+	signature, err := s.Sign(cosignedData(logID, r.LogRoot))
+	if err != nil {
+		glog.Warningf("%v: signer failed to cosign log root: %v", s.KeyHint, err)
+		return nil, err
+	}
+	return signature, nil
+}
+
+// cosignedData returns the data signed by a cosignature of logRoot.
+func cosignedData(logID int64, logRoot []byte) []byte {
+	data := make([]byte, len(cosignaturePrefix)+8, len(cosignaturePrefix)+8+len(logRoot))
+	copy(data, cosignaturePrefix)
+	binary.BigEndian.PutUint64(data[len(cosignaturePrefix):], uint64(logID))
+	return append(data, logRoot...)
+}
+
 // SignMapRoot hashes and signs the supplied (to-be) SignedMapRoot and returns a signature.
 func (s *Signer) SignMapRoot(r *types.MapRootV1) (*trillian.SignedMapRoot, error) {
 	rootBytes, err := r.MarshalBinary()
@@ -113,6 +113,39 @@ func TestSignLogRoot(t *testing.T) {
 	}
 }
 
+func TestCosignLogRoot(t *testing.T) {
+	key, err := pem.UnmarshalPrivateKey(testonly.DemoPrivateKey, testonly.DemoPrivateKeyPass)
+	if err != nil {
+		t.Fatalf("Failed to open test key, err=%v", err)
+	}
+	signer := NewSigner(0, key, crypto.SHA256)
+
+	slr, err := signer.SignLogRoot(&types.LogRootV1{TimestampNanos: 2267709, RootHash: []byte("Islington"), TreeSize: 2})
+	if err != nil {
+		t.Fatalf("Failed to sign log root: %v", err)
+	}
+	sig, err := signer.CosignLogRoot(123, slr)
+	if err != nil {
+		t.Fatalf("Failed to cosign log root: %v", err)
+	}
+
+	for _, test := range []struct {
+		desc    string
+		logID   int64
+		sig     []byte
+		wantErr bool
+	}{
+		{desc: "valid", logID: 123, sig: sig},
+		{desc: "wrongLog", logID: 124, sig: sig, wantErr: true},
+		{desc: "rootSignature", logID: 123, sig: slr.LogRootSignature, wantErr: true},
+	} {
+		err := VerifyCosignature(key.Public(), crypto.SHA256, test.logID, slr, test.sig)
+		if gotErr := err != nil; gotErr != test.wantErr {
+			t.Errorf("%v: VerifyCosignature() = %v, want err? %v", test.desc, err, test.wantErr)
+		}
+	}
+}
+
 func TestSignMapRoot(t *testing.T) {
 	key, err := pem.UnmarshalPrivateKey(testonly.DemoPrivateKey, testonly.DemoPrivateKeyPass)
 	if err != nil {
@@ -43,6 +43,12 @@ func VerifySignedLogRoot(pub crypto.PublicKey, hash crypto.Hash, r *trillian.Sig
 	return &logRoot, nil
 }
 
+// VerifyCosignature verifies a witness cosignature of r, a log root of the
+// log with ID logID, as produced by Signer.CosignLogRoot.
+func VerifyCosignature(pub crypto.PublicKey, hash crypto.Hash, logID int64, r *trillian.SignedLogRoot, sig []byte) error {
+	return Verify(pub, hash, cosignedData(logID, r.LogRoot), sig)
+}
+
 // VerifySignedMapRoot verifies the signature on the SignedMapRoot.
 // VerifySignedMapRoot returns MapRootV1 to encourage safe API use.
 // It should be the only function available to clients that returns MapRootV1.
@@ -17,6 +17,7 @@
 package extension
 
 import (
+	"github.com/google/trillian/auth"
 	"github.com/google/trillian/crypto/keys"
 	"github.com/google/trillian/monitoring"
 	"github.com/google/trillian/quota"
@@ -38,6 +39,9 @@ type Registry struct {
 	ElectionFactory election2.Factory
 	// QuotaManager provides rate limiting capabilities for Trillian.
 	QuotaManager quota.Manager
+	// Authorizer decides which callers may perform which operations on each
+	// tree. If nil, all requests are allowed.
+	Authorizer auth.Authorizer
 	// MetricFactory provides metrics for monitoring.
 	monitoring.MetricFactory
 	// NewKeyProto creates a new private key based on a key specification.
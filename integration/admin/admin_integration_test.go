@@ -605,7 +605,7 @@ func setupAdminServer(ctx context.Context, t *testing.T) (*testServer, error) {
 	ts.adminStorage = registry.AdminStorage
 
 	ti := interceptor.New(
-		registry.AdminStorage, registry.QuotaManager, false /* quotaDryRun */, registry.MetricFactory)
+		registry.AdminStorage, registry.QuotaManager, registry.Authorizer, false /* quotaDryRun */, registry.MetricFactory)
 	ts.server = grpc.NewServer(
 		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
 			interceptor.ErrorWrapper,
@@ -19,6 +19,7 @@ import (
 	"context"
 	"encoding/hex"
 	"fmt"
+	"io"
 	"testing"
 
 	"github.com/golang/glog"
@@ -55,6 +56,7 @@ var AllTests = TestTable{
 	{"LeafHistory", RunLeafHistory},
 	{"Inclusion", RunInclusion},
 	{"InclusionBatch", RunInclusionBatch},
+	{"MapDiff", RunMapDiff},
 }
 
 var (
@@ -570,6 +572,18 @@ func RunInclusion(ctx context.Context, t *testing.T, tadmin trillian.TrillianAdm
 				if err := verifyGetMapLeavesResponse(mapVerifier, getResp, indexes, 1); err != nil {
 					t.Errorf("verifyGetMapLeavesResponse(): %v", err)
 				}
+
+				compactResp, err := tmap.GetLeaves(ctx, &trillian.GetMapLeavesRequest{
+					MapId:        tree.TreeId,
+					Index:        indexes,
+					CompactProof: true,
+				})
+				if err != nil {
+					t.Fatalf("GetLeaves(compact): %v", err)
+				}
+				if _, err := mapVerifier.VerifyMapLeavesResponse(indexes, 1, compactResp); err != nil {
+					t.Errorf("VerifyMapLeavesResponse(compact): %v", err)
+				}
 			})
 		}
 	}
@@ -606,6 +620,82 @@ func RunGetLeafByRevisionNoProof(ctx context.Context, t *testing.T, tadmin trill
 	}
 }
 
+// RunMapDiff checks that GetMapDiff returns the leaves whose values changed
+// between two revisions, with inclusion proofs against both map roots.
+func RunMapDiff(ctx context.Context, t *testing.T, tadmin trillian.TrillianAdminClient, tmap trillian.TrillianMapClient) {
+	tree, err := newTreeWithHasher(ctx, tadmin, tmap, trillian.HashStrategy_TEST_MAP_HASHER)
+	if err != nil {
+		t.Fatalf("newTreeWithHasher(): %v", err)
+	}
+	mapVerifier, err := client.NewMapVerifierFromTree(tree)
+	if err != nil {
+		t.Fatalf("NewMapVerifierFromTree(): %v", err)
+	}
+
+	for _, batch := range [][]*trillian.MapLeaf{
+		{{Index: index0, LeafValue: []byte("A")}, {Index: index1, LeafValue: []byte("B")}},
+		{{Index: index1, LeafValue: []byte("B")}, {Index: index2, LeafValue: []byte("C")}}, // index1 is unchanged.
+		{{Index: index0, LeafValue: []byte("D")}},
+	} {
+		if _, err := tmap.SetLeaves(ctx, &trillian.SetMapLeavesRequest{MapId: tree.TreeId, Leaves: batch}); err != nil {
+			t.Fatalf("SetLeaves(): %v", err)
+		}
+	}
+
+	stream, err := tmap.GetMapDiff(ctx, &trillian.GetMapDiffRequest{MapId: tree.TreeId, FromRevision: 1, ToRevision: 3})
+	if err != nil {
+		t.Fatalf("GetMapDiff(): %v", err)
+	}
+	var fromRoot, toRoot *trillian.SignedMapRoot
+	var diffs []*trillian.MapLeafDiff
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("GetMapDiff().Recv(): %v", err)
+		}
+		if resp.FromRoot != nil {
+			fromRoot, toRoot = resp.FromRoot, resp.ToRoot
+		}
+		diffs = append(diffs, resp.Diffs...)
+	}
+	if err := verifyGetSignedMapRootResponse(mapVerifier, fromRoot, 1); err != nil {
+		t.Errorf("from root: %v", err)
+	}
+	if err := verifyGetSignedMapRootResponse(mapVerifier, toRoot, 3); err != nil {
+		t.Errorf("to root: %v", err)
+	}
+
+	want := []struct {
+		index    []byte
+		old, new string
+	}{
+		{index: index0, old: "A", new: "D"},
+		{index: index2, new: "C"},
+	}
+	if got := len(diffs); got != len(want) {
+		t.Fatalf("GetMapDiff() returned %d diffs, want %d", got, len(want))
+	}
+	for i, d := range diffs {
+		if !bytes.Equal(d.Index, want[i].index) {
+			t.Errorf("diff %d: Index = %x, want %x", i, d.Index, want[i].index)
+		}
+		if got := string(d.OldLeaf.GetLeaf().GetLeafValue()); got != want[i].old {
+			t.Errorf("diff %d: old LeafValue = %q, want %q", i, got, want[i].old)
+		}
+		if got := string(d.NewLeaf.GetLeaf().GetLeafValue()); got != want[i].new {
+			t.Errorf("diff %d: new LeafValue = %q, want %q", i, got, want[i].new)
+		}
+		if err := mapVerifier.VerifyMapLeafInclusion(fromRoot, d.OldLeaf); err != nil {
+			t.Errorf("diff %d: VerifyMapLeafInclusion(old): %v", i, err)
+		}
+		if err := mapVerifier.VerifyMapLeafInclusion(toRoot, d.NewLeaf); err != nil {
+			t.Errorf("diff %d: VerifyMapLeafInclusion(new): %v", i, err)
+		}
+	}
+}
+
 // RunInclusionBatch performs checks on Trillian Map inclusion proofs, after setting and getting leafs in
 // larger batches, checking also the SignedMapRoot revisions along the way, for a variety of hash strategies.
 func RunInclusionBatch(ctx context.Context, t *testing.T, tadmin trillian.TrillianAdminClient, tmap trillian.TrillianMapClient) {
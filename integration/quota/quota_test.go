@@ -227,7 +227,7 @@ func (s *testServer) serve() {
 func newTestServer(registry extension.Registry) (*testServer, error) {
 	s := &testServer{}
 
-	ti := interceptor.New(registry.AdminStorage, registry.QuotaManager, false /* quotaDryRun */, registry.MetricFactory)
+	ti := interceptor.New(registry.AdminStorage, registry.QuotaManager, registry.Authorizer, false /* quotaDryRun */, registry.MetricFactory)
 	s.server = grpc.NewServer(
 		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
 			interceptor.ErrorWrapper,
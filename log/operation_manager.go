@@ -18,6 +18,7 @@ package log
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"sort"
@@ -38,6 +39,13 @@ var (
 	// DefaultTimeout is the default timeout on a single log operation run.
 	DefaultTimeout = 60 * time.Second
 
+	// ErrNotMaster is returned by RunOperation if this instance is not master
+	// for the log.
+	ErrNotMaster = errors.New("not master for log")
+	// ErrOperationPaused is returned by RunOperation if operations have been
+	// paused for the log.
+	ErrOperationPaused = errors.New("operations paused for log")
+
 	once              sync.Once
 	knownLogs         monitoring.Gauge
 	resignations      monitoring.Counter
@@ -46,6 +54,7 @@ var (
 	failedSigningRuns monitoring.Counter
 	entriesAdded      monitoring.Counter
 	batchesAdded      monitoring.Counter
+	pausedLogs        monitoring.Gauge
 )
 
 func createMetrics(mf monitoring.MetricFactory) {
@@ -68,6 +77,7 @@ func createMetrics(mf monitoring.MetricFactory) {
 	// entriesAdded / batchesAdded is average batch size. These can be used for
 	// tuning sequencing or evaluating performance.
 	batchesAdded = mf.NewCounter("batches_added", "Number of times a non zero number of entries was added", logIDLabel)
+	pausedLogs = mf.NewGauge("paused_logs", "Whether operations are paused for the log on this instance (0/1)", logIDLabel)
 }
 
 // Operation defines a task that operates on a log. Examples are scheduling, signing,
@@ -118,8 +128,17 @@ type OperationManager struct {
 	electionRunner      map[string]*election.Runner
 	pendingResignations chan election.Resignation
 	runnerWG            sync.WaitGroup
-	tracker             *election.MasterTracker
 	lastHeld            []int64
+
+	// passMu serializes passes over the logs with runs of logOperation
+	// requested through RunOperation.
+	passMu sync.Mutex
+
+	// mu guards the fields below, which are also accessed by operator RPCs.
+	mu      sync.Mutex
+	tracker *election.MasterTracker
+	paused  map[int64]bool
+
 	// Cache of logID => name; assumed not to change during runtime
 	logNamesMutex sync.Mutex
 	logNames      map[int64]string
@@ -139,6 +158,7 @@ func NewOperationManager(info OperationInfo, logOperation Operation) *OperationM
 		electionRunner:      make(map[string]*election.Runner),
 		pendingResignations: make(chan election.Resignation, 100),
 		logNames:            make(map[int64]string),
+		paused:              make(map[int64]bool),
 	}
 }
 
@@ -211,6 +231,7 @@ func (o *OperationManager) masterFor(ctx context.Context, allIDs []int64) ([]int
 		s := strconv.FormatInt(id, 10)
 		allStringIDs = append(allStringIDs, s)
 	}
+	o.mu.Lock()
 	if o.tracker == nil {
 		glog.Infof("creating mastership tracker for %v", allIDs)
 		o.tracker = election.NewMasterTracker(allStringIDs, func(id string, v bool) {
@@ -221,6 +242,7 @@ func (o *OperationManager) masterFor(ctx context.Context, allIDs []int64) ([]int
 			isMaster.Set(val, id)
 		})
 	}
+	o.mu.Unlock()
 
 	// Synchronize the set of log IDs with those we are tracking mastership for.
 	for _, logID := range allStringIDs {
@@ -279,6 +301,8 @@ func (o *OperationManager) updateHeldIDs(ctx context.Context, logIDs, activeIDs
 }
 
 func (o *OperationManager) getLogsAndExecutePass(ctx context.Context) error {
+	o.passMu.Lock()
+	defer o.passMu.Unlock()
 	runCtx, cancel := context.WithTimeout(ctx, o.info.Timeout)
 	defer cancel()
 
@@ -294,6 +318,7 @@ func (o *OperationManager) getLogsAndExecutePass(ctx context.Context) error {
 		return fmt.Errorf("failed to determine log IDs we're master for: %v", err)
 	}
 	o.updateHeldIDs(ctx, logIDs, activeIDs)
+	logIDs = o.unpaused(logIDs)
 
 	// TODO(pavelkalinnikov): Run executor once instead of doing it on each pass.
 	// This will be also needed when factoring out per-log operation loop.
@@ -307,6 +332,127 @@ func (o *OperationManager) getLogsAndExecutePass(ctx context.Context) error {
 	return nil
 }
 
+// unpaused returns the IDs among logIDs for which operations are not paused.
+func (o *OperationManager) unpaused(logIDs []int64) []int64 {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if len(o.paused) == 0 {
+		return logIDs
+	}
+	ids := make([]int64, 0, len(logIDs))
+	for _, logID := range logIDs {
+		if !o.paused[logID] {
+			ids = append(ids, logID)
+		}
+	}
+	return ids
+}
+
+// PauseLog stops the manager from running operations on the given log until
+// ResumeLog is called. Pausing only affects this instance, and does not give
+// up mastership for the log. Pauses are kept in memory only, so they are lost
+// when the process restarts, and other instances which become master for the
+// log don't see them.
+func (o *OperationManager) PauseLog(logID int64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.paused[logID] = true
+	pausedLogs.Set(1, strconv.FormatInt(logID, 10))
+}
+
+// ResumeLog undoes the effect of PauseLog for the given log.
+func (o *OperationManager) ResumeLog(logID int64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	delete(o.paused, logID)
+	pausedLogs.Set(0, strconv.FormatInt(logID, 10))
+}
+
+// IsPaused returns whether operations are paused for the given log.
+func (o *OperationManager) IsPaused(logID int64) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.paused[logID]
+}
+
+// Mastership returns the mastership status of this instance for every log
+// that it runs elections for, keyed by log ID. Logs for which operations are
+// paused are always included. Returns an empty map if no elections have been
+// started yet, or if the manager does not use elections.
+func (o *OperationManager) Mastership() (map[int64]bool, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	status := make(map[int64]bool)
+	for logID := range o.paused {
+		status[logID] = false
+	}
+	if o.tracker == nil {
+		return status, nil
+	}
+	held := make(map[string]bool)
+	for _, s := range o.tracker.Held() {
+		held[s] = true
+	}
+	for _, s := range o.tracker.IDs() {
+		logID, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse logID %v as int64", s)
+		}
+		status[logID] = held[s]
+	}
+	return status, nil
+}
+
+// isMasterFor returns whether this instance is currently master for logID.
+func (o *OperationManager) isMasterFor(logID int64) bool {
+	if o.info.Registry.ElectionFactory == nil {
+		return true
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.tracker == nil {
+		return false
+	}
+	id := strconv.FormatInt(logID, 10)
+	for _, s := range o.tracker.Held() {
+		if s == id {
+			return true
+		}
+	}
+	return false
+}
+
+// RunOperation runs the manager's Operation on a single log immediately,
+// rather than waiting for the next pass. It waits for any pass in progress to
+// complete first. Returns the number of items processed, ErrNotMaster if this
+// instance is not master for the log, or ErrOperationPaused if operations are
+// paused for it.
+func (o *OperationManager) RunOperation(ctx context.Context, logID int64) (int, error) {
+	o.passMu.Lock()
+	defer o.passMu.Unlock()
+	if !o.isMasterFor(logID) {
+		return 0, ErrNotMaster
+	}
+	if o.IsPaused(logID) {
+		return 0, ErrOperationPaused
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, o.info.Timeout)
+	defer cancel()
+	label := strconv.FormatInt(logID, 10)
+	count, err := o.logOperation.ExecutePass(runCtx, logID, &o.info)
+	if err != nil {
+		failedSigningRuns.Inc(label)
+		return 0, err
+	}
+	signingRuns.Inc(label)
+	if count > 0 {
+		entriesAdded.Add(float64(count), label)
+		batchesAdded.Inc(label)
+	}
+	return count, nil
+}
+
 // OperationSingle performs a single pass of the manager.
 func (o *OperationManager) OperationSingle(ctx context.Context) {
 	if err := o.getLogsAndExecutePass(ctx); err != nil {
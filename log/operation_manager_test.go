@@ -195,6 +195,120 @@ func TestOperationManagerPassesIDs(t *testing.T) {
 	lom.OperationSingle(ctx)
 }
 
+func TestOperationManagerPausedLog(t *testing.T) {
+	ctx := context.Background()
+	logID1 := int64(451)
+	logID2 := int64(145)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeStorage, mockAdmin := setupLogIDs(ctrl, map[int64]string{logID1: "LogID1", logID2: "LogID2"})
+	registry := extension.Registry{
+		LogStorage:   fakeStorage,
+		AdminStorage: mockAdmin,
+	}
+
+	mockLogOp := NewMockOperation(ctrl)
+	infoMatcher := logOpInfoMatcher{50}
+	mockLogOp.EXPECT().ExecutePass(gomock.Any(), logID1, infoMatcher).Return(1, nil)
+	mockLogOp.EXPECT().ExecutePass(gomock.Any(), logID2, infoMatcher).Return(0, nil)
+
+	info := defaultOperationInfo(registry)
+	lom := NewOperationManager(info, mockLogOp)
+
+	// Only logID1 should be processed while logID2 is paused.
+	lom.PauseLog(logID2)
+	if !lom.IsPaused(logID2) {
+		t.Errorf("IsPaused(%d)=false after PauseLog, want true", logID2)
+	}
+	lom.OperationSingle(ctx)
+
+	// Both logs should be processed once logID2 is resumed.
+	lom.ResumeLog(logID2)
+	if lom.IsPaused(logID2) {
+		t.Errorf("IsPaused(%d)=true after ResumeLog, want false", logID2)
+	}
+	mockLogOp.EXPECT().ExecutePass(gomock.Any(), logID1, infoMatcher).Return(0, nil)
+	lom.OperationSingle(ctx)
+}
+
+func TestOperationManagerRunOperation(t *testing.T) {
+	ctx := context.Background()
+	logID := int64(451)
+
+	for _, test := range []struct {
+		desc    string
+		factory election2.Factory
+		paused  bool
+		passErr error
+		want    int
+		wantErr error
+	}{
+		{desc: "no-factory", want: 3},
+		{desc: "master", factory: election2.NoopFactory{}, want: 3},
+		{desc: "not-master", factory: masterForEvenFactory{}, wantErr: ErrNotMaster},
+		{desc: "paused", paused: true, wantErr: ErrOperationPaused},
+		{desc: "pass-error", passErr: errors.New("pass failed"), wantErr: errors.New("pass failed")},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			testCtx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockLogOp := NewMockOperation(ctrl)
+			if test.wantErr == nil || test.passErr != nil {
+				mockLogOp.EXPECT().ExecutePass(gomock.Any(), logID, logOpInfoMatcher{50}).Return(test.want, test.passErr)
+			}
+			registry := extension.Registry{ElectionFactory: test.factory}
+			lom := NewOperationManager(defaultOperationInfo(registry), mockLogOp)
+			if test.factory != nil {
+				// Start the election for the log, and give it a chance to report.
+				lom.masterFor(testCtx, []int64{logID})
+				time.Sleep(100 * time.Millisecond)
+			}
+			if test.paused {
+				lom.PauseLog(logID)
+			}
+
+			got, err := lom.RunOperation(testCtx, logID)
+			if !reflect.DeepEqual(err, test.wantErr) {
+				t.Fatalf("RunOperation()=_,%v; want _,%v", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("RunOperation()=%d,_; want %d,_", got, test.want)
+			}
+		})
+	}
+}
+
+func TestOperationManagerMastership(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	registry := extension.Registry{ElectionFactory: masterForEvenFactory{}}
+	lom := NewOperationManager(defaultOperationInfo(registry), nil)
+
+	got, err := lom.Mastership()
+	if err != nil {
+		t.Fatalf("Mastership()=_,%v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("Mastership()=%v before any elections; want empty", got)
+	}
+
+	lom.masterFor(ctx, []int64{1, 2, 3})
+	time.Sleep(100 * time.Millisecond)
+	lom.PauseLog(7)
+	got, err = lom.Mastership()
+	if err != nil {
+		t.Fatalf("Mastership()=_,%v", err)
+	}
+	want := map[int64]bool{1: false, 2: true, 3: false, 7: false}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Mastership()=%v; want %v", got, want)
+	}
+}
+
 func TestOperationManagerExecutePassError(t *testing.T) {
 	ctx := context.Background()
 	logID1 := int64(451)
@@ -0,0 +1,185 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/trillian"
+	"github.com/google/trillian/extension"
+	"github.com/google/trillian/monitoring"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/util/clock"
+)
+
+// DefaultQueueSweepBatchSize is the default maximum number of leaves expired
+// in a single storage transaction.
+const DefaultQueueSweepBatchSize = 1000
+
+var (
+	sweeperOnce    sync.Once
+	queueDepth     monitoring.Gauge
+	expiredLeaves  monitoring.Counter
+	failedSweeps   monitoring.Counter
+	sweepsComplete monitoring.Counter
+)
+
+func createSweeperMetrics(mf monitoring.MetricFactory) {
+	if mf == nil {
+		mf = monitoring.InertMetricFactory{}
+	}
+	queueDepth = mf.NewGauge("queue_depth", "Number of unsequenced leaves queued for the log", logIDLabel)
+	expiredLeaves = mf.NewCounter("queue_expired_leaves", "Number of queued leaves expired after exceeding the log's queue TTL", logIDLabel)
+	failedSweeps = mf.NewCounter("queue_failed_sweeps", "Number of times expiring queued leaves for the log has failed", logIDLabel)
+	sweepsComplete = mf.NewCounter("queue_sweeps", "Number of completed queue sweeps")
+}
+
+// QueueSweeper enforces Tree.QueueTtl by periodically removing leaves that
+// have stayed in the unsequenced queue of a LOG tree for longer than the TTL,
+// e.g. because the signer is down or the tree is DRAINING/FROZEN. It also
+// exports the queue depth of each log as a metric.
+type QueueSweeper struct {
+	registry   extension.Registry
+	interval   time.Duration
+	batchSize  int
+	timeSource clock.TimeSource
+}
+
+// NewQueueSweeper returns a QueueSweeper that sweeps the queues of all logs
+// in registry every interval, expiring at most batchSize leaves per
+// transaction.
+func NewQueueSweeper(registry extension.Registry, interval time.Duration, batchSize int, timeSource clock.TimeSource) *QueueSweeper {
+	sweeperOnce.Do(func() {
+		createSweeperMetrics(registry.MetricFactory)
+	})
+	if batchSize <= 0 {
+		batchSize = DefaultQueueSweepBatchSize
+	}
+	return &QueueSweeper{
+		registry:   registry,
+		interval:   interval,
+		batchSize:  batchSize,
+		timeSource: timeSource,
+	}
+}
+
+// Run sweeps the queues every interval until ctx is cancelled.
+func (s *QueueSweeper) Run(ctx context.Context) {
+	for {
+		count, err := s.RunOnce(ctx)
+		if err != nil {
+			glog.Errorf("QueueSweeper.Run: %v", err)
+		}
+		if count > 0 {
+			glog.Infof("QueueSweeper.Run: expired %d queued leaves", count)
+		}
+		if err := clock.SleepSource(ctx, s.interval, s.timeSource); err != nil {
+			return
+		}
+	}
+}
+
+// RunOnce performs a single sweep over all LOG trees. Returns the number of
+// leaves expired. Failures to sweep individual trees are logged and counted,
+// but don't stop the sweep.
+func (s *QueueSweeper) RunOnce(ctx context.Context) (int, error) {
+	trees, err := storage.ListTrees(ctx, s.registry.AdminStorage, false /* includeDeleted */)
+	if err != nil {
+		return 0, err
+	}
+	depths, err := s.queueDepths(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := s.timeSource.Now()
+	total := 0
+	for _, tree := range trees {
+		if tree.TreeType != trillian.TreeType_LOG {
+			continue
+		}
+		label := strconv.FormatInt(tree.TreeId, 10)
+		queueDepth.Set(float64(depths[tree.TreeId]), label)
+
+		if tree.QueueTtl == nil {
+			continue
+		}
+		ttl, err := ptypes.Duration(tree.QueueTtl)
+		if err != nil {
+			glog.Warningf("%v: failed to parse QueueTtl: %v", tree.TreeId, err)
+			failedSweeps.Inc(label)
+			continue
+		}
+		if ttl <= 0 {
+			continue
+		}
+		count, err := s.expire(ctx, tree, now.Add(-ttl))
+		expiredLeaves.Add(float64(count), label)
+		total += count
+		if err != nil {
+			glog.Warningf("%v: failed to expire queued leaves: %v", tree.TreeId, err)
+			failedSweeps.Inc(label)
+			continue
+		}
+		queueDepth.Set(float64(depths[tree.TreeId]-int64(count)), label)
+	}
+	sweepsComplete.Inc()
+	return total, nil
+}
+
+// expire removes the leaves queued before cutoff from the tree's queue, in
+// batches of at most batchSize leaves. Returns the number of leaves removed.
+func (s *QueueSweeper) expire(ctx context.Context, tree *trillian.Tree, cutoff time.Time) (int, error) {
+	total := 0
+	for {
+		var count int
+		err := s.registry.LogStorage.ReadWriteTransaction(ctx, tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+			var err error
+			count, err = tx.ExpireQueuedLeaves(ctx, s.batchSize, cutoff)
+			return err
+		})
+		if err == storage.ErrTreeNeedsInit {
+			// Nothing can have been queued before the log is initialized.
+			return total, nil
+		} else if err != nil {
+			return total, err
+		}
+		total += count
+		if count < s.batchSize {
+			return total, nil
+		}
+	}
+}
+
+func (s *QueueSweeper) queueDepths(ctx context.Context) (storage.CountByLogID, error) {
+	tx, err := s.registry.LogStorage.Snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Close()
+	depths, err := tx.GetUnsequencedCounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return depths, nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/trillian"
+	"github.com/google/trillian/extension"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/memory"
+	"github.com/google/trillian/util/clock"
+
+	stestonly "github.com/google/trillian/storage/testonly"
+)
+
+func TestQueueSweeperRunOnce(t *testing.T) {
+	ctx := context.Background()
+	ts := memory.NewTreeStorage()
+	registry := extension.Registry{
+		AdminStorage: memory.NewAdminStorage(ts),
+		LogStorage:   memory.NewLogStorage(ts, nil),
+	}
+
+	ttlTree := proto.Clone(stestonly.LogTree).(*trillian.Tree)
+	ttlTree.QueueTtl = ptypes.DurationProto(time.Hour)
+	ttlTree, err := storage.CreateTree(ctx, registry.AdminStorage, ttlTree)
+	if err != nil {
+		t.Fatalf("CreateTree() = (_, %v)", err)
+	}
+	noTTLTree, err := storage.CreateTree(ctx, registry.AdminStorage, stestonly.LogTree)
+	if err != nil {
+		t.Fatalf("CreateTree() = (_, %v)", err)
+	}
+
+	// Queue 3 leaves that are past the TTL, and 2 that aren't, in each tree.
+	for _, tree := range []*trillian.Tree{ttlTree, noTTLTree} {
+		queueSweeperTestLeaves(ctx, t, registry.LogStorage, tree, 0, 3, fakeTime.Add(-2*time.Hour))
+		queueSweeperTestLeaves(ctx, t, registry.LogStorage, tree, 3, 2, fakeTime.Add(-time.Minute))
+	}
+
+	// A batch size smaller than the number of expired leaves needs more than
+	// one transaction.
+	sweeper := NewQueueSweeper(registry, time.Minute, 2, clock.NewFake(fakeTime))
+	count, err := sweeper.RunOnce(ctx)
+	if err != nil {
+		t.Fatalf("RunOnce() = (_, %v)", err)
+	}
+	if got, want := count, 3; got != want {
+		t.Errorf("RunOnce() = %d, want %d", got, want)
+	}
+
+	tx, err := registry.LogStorage.Snapshot(ctx)
+	if err != nil {
+		t.Fatalf("Snapshot() = (_, %v)", err)
+	}
+	defer tx.Close()
+	counts, err := tx.GetUnsequencedCounts(ctx)
+	if err != nil {
+		t.Fatalf("GetUnsequencedCounts() = (_, %v)", err)
+	}
+	if got, want := counts[ttlTree.TreeId], int64(2); got != want {
+		t.Errorf("queue depth of tree with TTL = %d, want %d", got, want)
+	}
+	if got, want := counts[noTTLTree.TreeId], int64(5); got != want {
+		t.Errorf("queue depth of tree without TTL = %d, want %d", got, want)
+	}
+}
+
+func queueSweeperTestLeaves(ctx context.Context, t *testing.T, ls storage.LogStorage, tree *trillian.Tree, start, n int, queueTime time.Time) {
+	t.Helper()
+	leaves := make([]*trillian.LogLeaf, 0, n)
+	for i := start; i < start+n; i++ {
+		value := []byte(fmt.Sprintf("leaf %d", i))
+		hash := sha256.Sum256(value)
+		leaves = append(leaves, &trillian.LogLeaf{LeafValue: value, LeafIdentityHash: hash[:], MerkleLeafHash: hash[:]})
+	}
+	if err := ls.ReadWriteTransaction(ctx, tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		_, err := tx.QueueLeaves(ctx, leaves, queueTime)
+		return err
+	}); err != nil {
+		t.Fatalf("QueueLeaves() = %v", err)
+	}
+}
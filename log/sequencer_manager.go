@@ -34,10 +34,16 @@ import (
 type SequencerManager struct {
 	guardWindow  time.Duration
 	registry     extension.Registry
-	signers      map[int64]*tcrypto.Signer
+	signers      map[int64]cachedSigner
 	signersMutex sync.Mutex
 }
 
+// cachedSigner is a signer for the key version keyID of a tree.
+type cachedSigner struct {
+	keyID  int64
+	signer *tcrypto.Signer
+}
+
 var seqOpts = trees.NewGetOpts(trees.SequenceLog, trillian.TreeType_LOG, trillian.TreeType_PREORDERED_LOG)
 
 // NewSequencerManager creates a new SequencerManager instance based on the provided KeyManager instance
@@ -46,7 +52,7 @@ func NewSequencerManager(registry extension.Registry, gw time.Duration) *Sequenc
 	return &SequencerManager{
 		guardWindow: gw,
 		registry:    registry,
-		signers:     make(map[int64]*tcrypto.Signer),
+		signers:     make(map[int64]cachedSigner),
 	}
 }
 
@@ -86,13 +92,13 @@ func (s *SequencerManager) ExecutePass(ctx context.Context, logID int64, info *O
 }
 
 // getSigner returns a signer for the given tree.
-// Signers are cached, so only one will be created per tree.
+// Signers are cached, so only one will be created per tree and key version.
 func (s *SequencerManager) getSigner(ctx context.Context, tree *trillian.Tree) (*tcrypto.Signer, error) {
 	s.signersMutex.Lock()
 	defer s.signersMutex.Unlock()
 
-	if signer, ok := s.signers[tree.GetTreeId()]; ok {
-		return signer, nil
+	if cached, ok := s.signers[tree.GetTreeId()]; ok && cached.keyID == tree.GetKeyId() {
+		return cached.signer, nil
 	}
 
 	signer, err := trees.Signer(ctx, tree)
@@ -100,6 +106,6 @@ func (s *SequencerManager) getSigner(ctx context.Context, tree *trillian.Tree) (
 		return nil, err
 	}
 
-	s.signers[tree.GetTreeId()] = signer
+	s.signers[tree.GetTreeId()] = cachedSigner{keyID: tree.GetKeyId(), signer: signer}
 	return signer, nil
 }
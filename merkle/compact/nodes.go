@@ -54,3 +54,27 @@ func RangeNodesForPrefix(size uint64) []NodeID {
 	}
 	return ids
 }
+
+// RangeNodes returns the list of node IDs that comprise the [begin, end)
+// compact range, ordered left to right like the hashes of a Range.
+func RangeNodes(begin, end uint64) []NodeID {
+	left, right := decompose(begin, end)
+	ids := make([]NodeID, 0, bits.OnesCount64(left)+bits.OnesCount64(right))
+
+	pos := begin
+	// The left bits of the decomposition correspond to nodes growing in size
+	// from begin towards the point where the paths to begin-1 and end diverge.
+	for ; left != 0; left &= left - 1 {
+		level := uint(bits.TrailingZeros64(left))
+		ids = append(ids, NewNodeID(level, pos>>level))
+		pos += 1 << level
+	}
+	// The right bits correspond to nodes shrinking in size from there to end.
+	for right != 0 {
+		level := uint(bits.Len64(right)) - 1
+		ids = append(ids, NewNodeID(level, pos>>level))
+		pos += 1 << level
+		right ^= 1 << level
+	}
+	return ids
+}
@@ -44,3 +44,30 @@ func TestRangeNodesForPrefix(t *testing.T) {
 		})
 	}
 }
+
+func TestRangeNodes(t *testing.T) {
+	const size = uint64(37)
+	tr, _ := newTree(t, size)
+	factory := &RangeFactory{Hash: hashChildren}
+	for begin := uint64(0); begin <= size; begin++ {
+		for end := begin; end <= size; end++ {
+			t.Run(fmt.Sprintf("range:%d:%d", begin, end), func(t *testing.T) {
+				ids := RangeNodes(begin, end)
+				var hashes [][]byte
+				for _, id := range ids {
+					hashes = append(hashes, tr.nodes[id.Level][id.Index].hash)
+				}
+				r, err := factory.NewRange(begin, end, hashes)
+				if err != nil {
+					t.Fatalf("NewRange: %v", err)
+				}
+				tr.verifyRange(t, r, true)
+				if begin == 0 {
+					if got, want := ids, RangeNodesForPrefix(end); !reflect.DeepEqual(got, want) {
+						t.Errorf("RangeNodes: got %v, want %v", got, want)
+					}
+				}
+			})
+		}
+	}
+}
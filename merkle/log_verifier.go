@@ -19,7 +19,9 @@ import (
 	"errors"
 	"fmt"
 	"math/bits"
+	"sort"
 
+	"github.com/google/trillian/merkle/compact"
 	"github.com/google/trillian/merkle/hashers"
 )
 
@@ -86,6 +88,96 @@ func (v LogVerifier) RootFromInclusionProof(leafIndex, treeSize int64, proof [][
 	return res, nil
 }
 
+// VerifyBatchInclusionProof verifies a batch inclusion proof, as built by
+// MergeInclusionProofs, for the leaves with the given indices and hashes in a
+// tree of size treeSize. leafHashes[i] is the hash of the leaf at
+// leafIndices[i]. The leaves may be given in any order, and a leaf may be
+// repeated as long as each copy has the same hash.
+func (v LogVerifier) VerifyBatchInclusionProof(leafIndices []int64, treeSize int64, proof [][]byte, root []byte, leafHashes [][]byte) error {
+	if got, want := len(leafHashes), len(leafIndices); got != want {
+		return fmt.Errorf("got %d leaf hashes for %d leaves", got, want)
+	}
+	if len(leafIndices) == 0 {
+		return errors.New("no leaves to verify")
+	}
+
+	order := make([]int, len(leafIndices))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool { return leafIndices[order[i]] < leafIndices[order[j]] })
+	indices := make([]int64, 0, len(order))
+	hashes := make([][]byte, 0, len(order))
+	for _, i := range order {
+		if n := len(indices); n > 0 && indices[n-1] == leafIndices[i] {
+			if !bytes.Equal(hashes[n-1], leafHashes[i]) {
+				return fmt.Errorf("conflicting leaf hashes for index %d", leafIndices[i])
+			}
+			continue
+		}
+		indices = append(indices, leafIndices[i])
+		hashes = append(hashes, leafHashes[i])
+	}
+
+	proofs, err := splitBatchInclusionProof(indices, treeSize, proof)
+	if err != nil {
+		return err
+	}
+	for i, index := range indices {
+		if err := v.VerifyInclusionProof(index, treeSize, proofs[i], root, hashes[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VerifyRangeInclusionProof verifies that the leaves with the given hashes are
+// the [begin, begin+len(leafHashes)) range of leaves of a tree of size
+// treeSize with the passed in root. left holds the hashes of the compact range
+// of leaves [0, begin), and right the hashes of the compact range covering the
+// rest of the tree, both ordered left to right. The check merges the three
+// compact ranges and compares the resulting root hash with root.
+func (v LogVerifier) VerifyRangeInclusionProof(begin, treeSize int64, leafHashes, left, right [][]byte, root []byte) error {
+	end := begin + int64(len(leafHashes))
+	switch {
+	case begin < 0:
+		return fmt.Errorf("begin %d < 0", begin)
+	case len(leafHashes) == 0:
+		return errors.New("no leaves to verify")
+	case end > treeSize:
+		return fmt.Errorf("range end is beyond treeSize: %d > %d", end, treeSize)
+	}
+
+	factory := &compact.RangeFactory{Hash: v.hasher.HashChildren}
+	rng, err := factory.NewRange(0, uint64(begin), left)
+	if err != nil {
+		return fmt.Errorf("left: %v", err)
+	}
+	for _, hash := range leafHashes {
+		if err := rng.Append(hash, nil); err != nil {
+			return err
+		}
+	}
+	rightRng, err := factory.NewRange(uint64(end), uint64(treeSize), right)
+	if err != nil {
+		return fmt.Errorf("right: %v", err)
+	}
+	if err := rng.AppendRange(rightRng, nil); err != nil {
+		return err
+	}
+	calcRoot, err := rng.GetRootHash(nil)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(calcRoot, root) {
+		return RootMismatchError{
+			CalculatedRoot: calcRoot,
+			ExpectedRoot:   root,
+		}
+	}
+	return nil
+}
+
 // VerifyConsistencyProof checks that the passed in consistency proof is valid
 // between the passed in tree snapshots. Snapshots are the respective tree
 // sizes. Accepts shapshot2 >= snapshot1 >= 0.
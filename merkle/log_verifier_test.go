@@ -21,6 +21,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/google/trillian/merkle/compact"
 	"github.com/google/trillian/merkle/rfc6962"
 )
 
@@ -338,6 +339,178 @@ func TestVerifyInclusionProofGenerated(t *testing.T) {
 	}
 }
 
+func TestVerifyBatchInclusionProofGenerated(t *testing.T) {
+	var sizes []int64
+	for s := 1; s <= 70; s++ {
+		sizes = append(sizes, int64(s))
+	}
+	sizes = append(sizes, 1024)
+
+	tree, v := createTree(0)
+	for _, size := range sizes {
+		growTree(tree, size)
+		root := tree.CurrentRoot().Hash()
+		batches := map[string][]int64{
+			"first": {0},
+			"ends":  {0, size - 1},
+			"pair":  {size / 2, size/2 + 1},
+		}
+		for i := int64(0); i < size; i++ {
+			batches["all"] = append(batches["all"], i)
+			if i%3 == 1 {
+				batches["every third"] = append(batches["every third"], i)
+			}
+		}
+		for desc, indices := range batches {
+			if indices[len(indices)-1] >= size || (len(indices) > 1 && indices[0] == indices[1]) {
+				continue
+			}
+			t.Run(fmt.Sprintf("size:%d:%s", size, desc), func(t *testing.T) {
+				var proofs [][][]byte
+				var hashes [][]byte
+				total := 0
+				for _, i := range indices {
+					leaf, proof := getLeafAndProof(tree, i)
+					hashes = append(hashes, leaf)
+					proofs = append(proofs, proof)
+					total += len(proof)
+				}
+				batch, err := MergeInclusionProofs(indices, size, proofs)
+				if err != nil {
+					t.Fatalf("MergeInclusionProofs(): %v", err)
+				}
+				if len(indices) > 2 && len(batch) >= total {
+					t.Errorf("batch proof has %d hashes, want fewer than %d", len(batch), total)
+				}
+				if err := v.VerifyBatchInclusionProof(indices, size, batch, root, hashes); err != nil {
+					t.Fatalf("VerifyBatchInclusionProof(): %v", err)
+				}
+
+				// The leaves may be given in any order, and repeated.
+				n := len(indices)
+				reversed := make([]int64, 0, n+1)
+				reversedHashes := make([][]byte, 0, n+1)
+				for i := n - 1; i >= 0; i-- {
+					reversed = append(reversed, indices[i])
+					reversedHashes = append(reversedHashes, hashes[i])
+				}
+				reversed = append(reversed, indices[0])
+				reversedHashes = append(reversedHashes, hashes[0])
+				if err := v.VerifyBatchInclusionProof(reversed, size, batch, root, reversedHashes); err != nil {
+					t.Errorf("VerifyBatchInclusionProof(reversed): %v", err)
+				}
+
+				if len(batch) > 0 {
+					if err := v.VerifyBatchInclusionProof(indices, size, batch[1:], root, hashes); err == nil {
+						t.Error("VerifyBatchInclusionProof() with a short proof returned nil err")
+					}
+					corrupted := append([][]byte{}, batch...)
+					corrupted[len(corrupted)-1] = sha256SomeHash
+					if err := v.VerifyBatchInclusionProof(indices, size, corrupted, root, hashes); err == nil {
+						t.Error("VerifyBatchInclusionProof() with a corrupted proof returned nil err")
+					}
+				}
+				if err := v.VerifyBatchInclusionProof(indices, size, append(batch, sha256SomeHash), root, hashes); err == nil {
+					t.Error("VerifyBatchInclusionProof() with a long proof returned nil err")
+				}
+				wrongHashes := append([][]byte{sha256SomeHash}, hashes[1:]...)
+				if err := v.VerifyBatchInclusionProof(indices, size, batch, root, wrongHashes); err == nil {
+					t.Error("VerifyBatchInclusionProof() with a wrong leaf hash returned nil err")
+				}
+			})
+		}
+	}
+}
+
+func TestMergeInclusionProofsErrors(t *testing.T) {
+	tree, _ := createTree(10)
+	_, proof2 := getLeafAndProof(tree, 2)
+	_, proof5 := getLeafAndProof(tree, 5)
+	for _, tc := range []struct {
+		desc    string
+		indices []int64
+		size    int64
+		proofs  [][][]byte
+	}{
+		{desc: "unsorted", indices: []int64{5, 2}, size: 10, proofs: [][][]byte{proof5, proof2}},
+		{desc: "duplicate", indices: []int64{2, 2}, size: 10, proofs: [][][]byte{proof2, proof2}},
+		{desc: "beyond size", indices: []int64{2, 10}, size: 10, proofs: [][][]byte{proof2, proof5}},
+		{desc: "negative", indices: []int64{-1}, size: 10, proofs: [][][]byte{proof2}},
+		{desc: "missing proof", indices: []int64{2, 5}, size: 10, proofs: [][][]byte{proof2}},
+		{desc: "wrong proof size", indices: []int64{2}, size: 10, proofs: [][][]byte{proof2[1:]}},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			if _, err := MergeInclusionProofs(tc.indices, tc.size, tc.proofs); err == nil {
+				t.Error("MergeInclusionProofs() returned nil err")
+			}
+		})
+	}
+}
+
+func TestVerifyRangeInclusionProofGenerated(t *testing.T) {
+	const size = 37
+	tree, v := createTree(size)
+	factory := &compact.RangeFactory{Hash: rfc6962.DefaultHasher.HashChildren}
+	leafHashes := make([][]byte, size)
+	for i := range leafHashes {
+		leafHashes[i] = tree.LeafHash(int64(i) + 1)
+	}
+	// rangeHashes returns the compact range hashes for leaves [begin, end).
+	rangeHashes := func(begin, end int64) [][]byte {
+		rng := factory.NewEmptyRange(uint64(begin))
+		for _, hash := range leafHashes[begin:end] {
+			if err := rng.Append(hash, nil); err != nil {
+				t.Fatalf("Append(): %v", err)
+			}
+		}
+		return rng.Hashes()
+	}
+
+	for treeSize := int64(1); treeSize <= size; treeSize++ {
+		root := tree.RootAtSnapshot(treeSize).Hash()
+		for begin := int64(0); begin < treeSize; begin++ {
+			for end := begin + 1; end <= treeSize; end++ {
+				left, right := rangeHashes(0, begin), rangeHashes(end, treeSize)
+				leaves := leafHashes[begin:end]
+				if err := v.VerifyRangeInclusionProof(begin, treeSize, leaves, left, right, root); err != nil {
+					t.Fatalf("VerifyRangeInclusionProof(%d, %d, [%d, %d)): %v", begin, treeSize, begin, end, err)
+				}
+				// A range shifted by one leaf must not verify.
+				if begin > 0 {
+					if err := v.VerifyRangeInclusionProof(begin-1, treeSize, leaves, rangeHashes(0, begin-1), rangeHashes(end-1, treeSize), root); err == nil {
+						t.Errorf("VerifyRangeInclusionProof(%d, %d, [%d, %d)) with shifted leaves returned nil err", begin-1, treeSize, begin, end)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestVerifyRangeInclusionProofErrors(t *testing.T) {
+	tree, v := createTree(10)
+	root := tree.CurrentRoot().Hash()
+	leaf := tree.LeafHash(3)
+	_, proof := getLeafAndProof(tree, 2)
+	for _, tc := range []struct {
+		desc        string
+		begin, size int64
+		leaves      [][]byte
+		left, right [][]byte
+	}{
+		{desc: "no leaves", begin: 2, size: 10},
+		{desc: "negative begin", begin: -1, size: 10, leaves: [][]byte{leaf}},
+		{desc: "beyond size", begin: 10, size: 10, leaves: [][]byte{leaf}},
+		{desc: "short left", begin: 2, size: 10, leaves: [][]byte{leaf}, right: proof[1:]},
+		{desc: "wrong hashes", begin: 2, size: 10, leaves: [][]byte{leaf}, left: [][]byte{sha256SomeHash}, right: [][]byte{sha256SomeHash, sha256SomeHash}},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			if err := v.VerifyRangeInclusionProof(tc.begin, tc.size, tc.leaves, tc.left, tc.right, root); err == nil {
+				t.Error("VerifyRangeInclusionProof() returned nil err")
+			}
+		})
+	}
+}
+
 func TestVerifyConsistencyProof(t *testing.T) {
 	v := NewLogVerifier(rfc6962.DefaultHasher)
 
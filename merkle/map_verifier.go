@@ -16,7 +16,9 @@ package merkle
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/google/trillian"
 	"github.com/google/trillian/merkle/hashers"
@@ -92,3 +94,134 @@ func VerifyMapInclusionProof(treeID int64, leaf *trillian.MapLeaf, expectedRoot
 	}
 	return nil
 }
+
+// VerifyMapBatchInclusionProof verifies that the passed in expectedRoot can be
+// reconstructed correctly from the leaves and a compact batch inclusion proof,
+// as returned by SparseMerkleTreeReader.CompactBatchInclusionProof.
+//
+// The leaves may be passed in any order. A leaf may be repeated as long as
+// each copy has the same value.
+//
+// Returns nil on a successful verification, and an error otherwise.
+func VerifyMapBatchInclusionProof(treeID int64, leaves []*trillian.MapLeaf, expectedRoot []byte, proof [][]byte, h hashers.MapHasher) error {
+	if len(leaves) == 0 {
+		return errors.New("no leaves to verify")
+	}
+	for i, leaf := range leaves {
+		if got, want := len(leaf.Index)*8, h.BitLen(); got != want {
+			return fmt.Errorf("leaves[%d] index len: %d, want %d", i, got, want)
+		}
+	}
+	for i, element := range proof {
+		if got, wanta, wantb := len(element), 0, h.Size(); got != wanta && got != wantb {
+			return fmt.Errorf("proof[%d] len: %d, want %d or %d", i, got, wanta, wantb)
+		}
+	}
+
+	sorted := make([]*trillian.MapLeaf, len(leaves))
+	copy(sorted, leaves)
+	sort.Slice(sorted, func(i, j int) bool { return bytes.Compare(sorted[i].Index, sorted[j].Index) < 0 })
+
+	ids := make([]storage.NodeID, 0, len(sorted))
+	hashes := make([][]byte, 0, len(sorted))
+	for i, leaf := range sorted {
+		leafHash := h.HashLeaf(treeID, leaf.Index, leaf.LeafValue)
+		if len(leaf.LeafValue) == 0 && len(leaf.LeafHash) == 0 {
+			// An empty value that has never been set, see VerifyMapInclusionProof.
+			leafHash = nil
+		}
+		if i > 0 && bytes.Equal(leaf.Index, sorted[i-1].Index) {
+			if !bytes.Equal(leafHash, hashes[len(hashes)-1]) {
+				return fmt.Errorf("conflicting leaves for index %x", leaf.Index)
+			}
+			continue
+		}
+		ids = append(ids, storage.NewNodeIDFromHash(leaf.Index))
+		hashes = append(hashes, leafHash)
+	}
+
+	next := 0
+	if err := batchProofWalk(ids, func(height, i, j int) error {
+		depth := h.BitLen() - height
+		var sibHash []byte
+		var sibID *storage.NodeID
+		if j >= 0 {
+			sibHash, sibID = hashes[j], ids[j].MaskLeft(depth)
+		} else {
+			if next >= len(proof) {
+				return fmt.Errorf("proof too short: %d elements", len(proof))
+			}
+			sibHash, sibID = proof[next], ids[i].Neighbor(depth)
+			next++
+		}
+
+		runningHash := hashes[i]
+		// Both branches empty: keep the empty marker, as in VerifyMapInclusionProof.
+		if len(runningHash) == 0 && len(sibHash) == 0 {
+			return nil
+		}
+		if len(runningHash) == 0 {
+			runningHash = h.HashEmpty(treeID, ids[i].MaskLeft(depth).Path, height)
+		}
+		if len(sibHash) == 0 {
+			sibHash = h.HashEmpty(treeID, sibID.Path, height)
+		}
+		if ids[i].Bit(height) == 0 {
+			hashes[i] = h.HashChildren(runningHash, sibHash)
+		} else {
+			hashes[i] = h.HashChildren(sibHash, runningHash)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	if next != len(proof) {
+		return fmt.Errorf("proof too long: used %d of %d elements", next, len(proof))
+	}
+
+	runningHash := hashes[0]
+	if len(runningHash) == 0 {
+		runningHash = h.HashEmpty(treeID, ids[0].MaskLeft(0).Path, h.BitLen())
+	}
+	if got, want := runningHash, expectedRoot; !bytes.Equal(got, want) {
+		return fmt.Errorf("calculated root: %x, want: %x", got, want)
+	}
+	return nil
+}
+
+// batchProofWalk walks the paths from the given sorted, distinct leaf IDs up
+// to the root, calling visit once for each node on those paths below the
+// root. Nodes are identified by the position in ids of the leftmost leaf below
+// them. visit is given the node i and, if the node's sibling is also on one of
+// the paths, the sibling j > i; otherwise j is -1 and the sibling comes from
+// the proof. Nodes are visited level by level from the leaves up, and within a
+// level in increasing index order, which is the order of the proof elements.
+func batchProofWalk(ids []storage.NodeID, visit func(height, i, j int) error) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	level := make([]int, len(ids))
+	for i := range level {
+		level[i] = i
+	}
+	bitLen := ids[0].PrefixLenBits
+	for height := 0; height < bitLen; height++ {
+		parentDepth := bitLen - height - 1
+		// The parents are written in place; there are never more of them than
+		// nodes already read from the current level.
+		parents := level[:0]
+		for k := 0; k < len(level); k++ {
+			i, j := level[k], -1
+			if k+1 < len(level) && bytes.Equal(ids[i].MaskLeft(parentDepth).Path, ids[level[k+1]].MaskLeft(parentDepth).Path) {
+				j = level[k+1]
+				k++
+			}
+			if err := visit(height, i, j); err != nil {
+				return err
+			}
+			parents = append(parents, i)
+		}
+		level = parents
+	}
+	return nil
+}
@@ -68,6 +68,10 @@ func TestConiksHasherTestVectors(t *testing.T) {
 			if err := VerifyMapInclusionProof(tc.treeID, &leaf, tc.root, tc.proof, h); err != nil {
 				t.Errorf("VerifyMapInclusionProof failed: %v", err)
 			}
+			// A compact proof for a single leaf is its full inclusion proof.
+			if err := VerifyMapBatchInclusionProof(tc.treeID, []*trillian.MapLeaf{&leaf}, tc.root, tc.proof, h); err != nil {
+				t.Errorf("VerifyMapBatchInclusionProof failed: %v", err)
+			}
 		})
 	}
 }
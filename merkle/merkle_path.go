@@ -462,3 +462,109 @@ func siblingIDSkipLevels(snapshot, lastNode int64, level int, node int64, maxBit
 	l, sibling := skipMissingLevels(snapshot, lastNode, level, node)
 	return storage.NewNodeIDForTreeCoords(int64(l), sibling, maxBitLen)
 }
+
+// proofNode identifies a node of a log Merkle tree of a given size by its
+// level (0 for leaves) and its index within that level. The node may be an
+// ephemeral one on the right border of the tree.
+type proofNode struct {
+	level uint
+	index int64
+}
+
+// inclusionProofNodes returns the nodes whose hashes make up the inclusion
+// proof for the leaf at index in a tree of the given size, from the bottom to
+// the root. The caller must make sure that 0 <= index < size.
+func inclusionProofNodes(index, size int64) []proofNode {
+	inner, border := decompInclProof(index, size)
+	nodes := make([]proofNode, 0, inner+border)
+	for level := uint(0); level < uint(inner); level++ {
+		nodes = append(nodes, proofNode{level: level, index: (index >> level) ^ 1})
+	}
+	for level := uint(inner); index>>level > 0; level++ {
+		if (index>>level)&1 == 1 {
+			nodes = append(nodes, proofNode{level: level, index: (index >> level) ^ 1})
+		}
+	}
+	return nodes
+}
+
+// checkBatchLeafIndices checks that leafIndices are in increasing order,
+// without duplicates, and within a tree of size treeSize.
+func checkBatchLeafIndices(leafIndices []int64, treeSize int64) error {
+	for i, index := range leafIndices {
+		switch {
+		case index < 0:
+			return fmt.Errorf("leafIndices[%d] %d < 0", i, index)
+		case index >= treeSize:
+			return fmt.Errorf("leafIndices[%d] is beyond treeSize: %d >= %d", i, index, treeSize)
+		case i > 0 && index <= leafIndices[i-1]:
+			return fmt.Errorf("leafIndices[%d] %d <= leafIndices[%d] %d, want increasing indices", i, index, i-1, leafIndices[i-1])
+		}
+	}
+	return nil
+}
+
+// MergeInclusionProofs combines the inclusion proofs for several leaves of a
+// tree into a single batch inclusion proof, in which every node shared by the
+// individual proofs appears only once. leafIndices must be in increasing
+// order without duplicates, and proofs[i] must be the inclusion proof for
+// leafIndices[i] in a tree of size treeSize.
+//
+// The batch proof holds the hashes of each individual proof in turn, from
+// the bottom to the root, skipping nodes that already appeared in the proof
+// of a lower leaf index. It can be checked with
+// LogVerifier.VerifyBatchInclusionProof.
+func MergeInclusionProofs(leafIndices []int64, treeSize int64, proofs [][][]byte) ([][]byte, error) {
+	if got, want := len(proofs), len(leafIndices); got != want {
+		return nil, fmt.Errorf("got %d proofs for %d leaves", got, want)
+	}
+	if err := checkBatchLeafIndices(leafIndices, treeSize); err != nil {
+		return nil, err
+	}
+	var batch [][]byte
+	seen := make(map[proofNode]bool)
+	for i, index := range leafIndices {
+		nodes := inclusionProofNodes(index, treeSize)
+		if got, want := len(proofs[i]), len(nodes); got != want {
+			return nil, fmt.Errorf("proofs[%d] has size %d, want %d", i, got, want)
+		}
+		for j, node := range nodes {
+			if !seen[node] {
+				seen[node] = true
+				batch = append(batch, proofs[i][j])
+			}
+		}
+	}
+	return batch, nil
+}
+
+// splitBatchInclusionProof undoes MergeInclusionProofs, returning the
+// individual inclusion proof for each of leafIndices.
+func splitBatchInclusionProof(leafIndices []int64, treeSize int64, batch [][]byte) ([][][]byte, error) {
+	if err := checkBatchLeafIndices(leafIndices, treeSize); err != nil {
+		return nil, err
+	}
+	proofs := make([][][]byte, len(leafIndices))
+	hashes := make(map[proofNode][]byte)
+	next := 0
+	for i, index := range leafIndices {
+		nodes := inclusionProofNodes(index, treeSize)
+		proofs[i] = make([][]byte, len(nodes))
+		for j, node := range nodes {
+			hash, ok := hashes[node]
+			if !ok {
+				if next >= len(batch) {
+					return nil, fmt.Errorf("batch proof too short: %d hashes", len(batch))
+				}
+				hash = batch[next]
+				hashes[node] = hash
+				next++
+			}
+			proofs[i][j] = hash
+		}
+	}
+	if next != len(batch) {
+		return nil, fmt.Errorf("batch proof too long: used %d of %d hashes", next, len(batch))
+	}
+	return proofs, nil
+}
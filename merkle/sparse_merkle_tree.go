@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sort"
 	"sync"
 
 	"github.com/golang/glog"
@@ -485,6 +486,52 @@ func (s SparseMerkleTreeReader) BatchInclusionProof(ctx context.Context, rev int
 	return r, nil
 }
 
+// CompactBatchInclusionProof returns a single inclusion (or non-inclusion)
+// proof for all of the specified keys at the specified revision. Sibling
+// nodes shared between the keys' paths, and nodes on another key's path, are
+// left out. The proof can be checked with VerifyMapBatchInclusionProof.
+func (s SparseMerkleTreeReader) CompactBatchInclusionProof(ctx context.Context, rev int64, indices [][]byte) ([][]byte, error) {
+	ctx, spanEnd := spanFor(ctx, "CompactBatchInclusionProof")
+	defer spanEnd()
+
+	sorted := make([][]byte, len(indices))
+	copy(sorted, indices)
+	sort.Slice(sorted, func(i, j int) bool { return bytes.Compare(sorted[i], sorted[j]) < 0 })
+	ids := make([]storage.NodeID, 0, len(sorted))
+	for i, index := range sorted {
+		if i > 0 && bytes.Equal(index, sorted[i-1]) {
+			continue
+		}
+		ids = append(ids, storage.NewNodeIDFromHash(index))
+	}
+
+	var sibs []storage.NodeID
+	if err := batchProofWalk(ids, func(height, i, j int) error {
+		if j < 0 {
+			sibs = append(sibs, *ids[i].Neighbor(ids[i].PrefixLenBits - height))
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	nodes, err := s.tx.GetMerkleNodes(ctx, rev, sibs)
+	if err != nil {
+		return nil, err
+	}
+	nodeMap := make(map[string][]byte)
+	for _, n := range nodes {
+		nodeMap[n.NodeID.AsKey()] = n.Hash
+	}
+
+	// Siblings without a node from storage are empty subtrees, and are left nil.
+	proof := make([][]byte, len(sibs))
+	for i, sib := range sibs {
+		proof[i] = nodeMap[sib.AsKey()]
+	}
+	return proof, nil
+}
+
 // SetLeaves adds a batch of leaves to the in-flight tree update.
 func (s *SparseMerkleTreeWriter) SetLeaves(ctx context.Context, leaves []HashKeyValue) error {
 	ctx, spanEnd := spanFor(ctx, "SetLeaves")
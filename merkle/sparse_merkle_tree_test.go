@@ -22,12 +22,14 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"math/big"
 	"os"
 	"runtime/pprof"
 	"sync"
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/trillian"
 	"github.com/google/trillian/merkle/maphasher"
 	"github.com/google/trillian/storage"
 	"github.com/google/trillian/testonly"
@@ -604,3 +606,112 @@ func TestSparseMerkleTreeWriterBigBatch(t *testing.T) {
 	}
 	maybeProfileMemory(t)
 }
+
+func TestCompactBatchInclusionProof(t *testing.T) {
+	ctx := context.Background()
+	const treeID = 0
+	const rev = 100
+	h := maphasher.Default
+
+	// Build a small map directly with HStar2, keeping every non-empty node.
+	leaves := make([]*trillian.MapLeaf, 0, 20)
+	for i := 0; i < 20; i++ {
+		key := fmt.Sprintf("key%d", i)
+		leaves = append(leaves, &trillian.MapLeaf{Index: testonly.HashKey(key), LeafValue: []byte("value-" + key)})
+	}
+	// Two leaves that share all but their last bit, so that they are
+	// each other's siblings.
+	near := testonly.HashKey("near")
+	nearSib := append([]byte{}, near...)
+	nearSib[len(nearSib)-1] ^= 1
+	leaves = append(leaves,
+		&trillian.MapLeaf{Index: near, LeafValue: []byte("near")},
+		&trillian.MapLeaf{Index: nearSib, LeafValue: []byte("nearSib")})
+
+	nodes := make(map[string]storage.Node)
+	values := make([]*HStar2LeafHash, 0, len(leaves))
+	for _, l := range leaves {
+		l.LeafHash = h.HashLeaf(treeID, l.Index, l.LeafValue)
+		id := storage.NewNodeIDFromHash(l.Index)
+		nodes[id.AsKey()] = storage.Node{NodeID: id, Hash: l.LeafHash, NodeRevision: rev}
+		values = append(values, &HStar2LeafHash{Index: new(big.Int).SetBytes(l.Index), LeafHash: l.LeafHash})
+	}
+	hs2 := NewHStar2(treeID, h)
+	root, err := hs2.HStar2Nodes(nil, h.BitLen(), values, nil, func(depth int, index *big.Int, hash []byte) error {
+		id := storage.NewNodeIDFromBigInt(depth, index, h.BitLen())
+		nodes[id.AsKey()] = storage.Node{NodeID: id, Hash: hash, NodeRevision: rev}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("HStar2Nodes(): %v", err)
+	}
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	r, tx := getSparseMerkleTreeReaderWithMockTX(mockCtrl, rev)
+	tx.EXPECT().GetMerkleNodes(gomock.Any(), int64(rev), gomock.Any()).AnyTimes().DoAndReturn(
+		func(_ context.Context, _ int64, ids []storage.NodeID) ([]storage.Node, error) {
+			var ret []storage.Node
+			for _, id := range ids {
+				if n, ok := nodes[id.AsKey()]; ok {
+					ret = append(ret, n)
+				}
+			}
+			return ret, nil
+		})
+
+	absent := &trillian.MapLeaf{Index: testonly.HashKey("absent")}
+	for _, tc := range []struct {
+		desc   string
+		leaves []*trillian.MapLeaf
+	}{
+		{desc: "one", leaves: leaves[:1]},
+		{desc: "absent", leaves: []*trillian.MapLeaf{absent}},
+		{desc: "siblings", leaves: leaves[20:]},
+		{desc: "all", leaves: leaves},
+		{desc: "all plus absent", leaves: append([]*trillian.MapLeaf{absent}, leaves...)},
+		{desc: "duplicates", leaves: []*trillian.MapLeaf{leaves[3], leaves[1], leaves[3], absent, absent}},
+	} {
+		t.Run(tc.desc, func(t *testing.T) {
+			indices := make([][]byte, 0, len(tc.leaves))
+			for _, l := range tc.leaves {
+				indices = append(indices, l.Index)
+			}
+			full, err := r.BatchInclusionProof(ctx, rev, indices)
+			if err != nil {
+				t.Fatalf("BatchInclusionProof(): %v", err)
+			}
+			fullLen := 0
+			for _, l := range tc.leaves {
+				if err := VerifyMapInclusionProof(treeID, l, root, full[string(l.Index)], h); err != nil {
+					t.Fatalf("VerifyMapInclusionProof(%x): %v", l.Index, err)
+				}
+				fullLen += len(full[string(l.Index)])
+			}
+
+			proof, err := r.CompactBatchInclusionProof(ctx, rev, indices)
+			if err != nil {
+				t.Fatalf("CompactBatchInclusionProof(): %v", err)
+			}
+			if len(tc.leaves) > 1 && len(proof) >= fullLen {
+				t.Errorf("compact proof has %d elements, want fewer than %d", len(proof), fullLen)
+			}
+			if err := VerifyMapBatchInclusionProof(treeID, tc.leaves, root, proof, h); err != nil {
+				t.Errorf("VerifyMapBatchInclusionProof(): %v", err)
+			}
+
+			// Tampering with the proof or the leaves must fail verification.
+			if err := VerifyMapBatchInclusionProof(treeID, tc.leaves, root, proof[1:], h); err == nil {
+				t.Error("VerifyMapBatchInclusionProof() with a short proof returned nil err")
+			}
+			if err := VerifyMapBatchInclusionProof(treeID, tc.leaves, root, append(proof, nil), h); err == nil {
+				t.Error("VerifyMapBatchInclusionProof() with a long proof returned nil err")
+			}
+			changed := *tc.leaves[0]
+			changed.LeafValue = []byte("changed")
+			if err := VerifyMapBatchInclusionProof(treeID, append([]*trillian.MapLeaf{&changed}, tc.leaves[1:]...), root, proof, h); err == nil {
+				t.Error("VerifyMapBatchInclusionProof() with a changed leaf returned nil err")
+			}
+		})
+	}
+}
@@ -28,13 +28,14 @@ const traceSpanRoot = "/trillian/mon/"
 
 // RPCStatsInterceptor provides a gRPC interceptor that records statistics about the RPCs passing through it.
 type RPCStatsInterceptor struct {
-	prefix            string
-	timeSource        clock.TimeSource
-	ReqCount          Counter
-	ReqSuccessCount   Counter
-	ReqSuccessLatency Histogram
-	ReqErrorCount     Counter
-	ReqErrorLatency   Histogram
+	prefix             string
+	timeSource         clock.TimeSource
+	ReqCount           Counter
+	ReqSuccessCount    Counter
+	ReqSuccessLatency  Histogram
+	ReqErrorCount      Counter
+	ReqErrorLatency    Histogram
+	StreamMsgSentCount Counter
 }
 
 // NewRPCStatsInterceptor creates a new RPCStatsInterceptor for the given application/component, with
@@ -44,13 +45,14 @@ func NewRPCStatsInterceptor(timeSource clock.TimeSource, prefix string, mf Metri
 		mf = InertMetricFactory{}
 	}
 	interceptor := RPCStatsInterceptor{
-		prefix:            prefix,
-		timeSource:        timeSource,
-		ReqCount:          mf.NewCounter(prefixedName(prefix, "rpc_requests"), "Number of requests", "method"),
-		ReqSuccessCount:   mf.NewCounter(prefixedName(prefix, "rpc_success"), "Number of successful requests", "method"),
-		ReqSuccessLatency: mf.NewHistogram(prefixedName(prefix, "rpc_success_latency"), "Latency of successful requests in seconds", "method"),
-		ReqErrorCount:     mf.NewCounter(prefixedName(prefix, "rpc_errors"), "Number of errored requests", "method"),
-		ReqErrorLatency:   mf.NewHistogram(prefixedName(prefix, "rpc_error_latency"), "Latency of errored requests in seconds", "method"),
+		prefix:             prefix,
+		timeSource:         timeSource,
+		ReqCount:           mf.NewCounter(prefixedName(prefix, "rpc_requests"), "Number of requests", "method"),
+		ReqSuccessCount:    mf.NewCounter(prefixedName(prefix, "rpc_success"), "Number of successful requests", "method"),
+		ReqSuccessLatency:  mf.NewHistogram(prefixedName(prefix, "rpc_success_latency"), "Latency of successful requests in seconds", "method"),
+		ReqErrorCount:      mf.NewCounter(prefixedName(prefix, "rpc_errors"), "Number of errored requests", "method"),
+		ReqErrorLatency:    mf.NewHistogram(prefixedName(prefix, "rpc_error_latency"), "Latency of errored requests in seconds", "method"),
+		StreamMsgSentCount: mf.NewCounter(prefixedName(prefix, "rpc_stream_messages_sent"), "Number of messages sent by streaming requests", "method"),
 	}
 	return &interceptor
 }
@@ -59,6 +61,12 @@ func prefixedName(prefix, name string) string {
 	return fmt.Sprintf("%s_%s", prefix, name)
 }
 
+func (r *RPCStatsInterceptor) recordSuccessLatency(labels []string, startTime time.Time) {
+	latency := clock.SecondsSince(r.timeSource, startTime)
+	r.ReqSuccessCount.Inc(labels...)
+	r.ReqSuccessLatency.Observe(latency, labels...)
+}
+
 func (r *RPCStatsInterceptor) recordFailureLatency(labels []string, startTime time.Time) {
 	latency := clock.SecondsSince(r.timeSource, startTime)
 	r.ReqErrorCount.Inc(labels...)
@@ -95,12 +103,60 @@ func (r *RPCStatsInterceptor) Interceptor() grpc.UnaryServerInterceptor {
 		if err != nil {
 			r.recordFailureLatency(labels, startTime)
 		} else {
-			latency := clock.SecondsSince(r.timeSource, startTime)
-			r.ReqSuccessCount.Inc(labels...)
-			r.ReqSuccessLatency.Observe(latency, labels...)
+			r.recordSuccessLatency(labels, startTime)
 		}
 
 		// Pass the result of the handler invocation back
 		return rsp, err
 	}
 }
+
+// StreamInterceptor returns a StreamServerInterceptor that can be registered with an RPC server
+// and will record the same statistics as Interceptor for streaming handlers, with latencies
+// covering the whole stream. It also counts the messages sent by the handlers.
+func (r *RPCStatsInterceptor) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		labels := []string{info.FullMethod}
+
+		ctx, spanEnd := StartSpan(ss.Context(), traceSpanRoot)
+		defer spanEnd()
+
+		r.ReqCount.Inc(labels...)
+		startTime := r.timeSource.Now()
+
+		defer func() {
+			if rec := recover(); rec != nil {
+				r.recordFailureLatency(labels, startTime)
+				panic(rec)
+			}
+		}()
+
+		err := handler(srv, &statsServerStream{ServerStream: ss, ctx: ctx, r: r, labels: labels})
+		if err != nil {
+			r.recordFailureLatency(labels, startTime)
+		} else {
+			r.recordSuccessLatency(labels, startTime)
+		}
+		return err
+	}
+}
+
+// statsServerStream wraps a grpc.ServerStream to count the messages sent on it.
+type statsServerStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	r      *RPCStatsInterceptor
+	labels []string
+}
+
+func (s *statsServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *statsServerStream) SendMsg(m interface{}) error {
+	if err := s.ServerStream.SendMsg(m); err != nil {
+		return err
+	}
+	s.r.StreamMsgSentCount.Inc(s.labels...)
+	return nil
+}
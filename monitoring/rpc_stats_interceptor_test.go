@@ -187,3 +187,61 @@ func TestCanInitializeNilMetricFactory(t *testing.T) {
 	monitoring.NewRPCStatsInterceptor(&ts, "test_nil_metric_factory", nil)
 	// Should reach here without throwing an exception
 }
+
+// fakeServerStream is a grpc.ServerStream that discards the messages sent.
+type fakeServerStream struct {
+	grpc.ServerStream
+}
+
+func (fakeServerStream) Context() context.Context {
+	return context.Background()
+}
+
+func (fakeServerStream) SendMsg(m interface{}) error {
+	return nil
+}
+
+func TestStreamRequests(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		msgs    int
+		err     error
+		latency time.Duration
+	}{
+		{name: "ok_stream", msgs: 3, latency: 1500 * time.Millisecond},
+		{name: "error_stream", msgs: 1, err: errors.New("bang"), latency: 250 * time.Millisecond},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			ts := clock.PredefinedFake{Base: fakeTime, Delays: []time.Duration{0, test.latency}}
+			stats := monitoring.NewRPCStatsInterceptor(&ts, "test_"+test.name, monitoring.InertMetricFactory{})
+			i := stats.StreamInterceptor()
+
+			method := "streammethod"
+			err := i(nil, fakeServerStream{}, &grpc.StreamServerInfo{FullMethod: method}, func(srv interface{}, stream grpc.ServerStream) error {
+				for m := 0; m < test.msgs; m++ {
+					if err := stream.SendMsg("msg"); err != nil {
+						return err
+					}
+				}
+				return test.err
+			})
+			if err != test.err {
+				t.Errorf("interceptor()=%v; want %v", err, test.err)
+			}
+
+			if got, want := stats.ReqCount.Value(method), 1.0; got != want {
+				t.Errorf("stats.ReqCount=%v; want %v", got, want)
+			}
+			if got, want := stats.StreamMsgSentCount.Value(method), float64(test.msgs); got != want {
+				t.Errorf("stats.StreamMsgSentCount=%v; want %v", got, want)
+			}
+			latency := stats.ReqSuccessLatency
+			if test.err != nil {
+				latency = stats.ReqErrorLatency
+			}
+			if count, sum := latency.Info(method); count != 1 || sum != test.latency.Seconds() {
+				t.Errorf("latency.Info=%v,%v; want 1,%v", count, sum, test.latency.Seconds())
+			}
+		})
+	}
+}
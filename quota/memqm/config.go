@@ -0,0 +1,220 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memqm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/google/trillian/quota"
+)
+
+// Config holds the token buckets of a Manager, or of any other Configurable
+// quota manager. It is usually read from a JSON file, e.g.:
+//
+//	{
+//	  "global": {"write": {"rate": 1000, "burst": 5000}},
+//	  "tree": {
+//	    "read": {"rate": 100, "burst": 500},
+//	    "write": {"rate": 50, "burst": 200}
+//	  },
+//	  "trees": {"123": {"write": {"rate": 10, "burst": 10}}},
+//	  "user": {"read": {"rate": 10, "burst": 100}},
+//	  "users": {"vip": {"read": {"unlimited": true}}}
+//	}
+//
+// Quotas without a bucket are unlimited. The overrides of specific trees and
+// users replace the buckets of their group kind by kind: a kind without a
+// bucket in an override uses its group's bucket, and an unlimited bucket makes
+// it unlimited.
+type Config struct {
+	// Global holds the buckets shared by all requests.
+	Global Buckets `json:"global"`
+	// Tree holds the buckets of each tree, unless overridden in Trees.
+	Tree Buckets `json:"tree"`
+	// User holds the buckets of each user, unless overridden in Users.
+	User Buckets `json:"user"`
+	// Trees overrides the Tree buckets of specific trees, by tree ID.
+	Trees map[int64]Buckets `json:"trees"`
+	// Users overrides the User buckets of specific users.
+	Users map[string]Buckets `json:"users"`
+}
+
+// Buckets holds a bucket for each quota.Kind.
+type Buckets struct {
+	Read  *Bucket `json:"read"`
+	Write *Bucket `json:"write"`
+}
+
+// Bucket configures a token bucket.
+type Bucket struct {
+	// Rate is the number of tokens added to the bucket every second.
+	Rate float64 `json:"rate,omitempty"`
+	// Burst is the maximum number of tokens the bucket holds. Buckets start
+	// full.
+	Burst int `json:"burst,omitempty"`
+	// Unlimited makes the quota unlimited, instead of using a token bucket.
+	// It's meant for overrides of limited groups, and requires Rate and Burst
+	// to be zero.
+	Unlimited bool `json:"unlimited,omitempty"`
+}
+
+// LoadConfig reads a JSON encoded Config from path, and validates it.
+func LoadConfig(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := ParseConfig(b)
+	if err != nil {
+		return nil, fmt.Errorf("quota config %q: %v", path, err)
+	}
+	return cfg, nil
+}
+
+// ParseConfig decodes a JSON encoded Config, and validates it.
+func ParseConfig(b []byte) (*Config, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	var cfg Config
+	if err := dec.Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid: %v", err)
+	}
+	return &cfg, nil
+}
+
+// SaveConfig validates cfg, and writes it to path as JSON. The file is
+// replaced atomically, so LoadConfig never reads a partially written config.
+func SaveConfig(path string, cfg *Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	_, err = f.Write(append(b, '\n'))
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// Validate checks that all the buckets of cfg have positive rates and bursts.
+func (cfg *Config) Validate() error {
+	if err := cfg.Global.validate("global"); err != nil {
+		return err
+	}
+	if err := cfg.Tree.validate("tree"); err != nil {
+		return err
+	}
+	if err := cfg.User.validate("user"); err != nil {
+		return err
+	}
+	for id, b := range cfg.Trees {
+		if id <= 0 {
+			return fmt.Errorf("invalid tree ID: %v (>0 required)", id)
+		}
+		if err := b.validate(fmt.Sprintf("trees/%v", id)); err != nil {
+			return err
+		}
+	}
+	for user, b := range cfg.Users {
+		if err := b.validate(fmt.Sprintf("users/%v", user)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get returns the bucket of kind, or nil if kind is unlimited.
+func (b Buckets) Get(kind quota.Kind) *Bucket {
+	if kind == quota.Read {
+		return b.Read
+	}
+	return b.Write
+}
+
+// Set sets the bucket of kind. A nil bucket makes kind unlimited.
+func (b *Buckets) Set(kind quota.Kind, bucket *Bucket) {
+	if kind == quota.Read {
+		b.Read = bucket
+	} else {
+		b.Write = bucket
+	}
+}
+
+func (b Buckets) validate(name string) error {
+	if err := b.Read.validate(name + "/read"); err != nil {
+		return err
+	}
+	return b.Write.validate(name + "/write")
+}
+
+func (b *Bucket) validate(name string) error {
+	switch {
+	case b == nil:
+		return nil
+	case b.Unlimited:
+		if b.Rate != 0 || b.Burst != 0 {
+			return fmt.Errorf("%v: unlimited bucket with rate %v and burst %v (0 required)", name, b.Rate, b.Burst)
+		}
+		return nil
+	case b.Rate <= 0:
+		return fmt.Errorf("%v: invalid rate: %v (>0 required)", name, b.Rate)
+	case b.Burst <= 0:
+		return fmt.Errorf("%v: invalid burst: %v (>0 required)", name, b.Burst)
+	}
+	return nil
+}
+
+// Bucket returns the configured bucket of spec, or nil if spec is unlimited.
+func (cfg *Config) Bucket(spec quota.Spec) *Bucket {
+	var b *Bucket
+	switch spec.Group {
+	case quota.Global:
+		b = cfg.Global.Get(spec.Kind)
+	case quota.Tree:
+		if b = cfg.Trees[spec.TreeID].Get(spec.Kind); b == nil {
+			b = cfg.Tree.Get(spec.Kind)
+		}
+	case quota.User:
+		if b = cfg.Users[spec.User].Get(spec.Kind); b == nil {
+			b = cfg.User.Get(spec.Kind)
+		}
+	}
+	if b != nil && b.Unlimited {
+		return nil
+	}
+	return b
+}
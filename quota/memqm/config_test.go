@@ -0,0 +1,122 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memqm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	for _, test := range []struct {
+		desc    string
+		config  string
+		want    *Config
+		wantErr bool
+	}{
+		{
+			desc: "valid",
+			config: `{
+			  "global": {"write": {"rate": 1000, "burst": 5000}},
+			  "tree": {"read": {"rate": 0.5, "burst": 1}},
+			  "trees": {"123": {"write": {"rate": 10, "burst": 10}}},
+			  "users": {"vip": {"write": {"unlimited": true}}}
+			}`,
+			want: &Config{
+				Global: Buckets{Write: &Bucket{Rate: 1000, Burst: 5000}},
+				Tree:   Buckets{Read: &Bucket{Rate: 0.5, Burst: 1}},
+				Trees:  map[int64]Buckets{123: {Write: &Bucket{Rate: 10, Burst: 10}}},
+				Users:  map[string]Buckets{"vip": {Write: &Bucket{Unlimited: true}}},
+			},
+		},
+		{desc: "empty", config: `{}`, want: &Config{}},
+		{desc: "badJSON", config: `{"global": `, wantErr: true},
+		{desc: "unknownField", config: `{"global": {"write": {"rate": 1, "size": 1}}}`, wantErr: true},
+		{desc: "unknownGroup", config: `{"llamas": {}}`, wantErr: true},
+		{desc: "zeroRate", config: `{"tree": {"write": {"burst": 1}}}`, wantErr: true},
+		{desc: "zeroBurst", config: `{"user": {"read": {"rate": 1}}}`, wantErr: true},
+		{desc: "unlimitedWithRate", config: `{"users": {"vip": {"read": {"unlimited": true, "rate": 1}}}}`, wantErr: true},
+		{desc: "badTreeID", config: `{"trees": {"0": {}}}`, wantErr: true},
+		{desc: "badTreeOverride", config: `{"trees": {"1": {"read": {"rate": -1, "burst": 1}}}}`, wantErr: true},
+		{desc: "badUserOverride", config: `{"users": {"llama": {"write": {"rate": 1, "burst": -1}}}}`, wantErr: true},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			f, err := ioutil.TempFile("", "memqm")
+			if err != nil {
+				t.Fatalf("TempFile(): %v", err)
+			}
+			defer os.Remove(f.Name())
+			if _, err := f.WriteString(test.config); err != nil {
+				t.Fatalf("WriteString(): %v", err)
+			}
+			f.Close()
+
+			got, err := LoadConfig(f.Name())
+			if gotErr := err != nil; gotErr != test.wantErr {
+				t.Fatalf("LoadConfig() returned err = %v, wantErr %v", err, test.wantErr)
+			}
+			if err == nil && !reflect.DeepEqual(got, test.want) {
+				t.Errorf("LoadConfig() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigMissingFile(t *testing.T) {
+	if _, err := LoadConfig("/nonexistent/memqm.json"); err == nil {
+		t.Error("LoadConfig() of missing file returned nil err")
+	}
+}
+
+func TestSaveConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "memqm")
+	if err != nil {
+		t.Fatalf("TempDir(): %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "quota.json")
+
+	cfg := &Config{
+		Global: Buckets{Write: &Bucket{Rate: 1000, Burst: 5000}},
+		Trees:  map[int64]Buckets{123: {Write: &Bucket{Rate: 0.5, Burst: 10}}},
+		Users:  map[string]Buckets{"vip": {Read: &Bucket{Unlimited: true}}},
+	}
+	if err := SaveConfig(path, cfg); err != nil {
+		t.Fatalf("SaveConfig(): %v", err)
+	}
+	got, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig(): %v", err)
+	}
+	if !reflect.DeepEqual(got, cfg) {
+		t.Errorf("LoadConfig() = %+v, want %+v", got, cfg)
+	}
+
+	if err := SaveConfig(path, &Config{Tree: Buckets{Read: &Bucket{Rate: 1}}}); err == nil {
+		t.Error("SaveConfig() of invalid config returned nil err")
+	}
+	if got, err := LoadConfig(path); err != nil || !reflect.DeepEqual(got, cfg) {
+		t.Errorf("LoadConfig() after failed save = (%+v, %v), want %+v", got, err, cfg)
+	}
+	if files, err := ioutil.ReadDir(dir); err != nil || len(files) != 1 {
+		t.Errorf("ReadDir() = (%v files, %v), want only the config", len(files), err)
+	}
+	if err := SaveConfig(filepath.Join(dir, "missing", "quota.json"), cfg); err == nil {
+		t.Error("SaveConfig() to missing directory returned nil err")
+	}
+}
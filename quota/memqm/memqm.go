@@ -0,0 +1,309 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package memqm contains an in-process, token bucket based, quota.Manager
+// implementation.
+//
+// Quotas are held in memory, so each server process enforces its own limits.
+// That makes memqm suitable for single-binary deployments, where etcd based
+// quotas would be an extra dependency.
+package memqm
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/google/trillian/quota"
+	"github.com/google/trillian/util/clock"
+)
+
+// minPruneSize is the number of buckets above which full buckets are pruned.
+const minPruneSize = 1024
+
+// Manager is a quota.Manager that holds a token bucket per spec in memory.
+// Buckets are refilled at a constant rate, up to their burst size.
+type Manager struct {
+	timeSource clock.TimeSource
+	// path is the file the config is saved to, if any.
+	path string
+
+	// mu guards the fields below.
+	mu      sync.Mutex
+	cfg     *Config
+	buckets map[quota.Spec]*bucket
+	// pruneSize is the number of buckets above which full buckets are
+	// pruned.
+	pruneSize int
+}
+
+type bucket struct {
+	tokens float64
+	// last is the time tokens was last refilled.
+	last time.Time
+}
+
+// New returns a Manager enforcing cfg, with timeSource driving the refill of
+// buckets.
+func New(cfg *Config, timeSource clock.TimeSource) (*Manager, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &Manager{
+		timeSource: timeSource,
+		cfg:        cfg,
+		buckets:    make(map[quota.Spec]*bucket),
+		pruneSize:  minPruneSize,
+	}, nil
+}
+
+// NewFromFile returns a Manager enforcing the config loaded from path. Configs
+// passed to SaveConfig are saved to path.
+func NewFromFile(path string, timeSource clock.TimeSource) (*Manager, error) {
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	m, err := New(cfg, timeSource)
+	if err != nil {
+		return nil, err
+	}
+	m.path = path
+	return m, nil
+}
+
+// Config returns the current config of m, which must not be modified.
+func (m *Manager) Config() *Config {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.cfg
+}
+
+// SetConfig replaces the config of m. Buckets keep their current tokens, up to
+// their new burst size.
+func (m *Manager) SetConfig(cfg *Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.timeSource.Now()
+	// Refill existing buckets at their old rate, so the new rate only
+	// applies from now on.
+	for spec, b := range m.buckets {
+		if cb := m.cfg.Bucket(spec); cb != nil {
+			b.refill(cb, now)
+		}
+	}
+	m.cfg = cfg
+	for spec, b := range m.buckets {
+		cb := cfg.Bucket(spec)
+		if cb == nil {
+			delete(m.buckets, spec)
+			continue
+		}
+		b.tokens = math.Min(b.tokens, float64(cb.Burst))
+	}
+	return nil
+}
+
+// GetTokens implements quota.Manager.GetTokens. Tokens are only taken if all
+// specs have enough of them.
+func (m *Manager) GetTokens(ctx context.Context, numTokens int, specs []quota.Spec) error {
+	if err := validateNumTokens(numTokens); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.timeSource.Now()
+	buckets := make([]*bucket, 0, len(specs))
+	for _, spec := range specs {
+		b := m.bucket(spec, now)
+		if b == nil {
+			continue
+		}
+		if b.tokens < float64(numTokens) {
+			return fmt.Errorf("insufficient tokens on %v (%v vs %v)", spec, int(b.tokens), numTokens)
+		}
+		buckets = append(buckets, b)
+	}
+	for _, b := range buckets {
+		b.tokens -= float64(numTokens)
+	}
+	m.prune()
+	return nil
+}
+
+// PeekTokens implements quota.Manager.PeekTokens.
+func (m *Manager) PeekTokens(ctx context.Context, specs []quota.Spec) (map[quota.Spec]int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.timeSource.Now()
+	tokens := make(map[quota.Spec]int)
+	for _, spec := range specs {
+		if b := m.bucket(spec, now); b != nil {
+			tokens[spec] = int(b.tokens)
+		} else {
+			tokens[spec] = quota.MaxTokens
+		}
+	}
+	return tokens, nil
+}
+
+// PutTokens implements quota.Manager.PutTokens. Tokens returned to a bucket
+// can't make it exceed its burst size.
+func (m *Manager) PutTokens(ctx context.Context, numTokens int, specs []quota.Spec) error {
+	if err := validateNumTokens(numTokens); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.timeSource.Now()
+	for _, spec := range specs {
+		if b := m.bucket(spec, now); b != nil {
+			b.tokens = math.Min(b.tokens+float64(numTokens), float64(m.cfg.Bucket(spec).Burst))
+		}
+	}
+	return nil
+}
+
+// ResetQuota implements quota.Manager.ResetQuota, refilling the buckets of
+// specs.
+func (m *Manager) ResetQuota(ctx context.Context, specs []quota.Spec) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, spec := range specs {
+		delete(m.buckets, spec)
+	}
+	return nil
+}
+
+// bucket returns the refilled bucket of spec, creating it full if needed, or
+// nil if spec is unlimited. m.mu must be held.
+func (m *Manager) bucket(spec quota.Spec, now time.Time) *bucket {
+	cb := m.cfg.Bucket(spec)
+	if cb == nil {
+		return nil
+	}
+	b, ok := m.buckets[spec]
+	if !ok {
+		b = &bucket{tokens: float64(cb.Burst), last: now}
+		m.buckets[spec] = b
+		return b
+	}
+	b.refill(cb, now)
+	return b
+}
+
+// prune drops the buckets that are full, as they're equivalent to new ones,
+// once there are more than pruneSize buckets. m.mu must be held.
+func (m *Manager) prune() {
+	if len(m.buckets) <= m.pruneSize {
+		return
+	}
+	now := m.timeSource.Now()
+	for spec, b := range m.buckets {
+		cb := m.cfg.Bucket(spec)
+		b.refill(cb, now)
+		if b.tokens >= float64(cb.Burst) {
+			delete(m.buckets, spec)
+		}
+	}
+	m.pruneSize = 2 * len(m.buckets)
+	if m.pruneSize < minPruneSize {
+		m.pruneSize = minPruneSize
+	}
+}
+
+func (b *bucket) refill(cb *Bucket, now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(b.tokens+elapsed*cb.Rate, float64(cb.Burst))
+	}
+	b.last = now
+}
+
+func validateNumTokens(numTokens int) error {
+	if numTokens <= 0 {
+		return fmt.Errorf("invalid numTokens: %v (>0 required)", numTokens)
+	}
+	return nil
+}
+
+// SaveConfig implements Persistent. If m was created by NewFromFile, cfg is
+// written to its file first, so that reloads and restarts keep it.
+func (m *Manager) SaveConfig(ctx context.Context, cfg *Config) error {
+	if m.path != "" {
+		if err := SaveConfig(m.path, cfg); err != nil {
+			return err
+		}
+	}
+	return m.SetConfig(cfg)
+}
+
+// Configurable is implemented by the quota managers whose buckets are
+// configured by a Config.
+type Configurable interface {
+	// Config returns the current config, which must not be modified.
+	Config() *Config
+	// SetConfig replaces the current config.
+	SetConfig(cfg *Config) error
+}
+
+// Persistent is implemented by the Configurables that can save their config
+// where they load it from, so that changes aren't lost on reload or restart.
+type Persistent interface {
+	Configurable
+	// SaveConfig saves cfg, and makes it the current config.
+	SaveConfig(ctx context.Context, cfg *Config) error
+}
+
+// ReloadOnSIGHUP loads the config of m from path whenever the process receives
+// SIGHUP, until ctx is done. Configs that fail to load are logged, and the
+// current config is kept. SIGHUP is handled from the time ReloadOnSIGHUP
+// returns, by a new goroutine.
+func ReloadOnSIGHUP(ctx context.Context, m Configurable, path string) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(sigs)
+		reloadOnSignal(ctx, m, path, sigs)
+	}()
+}
+
+// reloadOnSignal loads the config of m from path whenever a signal is received
+// from sigs, until ctx is done.
+func reloadOnSignal(ctx context.Context, m Configurable, path string, sigs <-chan os.Signal) {
+	for {
+		select {
+		case <-sigs:
+			cfg, err := LoadConfig(path)
+			if err == nil {
+				err = m.SetConfig(cfg)
+			}
+			if err != nil {
+				glog.Errorf("Failed to reload quota config: %v", err)
+				continue
+			}
+			glog.Infof("Reloaded quota config from %q", path)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,334 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memqm
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/google/trillian/quota"
+	"github.com/google/trillian/util/clock"
+)
+
+var (
+	fakeTime = time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	globalRead  = quota.Spec{Group: quota.Global, Kind: quota.Read}
+	globalWrite = quota.Spec{Group: quota.Global, Kind: quota.Write}
+	treeRead    = quota.Spec{Group: quota.Tree, Kind: quota.Read, TreeID: 10}
+	treeWrite   = quota.Spec{Group: quota.Tree, Kind: quota.Write, TreeID: 10}
+	tree11Read  = quota.Spec{Group: quota.Tree, Kind: quota.Read, TreeID: 11}
+	tree11Write = quota.Spec{Group: quota.Tree, Kind: quota.Write, TreeID: 11}
+	userWrite   = quota.Spec{Group: quota.User, Kind: quota.Write, User: "llama"}
+	vipWrite    = quota.Spec{Group: quota.User, Kind: quota.Write, User: "vip"}
+)
+
+func testConfig() *Config {
+	return &Config{
+		Global: Buckets{Write: &Bucket{Rate: 100, Burst: 1000}},
+		Tree:   Buckets{Read: &Bucket{Rate: 10, Burst: 20}, Write: &Bucket{Rate: 1, Burst: 10}},
+		User:   Buckets{Write: &Bucket{Rate: 1, Burst: 5}},
+		Trees:  map[int64]Buckets{11: {Write: &Bucket{Rate: 2, Burst: 50}}},
+		Users:  map[string]Buckets{"vip": {Write: &Bucket{Unlimited: true}}},
+	}
+}
+
+func newTestManager(t *testing.T) (*Manager, *clock.FakeTimeSource) {
+	t.Helper()
+	ts := clock.NewFake(fakeTime)
+	m, err := New(testConfig(), ts)
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	return m, ts
+}
+
+func peek(t *testing.T, m *Manager, spec quota.Spec) int {
+	t.Helper()
+	tokens, err := m.PeekTokens(context.Background(), []quota.Spec{spec})
+	if err != nil {
+		t.Fatalf("PeekTokens(): %v", err)
+	}
+	return tokens[spec]
+}
+
+func TestManager_PeekTokens(t *testing.T) {
+	m, _ := newTestManager(t)
+	for _, test := range []struct {
+		spec quota.Spec
+		want int
+	}{
+		{spec: globalRead, want: quota.MaxTokens},
+		{spec: globalWrite, want: 1000},
+		{spec: treeRead, want: 20},
+		{spec: treeWrite, want: 10},
+		{spec: tree11Read, want: 20}, // Not overridden
+		{spec: tree11Write, want: 50},
+		{spec: userWrite, want: 5},
+		{spec: vipWrite, want: quota.MaxTokens},
+	} {
+		if got := peek(t, m, test.spec); got != test.want {
+			t.Errorf("PeekTokens(%v) = %v, want %v", test.spec, got, test.want)
+		}
+	}
+}
+
+func TestManager_GetTokensRefill(t *testing.T) {
+	ctx := context.Background()
+	m, ts := newTestManager(t)
+	specs := []quota.Spec{userWrite, treeWrite, globalWrite}
+
+	if err := m.GetTokens(ctx, 5, specs); err != nil {
+		t.Fatalf("GetTokens(5): %v", err)
+	}
+	// The user bucket is now empty, so no tokens are taken from any spec.
+	if err := m.GetTokens(ctx, 1, specs); err == nil {
+		t.Fatal("GetTokens(1) on an empty bucket returned nil err")
+	}
+	if got, want := peek(t, m, treeWrite), 5; got != want {
+		t.Errorf("PeekTokens(%v) = %v, want %v", treeWrite, got, want)
+	}
+
+	// The user bucket refills at 1 token per second.
+	ts.Set(fakeTime.Add(2500 * time.Millisecond))
+	if got, want := peek(t, m, userWrite), 2; got != want {
+		t.Errorf("PeekTokens(%v) = %v, want %v", userWrite, got, want)
+	}
+	if err := m.GetTokens(ctx, 2, specs); err != nil {
+		t.Errorf("GetTokens(2) after refill: %v", err)
+	}
+	if err := m.GetTokens(ctx, 1, specs); err == nil {
+		t.Error("GetTokens(1) after spending the refill returned nil err")
+	}
+
+	// Buckets don't refill past their burst size.
+	ts.Set(fakeTime.Add(time.Hour))
+	if got, want := peek(t, m, userWrite), 5; got != want {
+		t.Errorf("PeekTokens(%v) = %v, want %v", userWrite, got, want)
+	}
+	if got, want := peek(t, m, globalWrite), 1000; got != want {
+		t.Errorf("PeekTokens(%v) = %v, want %v", globalWrite, got, want)
+	}
+}
+
+func TestManager_GetTokensErrors(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestManager(t)
+	for _, numTokens := range []int{-1, 0} {
+		if err := m.GetTokens(ctx, numTokens, []quota.Spec{treeWrite}); err == nil {
+			t.Errorf("GetTokens(%v) returned nil err", numTokens)
+		}
+	}
+	if err := m.GetTokens(ctx, 11, []quota.Spec{treeWrite}); err == nil {
+		t.Error("GetTokens() over burst size returned nil err")
+	}
+	if err := m.GetTokens(ctx, quota.MaxTokens, []quota.Spec{globalRead, vipWrite}); err != nil {
+		t.Errorf("GetTokens() on unlimited specs: %v", err)
+	}
+}
+
+func TestManager_PutTokens(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestManager(t)
+	if err := m.GetTokens(ctx, 8, []quota.Spec{treeWrite}); err != nil {
+		t.Fatalf("GetTokens(): %v", err)
+	}
+	if err := m.PutTokens(ctx, 3, []quota.Spec{treeWrite, globalRead}); err != nil {
+		t.Fatalf("PutTokens(): %v", err)
+	}
+	if got, want := peek(t, m, treeWrite), 5; got != want {
+		t.Errorf("PeekTokens(%v) = %v, want %v", treeWrite, got, want)
+	}
+	if err := m.PutTokens(ctx, 100, []quota.Spec{treeWrite}); err != nil {
+		t.Fatalf("PutTokens(): %v", err)
+	}
+	if got, want := peek(t, m, treeWrite), 10; got != want {
+		t.Errorf("PeekTokens(%v) = %v, want %v", treeWrite, got, want)
+	}
+	if err := m.PutTokens(ctx, 0, []quota.Spec{treeWrite}); err == nil {
+		t.Error("PutTokens(0) returned nil err")
+	}
+}
+
+func TestManager_ResetQuota(t *testing.T) {
+	ctx := context.Background()
+	m, _ := newTestManager(t)
+	if err := m.GetTokens(ctx, 10, []quota.Spec{treeWrite, tree11Write}); err != nil {
+		t.Fatalf("GetTokens(): %v", err)
+	}
+	if err := m.ResetQuota(ctx, []quota.Spec{treeWrite}); err != nil {
+		t.Fatalf("ResetQuota(): %v", err)
+	}
+	if got, want := peek(t, m, treeWrite), 10; got != want {
+		t.Errorf("PeekTokens(%v) = %v, want %v", treeWrite, got, want)
+	}
+	if got, want := peek(t, m, tree11Write), 40; got != want {
+		t.Errorf("PeekTokens(%v) = %v, want %v", tree11Write, got, want)
+	}
+}
+
+func TestManager_SetConfig(t *testing.T) {
+	ctx := context.Background()
+	m, ts := newTestManager(t)
+	if err := m.GetTokens(ctx, 8, []quota.Spec{treeWrite, tree11Write}); err != nil {
+		t.Fatalf("GetTokens(): %v", err)
+	}
+	ts.Set(fakeTime.Add(time.Second))
+
+	cfg := testConfig()
+	cfg.Tree.Write = &Bucket{Rate: 5, Burst: 6}
+	cfg.Trees = nil
+	if err := m.SetConfig(cfg); err != nil {
+		t.Fatalf("SetConfig(): %v", err)
+	}
+	// Tree 10 refilled 1 token at the old rate, and tree 11 is capped to the
+	// new burst size.
+	if got, want := peek(t, m, treeWrite), 3; got != want {
+		t.Errorf("PeekTokens(%v) = %v, want %v", treeWrite, got, want)
+	}
+	if got, want := peek(t, m, tree11Write), 6; got != want {
+		t.Errorf("PeekTokens(%v) = %v, want %v", tree11Write, got, want)
+	}
+	ts.Set(fakeTime.Add(1500 * time.Millisecond))
+	if got, want := peek(t, m, treeWrite), 5; got != want {
+		t.Errorf("PeekTokens(%v) after refill = %v, want %v", treeWrite, got, want)
+	}
+
+	if err := m.SetConfig(&Config{Tree: Buckets{Write: &Bucket{Rate: -1, Burst: 1}}}); err == nil {
+		t.Error("SetConfig() with invalid config returned nil err")
+	}
+	if got, want := peek(t, m, treeWrite), 5; got != want {
+		t.Errorf("PeekTokens(%v) after invalid config = %v, want %v", treeWrite, got, want)
+	}
+}
+
+func TestManager_Prune(t *testing.T) {
+	ctx := context.Background()
+	m, ts := newTestManager(t)
+	spec := func(id int64) quota.Spec {
+		return quota.Spec{Group: quota.Tree, Kind: quota.Write, TreeID: id}
+	}
+	for id := int64(1); id <= minPruneSize; id++ {
+		if err := m.GetTokens(ctx, 1, []quota.Spec{spec(id)}); err != nil {
+			t.Fatalf("GetTokens(): %v", err)
+		}
+	}
+	// All the buckets are full again by now, except for the one just used.
+	ts.Set(fakeTime.Add(time.Minute))
+	if err := m.GetTokens(ctx, 10, []quota.Spec{spec(minPruneSize + 1)}); err != nil {
+		t.Fatalf("GetTokens(): %v", err)
+	}
+	if got, want := len(m.buckets), 1; got != want {
+		t.Errorf("got %v buckets after pruning, want %v", got, want)
+	}
+	if got, want := peek(t, m, spec(minPruneSize+1)), 0; got != want {
+		t.Errorf("PeekTokens() of unpruned bucket = %v, want %v", got, want)
+	}
+}
+
+func TestManager_SaveConfig(t *testing.T) {
+	ctx := context.Background()
+	f, err := ioutil.TempFile("", "memqm")
+	if err != nil {
+		t.Fatalf("TempFile(): %v", err)
+	}
+	defer os.Remove(f.Name())
+	f.Close()
+	if err := SaveConfig(f.Name(), testConfig()); err != nil {
+		t.Fatalf("SaveConfig(): %v", err)
+	}
+
+	m, err := NewFromFile(f.Name(), clock.NewFake(fakeTime))
+	if err != nil {
+		t.Fatalf("NewFromFile(): %v", err)
+	}
+	cfg := testConfig()
+	cfg.Tree.Write = &Bucket{Rate: 1, Burst: 3}
+	if err := m.SaveConfig(ctx, cfg); err != nil {
+		t.Fatalf("SaveConfig(): %v", err)
+	}
+	if got, want := peek(t, m, treeWrite), 3; got != want {
+		t.Errorf("PeekTokens(%v) = %v, want %v", treeWrite, got, want)
+	}
+	// The saved config survives a reload.
+	loaded, err := LoadConfig(f.Name())
+	if err != nil {
+		t.Fatalf("LoadConfig(): %v", err)
+	}
+	if !reflect.DeepEqual(loaded, cfg) {
+		t.Errorf("LoadConfig() = %+v, want %+v", loaded, cfg)
+	}
+
+	if err := m.SaveConfig(ctx, &Config{Tree: Buckets{Write: &Bucket{Rate: 1}}}); err == nil {
+		t.Error("SaveConfig() of invalid config returned nil err")
+	}
+	if got, want := peek(t, m, treeWrite), 3; got != want {
+		t.Errorf("PeekTokens(%v) after invalid config = %v, want %v", treeWrite, got, want)
+	}
+
+	// Managers without a file only change their config.
+	m, _ = newTestManager(t)
+	if err := m.SaveConfig(ctx, cfg); err != nil {
+		t.Fatalf("SaveConfig() without file: %v", err)
+	}
+	if got, want := peek(t, m, treeWrite), 3; got != want {
+		t.Errorf("PeekTokens(%v) = %v, want %v", treeWrite, got, want)
+	}
+}
+
+func TestReloadOnSignal(t *testing.T) {
+	f, err := ioutil.TempFile("", "memqm")
+	if err != nil {
+		t.Fatalf("TempFile(): %v", err)
+	}
+	defer os.Remove(f.Name())
+	f.Close()
+	write := func(cfg string) {
+		if err := ioutil.WriteFile(f.Name(), []byte(cfg), 0600); err != nil {
+			t.Fatalf("WriteFile(): %v", err)
+		}
+	}
+
+	m, _ := newTestManager(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	sigs := make(chan os.Signal)
+	done := make(chan struct{})
+	go func() {
+		reloadOnSignal(ctx, m, f.Name(), sigs)
+		close(done)
+	}()
+
+	// Sending on the unbuffered channel twice ensures the first reload is
+	// complete.
+	write(`{"tree": {"write": {"rate": 1, "burst": 3}}}`)
+	sigs <- syscall.SIGHUP
+	write(`{"tree": {"write": {"rate": 0, "burst": 3}}}`)
+	sigs <- syscall.SIGHUP
+	sigs <- syscall.SIGHUP
+	cancel()
+	<-done
+
+	if got, want := peek(t, m, treeWrite), 3; got != want {
+		t.Errorf("PeekTokens(%v) = %v, want %v", treeWrite, got, want)
+	}
+	if got, want := peek(t, m, userWrite), quota.MaxTokens; got != want {
+		t.Errorf("PeekTokens(%v) = %v, want %v", userWrite, got, want)
+	}
+}
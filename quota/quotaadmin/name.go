@@ -0,0 +1,118 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quotaadmin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/trillian/quota"
+)
+
+const (
+	namePrefix = "quotas/"
+	nameSuffix = "/config"
+)
+
+// Name identifies a trillian.QuotaConfig. See QuotaConfig.name for the format
+// of names.
+type Name struct {
+	Group quota.Group
+	Kind  quota.Kind
+	// TreeID is the ID of the tree of a Tree config, or zero for the default
+	// config of trees.
+	TreeID int64
+	// User is the user of a User config, or empty for the default config of
+	// users.
+	User string
+}
+
+// ParseName parses a config name.
+func ParseName(name string) (Name, error) {
+	if !strings.HasPrefix(name, namePrefix) || !strings.HasSuffix(name, nameSuffix) {
+		return Name{}, fmt.Errorf("invalid config name %q", name)
+	}
+	path := strings.TrimSuffix(strings.TrimPrefix(name, namePrefix), nameSuffix)
+	i := strings.Index(path, "/")
+	j := strings.LastIndex(path, "/")
+	if i < 0 {
+		return Name{}, fmt.Errorf("invalid config name %q", name)
+	}
+
+	var n Name
+	switch kind := path[j+1:]; kind {
+	case "read":
+		n.Kind = quota.Read
+	case "write":
+		n.Kind = quota.Write
+	default:
+		return Name{}, fmt.Errorf("invalid kind %q in config name %q", kind, name)
+	}
+	var id string
+	if i < j {
+		id = path[i+1 : j]
+	}
+	switch group := path[:i]; {
+	case group == "global" && i == j:
+		n.Group = quota.Global
+	case group == "trees":
+		n.Group = quota.Tree
+		if i < j {
+			var err error
+			if n.TreeID, err = strconv.ParseInt(id, 10, 64); err != nil || n.TreeID <= 0 {
+				return Name{}, fmt.Errorf("invalid tree ID %q in config name %q", id, name)
+			}
+		}
+	case group == "users":
+		n.Group = quota.User
+		if i < j {
+			if id == "" {
+				return Name{}, fmt.Errorf("empty user in config name %q", name)
+			}
+			n.User = id
+		}
+	default:
+		return Name{}, fmt.Errorf("invalid config name %q", name)
+	}
+	return n, nil
+}
+
+// IsDefault returns true if n is the default config of trees or users.
+func (n Name) IsDefault() bool {
+	return (n.Group == quota.Tree && n.TreeID == 0) || (n.Group == quota.User && n.User == "")
+}
+
+// Spec returns the quota.Spec configured by n. It must not be called for
+// default configs.
+func (n Name) Spec() quota.Spec {
+	return quota.Spec{Group: n.Group, Kind: n.Kind, TreeID: n.TreeID, User: n.User}
+}
+
+// String returns the config name of n.
+func (n Name) String() string {
+	kind := strings.ToLower(n.Kind.String())
+	switch {
+	case n.Group == quota.Global:
+		return fmt.Sprintf("%vglobal/%v%v", namePrefix, kind, nameSuffix)
+	case n.Group == quota.Tree && n.TreeID == 0:
+		return fmt.Sprintf("%vtrees/%v%v", namePrefix, kind, nameSuffix)
+	case n.Group == quota.Tree:
+		return fmt.Sprintf("%vtrees/%v/%v%v", namePrefix, n.TreeID, kind, nameSuffix)
+	case n.User == "":
+		return fmt.Sprintf("%vusers/%v%v", namePrefix, kind, nameSuffix)
+	}
+	return fmt.Sprintf("%vusers/%v/%v%v", namePrefix, n.User, kind, nameSuffix)
+}
@@ -0,0 +1,72 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quotaadmin
+
+import (
+	"testing"
+
+	"github.com/google/trillian/quota"
+)
+
+func TestParseName(t *testing.T) {
+	for _, test := range []struct {
+		name        string
+		want        Name
+		wantDefault bool
+	}{
+		{name: "quotas/global/read/config", want: Name{Group: quota.Global, Kind: quota.Read}},
+		{name: "quotas/global/write/config", want: Name{Group: quota.Global, Kind: quota.Write}},
+		{name: "quotas/trees/12345/write/config", want: Name{Group: quota.Tree, Kind: quota.Write, TreeID: 12345}},
+		{name: "quotas/trees/read/config", want: Name{Group: quota.Tree, Kind: quota.Read}, wantDefault: true},
+		{name: "quotas/users/llama/read/config", want: Name{Group: quota.User, Kind: quota.Read, User: "llama"}},
+		{name: "quotas/users/a/b/write/config", want: Name{Group: quota.User, Kind: quota.Write, User: "a/b"}},
+		{name: "quotas/users/write/config", want: Name{Group: quota.User, Kind: quota.Write}, wantDefault: true},
+	} {
+		got, err := ParseName(test.name)
+		if err != nil {
+			t.Errorf("ParseName(%q): %v", test.name, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("ParseName(%q) = %+v, want %+v", test.name, got, test.want)
+		}
+		if got.IsDefault() != test.wantDefault {
+			t.Errorf("ParseName(%q).IsDefault() = %v, want %v", test.name, got.IsDefault(), test.wantDefault)
+		}
+		if s := got.String(); s != test.name {
+			t.Errorf("ParseName(%q).String() = %q", test.name, s)
+		}
+	}
+}
+
+func TestParseName_Errors(t *testing.T) {
+	for _, name := range []string{
+		"",
+		"quotas/global/config",
+		"quotas/global/-/config",
+		"quotas/global/1/read/config",
+		"quotas/trees/-/write/config",
+		"quotas/trees/0/write/config",
+		"quotas/trees/llama/write/config",
+		"quotas/users//write/config",
+		"quotas/llamas/write/config",
+		"quotas/trees/1/write",
+		"trees/1/write/config",
+	} {
+		if n, err := ParseName(name); err == nil {
+			t.Errorf("ParseName(%q) = %+v, want err", name, n)
+		}
+	}
+}
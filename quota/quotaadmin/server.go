@@ -0,0 +1,213 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package quotaadmin contains an implementation of the trillian.Quota admin
+// service, which manages the quota configs of any quota.Manager with a Store.
+package quotaadmin
+
+import (
+	"context"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/google/trillian"
+	"github.com/google/trillian/quota"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server is an implementation of trillian.QuotaServer.
+type Server struct {
+	qm    quota.Manager
+	store Store
+
+	// mu serializes config changes, which read, modify and write back all
+	// the configs of store.
+	mu sync.Mutex
+}
+
+// NewServer returns a trillian.QuotaServer that manages the configs of qm,
+// held in store.
+func NewServer(qm quota.Manager, store Store) *Server {
+	return &Server{qm: qm, store: store}
+}
+
+// CreateQuotaConfig implements trillian.QuotaServer.CreateQuotaConfig.
+func (s *Server) CreateQuotaConfig(ctx context.Context, req *trillian.CreateQuotaConfigRequest) (*trillian.QuotaConfig, error) {
+	c := req.GetConfig()
+	if c == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "a config is required")
+	}
+	if _, err := parseName(c.Name); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	configs, err := s.store.Configs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if find(configs, c.Name) != nil {
+		return nil, status.Errorf(codes.AlreadyExists, "config %v already exists", c.Name)
+	}
+	created := proto.Clone(c).(*trillian.QuotaConfig)
+	created.CurrentTokens = 0
+	if err := s.store.SetConfigs(ctx, append(configs, created)); err != nil {
+		return nil, err
+	}
+	got, err := s.get(ctx, c.Name)
+	if status.Code(err) == codes.NotFound {
+		// The store dropped the config, e.g. a disabled config of a quota
+		// that's unlimited anyway.
+		return nil, status.Errorf(codes.FailedPrecondition, "config %v has no effect, and wasn't created", c.Name)
+	}
+	return got, err
+}
+
+// GetQuotaConfig implements trillian.QuotaServer.GetQuotaConfig.
+func (s *Server) GetQuotaConfig(ctx context.Context, req *trillian.GetQuotaConfigRequest) (*trillian.QuotaConfig, error) {
+	if _, err := parseName(req.Name); err != nil {
+		return nil, err
+	}
+	return s.get(ctx, req.Name)
+}
+
+// ListQuotaConfigs implements trillian.QuotaServer.ListQuotaConfigs.
+func (s *Server) ListQuotaConfigs(ctx context.Context, req *trillian.ListQuotaConfigsRequest) (*trillian.ListQuotaConfigsResponse, error) {
+	configs, err := s.store.Configs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &trillian.ListQuotaConfigsResponse{Configs: configs}, nil
+}
+
+// UpdateQuotaConfig implements trillian.QuotaServer.UpdateQuotaConfig.
+func (s *Server) UpdateQuotaConfig(ctx context.Context, req *trillian.UpdateQuotaConfigRequest) (*trillian.QuotaConfig, error) {
+	c := req.GetConfig()
+	if c == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "a config is required")
+	}
+	n, err := parseName(c.Name)
+	if err != nil {
+		return nil, err
+	}
+	if req.ResetQuota && n.IsDefault() {
+		return nil, status.Errorf(codes.InvalidArgument, "reset_quota is not supported for default configs")
+	}
+	paths := req.GetUpdateMask().GetPaths()
+	if len(paths) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "an update_mask is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	configs, err := s.store.Configs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	updated := find(configs, c.Name)
+	if updated == nil {
+		return nil, status.Errorf(codes.NotFound, "config %v not found", c.Name)
+	}
+	for _, path := range paths {
+		switch path {
+		case "max_tokens":
+			updated.MaxTokens = c.MaxTokens
+		case "replenish_rate":
+			updated.ReplenishRate = c.ReplenishRate
+		case "disabled":
+			updated.Disabled = c.Disabled
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "invalid update_mask path: %q", path)
+		}
+	}
+	if err := s.store.SetConfigs(ctx, configs); err != nil {
+		return nil, err
+	}
+	if req.ResetQuota {
+		if err := s.qm.ResetQuota(ctx, []quota.Spec{n.Spec()}); err != nil {
+			return nil, status.Errorf(codes.Internal, "config updated, but failed to reset quota: %v", err)
+		}
+	}
+	return s.get(ctx, c.Name)
+}
+
+// DeleteQuotaConfig implements trillian.QuotaServer.DeleteQuotaConfig.
+func (s *Server) DeleteQuotaConfig(ctx context.Context, req *trillian.DeleteQuotaConfigRequest) (*empty.Empty, error) {
+	if _, err := parseName(req.Name); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	configs, err := s.store.Configs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	remaining := make([]*trillian.QuotaConfig, 0, len(configs))
+	for _, c := range configs {
+		if c.Name != req.Name {
+			remaining = append(remaining, c)
+		}
+	}
+	if len(remaining) == len(configs) {
+		return nil, status.Errorf(codes.NotFound, "config %v not found", req.Name)
+	}
+	if err := s.store.SetConfigs(ctx, remaining); err != nil {
+		return nil, err
+	}
+	return &empty.Empty{}, nil
+}
+
+// get returns the named config, with its current tokens.
+func (s *Server) get(ctx context.Context, name string) (*trillian.QuotaConfig, error) {
+	configs, err := s.store.Configs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c := find(configs, name)
+	if c == nil {
+		return nil, status.Errorf(codes.NotFound, "config %v not found", name)
+	}
+	if n, err := ParseName(name); err == nil && !n.IsDefault() {
+		spec := n.Spec()
+		tokens, err := s.qm.PeekTokens(ctx, []quota.Spec{spec})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to read current tokens: %v", err)
+		}
+		c.CurrentTokens = int64(tokens[spec])
+	}
+	return c, nil
+}
+
+// parseName is like ParseName, but returns InvalidArgument status errors.
+func parseName(name string) (Name, error) {
+	n, err := ParseName(name)
+	if err != nil {
+		return Name{}, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	return n, nil
+}
+
+// find returns the config of configs with the given name, or nil.
+func find(configs []*trillian.QuotaConfig, name string) *trillian.QuotaConfig {
+	for _, c := range configs {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
@@ -0,0 +1,264 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quotaadmin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/trillian"
+	"github.com/google/trillian/quota"
+	"github.com/google/trillian/quota/memqm"
+	"github.com/kylelemons/godebug/pretty"
+	"google.golang.org/genproto/protobuf/field_mask"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var treeWrite = quota.Spec{Group: quota.Tree, Kind: quota.Write, TreeID: 10}
+
+func newTestServer(t *testing.T) (*Server, *memqm.Manager) {
+	t.Helper()
+	m := newMemoryManager(t, &memqm.Config{
+		Global: memqm.Buckets{Write: &memqm.Bucket{Rate: 100, Burst: 1000}},
+		Tree:   memqm.Buckets{Write: &memqm.Bucket{Rate: 1, Burst: 10}},
+	})
+	return NewServer(m, NewBucketStore(m)), m
+}
+
+func TestServer_CreateQuotaConfig(t *testing.T) {
+	ctx := context.Background()
+	s, m := newTestServer(t)
+
+	config := &trillian.QuotaConfig{Name: "quotas/trees/10/write/config", MaxTokens: 5, ReplenishRate: 2, CurrentTokens: 123}
+	got, err := s.CreateQuotaConfig(ctx, &trillian.CreateQuotaConfigRequest{Config: config})
+	if err != nil {
+		t.Fatalf("CreateQuotaConfig(): %v", err)
+	}
+	want := &trillian.QuotaConfig{Name: config.Name, MaxTokens: 5, ReplenishRate: 2, CurrentTokens: 5}
+	if diff := pretty.Compare(got, want); diff != "" {
+		t.Errorf("CreateQuotaConfig() diff (-got +want):\n%v", diff)
+	}
+	if err := m.GetTokens(ctx, 6, []quota.Spec{treeWrite}); err == nil {
+		t.Error("GetTokens() over the created config's max_tokens returned nil err")
+	}
+
+	for _, test := range []struct {
+		desc     string
+		config   *trillian.QuotaConfig
+		wantCode codes.Code
+	}{
+		{desc: "noConfig", wantCode: codes.InvalidArgument},
+		{desc: "badName", config: &trillian.QuotaConfig{Name: "llamas"}, wantCode: codes.InvalidArgument},
+		{desc: "exists", config: config, wantCode: codes.AlreadyExists},
+		{
+			desc:     "invalid",
+			config:   &trillian.QuotaConfig{Name: "quotas/global/read/config", MaxTokens: 10},
+			wantCode: codes.InvalidArgument,
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := s.CreateQuotaConfig(ctx, &trillian.CreateQuotaConfigRequest{Config: test.config})
+			if got := status.Code(err); got != test.wantCode {
+				t.Errorf("CreateQuotaConfig() returned err = %v, want code %v", err, test.wantCode)
+			}
+		})
+	}
+}
+
+func TestServer_GetQuotaConfig(t *testing.T) {
+	ctx := context.Background()
+	s, m := newTestServer(t)
+	if err := m.GetTokens(ctx, 400, []quota.Spec{{Group: quota.Global, Kind: quota.Write}}); err != nil {
+		t.Fatalf("GetTokens(): %v", err)
+	}
+
+	for _, test := range []struct {
+		name     string
+		want     *trillian.QuotaConfig
+		wantCode codes.Code
+	}{
+		{
+			name: "quotas/global/write/config",
+			want: &trillian.QuotaConfig{Name: "quotas/global/write/config", MaxTokens: 1000, ReplenishRate: 100, CurrentTokens: 600},
+		},
+		{
+			// Default configs have no current tokens.
+			name: "quotas/trees/write/config",
+			want: &trillian.QuotaConfig{Name: "quotas/trees/write/config", MaxTokens: 10, ReplenishRate: 1},
+		},
+		{name: "quotas/global/read/config", wantCode: codes.NotFound},
+		{name: "quotas/trees/10/write/config", wantCode: codes.NotFound},
+		{name: "quotas/global/config", wantCode: codes.InvalidArgument},
+	} {
+		got, err := s.GetQuotaConfig(ctx, &trillian.GetQuotaConfigRequest{Name: test.name})
+		if gotCode := status.Code(err); gotCode != test.wantCode {
+			t.Errorf("GetQuotaConfig(%q) returned err = %v, want code %v", test.name, err, test.wantCode)
+			continue
+		}
+		if diff := pretty.Compare(got, test.want); err == nil && diff != "" {
+			t.Errorf("GetQuotaConfig(%q) diff (-got +want):\n%v", test.name, diff)
+		}
+	}
+}
+
+func TestServer_ListQuotaConfigs(t *testing.T) {
+	s, _ := newTestServer(t)
+	got, err := s.ListQuotaConfigs(context.Background(), &trillian.ListQuotaConfigsRequest{})
+	if err != nil {
+		t.Fatalf("ListQuotaConfigs(): %v", err)
+	}
+	want := &trillian.ListQuotaConfigsResponse{Configs: []*trillian.QuotaConfig{
+		{Name: "quotas/global/write/config", MaxTokens: 1000, ReplenishRate: 100},
+		{Name: "quotas/trees/write/config", MaxTokens: 10, ReplenishRate: 1},
+	}}
+	if diff := pretty.Compare(got, want); diff != "" {
+		t.Errorf("ListQuotaConfigs() diff (-got +want):\n%v", diff)
+	}
+}
+
+func TestServer_UpdateQuotaConfig(t *testing.T) {
+	ctx := context.Background()
+	s, m := newTestServer(t)
+	if _, err := s.CreateQuotaConfig(ctx, &trillian.CreateQuotaConfigRequest{
+		Config: &trillian.QuotaConfig{Name: "quotas/trees/10/write/config", MaxTokens: 5, ReplenishRate: 2},
+	}); err != nil {
+		t.Fatalf("CreateQuotaConfig(): %v", err)
+	}
+	if err := m.GetTokens(ctx, 5, []quota.Spec{treeWrite}); err != nil {
+		t.Fatalf("GetTokens(): %v", err)
+	}
+
+	got, err := s.UpdateQuotaConfig(ctx, &trillian.UpdateQuotaConfigRequest{
+		Config:     &trillian.QuotaConfig{Name: "quotas/trees/10/write/config", MaxTokens: 8, ReplenishRate: 100},
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"max_tokens"}},
+	})
+	if err != nil {
+		t.Fatalf("UpdateQuotaConfig(): %v", err)
+	}
+	want := &trillian.QuotaConfig{Name: "quotas/trees/10/write/config", MaxTokens: 8, ReplenishRate: 2}
+	if diff := pretty.Compare(got, want); diff != "" {
+		t.Errorf("UpdateQuotaConfig() diff (-got +want):\n%v", diff)
+	}
+
+	got, err = s.UpdateQuotaConfig(ctx, &trillian.UpdateQuotaConfigRequest{
+		Config:     &trillian.QuotaConfig{Name: "quotas/trees/10/write/config", ReplenishRate: 4},
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"replenish_rate"}},
+		ResetQuota: true,
+	})
+	if err != nil {
+		t.Fatalf("UpdateQuotaConfig(reset_quota): %v", err)
+	}
+	want = &trillian.QuotaConfig{Name: "quotas/trees/10/write/config", MaxTokens: 8, ReplenishRate: 4, CurrentTokens: 8}
+	if diff := pretty.Compare(got, want); diff != "" {
+		t.Errorf("UpdateQuotaConfig(reset_quota) diff (-got +want):\n%v", diff)
+	}
+
+	got, err = s.UpdateQuotaConfig(ctx, &trillian.UpdateQuotaConfigRequest{
+		Config:     &trillian.QuotaConfig{Name: "quotas/trees/10/write/config", Disabled: true},
+		UpdateMask: &field_mask.FieldMask{Paths: []string{"disabled"}},
+	})
+	if err != nil {
+		t.Fatalf("UpdateQuotaConfig(disabled): %v", err)
+	}
+	if got, want := got.CurrentTokens, int64(quota.MaxTokens); got != want {
+		t.Errorf("UpdateQuotaConfig(disabled).CurrentTokens = %v, want %v", got, want)
+	}
+
+	for _, test := range []struct {
+		desc     string
+		req      *trillian.UpdateQuotaConfigRequest
+		wantCode codes.Code
+	}{
+		{desc: "noConfig", req: &trillian.UpdateQuotaConfigRequest{}, wantCode: codes.InvalidArgument},
+		{
+			desc: "noMask",
+			req: &trillian.UpdateQuotaConfigRequest{
+				Config: &trillian.QuotaConfig{Name: "quotas/trees/write/config", MaxTokens: 1},
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc: "badPath",
+			req: &trillian.UpdateQuotaConfigRequest{
+				Config:     &trillian.QuotaConfig{Name: "quotas/trees/write/config", MaxTokens: 1},
+				UpdateMask: &field_mask.FieldMask{Paths: []string{"name"}},
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc: "invalidValue",
+			req: &trillian.UpdateQuotaConfigRequest{
+				Config:     &trillian.QuotaConfig{Name: "quotas/trees/write/config", MaxTokens: -1},
+				UpdateMask: &field_mask.FieldMask{Paths: []string{"max_tokens"}},
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc: "resetDefault",
+			req: &trillian.UpdateQuotaConfigRequest{
+				Config:     &trillian.QuotaConfig{Name: "quotas/trees/write/config", MaxTokens: 1},
+				UpdateMask: &field_mask.FieldMask{Paths: []string{"max_tokens"}},
+				ResetQuota: true,
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc: "notFound",
+			req: &trillian.UpdateQuotaConfigRequest{
+				Config:     &trillian.QuotaConfig{Name: "quotas/users/write/config", MaxTokens: 1},
+				UpdateMask: &field_mask.FieldMask{Paths: []string{"max_tokens"}},
+			},
+			wantCode: codes.NotFound,
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			_, err := s.UpdateQuotaConfig(ctx, test.req)
+			if got := status.Code(err); got != test.wantCode {
+				t.Errorf("UpdateQuotaConfig() returned err = %v, want code %v", err, test.wantCode)
+			}
+		})
+	}
+	if got, want := m.Config().Tree.Write.Burst, 10; got != want {
+		t.Errorf("Tree.Write.Burst = %v after failed updates, want %v", got, want)
+	}
+}
+
+func TestServer_DeleteQuotaConfig(t *testing.T) {
+	ctx := context.Background()
+	s, m := newTestServer(t)
+	if _, err := s.DeleteQuotaConfig(ctx, &trillian.DeleteQuotaConfigRequest{Name: "quotas/trees/write/config"}); err != nil {
+		t.Fatalf("DeleteQuotaConfig(): %v", err)
+	}
+	if m.Config().Tree.Write != nil {
+		t.Errorf("Tree.Write = %+v after delete, want nil", m.Config().Tree.Write)
+	}
+	if _, err := s.GetQuotaConfig(ctx, &trillian.GetQuotaConfigRequest{Name: "quotas/trees/write/config"}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetQuotaConfig() after delete returned err = %v, want code %v", err, codes.NotFound)
+	}
+
+	for _, test := range []struct {
+		name     string
+		wantCode codes.Code
+	}{
+		{name: "quotas/trees/write/config", wantCode: codes.NotFound},
+		{name: "llamas", wantCode: codes.InvalidArgument},
+	} {
+		_, err := s.DeleteQuotaConfig(ctx, &trillian.DeleteQuotaConfigRequest{Name: test.name})
+		if got := status.Code(err); got != test.wantCode {
+			t.Errorf("DeleteQuotaConfig(%q) returned err = %v, want code %v", test.name, err, test.wantCode)
+		}
+	}
+}
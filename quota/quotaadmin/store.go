@@ -0,0 +1,222 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quotaadmin
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/trillian"
+	"github.com/google/trillian/quota"
+	"github.com/google/trillian/quota/memqm"
+	"github.com/google/trillian/quota/mysqlqm"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Store holds the quota configs of a quota.Manager, in whichever form the
+// manager keeps them.
+type Store interface {
+	// Configs returns all the configs held by the store. Configs don't have
+	// current_tokens set.
+	Configs(ctx context.Context) ([]*trillian.QuotaConfig, error)
+
+	// SetConfigs replaces all the configs held by the store. Configs the
+	// quota manager can't enforce are rejected with a gRPC status error, with
+	// code InvalidArgument or FailedPrecondition.
+	SetConfigs(ctx context.Context, configs []*trillian.QuotaConfig) error
+}
+
+// NewStore returns the Store of qm, for the quota managers that have
+// configurable quotas: memqm and redisqm (or any memqm.Configurable), and
+// mysqlqm.
+//
+// The Store of a memqm.Persistent saves changes where the quota manager, and
+// any other sharing its quotas, reloads them from. The Stores of other
+// memqm.Configurables and of mysqlqm are read-only, as nothing would keep
+// their changes across restarts.
+func NewStore(qm quota.Manager) (Store, error) {
+	switch m := qm.(type) {
+	case memqm.Persistent:
+		return NewBucketStore(m), nil
+	case memqm.Configurable:
+		return NewReadOnlyStore(NewBucketStore(m)), nil
+	case *mysqlqm.QuotaManager:
+		return NewMySQLStore(m), nil
+	}
+	return nil, fmt.Errorf("quota manager %T has no configurable quotas", qm)
+}
+
+// bucketStore is a Store of the memqm.Config of a memqm.Configurable.
+type bucketStore struct {
+	m memqm.Configurable
+}
+
+// NewBucketStore returns a Store of the memqm.Config of m. Changes are saved
+// with SaveConfig if m is a memqm.Persistent, and are otherwise only held in
+// memory.
+//
+// Disabled configs are stored as unlimited buckets, which are only allowed in
+// the overrides of specific trees and users.
+func NewBucketStore(m memqm.Configurable) Store {
+	return &bucketStore{m: m}
+}
+
+// Configs implements Store.Configs.
+func (s *bucketStore) Configs(ctx context.Context) ([]*trillian.QuotaConfig, error) {
+	cfg := s.m.Config()
+	var configs []*trillian.QuotaConfig
+	add := func(n Name, b memqm.Buckets) {
+		for _, kind := range []quota.Kind{quota.Read, quota.Write} {
+			n.Kind = kind
+			switch bucket := b.Get(kind); {
+			case bucket == nil:
+				// Not configured.
+			case bucket.Unlimited:
+				// Unlimited buckets of defaults are the same as no bucket.
+				if !n.IsDefault() && n.Group != quota.Global {
+					configs = append(configs, &trillian.QuotaConfig{Name: n.String(), Disabled: true})
+				}
+			default:
+				configs = append(configs, &trillian.QuotaConfig{
+					Name:          n.String(),
+					MaxTokens:     int64(bucket.Burst),
+					ReplenishRate: bucket.Rate,
+				})
+			}
+		}
+	}
+	add(Name{Group: quota.Global}, cfg.Global)
+	add(Name{Group: quota.Tree}, cfg.Tree)
+	add(Name{Group: quota.User}, cfg.User)
+	for id, b := range cfg.Trees {
+		add(Name{Group: quota.Tree, TreeID: id}, b)
+	}
+	for user, b := range cfg.Users {
+		add(Name{Group: quota.User, User: user}, b)
+	}
+	sort.Slice(configs, func(i, j int) bool { return configs[i].Name < configs[j].Name })
+	return configs, nil
+}
+
+// SetConfigs implements Store.SetConfigs.
+func (s *bucketStore) SetConfigs(ctx context.Context, configs []*trillian.QuotaConfig) error {
+	cfg := &memqm.Config{}
+	for _, c := range configs {
+		n, err := ParseName(c.Name)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+		var bucket *memqm.Bucket
+		if c.Disabled {
+			if n.Group == quota.Global || n.IsDefault() {
+				return status.Errorf(codes.InvalidArgument, "%v: only tree and user configs can be disabled", c.Name)
+			}
+			bucket = &memqm.Bucket{Unlimited: true}
+		} else {
+			if c.MaxTokens <= 0 {
+				return status.Errorf(codes.InvalidArgument, "%v: invalid max_tokens: %v", c.Name, c.MaxTokens)
+			}
+			if c.ReplenishRate <= 0 {
+				return status.Errorf(codes.InvalidArgument, "%v: invalid replenish_rate: %v (>0 required)", c.Name, c.ReplenishRate)
+			}
+			bucket = &memqm.Bucket{Rate: c.ReplenishRate, Burst: int(c.MaxTokens)}
+		}
+
+		switch {
+		case n.Group == quota.Global:
+			cfg.Global.Set(n.Kind, bucket)
+		case n.IsDefault() && n.Group == quota.Tree:
+			cfg.Tree.Set(n.Kind, bucket)
+		case n.IsDefault():
+			cfg.User.Set(n.Kind, bucket)
+		case n.Group == quota.Tree:
+			if cfg.Trees == nil {
+				cfg.Trees = make(map[int64]memqm.Buckets)
+			}
+			b := cfg.Trees[n.TreeID]
+			b.Set(n.Kind, bucket)
+			cfg.Trees[n.TreeID] = b
+		default:
+			if cfg.Users == nil {
+				cfg.Users = make(map[string]memqm.Buckets)
+			}
+			b := cfg.Users[n.User]
+			b.Set(n.Kind, bucket)
+			cfg.Users[n.User] = b
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if p, ok := s.m.(memqm.Persistent); ok {
+		if err := p.SaveConfig(ctx, cfg); err != nil {
+			return status.Errorf(codes.Internal, "failed to save quota config: %v", err)
+		}
+		return nil
+	}
+	if err := s.m.SetConfig(cfg); err != nil {
+		return status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	return nil
+}
+
+// readOnlyStore is a Store which rejects all changes.
+type readOnlyStore struct {
+	Store
+}
+
+// NewReadOnlyStore returns a Store with the configs of s, which rejects all
+// changes with a FailedPrecondition error.
+func NewReadOnlyStore(s Store) Store {
+	return readOnlyStore{Store: s}
+}
+
+// SetConfigs implements Store.SetConfigs.
+func (s readOnlyStore) SetConfigs(ctx context.Context, configs []*trillian.QuotaConfig) error {
+	return status.Errorf(codes.FailedPrecondition, "quota configs are read-only, and must be changed in the configuration of the quota manager")
+}
+
+// mysqlStore is a read-only Store of the limit of Unsequenced rows of a
+// mysqlqm.QuotaManager.
+type mysqlStore struct {
+	m *mysqlqm.QuotaManager
+}
+
+// globalWrite is the name of the only config of mysqlqm.
+var globalWrite = Name{Group: quota.Global, Kind: quota.Write}.String()
+
+// NewMySQLStore returns a Store of the limit of Unsequenced rows of m, which
+// is the global/write config. Its tokens are replenished by sequencing, so its
+// replenish_rate is zero. The limit is set when m is created, so the Store
+// rejects all changes with a FailedPrecondition error.
+func NewMySQLStore(m *mysqlqm.QuotaManager) Store {
+	return &mysqlStore{m: m}
+}
+
+// Configs implements Store.Configs.
+func (s *mysqlStore) Configs(ctx context.Context) ([]*trillian.QuotaConfig, error) {
+	return []*trillian.QuotaConfig{{
+		Name:      globalWrite,
+		MaxTokens: int64(s.m.MaxUnsequencedRows),
+	}}, nil
+}
+
+// SetConfigs implements Store.SetConfigs.
+func (s *mysqlStore) SetConfigs(ctx context.Context, configs []*trillian.QuotaConfig) error {
+	return status.Errorf(codes.FailedPrecondition, "the MySQL quota limit is read-only, and must be changed in the configuration of the quota manager")
+}
@@ -0,0 +1,210 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quotaadmin
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian"
+	"github.com/google/trillian/quota"
+	"github.com/google/trillian/quota/memqm"
+	"github.com/google/trillian/quota/mysqlqm"
+	"github.com/google/trillian/util/clock"
+	"github.com/kylelemons/godebug/pretty"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newMemoryManager(t *testing.T, cfg *memqm.Config) *memqm.Manager {
+	t.Helper()
+	m, err := memqm.New(cfg, clock.NewFake(time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)))
+	if err != nil {
+		t.Fatalf("memqm.New(): %v", err)
+	}
+	return m
+}
+
+// configurable hides the memqm.Persistent methods of a quota manager.
+type configurable struct {
+	quota.Manager
+	memqm.Configurable
+}
+
+func TestNewStore(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "quotaadmin")
+	if err != nil {
+		t.Fatalf("TempDir(): %v", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "quota.json")
+	if err := memqm.SaveConfig(path, &memqm.Config{Global: memqm.Buckets{Write: &memqm.Bucket{Rate: 1, Burst: 1}}}); err != nil {
+		t.Fatalf("SaveConfig(): %v", err)
+	}
+	m, err := memqm.NewFromFile(path, clock.NewFake(time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)))
+	if err != nil {
+		t.Fatalf("NewFromFile(): %v", err)
+	}
+
+	// memqm saves changes to its config file.
+	s, err := NewStore(m)
+	if err != nil {
+		t.Fatalf("NewStore(memqm): %v", err)
+	}
+	configs, err := s.Configs(ctx)
+	if err != nil || len(configs) != 1 {
+		t.Fatalf("NewStore(memqm).Configs() = (%v, %v), want 1 config", configs, err)
+	}
+	configs[0].MaxTokens = 10
+	if err := s.SetConfigs(ctx, configs); err != nil {
+		t.Fatalf("NewStore(memqm).SetConfigs(): %v", err)
+	}
+	cfg, err := memqm.LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig(): %v", err)
+	}
+	if got, want := cfg.Global.Write.Burst, 10; got != want {
+		t.Errorf("saved burst = %v, want %v", got, want)
+	}
+
+	// Other Configurables are read-only.
+	s, err = NewStore(configurable{m, m})
+	if err != nil {
+		t.Fatalf("NewStore(Configurable): %v", err)
+	}
+	if err := s.SetConfigs(ctx, nil); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("NewStore(Configurable).SetConfigs() returned err = %v, want code %v", err, codes.FailedPrecondition)
+	}
+	if m.Config().Global.Write == nil {
+		t.Error("NewStore(Configurable).SetConfigs() changed the config")
+	}
+
+	if _, err := NewStore(&mysqlqm.QuotaManager{}); err != nil {
+		t.Errorf("NewStore(mysqlqm): %v", err)
+	}
+	if _, err := NewStore(quota.Noop()); err == nil {
+		t.Error("NewStore(noop) returned nil err")
+	}
+}
+
+func TestBucketStore_Configs(t *testing.T) {
+	m := newMemoryManager(t, &memqm.Config{
+		Global: memqm.Buckets{Write: &memqm.Bucket{Rate: 100, Burst: 1000}},
+		User:   memqm.Buckets{Write: &memqm.Bucket{Rate: 1, Burst: 5}},
+		Trees:  map[int64]memqm.Buckets{11: {Read: &memqm.Bucket{Rate: 0.5, Burst: 50}}, 12: {Write: &memqm.Bucket{Unlimited: true}}},
+		Users:  map[string]memqm.Buckets{"vip": {Write: &memqm.Bucket{Unlimited: true}}},
+	})
+	s := NewBucketStore(m)
+	got, err := s.Configs(context.Background())
+	if err != nil {
+		t.Fatalf("Configs(): %v", err)
+	}
+	want := []*trillian.QuotaConfig{
+		{Name: "quotas/global/write/config", MaxTokens: 1000, ReplenishRate: 100},
+		{Name: "quotas/trees/11/read/config", MaxTokens: 50, ReplenishRate: 0.5},
+		{Name: "quotas/trees/12/write/config", Disabled: true},
+		{Name: "quotas/users/vip/write/config", Disabled: true},
+		{Name: "quotas/users/write/config", MaxTokens: 5, ReplenishRate: 1},
+	}
+	if diff := pretty.Compare(got, want); diff != "" {
+		t.Errorf("Configs() diff (-got +want):\n%v", diff)
+	}
+
+	// Configs round trip.
+	cfg := m.Config()
+	if err := s.SetConfigs(context.Background(), got); err != nil {
+		t.Fatalf("SetConfigs(): %v", err)
+	}
+	if !reflect.DeepEqual(m.Config(), cfg) {
+		t.Errorf("SetConfigs(Configs()) changed config to %+v, want %+v", m.Config(), cfg)
+	}
+}
+
+func TestBucketStore_SetConfigs(t *testing.T) {
+	ctx := context.Background()
+	m := newMemoryManager(t, &memqm.Config{})
+	s := NewBucketStore(m)
+
+	configs := []*trillian.QuotaConfig{
+		{Name: "quotas/trees/read/config", MaxTokens: 20, ReplenishRate: 10},
+		{Name: "quotas/trees/write/config", MaxTokens: 10, ReplenishRate: 1},
+		{Name: "quotas/trees/11/write/config", MaxTokens: 50, ReplenishRate: 2},
+		{Name: "quotas/users/llama/read/config", Disabled: true},
+	}
+	if err := s.SetConfigs(ctx, configs); err != nil {
+		t.Fatalf("SetConfigs(): %v", err)
+	}
+	want := &memqm.Config{
+		Tree: memqm.Buckets{Read: &memqm.Bucket{Rate: 10, Burst: 20}, Write: &memqm.Bucket{Rate: 1, Burst: 10}},
+		// The read bucket of tree 11 is left unset, so it uses the default.
+		Trees: map[int64]memqm.Buckets{11: {Write: &memqm.Bucket{Rate: 2, Burst: 50}}},
+		Users: map[string]memqm.Buckets{"llama": {Read: &memqm.Bucket{Unlimited: true}}},
+	}
+	if diff := pretty.Compare(m.Config(), want); diff != "" {
+		t.Errorf("SetConfigs() diff (-got +want):\n%v", diff)
+	}
+
+	for _, test := range []struct {
+		desc   string
+		config *trillian.QuotaConfig
+	}{
+		{desc: "badName", config: &trillian.QuotaConfig{Name: "quotas/llamas/read/config", MaxTokens: 1, ReplenishRate: 1}},
+		{desc: "zeroMaxTokens", config: &trillian.QuotaConfig{Name: "quotas/global/read/config", ReplenishRate: 1}},
+		{desc: "zeroRate", config: &trillian.QuotaConfig{Name: "quotas/global/read/config", MaxTokens: 1}},
+		{desc: "disabledGlobal", config: &trillian.QuotaConfig{Name: "quotas/global/read/config", Disabled: true}},
+		{desc: "disabledDefault", config: &trillian.QuotaConfig{Name: "quotas/users/read/config", Disabled: true}},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			err := s.SetConfigs(ctx, append(configs, test.config))
+			if got, want := status.Code(err), codes.InvalidArgument; got != want {
+				t.Errorf("SetConfigs() returned err = %v, want code %v", err, want)
+			}
+			if diff := pretty.Compare(m.Config(), want); diff != "" {
+				t.Errorf("SetConfigs() changed config on error, diff (-got +want):\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestMySQLStore(t *testing.T) {
+	ctx := context.Background()
+	m := &mysqlqm.QuotaManager{MaxUnsequencedRows: 100}
+	s := NewMySQLStore(m)
+
+	got, err := s.Configs(ctx)
+	if err != nil {
+		t.Fatalf("Configs(): %v", err)
+	}
+	want := []*trillian.QuotaConfig{{Name: "quotas/global/write/config", MaxTokens: 100}}
+	if diff := pretty.Compare(got, want); diff != "" {
+		t.Errorf("Configs() diff (-got +want):\n%v", diff)
+	}
+
+	updated := proto.Clone(want[0]).(*trillian.QuotaConfig)
+	updated.MaxTokens = 200
+	if err := s.SetConfigs(ctx, []*trillian.QuotaConfig{updated}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("SetConfigs() returned err = %v, want code %v", err, codes.FailedPrecondition)
+	}
+	if got, want := m.MaxUnsequencedRows, 100; got != want {
+		t.Errorf("MaxUnsequencedRows = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,239 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisqm
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// maxIdleConns is the number of idle connections a Client keeps open.
+	maxIdleConns = 8
+
+	dialTimeout = 5 * time.Second
+)
+
+// Error is an error reply from a Redis server.
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+// Client is a minimal client of the Redis protocol (RESP), safe for
+// concurrent use. It keeps a small pool of connections to a single server.
+type Client struct {
+	addr     string
+	password string
+
+	// mu guards idle and closed.
+	mu     sync.Mutex
+	idle   []*conn
+	closed bool
+}
+
+// NewClient returns a Client for the server at addr. Connections are opened
+// as needed. If password is not empty, connections are authenticated with the
+// AUTH command.
+func NewClient(addr, password string) *Client {
+	return &Client{addr: addr, password: password}
+}
+
+// Close closes the idle connections of c. Connections in use are closed once
+// they're released.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	for _, cn := range c.idle {
+		cn.nc.Close()
+	}
+	c.idle = nil
+	return nil
+}
+
+// Do sends a command to the server and returns its reply. Replies are one of:
+// string (simple strings), int64 (integers), []byte (bulk strings),
+// []interface{} (arrays) or nil (null bulk strings or arrays). Error replies
+// are returned as an Error.
+func (c *Client) Do(ctx context.Context, args ...string) (interface{}, error) {
+	var reply interface{}
+	err := c.withConn(ctx, func(cn *conn) error {
+		var err error
+		reply, err = cn.do(args...)
+		return err
+	})
+	return reply, err
+}
+
+// withConn runs f with a connection of c. Connections that fail with an error
+// other than an Error reply are closed.
+func (c *Client) withConn(ctx context.Context, f func(*conn) error) error {
+	cn, err := c.get(ctx)
+	if err != nil {
+		return err
+	}
+	deadline, _ := ctx.Deadline()
+	if err := cn.nc.SetDeadline(deadline); err != nil {
+		cn.nc.Close()
+		return err
+	}
+	err = f(cn)
+	if _, ok := err.(Error); err != nil && !ok {
+		cn.nc.Close()
+		return err
+	}
+	c.put(cn)
+	return err
+}
+
+func (c *Client) get(ctx context.Context) (*conn, error) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil, errors.New("redisqm: client closed")
+	}
+	if n := len(c.idle); n > 0 {
+		cn := c.idle[n-1]
+		c.idle = c.idle[:n-1]
+		c.mu.Unlock()
+		return cn, nil
+	}
+	c.mu.Unlock()
+
+	d := net.Dialer{Timeout: dialTimeout}
+	nc, err := d.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return nil, err
+	}
+	cn := &conn{nc: nc, r: bufio.NewReader(nc), w: bufio.NewWriter(nc)}
+	if c.password != "" {
+		if deadline, ok := ctx.Deadline(); ok {
+			nc.SetDeadline(deadline)
+		}
+		if _, err := cn.do("AUTH", c.password); err != nil {
+			nc.Close()
+			return nil, fmt.Errorf("redisqm: AUTH failed: %v", err)
+		}
+	}
+	return cn, nil
+}
+
+func (c *Client) put(cn *conn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed || len(c.idle) >= maxIdleConns {
+		cn.nc.Close()
+		return
+	}
+	c.idle = append(c.idle, cn)
+}
+
+// conn is a connection to a Redis server.
+type conn struct {
+	nc net.Conn
+	r  *bufio.Reader
+	w  *bufio.Writer
+}
+
+// do sends a command and reads its reply.
+func (cn *conn) do(args ...string) (interface{}, error) {
+	if err := writeCommand(cn.w, args); err != nil {
+		return nil, err
+	}
+	if err := cn.w.Flush(); err != nil {
+		return nil, err
+	}
+	reply, err := ReadReply(cn.r)
+	if err != nil {
+		return nil, err
+	}
+	if e, ok := reply.(Error); ok {
+		return nil, e
+	}
+	return reply, nil
+}
+
+// writeCommand writes args as an array of bulk strings.
+func writeCommand(w *bufio.Writer, args []string) error {
+	fmt.Fprintf(w, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(w, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	// Errors are sticky in bufio.Writer, and returned by Flush.
+	return nil
+}
+
+// ReadReply reads a RESP value from r. Error replies are returned as an Error
+// value, rather than as an error.
+func ReadReply(r *bufio.Reader) (interface{}, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, errors.New("redisqm: empty reply")
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return Error(line[1:]), nil
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		b := make([]byte, n+2)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return nil, err
+		}
+		values := make([]interface{}, n)
+		for i := range values {
+			if values[i], err = ReadReply(r); err != nil {
+				return nil, err
+			}
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("redisqm: unexpected reply %q", line)
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return "", fmt.Errorf("redisqm: malformed line %q", line)
+	}
+	return line[:len(line)-2], nil
+}
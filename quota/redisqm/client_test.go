@@ -0,0 +1,118 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisqm_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/google/trillian/quota/redisqm"
+	"github.com/google/trillian/quota/redisqm/redistest"
+)
+
+func newTestServer(t *testing.T, password string) *redistest.Server {
+	t.Helper()
+	s, err := redistest.NewServer(password)
+	if err != nil {
+		t.Fatalf("NewServer(): %v", err)
+	}
+	return s
+}
+
+func TestClient_Do(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t, "")
+	defer s.Close()
+	c := redisqm.NewClient(s.Addr(), "")
+	defer c.Close()
+
+	for _, test := range []struct {
+		args    []string
+		want    interface{}
+		wantErr bool
+	}{
+		{args: []string{"PING"}, want: "PONG"},
+		{args: []string{"HSET", "k", "a", "1", "b", ""}, want: int64(2)},
+		{args: []string{"HMGET", "k", "a", "b", "c"}, want: []interface{}{[]byte("1"), []byte(""), nil}},
+		{args: []string{"DEL", "k", "missing"}, want: int64(1)},
+		{args: []string{"EVAL", "return 1", "0"}, wantErr: true},
+		{args: []string{"LLAMAS"}, wantErr: true},
+	} {
+		got, err := c.Do(ctx, test.args...)
+		if gotErr := err != nil; gotErr != test.wantErr {
+			t.Errorf("Do(%v) returned err = %v, wantErr %v", test.args, err, test.wantErr)
+			continue
+		}
+		if err != nil {
+			if _, ok := err.(redisqm.Error); !ok {
+				t.Errorf("Do(%v) returned err of type %T, want redisqm.Error", test.args, err)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Do(%v) = %#v, want %#v", test.args, got, test.want)
+		}
+	}
+}
+
+func TestClient_Auth(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t, "secret")
+	defer s.Close()
+
+	for _, test := range []struct {
+		desc     string
+		password string
+		wantErr  bool
+	}{
+		{desc: "ok", password: "secret"},
+		{desc: "noPassword", wantErr: true},
+		{desc: "wrongPassword", password: "llama", wantErr: true},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			c := redisqm.NewClient(s.Addr(), test.password)
+			defer c.Close()
+			_, err := c.Do(ctx, "PING")
+			if gotErr := err != nil; gotErr != test.wantErr {
+				t.Errorf("Do(PING) returned err = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestClient_Closed(t *testing.T) {
+	s := newTestServer(t, "")
+	defer s.Close()
+	c := redisqm.NewClient(s.Addr(), "")
+	c.Close()
+	if _, err := c.Do(context.Background(), "PING"); err == nil {
+		t.Error("Do() on closed client returned nil err")
+	}
+}
+
+func TestClient_ServerDown(t *testing.T) {
+	s := newTestServer(t, "")
+	c := redisqm.NewClient(s.Addr(), "")
+	defer c.Close()
+	ctx := context.Background()
+	if _, err := c.Do(ctx, "PING"); err != nil {
+		t.Fatalf("Do(): %v", err)
+	}
+	s.Close()
+	if _, err := c.Do(ctx, "PING"); err == nil {
+		t.Error("Do() with server down returned nil err")
+	}
+}
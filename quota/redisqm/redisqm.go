@@ -0,0 +1,319 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package redisqm contains a token bucket based quota.Manager implementation,
+// backed by a Redis-protocol server.
+//
+// Buckets are shared by all the servers using the same Redis instance, so a
+// fleet of log servers enforces a single set of limits. Buckets are configured
+// by a memqm.Config, which is shared through Redis too once it's saved with
+// SaveConfig.
+//
+// Each bucket is a Redis hash, updated by a Lua script run with EVAL. The
+// script refills buckets from the clock of the Redis server, and updates all
+// the buckets of a request in one atomic step, or none of them.
+package redisqm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/google/trillian/quota"
+	"github.com/google/trillian/quota/memqm"
+)
+
+const (
+	// keyPrefix is the prefix of the Redis keys of buckets.
+	keyPrefix = "trillian/quota/"
+
+	// configKey is the Redis key of the config saved by SaveConfig. It can't
+	// clash with the keys of buckets, which end with a quota kind.
+	configKey = keyPrefix + "config"
+)
+
+// UpdateScript is the Lua script that reads, refills and updates buckets.
+//
+// KEYS are the keys of the buckets. ARGV[1] is the operation: "get", "put" or
+// "peek". ARGV[2] is the number of tokens to get or put. ARGV[2i+1] and
+// ARGV[2i+2] are the rate and burst size of KEYS[i].
+//
+// Buckets are refilled from the time of the Redis server, and never moved back
+// in time. Missing buckets are full. A "get" updates no bucket unless all of
+// them have enough tokens. Updated buckets expire a second after they're full.
+//
+// The script returns an array of 1 if the buckets were updated (or peeked), or
+// 0 if not, followed by the tokens of each bucket, as strings.
+const UpdateScript = `
+redis.replicate_commands()
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+local op, n = ARGV[1], tonumber(ARGV[2])
+local ok = 1
+local tokens, last = {}, {}
+for i, key in ipairs(KEYS) do
+  local rate, burst = tonumber(ARGV[2*i+1]), tonumber(ARGV[2*i+2])
+  local b = redis.call("HMGET", key, "tokens", "last")
+  tokens[i], last[i] = burst, now
+  if b[1] and b[2] then
+    tokens[i], last[i] = tonumber(b[1]), tonumber(b[2])
+    if now > last[i] then
+      tokens[i] = tokens[i] + (now - last[i]) / 1000000 * rate
+      last[i] = now
+    end
+    tokens[i] = math.min(tokens[i], burst)
+  end
+  if op == "get" and tokens[i] < n then
+    ok = 0
+  end
+end
+if op ~= "peek" and ok == 1 then
+  for i, key in ipairs(KEYS) do
+    local rate, burst = tonumber(ARGV[2*i+1]), tonumber(ARGV[2*i+2])
+    if op == "get" then
+      tokens[i] = tokens[i] - n
+    else
+      tokens[i] = math.min(tokens[i] + n, burst)
+    end
+    redis.call("HSET", key, "tokens", string.format("%.17g", tokens[i]), "last", string.format("%d", last[i]))
+    redis.call("PEXPIRE", key, string.format("%d", math.ceil((burst - tokens[i]) / rate * 1000) + 1000))
+  end
+end
+local reply = {ok}
+for i = 1, #tokens do
+  reply[i + 1] = string.format("%.17g", tokens[i])
+end
+return reply
+`
+
+// Manager is a quota.Manager that holds a token bucket per spec in Redis.
+// Buckets are refilled at a constant rate, up to their burst size.
+type Manager struct {
+	client *Client
+
+	// mu guards cfg.
+	mu  sync.RWMutex
+	cfg *memqm.Config
+}
+
+// New returns a Manager enforcing cfg on the buckets held by client.
+func New(client *Client, cfg *memqm.Config) (*Manager, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &Manager{client: client, cfg: cfg}, nil
+}
+
+// Config implements memqm.Configurable.
+func (m *Manager) Config() *memqm.Config {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.cfg
+}
+
+// SetConfig implements memqm.Configurable. Buckets keep their current tokens,
+// up to their new burst size. All the servers sharing buckets should use the
+// same config, see SaveConfig.
+func (m *Manager) SetConfig(cfg *memqm.Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cfg = cfg
+	return nil
+}
+
+// SaveConfig implements memqm.Persistent. cfg is saved to Redis, where all the
+// servers sharing it reload it from with ReloadConfig. Changes saved through
+// different servers at the same time overwrite each other.
+func (m *Manager) SaveConfig(ctx context.Context, cfg *memqm.Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	b, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	if _, err := m.client.Do(ctx, "SET", configKey, string(b)); err != nil {
+		return fmt.Errorf("failed to save quota config: %v", err)
+	}
+	return m.SetConfig(cfg)
+}
+
+// ReloadConfig makes the config saved to Redis by SaveConfig the current config
+// of m. The current config is kept if none was saved.
+func (m *Manager) ReloadConfig(ctx context.Context) error {
+	reply, err := m.client.Do(ctx, "GET", configKey)
+	if err != nil {
+		return fmt.Errorf("failed to load quota config: %v", err)
+	}
+	if reply == nil {
+		return nil
+	}
+	b, ok := reply.([]byte)
+	if !ok {
+		return fmt.Errorf("unexpected quota config: %v", reply)
+	}
+	cfg, err := memqm.ParseConfig(b)
+	if err != nil {
+		return fmt.Errorf("quota config %q: %v", configKey, err)
+	}
+	return m.SetConfig(cfg)
+}
+
+// ReloadPeriodically runs ReloadConfig on m every interval, until ctx is done.
+// Configs that fail to load are logged, and the current config is kept.
+// Reloads are run from the time ReloadPeriodically returns, by a new goroutine.
+func ReloadPeriodically(ctx context.Context, m *Manager, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := m.ReloadConfig(ctx); err != nil {
+					glog.Errorf("Failed to reload quota config: %v", err)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// GetTokens implements quota.Manager.GetTokens. Tokens are only taken if all
+// specs have enough of them.
+func (m *Manager) GetTokens(ctx context.Context, numTokens int, specs []quota.Spec) error {
+	if err := validateNumTokens(numTokens); err != nil {
+		return err
+	}
+	limited, tokens, ok, err := m.run(ctx, "get", numTokens, specs)
+	if err != nil || ok {
+		return err
+	}
+	for i, spec := range limited {
+		if tokens[i] < float64(numTokens) {
+			return fmt.Errorf("insufficient tokens on %v (%v vs %v)", spec, int(tokens[i]), numTokens)
+		}
+	}
+	return fmt.Errorf("quota script didn't update %v", limited)
+}
+
+// PeekTokens implements quota.Manager.PeekTokens.
+func (m *Manager) PeekTokens(ctx context.Context, specs []quota.Spec) (map[quota.Spec]int, error) {
+	limited, tokens, _, err := m.run(ctx, "peek", 0, specs)
+	if err != nil {
+		return nil, err
+	}
+	peeked := make(map[quota.Spec]int)
+	for _, spec := range specs {
+		peeked[spec] = quota.MaxTokens
+	}
+	for i, spec := range limited {
+		peeked[spec] = int(tokens[i])
+	}
+	return peeked, nil
+}
+
+// PutTokens implements quota.Manager.PutTokens. Tokens returned to a bucket
+// can't make it exceed its burst size.
+func (m *Manager) PutTokens(ctx context.Context, numTokens int, specs []quota.Spec) error {
+	if err := validateNumTokens(numTokens); err != nil {
+		return err
+	}
+	_, _, _, err := m.run(ctx, "put", numTokens, specs)
+	return err
+}
+
+// ResetQuota implements quota.Manager.ResetQuota, refilling the buckets of
+// specs.
+func (m *Manager) ResetQuota(ctx context.Context, specs []quota.Spec) error {
+	if len(specs) == 0 {
+		return nil
+	}
+	args := []string{"DEL"}
+	for _, spec := range specs {
+		args = append(args, key(spec))
+	}
+	_, err := m.client.Do(ctx, args...)
+	return err
+}
+
+// run runs UpdateScript with op on the buckets of specs. Unlimited and
+// repeated specs are skipped. It returns the limited specs, their tokens, and
+// whether the buckets were updated.
+func (m *Manager) run(ctx context.Context, op string, numTokens int, specs []quota.Spec) ([]quota.Spec, []float64, bool, error) {
+	cfg := m.Config()
+	var limited []quota.Spec
+	var keys, buckets []string
+	seen := make(map[quota.Spec]bool)
+	for _, spec := range specs {
+		cb := cfg.Bucket(spec)
+		if cb == nil || seen[spec] {
+			continue
+		}
+		seen[spec] = true
+		limited = append(limited, spec)
+		keys = append(keys, key(spec))
+		buckets = append(buckets, strconv.FormatFloat(cb.Rate, 'g', -1, 64), strconv.Itoa(cb.Burst))
+	}
+	if len(limited) == 0 {
+		return nil, nil, true, nil
+	}
+
+	args := append([]string{"EVAL", UpdateScript, strconv.Itoa(len(keys))}, keys...)
+	args = append(args, op, strconv.Itoa(numTokens))
+	args = append(args, buckets...)
+	reply, err := m.client.Do(ctx, args...)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != len(limited)+1 {
+		return nil, nil, false, fmt.Errorf("unexpected quota script reply: %v", reply)
+	}
+	updated, ok := values[0].(int64)
+	if !ok {
+		return nil, nil, false, fmt.Errorf("unexpected quota script status: %v", values[0])
+	}
+	tokens := make([]float64, 0, len(limited))
+	for i, v := range values[1:] {
+		b, ok := v.([]byte)
+		if !ok {
+			return nil, nil, false, fmt.Errorf("unexpected tokens of %v: %v", limited[i], v)
+		}
+		t, err := strconv.ParseFloat(string(b), 64)
+		if err != nil {
+			return nil, nil, false, fmt.Errorf("bad tokens of %v: %v", limited[i], err)
+		}
+		tokens = append(tokens, t)
+	}
+	return limited, tokens, updated == 1, nil
+}
+
+func key(spec quota.Spec) string {
+	return keyPrefix + spec.Name()
+}
+
+func validateNumTokens(numTokens int) error {
+	if numTokens <= 0 {
+		return fmt.Errorf("invalid numTokens: %v (>0 required)", numTokens)
+	}
+	return nil
+}
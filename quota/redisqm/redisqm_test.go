@@ -0,0 +1,445 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisqm_test
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/trillian/quota"
+	"github.com/google/trillian/quota/memqm"
+	"github.com/google/trillian/quota/redisqm"
+	"github.com/google/trillian/util/clock"
+)
+
+var (
+	fakeTime = time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	globalRead  = quota.Spec{Group: quota.Global, Kind: quota.Read}
+	globalWrite = quota.Spec{Group: quota.Global, Kind: quota.Write}
+	treeRead    = quota.Spec{Group: quota.Tree, Kind: quota.Read, TreeID: 10}
+	treeWrite   = quota.Spec{Group: quota.Tree, Kind: quota.Write, TreeID: 10}
+	tree11Read  = quota.Spec{Group: quota.Tree, Kind: quota.Read, TreeID: 11}
+	tree11Write = quota.Spec{Group: quota.Tree, Kind: quota.Write, TreeID: 11}
+	userWrite   = quota.Spec{Group: quota.User, Kind: quota.Write, User: "llama"}
+	vipWrite    = quota.Spec{Group: quota.User, Kind: quota.Write, User: "vip"}
+)
+
+func testConfig() *memqm.Config {
+	return &memqm.Config{
+		Global: memqm.Buckets{Write: &memqm.Bucket{Rate: 100, Burst: 1000}},
+		Tree:   memqm.Buckets{Read: &memqm.Bucket{Rate: 10, Burst: 20}, Write: &memqm.Bucket{Rate: 1, Burst: 10}},
+		User:   memqm.Buckets{Write: &memqm.Bucket{Rate: 1, Burst: 5}},
+		Trees:  map[int64]memqm.Buckets{11: {Write: &memqm.Bucket{Rate: 2, Burst: 50}}},
+		Users:  map[string]memqm.Buckets{"vip": {Write: &memqm.Bucket{Unlimited: true}}},
+	}
+}
+
+// newTestManager returns a Manager backed by a new fake server, the clock of
+// the server, and a function to clean up both.
+func newTestManager(t *testing.T) (*redisqm.Manager, *clock.FakeTimeSource, func()) {
+	t.Helper()
+	s := newTestServer(t, "")
+	ts := clock.NewFake(fakeTime)
+	s.SetTimeSource(ts)
+	c := redisqm.NewClient(s.Addr(), "")
+	m, err := redisqm.New(c, testConfig())
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	return m, ts, func() {
+		c.Close()
+		s.Close()
+	}
+}
+
+func peek(t *testing.T, m *redisqm.Manager, spec quota.Spec) int {
+	t.Helper()
+	tokens, err := m.PeekTokens(context.Background(), []quota.Spec{spec})
+	if err != nil {
+		t.Fatalf("PeekTokens(): %v", err)
+	}
+	return tokens[spec]
+}
+
+func TestManager_PeekTokens(t *testing.T) {
+	m, _, cleanup := newTestManager(t)
+	defer cleanup()
+	for _, test := range []struct {
+		spec quota.Spec
+		want int
+	}{
+		{spec: globalRead, want: quota.MaxTokens},
+		{spec: globalWrite, want: 1000},
+		{spec: treeRead, want: 20},
+		{spec: treeWrite, want: 10},
+		{spec: tree11Read, want: 20}, // Not overridden
+		{spec: tree11Write, want: 50},
+		{spec: userWrite, want: 5},
+		{spec: vipWrite, want: quota.MaxTokens},
+	} {
+		if got := peek(t, m, test.spec); got != test.want {
+			t.Errorf("PeekTokens(%v) = %v, want %v", test.spec, got, test.want)
+		}
+	}
+}
+
+func TestManager_GetTokensRefill(t *testing.T) {
+	ctx := context.Background()
+	m, ts, cleanup := newTestManager(t)
+	defer cleanup()
+	specs := []quota.Spec{userWrite, treeWrite, globalWrite}
+
+	if err := m.GetTokens(ctx, 5, specs); err != nil {
+		t.Fatalf("GetTokens(5): %v", err)
+	}
+	// The user bucket is now empty, so no tokens are taken from any spec.
+	if err := m.GetTokens(ctx, 1, specs); err == nil {
+		t.Fatal("GetTokens(1) on an empty bucket returned nil err")
+	}
+	if got, want := peek(t, m, treeWrite), 5; got != want {
+		t.Errorf("PeekTokens(%v) = %v, want %v", treeWrite, got, want)
+	}
+
+	// The user bucket refills at 1 token per second.
+	ts.Set(fakeTime.Add(2500 * time.Millisecond))
+	if got, want := peek(t, m, userWrite), 2; got != want {
+		t.Errorf("PeekTokens(%v) = %v, want %v", userWrite, got, want)
+	}
+	if err := m.GetTokens(ctx, 2, specs); err != nil {
+		t.Errorf("GetTokens(2) after refill: %v", err)
+	}
+	if err := m.GetTokens(ctx, 1, specs); err == nil {
+		t.Error("GetTokens(1) after spending the refill returned nil err")
+	}
+
+	// Buckets don't refill past their burst size.
+	ts.Set(fakeTime.Add(time.Hour))
+	if got, want := peek(t, m, userWrite), 5; got != want {
+		t.Errorf("PeekTokens(%v) = %v, want %v", userWrite, got, want)
+	}
+	if got, want := peek(t, m, globalWrite), 1000; got != want {
+		t.Errorf("PeekTokens(%v) = %v, want %v", globalWrite, got, want)
+	}
+}
+
+func TestManager_GetTokensErrors(t *testing.T) {
+	ctx := context.Background()
+	m, _, cleanup := newTestManager(t)
+	defer cleanup()
+	for _, numTokens := range []int{-1, 0} {
+		if err := m.GetTokens(ctx, numTokens, []quota.Spec{treeWrite}); err == nil {
+			t.Errorf("GetTokens(%v) returned nil err", numTokens)
+		}
+	}
+	if err := m.GetTokens(ctx, 11, []quota.Spec{treeWrite}); err == nil {
+		t.Error("GetTokens() over burst size returned nil err")
+	}
+	if err := m.GetTokens(ctx, quota.MaxTokens, []quota.Spec{globalRead, vipWrite}); err != nil {
+		t.Errorf("GetTokens() on unlimited specs: %v", err)
+	}
+	// Repeated specs are only charged once.
+	if err := m.GetTokens(ctx, 6, []quota.Spec{treeWrite, treeWrite}); err != nil {
+		t.Errorf("GetTokens() on repeated specs: %v", err)
+	}
+	if got, want := peek(t, m, treeWrite), 4; got != want {
+		t.Errorf("PeekTokens(%v) = %v, want %v", treeWrite, got, want)
+	}
+}
+
+func TestManager_PutTokens(t *testing.T) {
+	ctx := context.Background()
+	m, _, cleanup := newTestManager(t)
+	defer cleanup()
+	if err := m.GetTokens(ctx, 8, []quota.Spec{treeWrite}); err != nil {
+		t.Fatalf("GetTokens(): %v", err)
+	}
+	if err := m.PutTokens(ctx, 3, []quota.Spec{treeWrite, globalRead}); err != nil {
+		t.Fatalf("PutTokens(): %v", err)
+	}
+	if got, want := peek(t, m, treeWrite), 5; got != want {
+		t.Errorf("PeekTokens(%v) = %v, want %v", treeWrite, got, want)
+	}
+	if err := m.PutTokens(ctx, 100, []quota.Spec{treeWrite}); err != nil {
+		t.Fatalf("PutTokens(): %v", err)
+	}
+	if got, want := peek(t, m, treeWrite), 10; got != want {
+		t.Errorf("PeekTokens(%v) = %v, want %v", treeWrite, got, want)
+	}
+	if err := m.PutTokens(ctx, 0, []quota.Spec{treeWrite}); err == nil {
+		t.Error("PutTokens(0) returned nil err")
+	}
+}
+
+func TestManager_ResetQuota(t *testing.T) {
+	ctx := context.Background()
+	m, _, cleanup := newTestManager(t)
+	defer cleanup()
+	if err := m.GetTokens(ctx, 10, []quota.Spec{treeWrite, tree11Write}); err != nil {
+		t.Fatalf("GetTokens(): %v", err)
+	}
+	if err := m.ResetQuota(ctx, []quota.Spec{treeWrite, globalRead}); err != nil {
+		t.Fatalf("ResetQuota(): %v", err)
+	}
+	if got, want := peek(t, m, treeWrite), 10; got != want {
+		t.Errorf("PeekTokens(%v) = %v, want %v", treeWrite, got, want)
+	}
+	if got, want := peek(t, m, tree11Write), 40; got != want {
+		t.Errorf("PeekTokens(%v) = %v, want %v", tree11Write, got, want)
+	}
+}
+
+func TestManager_SetConfig(t *testing.T) {
+	ctx := context.Background()
+	m, ts, cleanup := newTestManager(t)
+	defer cleanup()
+	if err := m.GetTokens(ctx, 8, []quota.Spec{treeWrite, tree11Write}); err != nil {
+		t.Fatalf("GetTokens(): %v", err)
+	}
+
+	cfg := testConfig()
+	cfg.Tree.Write = &memqm.Bucket{Rate: 5, Burst: 6}
+	cfg.Trees = nil
+	if err := m.SetConfig(cfg); err != nil {
+		t.Fatalf("SetConfig(): %v", err)
+	}
+	// Tree 11 is capped to the new burst size, and both trees refill at the
+	// new rate.
+	if got, want := peek(t, m, tree11Write), 6; got != want {
+		t.Errorf("PeekTokens(%v) = %v, want %v", tree11Write, got, want)
+	}
+	ts.Set(fakeTime.Add(500 * time.Millisecond))
+	if got, want := peek(t, m, treeWrite), 4; got != want {
+		t.Errorf("PeekTokens(%v) after refill = %v, want %v", treeWrite, got, want)
+	}
+
+	if err := m.SetConfig(&memqm.Config{Tree: memqm.Buckets{Write: &memqm.Bucket{Rate: -1, Burst: 1}}}); err == nil {
+		t.Error("SetConfig() with invalid config returned nil err")
+	}
+	if got, want := peek(t, m, treeWrite), 4; got != want {
+		t.Errorf("PeekTokens(%v) after invalid config = %v, want %v", treeWrite, got, want)
+	}
+}
+
+func TestManager_SaveConfig(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t, "")
+	defer s.Close()
+	var managers []*redisqm.Manager
+	for i := 0; i < 2; i++ {
+		c := redisqm.NewClient(s.Addr(), "")
+		defer c.Close()
+		m, err := redisqm.New(c, testConfig())
+		if err != nil {
+			t.Fatalf("New(): %v", err)
+		}
+		managers = append(managers, m)
+	}
+
+	// Nothing was saved yet, so the current config is kept.
+	if err := managers[1].ReloadConfig(ctx); err != nil {
+		t.Fatalf("ReloadConfig(): %v", err)
+	}
+	if got, want := peek(t, managers[1], treeWrite), 10; got != want {
+		t.Errorf("PeekTokens(%v) = %v, want %v", treeWrite, got, want)
+	}
+
+	cfg := testConfig()
+	cfg.Tree.Write = &memqm.Bucket{Rate: 1, Burst: 3}
+	if err := managers[0].SaveConfig(ctx, cfg); err != nil {
+		t.Fatalf("SaveConfig(): %v", err)
+	}
+	if got, want := peek(t, managers[0], treeWrite), 3; got != want {
+		t.Errorf("PeekTokens(%v) after save = %v, want %v", treeWrite, got, want)
+	}
+	if err := managers[1].ReloadConfig(ctx); err != nil {
+		t.Fatalf("ReloadConfig(): %v", err)
+	}
+	if got, want := managers[1].Config(), cfg; !reflect.DeepEqual(got, want) {
+		t.Errorf("Config() after reload = %+v, want %+v", got, want)
+	}
+
+	if err := managers[0].SaveConfig(ctx, &memqm.Config{Tree: memqm.Buckets{Write: &memqm.Bucket{Rate: 1}}}); err == nil {
+		t.Error("SaveConfig() of invalid config returned nil err")
+	}
+	c := redisqm.NewClient(s.Addr(), "")
+	defer c.Close()
+	if _, err := c.Do(ctx, "SET", "trillian/quota/config", "{"); err != nil {
+		t.Fatalf("Do(SET): %v", err)
+	}
+	if err := managers[1].ReloadConfig(ctx); err == nil {
+		t.Error("ReloadConfig() of bad config returned nil err")
+	}
+	if got, want := managers[1].Config(), cfg; !reflect.DeepEqual(got, want) {
+		t.Errorf("Config() after failed reload = %+v, want %+v", got, want)
+	}
+}
+
+func TestReloadPeriodically(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := newTestServer(t, "")
+	defer s.Close()
+	var managers []*redisqm.Manager
+	for i := 0; i < 2; i++ {
+		c := redisqm.NewClient(s.Addr(), "")
+		defer c.Close()
+		m, err := redisqm.New(c, testConfig())
+		if err != nil {
+			t.Fatalf("New(): %v", err)
+		}
+		managers = append(managers, m)
+	}
+	redisqm.ReloadPeriodically(ctx, managers[1], time.Millisecond)
+
+	cfg := testConfig()
+	cfg.Tree.Write = &memqm.Bucket{Rate: 1, Burst: 3}
+	if err := managers[0].SaveConfig(ctx, cfg); err != nil {
+		t.Fatalf("SaveConfig(): %v", err)
+	}
+	for deadline := time.Now().Add(10 * time.Second); !reflect.DeepEqual(managers[1].Config(), cfg); time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Config() = %+v, want %+v", managers[1].Config(), cfg)
+		}
+	}
+}
+
+func TestManager_SharedBuckets(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t, "")
+	defer s.Close()
+	ts := clock.NewFake(fakeTime)
+	s.SetTimeSource(ts)
+
+	// Each manager stands for a server of the fleet.
+	var managers []*redisqm.Manager
+	for i := 0; i < 2; i++ {
+		c := redisqm.NewClient(s.Addr(), "")
+		defer c.Close()
+		m, err := redisqm.New(c, testConfig())
+		if err != nil {
+			t.Fatalf("New(): %v", err)
+		}
+		managers = append(managers, m)
+	}
+
+	if err := managers[0].GetTokens(ctx, 3, []quota.Spec{userWrite}); err != nil {
+		t.Fatalf("GetTokens(): %v", err)
+	}
+	if got, want := peek(t, managers[1], userWrite), 2; got != want {
+		t.Errorf("PeekTokens(%v) = %v, want %v", userWrite, got, want)
+	}
+	if err := managers[1].GetTokens(ctx, 3, []quota.Spec{userWrite}); err == nil {
+		t.Error("GetTokens() over the shared quota returned nil err")
+	}
+	if err := managers[1].ResetQuota(ctx, []quota.Spec{userWrite}); err != nil {
+		t.Fatalf("ResetQuota(): %v", err)
+	}
+	if got, want := peek(t, managers[0], userWrite), 5; got != want {
+		t.Errorf("PeekTokens(%v) after reset = %v, want %v", userWrite, got, want)
+	}
+
+	// Buckets are refilled from the clock of the server, so all the managers
+	// see the same refill.
+	if err := managers[0].GetTokens(ctx, 5, []quota.Spec{userWrite}); err != nil {
+		t.Fatalf("GetTokens(): %v", err)
+	}
+	ts.Set(fakeTime.Add(2 * time.Second))
+	for i, m := range managers {
+		if got, want := peek(t, m, userWrite), 2; got != want {
+			t.Errorf("managers[%v].PeekTokens(%v) after refill = %v, want %v", i, userWrite, got, want)
+		}
+	}
+}
+
+func TestManager_ConcurrentGetTokens(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t, "")
+	defer s.Close()
+	ts := clock.NewFake(fakeTime)
+	s.SetTimeSource(ts)
+
+	const workers, requests = 4, 10
+	var mu sync.Mutex
+	granted := 0
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		c := redisqm.NewClient(s.Addr(), "")
+		defer c.Close()
+		m, err := redisqm.New(c, testConfig())
+		if err != nil {
+			t.Fatalf("New(): %v", err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < requests; j++ {
+				if err := m.GetTokens(ctx, 1, []quota.Spec{treeWrite, globalWrite}); err == nil {
+					mu.Lock()
+					granted++
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The clock is frozen, so no more than the burst size of the tree bucket
+	// is granted, and the global bucket is charged for each grant.
+	if granted > 10 {
+		t.Errorf("%v requests granted, want <= 10", granted)
+	}
+	c := redisqm.NewClient(s.Addr(), "")
+	defer c.Close()
+	m, err := redisqm.New(c, testConfig())
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	tokens, err := m.PeekTokens(ctx, []quota.Spec{treeWrite, globalWrite})
+	if err != nil {
+		t.Fatalf("PeekTokens(): %v", err)
+	}
+	if got, want := tokens[treeWrite], 10-granted; got != want {
+		t.Errorf("PeekTokens(%v) = %v, want %v", treeWrite, got, want)
+	}
+	if got, want := tokens[globalWrite], 1000-granted; got != want {
+		t.Errorf("PeekTokens(%v) = %v, want %v", globalWrite, got, want)
+	}
+}
+
+func TestManager_ServerDown(t *testing.T) {
+	ctx := context.Background()
+	s := newTestServer(t, "")
+	c := redisqm.NewClient(s.Addr(), "")
+	defer c.Close()
+	m, err := redisqm.New(c, testConfig())
+	if err != nil {
+		t.Fatalf("New(): %v", err)
+	}
+	s.Close()
+	if err := m.GetTokens(ctx, 1, []quota.Spec{treeWrite}); err == nil {
+		t.Error("GetTokens() with server down returned nil err")
+	}
+	if _, err := m.PeekTokens(ctx, []quota.Spec{treeWrite}); err == nil {
+		t.Error("PeekTokens() with server down returned nil err")
+	}
+	// Unlimited specs don't need the server.
+	if err := m.GetTokens(ctx, 1, []quota.Spec{globalRead}); err != nil {
+		t.Errorf("GetTokens() of unlimited spec with server down: %v", err)
+	}
+}
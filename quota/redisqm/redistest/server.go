@@ -0,0 +1,438 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package redistest contains an in-process fake of a Redis server, for tests.
+package redistest
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/trillian/quota/redisqm"
+	"github.com/google/trillian/util/clock"
+)
+
+// Server is a fake Redis server, supporting the subset of commands used by
+// redisqm: PING, AUTH, TIME, GET, SET, HMGET, HSET, DEL, PEXPIRE and EVAL. EVAL only
+// runs redisqm.UpdateScript, which is emulated in Go.
+type Server struct {
+	lis      net.Listener
+	password string
+	wg       sync.WaitGroup
+
+	// mu guards the fields below.
+	mu         sync.Mutex
+	timeSource clock.TimeSource
+	keys       map[string]*entry
+	conns      map[net.Conn]bool
+	closed     bool
+}
+
+// entry is the value of a key: a hash, or a string if hash is nil.
+type entry struct {
+	hash  map[string]string
+	value string
+	// expiry is the time the entry expires, or zero if it doesn't.
+	expiry time.Time
+}
+
+// NewServer starts a Server listening on a local port. If password is not
+// empty, clients must authenticate with AUTH before running other commands.
+func NewServer(password string) (*Server, error) {
+	lis, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		lis:        lis,
+		password:   password,
+		timeSource: clock.System,
+		keys:       make(map[string]*entry),
+		conns:      make(map[net.Conn]bool),
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// SetTimeSource sets the clock of s, which drives TIME and the expiry of keys.
+func (s *Server) SetTimeSource(ts clock.TimeSource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.timeSource = ts
+}
+
+// Addr returns the address s is listening on.
+func (s *Server) Addr() string {
+	return s.lis.Addr().String()
+}
+
+// Len returns the number of unexpired keys held by s.
+func (s *Server) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for k := range s.keys {
+		if s.lookup(k) != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// Close stops s, and closes all its connections.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	for c := range s.conns {
+		c.Close()
+	}
+	s.mu.Unlock()
+	err := s.lis.Close()
+	s.wg.Wait()
+	return err
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		c, err := s.lis.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			c.Close()
+			return
+		}
+		s.conns[c] = true
+		s.mu.Unlock()
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(c)
+			s.mu.Lock()
+			delete(s.conns, c)
+			s.mu.Unlock()
+			c.Close()
+		}()
+	}
+}
+
+func (s *Server) handle(c net.Conn) {
+	r := bufio.NewReader(c)
+	w := bufio.NewWriter(c)
+	authenticated := s.password == ""
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+		var reply interface{}
+		switch {
+		case len(args) == 0:
+			reply = redisqm.Error("ERR empty command")
+		case strings.ToUpper(args[0]) == "AUTH":
+			reply = s.auth(args)
+			authenticated = authenticated || reply == "OK"
+		case !authenticated:
+			reply = redisqm.Error("NOAUTH Authentication required.")
+		default:
+			s.mu.Lock()
+			reply = s.run(strings.ToUpper(args[0]), args)
+			s.mu.Unlock()
+		}
+		writeReply(w, reply)
+		if err := w.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) auth(args []string) interface{} {
+	if len(args) != 2 {
+		return wrongArgs("AUTH")
+	}
+	if args[1] != s.password {
+		return redisqm.Error("WRONGPASS invalid password")
+	}
+	return "OK"
+}
+
+// run runs a command, and returns its reply. s.mu must be held, which makes
+// each command, including EVAL, atomic.
+func (s *Server) run(cmd string, args []string) interface{} {
+	switch cmd {
+	case "PING":
+		return "PONG"
+	case "TIME":
+		now := s.timeSource.Now()
+		return []interface{}{
+			[]byte(strconv.FormatInt(now.Unix(), 10)),
+			[]byte(strconv.Itoa(now.Nanosecond() / int(time.Microsecond))),
+		}
+	case "GET":
+		if len(args) != 2 {
+			return wrongArgs(cmd)
+		}
+		e := s.lookup(args[1])
+		switch {
+		case e == nil:
+			return []byte(nil)
+		case e.hash != nil:
+			return wrongType()
+		}
+		return []byte(e.value)
+	case "SET":
+		if len(args) != 3 {
+			return wrongArgs(cmd)
+		}
+		s.keys[args[1]] = &entry{value: args[2]}
+		return "OK"
+	case "HMGET":
+		if len(args) < 3 {
+			return wrongArgs(cmd)
+		}
+		e := s.lookup(args[1])
+		if e != nil && e.hash == nil {
+			return wrongType()
+		}
+		values := make([]interface{}, 0, len(args)-2)
+		for _, field := range args[2:] {
+			if v, ok := e.get(field); ok {
+				values = append(values, []byte(v))
+			} else {
+				values = append(values, nil)
+			}
+		}
+		return values
+	case "HSET":
+		if len(args) < 4 || len(args)%2 != 0 {
+			return wrongArgs(cmd)
+		}
+		e := s.lookup(args[1])
+		if e == nil {
+			e = &entry{hash: make(map[string]string)}
+			s.keys[args[1]] = e
+		} else if e.hash == nil {
+			return wrongType()
+		}
+		added := int64(0)
+		for i := 2; i < len(args); i += 2 {
+			if _, ok := e.hash[args[i]]; !ok {
+				added++
+			}
+			e.hash[args[i]] = args[i+1]
+		}
+		return added
+	case "DEL":
+		if len(args) < 2 {
+			return wrongArgs(cmd)
+		}
+		deleted := int64(0)
+		for _, k := range args[1:] {
+			if s.lookup(k) != nil {
+				deleted++
+			}
+			delete(s.keys, k)
+		}
+		return deleted
+	case "PEXPIRE":
+		if len(args) != 3 {
+			return wrongArgs(cmd)
+		}
+		ms, err := strconv.ParseInt(args[2], 10, 64)
+		if err != nil {
+			return redisqm.Error("ERR value is not an integer or out of range")
+		}
+		e := s.lookup(args[1])
+		if e == nil {
+			return int64(0)
+		}
+		e.expiry = s.timeSource.Now().Add(time.Duration(ms) * time.Millisecond)
+		return int64(1)
+	case "EVAL":
+		if len(args) < 3 {
+			return wrongArgs(cmd)
+		}
+		if args[1] != redisqm.UpdateScript {
+			return redisqm.Error("ERR unsupported script")
+		}
+		numKeys, err := strconv.Atoi(args[2])
+		if err != nil || numKeys < 0 || numKeys > len(args)-3 {
+			return redisqm.Error("ERR Number of keys can't be greater than number of args")
+		}
+		return s.update(args[3:3+numKeys], args[3+numKeys:])
+	}
+	return redisqm.Error(fmt.Sprintf("ERR unknown command '%v'", cmd))
+}
+
+// update emulates redisqm.UpdateScript. s.mu must be held.
+func (s *Server) update(keys, argv []string) interface{} {
+	if len(argv) != 2+2*len(keys) {
+		return redisqm.Error("ERR bad quota script arguments")
+	}
+	op := argv[0]
+	n, err := strconv.ParseFloat(argv[1], 64)
+	if err != nil {
+		return redisqm.Error("ERR bad number of tokens")
+	}
+	now := s.timeSource.Now().UnixNano() / int64(time.Microsecond)
+
+	ok := int64(1)
+	tokens := make([]float64, len(keys))
+	last := make([]int64, len(keys))
+	rates := make([]float64, len(keys))
+	bursts := make([]float64, len(keys))
+	for i, k := range keys {
+		rate, err := strconv.ParseFloat(argv[2+2*i], 64)
+		if err != nil {
+			return redisqm.Error("ERR bad rate")
+		}
+		burst, err := strconv.ParseFloat(argv[3+2*i], 64)
+		if err != nil {
+			return redisqm.Error("ERR bad burst")
+		}
+		rates[i], bursts[i] = rate, burst
+		tokens[i], last[i] = burst, now
+		e := s.lookup(k)
+		t, okT := e.get("tokens")
+		l, okL := e.get("last")
+		if okT && okL {
+			if tokens[i], err = strconv.ParseFloat(t, 64); err != nil {
+				return redisqm.Error("ERR bad tokens")
+			}
+			if last[i], err = strconv.ParseInt(l, 10, 64); err != nil {
+				return redisqm.Error("ERR bad last refill")
+			}
+			if now > last[i] {
+				tokens[i] += float64(now-last[i]) / 1e6 * rate
+				last[i] = now
+			}
+			tokens[i] = math.Min(tokens[i], burst)
+		}
+		if op == "get" && tokens[i] < n {
+			ok = 0
+		}
+	}
+	if op != "peek" && ok == 1 {
+		for i, k := range keys {
+			if op == "get" {
+				tokens[i] -= n
+			} else {
+				tokens[i] = math.Min(tokens[i]+n, bursts[i])
+			}
+			s.run("HSET", []string{"HSET", k, "tokens", formatFloat(tokens[i]), "last", strconv.FormatInt(last[i], 10)})
+			ms := int64(math.Ceil((bursts[i]-tokens[i])/rates[i]*1000)) + 1000
+			s.run("PEXPIRE", []string{"PEXPIRE", k, strconv.FormatInt(ms, 10)})
+		}
+	}
+	reply := []interface{}{ok}
+	for _, t := range tokens {
+		reply = append(reply, []byte(formatFloat(t)))
+	}
+	return reply
+}
+
+// lookup returns the unexpired entry of key, or nil. s.mu must be held.
+func (s *Server) lookup(key string) *entry {
+	e, ok := s.keys[key]
+	if !ok {
+		return nil
+	}
+	if !e.expiry.IsZero() && !s.timeSource.Now().Before(e.expiry) {
+		delete(s.keys, key)
+		return nil
+	}
+	return e
+}
+
+func (e *entry) get(field string) (string, bool) {
+	if e == nil {
+		return "", false
+	}
+	v, ok := e.hash[field]
+	return v, ok
+}
+
+// formatFloat formats f like Lua's string.format("%.17g", f).
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', 17, 64)
+}
+
+func wrongType() redisqm.Error {
+	return redisqm.Error("WRONGTYPE Operation against a key holding the wrong kind of value")
+}
+
+func wrongArgs(cmd string) redisqm.Error {
+	return redisqm.Error(fmt.Sprintf("ERR wrong number of arguments for '%v' command", strings.ToLower(cmd)))
+}
+
+// readCommand reads a command, sent as an array of bulk strings.
+func readCommand(r *bufio.Reader) ([]string, error) {
+	v, err := redisqm.ReadReply(r)
+	if err != nil {
+		return nil, err
+	}
+	values, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected command: %v", v)
+	}
+	args := make([]string, 0, len(values))
+	for _, v := range values {
+		b, ok := v.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("unexpected argument: %v", v)
+		}
+		args = append(args, string(b))
+	}
+	return args, nil
+}
+
+func writeReply(w *bufio.Writer, reply interface{}) {
+	switch v := reply.(type) {
+	case nil:
+		w.WriteString("*-1\r\n")
+	case string:
+		fmt.Fprintf(w, "+%s\r\n", v)
+	case redisqm.Error:
+		fmt.Fprintf(w, "-%s\r\n", v)
+	case int64:
+		fmt.Fprintf(w, ":%d\r\n", v)
+	case []byte:
+		if v == nil {
+			w.WriteString("$-1\r\n")
+			return
+		}
+		fmt.Fprintf(w, "$%d\r\n%s\r\n", len(v), v)
+	case []interface{}:
+		fmt.Fprintf(w, "*%d\r\n", len(v))
+		for _, e := range v {
+			if e == nil {
+				w.WriteString("$-1\r\n")
+				continue
+			}
+			writeReply(w, e)
+		}
+	default:
+		panic(fmt.Sprintf("unexpected reply type %T", reply))
+	}
+}
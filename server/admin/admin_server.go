@@ -20,6 +20,7 @@ import (
 	"fmt"
 
 	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/google/trillian"
 	"github.com/google/trillian/crypto/keys/der"
@@ -157,6 +158,8 @@ func (s *Server) CreateTree(ctx context.Context, req *trillian.CreateTreeRequest
 	tree.UpdateTime = nil
 	tree.Deleted = false
 	tree.DeleteTime = nil
+	tree.KeyId = 0
+	tree.PreviousKeys = nil
 
 	createdTree, err := storage.CreateTree(ctx, s.registry.AdminStorage, tree)
 	if err != nil {
@@ -221,6 +224,10 @@ func applyUpdateMask(from, to *trillian.Tree, mask *field_mask.FieldMask) error
 			to.MaxRootDuration = from.MaxRootDuration
 		case "private_key":
 			to.PrivateKey = from.PrivateKey
+		case "queue_ttl":
+			to.QueueTtl = from.QueueTtl
+		case "max_queue_depth":
+			to.MaxQueueDepth = from.MaxQueueDepth
 		default:
 			return status.Errorf(codes.InvalidArgument, "invalid update_mask path: %q", path)
 		}
@@ -246,6 +253,67 @@ func (s *Server) UndeleteTree(ctx context.Context, req *trillian.UndeleteTreeReq
 	return redact(tree), nil
 }
 
+// AddTreeKey implements trillian.TrillianAdminServer.AddTreeKey.
+func (s *Server) AddTreeKey(ctx context.Context, req *trillian.AddTreeKeyRequest) (*trillian.Tree, error) {
+	privateKey := req.GetPrivateKey()
+
+	// If a key specification was provided, generate a new key.
+	if req.KeySpec != nil {
+		if privateKey != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "the private_key and key_spec fields are mutually exclusive")
+		}
+		if s.registry.NewKeyProto == nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "key generation is not enabled")
+		}
+
+		keyProto, err := s.registry.NewKeyProto(ctx, req.KeySpec)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "failed to generate private key: %v", err.Error())
+		}
+
+		privateKey, err = ptypes.MarshalAny(keyProto)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to marshal private key: %v", err.Error())
+		}
+	}
+
+	if privateKey == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "private_key or key_spec is required")
+	}
+
+	tree, err := storage.GetTree(ctx, s.registry.AdminStorage, req.GetTreeId())
+	if err != nil {
+		return nil, err
+	}
+
+	// Check that the new key is valid for the tree by trying to get a signer.
+	candidate := proto.Clone(tree).(*trillian.Tree)
+	candidate.PrivateKey = privateKey
+	signer, err := trees.Signer(ctx, candidate)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to create signer for tree: %v", err.Error())
+	}
+	publicKey, err := der.ToPublicProto(signer.Public())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to marshal public key: %v", err.Error())
+	}
+
+	// The current key is retired into previous_keys. Storage validates that
+	// the new key hasn't been used by the tree before.
+	updatedTree, err := storage.UpdateTree(ctx, s.registry.AdminStorage, req.GetTreeId(), func(other *trillian.Tree) {
+		previousKeys := make([]*trillian.TreeKey, 0, len(other.PreviousKeys)+1)
+		previousKeys = append(previousKeys, other.PreviousKeys...)
+		other.PreviousKeys = append(previousKeys, &trillian.TreeKey{KeyId: other.KeyId, PublicKey: other.PublicKey})
+		other.KeyId++
+		other.PrivateKey = privateKey
+		other.PublicKey = publicKey
+	})
+	if err != nil {
+		return nil, err
+	}
+	return redact(updatedTree), nil
+}
+
 // redact removes sensitive information from t. Returns t for convenience.
 func redact(t *trillian.Tree) *trillian.Tree {
 	t.PrivateKey = nil
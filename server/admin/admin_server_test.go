@@ -39,6 +39,8 @@ import (
 	"github.com/google/trillian/crypto/sigpb"
 	"github.com/google/trillian/extension"
 	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/memory"
+	"github.com/google/trillian/storage/storagepb"
 	"github.com/google/trillian/storage/testonly"
 	"github.com/kylelemons/godebug/pretty"
 	"google.golang.org/genproto/protobuf/field_mask"
@@ -640,9 +642,11 @@ func TestServer_UpdateTree(t *testing.T) {
 		StorageSettings: settings,
 		MaxRootDuration: ptypes.DurationProto(2 * time.Nanosecond),
 		PrivateKey:      ttestonly.MustMarshalAny(t, &empty.Empty{}),
+		QueueTtl:        ptypes.DurationProto(1 * time.Hour),
+		MaxQueueDepth:   1000,
 	}
 	successMask := &field_mask.FieldMask{
-		Paths: []string{"tree_state", "display_name", "description", "storage_settings", "max_root_duration", "private_key"},
+		Paths: []string{"tree_state", "display_name", "description", "storage_settings", "max_root_duration", "private_key", "queue_ttl", "max_queue_depth"},
 	}
 
 	successWant := existingTree
@@ -652,6 +656,8 @@ func TestServer_UpdateTree(t *testing.T) {
 	successWant.StorageSettings = successTree.StorageSettings
 	successWant.PrivateKey = nil // redacted on responses
 	successWant.MaxRootDuration = successTree.MaxRootDuration
+	successWant.QueueTtl = successTree.QueueTtl
+	successWant.MaxQueueDepth = successTree.MaxQueueDepth
 
 	tests := []struct {
 		desc                           string
@@ -913,6 +919,129 @@ func TestServer_UndeleteTreeErrors(t *testing.T) {
 	}
 }
 
+func TestServer_AddTreeKey(t *testing.T) {
+	ctx := context.Background()
+
+	// PEM on the testonly trees is ECDSA, so new keys must be ECDSA too.
+	ecdsaPrivateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Error generating test ECDSA key: %v", err)
+	}
+	ecdsaPublicKey, err := der.ToPublicProto(ecdsaPrivateKey.Public())
+	if err != nil {
+		t.Fatalf("Error marshaling ECDSA public key: %v", err)
+	}
+	keyProto := &empty.Empty{}
+	keys.RegisterHandler(fakeKeyProtoHandler(keyProto, ecdsaPrivateKey))
+	defer keys.UnregisterHandler(keyProto)
+
+	rsaPrivateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Error generating test RSA key: %v", err)
+	}
+	rsaKeyProto := &timestamp.Timestamp{}
+	keys.RegisterHandler(fakeKeyProtoHandler(rsaKeyProto, rsaPrivateKey))
+	defer keys.UnregisterHandler(rsaKeyProto)
+
+	keySpec := &keyspb.Specification{Params: &keyspb.Specification_EcdsaParams{}}
+	as := memory.NewAdminStorage(memory.NewTreeStorage())
+	s := New(extension.Registry{
+		AdminStorage: as,
+		NewKeyProto:  fakeKeyProtoGenerator(keySpec, keyProto),
+	}, nil /* allowedTreeTypes */)
+
+	tree, err := storage.CreateTree(ctx, as, testonly.LogTree)
+	if err != nil {
+		t.Fatalf("CreateTree() = (_, %v)", err)
+	}
+
+	tests := []struct {
+		desc     string
+		req      *trillian.AddTreeKeyRequest
+		wantCode codes.Code
+		wantTree *trillian.Tree
+	}{
+		{
+			desc:     "noKey",
+			req:      &trillian.AddTreeKeyRequest{TreeId: tree.TreeId},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc: "privateKeyAndKeySpec",
+			req: &trillian.AddTreeKeyRequest{
+				TreeId:     tree.TreeId,
+				PrivateKey: ttestonly.MustMarshalAny(t, keyProto),
+				KeySpec:    keySpec,
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc: "wrongSignatureAlgorithm",
+			req: &trillian.AddTreeKeyRequest{
+				TreeId:     tree.TreeId,
+				PrivateKey: ttestonly.MustMarshalAny(t, rsaKeyProto),
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc: "reusedKey",
+			req: &trillian.AddTreeKeyRequest{
+				TreeId:     tree.TreeId,
+				PrivateKey: testonly.LogTree.PrivateKey,
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc: "privateKey",
+			req: &trillian.AddTreeKeyRequest{
+				TreeId:     tree.TreeId,
+				PrivateKey: ttestonly.MustMarshalAny(t, keyProto),
+			},
+			wantTree: &trillian.Tree{
+				KeyId:     1,
+				PublicKey: ecdsaPublicKey,
+				PreviousKeys: []*trillian.TreeKey{
+					{KeyId: 0, PublicKey: tree.PublicKey},
+				},
+			},
+		},
+		{
+			// The key generated from keySpec is the same as the current one.
+			desc: "keySpecReusedKey",
+			req: &trillian.AddTreeKeyRequest{
+				TreeId:  tree.TreeId,
+				KeySpec: keySpec,
+			},
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, test := range tests {
+		got, err := s.AddTreeKey(ctx, test.req)
+		if gotCode := status.Code(err); gotCode != test.wantCode {
+			t.Errorf("%v: AddTreeKey() = (_, %v), want code %v", test.desc, err, test.wantCode)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if got.PrivateKey != nil {
+			t.Errorf("%v: AddTreeKey() returned a tree with private_key set", test.desc)
+		}
+		if got.KeyId != test.wantTree.KeyId {
+			t.Errorf("%v: AddTreeKey().KeyId = %v, want %v", test.desc, got.KeyId, test.wantTree.KeyId)
+		}
+		if !proto.Equal(got.PublicKey, test.wantTree.PublicKey) {
+			t.Errorf("%v: AddTreeKey().PublicKey = %v, want %v", test.desc, got.PublicKey, test.wantTree.PublicKey)
+		}
+		gotKeys := &storagepb.TreeKeys{Keys: got.PreviousKeys}
+		wantKeys := &storagepb.TreeKeys{Keys: test.wantTree.PreviousKeys}
+		if !proto.Equal(gotKeys, wantKeys) {
+			t.Errorf("%v: AddTreeKey().PreviousKeys = %v, want %v", test.desc, gotKeys.Keys, wantKeys.Keys)
+		}
+	}
+}
+
 // adminTestSetup contains an operational Server and required dependencies.
 // It's created via setupAdminServer.
 type adminTestSetup struct {
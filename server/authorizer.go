@@ -0,0 +1,42 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"flag"
+
+	"github.com/google/trillian/auth"
+)
+
+// AuthPolicyFile is a flag specifying the authorization policy in use.
+var AuthPolicyFile = flag.String("auth_policy_file", "", "Path to a JSON auth.Policy restricting which callers may perform which operations on each tree. If unset, all requests are allowed.")
+
+// NewAuthorizerFromFlags returns an auth.Authorizer as specified by flag.
+func NewAuthorizerFromFlags() (auth.Authorizer, error) {
+	return NewAuthorizer(*AuthPolicyFile)
+}
+
+// NewAuthorizer returns an auth.Authorizer enforcing the policy in
+// policyFile, or allowing all requests if policyFile is empty.
+func NewAuthorizer(policyFile string) (auth.Authorizer, error) {
+	if policyFile == "" {
+		return auth.AllowAll(), nil
+	}
+	p, err := auth.LoadPolicy(policyFile)
+	if err != nil {
+		return nil, err
+	}
+	return auth.NewPolicyAuthorizer(p)
+}
@@ -0,0 +1,63 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/google/trillian/trees"
+)
+
+func TestNewAuthorizer(t *testing.T) {
+	f, err := ioutil.TempFile("", "policy")
+	if err != nil {
+		t.Fatalf("TempFile(): %v", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(`{"grants": [{"identity": "*", "ops": ["Query"]}]}`); err != nil {
+		t.Fatalf("WriteString(): %v", err)
+	}
+	f.Close()
+
+	ctx := context.Background()
+	for _, test := range []struct {
+		desc      string
+		file      string
+		wantErr   bool
+		wantAdmin bool
+	}{
+		{desc: "noPolicy", wantAdmin: true},
+		{desc: "policy", file: f.Name()},
+		{desc: "missingPolicy", file: f.Name() + ".missing", wantErr: true},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			authz, err := NewAuthorizer(test.file)
+			if gotErr := err != nil; gotErr != test.wantErr {
+				t.Fatalf("NewAuthorizer(%q) returned err = %v, wantErr %v", test.file, err, test.wantErr)
+			} else if gotErr {
+				return
+			}
+			if err := authz.Authorize(ctx, 1, trees.Query); err != nil {
+				t.Errorf("Authorize(Query) returned err = %v", err)
+			}
+			if err := authz.Authorize(ctx, 1, trees.Admin); (err == nil) != test.wantAdmin {
+				t.Errorf("Authorize(Admin) returned err = %v, want allowed = %v", err, test.wantAdmin)
+			}
+		})
+	}
+}
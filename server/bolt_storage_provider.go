@@ -0,0 +1,81 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"flag"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/google/trillian/monitoring"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/bolt"
+
+	bbolt "go.etcd.io/bbolt"
+)
+
+var (
+	boltPath = flag.String("bolt_path", "trillian.db", "Path to the file of the embedded bolt database, which is created if it doesn't exist")
+
+	boltOnce            sync.Once
+	boltOnceErr         error
+	boltStorageInstance *boltProvider
+)
+
+func init() {
+	if err := RegisterStorageProvider("bolt", newBoltStorageProvider); err != nil {
+		glog.Fatalf("Failed to register storage provider bolt: %v", err)
+	}
+}
+
+type boltProvider struct {
+	db *bbolt.DB
+	mf monitoring.MetricFactory
+}
+
+func newBoltStorageProvider(mf monitoring.MetricFactory) (StorageProvider, error) {
+	// The database file can only be opened once per process.
+	boltOnce.Do(func() {
+		var db *bbolt.DB
+		db, boltOnceErr = bolt.OpenDB(*boltPath)
+		if boltOnceErr != nil {
+			return
+		}
+		boltStorageInstance = &boltProvider{
+			db: db,
+			mf: mf,
+		}
+	})
+	if boltOnceErr != nil {
+		return nil, boltOnceErr
+	}
+	return boltStorageInstance, nil
+}
+
+func (s *boltProvider) LogStorage() storage.LogStorage {
+	return bolt.NewLogStorage(s.db, s.mf)
+}
+
+func (s *boltProvider) MapStorage() storage.MapStorage {
+	return bolt.NewMapStorage(s.db)
+}
+
+func (s *boltProvider) AdminStorage() storage.AdminStorage {
+	return bolt.NewAdminStorage(s.db)
+}
+
+func (s *boltProvider) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,63 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/trillian/testonly/flagsaver"
+)
+
+func TestBoltStorageProvider(t *testing.T) {
+	defer flagsaver.Save().MustRestore()
+	dir, err := ioutil.TempDir("", "bolt_storage_provider")
+	if err != nil {
+		t.Fatalf("TempDir(): %v", err)
+	}
+	defer os.RemoveAll(dir)
+	if err := flag.Set("bolt_path", filepath.Join(dir, "trillian.db")); err != nil {
+		t.Fatalf("Failed to set flag: %v", err)
+	}
+
+	sp, err := NewStorageProvider("bolt", nil)
+	if err != nil {
+		t.Fatalf("Got an unexpected error: %v", err)
+	}
+	if sp == nil {
+		t.Fatal("Got a nil storage provider.")
+	}
+	defer sp.Close()
+
+	// The provider is a singleton, as the database can only be opened once.
+	if sp2, err := NewStorageProvider("bolt", nil); err != nil || sp2 != sp {
+		t.Errorf("Second NewStorageProvider() = (%v, %v), want (%v, nil)", sp2, err, sp)
+	}
+
+	ctx := context.Background()
+	if err := sp.AdminStorage().CheckDatabaseAccessible(ctx); err != nil {
+		t.Errorf("AdminStorage().CheckDatabaseAccessible() = %v", err)
+	}
+	if err := sp.LogStorage().CheckDatabaseAccessible(ctx); err != nil {
+		t.Errorf("LogStorage().CheckDatabaseAccessible() = %v", err)
+	}
+	if err := sp.MapStorage().CheckDatabaseAccessible(ctx); err != nil {
+		t.Errorf("MapStorage().CheckDatabaseAccessible() = %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"flag"
+	"fmt"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/google/trillian/monitoring"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/commitlog"
+)
+
+const commitLogStorageSystem = "commitlog"
+
+var (
+	commitLogDir          = flag.String("commitlog_dir", "commitlog", "Directory of the file-backed commit log, which is created if it doesn't exist")
+	commitLogLocalStorage = flag.String("commitlog_local_storage_system", "bolt", "Storage system of the local database which serves reads for the commit log storage")
+
+	commitLogOnce            sync.Once
+	commitLogOnceErr         error
+	commitLogStorageInstance *commitLogProvider
+)
+
+func init() {
+	if err := RegisterStorageProvider(commitLogStorageSystem, newCommitLogStorageProvider); err != nil {
+		glog.Fatalf("Failed to register storage provider %s: %v", commitLogStorageSystem, err)
+	}
+}
+
+// commitLogProvider provides log storage which sequences leaves in a
+// commit log, kept in local files. Trees and maps are kept in the local
+// database only.
+type commitLogProvider struct {
+	cl    *commitlog.FileLog
+	local StorageProvider
+	ls    *commitlog.LogStorage
+}
+
+func newCommitLogStorageProvider(mf monitoring.MetricFactory) (StorageProvider, error) {
+	// The commit log files must not be written by more than one FileLog.
+	commitLogOnce.Do(func() {
+		if *commitLogLocalStorage == commitLogStorageSystem {
+			commitLogOnceErr = fmt.Errorf("%s can't be its own local storage system", commitLogStorageSystem)
+			return
+		}
+		local, err := NewStorageProvider(*commitLogLocalStorage, mf)
+		if err != nil {
+			commitLogOnceErr = err
+			return
+		}
+		cl, err := commitlog.NewFileLog(*commitLogDir)
+		if err != nil {
+			local.Close()
+			commitLogOnceErr = err
+			return
+		}
+		commitLogStorageInstance = &commitLogProvider{
+			cl:    cl,
+			local: local,
+			ls:    commitlog.NewLogStorage(cl, local.LogStorage()),
+		}
+	})
+	if commitLogOnceErr != nil {
+		return nil, commitLogOnceErr
+	}
+	return commitLogStorageInstance, nil
+}
+
+func (s *commitLogProvider) LogStorage() storage.LogStorage {
+	return s.ls
+}
+
+func (s *commitLogProvider) MapStorage() storage.MapStorage {
+	return s.local.MapStorage()
+}
+
+func (s *commitLogProvider) AdminStorage() storage.AdminStorage {
+	return s.local.AdminStorage()
+}
+
+func (s *commitLogProvider) Close() error {
+	if err := s.cl.Close(); err != nil {
+		s.local.Close()
+		return err
+	}
+	return s.local.Close()
+}
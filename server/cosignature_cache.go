@@ -0,0 +1,105 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+
+	"github.com/google/trillian"
+	"github.com/google/trillian/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// cosignedRoot is a log root together with the witness cosignatures of it.
+type cosignedRoot struct {
+	slr    *trillian.SignedLogRoot
+	root   types.LogRootV1
+	cosigs map[string]*trillian.Cosignature
+}
+
+// newerThan returns whether c's root was produced after root.
+func (c *cosignedRoot) newerThan(root *types.LogRootV1) bool {
+	if c.root.TreeSize != root.TreeSize {
+		return c.root.TreeSize > root.TreeSize
+	}
+	return c.root.TimestampNanos > root.TimestampNanos
+}
+
+// cosignatureCache holds the cosignatures of the most recently cosigned root
+// of each log. It is neither persisted nor shared between servers, so
+// witnesses are expected to keep cosigning new roots as the log grows.
+type cosignatureCache struct {
+	mu    sync.Mutex
+	roots map[int64]*cosignedRoot
+}
+
+// add records cosig as a cosignature of slr, whose contents are root. It
+// fails if a newer root of the log has already been cosigned.
+func (c *cosignatureCache) add(logID int64, slr *trillian.SignedLogRoot, root *types.LogRootV1, cosig *trillian.Cosignature) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.roots == nil {
+		c.roots = make(map[int64]*cosignedRoot)
+	}
+
+	cr := c.roots[logID]
+	if cr == nil || !bytes.Equal(cr.slr.LogRoot, slr.LogRoot) {
+		if cr != nil && cr.newerThan(root) {
+			return status.Errorf(codes.FailedPrecondition, "a newer root of log %d (size %d) has already been cosigned", logID, cr.root.TreeSize)
+		}
+		cr = &cosignedRoot{slr: slr, root: *root, cosigs: make(map[string]*trillian.Cosignature)}
+		c.roots[logID] = cr
+	}
+	cr.cosigs[cosig.Witness] = cosig
+	return nil
+}
+
+// latest returns the most recently cosigned root of the log, and its
+// cosignatures ordered by witness name. It returns a nil root if no root of
+// the log has been cosigned.
+func (c *cosignatureCache) latest(logID int64) (*trillian.SignedLogRoot, []*trillian.Cosignature) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cr := c.roots[logID]
+	if cr == nil {
+		return nil, nil
+	}
+	return cr.slr, cr.sorted()
+}
+
+// cosignatures returns the known cosignatures of slr, ordered by witness name.
+func (c *cosignatureCache) cosignatures(logID int64, slr *trillian.SignedLogRoot) []*trillian.Cosignature {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cr := c.roots[logID]
+	if cr == nil || !bytes.Equal(cr.slr.LogRoot, slr.LogRoot) {
+		return nil
+	}
+	return cr.sorted()
+}
+
+// sorted returns the cosignatures of c ordered by witness name. Must be
+// called with the cache's mu held.
+func (c *cosignedRoot) sorted() []*trillian.Cosignature {
+	cosigs := make([]*trillian.Cosignature, 0, len(c.cosigs))
+	for _, cosig := range c.cosigs {
+		cosigs = append(cosigs, cosig)
+	}
+	sort.Slice(cosigs, func(i, j int) bool { return cosigs[i].Witness < cosigs[j].Witness })
+	return cosigs
+}
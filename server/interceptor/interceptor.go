@@ -24,6 +24,7 @@ import (
 
 	"github.com/golang/glog"
 	"github.com/google/trillian"
+	"github.com/google/trillian/auth"
 	"github.com/google/trillian/monitoring"
 	"github.com/google/trillian/quota"
 	"github.com/google/trillian/quota/etcd/quotapb"
@@ -39,9 +40,14 @@ const (
 	badInfoReason            = "bad_info"
 	badTreeReason            = "bad_tree"
 	insufficientTokensReason = "insufficient_tokens"
+	unauthorizedReason       = "unauthorized"
 	getTreeStage             = "get_tree"
 	getTokensStage           = "get_tokens"
 	traceSpanRoot            = "/trillian/server/int"
+
+	// maxListMapLeavesTokens is the most tokens charged for a ListLeaves
+	// request, and matches the largest page size served by the map server.
+	maxListMapLeavesTokens = 10000
 )
 
 var (
@@ -55,12 +61,17 @@ var (
 	contextErrCounter    monitoring.Counter
 	metricsOnce          sync.Once
 	enabledServices      = map[string]bool{
-		"trillian.TrillianLog":   true,
-		"trillian.TrillianMap":   true,
-		"trillian.TrillianAdmin": true,
-		"TrillianLog":            true,
-		"TrillianMap":            true,
-		"TrillianAdmin":          true,
+		"trillian.TrillianLog":          true,
+		"trillian.TrillianMap":          true,
+		"trillian.TrillianAdmin":        true,
+		"trillian.TrillianLogSequencer": true,
+		"trillian.Quota":                true,
+		"quotapb.Quota":                 true,
+		"TrillianLog":                   true,
+		"TrillianMap":                   true,
+		"TrillianAdmin":                 true,
+		"TrillianLogSequencer":          true,
+		"Quota":                         true,
 	}
 )
 
@@ -80,12 +91,13 @@ type RequestProcessor interface {
 }
 
 // TrillianInterceptor checks that:
-// * Requests addressing a tree have the correct tree type and tree state;
-// * TODO(codingllama): Requests are properly authenticated / authorized ; and
+// * Requests are authorized for the tree and operation they address;
+// * Requests addressing a tree have the correct tree type and tree state; and
 // * Requests are rate limited appropriately.
 type TrillianInterceptor struct {
 	admin storage.AdminStorage
 	qm    quota.Manager
+	authz auth.Authorizer
 
 	// quotaDryRun controls whether lack of tokens actually blocks requests (if set to true, no
 	// requests are blocked by lack of tokens).
@@ -93,11 +105,16 @@ type TrillianInterceptor struct {
 }
 
 // New returns a new TrillianInterceptor instance.
-func New(admin storage.AdminStorage, qm quota.Manager, quotaDryRun bool, mf monitoring.MetricFactory) *TrillianInterceptor {
+// If authz is nil, all requests are authorized.
+func New(admin storage.AdminStorage, qm quota.Manager, authz auth.Authorizer, quotaDryRun bool, mf monitoring.MetricFactory) *TrillianInterceptor {
 	metricsOnce.Do(func() { initMetrics(mf) })
+	if authz == nil {
+		authz = auth.AllowAll()
+	}
 	return &TrillianInterceptor{
 		admin:       admin,
 		qm:          qm,
+		authz:       authz,
 		quotaDryRun: quotaDryRun,
 	}
 }
@@ -142,6 +159,81 @@ func (i *TrillianInterceptor) UnaryInterceptor(ctx context.Context, req interfac
 	return resp, err
 }
 
+// StreamInterceptor executes the TrillianInterceptor logic for streaming RPCs.
+// The interceptor logic is run against the request message once it has been
+// received by the handler, so server-streaming RPCs are authorized and checked
+// against their tree in the same way as their unary counterparts. Quota is
+// charged for each message sent on the stream, rather than for the request.
+func (i *TrillianInterceptor) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	rp := &trillianProcessor{parent: i, stream: true}
+	s := &serverStream{ServerStream: ss, rp: rp, method: info.FullMethod}
+	err := handler(srv, s)
+	if s.ctx != nil {
+		s.rp.After(s.ctx, nil, info.FullMethod, err)
+	}
+	return err
+}
+
+// serverStream wraps a grpc.ServerStream, running a trillianProcessor's Before
+// stage on the received request, exposing the resulting context to the
+// handler, and charging quota for the messages it sends.
+type serverStream struct {
+	grpc.ServerStream
+	rp     *trillianProcessor
+	method string
+	// ctx is the context returned by rp.Before, or nil if no request has
+	// been successfully processed yet.
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	if s.ctx != nil {
+		return s.ctx
+	}
+	return s.ServerStream.Context()
+}
+
+func (s *serverStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if s.ctx != nil {
+		// Only the request that opens the stream is intercepted.
+		return nil
+	}
+	ctx, err := s.rp.Before(s.ServerStream.Context(), m, s.method)
+	if err != nil {
+		return err
+	}
+	s.ctx = ctx
+	return nil
+}
+
+func (s *serverStream) SendMsg(m interface{}) error {
+	if info := s.rp.info; s.ctx != nil && info != nil && info.tokens > 0 && len(info.specs) > 0 {
+		if err := s.rp.getTokens(s.ctx, messageTokens(m), m); err != nil {
+			return err
+		}
+	}
+	return s.ServerStream.SendMsg(m)
+}
+
+// messageTokens returns the number of tokens charged for sending m on a
+// stream.
+func messageTokens(m interface{}) int {
+	var n int
+	switch m := m.(type) {
+	case *trillian.StreamLeavesResponse:
+		n = len(m.GetLeaves())
+	case *trillian.GetMapDiffResponse:
+		n = len(m.GetDiffs())
+	}
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
 // NewProcessor returns a RequestProcessor for the TrillianInterceptor logic.
 func (i *TrillianInterceptor) NewProcessor() RequestProcessor {
 	return &trillianProcessor{parent: i}
@@ -150,6 +242,9 @@ func (i *TrillianInterceptor) NewProcessor() RequestProcessor {
 type trillianProcessor struct {
 	parent *TrillianInterceptor
 	info   *rpcInfo
+	// stream is set for streaming RPCs, which are charged quota for the
+	// messages they send instead of by Before.
+	stream bool
 }
 
 func (tp *trillianProcessor) Before(ctx context.Context, req interface{}, method string) (context.Context, error) {
@@ -170,7 +265,12 @@ func (tp *trillianProcessor) Before(ctx context.Context, req interface{}, method
 	tp.info = info
 	requestCounter.Inc(fmt.Sprint(info.treeID))
 
-	// TODO(codingllama): Add auth interception
+	// Authorize before reading the tree, so unauthorized callers can't learn
+	// which trees exist.
+	if err := tp.parent.authz.Authorize(innerCtx, info.treeID, info.op); err != nil {
+		incRequestDeniedCounter(unauthorizedReason, info.treeID, info.quotaUsers)
+		return ctx, err
+	}
 
 	if info.getTree {
 		tree, err := trees.GetTree(
@@ -186,17 +286,11 @@ func (tp *trillianProcessor) Before(ctx context.Context, req interface{}, method
 		ctx = trees.NewContext(ctx, tree)
 	}
 
-	if info.tokens > 0 && len(info.specs) > 0 {
-		err := tp.parent.qm.GetTokens(innerCtx, info.tokens, info.specs)
-		if err != nil {
-			if !tp.parent.quotaDryRun {
-				incRequestDeniedCounter(insufficientTokensReason, info.treeID, info.quotaUsers)
-				return ctx, status.Errorf(codes.ResourceExhausted, "quota exhausted: %v", err)
-			}
-			glog.Warningf("(quotaDryRun) Request %+v not denied due to dry run mode: %v", req, err)
+	if info.tokens > 0 && len(info.specs) > 0 && !tp.stream {
+		if err := tp.getTokens(innerCtx, info.tokens, req); err != nil {
+			return ctx, err
 		}
-		quota.Metrics.IncAcquired(info.tokens, info.specs, err == nil)
-		if err = innerCtx.Err(); err != nil {
+		if err := innerCtx.Err(); err != nil {
 			contextErrCounter.Inc(getTokensStage)
 			return ctx, err
 		}
@@ -205,6 +299,23 @@ func (tp *trillianProcessor) Before(ctx context.Context, req interface{}, method
 	return ctx, nil
 }
 
+// getTokens acquires tokens from the quota manager for the specs of the
+// request, unless in dry run mode. m is the message being charged for, and is
+// only used for logging.
+func (tp *trillianProcessor) getTokens(ctx context.Context, tokens int, m interface{}) error {
+	info := tp.info
+	err := tp.parent.qm.GetTokens(ctx, tokens, info.specs)
+	if err != nil {
+		if !tp.parent.quotaDryRun {
+			incRequestDeniedCounter(insufficientTokensReason, info.treeID, info.quotaUsers)
+			return status.Errorf(codes.ResourceExhausted, "quota exhausted: %v", err)
+		}
+		glog.Warningf("(quotaDryRun) Request %+v not denied due to dry run mode: %v", m, err)
+	}
+	quota.Metrics.IncAcquired(tokens, info.specs, err == nil)
+	return nil
+}
+
 func (tp *trillianProcessor) After(ctx context.Context, resp interface{}, method string, handlerErr error) {
 	if !enabledServices[serviceName(method)] {
 		return
@@ -218,6 +329,10 @@ func (tp *trillianProcessor) After(ctx context.Context, resp interface{}, method
 	case tp.info.tokens == 0:
 		// After() currently only does quota processing
 		return
+	case tp.stream:
+		// Streams are only charged for the messages they actually sent, so
+		// there are no tokens to replenish.
+		return
 	}
 
 	// Decide if we have to replenish tokens. There are a few situations that require tokens to
@@ -300,6 +415,8 @@ type rpcInfo struct {
 	readonly  bool
 	treeID    int64
 	treeTypes []trillian.TreeType
+	// op is the operation the request performs on the tree, for authorization.
+	op trees.OpType
 
 	specs  []quota.Spec
 	tokens int
@@ -333,26 +450,42 @@ func newRPCInfoForRequest(req interface{}) (*rpcInfo, error) {
 		getTree:   true,
 		readonly:  true,
 		treeTypes: nil,
+		op:        trees.Query,
 		tokens:    0,
 	}
 
 	switch req := req.(type) {
 
-	// Not intercepted at all
+	// Quota configuration requests, which are only authorized
 	case
-		// Quota configuration requests
 		*quotapb.CreateConfigRequest,
 		*quotapb.DeleteConfigRequest,
 		*quotapb.GetConfigRequest,
 		*quotapb.ListConfigsRequest,
 		*quotapb.UpdateConfigRequest:
 		info.getTree = false
-		info.readonly = false // Doesn't really matter as all interceptors are turned off
+		info.readonly = false
+		info.op = trees.Admin
+
+	// Quota admin
+	case
+		*trillian.CreateQuotaConfigRequest,
+		*trillian.DeleteQuotaConfigRequest,
+		*trillian.UpdateQuotaConfigRequest:
+		info.getTree = false
+		info.readonly = false
+		info.op = trees.Admin
+	case
+		*trillian.GetQuotaConfigRequest,
+		*trillian.ListQuotaConfigsRequest:
+		info.getTree = false
+		info.op = trees.Admin
 
 	// Admin create
 	case *trillian.CreateTreeRequest:
 		info.getTree = false // Tree doesn't exist
 		info.readonly = false
+		info.op = trees.Admin
 
 	// Admin list
 	case *trillian.ListTreesRequest:
@@ -363,23 +496,43 @@ func newRPCInfoForRequest(req interface{}) (*rpcInfo, error) {
 		info.getTree = false // Read done within RPC handler
 
 	// Admin / readwrite
-	case *trillian.DeleteTreeRequest,
+	case *trillian.AddTreeKeyRequest,
+		*trillian.DeleteTreeRequest,
 		*trillian.UndeleteTreeRequest,
 		*trillian.UpdateTreeRequest:
 		info.getTree = false // Read-modify-write done within RPC handler
 		info.readonly = false
+		info.op = trees.Admin
+
+	// Sequencer / readonly
+	case *trillian.GetMastershipRequest:
+		info.getTree = false // Zero to many trees
+	case *trillian.GetQueueSizeRequest:
+		info.treeTypes = []trillian.TreeType{trillian.TreeType_LOG, trillian.TreeType_PREORDERED_LOG}
+
+	// Sequencer / readwrite
+	case *trillian.IntegrateBatchRequest,
+		*trillian.PauseSequencingRequest,
+		*trillian.ResumeSequencingRequest:
+		info.readonly = false
+		info.op = trees.Admin
+		info.treeTypes = []trillian.TreeType{trillian.TreeType_LOG, trillian.TreeType_PREORDERED_LOG}
 
 	// (Log + Pre-ordered Log) / readonly
 	case *trillian.GetConsistencyProofRequest,
 		*trillian.GetEntryAndProofRequest,
 		*trillian.GetInclusionProofByHashRequest,
 		*trillian.GetInclusionProofRequest,
-		*trillian.GetLatestSignedLogRootRequest:
+		*trillian.GetLatestSignedLogRootRequest,
+		*trillian.GetRangeInclusionProofRequest:
 		info.treeTypes = []trillian.TreeType{trillian.TreeType_LOG, trillian.TreeType_PREORDERED_LOG}
 		info.tokens = 1
 	case *trillian.GetLeavesByHashRequest:
 		info.treeTypes = []trillian.TreeType{trillian.TreeType_LOG, trillian.TreeType_PREORDERED_LOG}
 		info.tokens = len(req.GetLeafHash())
+	case *trillian.GetBatchInclusionProofRequest:
+		info.treeTypes = []trillian.TreeType{trillian.TreeType_LOG, trillian.TreeType_PREORDERED_LOG}
+		info.tokens = len(req.GetLeafIndex())
 	case *trillian.GetLeavesByIndexRequest:
 		info.treeTypes = []trillian.TreeType{trillian.TreeType_LOG, trillian.TreeType_PREORDERED_LOG}
 		info.tokens = len(req.GetLeafIndex())
@@ -391,30 +544,44 @@ func newRPCInfoForRequest(req interface{}) (*rpcInfo, error) {
 		}
 	case *trillian.GetSequencedLeafCountRequest:
 		info.treeTypes = []trillian.TreeType{trillian.TreeType_LOG, trillian.TreeType_PREORDERED_LOG}
+	case *trillian.StreamLeavesRequest:
+		info.treeTypes = []trillian.TreeType{trillian.TreeType_LOG, trillian.TreeType_PREORDERED_LOG}
+		info.tokens = 1
 
 	// Log / readwrite
 	case *trillian.QueueLeafRequest:
 		info.readonly = false
+		info.op = trees.QueueLog
 		info.treeTypes = []trillian.TreeType{trillian.TreeType_LOG}
 		info.tokens = 1
 	case *trillian.QueueLeavesRequest:
 		info.readonly = false
+		info.op = trees.QueueLog
 		info.treeTypes = []trillian.TreeType{trillian.TreeType_LOG}
 		info.tokens = len(req.GetLeaves())
 
 	// Pre-ordered Log / readwrite
 	case *trillian.AddSequencedLeafRequest:
 		info.readonly = false
+		info.op = trees.QueueLog
 		info.treeTypes = []trillian.TreeType{trillian.TreeType_PREORDERED_LOG}
 		info.tokens = 1
 	case *trillian.AddSequencedLeavesRequest:
 		info.readonly = false
+		info.op = trees.QueueLog
 		info.treeTypes = []trillian.TreeType{trillian.TreeType_PREORDERED_LOG}
 		info.tokens = len(req.GetLeaves())
 
 	// (Log + Pre-ordered Log) / readwrite
 	case *trillian.InitLogRequest:
 		info.readonly = false
+		info.op = trees.QueueLog
+		info.treeTypes = []trillian.TreeType{trillian.TreeType_LOG, trillian.TreeType_PREORDERED_LOG}
+		info.tokens = 1
+	case *trillian.AddLogRootCosignatureRequest:
+		// Cosignatures are kept by the server, so they're charged as writes.
+		info.readonly = false
+		info.op = trees.QueueLog
 		info.treeTypes = []trillian.TreeType{trillian.TreeType_LOG, trillian.TreeType_PREORDERED_LOG}
 		info.tokens = 1
 
@@ -431,6 +598,19 @@ func newRPCInfoForRequest(req interface{}) (*rpcInfo, error) {
 	case *trillian.GetMapLeavesRequest:
 		info.treeTypes = []trillian.TreeType{trillian.TreeType_MAP}
 		info.tokens = len(req.GetIndex())
+	case *trillian.GetMapDiffRequest:
+		info.treeTypes = []trillian.TreeType{trillian.TreeType_MAP}
+		info.tokens = 1
+	case *trillian.ListMapLeavesRequest:
+		info.treeTypes = []trillian.TreeType{trillian.TreeType_MAP}
+		info.tokens = 1
+		if c := req.GetPageSize(); c > 1 {
+			info.tokens = int(c)
+		}
+		// The map server never returns more than maxListMapLeavesTokens leaves.
+		if info.tokens > maxListMapLeavesTokens {
+			info.tokens = maxListMapLeavesTokens
+		}
 	case *trillian.GetSignedMapRootByRevisionRequest,
 		*trillian.GetSignedMapRootRequest:
 		info.treeTypes = []trillian.TreeType{trillian.TreeType_MAP}
@@ -439,10 +619,12 @@ func newRPCInfoForRequest(req interface{}) (*rpcInfo, error) {
 	// Map / readwrite
 	case *trillian.SetMapLeavesRequest:
 		info.readonly = false
+		info.op = trees.UpdateMap
 		info.treeTypes = []trillian.TreeType{trillian.TreeType_MAP}
 		info.tokens = len(req.GetLeaves())
 	case *trillian.InitMapRequest:
 		info.readonly = false
+		info.op = trees.UpdateMap
 		info.treeTypes = []trillian.TreeType{trillian.TreeType_MAP}
 		info.tokens = 1
 
@@ -459,17 +641,19 @@ func newRPCInfo(req interface{}) (*rpcInfo, error) {
 		return nil, err
 	}
 
-	if info.getTree || info.tokens > 0 {
-		switch req := req.(type) {
-		case logIDRequest:
-			info.treeID = req.GetLogId()
-		case mapIDRequest:
-			info.treeID = req.GetMapId()
-		case treeIDRequest:
-			info.treeID = req.GetTreeId()
-		case treeRequest:
-			info.treeID = req.GetTree().GetTreeId()
-		default:
+	// The treeID is also used for authorization, so it's read whenever the
+	// request has one.
+	switch req := req.(type) {
+	case logIDRequest:
+		info.treeID = req.GetLogId()
+	case mapIDRequest:
+		info.treeID = req.GetMapId()
+	case treeIDRequest:
+		info.treeID = req.GetTreeId()
+	case treeRequest:
+		info.treeID = req.GetTree().GetTreeId()
+	default:
+		if info.getTree || info.tokens > 0 {
 			return nil, status.Errorf(codes.Internal, "cannot retrieve treeID from request: %T", req)
 		}
 	}
@@ -520,6 +704,14 @@ func ErrorWrapper(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo,
 	return rsp, errors.WrapError(err)
 }
 
+// StreamErrorWrapper is a grpc.StreamServerInterceptor that wraps the errors emitted by the
+// underlying handler.
+func StreamErrorWrapper(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	_, spanEnd := spanFor(ss.Context(), "StreamErrorWrapper")
+	defer spanEnd()
+	return errors.WrapError(handler(srv, ss))
+}
+
 func spanFor(ctx context.Context, name string) (context.Context, func()) {
 	return monitoring.StartSpan(ctx, fmt.Sprintf("%s.%s", traceSpanRoot, name))
 }
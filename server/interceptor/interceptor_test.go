@@ -16,6 +16,7 @@ package interceptor
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"testing"
 	"time"
@@ -24,6 +25,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/google/trillian"
+	"github.com/google/trillian/auth"
 	"github.com/google/trillian/quota"
 	"github.com/google/trillian/quota/etcd/quotapb"
 	"github.com/google/trillian/storage"
@@ -78,8 +80,8 @@ func TestTrillianInterceptor_TreeInterception(t *testing.T) {
 		wantTree   *trillian.Tree
 		cancelled  bool
 	}{
-		// TODO(codingllama): Admin requests don't benefit from tree-reading logic, but we may read
-		// their tree IDs for auth purposes.
+		// Admin requests don't benefit from tree-reading logic, their tree IDs are only read for
+		// authorization.
 		{
 			desc:   "adminReadByID",
 			method: "/trillian.TrillianAdmin/GetTree",
@@ -148,7 +150,7 @@ func TestTrillianInterceptor_TreeInterception(t *testing.T) {
 			adminTX.EXPECT().Close().AnyTimes().Return(nil)
 			adminTX.EXPECT().Commit().AnyTimes().Return(nil)
 
-			intercept := New(admin, quota.Noop(), false /* quotaDryRun */, nil /* mf */)
+			intercept := New(admin, quota.Noop(), nil /* authz */, false /* quotaDryRun */, nil /* mf */)
 			handler := &fakeHandler{resp: "handler response", err: test.handlerErr}
 
 			if test.cancelled {
@@ -190,6 +192,207 @@ func TestTrillianInterceptor_TreeInterception(t *testing.T) {
 	}
 }
 
+// fakeAuthorizer records the arguments of Authorize and returns err.
+type fakeAuthorizer struct {
+	treeID int64
+	op     trees.OpType
+	called bool
+	err    error
+}
+
+func (f *fakeAuthorizer) Authorize(ctx context.Context, treeID int64, op trees.OpType) error {
+	f.treeID, f.op, f.called = treeID, op, true
+	return f.err
+}
+
+func TestTrillianInterceptor_Authorization(t *testing.T) {
+	logTree := proto.Clone(testonly.LogTree).(*trillian.Tree)
+	logTree.TreeId = 10
+	mapTree := proto.Clone(testonly.MapTree).(*trillian.Tree)
+	mapTree.TreeId = 11
+
+	tests := []struct {
+		desc       string
+		method     string
+		req        interface{}
+		deny       bool
+		wantTreeID int64
+		wantOp     trees.OpType
+	}{
+		{
+			desc:   "adminCreate",
+			method: "/trillian.TrillianAdmin/CreateTree",
+			req:    &trillian.CreateTreeRequest{Tree: &trillian.Tree{}},
+			wantOp: trees.Admin,
+		},
+		{
+			desc:   "adminList",
+			method: "/trillian.TrillianAdmin/ListTrees",
+			req:    &trillian.ListTreesRequest{},
+			wantOp: trees.Query,
+		},
+		{
+			desc:       "adminGet",
+			method:     "/trillian.TrillianAdmin/GetTree",
+			req:        &trillian.GetTreeRequest{TreeId: logTree.TreeId},
+			wantTreeID: logTree.TreeId,
+			wantOp:     trees.Query,
+		},
+		{
+			desc:       "adminDelete",
+			method:     "/trillian.TrillianAdmin/DeleteTree",
+			req:        &trillian.DeleteTreeRequest{TreeId: logTree.TreeId},
+			wantTreeID: logTree.TreeId,
+			wantOp:     trees.Admin,
+		},
+		{
+			desc:       "adminDeleteDenied",
+			method:     "/trillian.TrillianAdmin/DeleteTree",
+			req:        &trillian.DeleteTreeRequest{TreeId: logTree.TreeId},
+			deny:       true,
+			wantTreeID: logTree.TreeId,
+			wantOp:     trees.Admin,
+		},
+		{
+			desc:       "logRead",
+			method:     "/trillian.TrillianLog/GetLatestSignedLogRoot",
+			req:        &trillian.GetLatestSignedLogRootRequest{LogId: logTree.TreeId},
+			wantTreeID: logTree.TreeId,
+			wantOp:     trees.Query,
+		},
+		{
+			desc:       "logWrite",
+			method:     "/trillian.TrillianLog/QueueLeaves",
+			req:        &trillian.QueueLeavesRequest{LogId: logTree.TreeId},
+			wantTreeID: logTree.TreeId,
+			wantOp:     trees.QueueLog,
+		},
+		{
+			desc:       "logWriteDenied",
+			method:     "/trillian.TrillianLog/QueueLeaves",
+			req:        &trillian.QueueLeavesRequest{LogId: logTree.TreeId},
+			deny:       true,
+			wantTreeID: logTree.TreeId,
+			wantOp:     trees.QueueLog,
+		},
+		{
+			desc:       "logCosignDenied",
+			method:     "/trillian.TrillianLog/AddLogRootCosignature",
+			req:        &trillian.AddLogRootCosignatureRequest{LogId: logTree.TreeId},
+			deny:       true,
+			wantTreeID: logTree.TreeId,
+			wantOp:     trees.QueueLog,
+		},
+		{
+			desc:       "mapRead",
+			method:     "/trillian.TrillianMap/GetSignedMapRoot",
+			req:        &trillian.GetSignedMapRootRequest{MapId: mapTree.TreeId},
+			wantTreeID: mapTree.TreeId,
+			wantOp:     trees.Query,
+		},
+		{
+			desc:       "mapWriteDenied",
+			method:     "/trillian.TrillianMap/SetLeaves",
+			req:        &trillian.SetMapLeavesRequest{MapId: mapTree.TreeId},
+			deny:       true,
+			wantTreeID: mapTree.TreeId,
+			wantOp:     trees.UpdateMap,
+		},
+		{
+			desc:   "sequencerMastership",
+			method: "/trillian.TrillianLogSequencer/GetMastership",
+			req:    &trillian.GetMastershipRequest{},
+			wantOp: trees.Query,
+		},
+		{
+			desc:       "sequencerQueueSize",
+			method:     "/trillian.TrillianLogSequencer/GetQueueSize",
+			req:        &trillian.GetQueueSizeRequest{LogId: logTree.TreeId},
+			wantTreeID: logTree.TreeId,
+			wantOp:     trees.Query,
+		},
+		{
+			desc:       "sequencerPause",
+			method:     "/trillian.TrillianLogSequencer/PauseSequencing",
+			req:        &trillian.PauseSequencingRequest{LogId: logTree.TreeId},
+			wantTreeID: logTree.TreeId,
+			wantOp:     trees.Admin,
+		},
+		{
+			desc:       "sequencerIntegrateDenied",
+			method:     "/trillian.TrillianLogSequencer/IntegrateBatch",
+			req:        &trillian.IntegrateBatchRequest{LogId: logTree.TreeId},
+			deny:       true,
+			wantTreeID: logTree.TreeId,
+			wantOp:     trees.Admin,
+		},
+		{
+			desc:   "quotaList",
+			method: "/trillian.Quota/ListQuotaConfigs",
+			req:    &trillian.ListQuotaConfigsRequest{},
+			wantOp: trees.Admin,
+		},
+		{
+			desc:   "quotaUpdateDenied",
+			method: "/trillian.Quota/UpdateQuotaConfig",
+			req:    &trillian.UpdateQuotaConfigRequest{},
+			deny:   true,
+			wantOp: trees.Admin,
+		},
+		{
+			desc:   "etcdQuotaDeleteDenied",
+			method: "/quotapb.Quota/DeleteConfig",
+			req:    &quotapb.DeleteConfigRequest{},
+			deny:   true,
+			wantOp: trees.Admin,
+		},
+	}
+
+	ctx := context.Background()
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			admin := storage.NewMockAdminStorage(ctrl)
+			if !test.deny {
+				// Denied requests must not read the tree.
+				adminTX := storage.NewMockReadOnlyAdminTX(ctrl)
+				admin.EXPECT().Snapshot(gomock.Any()).AnyTimes().Return(adminTX, nil)
+				adminTX.EXPECT().GetTree(gomock.Any(), logTree.TreeId).AnyTimes().Return(logTree, nil)
+				adminTX.EXPECT().GetTree(gomock.Any(), mapTree.TreeId).AnyTimes().Return(mapTree, nil)
+				adminTX.EXPECT().Close().AnyTimes().Return(nil)
+				adminTX.EXPECT().Commit().AnyTimes().Return(nil)
+			}
+
+			authz := &fakeAuthorizer{}
+			if test.deny {
+				authz.err = status.Error(codes.PermissionDenied, "denied")
+			}
+			intercept := New(admin, quota.Noop(), authz, false /* quotaDryRun */, nil /* mf */)
+			handler := &fakeHandler{resp: "handler response"}
+
+			_, err := intercept.UnaryInterceptor(ctx, test.req, &grpc.UnaryServerInfo{FullMethod: test.method}, handler.run)
+			if test.deny {
+				if status.Code(err) != codes.PermissionDenied {
+					t.Errorf("UnaryInterceptor() returned err = %v, want code %v", err, codes.PermissionDenied)
+				}
+				if handler.called {
+					t.Error("handler called for denied request")
+				}
+			} else if err != nil {
+				t.Errorf("UnaryInterceptor() returned err = %v", err)
+			}
+
+			if !authz.called {
+				t.Fatal("Authorize() not called")
+			}
+			if authz.treeID != test.wantTreeID || authz.op != test.wantOp {
+				t.Errorf("Authorize(_, %v, %v), want Authorize(_, %v, %v)", authz.treeID, authz.op, test.wantTreeID, test.wantOp)
+			}
+		})
+	}
+}
+
 func TestTrillianInterceptor_QuotaInterception(t *testing.T) {
 
 	logTree := *testonly.LogTree
@@ -234,6 +437,26 @@ func TestTrillianInterceptor_QuotaInterception(t *testing.T) {
 			},
 			wantTokens: 3,
 		},
+		{
+			desc:   "logBatchInclusionProof",
+			method: "/trillian.TrillianLog/GetBatchInclusionProof",
+			req:    &trillian.GetBatchInclusionProofRequest{LogId: logTree.TreeId, LeafIndex: []int64{1, 2, 3, 4}, TreeSize: 10},
+			specs: []quota.Spec{
+				{Group: quota.Tree, Kind: quota.Read, TreeID: logTree.TreeId},
+				{Group: quota.Global, Kind: quota.Read},
+			},
+			wantTokens: 4,
+		},
+		{
+			desc:   "logRangeInclusionProof",
+			method: "/trillian.TrillianLog/GetRangeInclusionProof",
+			req:    &trillian.GetRangeInclusionProofRequest{LogId: logTree.TreeId, StartIndex: 10, Count: 123, TreeSize: 200},
+			specs: []quota.Spec{
+				{Group: quota.Tree, Kind: quota.Read, TreeID: logTree.TreeId},
+				{Group: quota.Global, Kind: quota.Read},
+			},
+			wantTokens: 1,
+		},
 		{
 			desc:   "logReadRange",
 			method: "/trillian.TrillianLog/GetLeavesByRange",
@@ -264,6 +487,26 @@ func TestTrillianInterceptor_QuotaInterception(t *testing.T) {
 			},
 			wantTokens: 1,
 		},
+		{
+			desc:   "logStream",
+			method: "/trillian.TrillianLog/StreamLeaves",
+			req:    &trillian.StreamLeavesRequest{LogId: logTree.TreeId, StartIndex: 123},
+			specs: []quota.Spec{
+				{Group: quota.Tree, Kind: quota.Read, TreeID: logTree.TreeId},
+				{Group: quota.Global, Kind: quota.Read},
+			},
+			wantTokens: 1,
+		},
+		{
+			desc:   "logCosign",
+			method: "/trillian.TrillianLog/AddLogRootCosignature",
+			req:    &trillian.AddLogRootCosignatureRequest{LogId: logTree.TreeId},
+			specs: []quota.Spec{
+				{Group: quota.Tree, Kind: quota.Write, TreeID: logTree.TreeId},
+				{Group: quota.Global, Kind: quota.Write},
+			},
+			wantTokens: 1,
+		},
 		{
 			desc:   "logRead with charges",
 			method: "/trillian.TrillianLog/GetLatestSignedLogRoot",
@@ -308,6 +551,36 @@ func TestTrillianInterceptor_QuotaInterception(t *testing.T) {
 			},
 			wantTokens: 2,
 		},
+		{
+			desc:   "mapDiff",
+			method: "/trillian.TrillianMap/GetMapDiff",
+			req:    &trillian.GetMapDiffRequest{MapId: mapTree.TreeId, FromRevision: 1, ToRevision: 5},
+			specs: []quota.Spec{
+				{Group: quota.Tree, Kind: quota.Read, TreeID: mapTree.TreeId},
+				{Group: quota.Global, Kind: quota.Read},
+			},
+			wantTokens: 1,
+		},
+		{
+			desc:   "mapList",
+			method: "/trillian.TrillianMap/ListLeaves",
+			req:    &trillian.ListMapLeavesRequest{MapId: mapTree.TreeId, PageSize: 50},
+			specs: []quota.Spec{
+				{Group: quota.Tree, Kind: quota.Read, TreeID: mapTree.TreeId},
+				{Group: quota.Global, Kind: quota.Read},
+			},
+			wantTokens: 50,
+		},
+		{
+			desc:   "mapListLargePage",
+			method: "/trillian.TrillianMap/ListLeaves",
+			req:    &trillian.ListMapLeavesRequest{MapId: mapTree.TreeId, PageSize: 1000000},
+			specs: []quota.Spec{
+				{Group: quota.Tree, Kind: quota.Read, TreeID: mapTree.TreeId},
+				{Group: quota.Global, Kind: quota.Read},
+			},
+			wantTokens: maxListMapLeavesTokens,
+		},
 		{
 			desc:   "emptyBatchRequest",
 			method: "/trillian.TrillianLog/QueueLeaves",
@@ -417,7 +690,7 @@ func TestTrillianInterceptor_QuotaInterception(t *testing.T) {
 			}
 
 			handler := &fakeHandler{resp: "ok"}
-			intercept := New(admin, qm, test.dryRun, nil /* mf */)
+			intercept := New(admin, qm, nil /* authz */, test.dryRun, nil /* mf */)
 
 			// resp and handler assertions are done by TestTrillianInterceptor_TreeInterception,
 			// we're only concerned with the quota logic here.
@@ -577,7 +850,7 @@ func TestTrillianInterceptor_QuotaInterception_ReturnsTokens(t *testing.T) {
 			}
 
 			handler := &fakeHandler{resp: test.resp, err: test.handlerErr}
-			intercept := New(admin, qm, false /* quotaDryRun */, nil /* mf */)
+			intercept := New(admin, qm, nil /* authz */, false /* quotaDryRun */, nil /* mf */)
 
 			if _, err := intercept.UnaryInterceptor(ctx, test.req,
 				&grpc.UnaryServerInfo{FullMethod: test.method},
@@ -596,6 +869,187 @@ func TestTrillianInterceptor_QuotaInterception_ReturnsTokens(t *testing.T) {
 	}
 }
 
+func TestTrillianInterceptor_StreamInterceptor(t *testing.T) {
+	logTree := *testonly.LogTree
+	logTree.TreeId = 10
+	specs := []quota.Spec{
+		{Group: quota.Tree, Kind: quota.Read, TreeID: logTree.TreeId},
+		{Group: quota.Global, Kind: quota.Read},
+	}
+	leaves := func(n int) *trillian.StreamLeavesResponse {
+		return &trillian.StreamLeavesResponse{Leaves: make([]*trillian.LogLeaf, n)}
+	}
+
+	tests := []struct {
+		desc         string
+		req          proto.Message
+		resps        []*trillian.StreamLeavesResponse
+		getTokensErr error
+		dryRun       bool
+		wantTokens   []int
+		wantSent     int
+		wantCode     codes.Code
+		wantTree     bool
+	}{
+		{
+			desc:     "noMessages",
+			req:      &trillian.StreamLeavesRequest{LogId: logTree.TreeId},
+			wantTree: true,
+		},
+		{
+			desc:       "messages",
+			req:        &trillian.StreamLeavesRequest{LogId: logTree.TreeId},
+			resps:      []*trillian.StreamLeavesResponse{leaves(0), leaves(1), leaves(5)},
+			wantTokens: []int{1, 1, 5},
+			wantSent:   3,
+			wantTree:   true,
+		},
+		{
+			desc:     "unknownTree",
+			req:      &trillian.StreamLeavesRequest{LogId: 1234},
+			resps:    []*trillian.StreamLeavesResponse{leaves(1)},
+			wantCode: codes.NotFound,
+		},
+		{
+			desc:         "quotaError",
+			req:          &trillian.StreamLeavesRequest{LogId: logTree.TreeId},
+			resps:        []*trillian.StreamLeavesResponse{leaves(2), leaves(3)},
+			getTokensErr: errors.New("not enough tokens"),
+			wantTokens:   []int{2},
+			wantCode:     codes.ResourceExhausted,
+			wantTree:     true,
+		},
+		{
+			desc:         "quotaErrorDryRun",
+			req:          &trillian.StreamLeavesRequest{LogId: logTree.TreeId},
+			resps:        []*trillian.StreamLeavesResponse{leaves(2), leaves(3)},
+			getTokensErr: errors.New("not enough tokens"),
+			dryRun:       true,
+			wantTokens:   []int{2, 3},
+			wantSent:     2,
+			wantTree:     true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			admin := storage.NewMockAdminStorage(ctrl)
+			adminTX := storage.NewMockReadOnlyAdminTX(ctrl)
+			admin.EXPECT().Snapshot(gomock.Any()).AnyTimes().Return(adminTX, nil)
+			adminTX.EXPECT().GetTree(gomock.Any(), logTree.TreeId).AnyTimes().Return(&logTree, nil)
+			adminTX.EXPECT().GetTree(gomock.Any(), gomock.Any()).AnyTimes().Return(nil, status.Error(codes.NotFound, "not found"))
+			adminTX.EXPECT().Close().AnyTimes().Return(nil)
+			adminTX.EXPECT().Commit().AnyTimes().Return(nil)
+
+			qm := quota.NewMockManager(ctrl)
+			var calls []*gomock.Call
+			for _, tokens := range test.wantTokens {
+				calls = append(calls, qm.EXPECT().GetTokens(gomock.Any(), tokens, specs).Return(test.getTokensErr))
+			}
+			gomock.InOrder(calls...)
+			intercept := New(admin, qm, nil /* authz */, test.dryRun, nil /* mf */)
+
+			ss := &fakeServerStream{ctx: context.Background(), req: test.req}
+			var gotTree *trillian.Tree
+			handler := func(srv interface{}, stream grpc.ServerStream) error {
+				req := &trillian.StreamLeavesRequest{}
+				if err := stream.RecvMsg(req); err != nil {
+					return err
+				}
+				if !proto.Equal(req, test.req) {
+					t.Errorf("RecvMsg() = %v, want %v", req, test.req)
+				}
+				gotTree, _ = trees.FromContext(stream.Context())
+				for _, resp := range test.resps {
+					if err := stream.SendMsg(resp); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+
+			err := intercept.StreamInterceptor(nil, ss,
+				&grpc.StreamServerInfo{FullMethod: "/trillian.TrillianLog/StreamLeaves", IsServerStream: true},
+				handler)
+			if got := status.Code(err); got != test.wantCode {
+				t.Errorf("StreamInterceptor() returned err = %v, wantCode = %v", err, test.wantCode)
+			}
+			if gotTree != nil != test.wantTree {
+				t.Errorf("handler got tree = %v, want tree = %v", gotTree, test.wantTree)
+			}
+			if got := len(ss.sent); got != test.wantSent {
+				t.Errorf("sent %d messages, want %d", got, test.wantSent)
+			}
+		})
+	}
+}
+
+func TestStreamErrorWrapper(t *testing.T) {
+	badLlamaErr := status.Errorf(codes.InvalidArgument, "Bad Llama")
+	tests := []struct {
+		desc         string
+		err, wantErr error
+	}{
+		{
+			desc: "success",
+		},
+		{
+			desc:    "error",
+			err:     badLlamaErr,
+			wantErr: serrors.WrapError(badLlamaErr),
+		},
+		{
+			desc:    "noRows",
+			err:     sql.ErrNoRows,
+			wantErr: serrors.WrapError(sql.ErrNoRows),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			ss := &fakeServerStream{ctx: context.Background()}
+			err := StreamErrorWrapper(nil, ss, &grpc.StreamServerInfo{}, func(srv interface{}, stream grpc.ServerStream) error {
+				return test.err
+			})
+			if diff := pretty.Compare(err, test.wantErr); diff != "" {
+				t.Errorf("post-WrapErrors diff:\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestTrillianInterceptor_DenyAllPolicy(t *testing.T) {
+	authz, err := auth.NewPolicyAuthorizer(&auth.Policy{})
+	if err != nil {
+		t.Fatalf("NewPolicyAuthorizer(): %v", err)
+	}
+
+	tests := []struct {
+		method string
+		req    interface{}
+	}{
+		{method: "/trillian.TrillianAdmin/CreateTree", req: &trillian.CreateTreeRequest{Tree: &trillian.Tree{}}},
+		{method: "/quotapb.Quota/CreateConfig", req: &quotapb.CreateConfigRequest{}},
+		{method: "/quotapb.Quota/UpdateConfig", req: &quotapb.UpdateConfigRequest{}},
+		{method: "/quotapb.Quota/DeleteConfig", req: &quotapb.DeleteConfigRequest{}},
+		{method: "/Quota.DeleteConfig", req: &quotapb.DeleteConfigRequest{}},
+	}
+
+	ctx := context.Background()
+	for _, test := range tests {
+		handler := &fakeHandler{}
+		intercept := New(nil /* admin */, quota.Noop(), authz, false /* quotaDryRun */, nil /* mf */)
+		_, err := intercept.UnaryInterceptor(ctx, test.req, &grpc.UnaryServerInfo{FullMethod: test.method}, handler.run)
+		if status.Code(err) != codes.Unauthenticated {
+			t.Errorf("UnaryInterceptor(%v) returned err = %v, want code %v", test.method, err, codes.Unauthenticated)
+		}
+		if handler.called {
+			t.Errorf("UnaryInterceptor(%v): handler called for unauthenticated request", test.method)
+		}
+	}
+}
+
 func TestTrillianInterceptor_NotIntercepted(t *testing.T) {
 	tests := []struct {
 		method string
@@ -615,7 +1069,7 @@ func TestTrillianInterceptor_NotIntercepted(t *testing.T) {
 	ctx := context.Background()
 	for _, test := range tests {
 		handler := &fakeHandler{}
-		intercept := New(nil /* admin */, quota.Noop(), false /* quotaDryRun */, nil /* mf */)
+		intercept := New(nil /* admin */, quota.Noop(), nil /* authz */, false /* quotaDryRun */, nil /* mf */)
 		if _, err := intercept.UnaryInterceptor(ctx, test.req,
 			&grpc.UnaryServerInfo{FullMethod: test.method},
 			handler.run); err != nil {
@@ -668,7 +1122,7 @@ func TestTrillianInterceptor_BeforeAfter(t *testing.T) {
 			adminTX.EXPECT().Close().AnyTimes().Return(nil)
 			adminTX.EXPECT().Commit().AnyTimes().Return(nil)
 
-			intercept := New(admin, qm, false /* quotaDryRun */, nil /* mf */)
+			intercept := New(admin, qm, nil /* authz */, false /* quotaDryRun */, nil /* mf */)
 			p := intercept.NewProcessor()
 
 			_, err := p.Before(ctx, test.req, "/trillian.TrillianLog/foo")
@@ -868,3 +1322,26 @@ func (f *fakeInterceptor) run(ctx context.Context, req interface{}, info *grpc.U
 	}
 	return handler(context.WithValue(ctx, f.key, f.val), req)
 }
+
+// fakeServerStream is a grpc.ServerStream that receives a single request, and
+// records the messages sent.
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	req  proto.Message
+	sent []interface{}
+}
+
+func (f *fakeServerStream) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeServerStream) RecvMsg(m interface{}) error {
+	proto.Merge(m.(proto.Message), f.req)
+	return nil
+}
+
+func (f *fakeServerStream) SendMsg(m interface{}) error {
+	f.sent = append(f.sent, m)
+	return nil
+}
@@ -15,13 +15,21 @@
 package server
 
 import (
+	"bytes"
 	"context"
+	"crypto"
 	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/golang/glog"
 	"github.com/google/trillian"
+	"github.com/google/trillian/crypto/keys/der"
 	"github.com/google/trillian/extension"
 	"github.com/google/trillian/merkle"
+	"github.com/google/trillian/merkle/compact"
 	"github.com/google/trillian/merkle/hashers"
 	"github.com/google/trillian/monitoring"
 	"github.com/google/trillian/storage"
@@ -30,6 +38,8 @@ import (
 	"github.com/google/trillian/util/clock"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+
+	tcrypto "github.com/google/trillian/crypto"
 )
 
 // TODO: There is no access control in the server yet and clients could easily modify
@@ -46,6 +56,17 @@ var (
 	optsLogRead            = trees.NewGetOpts(trees.Query, trillian.TreeType_LOG, trillian.TreeType_PREORDERED_LOG)
 	optsLogWrite           = trees.NewGetOpts(trees.QueueLog, trillian.TreeType_LOG)
 	optsPreorderedLogWrite = trees.NewGetOpts(trees.SequenceLog, trillian.TreeType_PREORDERED_LOG)
+
+	// StreamLeavesPollInterval is how long StreamLeaves waits before checking
+	// for a new log root, once it has sent all the leaves available.
+	StreamLeavesPollInterval = 1 * time.Second
+	// StreamLeavesBatchSize is the maximum number of leaves sent in a single
+	// StreamLeavesResponse.
+	StreamLeavesBatchSize int64 = 1000
+
+	// QueueDepthRefreshInterval is how often the unsequenced queue depths are
+	// re-read from storage when enforcing Tree.MaxQueueDepth.
+	QueueDepthRefreshInterval = 5 * time.Second
 )
 
 // TrillianLogRPCServer implements the RPC API defined in the proto
@@ -53,7 +74,22 @@ type TrillianLogRPCServer struct {
 	registry              extension.Registry
 	timeSource            clock.TimeSource
 	leafCounter           monitoring.Counter
+	queueFullCounter      monitoring.Counter
 	proofIndexPercentiles monitoring.Histogram
+
+	// queueMu guards the cached queue depths below, which are used to enforce
+	// Tree.MaxQueueDepth without counting the queue on every request.
+	queueMu        sync.Mutex
+	queueDepths    storage.CountByLogID
+	queueDepthTime time.Time
+	// queueRefresh is set while a request reads the queue depths from
+	// storage, so that other requests keep using the cached depths.
+	queueRefresh bool
+
+	// witnesses holds the public keys of the witnesses allowed to cosign log
+	// roots, keyed by witness name.
+	witnesses    map[string]crypto.PublicKey
+	cosignatures cosignatureCache
 }
 
 // NewTrillianLogRPCServer creates a new RPC server backed by a LogStorageProvider.
@@ -70,6 +106,11 @@ func NewTrillianLogRPCServer(registry extension.Registry, timeSource clock.TimeS
 			"Number of leaves requested to be queued",
 			"status",
 		),
+		queueFullCounter: mf.NewCounter(
+			"queue_full_rejections",
+			"Number of QueueLeaves requests rejected because the queue was full",
+			monitoring.TreeIDLabel,
+		),
 		proofIndexPercentiles: mf.NewHistogramWithBuckets(
 			"proof_index_percentiles",
 			"Count of inclusion proof request index using percentage of current log size at the time",
@@ -78,6 +119,14 @@ func NewTrillianLogRPCServer(registry extension.Registry, timeSource clock.TimeS
 	}
 }
 
+// SetWitnesses sets the witnesses whose cosignatures are accepted by
+// AddLogRootCosignature, keyed by witness name. Witnesses sign with SHA-256
+// (Ed25519 witnesses sign the full message). It must be called before the
+// server starts handling requests.
+func (t *TrillianLogRPCServer) SetWitnesses(witnesses map[string]crypto.PublicKey) {
+	t.witnesses = witnesses
+}
+
 // IsHealthy returns nil if the server is healthy, error otherwise.
 func (t *TrillianLogRPCServer) IsHealthy() error {
 	ctx, spanEnd := spanFor(context.Background(), "IsHealthy")
@@ -135,6 +184,10 @@ func (t *TrillianLogRPCServer) QueueLeaves(ctx context.Context, req *trillian.Qu
 
 	ctx = trees.NewContext(ctx, tree)
 
+	if err := t.checkQueueDepth(ctx, tree); err != nil {
+		return nil, err
+	}
+
 	hashLeaves(req.Leaves, hasher)
 
 	ret, err := t.registry.LogStorage.QueueLeaves(ctx, tree, req.Leaves, t.timeSource.Now())
@@ -142,16 +195,97 @@ func (t *TrillianLogRPCServer) QueueLeaves(ctx context.Context, req *trillian.Qu
 		return nil, err
 	}
 
+	var queued int64
 	for _, l := range ret {
 		if l.Status == nil || l.Status.Code == int32(codes.OK) {
 			t.leafCounter.Inc("new")
+			queued++
 		} else if l.Status.Code == int32(codes.AlreadyExists) {
 			t.leafCounter.Inc("existing")
 		}
 	}
+	t.addQueueDepth(tree, queued)
 	return &trillian.QueueLeavesResponse{QueuedLeaves: ret}, nil
 }
 
+// checkQueueDepth returns a ResourceExhausted error if the tree has a
+// MaxQueueDepth and its unsequenced queue is at or above it.
+func (t *TrillianLogRPCServer) checkQueueDepth(ctx context.Context, tree *trillian.Tree) error {
+	if tree.MaxQueueDepth <= 0 {
+		return nil
+	}
+
+	depth, err := t.queueDepth(ctx, tree.TreeId)
+	if err != nil {
+		return err
+	}
+	if depth >= tree.MaxQueueDepth {
+		t.queueFullCounter.Inc(strconv.FormatInt(tree.TreeId, 10))
+		return status.Errorf(codes.ResourceExhausted, "queue for log %d is full: %d leaves queued, max_queue_depth is %d", tree.TreeId, depth, tree.MaxQueueDepth)
+	}
+	return nil
+}
+
+// queueDepth returns the cached queue depth of a tree, refreshing the cache
+// from storage if it's stale. Storage is read without holding queueMu, and
+// while one request refreshes the cache the others use the stale depths, so
+// QueueLeaves requests don't wait on the query.
+func (t *TrillianLogRPCServer) queueDepth(ctx context.Context, treeID int64) (int64, error) {
+	t.queueMu.Lock()
+	now := t.timeSource.Now()
+	stale := t.queueDepths == nil || now.Sub(t.queueDepthTime) >= QueueDepthRefreshInterval
+	if !stale || (t.queueRefresh && t.queueDepths != nil) {
+		depth := t.queueDepths[treeID]
+		t.queueMu.Unlock()
+		return depth, nil
+	}
+	t.queueRefresh = true
+	t.queueMu.Unlock()
+
+	depths, err := t.readQueueDepths(ctx)
+
+	t.queueMu.Lock()
+	defer t.queueMu.Unlock()
+	t.queueRefresh = false
+	if err != nil {
+		return 0, err
+	}
+	// Leaves queued during the read may be missing from depths, but they'll be
+	// counted by the next refresh.
+	t.queueDepths = depths
+	t.queueDepthTime = now
+	return depths[treeID], nil
+}
+
+// addQueueDepth accounts for newly queued leaves in the cached queue depth of
+// the tree, so that the limit holds between refreshes.
+func (t *TrillianLogRPCServer) addQueueDepth(tree *trillian.Tree, n int64) {
+	if tree.MaxQueueDepth <= 0 || n == 0 {
+		return
+	}
+	t.queueMu.Lock()
+	defer t.queueMu.Unlock()
+	if t.queueDepths != nil {
+		t.queueDepths[tree.TreeId] += n
+	}
+}
+
+func (t *TrillianLogRPCServer) readQueueDepths(ctx context.Context) (storage.CountByLogID, error) {
+	tx, err := t.registry.LogStorage.Snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Close()
+	depths, err := tx.GetUnsequencedCounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return depths, nil
+}
+
 // AddSequencedLeaf submits one sequenced leaf to the storage.
 func (t *TrillianLogRPCServer) AddSequencedLeaf(ctx context.Context, req *trillian.AddSequencedLeafRequest) (*trillian.AddSequencedLeafResponse, error) {
 	ctx, spanEnd := spanFor(ctx, "AddSequencedLeaf")
@@ -259,6 +393,126 @@ func (t *TrillianLogRPCServer) GetInclusionProof(ctx context.Context, req *trill
 	return r, nil
 }
 
+// GetBatchInclusionProof obtains a single proof of inclusion for several leaves
+// by index, in which the proof nodes shared by the leaves appear only once.
+func (t *TrillianLogRPCServer) GetBatchInclusionProof(ctx context.Context, req *trillian.GetBatchInclusionProofRequest) (*trillian.GetBatchInclusionProofResponse, error) {
+	ctx, spanEnd := spanFor(ctx, "GetBatchInclusionProof")
+	defer spanEnd()
+	if err := validateGetBatchInclusionProofRequest(req); err != nil {
+		return nil, err
+	}
+
+	tree, hasher, err := t.getTreeAndHasher(ctx, req.LogId, optsLogRead)
+	if err != nil {
+		return nil, err
+	}
+	ctx = trees.NewContext(ctx, tree)
+
+	// As for GetInclusionProof, the requested tree size must correspond to an
+	// STH so that we have a usable tree revision.
+	tx, err := t.snapshotForTree(ctx, tree, "GetBatchInclusionProof")
+	if err != nil {
+		return nil, err
+	}
+	defer t.closeAndLog(ctx, tree.TreeId, tx, "GetBatchInclusionProof")
+
+	slr, err := tx.LatestSignedLogRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var root types.LogRootV1
+	if err := root.UnmarshalBinary(slr.LogRoot); err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not read current log root: %v", err)
+	}
+
+	r := &trillian.GetBatchInclusionProofResponse{SignedLogRoot: &slr}
+
+	if uint64(req.TreeSize) > root.TreeSize {
+		return r, nil
+	}
+
+	// The batch proof is defined over the distinct leaf indices, in order.
+	indices := make([]int64, len(req.LeafIndex))
+	copy(indices, req.LeafIndex)
+	sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+	distinct := indices[:0]
+	for i, index := range indices {
+		if i == 0 || index != indices[i-1] {
+			distinct = append(distinct, index)
+		}
+	}
+
+	hashes, err := getBatchInclusionProofForLeafIndices(ctx, tx, hasher, req.TreeSize, distinct, int64(root.TreeSize))
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range distinct {
+		t.recordIndexPercent(index, root.TreeSize)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	r.LeafIndex = distinct
+	r.Hashes = hashes
+
+	return r, nil
+}
+
+// GetRangeInclusionProof obtains a proof of inclusion for a contiguous range
+// of leaves, consisting of the compact ranges on either side of it.
+func (t *TrillianLogRPCServer) GetRangeInclusionProof(ctx context.Context, req *trillian.GetRangeInclusionProofRequest) (*trillian.GetRangeInclusionProofResponse, error) {
+	ctx, spanEnd := spanFor(ctx, "GetRangeInclusionProof")
+	defer spanEnd()
+	if err := validateGetRangeInclusionProofRequest(req); err != nil {
+		return nil, err
+	}
+
+	tree, ctx, err := t.getTreeAndContext(ctx, req.LogId, optsLogRead)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := t.snapshotForTree(ctx, tree, "GetRangeInclusionProof")
+	if err != nil {
+		return nil, err
+	}
+	defer t.closeAndLog(ctx, tree.TreeId, tx, "GetRangeInclusionProof")
+
+	slr, err := tx.LatestSignedLogRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var root types.LogRootV1
+	if err := root.UnmarshalBinary(slr.LogRoot); err != nil {
+		return nil, status.Errorf(codes.Internal, "Could not read current log root: %v", err)
+	}
+
+	r := &trillian.GetRangeInclusionProofResponse{SignedLogRoot: &slr}
+
+	if uint64(req.TreeSize) > root.TreeSize {
+		return r, nil
+	}
+
+	rev, err := tx.ReadRevision(ctx)
+	if err != nil {
+		return nil, err
+	}
+	begin, end := uint64(req.StartIndex), uint64(req.StartIndex+req.Count)
+	if r.LeftHashes, err = fetchRangeHashes(ctx, tx, rev, compact.RangeNodes(0, begin)); err != nil {
+		return nil, err
+	}
+	if r.RightHashes, err = fetchRangeHashes(ctx, tx, rev, compact.RangeNodes(end, uint64(req.TreeSize))); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
 // GetInclusionProofByHash obtains proofs of inclusion by leaf hash. Because some logs can
 // contain duplicate hashes it is possible for multiple proofs to be returned.
 func (t *TrillianLogRPCServer) GetInclusionProofByHash(ctx context.Context, req *trillian.GetInclusionProofByHashRequest) (*trillian.GetInclusionProofByHashResponse, error) {
@@ -407,9 +661,24 @@ func (t *TrillianLogRPCServer) GetLatestSignedLogRoot(ctx context.Context, req *
 	}
 
 	r := &trillian.GetLatestSignedLogRootResponse{SignedLogRoot: &slr}
+	treeSize := root.TreeSize
+	if req.Cosigned {
+		cosigned, cosigs := t.cosignatures.latest(tree.TreeId)
+		if cosigned == nil {
+			return nil, status.Errorf(codes.NotFound, "no root of log %d has been cosigned", tree.TreeId)
+		}
+		var cosignedRoot types.LogRootV1
+		if err := cosignedRoot.UnmarshalBinary(cosigned.LogRoot); err != nil {
+			return nil, status.Errorf(codes.Internal, "Could not read cosigned log root: %v", err)
+		}
+		r.SignedLogRoot, r.Cosignatures, treeSize = cosigned, cosigs, cosignedRoot.TreeSize
+	} else {
+		r.Cosignatures = t.cosignatures.cosignatures(tree.TreeId, &slr)
+	}
 
-	if req.FirstTreeSize == 0 {
-		// no need to get consistency proof in this case
+	// No need to get a consistency proof if none was requested, or if this
+	// server hasn't caught up with the cosigned root yet.
+	if req.FirstTreeSize == 0 || treeSize > root.TreeSize {
 		if err := t.commitAndLog(ctx, req.LogId, tx, "GetLatestSignedLogRoot"); err != nil {
 			return nil, err
 		}
@@ -419,7 +688,7 @@ func (t *TrillianLogRPCServer) GetLatestSignedLogRoot(ctx context.Context, req *
 	reqProof := &trillian.GetConsistencyProofRequest{
 		LogId:          req.LogId,
 		FirstTreeSize:  int64(req.FirstTreeSize),
-		SecondTreeSize: int64(root.TreeSize),
+		SecondTreeSize: int64(treeSize),
 	}
 	if err := validateGetConsistencyProofRequest(reqProof); err != nil {
 		return nil, err
@@ -437,6 +706,61 @@ func (t *TrillianLogRPCServer) GetLatestSignedLogRoot(ctx context.Context, req *
 	return r, nil
 }
 
+// AddLogRootCosignature records a witness's cosignature of a log root, so
+// that it can be returned alongside the root by GetLatestSignedLogRoot.
+func (t *TrillianLogRPCServer) AddLogRootCosignature(ctx context.Context, req *trillian.AddLogRootCosignatureRequest) (*trillian.AddLogRootCosignatureResponse, error) {
+	ctx, spanEnd := spanFor(ctx, "AddLogRootCosignature")
+	defer spanEnd()
+	if err := validateAddLogRootCosignatureRequest(req); err != nil {
+		return nil, err
+	}
+	tree, _, err := t.getTreeAndContext(ctx, req.LogId, optsLogRead)
+	if err != nil {
+		return nil, err
+	}
+
+	witness := req.Cosignature.Witness
+	witnessKey, ok := t.witnesses[witness]
+	if !ok {
+		return nil, status.Errorf(codes.PermissionDenied, "unknown witness %q", witness)
+	}
+	logKey, err := logPublicKey(tree, req.SignedLogRoot.KeyHint)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to parse public key of log %d: %v", tree.TreeId, err)
+	}
+	hash, err := trees.Hash(tree)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get signature hash of log %d: %v", tree.TreeId, err)
+	}
+	root, err := tcrypto.VerifySignedLogRoot(logKey, hash, req.SignedLogRoot)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "log root not signed by log %d: %v", tree.TreeId, err)
+	}
+	if err := tcrypto.VerifyCosignature(witnessKey, crypto.SHA256, tree.TreeId, req.SignedLogRoot, req.Cosignature.Signature); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid cosignature from witness %q: %v", witness, err)
+	}
+	if err := t.cosignatures.add(tree.TreeId, req.SignedLogRoot, root, req.Cosignature); err != nil {
+		return nil, err
+	}
+	return &trillian.AddLogRootCosignatureResponse{}, nil
+}
+
+// logPublicKey returns the public key which verifies the roots of tree signed
+// with the given key hint. This is one of the tree's previous keys if the hint
+// names one, and its current key otherwise.
+func logPublicKey(tree *trillian.Tree, keyHint []byte) (crypto.PublicKey, error) {
+	pubKey := tree.PublicKey
+	if _, keyID, err := types.ParseKeyVersionHint(keyHint); err == nil {
+		for _, key := range tree.PreviousKeys {
+			if key.KeyId == keyID {
+				pubKey = key.PublicKey
+				break
+			}
+		}
+	}
+	return der.UnmarshalPublicKey(pubKey.GetDer())
+}
+
 func tryGetConsistencyProof(ctx context.Context, firstTreeSize, secondTreeSize, rootTreeSize int64, tx storage.ReadOnlyLogTreeTX, hasher hashers.LogHasher) (*trillian.Proof, error) {
 	nodeFetches, err := merkle.CalcConsistencyProofNodeAddresses(firstTreeSize, secondTreeSize, rootTreeSize, proofMaxBitLen)
 	if err != nil {
@@ -571,6 +895,88 @@ func (t *TrillianLogRPCServer) GetLeavesByRange(ctx context.Context, req *trilli
 	return r, nil
 }
 
+// StreamLeaves sends the sequenced leaves of a log to the client in order,
+// starting from req.StartIndex, and then continues to send new leaves as they
+// are integrated until the client cancels the stream. Each new SignedLogRoot is
+// sent along with the first batch of leaves that it covers.
+func (t *TrillianLogRPCServer) StreamLeaves(req *trillian.StreamLeavesRequest, stream trillian.TrillianLog_StreamLeavesServer) error {
+	ctx, spanEnd := spanFor(stream.Context(), "StreamLeaves")
+	defer spanEnd()
+	if err := validateStreamLeavesRequest(req); err != nil {
+		return err
+	}
+
+	tree, ctx, err := t.getTreeAndContext(ctx, req.LogId, optsLogRead)
+	if err != nil {
+		return err
+	}
+
+	next := req.StartIndex
+	var lastRoot []byte
+	for {
+		slr, leaves, err := t.getLeavesFrom(ctx, tree, next, StreamLeavesBatchSize)
+		if err != nil {
+			return err
+		}
+
+		r := &trillian.StreamLeavesResponse{Leaves: leaves}
+		if !bytes.Equal(slr.LogRoot, lastRoot) {
+			r.SignedLogRoot = slr
+			lastRoot = slr.LogRoot
+		}
+		if r.SignedLogRoot != nil || len(r.Leaves) > 0 {
+			if err := stream.Send(r); err != nil {
+				return err
+			}
+			next += int64(len(leaves))
+		}
+
+		// A full batch means that there may be more leaves available already.
+		if int64(len(leaves)) < StreamLeavesBatchSize {
+			if err := clock.SleepSource(ctx, StreamLeavesPollInterval, t.timeSource); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// getLeavesFrom returns the latest SignedLogRoot of the tree, along with up to
+// count of the leaves that it covers, starting at index start. The leaves are
+// read in the same transaction as the root, so they are always within it.
+func (t *TrillianLogRPCServer) getLeavesFrom(ctx context.Context, tree *trillian.Tree, start, count int64) (*trillian.SignedLogRoot, []*trillian.LogLeaf, error) {
+	tx, err := t.snapshotForTree(ctx, tree, "StreamLeaves")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer t.closeAndLog(ctx, tree.TreeId, tx, "StreamLeaves")
+
+	slr, err := tx.LatestSignedLogRoot(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	var root types.LogRootV1
+	if err := root.UnmarshalBinary(slr.LogRoot); err != nil {
+		return nil, nil, status.Errorf(codes.Internal, "Could not read current log root: %v", err)
+	}
+
+	var leaves []*trillian.LogLeaf
+	if treeSize := int64(root.TreeSize); start < treeSize {
+		// Pre-ordered logs may hold leaves beyond the tree size, which
+		// should not be returned until they have been integrated.
+		if maxCount := treeSize - start; count > maxCount {
+			count = maxCount
+		}
+		if leaves, err = tx.GetLeavesByRange(ctx, start, count); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := t.commitAndLog(ctx, tree.TreeId, tx, "StreamLeaves"); err != nil {
+		return nil, nil, err
+	}
+	return &slr, leaves, nil
+}
+
 // GetLeavesByHash obtains one or more leaves based on their tree hash. It is not possible
 // to fetch leaves that have been queued but not yet integrated. Logs may accept duplicate
 // entries so this may return more results than the number of hashes in the request.
@@ -719,6 +1125,34 @@ func getInclusionProofForLeafIndex(ctx context.Context, tx storage.ReadOnlyLogTr
 	return fetchNodesAndBuildProof(ctx, tx, hasher, rev, leafIndex, proofNodeIDs)
 }
 
+// getBatchInclusionProofForLeafIndices returns a batch inclusion proof, as
+// built by merkle.MergeInclusionProofs, for the given leaf indices, which must
+// be in increasing order without duplicates.
+func getBatchInclusionProofForLeafIndices(ctx context.Context, tx storage.ReadOnlyLogTreeTX, hasher hashers.LogHasher, snapshot int64, leafIndices []int64, treeSize int64) ([][]byte, error) {
+	proofNodeFetches := make([][]merkle.NodeFetch, len(leafIndices))
+	for i, leafIndex := range leafIndices {
+		fetches, err := merkle.CalcInclusionProofNodeAddresses(snapshot, leafIndex, treeSize, proofMaxBitLen)
+		if err != nil {
+			return nil, err
+		}
+		proofNodeFetches[i] = fetches
+	}
+
+	rev, err := tx.ReadRevision(ctx)
+	if err != nil {
+		return nil, err
+	}
+	proofs, err := fetchNodesAndBuildProofs(ctx, tx, hasher, rev, leafIndices, proofNodeFetches)
+	if err != nil {
+		return nil, err
+	}
+	hashes := make([][][]byte, len(proofs))
+	for i, proof := range proofs {
+		hashes[i] = proof.Hashes
+	}
+	return merkle.MergeInclusionProofs(leafIndices, snapshot, hashes)
+}
+
 func (t *TrillianLogRPCServer) getTreeAndHasher(ctx context.Context, treeID int64, opts trees.GetOpts) (*trillian.Tree, hashers.LogHasher, error) {
 	tree, err := trees.GetTree(ctx, t.registry.AdminStorage, treeID, opts)
 	if err != nil {
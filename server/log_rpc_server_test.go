@@ -17,6 +17,9 @@ package server
 import (
 	"context"
 	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"errors"
 	"fmt"
 	"reflect"
@@ -27,16 +30,20 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/golang/protobuf/proto"
 	"github.com/google/trillian"
+	"github.com/google/trillian/crypto/keys/der"
 	"github.com/google/trillian/crypto/keys/pem"
+	"github.com/google/trillian/crypto/keyspb"
 	"github.com/google/trillian/crypto/sigpb"
 	"github.com/google/trillian/extension"
 	"github.com/google/trillian/merkle/rfc6962"
 	"github.com/google/trillian/storage"
 	"github.com/google/trillian/testonly"
+	"github.com/google/trillian/trees"
 	"github.com/google/trillian/types"
 	"github.com/google/trillian/util/clock"
 	"github.com/kylelemons/godebug/pretty"
 	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -415,6 +422,103 @@ func TestGetLeavesByRange(t *testing.T) {
 	}
 }
 
+// fakeStreamLeavesServer collects the responses sent by StreamLeaves, and
+// cancels the stream once it has received want of them.
+type fakeStreamLeavesServer struct {
+	grpc.ServerStream
+	ctx    context.Context
+	cancel context.CancelFunc
+	want   int
+	got    []*trillian.StreamLeavesResponse
+}
+
+func (f *fakeStreamLeavesServer) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeStreamLeavesServer) Send(r *trillian.StreamLeavesResponse) error {
+	f.got = append(f.got, r)
+	if len(f.got) >= f.want {
+		f.cancel()
+	}
+	return nil
+}
+
+func TestStreamLeaves(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	defer func(size int64, interval time.Duration) {
+		StreamLeavesBatchSize, StreamLeavesPollInterval = size, interval
+	}(StreamLeavesBatchSize, StreamLeavesPollInterval)
+	StreamLeavesBatchSize, StreamLeavesPollInterval = 2, time.Millisecond
+
+	tree := addTreeID(stestonly.LogTree, logID1)
+	rootA, err := fixedSigner.SignLogRoot(&types.LogRootV1{TreeSize: 3, RootHash: []byte("A")})
+	if err != nil {
+		t.Fatalf("SignLogRoot(): %v", err)
+	}
+	rootB, err := fixedSigner.SignLogRoot(&types.LogRootV1{TreeSize: 4, RootHash: []byte("B")})
+	if err != nil {
+		t.Fatalf("SignLogRoot(): %v", err)
+	}
+
+	fakeStorage := storage.NewMockLogStorage(ctrl)
+	// Each poll of the log reads the latest root, and any leaves it covers
+	// beyond those already sent.
+	var calls []*gomock.Call
+	for _, poll := range []struct {
+		root         *trillian.SignedLogRoot
+		start, count int64
+		leaves       []*trillian.LogLeaf
+	}{
+		{root: rootA, start: 1, count: 2, leaves: []*trillian.LogLeaf{leaf1, leaf2}},
+		{root: rootA},
+		{root: rootB, start: 3, count: 1, leaves: []*trillian.LogLeaf{leaf3}},
+	} {
+		mockTX := storage.NewMockLogTreeTX(ctrl)
+		calls = append(calls, fakeStorage.EXPECT().SnapshotForTree(gomock.Any(), tree).Return(mockTX, nil))
+		mockTX.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(*poll.root, nil)
+		if poll.count > 0 {
+			mockTX.EXPECT().GetLeavesByRange(gomock.Any(), poll.start, poll.count).Return(poll.leaves, nil)
+		}
+		mockTX.EXPECT().Commit().Return(nil)
+		mockTX.EXPECT().Close().Return(nil)
+	}
+	gomock.InOrder(calls...)
+
+	registry := extension.Registry{
+		AdminStorage: fakeAdminStorage(ctrl, storageParams{treeID: tree.TreeId, numSnapshots: 1}),
+		LogStorage:   fakeStorage,
+	}
+	server := NewTrillianLogRPCServer(registry, clock.System)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := &fakeStreamLeavesServer{ctx: ctx, cancel: cancel, want: 2}
+	err = server.StreamLeaves(&trillian.StreamLeavesRequest{LogId: tree.TreeId, StartIndex: 1}, stream)
+	if err != context.Canceled {
+		t.Errorf("StreamLeaves()=%v, want %v", err, context.Canceled)
+	}
+
+	want := []*trillian.StreamLeavesResponse{
+		{SignedLogRoot: rootA, Leaves: []*trillian.LogLeaf{leaf1, leaf2}},
+		{SignedLogRoot: rootB, Leaves: []*trillian.LogLeaf{leaf3}},
+	}
+	if diff := pretty.Compare(stream.got, want); diff != "" {
+		t.Errorf("StreamLeaves() sent diff (-got +want):\n%v", diff)
+	}
+}
+
+func TestStreamLeavesInvalidRequest(t *testing.T) {
+	server := NewTrillianLogRPCServer(extension.Registry{}, fakeTimeSource)
+	stream := &fakeStreamLeavesServer{ctx: context.Background()}
+	err := server.StreamLeaves(&trillian.StreamLeavesRequest{LogId: logID1, StartIndex: -1}, stream)
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("StreamLeaves()=%v, want %v", err, codes.InvalidArgument)
+	}
+}
+
 func TestQueueLeavesStorageError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -458,6 +562,91 @@ func dupeQueuedLeaf(l *trillian.LogLeaf) *trillian.QueuedLogLeaf {
 	}
 }
 
+func TestQueueLeavesQueueFull(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTX := storage.NewMockReadOnlyLogTX(ctrl)
+	mockTX.EXPECT().GetUnsequencedCounts(gomock.Any()).Return(storage.CountByLogID{queueRequest0.LogId: 1}, nil)
+	mockTX.EXPECT().Commit().Return(nil)
+	mockTX.EXPECT().Close().Return(nil)
+	mockStorage := storage.NewMockLogStorage(ctrl)
+	// The queue depth is only read once, and then tracked locally until the
+	// next refresh.
+	mockStorage.EXPECT().Snapshot(gomock.Any()).Return(mockTX, nil)
+	mockStorage.EXPECT().QueueLeaves(gomock.Any(), gomock.Any(), []*trillian.LogLeaf{leaf1}, fakeTime).Return([]*trillian.QueuedLogLeaf{okQueuedLeaf(leaf1)}, nil)
+
+	tree := addTreeID(stestonly.LogTree, queueRequest0.LogId)
+	tree.MaxQueueDepth = 2
+	adminTX := storage.NewMockReadOnlyAdminTX(ctrl)
+	adminTX.EXPECT().GetTree(gomock.Any(), queueRequest0.LogId).Times(2).Return(tree, nil)
+	adminTX.EXPECT().Commit().Times(2).Return(nil)
+	adminTX.EXPECT().Close().Times(2).Return(nil)
+	adminStorage := storage.NewMockAdminStorage(ctrl)
+	adminStorage.EXPECT().Snapshot(gomock.Any()).Times(2).Return(adminTX, nil)
+
+	registry := extension.Registry{
+		AdminStorage: adminStorage,
+		LogStorage:   mockStorage,
+	}
+	server := NewTrillianLogRPCServer(registry, fakeTimeSource)
+
+	if _, err := server.QueueLeaves(ctx, &queueRequest0); err != nil {
+		t.Fatalf("QueueLeaves() = (_, %v), want (_, nil)", err)
+	}
+	_, err := server.QueueLeaves(ctx, &queueRequest0)
+	if got, want := status.Code(err), codes.ResourceExhausted; got != want {
+		t.Errorf("QueueLeaves() = (_, %v), want error code %v", err, want)
+	}
+}
+
+func TestCheckQueueDepthRefresh(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	mockTX := storage.NewMockReadOnlyLogTX(ctrl)
+	mockTX.EXPECT().GetUnsequencedCounts(gomock.Any()).DoAndReturn(func(context.Context) (storage.CountByLogID, error) {
+		close(started)
+		<-release
+		return storage.CountByLogID{queueRequest0.LogId: 5}, nil
+	})
+	mockTX.EXPECT().Commit().Return(nil)
+	mockTX.EXPECT().Close().Return(nil)
+	mockStorage := storage.NewMockLogStorage(ctrl)
+	mockStorage.EXPECT().Snapshot(gomock.Any()).Return(mockTX, nil)
+
+	server := NewTrillianLogRPCServer(extension.Registry{LogStorage: mockStorage}, fakeTimeSource)
+	// Make the cached depths stale.
+	server.queueDepths = storage.CountByLogID{queueRequest0.LogId: 1}
+	server.queueDepthTime = fakeTime.Add(-QueueDepthRefreshInterval)
+
+	tree := addTreeID(stestonly.LogTree, queueRequest0.LogId)
+	tree.MaxQueueDepth = 5
+	refreshErr := make(chan error)
+	go func() {
+		refreshErr <- server.checkQueueDepth(ctx, tree)
+	}()
+
+	// Requests made during the refresh use the stale depths, rather than wait
+	// for the refresh.
+	<-started
+	if err := server.checkQueueDepth(ctx, tree); err != nil {
+		t.Errorf("checkQueueDepth() during refresh = %v, want nil", err)
+	}
+
+	close(release)
+	if err := <-refreshErr; status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("checkQueueDepth() = %v, want code %v", err, codes.ResourceExhausted)
+	}
+	if err := server.checkQueueDepth(ctx, tree); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("checkQueueDepth() after refresh = %v, want code %v", err, codes.ResourceExhausted)
+	}
+}
+
 func TestQueueLeaves(t *testing.T) {
 	ctx := context.Background()
 	ctrl := gomock.NewController(t)
@@ -662,6 +851,189 @@ func TestGetLatestSignedLogRoot(t *testing.T) {
 	}
 }
 
+func TestAddLogRootCosignature(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	logSigner, err := trees.Signer(ctx, tree1)
+	if err != nil {
+		t.Fatalf("trees.Signer(): %v", err)
+	}
+	signRoot := func(size uint64) *trillian.SignedLogRoot {
+		t.Helper()
+		slr, err := logSigner.SignLogRoot(&types.LogRootV1{TreeSize: size, RootHash: []byte(fmt.Sprintf("root%d", size))})
+		if err != nil {
+			t.Fatalf("SignLogRoot(): %v", err)
+		}
+		return slr
+	}
+	root2, root3, root4 := signRoot(2), signRoot(3), signRoot(4)
+	notLogRoot, err := fixedSigner.SignLogRoot(&types.LogRootV1{TreeSize: 5})
+	if err != nil {
+		t.Fatalf("SignLogRoot(): %v", err)
+	}
+
+	aliceKey, err := pem.UnmarshalPrivateKey(testonly.DemoPrivateKey, testonly.DemoPrivateKeyPass)
+	if err != nil {
+		t.Fatalf("UnmarshalPrivateKey(): %v", err)
+	}
+	bobKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey(): %v", err)
+	}
+	witnessSigners := map[string]*tcrypto.Signer{
+		"alice": tcrypto.NewSigner(0, aliceKey, crypto.SHA256),
+		"bob":   tcrypto.NewSigner(0, bobKey, crypto.SHA256),
+		"eve":   tcrypto.NewSigner(0, bobKey, crypto.SHA256),
+	}
+	cosign := func(witness string, logID int64, slr *trillian.SignedLogRoot) *trillian.Cosignature {
+		t.Helper()
+		sig, err := witnessSigners[witness].CosignLogRoot(logID, slr)
+		if err != nil {
+			t.Fatalf("CosignLogRoot(): %v", err)
+		}
+		return &trillian.Cosignature{Witness: witness, Signature: sig}
+	}
+
+	registry := extension.Registry{
+		AdminStorage: fakeAdminStorage(ctrl, storageParams{treeID: logID1, numSnapshots: 20}),
+	}
+	server := NewTrillianLogRPCServer(registry, fakeTimeSource)
+	server.SetWitnesses(map[string]crypto.PublicKey{
+		"alice": aliceKey.Public(),
+		"bob":   bobKey.Public(),
+	})
+
+	// The tests run in order against the same server, as each cosignature
+	// added changes the state of the server.
+	for _, test := range []struct {
+		desc     string
+		slr      *trillian.SignedLogRoot
+		cosig    *trillian.Cosignature
+		wantCode codes.Code
+	}{
+		{desc: "noRoot", cosig: cosign("alice", logID1, root3), wantCode: codes.InvalidArgument},
+		{desc: "noSignature", slr: root3, cosig: &trillian.Cosignature{Witness: "alice"}, wantCode: codes.InvalidArgument},
+		{desc: "unknownWitness", slr: root3, cosig: cosign("eve", logID1, root3), wantCode: codes.PermissionDenied},
+		{desc: "notLogRoot", slr: notLogRoot, cosig: cosign("alice", logID1, notLogRoot), wantCode: codes.InvalidArgument},
+		{desc: "wrongLog", slr: root3, cosig: cosign("alice", logID2, root3), wantCode: codes.InvalidArgument},
+		{desc: "wrongWitness", slr: root3, cosig: &trillian.Cosignature{Witness: "bob", Signature: cosign("alice", logID1, root3).Signature}, wantCode: codes.InvalidArgument},
+		{desc: "alice", slr: root3, cosig: cosign("alice", logID1, root3)},
+		{desc: "olderRoot", slr: root2, cosig: cosign("bob", logID1, root2), wantCode: codes.FailedPrecondition},
+		{desc: "bob", slr: root3, cosig: cosign("bob", logID1, root3)},
+		{desc: "aliceAgain", slr: root3, cosig: cosign("alice", logID1, root3)},
+	} {
+		req := &trillian.AddLogRootCosignatureRequest{LogId: logID1, SignedLogRoot: test.slr, Cosignature: test.cosig}
+		_, err := server.AddLogRootCosignature(ctx, req)
+		if got := status.Code(err); got != test.wantCode {
+			t.Errorf("%v: AddLogRootCosignature()=%v, want %v", test.desc, err, test.wantCode)
+		}
+	}
+
+	for _, test := range []struct {
+		desc          string
+		cosigned      bool
+		storageRoot   *trillian.SignedLogRoot
+		wantRoot      *trillian.SignedLogRoot
+		wantWitnesses []string
+	}{
+		{desc: "latestCosigned", storageRoot: root3, wantRoot: root3, wantWitnesses: []string{"alice", "bob"}},
+		{desc: "latestNotCosigned", storageRoot: root4, wantRoot: root4},
+		{desc: "cosigned", cosigned: true, storageRoot: root4, wantRoot: root3, wantWitnesses: []string{"alice", "bob"}},
+	} {
+		fakeStorage := storage.NewMockLogStorage(ctrl)
+		mockTX := storage.NewMockLogTreeTX(ctrl)
+		fakeStorage.EXPECT().SnapshotForTree(gomock.Any(), gomock.Any()).Return(mockTX, nil)
+		mockTX.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(*test.storageRoot, nil)
+		mockTX.EXPECT().Commit().Return(nil)
+		mockTX.EXPECT().Close().Return(nil)
+		server.registry.LogStorage = fakeStorage
+
+		resp, err := server.GetLatestSignedLogRoot(ctx, &trillian.GetLatestSignedLogRootRequest{LogId: logID1, Cosigned: test.cosigned})
+		if err != nil {
+			t.Errorf("%v: GetLatestSignedLogRoot()=_, %v", test.desc, err)
+			continue
+		}
+		if !proto.Equal(resp.SignedLogRoot, test.wantRoot) {
+			t.Errorf("%v: GetLatestSignedLogRoot().SignedLogRoot=%v, want %v", test.desc, resp.SignedLogRoot, test.wantRoot)
+		}
+		var witnesses []string
+		for _, cosig := range resp.Cosignatures {
+			witnesses = append(witnesses, cosig.Witness)
+		}
+		if !reflect.DeepEqual(witnesses, test.wantWitnesses) {
+			t.Errorf("%v: GetLatestSignedLogRoot() returned cosignatures by %v, want %v", test.desc, witnesses, test.wantWitnesses)
+		}
+	}
+}
+
+func TestLogPublicKey(t *testing.T) {
+	newKey := func() *keyspb.PublicKey {
+		t.Helper()
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatalf("GenerateKey(): %v", err)
+		}
+		pb, err := der.ToPublicProto(key.Public())
+		if err != nil {
+			t.Fatalf("ToPublicProto(): %v", err)
+		}
+		return pb
+	}
+	oldKey, currentKey := newKey(), newKey()
+	tree := proto.Clone(tree1).(*trillian.Tree)
+	tree.KeyId = 2
+	tree.PublicKey = currentKey
+	tree.PreviousKeys = []*trillian.TreeKey{{KeyId: 1, PublicKey: oldKey}}
+
+	for _, test := range []struct {
+		desc    string
+		keyHint []byte
+		want    *keyspb.PublicKey
+	}{
+		{desc: "previousKey", keyHint: types.SerializeKeyVersionHint(tree.TreeId, 1), want: oldKey},
+		{desc: "currentKey", keyHint: types.SerializeKeyVersionHint(tree.TreeId, 2), want: currentKey},
+		{desc: "unknownKey", keyHint: types.SerializeKeyVersionHint(tree.TreeId, 3), want: currentKey},
+		{desc: "noKeyID", keyHint: types.SerializeKeyHint(tree.TreeId), want: currentKey},
+		{desc: "noHint", want: currentKey},
+	} {
+		got, err := logPublicKey(tree, test.keyHint)
+		if err != nil {
+			t.Errorf("%v: logPublicKey(): %v", test.desc, err)
+			continue
+		}
+		gotPB, err := der.ToPublicProto(got)
+		if err != nil {
+			t.Fatalf("%v: ToPublicProto(): %v", test.desc, err)
+		}
+		if !proto.Equal(gotPB, test.want) {
+			t.Errorf("%v: logPublicKey() returned the wrong key", test.desc)
+		}
+	}
+}
+
+func TestGetLatestSignedLogRootNotCosigned(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeStorage := storage.NewMockLogStorage(ctrl)
+	mockTX := storage.NewMockLogTreeTX(ctrl)
+	fakeStorage.EXPECT().SnapshotForTree(gomock.Any(), tree1).Return(mockTX, nil)
+	mockTX.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(*signedRoot1, nil)
+	mockTX.EXPECT().Close().Return(nil)
+
+	registry := extension.Registry{
+		AdminStorage: fakeAdminStorage(ctrl, storageParams{treeID: logID1, numSnapshots: 1}),
+		LogStorage:   fakeStorage,
+	}
+	server := NewTrillianLogRPCServer(registry, fakeTimeSource)
+	_, err := server.GetLatestSignedLogRoot(context.Background(), &trillian.GetLatestSignedLogRootRequest{LogId: logID1, Cosigned: true})
+	if got, want := status.Code(err), codes.NotFound; got != want {
+		t.Errorf("GetLatestSignedLogRoot()=_, %v, want %v", err, want)
+	}
+}
+
 func TestGetLeavesByHash(t *testing.T) {
 	for _, tc := range []struct {
 		name         string
@@ -1244,6 +1616,205 @@ func TestGetProofByIndex(t *testing.T) {
 	}
 }
 
+func TestGetBatchInclusionProof(t *testing.T) {
+	// The proofs for leaves 2 and 3 in a tree of size 7 share all but their
+	// first node.
+	nodeIDs := append(nodeIdsInclusionSize7Index2, stestonly.MustCreateNodeIDForTreeCoords(0, 2, 64))
+	req := &trillian.GetBatchInclusionProofRequest{LogId: logID1, TreeSize: 7, LeafIndex: []int64{3, 2, 2}}
+
+	for _, tc := range []struct {
+		name         string
+		setupStorage func(*gomock.Controller, *storage.MockLogStorage)
+		req          *trillian.GetBatchInclusionProofRequest
+		errStr       string
+		wantResp     *trillian.GetBatchInclusionProofResponse
+	}{
+		{
+			name: "get nodes fails",
+			setupStorage: func(c *gomock.Controller, s *storage.MockLogStorage) {
+				tx := storage.NewMockLogTreeTX(c)
+				s.EXPECT().SnapshotForTree(gomock.Any(), tree1).Return(tx, nil)
+				tx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(*signedRoot1, nil)
+				tx.EXPECT().ReadRevision(gomock.Any()).Return(int64(root1.Revision), nil)
+				tx.EXPECT().GetMerkleNodes(gomock.Any(), revision1, nodeIDs).Return([]storage.Node{}, errors.New("STORAGE"))
+				tx.EXPECT().Close().Return(nil)
+			},
+			req:    req,
+			errStr: "STORAGE",
+		},
+		{
+			name: "ok",
+			setupStorage: func(c *gomock.Controller, s *storage.MockLogStorage) {
+				tx := storage.NewMockLogTreeTX(c)
+				s.EXPECT().SnapshotForTree(gomock.Any(), tree1).Return(tx, nil)
+				tx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(*signedRoot1, nil)
+				tx.EXPECT().ReadRevision(gomock.Any()).Return(int64(root1.Revision), nil)
+				tx.EXPECT().GetMerkleNodes(gomock.Any(), revision1, nodeIDs).Return([]storage.Node{
+					{NodeID: nodeIDs[0], NodeRevision: 3, Hash: []byte("nodehash0")},
+					{NodeID: nodeIDs[1], NodeRevision: 2, Hash: []byte("nodehash1")},
+					{NodeID: nodeIDs[2], NodeRevision: 3, Hash: []byte("nodehash2")},
+					{NodeID: nodeIDs[3], NodeRevision: 3, Hash: []byte("nodehash3")}}, nil)
+				tx.EXPECT().Commit().Return(nil)
+				tx.EXPECT().Close().Return(nil)
+			},
+			req: req,
+			wantResp: &trillian.GetBatchInclusionProofResponse{
+				SignedLogRoot: signedRoot1,
+				LeafIndex:     []int64{2, 3},
+				Hashes: [][]byte{
+					[]byte("nodehash0"),
+					[]byte("nodehash1"),
+					[]byte("nodehash2"),
+					[]byte("nodehash3"),
+				},
+			},
+		},
+		{
+			name: "skew beyond sth",
+			setupStorage: func(c *gomock.Controller, s *storage.MockLogStorage) {
+				tx := storage.NewMockLogTreeTX(c)
+				s.EXPECT().SnapshotForTree(gomock.Any(), tree1).Return(tx, nil)
+				tx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(*signedRoot1, nil)
+				tx.EXPECT().Close().Return(nil)
+			},
+			req: &trillian.GetBatchInclusionProofRequest{LogId: logID1, TreeSize: 50, LeafIndex: []int64{25}},
+			wantResp: &trillian.GetBatchInclusionProofResponse{
+				SignedLogRoot: signedRoot1,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			fakeStorage := storage.NewMockLogStorage(ctrl)
+			tc.setupStorage(ctrl, fakeStorage)
+			registry := extension.Registry{
+				AdminStorage: fakeAdminStorage(ctrl, storageParams{treeID: leaf0Request.LogId, numSnapshots: 1}),
+				LogStorage:   fakeStorage,
+			}
+			server := NewTrillianLogRPCServer(registry, fakeTimeSource)
+			resp, err := server.GetBatchInclusionProof(context.Background(), tc.req)
+			if len(tc.errStr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tc.errStr) {
+					t.Errorf("GetBatchInclusionProof(%v)=%v, %v want nil, err containing: %s", tc.req, resp, err, tc.errStr)
+				}
+				return
+			}
+
+			if err != nil || !proto.Equal(tc.wantResp, resp) {
+				t.Errorf("GetBatchInclusionProof(%v)=%v, %v, want: %v, nil", tc.req, resp, err, tc.wantResp)
+			}
+		})
+	}
+}
+
+func TestGetRangeInclusionProof(t *testing.T) {
+	// The leaves [2, 5) of a tree of size 7 are preceded by the compact range
+	// [0, 2), which is a single node, and followed by the compact range [5, 7),
+	// which is two leaves.
+	leftIDs := []storage.NodeID{stestonly.MustCreateNodeIDForTreeCoords(1, 0, 64)}
+	rightIDs := []storage.NodeID{
+		stestonly.MustCreateNodeIDForTreeCoords(0, 5, 64),
+		stestonly.MustCreateNodeIDForTreeCoords(0, 6, 64),
+	}
+	req := &trillian.GetRangeInclusionProofRequest{LogId: logID1, StartIndex: 2, Count: 3, TreeSize: 7}
+
+	for _, tc := range []struct {
+		name         string
+		setupStorage func(*gomock.Controller, *storage.MockLogStorage)
+		req          *trillian.GetRangeInclusionProofRequest
+		errStr       string
+		wantResp     *trillian.GetRangeInclusionProofResponse
+	}{
+		{
+			name: "get nodes fails",
+			setupStorage: func(c *gomock.Controller, s *storage.MockLogStorage) {
+				tx := storage.NewMockLogTreeTX(c)
+				s.EXPECT().SnapshotForTree(gomock.Any(), tree1).Return(tx, nil)
+				tx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(*signedRoot1, nil)
+				tx.EXPECT().ReadRevision(gomock.Any()).Return(int64(root1.Revision), nil)
+				tx.EXPECT().GetMerkleNodes(gomock.Any(), revision1, leftIDs).Return(nil, errors.New("STORAGE"))
+				tx.EXPECT().Close().Return(nil)
+			},
+			req:    req,
+			errStr: "STORAGE",
+		},
+		{
+			name: "ok",
+			setupStorage: func(c *gomock.Controller, s *storage.MockLogStorage) {
+				tx := storage.NewMockLogTreeTX(c)
+				s.EXPECT().SnapshotForTree(gomock.Any(), tree1).Return(tx, nil)
+				tx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(*signedRoot1, nil)
+				tx.EXPECT().ReadRevision(gomock.Any()).Return(int64(root1.Revision), nil)
+				tx.EXPECT().GetMerkleNodes(gomock.Any(), revision1, leftIDs).Return([]storage.Node{
+					{NodeID: leftIDs[0], NodeRevision: 3, Hash: []byte("nodehash0")}}, nil)
+				tx.EXPECT().GetMerkleNodes(gomock.Any(), revision1, rightIDs).Return([]storage.Node{
+					{NodeID: rightIDs[0], NodeRevision: 3, Hash: []byte("nodehash1")},
+					{NodeID: rightIDs[1], NodeRevision: 2, Hash: []byte("nodehash2")}}, nil)
+				tx.EXPECT().Commit().Return(nil)
+				tx.EXPECT().Close().Return(nil)
+			},
+			req: req,
+			wantResp: &trillian.GetRangeInclusionProofResponse{
+				SignedLogRoot: signedRoot1,
+				LeftHashes:    [][]byte{[]byte("nodehash0")},
+				RightHashes:   [][]byte{[]byte("nodehash1"), []byte("nodehash2")},
+			},
+		},
+		{
+			name: "whole tree",
+			setupStorage: func(c *gomock.Controller, s *storage.MockLogStorage) {
+				tx := storage.NewMockLogTreeTX(c)
+				s.EXPECT().SnapshotForTree(gomock.Any(), tree1).Return(tx, nil)
+				tx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(*signedRoot1, nil)
+				tx.EXPECT().ReadRevision(gomock.Any()).Return(int64(root1.Revision), nil)
+				tx.EXPECT().Commit().Return(nil)
+				tx.EXPECT().Close().Return(nil)
+			},
+			req: &trillian.GetRangeInclusionProofRequest{LogId: logID1, StartIndex: 0, Count: 7, TreeSize: 7},
+			wantResp: &trillian.GetRangeInclusionProofResponse{
+				SignedLogRoot: signedRoot1,
+			},
+		},
+		{
+			name: "skew beyond sth",
+			setupStorage: func(c *gomock.Controller, s *storage.MockLogStorage) {
+				tx := storage.NewMockLogTreeTX(c)
+				s.EXPECT().SnapshotForTree(gomock.Any(), tree1).Return(tx, nil)
+				tx.EXPECT().LatestSignedLogRoot(gomock.Any()).Return(*signedRoot1, nil)
+				tx.EXPECT().Close().Return(nil)
+			},
+			req: &trillian.GetRangeInclusionProofRequest{LogId: logID1, StartIndex: 25, Count: 5, TreeSize: 50},
+			wantResp: &trillian.GetRangeInclusionProofResponse{
+				SignedLogRoot: signedRoot1,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			fakeStorage := storage.NewMockLogStorage(ctrl)
+			tc.setupStorage(ctrl, fakeStorage)
+			registry := extension.Registry{
+				AdminStorage: fakeAdminStorage(ctrl, storageParams{treeID: leaf0Request.LogId, numSnapshots: 1}),
+				LogStorage:   fakeStorage,
+			}
+			server := NewTrillianLogRPCServer(registry, fakeTimeSource)
+			resp, err := server.GetRangeInclusionProof(context.Background(), tc.req)
+			if len(tc.errStr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tc.errStr) {
+					t.Errorf("GetRangeInclusionProof(%v)=%v, %v want nil, err containing: %s", tc.req, resp, err, tc.errStr)
+				}
+				return
+			}
+
+			if err != nil || !proto.Equal(tc.wantResp, resp) {
+				t.Errorf("GetRangeInclusionProof(%v)=%v, %v, want: %v, nil", tc.req, resp, err, tc.wantResp)
+			}
+		})
+	}
+}
+
 func TestGetEntryAndProof(t *testing.T) {
 	for _, tc := range []struct {
 		name         string
@@ -1831,6 +2402,72 @@ func TestTrillianLogRPCServer_GetInclusionProofErrors(t *testing.T) {
 	}
 }
 
+func TestTrillianLogRPCServer_GetBatchInclusionProofErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		req  *trillian.GetBatchInclusionProofRequest
+	}{
+		{
+			desc: "noLeafIndex",
+			req:  &trillian.GetBatchInclusionProofRequest{LogId: 1, TreeSize: 20},
+		},
+		{
+			desc: "badLeafIndex",
+			req:  &trillian.GetBatchInclusionProofRequest{LogId: 1, LeafIndex: []int64{1, -10}, TreeSize: 20},
+		},
+		{
+			desc: "badTreeSize",
+			req:  &trillian.GetBatchInclusionProofRequest{LogId: 1, LeafIndex: []int64{10}, TreeSize: -20},
+		},
+		{
+			desc: "indexGreaterThanSize",
+			req:  &trillian.GetBatchInclusionProofRequest{LogId: 1, LeafIndex: []int64{1, 10}, TreeSize: 9},
+		},
+	}
+
+	logServer := NewTrillianLogRPCServer(extension.Registry{}, fakeTimeSource)
+	ctx := context.Background()
+	for _, test := range tests {
+		_, err := logServer.GetBatchInclusionProof(ctx, test.req)
+		if s, ok := status.FromError(err); !ok || s.Code() != codes.InvalidArgument {
+			t.Errorf("%v: GetBatchInclusionProof() returned err = %v, wantCode = %s", test.desc, err, codes.InvalidArgument)
+		}
+	}
+}
+
+func TestTrillianLogRPCServer_GetRangeInclusionProofErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		req  *trillian.GetRangeInclusionProofRequest
+	}{
+		{
+			desc: "badStartIndex",
+			req:  &trillian.GetRangeInclusionProofRequest{LogId: 1, StartIndex: -1, Count: 5, TreeSize: 20},
+		},
+		{
+			desc: "badCount",
+			req:  &trillian.GetRangeInclusionProofRequest{LogId: 1, StartIndex: 1, TreeSize: 20},
+		},
+		{
+			desc: "badTreeSize",
+			req:  &trillian.GetRangeInclusionProofRequest{LogId: 1, StartIndex: 1, Count: 5, TreeSize: -20},
+		},
+		{
+			desc: "rangeBeyondSize",
+			req:  &trillian.GetRangeInclusionProofRequest{LogId: 1, StartIndex: 5, Count: 5, TreeSize: 9},
+		},
+	}
+
+	logServer := NewTrillianLogRPCServer(extension.Registry{}, fakeTimeSource)
+	ctx := context.Background()
+	for _, test := range tests {
+		_, err := logServer.GetRangeInclusionProof(ctx, test.req)
+		if s, ok := status.FromError(err); !ok || s.Code() != codes.InvalidArgument {
+			t.Errorf("%v: GetRangeInclusionProof() returned err = %v, wantCode = %s", test.desc, err, codes.InvalidArgument)
+		}
+	}
+}
+
 func TestTrillianLogRPCServer_GetInclusionProofByHashErrors(t *testing.T) {
 	tests := []struct {
 		desc string
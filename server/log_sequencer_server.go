@@ -0,0 +1,137 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/trillian"
+	"github.com/google/trillian/extension"
+	"github.com/google/trillian/log"
+	"github.com/google/trillian/trees"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	optsSequence      = trees.NewGetOpts(trees.SequenceLog, trillian.TreeType_LOG, trillian.TreeType_PREORDERED_LOG)
+	optsSequenceAdmin = trees.NewGetOpts(trees.Admin, trillian.TreeType_LOG, trillian.TreeType_PREORDERED_LOG)
+	optsQueueRead     = trees.NewGetOpts(trees.Query, trillian.TreeType_LOG)
+)
+
+// TrillianLogSequencerRPCServer implements the TrillianLogSequencer RPC API,
+// which allows operators to inspect and control the sequencing performed by
+// a log signer.
+type TrillianLogSequencerRPCServer struct {
+	registry extension.Registry
+	manager  *log.OperationManager
+}
+
+// NewTrillianLogSequencerRPCServer creates a new RPC server backed by the
+// OperationManager that runs the signer's sequencing passes.
+func NewTrillianLogSequencerRPCServer(registry extension.Registry, manager *log.OperationManager) *TrillianLogSequencerRPCServer {
+	return &TrillianLogSequencerRPCServer{
+		registry: registry,
+		manager:  manager,
+	}
+}
+
+// IntegrateBatch runs a sequencing pass for a single log immediately.
+func (s *TrillianLogSequencerRPCServer) IntegrateBatch(ctx context.Context, req *trillian.IntegrateBatchRequest) (*trillian.IntegrateBatchResponse, error) {
+	ctx, spanEnd := spanFor(ctx, "IntegrateBatch")
+	defer spanEnd()
+	if _, err := trees.GetTree(ctx, s.registry.AdminStorage, req.LogId, optsSequence); err != nil {
+		return nil, err
+	}
+
+	count, err := s.manager.RunOperation(ctx, req.LogId)
+	switch err {
+	case nil:
+	case log.ErrNotMaster:
+		return nil, status.Errorf(codes.FailedPrecondition, "not master for log %d", req.LogId)
+	case log.ErrOperationPaused:
+		return nil, status.Errorf(codes.FailedPrecondition, "sequencing is paused for log %d", req.LogId)
+	default:
+		return nil, err
+	}
+	return &trillian.IntegrateBatchResponse{IntegratedLeafCount: int64(count)}, nil
+}
+
+// GetMastership reports the mastership status of the signer for each log.
+func (s *TrillianLogSequencerRPCServer) GetMastership(ctx context.Context, req *trillian.GetMastershipRequest) (*trillian.GetMastershipResponse, error) {
+	_, spanEnd := spanFor(ctx, "GetMastership")
+	defer spanEnd()
+	mastership, err := s.manager.Mastership()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to read mastership: %v", err)
+	}
+
+	logs := make([]*trillian.LogMastership, 0, len(mastership))
+	for logID, isMaster := range mastership {
+		logs = append(logs, &trillian.LogMastership{
+			LogId:    logID,
+			IsMaster: isMaster,
+			Paused:   s.manager.IsPaused(logID),
+		})
+	}
+	sort.Slice(logs, func(i, j int) bool { return logs[i].LogId < logs[j].LogId })
+	return &trillian.GetMastershipResponse{Logs: logs}, nil
+}
+
+// GetQueueSize returns the number of unsequenced leaves queued for a log.
+func (s *TrillianLogSequencerRPCServer) GetQueueSize(ctx context.Context, req *trillian.GetQueueSizeRequest) (*trillian.GetQueueSizeResponse, error) {
+	ctx, spanEnd := spanFor(ctx, "GetQueueSize")
+	defer spanEnd()
+	if _, err := trees.GetTree(ctx, s.registry.AdminStorage, req.LogId, optsQueueRead); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.registry.LogStorage.Snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Close()
+	counts, err := tx.GetUnsequencedCounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &trillian.GetQueueSizeResponse{QueueSize: counts[req.LogId]}, nil
+}
+
+// PauseSequencing stops the signer from sequencing a log.
+func (s *TrillianLogSequencerRPCServer) PauseSequencing(ctx context.Context, req *trillian.PauseSequencingRequest) (*trillian.PauseSequencingResponse, error) {
+	ctx, spanEnd := spanFor(ctx, "PauseSequencing")
+	defer spanEnd()
+	if _, err := trees.GetTree(ctx, s.registry.AdminStorage, req.LogId, optsSequenceAdmin); err != nil {
+		return nil, err
+	}
+	s.manager.PauseLog(req.LogId)
+	return &trillian.PauseSequencingResponse{}, nil
+}
+
+// ResumeSequencing restarts sequencing of a log paused by PauseSequencing.
+func (s *TrillianLogSequencerRPCServer) ResumeSequencing(ctx context.Context, req *trillian.ResumeSequencingRequest) (*trillian.ResumeSequencingResponse, error) {
+	ctx, spanEnd := spanFor(ctx, "ResumeSequencing")
+	defer spanEnd()
+	if _, err := trees.GetTree(ctx, s.registry.AdminStorage, req.LogId, optsSequenceAdmin); err != nil {
+		return nil, err
+	}
+	s.manager.ResumeLog(req.LogId)
+	return &trillian.ResumeSequencingResponse{}, nil
+}
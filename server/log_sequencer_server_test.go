@@ -0,0 +1,153 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian"
+	"github.com/google/trillian/extension"
+	"github.com/google/trillian/log"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/memory"
+	"github.com/google/trillian/util/clock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	stestonly "github.com/google/trillian/storage/testonly"
+)
+
+func setupSequencerServer(ctx context.Context, t *testing.T, op log.Operation) (*TrillianLogSequencerRPCServer, *trillian.Tree, *trillian.Tree) {
+	t.Helper()
+	ts := memory.NewTreeStorage()
+	registry := extension.Registry{
+		AdminStorage: memory.NewAdminStorage(ts),
+		LogStorage:   memory.NewLogStorage(ts, nil),
+	}
+	logTree, err := storage.CreateTree(ctx, registry.AdminStorage, stestonly.LogTree)
+	if err != nil {
+		t.Fatalf("CreateTree() = (_, %v)", err)
+	}
+	mapTree, err := storage.CreateTree(ctx, registry.AdminStorage, stestonly.MapTree)
+	if err != nil {
+		t.Fatalf("CreateTree() = (_, %v)", err)
+	}
+
+	info := log.OperationInfo{
+		Registry:   registry,
+		BatchSize:  10,
+		NumWorkers: 1,
+		TimeSource: clock.System,
+	}
+	s := NewTrillianLogSequencerRPCServer(registry, log.NewOperationManager(info, op))
+	return s, logTree, mapTree
+}
+
+func TestIntegrateBatch(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	op := log.NewMockOperation(ctrl)
+	s, logTree, mapTree := setupSequencerServer(ctx, t, op)
+
+	op.EXPECT().ExecutePass(gomock.Any(), logTree.TreeId, gomock.Any()).Return(5, nil)
+	resp, err := s.IntegrateBatch(ctx, &trillian.IntegrateBatchRequest{LogId: logTree.TreeId})
+	if err != nil {
+		t.Fatalf("IntegrateBatch() = (_, %v)", err)
+	}
+	if got, want := resp.IntegratedLeafCount, int64(5); got != want {
+		t.Errorf("IntegrateBatch().IntegratedLeafCount = %d, want %d", got, want)
+	}
+
+	// Paused logs and non-log trees must be rejected without running a pass.
+	if _, err := s.PauseSequencing(ctx, &trillian.PauseSequencingRequest{LogId: logTree.TreeId}); err != nil {
+		t.Fatalf("PauseSequencing() = (_, %v)", err)
+	}
+	_, err = s.IntegrateBatch(ctx, &trillian.IntegrateBatchRequest{LogId: logTree.TreeId})
+	if got, want := status.Code(err), codes.FailedPrecondition; got != want {
+		t.Errorf("IntegrateBatch() on paused log returned %v, want code %v", err, want)
+	}
+	_, err = s.IntegrateBatch(ctx, &trillian.IntegrateBatchRequest{LogId: mapTree.TreeId})
+	if got, want := status.Code(err), codes.InvalidArgument; got != want {
+		t.Errorf("IntegrateBatch() on map returned %v, want code %v", err, want)
+	}
+}
+
+func TestPauseResumeSequencing(t *testing.T) {
+	ctx := context.Background()
+	s, logTree, mapTree := setupSequencerServer(ctx, t, nil)
+
+	if _, err := s.PauseSequencing(ctx, &trillian.PauseSequencingRequest{LogId: logTree.TreeId}); err != nil {
+		t.Fatalf("PauseSequencing() = (_, %v)", err)
+	}
+	resp, err := s.GetMastership(ctx, &trillian.GetMastershipRequest{})
+	if err != nil {
+		t.Fatalf("GetMastership() = (_, %v)", err)
+	}
+	want := &trillian.GetMastershipResponse{
+		Logs: []*trillian.LogMastership{{LogId: logTree.TreeId, Paused: true}},
+	}
+	if !proto.Equal(resp, want) {
+		t.Errorf("GetMastership() = %v, want %v", resp, want)
+	}
+
+	if _, err := s.ResumeSequencing(ctx, &trillian.ResumeSequencingRequest{LogId: logTree.TreeId}); err != nil {
+		t.Fatalf("ResumeSequencing() = (_, %v)", err)
+	}
+	resp, err = s.GetMastership(ctx, &trillian.GetMastershipRequest{})
+	if err != nil {
+		t.Fatalf("GetMastership() = (_, %v)", err)
+	}
+	want = &trillian.GetMastershipResponse{}
+	if !proto.Equal(resp, want) {
+		t.Errorf("GetMastership() = %v, want %v", resp, want)
+	}
+
+	_, err = s.PauseSequencing(ctx, &trillian.PauseSequencingRequest{LogId: mapTree.TreeId})
+	if got, want := status.Code(err), codes.InvalidArgument; got != want {
+		t.Errorf("PauseSequencing() on map returned %v, want code %v", err, want)
+	}
+}
+
+func TestGetQueueSize(t *testing.T) {
+	ctx := context.Background()
+	s, logTree, _ := setupSequencerServer(ctx, t, nil)
+
+	var leaves []*trillian.LogLeaf
+	for _, value := range []string{"one", "two"} {
+		hash := sha256.Sum256([]byte(value))
+		leaves = append(leaves, &trillian.LogLeaf{LeafValue: []byte(value), LeafIdentityHash: hash[:], MerkleLeafHash: hash[:]})
+	}
+	if err := s.registry.LogStorage.ReadWriteTransaction(ctx, logTree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		_, err := tx.QueueLeaves(ctx, leaves, time.Now())
+		return err
+	}); err != nil {
+		t.Fatalf("QueueLeaves() = %v", err)
+	}
+
+	resp, err := s.GetQueueSize(ctx, &trillian.GetQueueSizeRequest{LogId: logTree.TreeId})
+	if err != nil {
+		t.Fatalf("GetQueueSize() = (_, %v)", err)
+	}
+	if got, want := resp.QueueSize, int64(len(leaves)); got != want {
+		t.Errorf("GetQueueSize().QueueSize = %d, want %d", got, want)
+	}
+}
@@ -17,6 +17,10 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"time"
@@ -61,6 +65,10 @@ type Main struct {
 
 	// TLS Certificate and Key files for the server.
 	TLSCertFile, TLSKeyFile string
+	// TLSClientCAFile is an optional PEM file of the CAs that issue client
+	// certificates. If set, client certificates are verified and their
+	// subjects identify callers to the Registry's Authorizer.
+	TLSClientCAFile string
 
 	DBClose func() error
 
@@ -195,7 +203,7 @@ func (m *Main) Run(ctx context.Context) error {
 // newGRPCServer starts a new Trillian gRPC server.
 func (m *Main) newGRPCServer() (*grpc.Server, error) {
 	stats := monitoring.NewRPCStatsInterceptor(clock.System, m.StatsPrefix, m.Registry.MetricFactory)
-	ti := interceptor.New(m.Registry.AdminStorage, m.Registry.QuotaManager, m.QuotaDryRun, m.Registry.MetricFactory)
+	ti := interceptor.New(m.Registry.AdminStorage, m.Registry.QuotaManager, m.Registry.Authorizer, m.QuotaDryRun, m.Registry.MetricFactory)
 
 	serverOpts := []grpc.ServerOption{
 		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
@@ -203,11 +211,23 @@ func (m *Main) newGRPCServer() (*grpc.Server, error) {
 			interceptor.ErrorWrapper,
 			ti.UnaryInterceptor,
 		)),
+		grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(
+			stats.StreamInterceptor(),
+			interceptor.StreamErrorWrapper,
+			ti.StreamInterceptor,
+		)),
 	}
 	serverOpts = append(serverOpts, m.ExtraOptions...)
 
 	// Let credentials.NewServerTLSFromFile handle the error case when only one of the flags is set.
-	if m.TLSCertFile != "" || m.TLSKeyFile != "" {
+	switch {
+	case m.TLSClientCAFile != "":
+		serverCreds, err := m.mutualTLSCredentials()
+		if err != nil {
+			return nil, err
+		}
+		serverOpts = append(serverOpts, grpc.Creds(serverCreds))
+	case m.TLSCertFile != "" || m.TLSKeyFile != "":
 		serverCreds, err := credentials.NewServerTLSFromFile(m.TLSCertFile, m.TLSKeyFile)
 		if err != nil {
 			return nil, err
@@ -220,6 +240,29 @@ func (m *Main) newGRPCServer() (*grpc.Server, error) {
 	return s, nil
 }
 
+// mutualTLSCredentials returns server credentials that verify the
+// certificates presented by clients against TLSClientCAFile. Clients without
+// a certificate are still accepted, and are left to the Authorizer.
+func (m *Main) mutualTLSCredentials() (credentials.TransportCredentials, error) {
+	cert, err := tls.LoadX509KeyPair(m.TLSCertFile, m.TLSKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	pem, err := ioutil.ReadFile(m.TLSClientCAFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in %q", m.TLSClientCAFile)
+	}
+	return credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.VerifyClientCertIfGiven,
+	}), nil
+}
+
 // AnnounceSelf announces this binary's presence to etcd.  Returns a function that
 // should be called on process exit.
 // AnnounceSelf does nothing if client is nil.
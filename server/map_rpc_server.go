@@ -30,6 +30,7 @@ import (
 	"github.com/google/trillian/types"
 
 	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -37,12 +38,24 @@ import (
 const (
 	// Used internally by GetLeaves.
 	mostRecentRevision = -1
+
+	// defaultListLeavesPageSize is the number of leaves returned by ListLeaves
+	// when the request doesn't specify a page size.
+	defaultListLeavesPageSize = 1000
+	// maxListLeavesPageSize is the largest number of leaves returned by a
+	// single ListLeaves request. The interceptor caps the quota charged for
+	// ListLeaves at the same value.
+	maxListLeavesPageSize = 10000
 )
 
 var (
 	optsMapInit  = trees.NewGetOpts(trees.Admin, trillian.TreeType_MAP)
 	optsMapRead  = trees.NewGetOpts(trees.Query, trillian.TreeType_MAP)
 	optsMapWrite = trees.NewGetOpts(trees.UpdateMap, trillian.TreeType_MAP)
+
+	// GetMapDiffBatchSize is the maximum number of changed leaves sent in a
+	// single GetMapDiffResponse.
+	GetMapDiffBatchSize = 100
 )
 
 // TODO(codingllama): There is no access control in the server yet and clients could easily modify
@@ -108,14 +121,14 @@ func (t *TrillianMapServer) IsHealthy() error {
 func (t *TrillianMapServer) GetLeaves(ctx context.Context, req *trillian.GetMapLeavesRequest) (*trillian.GetMapLeavesResponse, error) {
 	ctx, spanEnd := spanFor(ctx, "GetLeaves")
 	defer spanEnd()
-	return t.getLeavesByRevision(ctx, req.MapId, req.Index, mostRecentRevision)
+	return t.getLeavesByRevision(ctx, req.MapId, req.Index, mostRecentRevision, req.CompactProof)
 }
 
 // GetLeaf returns an inclusion proof to the leaf, or nil if the leaf does not exist.
 func (t *TrillianMapServer) GetLeaf(ctx context.Context, req *trillian.GetMapLeafRequest) (*trillian.GetMapLeafResponse, error) {
 	ctx, spanEnd := spanFor(ctx, "GetLeaf")
 	defer spanEnd()
-	ret, err := t.getLeavesByRevision(ctx, req.MapId, [][]byte{req.Index}, mostRecentRevision)
+	ret, err := t.getLeavesByRevision(ctx, req.MapId, [][]byte{req.Index}, mostRecentRevision, false)
 	if err != nil {
 		return nil, err
 	}
@@ -132,7 +145,7 @@ func (t *TrillianMapServer) GetLeaf(ctx context.Context, req *trillian.GetMapLea
 func (t *TrillianMapServer) GetLeafByRevision(ctx context.Context, req *trillian.GetMapLeafByRevisionRequest) (*trillian.GetMapLeafResponse, error) {
 	ctx, spanEnd := spanFor(ctx, "GetLeafByRevision")
 	defer spanEnd()
-	ret, err := t.getLeavesByRevision(ctx, req.MapId, [][]byte{req.Index}, req.Revision)
+	ret, err := t.getLeavesByRevision(ctx, req.MapId, [][]byte{req.Index}, req.Revision, false)
 	if err != nil {
 		return nil, err
 	}
@@ -152,7 +165,7 @@ func (t *TrillianMapServer) GetLeavesByRevision(ctx context.Context, req *trilli
 	if req.Revision < 0 {
 		return nil, fmt.Errorf("map revision %d must be >= 0", req.Revision)
 	}
-	return t.getLeavesByRevision(ctx, req.MapId, req.Index, req.Revision)
+	return t.getLeavesByRevision(ctx, req.MapId, req.Index, req.Revision, req.CompactProof)
 }
 
 // GetLeavesByRevisionNoProof implements the GetLeavesByRevision RPC method.
@@ -183,7 +196,222 @@ func (t *TrillianMapServer) GetLeavesByRevisionNoProof(ctx context.Context, req
 	return &trillian.MapLeaves{Leaves: leaves}, nil
 }
 
-func (t *TrillianMapServer) getLeavesByRevision(ctx context.Context, mapID int64, indices [][]byte, revision int64) (*trillian.GetMapLeavesResponse, error) {
+// ListLeaves implements the ListLeaves RPC method.
+func (t *TrillianMapServer) ListLeaves(ctx context.Context, req *trillian.ListMapLeavesRequest) (*trillian.ListMapLeavesResponse, error) {
+	ctx, spanEnd := spanFor(ctx, "ListLeaves")
+	defer spanEnd()
+	if req.Revision < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "map revision %d must be >= 0", req.Revision)
+	}
+	if req.PageSize < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "page size %d must be >= 0", req.PageSize)
+	}
+	pageSize := int(req.PageSize)
+	switch {
+	case pageSize == 0:
+		pageSize = defaultListLeavesPageSize
+	case pageSize > maxListLeavesPageSize:
+		pageSize = maxListLeavesPageSize
+	}
+
+	tree, hasher, err := t.getTreeAndHasher(ctx, req.MapId, optsMapRead)
+	if err != nil {
+		return nil, fmt.Errorf("could not get map %v: %v", req.MapId, err)
+	}
+	if len(req.StartIndex) > 0 {
+		if err := checkIndexSize(req.StartIndex, hasher); err != nil {
+			return nil, err
+		}
+	}
+	ctx = trees.NewContext(ctx, tree)
+
+	tx, err := t.snapshotForTree(ctx, tree, "ListLeaves")
+	if err != nil {
+		return nil, fmt.Errorf("could not create database snapshot: %v", err)
+	}
+	defer t.closeAndLog(ctx, tree.TreeId, tx, "ListLeaves")
+
+	root, err := tx.GetSignedMapRoot(ctx, req.Revision)
+	if err != nil {
+		return nil, err
+	}
+	// Read one more leaf than requested, to find the start of the next page.
+	leaves, err := tx.ListLeaves(ctx, req.Revision, req.StartIndex, pageSize+1)
+	if err != nil {
+		return nil, err
+	}
+	var nextIndex []byte
+	if len(leaves) > pageSize {
+		nextIndex = leaves[pageSize].Index
+		leaves = leaves[:pageSize]
+	}
+
+	if err := tx.Commit(); err != nil {
+		glog.Warningf("%v: Commit failed for ListLeaves: %v", req.MapId, err)
+		return nil, err
+	}
+
+	return &trillian.ListMapLeavesResponse{
+		Leaves:    leaves,
+		NextIndex: nextIndex,
+		MapRoot:   &root,
+	}, nil
+}
+
+// GetMapDiff implements the GetMapDiff RPC method. It sends the leaves set
+// between the two revisions in batches, and skips those that were set to the
+// same value as they had at the from revision.
+func (t *TrillianMapServer) GetMapDiff(req *trillian.GetMapDiffRequest, stream trillian.TrillianMap_GetMapDiffServer) error {
+	ctx, spanEnd := spanFor(stream.Context(), "GetMapDiff")
+	defer spanEnd()
+	if req.FromRevision < 0 {
+		return status.Errorf(codes.InvalidArgument, "from revision %d must be >= 0", req.FromRevision)
+	}
+	if req.ToRevision <= req.FromRevision {
+		return status.Errorf(codes.InvalidArgument, "to revision %d must be > from revision %d", req.ToRevision, req.FromRevision)
+	}
+	tree, hasher, err := t.getTreeAndHasher(ctx, req.MapId, optsMapRead)
+	if err != nil {
+		return err
+	}
+	ctx = trees.NewContext(ctx, tree)
+
+	var start []byte
+	for first := true; ; first = false {
+		r, next, err := t.getMapDiffBatch(ctx, tree, hasher, req.FromRevision, req.ToRevision, start)
+		if err != nil {
+			return err
+		}
+		if !first {
+			r.FromRoot, r.ToRoot = nil, nil
+		}
+		if first || len(r.Diffs) > 0 {
+			if err := stream.Send(r); err != nil {
+				return err
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		start = next
+	}
+}
+
+// getMapDiffBatch returns the map roots at both revisions, along with the
+// diffs of up to GetMapDiffBatchSize of the leaves set between them, starting
+// at index start. It also returns the index of the next leaf set between the
+// revisions, or nil if there are no more.
+func (t *TrillianMapServer) getMapDiffBatch(ctx context.Context, tree *trillian.Tree, hasher hashers.MapHasher, from, to int64, start []byte) (*trillian.GetMapDiffResponse, []byte, error) {
+	r, indexes, err := t.getChangedIndexes(ctx, tree, from, to, start)
+	if err != nil {
+		return nil, nil, err
+	}
+	var next []byte
+	if len(indexes) > GetMapDiffBatchSize {
+		next = indexes[GetMapDiffBatchSize]
+		indexes = indexes[:GetMapDiffBatchSize]
+	}
+	// The subtree cache of a transaction holds the nodes of a single revision,
+	// so the proofs for each revision are read in separate transactions.
+	oldLeaves, err := t.getInclusions(ctx, tree, hasher, from, indexes)
+	if err != nil {
+		return nil, nil, err
+	}
+	newLeaves, err := t.getInclusions(ctx, tree, hasher, to, indexes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i, index := range indexes {
+		if proto.Equal(oldLeaves[i].Leaf, newLeaves[i].Leaf) {
+			continue
+		}
+		r.Diffs = append(r.Diffs, &trillian.MapLeafDiff{
+			Index:   index,
+			OldLeaf: oldLeaves[i],
+			NewLeaf: newLeaves[i],
+		})
+	}
+	return r, next, nil
+}
+
+// getChangedIndexes returns a GetMapDiffResponse holding the map roots at both
+// revisions, along with up to GetMapDiffBatchSize+1 of the indexes of the
+// leaves set between them, starting at index start.
+func (t *TrillianMapServer) getChangedIndexes(ctx context.Context, tree *trillian.Tree, from, to int64, start []byte) (*trillian.GetMapDiffResponse, [][]byte, error) {
+	tx, err := t.snapshotForTree(ctx, tree, "GetMapDiff")
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not create database snapshot: %v", err)
+	}
+	defer t.closeAndLog(ctx, tree.TreeId, tx, "GetMapDiff")
+
+	fromRoot, err := tx.GetSignedMapRoot(ctx, from)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not fetch SignedMapRoot %v: %v", from, err)
+	}
+	toRoot, err := tx.GetSignedMapRoot(ctx, to)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not fetch SignedMapRoot %v: %v", to, err)
+	}
+	indexes, err := tx.ListChangedIndexes(ctx, from, to, start, GetMapDiffBatchSize+1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("could not commit db transaction: %v", err)
+	}
+	return &trillian.GetMapDiffResponse{FromRoot: &fromRoot, ToRoot: &toRoot}, indexes, nil
+}
+
+// getInclusions returns the leaves at the given indexes and revision, along
+// with their inclusion proofs. Leaves that are not set are returned empty.
+func (t *TrillianMapServer) getInclusions(ctx context.Context, tree *trillian.Tree, hasher hashers.MapHasher, revision int64, indexes [][]byte) ([]*trillian.MapLeafInclusion, error) {
+	if len(indexes) == 0 {
+		return nil, nil
+	}
+	tx, err := t.snapshotForTree(ctx, tree, "GetMapDiff")
+	if err != nil {
+		return nil, fmt.Errorf("could not create database snapshot: %v", err)
+	}
+	defer t.closeAndLog(ctx, tree.TreeId, tx, "GetMapDiff")
+
+	leaves, err := tx.Get(ctx, revision, indexes)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch leaves: %v", err)
+	}
+	leavesByIndex := make(map[string]*trillian.MapLeaf)
+	for _, l := range leaves {
+		leavesByIndex[string(l.Index)] = l
+	}
+	smtReader := merkle.NewSparseMerkleTreeReader(revision, hasher, tx)
+	proofs, err := smtReader.BatchInclusionProof(ctx, revision, indexes)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch inclusion proofs: %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("could not commit db transaction: %v", err)
+	}
+
+	inclusions := make([]*trillian.MapLeafInclusion, len(indexes))
+	for i, index := range indexes {
+		leaf, ok := leavesByIndex[string(index)]
+		if !ok {
+			leaf = &trillian.MapLeaf{Index: index}
+		}
+		inclusions[i] = &trillian.MapLeafInclusion{
+			Leaf:      leaf,
+			Inclusion: proofs[string(index)],
+		}
+	}
+	return inclusions, nil
+}
+
+// getLeavesByRevision returns the leaves at the given indices with their
+// inclusion proofs. If compact is set, a single batch proof for all of the
+// leaves is returned instead of one proof per leaf.
+func (t *TrillianMapServer) getLeavesByRevision(ctx context.Context, mapID int64, indices [][]byte, revision int64, compact bool) (*trillian.GetMapLeavesResponse, error) {
 	tree, hasher, err := t.getTreeAndHasher(ctx, mapID, optsMapRead)
 	if err != nil {
 		return nil, fmt.Errorf("could not get map %v: %v", mapID, err)
@@ -262,6 +490,7 @@ func (t *TrillianMapServer) getLeavesByRevision(ctx context.Context, mapID int64
 	////////////////////////////////////////////////////
 	// Inclusion proofs
 	var proofs map[string][][]byte
+	var batchProof [][]byte
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -269,7 +498,11 @@ func (t *TrillianMapServer) getLeavesByRevision(ctx context.Context, mapID int64
 		var err error
 		// Fetch inclusion proofs in parallel.
 		smtReader := merkle.NewSparseMerkleTreeReader(revision, hasher, tx)
-		proofs, err = smtReader.BatchInclusionProof(ctx, revision, indices)
+		if compact {
+			batchProof, err = smtReader.CompactBatchInclusionProof(ctx, revision, indices)
+		} else {
+			proofs, err = smtReader.BatchInclusionProof(ctx, revision, indices)
+		}
 		if err != nil {
 			errCh <- fmt.Errorf("could not fetch inclusion proofs: %v", err)
 		}
@@ -301,6 +534,7 @@ func (t *TrillianMapServer) getLeavesByRevision(ctx context.Context, mapID int64
 	return &trillian.GetMapLeavesResponse{
 		MapLeafInclusion: inclusions,
 		MapRoot:          root,
+		BatchInclusion:   batchProof,
 	}, nil
 }
 
@@ -15,6 +15,7 @@
 package server
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"testing"
@@ -26,6 +27,7 @@ import (
 	"github.com/google/trillian/storage"
 	stestonly "github.com/google/trillian/storage/testonly"
 	"github.com/kylelemons/godebug/pretty"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -336,6 +338,226 @@ func TestGetSignedMapRootByRevision(t *testing.T) {
 	}
 }
 
+func TestListLeaves(t *testing.T) {
+	ctx := context.Background()
+	index := func(b byte) []byte { return bytes.Repeat([]byte{b}, 32) }
+	leaves := []*trillian.MapLeaf{
+		{Index: index(1), LeafValue: []byte("one")},
+		{Index: index(2), LeafValue: []byte("two")},
+		{Index: index(3), LeafValue: []byte("three")},
+	}
+	mapRoot := trillian.SignedMapRoot{MapRoot: []byte("root"), Signature: []byte("sig")}
+
+	tests := []struct {
+		desc      string
+		req       *trillian.ListMapLeavesRequest
+		wantLimit int
+		leaves    []*trillian.MapLeaf
+		rootErr   error
+		want      *trillian.ListMapLeavesResponse
+		wantCode  codes.Code
+	}{
+		{
+			desc:     "negative revision",
+			req:      &trillian.ListMapLeavesRequest{MapId: mapID1, Revision: -1},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc:     "negative page size",
+			req:      &trillian.ListMapLeavesRequest{MapId: mapID1, PageSize: -1},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc:     "bad start index",
+			req:      &trillian.ListMapLeavesRequest{MapId: mapID1, StartIndex: []byte("short")},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc:      "unknown revision",
+			req:       &trillian.ListMapLeavesRequest{MapId: mapID1, Revision: 5},
+			wantLimit: -1,
+			rootErr:   status.Error(codes.NotFound, "no such revision"),
+			wantCode:  codes.NotFound,
+		},
+		{
+			desc:      "default page size",
+			req:       &trillian.ListMapLeavesRequest{MapId: mapID1, Revision: 1},
+			wantLimit: defaultListLeavesPageSize + 1,
+			leaves:    leaves,
+			want:      &trillian.ListMapLeavesResponse{Leaves: leaves, MapRoot: &mapRoot},
+		},
+		{
+			desc:      "max page size",
+			req:       &trillian.ListMapLeavesRequest{MapId: mapID1, Revision: 1, PageSize: maxListLeavesPageSize + 1},
+			wantLimit: maxListLeavesPageSize + 1,
+			leaves:    leaves,
+			want:      &trillian.ListMapLeavesResponse{Leaves: leaves, MapRoot: &mapRoot},
+		},
+		{
+			desc:      "first page",
+			req:       &trillian.ListMapLeavesRequest{MapId: mapID1, Revision: 1, PageSize: 2},
+			wantLimit: 3,
+			leaves:    leaves,
+			want:      &trillian.ListMapLeavesResponse{Leaves: leaves[:2], NextIndex: index(3), MapRoot: &mapRoot},
+		},
+		{
+			desc:      "last page",
+			req:       &trillian.ListMapLeavesRequest{MapId: mapID1, Revision: 1, StartIndex: index(3), PageSize: 2},
+			wantLimit: 3,
+			leaves:    leaves[2:],
+			want:      &trillian.ListMapLeavesResponse{Leaves: leaves[2:], MapRoot: &mapRoot},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			fakeStorage := storage.NewMockMapStorage(ctrl)
+			if test.wantLimit != 0 {
+				mockTX := storage.NewMockMapTreeTX(ctrl)
+				fakeStorage.EXPECT().SnapshotForTree(gomock.Any(), gomock.Any()).Return(mockTX, nil)
+				mockTX.EXPECT().GetSignedMapRoot(gomock.Any(), test.req.Revision).Return(mapRoot, test.rootErr)
+				if test.rootErr == nil {
+					mockTX.EXPECT().ListLeaves(gomock.Any(), test.req.Revision, test.req.StartIndex, test.wantLimit).Return(test.leaves, nil)
+					mockTX.EXPECT().Commit().Return(nil)
+				}
+				mockTX.EXPECT().Close().Return(nil)
+			}
+
+			server := NewTrillianMapServer(extension.Registry{
+				AdminStorage: fakeAdminStorageForMap(ctrl, 1, mapID1),
+				MapStorage:   fakeStorage,
+			}, TrillianMapServerOptions{})
+
+			got, err := server.ListLeaves(ctx, test.req)
+			if status.Code(err) != test.wantCode {
+				t.Fatalf("ListLeaves() returned err = %v, want code %v", err, test.wantCode)
+			}
+			if err != nil {
+				return
+			}
+			if !proto.Equal(got, test.want) {
+				t.Errorf("ListLeaves() diff:\n%v", pretty.Compare(got, test.want))
+			}
+		})
+	}
+}
+
+// fakeGetMapDiffServer collects the responses sent by GetMapDiff.
+type fakeGetMapDiffServer struct {
+	grpc.ServerStream
+	got []*trillian.GetMapDiffResponse
+}
+
+func (f *fakeGetMapDiffServer) Context() context.Context {
+	return context.Background()
+}
+
+func (f *fakeGetMapDiffServer) Send(r *trillian.GetMapDiffResponse) error {
+	f.got = append(f.got, r)
+	return nil
+}
+
+func TestGetMapDiff(t *testing.T) {
+	defer func(size int) { GetMapDiffBatchSize = size }(GetMapDiffBatchSize)
+	GetMapDiffBatchSize = 2
+
+	index := func(b byte) []byte { return bytes.Repeat([]byte{b}, 32) }
+	leaf := func(b byte, value string) *trillian.MapLeaf {
+		return &trillian.MapLeaf{Index: index(b), LeafValue: []byte(value)}
+	}
+	fromRoot := trillian.SignedMapRoot{MapRoot: []byte("root1")}
+	toRoot := trillian.SignedMapRoot{MapRoot: []byte("root3")}
+
+	for _, test := range []struct {
+		desc     string
+		req      *trillian.GetMapDiffRequest
+		want     []*trillian.GetMapDiffResponse
+		wantCode codes.Code
+	}{
+		{
+			desc:     "negative revision",
+			req:      &trillian.GetMapDiffRequest{MapId: mapID1, FromRevision: -1, ToRevision: 3},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc:     "reversed revisions",
+			req:      &trillian.GetMapDiffRequest{MapId: mapID1, FromRevision: 3, ToRevision: 1},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc: "ok",
+			req:  &trillian.GetMapDiffRequest{MapId: mapID1, FromRevision: 1, ToRevision: 3},
+			want: []*trillian.GetMapDiffResponse{
+				{
+					FromRoot: &fromRoot,
+					ToRoot:   &toRoot,
+					Diffs: []*trillian.MapLeafDiff{
+						{Index: index(1), OldLeaf: &trillian.MapLeafInclusion{Leaf: leaf(1, "a")}, NewLeaf: &trillian.MapLeafInclusion{Leaf: leaf(1, "b")}},
+						{Index: index(2), OldLeaf: &trillian.MapLeafInclusion{Leaf: &trillian.MapLeaf{Index: index(2)}}, NewLeaf: &trillian.MapLeafInclusion{Leaf: leaf(2, "c")}},
+					},
+				},
+				{
+					// Index 3 was set to the same value, so it is skipped.
+					Diffs: []*trillian.MapLeafDiff{
+						{Index: index(4), OldLeaf: &trillian.MapLeafInclusion{Leaf: leaf(4, "e")}, NewLeaf: &trillian.MapLeafInclusion{Leaf: leaf(4, "f")}},
+					},
+				},
+			},
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			fakeStorage := storage.NewMockMapStorage(ctrl)
+			if test.wantCode == codes.OK {
+				mockTX := storage.NewMockMapTreeTX(ctrl)
+				// Each batch reads the changed indexes, and the proofs at each
+				// revision, in separate transactions.
+				fakeStorage.EXPECT().SnapshotForTree(gomock.Any(), gomock.Any()).Times(6).Return(mockTX, nil)
+				mockTX.EXPECT().GetSignedMapRoot(gomock.Any(), int64(1)).Times(2).Return(fromRoot, nil)
+				mockTX.EXPECT().GetSignedMapRoot(gomock.Any(), int64(3)).Times(2).Return(toRoot, nil)
+				mockTX.EXPECT().ListChangedIndexes(gomock.Any(), int64(1), int64(3), nil, 3).Return([][]byte{index(1), index(2), index(3)}, nil)
+				mockTX.EXPECT().ListChangedIndexes(gomock.Any(), int64(1), int64(3), index(3), 3).Return([][]byte{index(3), index(4)}, nil)
+				mockTX.EXPECT().Get(gomock.Any(), int64(1), [][]byte{index(1), index(2)}).Return([]*trillian.MapLeaf{leaf(1, "a")}, nil)
+				mockTX.EXPECT().Get(gomock.Any(), int64(3), [][]byte{index(1), index(2)}).Return([]*trillian.MapLeaf{leaf(1, "b"), leaf(2, "c")}, nil)
+				mockTX.EXPECT().Get(gomock.Any(), int64(1), [][]byte{index(3), index(4)}).Return([]*trillian.MapLeaf{leaf(3, "d"), leaf(4, "e")}, nil)
+				mockTX.EXPECT().Get(gomock.Any(), int64(3), [][]byte{index(3), index(4)}).Return([]*trillian.MapLeaf{leaf(3, "d"), leaf(4, "f")}, nil)
+				// The tree is empty, so all the proofs are empty too.
+				mockTX.EXPECT().GetMerkleNodes(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes().Return(nil, nil)
+				mockTX.EXPECT().Commit().Times(6).Return(nil)
+				mockTX.EXPECT().Close().Times(6).Return(nil)
+			}
+
+			server := NewTrillianMapServer(extension.Registry{
+				AdminStorage: fakeAdminStorageForMap(ctrl, 1, mapID1),
+				MapStorage:   fakeStorage,
+			}, TrillianMapServerOptions{})
+
+			stream := &fakeGetMapDiffServer{}
+			err := server.GetMapDiff(test.req, stream)
+			if status.Code(err) != test.wantCode {
+				t.Fatalf("GetMapDiff() returned err = %v, want code %v", err, test.wantCode)
+			}
+			if len(stream.got) != len(test.want) {
+				t.Fatalf("GetMapDiff() sent %d responses, want %d", len(stream.got), len(test.want))
+			}
+			for i, got := range stream.got {
+				// Drop the proofs, which are all empty.
+				for _, d := range got.Diffs {
+					d.OldLeaf.Inclusion, d.NewLeaf.Inclusion = nil, nil
+				}
+				if want := test.want[i]; !proto.Equal(got, want) {
+					t.Errorf("GetMapDiff() response %d diff:\n%v", i, pretty.Compare(got, want))
+				}
+			}
+		})
+	}
+}
+
 func fakeAdminStorageForMap(ctrl *gomock.Controller, times int, treeID int64) storage.AdminStorage {
 	tree := *stestonly.MapTree
 	tree.TreeId = treeID
@@ -0,0 +1,52 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"flag"
+
+	"github.com/golang/glog"
+	"github.com/google/trillian/quota"
+	"github.com/google/trillian/quota/memqm"
+	"github.com/google/trillian/util/clock"
+)
+
+// QuotaMemory represents the in-process, token bucket quota implementation.
+const QuotaMemory = "memory"
+
+var memQuotaConfig = flag.String("memory_quota_config", "", "Path to the JSON memqm.Config holding the token buckets of each quota. "+
+	"The file is reloaded on SIGHUP, and rewritten by changes made with the Quota service. "+
+	"Only effective for quota_system=memory.")
+
+func init() {
+	if err := RegisterQuotaManager(QuotaMemory, newMemoryQuotaManager); err != nil {
+		glog.Fatalf("Failed to register quota manager %v: %v", QuotaMemory, err)
+	}
+}
+
+func newMemoryQuotaManager() (quota.Manager, error) {
+	if *memQuotaConfig == "" {
+		return nil, errors.New("can't create memory quota manager - memory_quota_config flag is unset")
+	}
+	qm, err := memqm.NewFromFile(*memQuotaConfig, clock.System)
+	if err != nil {
+		return nil, err
+	}
+	memqm.ReloadOnSIGHUP(context.Background(), qm, *memQuotaConfig)
+	glog.Info("Using memory QuotaManager")
+	return qm, nil
+}
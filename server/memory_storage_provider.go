@@ -44,7 +44,7 @@ func (s *memProvider) LogStorage() storage.LogStorage {
 }
 
 func (s *memProvider) MapStorage() storage.MapStorage {
-	return nil
+	return memory.NewMapStorage(s.ts)
 }
 
 func (s *memProvider) AdminStorage() storage.AdminStorage {
@@ -72,7 +72,8 @@ func (s *pgProvider) LogStorage() storage.LogStorage {
 }
 
 func (s *pgProvider) MapStorage() storage.MapStorage {
-	panic("Not Implemented")
+	glog.Warningf("Support for the PostgreSQL map is experimental.  Please use at your own risk!!!")
+	return postgres.NewMapStorage(s.db)
 }
 
 func (s *pgProvider) AdminStorage() storage.AdminStorage {
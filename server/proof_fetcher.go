@@ -20,6 +20,7 @@ import (
 
 	"github.com/google/trillian"
 	"github.com/google/trillian/merkle"
+	"github.com/google/trillian/merkle/compact"
 	"github.com/google/trillian/merkle/hashers"
 	"github.com/google/trillian/storage"
 )
@@ -45,6 +46,45 @@ func fetchNodesAndBuildProof(ctx context.Context, tx storage.NodeReader, th hash
 	return r.rehashedProof(leafIndex)
 }
 
+// fetchNodesAndBuildProofs is the batch version of fetchNodesAndBuildProof.
+// It reads the nodes of the inclusion proofs for several leaves from storage
+// at once, fetching nodes shared by the proofs only once, and returns the
+// proof for each of leafIndices.
+func fetchNodesAndBuildProofs(ctx context.Context, tx storage.NodeReader, th hashers.LogHasher, treeRevision int64, leafIndices []int64, proofNodeFetches [][]merkle.NodeFetch) ([]trillian.Proof, error) {
+	ctx, spanEnd := spanFor(ctx, "fetchNodesAndBuildProofs")
+	defer spanEnd()
+	var fetches []merkle.NodeFetch
+	seen := make(map[string]bool)
+	for _, proofFetches := range proofNodeFetches {
+		for _, fetch := range proofFetches {
+			if key := fetch.NodeID.AsKey(); !seen[key] {
+				seen[key] = true
+				fetches = append(fetches, fetch)
+			}
+		}
+	}
+	nodes, err := fetchNodes(ctx, tx, treeRevision, fetches)
+	if err != nil {
+		return nil, err
+	}
+	nodeMap := make(map[string]storage.Node, len(nodes))
+	for _, node := range nodes {
+		nodeMap[node.NodeID.AsKey()] = node
+	}
+
+	proofs := make([]trillian.Proof, len(proofNodeFetches))
+	for i, proofFetches := range proofNodeFetches {
+		r := &rehasher{th: th}
+		for _, fetch := range proofFetches {
+			r.process(nodeMap[fetch.NodeID.AsKey()], fetch)
+		}
+		if proofs[i], err = r.rehashedProof(leafIndices[i]); err != nil {
+			return nil, err
+		}
+	}
+	return proofs, nil
+}
+
 // rehasher bundles the rehashing logic into a simple state machine
 type rehasher struct {
 	th         hashers.LogHasher
@@ -129,3 +169,29 @@ func fetchNodes(ctx context.Context, tx storage.NodeReader, treeRevision int64,
 
 	return proofNodes, nil
 }
+
+// fetchRangeHashes returns the hashes of the given nodes, which must be roots
+// of perfect subtrees, such as the nodes of a compact range. No rehashing is
+// needed as these nodes are never ephemeral.
+func fetchRangeHashes(ctx context.Context, tx storage.NodeReader, treeRevision int64, ids []compact.NodeID) ([][]byte, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	fetches := make([]merkle.NodeFetch, 0, len(ids))
+	for _, id := range ids {
+		nodeID, err := storage.NewNodeIDForTreeCoords(int64(id.Level), int64(id.Index), proofMaxBitLen)
+		if err != nil {
+			return nil, err
+		}
+		fetches = append(fetches, merkle.NodeFetch{NodeID: nodeID})
+	}
+	nodes, err := fetchNodes(ctx, tx, treeRevision, fetches)
+	if err != nil {
+		return nil, err
+	}
+	hashes := make([][]byte, 0, len(nodes))
+	for _, node := range nodes {
+		hashes = append(hashes, node.Hash)
+	}
+	return hashes, nil
+}
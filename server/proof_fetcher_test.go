@@ -249,6 +249,66 @@ func TestTree32InclusionProofFetchMultiBatch(t *testing.T) {
 	}
 }
 
+func TestTree32BatchInclusionProofFetchAll(t *testing.T) {
+	ctx := context.Background()
+	hasher := rfc6962.DefaultHasher
+	v := merkle.NewLogVerifier(hasher)
+	for ts := 2; ts <= 32; ts++ {
+		mt := treeAtSize(ts)
+		r := testonly.NewMultiFakeNodeReaderFromLeaves([]testonly.LeafBatch{
+			{TreeRevision: testTreeRevision, Leaves: expandLeaves(0, ts-1), ExpectedRoot: expectedRootAtSize(mt)},
+		})
+
+		for s := int64(2); s <= int64(ts); s++ {
+			for step := int64(1); step <= 3; step++ {
+				var indices []int64
+				var fetches [][]merkle.NodeFetch
+				for l := int64(0); l < s; l += step {
+					f, err := merkle.CalcInclusionProofNodeAddresses(s, l, int64(ts), 64)
+					if err != nil {
+						t.Fatal(err)
+					}
+					indices = append(indices, l)
+					fetches = append(fetches, f)
+				}
+
+				proofs, err := fetchNodesAndBuildProofs(ctx, r, hasher, testTreeRevision, indices, fetches)
+				if err != nil {
+					t.Fatalf("(%d, %d, %d): fetchNodesAndBuildProofs(): %v", ts, s, step, err)
+				}
+
+				leafHashes := make([][]byte, len(indices))
+				hashes := make([][][]byte, len(indices))
+				for i, l := range indices {
+					if got, want := proofs[i].LeafIndex, l; got != want {
+						t.Fatalf("(%d, %d, %d): got proof for leaf %d, want %d", ts, s, step, got, want)
+					}
+					// We use +1 here because of the 1 based leaf indexing of this implementation
+					refProof := mt.PathToRootAtSnapshot(l+1, s)
+					if got, want := len(proofs[i].Hashes), len(refProof); got != want {
+						t.Fatalf("(%d, %d, %d): got proof len: %d, want: %d", ts, s, l, got, want)
+					}
+					for j := range refProof {
+						if got, want := hex.EncodeToString(proofs[i].Hashes[j]), hex.EncodeToString(refProof[j].Value.Hash()); got != want {
+							t.Fatalf("(%d, %d, %d): %d got proof node: %s, want: %s", ts, s, l, j, got, want)
+						}
+					}
+					leafHashes[i] = mt.LeafHash(l + 1)
+					hashes[i] = proofs[i].Hashes
+				}
+
+				batch, err := merkle.MergeInclusionProofs(indices, s, hashes)
+				if err != nil {
+					t.Fatalf("(%d, %d, %d): MergeInclusionProofs(): %v", ts, s, step, err)
+				}
+				if err := v.VerifyBatchInclusionProof(indices, s, batch, mt.RootAtSnapshot(s).Hash(), leafHashes); err != nil {
+					t.Errorf("(%d, %d, %d): VerifyBatchInclusionProof(): %v", ts, s, step, err)
+				}
+			}
+		}
+	}
+}
+
 func TestTree32ConsistencyProofFetchAll(t *testing.T) {
 	ctx := context.Background()
 	hasher := rfc6962.DefaultHasher
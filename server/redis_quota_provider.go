@@ -0,0 +1,71 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/google/trillian/quota"
+	"github.com/google/trillian/quota/memqm"
+	"github.com/google/trillian/quota/redisqm"
+)
+
+// QuotaRedis represents the Redis-backed, token bucket quota implementation.
+const QuotaRedis = "redis"
+
+var (
+	redisQuotaAddress = flag.String("redis_quota_address", "", "Address (host:port) of the Redis server holding quota buckets. "+
+		"Only effective for quota_system=redis.")
+	redisQuotaPassword = flag.String("redis_quota_password", "", "Password of the Redis server holding quota buckets, if any. "+
+		"Only effective for quota_system=redis.")
+	redisQuotaConfig = flag.String("redis_quota_config", "", "Path to the JSON memqm.Config holding the token buckets of each quota. "+
+		"It's only used until a config is saved to Redis with the Quota service. Only effective for quota_system=redis.")
+	redisQuotaConfigReload = flag.Duration("redis_quota_config_reload_interval", 10*time.Second, "Interval between reloads of the quota config saved to Redis. "+
+		"Only effective for quota_system=redis.")
+)
+
+func init() {
+	if err := RegisterQuotaManager(QuotaRedis, newRedisQuotaManager); err != nil {
+		glog.Fatalf("Failed to register quota manager %v: %v", QuotaRedis, err)
+	}
+}
+
+func newRedisQuotaManager() (quota.Manager, error) {
+	if *redisQuotaAddress == "" {
+		return nil, errors.New("can't create redis quota manager - redis_quota_address flag is unset")
+	}
+	if *redisQuotaConfig == "" {
+		return nil, errors.New("can't create redis quota manager - redis_quota_config flag is unset")
+	}
+	cfg, err := memqm.LoadConfig(*redisQuotaConfig)
+	if err != nil {
+		return nil, err
+	}
+	qm, err := redisqm.New(redisqm.NewClient(*redisQuotaAddress, *redisQuotaPassword), cfg)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	if err := qm.ReloadConfig(ctx); err != nil {
+		glog.Warningf("Using the quota config of %q: %v", *redisQuotaConfig, err)
+	}
+	redisqm.ReloadPeriodically(ctx, qm, *redisQuotaConfigReload)
+	glog.Info("Using Redis QuotaManager")
+	return qm, nil
+}
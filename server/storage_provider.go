@@ -61,10 +61,11 @@ func NewStorageProviderFromFlags(mf monitoring.MetricFactory) (StorageProvider,
 // NewStorageProvider returns a new StorageProvider instance of the type
 // specified by name.
 func NewStorageProvider(name string, mf monitoring.MetricFactory) (StorageProvider, error) {
+	// Don't hold the lock while creating the provider, as it may create other
+	// providers itself.
 	spMu.RLock()
-	defer spMu.RUnlock()
-
 	sp := spByName[name]
+	spMu.RUnlock()
 	if sp == nil {
 		return nil, fmt.Errorf("no such storage provider %v", name)
 	}
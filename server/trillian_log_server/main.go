@@ -18,24 +18,27 @@ package main
 
 import (
 	"context"
+	"crypto"
 	"flag"
+	"fmt"
 	_ "net/http/pprof" // Register pprof HTTP handlers.
 	"os"
 	"runtime/pprof"
+	"strings"
 	"time"
 
 	"github.com/golang/glog"
-	"github.com/golang/protobuf/proto"
 	"github.com/google/trillian"
 	"github.com/google/trillian/cmd"
-	"github.com/google/trillian/crypto/keys/der"
-	"github.com/google/trillian/crypto/keyspb"
+	"github.com/google/trillian/crypto/keys/pem"
 	"github.com/google/trillian/extension"
+	"github.com/google/trillian/log"
 	"github.com/google/trillian/monitoring"
 	"github.com/google/trillian/monitoring/opencensus"
 	"github.com/google/trillian/monitoring/prometheus"
 	"github.com/google/trillian/quota/etcd/quotaapi"
 	"github.com/google/trillian/quota/etcd/quotapb"
+	"github.com/google/trillian/quota/quotaadmin"
 	"github.com/google/trillian/server"
 	"github.com/google/trillian/util/clock"
 	"github.com/google/trillian/util/etcd"
@@ -44,6 +47,7 @@ import (
 
 	// Register key ProtoHandlers
 	_ "github.com/google/trillian/crypto/keys/der/proto"
+	envproto "github.com/google/trillian/crypto/keys/envelope/proto"
 	_ "github.com/google/trillian/crypto/keys/pem/proto"
 	_ "github.com/google/trillian/crypto/keys/pkcs11/proto"
 
@@ -57,6 +61,7 @@ var (
 	healthzTimeout  = flag.Duration("healthz_timeout", time.Second*5, "Timeout used during healthz checks")
 	tlsCertFile     = flag.String("tls_cert_file", "", "Path to the TLS server certificate. If unset, the server will use unsecured connections.")
 	tlsKeyFile      = flag.String("tls_key_file", "", "Path to the TLS server key. If unset, the server will use unsecured connections.")
+	tlsClientCAFile = flag.String("tls_client_ca_file", "", "Path to the PEM file of CAs whose client certificates are verified and used to identify callers for authorization. Requires tls_cert_file and tls_key_file.")
 	etcdService     = flag.String("etcd_service", "trillian-logserver", "Service name to announce ourselves under")
 	etcdHTTPService = flag.String("etcd_http_service", "trillian-logserver-http", "Service name to announce our HTTP endpoint under")
 
@@ -66,6 +71,11 @@ var (
 	treeDeleteThreshold      = flag.Duration("tree_delete_threshold", server.DefaultTreeDeleteThreshold, "Minimum period a tree has to remain deleted before being hard-deleted")
 	treeDeleteMinRunInterval = flag.Duration("tree_delete_min_run_interval", server.DefaultTreeDeleteMinInterval, "Minimum interval between tree garbage collection sweeps. Actual runs happen randomly between [minInterval,2*minInterval).")
 
+	queueSweepInterval  = flag.Duration("queue_sweep_interval", time.Minute, "Interval between sweeps that expire queued leaves older than their log's queue_ttl; zero disables the sweeper")
+	queueSweepBatchSize = flag.Int("queue_sweep_batch_size", log.DefaultQueueSweepBatchSize, "Maximum number of queued leaves expired in a single storage transaction")
+
+	witnessKeys = flag.String("witness_keys", "", "Comma-separated list of name=path pairs, one per witness allowed to cosign log roots, where path is a PEM file holding the witness's public key")
+
 	tracing          = flag.Bool("tracing", false, "If true opencensus Stackdriver tracing will be enabled. See https://opencensus.io/.")
 	tracingProjectID = flag.String("tracing_project_id", "", "project ID to pass to stackdriver. Can be empty for GCP, consult docs for other platforms.")
 	tracingPercent   = flag.Int("tracing_percent", 0, "Percent of requests to be traced. Zero is a special case to use the DefaultSampler")
@@ -126,14 +136,23 @@ func main() {
 		glog.Exitf("Error creating quota manager: %v", err)
 	}
 
+	authz, err := server.NewAuthorizerFromFlags()
+	if err != nil {
+		glog.Exitf("Error creating authorizer: %v", err)
+	}
+
+	witnesses, err := readWitnessKeys(*witnessKeys)
+	if err != nil {
+		glog.Exitf("Failed to read witness keys: %v", err)
+	}
+
 	registry := extension.Registry{
 		AdminStorage:  sp.AdminStorage(),
 		LogStorage:    sp.LogStorage(),
 		QuotaManager:  qm,
+		Authorizer:    authz,
 		MetricFactory: mf,
-		NewKeyProto: func(ctx context.Context, spec *keyspb.Specification) (proto.Message, error) {
-			return der.NewProtoFromSpec(spec)
-		},
+		NewKeyProto:   envproto.NewProtoFromSpec,
 	}
 
 	// Enable CPU profile if requested.
@@ -144,15 +163,16 @@ func main() {
 	}
 
 	m := server.Main{
-		RPCEndpoint:  *rpcEndpoint,
-		HTTPEndpoint: *httpEndpoint,
-		TLSCertFile:  *tlsCertFile,
-		TLSKeyFile:   *tlsKeyFile,
-		StatsPrefix:  "log",
-		ExtraOptions: options,
-		QuotaDryRun:  *quotaDryRun,
-		DBClose:      sp.Close,
-		Registry:     registry,
+		RPCEndpoint:     *rpcEndpoint,
+		HTTPEndpoint:    *httpEndpoint,
+		TLSCertFile:     *tlsCertFile,
+		TLSKeyFile:      *tlsKeyFile,
+		TLSClientCAFile: *tlsClientCAFile,
+		StatsPrefix:     "log",
+		ExtraOptions:    options,
+		QuotaDryRun:     *quotaDryRun,
+		DBClose:         sp.Close,
+		Registry:        registry,
 		RegisterHandlerFn: func(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error {
 			if err := trillian.RegisterTrillianLogHandlerFromEndpoint(ctx, mux, endpoint, opts); err != nil {
 				return err
@@ -164,12 +184,15 @@ func main() {
 		},
 		RegisterServerFn: func(s *grpc.Server, registry extension.Registry) error {
 			logServer := server.NewTrillianLogRPCServer(registry, clock.System)
+			logServer.SetWitnesses(witnesses)
 			if err := logServer.IsHealthy(); err != nil {
 				return err
 			}
 			trillian.RegisterTrillianLogServer(s, logServer)
 			if *server.QuotaSystem == server.QuotaEtcd {
 				quotapb.RegisterQuotaServer(s, quotaapi.NewServer(client))
+			} else if store, err := quotaadmin.NewStore(registry.QuotaManager); err == nil {
+				trillian.RegisterQuotaServer(s, quotaadmin.NewServer(registry.QuotaManager, store))
 			}
 			return nil
 		},
@@ -184,6 +207,13 @@ func main() {
 		TreeDeleteMinInterval: *treeDeleteMinRunInterval,
 	}
 
+	if *queueSweepInterval > 0 {
+		go func() {
+			glog.Info("Queue sweeper started")
+			log.NewQueueSweeper(registry, *queueSweepInterval, *queueSweepBatchSize, clock.System).Run(ctx)
+		}()
+	}
+
 	if err := m.Run(ctx); err != nil {
 		glog.Exitf("Server exited with error: %v", err)
 	}
@@ -201,3 +231,27 @@ func mustCreate(fileName string) *os.File {
 	}
 	return f
 }
+
+// readWitnessKeys parses the value of --witness_keys, and reads the witness
+// public keys it refers to.
+func readWitnessKeys(spec string) (map[string]crypto.PublicKey, error) {
+	witnesses := make(map[string]crypto.PublicKey)
+	if spec == "" {
+		return witnesses, nil
+	}
+	for _, entry := range strings.Split(spec, ",") {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("malformed witness %q, want name=path", entry)
+		}
+		if _, ok := witnesses[parts[0]]; ok {
+			return nil, fmt.Errorf("duplicate witness %q", parts[0])
+		}
+		key, err := pem.ReadPublicKeyFile(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("witness %q: %v", parts[0], err)
+		}
+		witnesses[parts[0]] = key
+	}
+	return witnesses, nil
+}
@@ -44,6 +44,7 @@ import (
 	tpb "github.com/google/trillian"
 	// Register key ProtoHandlers
 	_ "github.com/google/trillian/crypto/keys/der/proto"
+	_ "github.com/google/trillian/crypto/keys/envelope/proto"
 	_ "github.com/google/trillian/crypto/keys/pem/proto"
 	_ "github.com/google/trillian/crypto/keys/pkcs11/proto"
 
@@ -132,11 +133,17 @@ func main() {
 		glog.Exitf("Error creating quota manager: %v", err)
 	}
 
+	authz, err := server.NewAuthorizerFromFlags()
+	if err != nil {
+		glog.Exitf("Error creating authorizer: %v", err)
+	}
+
 	registry := extension.Registry{
 		AdminStorage:    sp.AdminStorage(),
 		LogStorage:      sp.LogStorage(),
 		ElectionFactory: electionFactory,
 		QuotaManager:    qm,
+		Authorizer:      authz,
 		MetricFactory:   mf,
 	}
 
@@ -187,8 +194,8 @@ func main() {
 			// No HTTP APIs are being exported.
 			return nil
 		},
-		RegisterServerFn: func(s *grpc.Server, _ extension.Registry) error {
-			tpb.RegisterTrillianLogSequencerServer(s, &struct{}{})
+		RegisterServerFn: func(s *grpc.Server, registry extension.Registry) error {
+			tpb.RegisterTrillianLogSequencerServer(s, server.NewTrillianLogSequencerRPCServer(registry, sequencerTask))
 			return nil
 		},
 		IsHealthy:       sp.AdminStorage().CheckDatabaseAccessible,
@@ -23,17 +23,15 @@ import (
 	"time"
 
 	"github.com/golang/glog"
-	"github.com/golang/protobuf/proto"
 	"github.com/google/trillian"
 	"github.com/google/trillian/cmd"
-	"github.com/google/trillian/crypto/keys/der"
-	"github.com/google/trillian/crypto/keyspb"
 	"github.com/google/trillian/extension"
 	"github.com/google/trillian/monitoring"
 	"github.com/google/trillian/monitoring/opencensus"
 	"github.com/google/trillian/monitoring/prometheus"
 	"github.com/google/trillian/quota/etcd/quotaapi"
 	"github.com/google/trillian/quota/etcd/quotapb"
+	"github.com/google/trillian/quota/quotaadmin"
 	"github.com/google/trillian/server"
 	"github.com/google/trillian/util/etcd"
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
@@ -41,6 +39,7 @@ import (
 
 	// Register key ProtoHandlers
 	_ "github.com/google/trillian/crypto/keys/der/proto"
+	envproto "github.com/google/trillian/crypto/keys/envelope/proto"
 	_ "github.com/google/trillian/crypto/keys/pem/proto"
 	_ "github.com/google/trillian/crypto/keys/pkcs11/proto"
 
@@ -50,11 +49,12 @@ import (
 )
 
 var (
-	rpcEndpoint    = flag.String("rpc_endpoint", "localhost:8090", "Endpoint for RPC requests (host:port)")
-	httpEndpoint   = flag.String("http_endpoint", "localhost:8091", "Endpoint for HTTP metrics and REST requests on (host:port, empty means disabled)")
-	healthzTimeout = flag.Duration("healthz_timeout", time.Second*5, "Timeout used during healthz checks")
-	tlsCertFile    = flag.String("tls_cert_file", "", "Path to the TLS server certificate. If unset, the server will use unsecured connections.")
-	tlsKeyFile     = flag.String("tls_key_file", "", "Path to the TLS server key. If unset, the server will use unsecured connections.")
+	rpcEndpoint     = flag.String("rpc_endpoint", "localhost:8090", "Endpoint for RPC requests (host:port)")
+	httpEndpoint    = flag.String("http_endpoint", "localhost:8091", "Endpoint for HTTP metrics and REST requests on (host:port, empty means disabled)")
+	healthzTimeout  = flag.Duration("healthz_timeout", time.Second*5, "Timeout used during healthz checks")
+	tlsCertFile     = flag.String("tls_cert_file", "", "Path to the TLS server certificate. If unset, the server will use unsecured connections.")
+	tlsKeyFile      = flag.String("tls_key_file", "", "Path to the TLS server key. If unset, the server will use unsecured connections.")
+	tlsClientCAFile = flag.String("tls_client_ca_file", "", "Path to the PEM file of CAs whose client certificates are verified and used to identify callers for authorization. Requires tls_cert_file and tls_key_file.")
 
 	quotaDryRun = flag.Bool("quota_dry_run", false, "If true no requests are blocked due to lack of tokens")
 
@@ -114,14 +114,18 @@ func main() {
 		glog.Exitf("Error creating quota manager: %v", err)
 	}
 
+	authz, err := server.NewAuthorizerFromFlags()
+	if err != nil {
+		glog.Exitf("Error creating authorizer: %v", err)
+	}
+
 	registry := extension.Registry{
 		AdminStorage:  sp.AdminStorage(),
 		MapStorage:    sp.MapStorage(),
 		QuotaManager:  qm,
+		Authorizer:    authz,
 		MetricFactory: mf,
-		NewKeyProto: func(ctx context.Context, spec *keyspb.Specification) (proto.Message, error) {
-			return der.NewProtoFromSpec(spec)
-		},
+		NewKeyProto:   envproto.NewProtoFromSpec,
 	}
 
 	// Enable CPU profile if requested.
@@ -132,15 +136,16 @@ func main() {
 	}
 
 	m := server.Main{
-		RPCEndpoint:  *rpcEndpoint,
-		HTTPEndpoint: *httpEndpoint,
-		TLSCertFile:  *tlsCertFile,
-		TLSKeyFile:   *tlsKeyFile,
-		StatsPrefix:  "map",
-		ExtraOptions: options,
-		QuotaDryRun:  *quotaDryRun,
-		DBClose:      sp.Close,
-		Registry:     registry,
+		RPCEndpoint:     *rpcEndpoint,
+		HTTPEndpoint:    *httpEndpoint,
+		TLSCertFile:     *tlsCertFile,
+		TLSKeyFile:      *tlsKeyFile,
+		TLSClientCAFile: *tlsClientCAFile,
+		StatsPrefix:     "map",
+		ExtraOptions:    options,
+		QuotaDryRun:     *quotaDryRun,
+		DBClose:         sp.Close,
+		Registry:        registry,
 		RegisterHandlerFn: func(ctx context.Context, mux *runtime.ServeMux, endpoint string, opts []grpc.DialOption) error {
 			if err := trillian.RegisterTrillianMapHandlerFromEndpoint(ctx, mux, endpoint, opts); err != nil {
 				return err
@@ -162,6 +167,8 @@ func main() {
 			trillian.RegisterTrillianMapServer(s, mapServer)
 			if *server.QuotaSystem == server.QuotaEtcd {
 				quotapb.RegisterQuotaServer(s, quotaapi.NewServer(client))
+			} else if store, err := quotaadmin.NewStore(registry.QuotaManager); err == nil {
+				trillian.RegisterQuotaServer(s, quotaadmin.NewServer(registry.QuotaManager, store))
 			}
 			return nil
 		},
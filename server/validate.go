@@ -36,6 +36,24 @@ func validateGetInclusionProofRequest(req *trillian.GetInclusionProofRequest) er
 	return nil
 }
 
+func validateGetBatchInclusionProofRequest(req *trillian.GetBatchInclusionProofRequest) error {
+	if req.TreeSize <= 0 {
+		return status.Errorf(codes.InvalidArgument, "GetBatchInclusionProofRequest.TreeSize: %v, want > 0", req.TreeSize)
+	}
+	if len(req.LeafIndex) == 0 {
+		return status.Error(codes.InvalidArgument, "GetBatchInclusionProofRequest.LeafIndex empty")
+	}
+	for i, leafIndex := range req.LeafIndex {
+		if leafIndex < 0 {
+			return status.Errorf(codes.InvalidArgument, "GetBatchInclusionProofRequest.LeafIndex[%v]: %v, want >= 0", i, leafIndex)
+		}
+		if leafIndex >= req.TreeSize {
+			return status.Errorf(codes.InvalidArgument, "GetBatchInclusionProofRequest.LeafIndex[%v]: %v >= TreeSize: %v, want < ", i, leafIndex, req.TreeSize)
+		}
+	}
+	return nil
+}
+
 func validateGetInclusionProofByHashRequest(req *trillian.GetInclusionProofByHashRequest, hasher hashers.LogHasher) error {
 	if req.TreeSize <= 0 {
 		return status.Errorf(codes.InvalidArgument, "GetInclusionProofByHashRequest.TreeSize: %v, want > 0", req.TreeSize)
@@ -80,6 +98,42 @@ func validateGetLeavesByRangeRequest(req *trillian.GetLeavesByRangeRequest) erro
 	return nil
 }
 
+func validateGetRangeInclusionProofRequest(req *trillian.GetRangeInclusionProofRequest) error {
+	if req.TreeSize <= 0 {
+		return status.Errorf(codes.InvalidArgument, "GetRangeInclusionProofRequest.TreeSize: %v, want > 0", req.TreeSize)
+	}
+	if req.StartIndex < 0 {
+		return status.Errorf(codes.InvalidArgument, "GetRangeInclusionProofRequest.StartIndex: %v, want >= 0", req.StartIndex)
+	}
+	if req.Count <= 0 {
+		return status.Errorf(codes.InvalidArgument, "GetRangeInclusionProofRequest.Count: %v, want > 0", req.Count)
+	}
+	if req.Count > req.TreeSize-req.StartIndex {
+		return status.Errorf(codes.InvalidArgument, "GetRangeInclusionProofRequest.StartIndex+Count: %v+%v, want StartIndex+Count <= TreeSize (%v)", req.StartIndex, req.Count, req.TreeSize)
+	}
+	return nil
+}
+
+func validateStreamLeavesRequest(req *trillian.StreamLeavesRequest) error {
+	if req.StartIndex < 0 {
+		return status.Errorf(codes.InvalidArgument, "StreamLeavesRequest.StartIndex: %v, want >= 0", req.StartIndex)
+	}
+	return nil
+}
+
+func validateAddLogRootCosignatureRequest(req *trillian.AddLogRootCosignatureRequest) error {
+	if req.SignedLogRoot == nil {
+		return status.Error(codes.InvalidArgument, "AddLogRootCosignatureRequest.SignedLogRoot empty")
+	}
+	if req.Cosignature.GetWitness() == "" {
+		return status.Error(codes.InvalidArgument, "AddLogRootCosignatureRequest.Cosignature.Witness empty")
+	}
+	if len(req.Cosignature.GetSignature()) == 0 {
+		return status.Error(codes.InvalidArgument, "AddLogRootCosignatureRequest.Cosignature.Signature empty")
+	}
+	return nil
+}
+
 func validateGetConsistencyProofRequest(req *trillian.GetConsistencyProofRequest) error {
 	if req.FirstTreeSize <= 0 {
 		return status.Errorf(codes.InvalidArgument, "GetConsistencyProofRequest.FirstTreeSize: %v, want > 0", req.FirstTreeSize)
@@ -0,0 +1,156 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/trillian"
+	"github.com/google/trillian/merkle/compact"
+	"github.com/google/trillian/merkle/hashers"
+	"github.com/google/trillian/skylog/storage"
+	"github.com/google/trillian/types"
+	"github.com/google/trillian/util/clock"
+
+	tcrypto "github.com/google/trillian/crypto"
+)
+
+// Coordinator merges the compact ranges produced by BuildWorkers into the
+// compact range of the whole tree, and signs its roots. Ranges can be added in
+// any order, and are merged as soon as all the ranges to their left are. It is
+// safe for concurrent use.
+type Coordinator struct {
+	tw     storage.TreeWriter
+	hasher hashers.LogHasher
+	signer *tcrypto.Signer
+	ts     clock.TimeSource
+
+	mu       sync.Mutex
+	rng      *compact.Range            // The merged range, [0, size).
+	pending  map[uint64]*compact.Range // Ranges waiting to be merged, by begin.
+	revision uint64                    // The revision of the next signed root.
+	err      error                     // Set if merging or storing ranges failed.
+}
+
+// NewCoordinator returns a new Coordinator for a tree whose current compact
+// range is rng, which must begin at 0 and be created by the same
+// RangeFactory as the ranges produced by the BuildWorkers. The nodes that
+// appear when merging ranges are stored with tw. The revisions of the roots
+// signed by the Coordinator start at revision, which must be greater than
+// the revision of any root signed before for the tree.
+func NewCoordinator(tw storage.TreeWriter, hasher hashers.LogHasher, rng *compact.Range, revision uint64, signer *tcrypto.Signer, ts clock.TimeSource) (*Coordinator, error) {
+	if begin := rng.Begin(); begin != 0 {
+		return nil, fmt.Errorf("range begins at %d, want 0", begin)
+	}
+	return &Coordinator{
+		tw:       tw,
+		hasher:   hasher,
+		signer:   signer,
+		ts:       ts,
+		rng:      rng,
+		pending:  make(map[uint64]*compact.Range),
+		revision: revision,
+	}, nil
+}
+
+// Add adds the compact range produced by a BuildWorker, and merges all the
+// ranges that are adjacent to the tree. The nodes created by the merges, which
+// span the borders of the ranges, are stored before Add returns. If merging or
+// storing them fails, the Coordinator can't be used any more, and must be
+// recreated from the stored state.
+func (c *Coordinator) Add(ctx context.Context, rng *compact.Range) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return c.err
+	}
+	if begin, size := rng.Begin(), c.rng.End(); begin < size {
+		return fmt.Errorf("range [%d, %d) overlaps with the tree of size %d", begin, rng.End(), size)
+	}
+	if _, ok := c.pending[rng.Begin()]; ok {
+		return fmt.Errorf("range beginning at %d is already pending", rng.Begin())
+	}
+	c.pending[rng.Begin()] = rng
+
+	var nodes []storage.Node
+	visit := func(id compact.NodeID, hash []byte) {
+		nodes = append(nodes, storage.Node{ID: id, Hash: hash})
+	}
+	for {
+		next, ok := c.pending[c.rng.End()]
+		if !ok {
+			break
+		}
+		delete(c.pending, next.Begin())
+		if err := c.rng.AppendRange(next, visit); err != nil {
+			// The range is no longer pending, and the merged range may be
+			// partially updated.
+			c.err = fmt.Errorf("merging range [%d, %d): %v", next.Begin(), next.End(), err)
+			return c.err
+		}
+	}
+	if len(nodes) != 0 {
+		if err := c.tw.Write(ctx, nodes); err != nil {
+			c.err = fmt.Errorf("writing tree nodes: %v", err)
+			return c.err
+		}
+	}
+	return nil
+}
+
+// Size returns the size of the tree, i.e. the number of leaves covered by the
+// merged ranges.
+func (c *Coordinator) Size() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rng.End()
+}
+
+// Pending returns the number of ranges waiting for the ranges to their left.
+func (c *Coordinator) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.pending)
+}
+
+// SignRoot returns a signed root of the tree covering the merged ranges.
+func (c *Coordinator) SignRoot() (*trillian.SignedLogRoot, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return nil, c.err
+	}
+	hash, err := c.rng.GetRootHash(nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.rng.End() == 0 {
+		hash = c.hasher.EmptyRoot()
+	}
+	root := &types.LogRootV1{
+		TreeSize:       c.rng.End(),
+		RootHash:       hash,
+		TimestampNanos: uint64(c.ts.Now().UnixNano()),
+		Revision:       c.revision,
+	}
+	slr, err := c.signer.SignLogRoot(root)
+	if err != nil {
+		return nil, fmt.Errorf("signing root: %v", err)
+	}
+	c.revision++
+	return slr, nil
+}
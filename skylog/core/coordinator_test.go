@@ -0,0 +1,193 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/trillian/crypto/keys"
+	"github.com/google/trillian/crypto/keyspb"
+	"github.com/google/trillian/merkle/compact"
+	"github.com/google/trillian/merkle/rfc6962"
+	"github.com/google/trillian/merkle/testonly"
+	"github.com/google/trillian/skylog/storage"
+	"github.com/google/trillian/skylog/storage/memory"
+	"github.com/google/trillian/util/clock"
+
+	tcrypto "github.com/google/trillian/crypto"
+)
+
+func newSigner(t *testing.T) *tcrypto.Signer {
+	t.Helper()
+	key, err := keys.NewFromSpec(&keyspb.Specification{Params: &keyspb.Specification_EcdsaParams{}})
+	if err != nil {
+		t.Fatalf("NewFromSpec: %v", err)
+	}
+	return tcrypto.NewSigner(0, key, crypto.SHA256)
+}
+
+type failingTreeWriter struct{}
+
+func (failingTreeWriter) Write(ctx context.Context, nodes []storage.Node) error {
+	return errors.New("write failed")
+}
+
+func TestCoordinator(t *testing.T) {
+	ctx := context.Background()
+	hashes := testonly.NodeHashes()
+	rf := &compact.RangeFactory{Hash: rfc6962.DefaultHasher.HashChildren}
+	ts := clock.NewFake(time.Unix(10, 0))
+	signer := newSigner(t)
+	tw := memory.NewTreeStorage()
+	bw := NewBuildWorker(tw, rf)
+
+	if _, err := NewCoordinator(tw, rfc6962.DefaultHasher, rf.NewEmptyRange(1), 0, signer, ts); err == nil {
+		t.Error("NewCoordinator(begin=1): got nil, want error")
+	}
+	c, err := NewCoordinator(tw, rfc6962.DefaultHasher, rf.NewEmptyRange(0), 0, signer, ts)
+	if err != nil {
+		t.Fatalf("NewCoordinator: %v", err)
+	}
+
+	checkRoot := func(size uint64, hash []byte, revision uint64) {
+		t.Helper()
+		slr, err := c.SignRoot()
+		if err != nil {
+			t.Fatalf("SignRoot: %v", err)
+		}
+		root, err := tcrypto.VerifySignedLogRoot(signer.Public(), crypto.SHA256, slr)
+		if err != nil {
+			t.Fatalf("VerifySignedLogRoot: %v", err)
+		}
+		if root.TreeSize != size || !bytes.Equal(root.RootHash, hash) || root.Revision != revision {
+			t.Errorf("root: got size %d, hash %x, revision %d; want %d, %x, %d", root.TreeSize, root.RootHash, root.Revision, size, hash, revision)
+		}
+		if got, want := root.TimestampNanos, uint64(ts.Now().UnixNano()); got != want {
+			t.Errorf("root: got timestamp %d, want %d", got, want)
+		}
+	}
+	checkRoot(0, rfc6962.DefaultHasher.EmptyRoot(), 0)
+
+	process := func(begin, end int) *compact.Range {
+		t.Helper()
+		rng, err := bw.Process(ctx, BuildJob{RangeStart: uint64(begin), Hashes: hashes[0][begin:end]})
+		if err != nil {
+			t.Fatalf("Process: %v", err)
+		}
+		return rng
+	}
+	// Add the ranges out of order: [5, 8), [2, 5), [0, 2).
+	for _, r := range [][2]int{{5, 8}, {2, 5}} {
+		if err := c.Add(ctx, process(r[0], r[1])); err != nil {
+			t.Fatalf("Add(%v): %v", r, err)
+		}
+	}
+	if err := c.Add(ctx, process(5, 8)); err == nil {
+		t.Error("Add(duplicate): got nil, want error")
+	}
+	if got, want := c.Pending(), 2; got != want {
+		t.Errorf("Pending: got %d, want %d", got, want)
+	}
+	if got := c.Size(); got != 0 {
+		t.Errorf("Size: got %d, want 0", got)
+	}
+	if err := c.Add(ctx, process(0, 2)); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if got, want := c.Size(), uint64(8); got != want {
+		t.Errorf("Size: got %d, want %d", got, want)
+	}
+	if got := c.Pending(); got != 0 {
+		t.Errorf("Pending: got %d, want 0", got)
+	}
+	if err := c.Add(ctx, process(7, 8)); err == nil {
+		t.Error("Add(overlapping): got nil, want error")
+	}
+	ts.Set(time.Unix(20, 0))
+	checkRoot(8, hashes[3][0], 1)
+
+	// A recreated Coordinator continues from the given revision.
+	if c, err = NewCoordinator(tw, rfc6962.DefaultHasher, c.rng, 2, signer, ts); err != nil {
+		t.Fatalf("NewCoordinator: %v", err)
+	}
+	checkRoot(8, hashes[3][0], 2)
+
+	// All the nodes of the perfect tree must be stored.
+	if got, want := tw.Len(), 15; got != want {
+		t.Errorf("stored %d nodes, want %d", got, want)
+	}
+	for level := range hashes {
+		for index, want := range hashes[level] {
+			got, err := tw.Read(ctx, []compact.NodeID{compact.NewNodeID(uint(level), uint64(index))})
+			if err != nil {
+				t.Fatalf("Read: %v", err)
+			}
+			if !bytes.Equal(got[0], want) {
+				t.Errorf("node (%d, %d): got hash %x, want %x", level, index, got[0], want)
+			}
+		}
+	}
+}
+
+func TestCoordinatorWriteFails(t *testing.T) {
+	ctx := context.Background()
+	hashes := testonly.NodeHashes()
+	rf := &compact.RangeFactory{Hash: rfc6962.DefaultHasher.HashChildren}
+	c, err := NewCoordinator(failingTreeWriter{}, rfc6962.DefaultHasher, rf.NewEmptyRange(0), 0, newSigner(t), clock.System)
+	if err != nil {
+		t.Fatalf("NewCoordinator: %v", err)
+	}
+	bw := NewBuildWorker(memory.NewTreeStorage(), rf)
+	for _, begin := range []int{0, 1} {
+		rng, err := bw.Process(ctx, BuildJob{RangeStart: uint64(begin), Hashes: hashes[0][begin : begin+1]})
+		if err != nil {
+			t.Fatalf("Process: %v", err)
+		}
+		if err := c.Add(ctx, rng); begin == 1 && err == nil {
+			t.Error("Add: got nil, want error")
+		}
+	}
+	if _, err := c.SignRoot(); err == nil {
+		t.Error("SignRoot after failed write: got nil, want error")
+	}
+}
+
+func TestCoordinatorMergeFails(t *testing.T) {
+	ctx := context.Background()
+	hashes := testonly.NodeHashes()
+	rf := &compact.RangeFactory{Hash: rfc6962.DefaultHasher.HashChildren}
+	c, err := NewCoordinator(memory.NewTreeStorage(), rfc6962.DefaultHasher, rf.NewEmptyRange(0), 0, newSigner(t), clock.System)
+	if err != nil {
+		t.Fatalf("NewCoordinator: %v", err)
+	}
+	// Ranges from another RangeFactory can't be merged.
+	other := &compact.RangeFactory{Hash: rfc6962.DefaultHasher.HashChildren}
+	bw := NewBuildWorker(memory.NewTreeStorage(), other)
+	rng, err := bw.Process(ctx, BuildJob{RangeStart: 0, Hashes: hashes[0][0:1]})
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if err := c.Add(ctx, rng); err == nil {
+		t.Error("Add(incompatible): got nil, want error")
+	}
+	if _, err := c.SignRoot(); err == nil {
+		t.Error("SignRoot after failed merge: got nil, want error")
+	}
+}
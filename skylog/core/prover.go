@@ -0,0 +1,167 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"math/bits"
+
+	"github.com/google/trillian/merkle/compact"
+	"github.com/google/trillian/skylog/storage"
+)
+
+// Prover builds Merkle tree proofs and root hashes from the node hashes in a
+// tree storage. Only the hashes of perfect subtrees are stored, so the hashes
+// of the other nodes are computed on the fly.
+type Prover struct {
+	tr   storage.TreeReader
+	hash compact.HashFn
+}
+
+// NewProver returns a new Prover for the specified tree.
+func NewProver(tr storage.TreeReader, hash compact.HashFn) *Prover {
+	return &Prover{tr: tr, hash: hash}
+}
+
+// subtree is the [begin, end) range of leaves of a subtree, whose begin is a
+// multiple of the smallest power of two that is not less than its size.
+type subtree struct {
+	begin, end uint64
+}
+
+// nodes returns the IDs of the perfect subtrees covering s, ordered left to
+// right.
+func (s subtree) nodes() []compact.NodeID {
+	ids := make([]compact.NodeID, 0, bits.OnesCount64(s.end-s.begin))
+	for pos, size := s.begin, s.end-s.begin; size != 0; {
+		level := uint(bits.Len64(size)) - 1
+		ids = append(ids, compact.NewNodeID(level, pos>>level))
+		pos += 1 << level
+		size -= 1 << level
+	}
+	return ids
+}
+
+// split returns the index at which the RFC 6962 hashing splits the leaves of
+// s, i.e. begin plus the largest power of two smaller than the size of s.
+func (s subtree) split() uint64 {
+	return s.begin + 1<<(uint(bits.Len64(s.end-s.begin-1))-1)
+}
+
+// InclusionProof returns the inclusion proof for the leaf with the given index
+// in the tree of the given size.
+func (p *Prover) InclusionProof(ctx context.Context, index, size uint64) ([][]byte, error) {
+	if index >= size {
+		return nil, fmt.Errorf("index %d out of range for tree size %d", index, size)
+	}
+	var plan []subtree
+	for s := (subtree{begin: 0, end: size}); s.end-s.begin > 1; {
+		mid := s.split()
+		if index < mid {
+			plan = append(plan, subtree{begin: mid, end: s.end})
+			s.end = mid
+		} else {
+			plan = append(plan, subtree{begin: s.begin, end: mid})
+			s.begin = mid
+		}
+	}
+	// The proof is ordered from the leaf to the root.
+	for i, j := 0, len(plan)-1; i < j; i, j = i+1, j-1 {
+		plan[i], plan[j] = plan[j], plan[i]
+	}
+	return p.hashes(ctx, plan)
+}
+
+// ConsistencyProof returns the consistency proof between the trees of sizes
+// size1 and size2.
+func (p *Prover) ConsistencyProof(ctx context.Context, size1, size2 uint64) ([][]byte, error) {
+	if size1 > size2 {
+		return nil, fmt.Errorf("size1 %d is greater than size2 %d", size1, size2)
+	}
+	if size1 == 0 || size1 == size2 {
+		return [][]byte{}, nil
+	}
+	// This follows the SUBPROOF algorithm of RFC 6962 section 2.1.2, where the
+	// subtree s is D[0:size2] initially, and shrinks towards the one for which
+	// size1 is the split point.
+	var plan []subtree
+	s, complete := subtree{begin: 0, end: size2}, true
+	for s.end != size1 {
+		mid := s.split()
+		if size1 <= mid {
+			plan = append(plan, subtree{begin: mid, end: s.end})
+			s.end = mid
+		} else {
+			plan = append(plan, subtree{begin: s.begin, end: mid})
+			s.begin, complete = mid, false
+		}
+	}
+	if !complete {
+		plan = append(plan, s)
+	}
+	for i, j := 0, len(plan)-1; i < j; i, j = i+1, j-1 {
+		plan[i], plan[j] = plan[j], plan[i]
+	}
+	return p.hashes(ctx, plan)
+}
+
+// RootHash returns the root hash of the tree of the given size, or nil if the
+// size is 0.
+func (p *Prover) RootHash(ctx context.Context, size uint64) ([]byte, error) {
+	if size == 0 {
+		return nil, nil
+	}
+	hashes, err := p.hashes(ctx, []subtree{{begin: 0, end: size}})
+	if err != nil {
+		return nil, err
+	}
+	return hashes[0], nil
+}
+
+// hashes reads the nodes covering the passed in subtrees, and returns the
+// subtree hashes.
+func (p *Prover) hashes(ctx context.Context, subtrees []subtree) ([][]byte, error) {
+	var ids []compact.NodeID
+	for _, s := range subtrees {
+		ids = append(ids, s.nodes()...)
+	}
+	nodes, err := p.tr.Read(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("reading tree nodes: %v", err)
+	}
+	if got, want := len(nodes), len(ids); got != want {
+		return nil, fmt.Errorf("read %d nodes, want %d", got, want)
+	}
+	for i, hash := range nodes {
+		if hash == nil {
+			return nil, fmt.Errorf("node %+v not found", ids[i])
+		}
+	}
+
+	ret := make([][]byte, 0, len(subtrees))
+	for _, s := range subtrees {
+		n := len(s.nodes())
+		hashes := nodes[:n]
+		nodes = nodes[n:]
+		// Hash the nodes from right to left, like compact.Range.GetRootHash.
+		hash := hashes[n-1]
+		for i := n - 2; i >= 0; i-- {
+			hash = p.hash(hashes[i], hash)
+		}
+		ret = append(ret, hash)
+	}
+	return ret, nil
+}
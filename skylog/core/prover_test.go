@@ -0,0 +1,152 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/google/trillian/merkle"
+	"github.com/google/trillian/merkle/compact"
+	"github.com/google/trillian/merkle/rfc6962"
+	"github.com/google/trillian/skylog/storage/memory"
+	"github.com/google/trillian/util/clock"
+	"golang.org/x/sync/errgroup"
+
+	tcrypto "github.com/google/trillian/crypto"
+)
+
+// TestPipeline runs the whole pipeline: leaves are sequenced in batches, built
+// by parallel workers, merged by the coordinator, and proven by the prover.
+func TestPipeline(t *testing.T) {
+	ctx := context.Background()
+	hasher := rfc6962.DefaultHasher
+	rf := &compact.RangeFactory{Hash: hasher.HashChildren}
+	ts := memory.NewTreeStorage()
+	signer := newSigner(t)
+	seq, err := NewSequencer(0, 7)
+	if err != nil {
+		t.Fatalf("NewSequencer: %v", err)
+	}
+	coord, err := NewCoordinator(ts, hasher, rf.NewEmptyRange(0), 0, signer, clock.System)
+	if err != nil {
+		t.Fatalf("NewCoordinator: %v", err)
+	}
+	mt := merkle.NewInMemoryMerkleTree(hasher)
+
+	const batches, batchSize = 10, 30
+	var g errgroup.Group
+	for b := 0; b < batches; b++ {
+		var leaves [][]byte
+		for i := 0; i < batchSize; i++ {
+			data := []byte(fmt.Sprintf("leaf %d", b*batchSize+i))
+			mt.AddLeaf(data)
+			leaves = append(leaves, hasher.HashLeaf(data))
+		}
+		jobs := seq.Sequence(leaves)
+		// Process the jobs of each batch in parallel, in a random order.
+		rand.Shuffle(len(jobs), func(i, j int) { jobs[i], jobs[j] = jobs[j], jobs[i] })
+		for _, job := range jobs {
+			job := job
+			g.Go(func() error {
+				rng, err := NewBuildWorker(ts, rf).Process(ctx, job)
+				if err != nil {
+					return err
+				}
+				return coord.Add(ctx, rng)
+			})
+		}
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatalf("pipeline: %v", err)
+	}
+
+	const size = batches * batchSize
+	if got := coord.Size(); got != size {
+		t.Fatalf("Size: got %d, want %d", got, size)
+	}
+	slr, err := coord.SignRoot()
+	if err != nil {
+		t.Fatalf("SignRoot: %v", err)
+	}
+	root, err := tcrypto.VerifySignedLogRoot(signer.Public(), crypto.SHA256, slr)
+	if err != nil {
+		t.Fatalf("VerifySignedLogRoot: %v", err)
+	}
+	if got, want := root.RootHash, mt.CurrentRoot().Hash(); !bytes.Equal(got, want) {
+		t.Fatalf("root hash: got %x, want %x", got, want)
+	}
+
+	p := NewProver(ts, hasher.HashChildren)
+	verifier := merkle.NewLogVerifier(hasher)
+	sizes := []uint64{1, 2, 3, 7, 8, 9, 64, 100, 255, 256, 257, size}
+	roots := make(map[uint64][]byte)
+	for _, sz := range sizes {
+		hash, err := p.RootHash(ctx, sz)
+		if err != nil {
+			t.Fatalf("RootHash(%d): %v", sz, err)
+		}
+		if want := mt.RootAtSnapshot(int64(sz)).Hash(); !bytes.Equal(hash, want) {
+			t.Errorf("RootHash(%d): got %x, want %x", sz, hash, want)
+		}
+		roots[sz] = hash
+	}
+	if hash, err := p.RootHash(ctx, 0); err != nil || hash != nil {
+		t.Errorf("RootHash(0): %x, %v, want nil, nil", hash, err)
+	}
+
+	for _, sz := range sizes {
+		for _, index := range []uint64{0, 1, sz / 2, sz - 2, sz - 1} {
+			if index >= sz {
+				continue
+			}
+			proof, err := p.InclusionProof(ctx, index, sz)
+			if err != nil {
+				t.Fatalf("InclusionProof(%d, %d): %v", index, sz, err)
+			}
+			leafHash := mt.LeafHash(int64(index) + 1)
+			if err := verifier.VerifyInclusionProof(int64(index), int64(sz), proof, roots[sz], leafHash); err != nil {
+				t.Errorf("VerifyInclusionProof(%d, %d): %v", index, sz, err)
+			}
+		}
+	}
+	if _, err := p.InclusionProof(ctx, 5, 5); err == nil {
+		t.Error("InclusionProof(5, 5): got nil, want error")
+	}
+
+	for i, sz1 := range sizes {
+		for _, sz2 := range sizes[i:] {
+			proof, err := p.ConsistencyProof(ctx, sz1, sz2)
+			if err != nil {
+				t.Fatalf("ConsistencyProof(%d, %d): %v", sz1, sz2, err)
+			}
+			if err := verifier.VerifyConsistencyProof(int64(sz1), int64(sz2), roots[sz1], roots[sz2], proof); err != nil {
+				t.Errorf("VerifyConsistencyProof(%d, %d): %v", sz1, sz2, err)
+			}
+		}
+	}
+	if _, err := p.ConsistencyProof(ctx, 10, 9); err == nil {
+		t.Error("ConsistencyProof(10, 9): got nil, want error")
+	}
+
+	// Proofs for a tree bigger than the stored one can't be built.
+	if _, err := p.InclusionProof(ctx, 0, size+1); err == nil {
+		t.Error("InclusionProof beyond the tree: got nil, want error")
+	}
+}
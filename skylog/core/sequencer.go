@@ -0,0 +1,66 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Sequencer assigns contiguous ranges of leaf indices to batches of leaf
+// hashes, and splits them into BuildJobs which can be processed in parallel.
+// It is safe for concurrent use.
+type Sequencer struct {
+	jobSize int
+
+	mu   sync.Mutex
+	next uint64 // The index assigned to the next leaf.
+}
+
+// NewSequencer returns a new Sequencer which assigns indices starting from
+// next, and produces BuildJobs of at most jobSize leaves.
+func NewSequencer(next uint64, jobSize int) (*Sequencer, error) {
+	if jobSize <= 0 {
+		return nil, fmt.Errorf("jobSize=%d, want > 0", jobSize)
+	}
+	return &Sequencer{jobSize: jobSize, next: next}, nil
+}
+
+// Sequence assigns the [next, next+len(hashes)) range of indices to the
+// passed in hashes, and returns the corresponding BuildJobs, ordered by
+// RangeStart.
+func (s *Sequencer) Sequence(hashes [][]byte) []BuildJob {
+	s.mu.Lock()
+	begin := s.next
+	s.next += uint64(len(hashes))
+	s.mu.Unlock()
+
+	jobs := make([]BuildJob, 0, (len(hashes)+s.jobSize-1)/s.jobSize)
+	for i := 0; i < len(hashes); i += s.jobSize {
+		end := i + s.jobSize
+		if end > len(hashes) {
+			end = len(hashes)
+		}
+		jobs = append(jobs, BuildJob{RangeStart: begin + uint64(i), Hashes: hashes[i:end]})
+	}
+	return jobs
+}
+
+// Next returns the index which will be assigned to the next leaf.
+func (s *Sequencer) Next() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.next
+}
@@ -0,0 +1,104 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func hashes(n int) [][]byte {
+	ret := make([][]byte, n)
+	for i := range ret {
+		ret[i] = []byte(fmt.Sprintf("hash %d", i))
+	}
+	return ret
+}
+
+func TestSequencer(t *testing.T) {
+	if _, err := NewSequencer(0, 0); err == nil {
+		t.Error("NewSequencer(jobSize=0): got nil, want error")
+	}
+	s, err := NewSequencer(10, 3)
+	if err != nil {
+		t.Fatalf("NewSequencer: %v", err)
+	}
+	for _, tc := range []struct {
+		n      int
+		starts []uint64
+		sizes  []int
+	}{
+		{n: 0},
+		{n: 1, starts: []uint64{10}, sizes: []int{1}},
+		{n: 3, starts: []uint64{11}, sizes: []int{3}},
+		{n: 7, starts: []uint64{14, 17, 20}, sizes: []int{3, 3, 1}},
+	} {
+		jobs := s.Sequence(hashes(tc.n))
+		if got, want := len(jobs), len(tc.starts); got != want {
+			t.Fatalf("Sequence(%d): got %d jobs, want %d", tc.n, got, want)
+		}
+		for i, job := range jobs {
+			if job.RangeStart != tc.starts[i] || len(job.Hashes) != tc.sizes[i] {
+				t.Errorf("Sequence(%d): job %d is [%d, +%d), want [%d, +%d)", tc.n, i, job.RangeStart, len(job.Hashes), tc.starts[i], tc.sizes[i])
+			}
+		}
+	}
+	if got, want := s.Next(), uint64(21); got != want {
+		t.Errorf("Next: got %d, want %d", got, want)
+	}
+}
+
+func TestSequencerConcurrent(t *testing.T) {
+	s, err := NewSequencer(0, 4)
+	if err != nil {
+		t.Fatalf("NewSequencer: %v", err)
+	}
+	const callers, batches, batchSize = 8, 20, 5
+	var (
+		mu     sync.Mutex
+		starts []uint64
+		wg     sync.WaitGroup
+	)
+	for c := 0; c < callers; c++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for b := 0; b < batches; b++ {
+				for _, job := range s.Sequence(hashes(batchSize)) {
+					mu.Lock()
+					starts = append(starts, job.RangeStart)
+					for i := 1; i < len(job.Hashes); i++ {
+						starts = append(starts, job.RangeStart+uint64(i))
+					}
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Every index must be assigned exactly once.
+	sort.Slice(starts, func(i, j int) bool { return starts[i] < starts[j] })
+	if got, want := len(starts), callers*batches*batchSize; got != want {
+		t.Fatalf("got %d indices, want %d", got, want)
+	}
+	for i, idx := range starts {
+		if idx != uint64(i) {
+			t.Fatalf("index #%d is %d, want %d", i, idx, i)
+		}
+	}
+}
@@ -37,6 +37,7 @@ func NewTreeStorage(c *spanner.Client, treeID int64, opts TreeOpts) *TreeStorage
 }
 
 // Read fetches Merkle tree hashes of the passed in nodes from the storage.
+// The returned slice contains nil hashes for the nodes that are not found.
 // TODO(pavelkalinnikov): Add nodes cache.
 func (t *TreeStorage) Read(ctx context.Context, ids []compact.NodeID) ([][]byte, error) {
 	keys := make([]spanner.KeySet, 0, len(ids))
@@ -44,19 +45,25 @@ func (t *TreeStorage) Read(ctx context.Context, ids []compact.NodeID) ([][]byte,
 		keys = append(keys, spanner.Key{t.id, t.opts.shardID(id), packNodeID(id)})
 	}
 	keySet := spanner.KeySets(keys...)
-	hashes := make([][]byte, 0, len(ids))
+	// Rows are returned in key order, and only for the nodes that exist.
+	found := make(map[int64][]byte, len(ids))
 
-	iter := t.c.Single().Read(ctx, "TreeNodes", keySet, []string{"NodeHash"})
+	iter := t.c.Single().Read(ctx, "TreeNodes", keySet, []string{"NodeID", "NodeHash"})
 	if err := iter.Do(func(r *spanner.Row) error {
+		var id int64
 		var hash []byte
-		if err := r.Column(0, &hash); err != nil {
+		if err := r.Columns(&id, &hash); err != nil {
 			return err
 		}
-		hashes = append(hashes, hash)
+		found[id] = hash
 		return nil
 	}); err != nil {
 		return nil, err
 	}
+	hashes := make([][]byte, len(ids))
+	for i, id := range ids {
+		hashes[i] = found[packNodeID(id)]
+	}
 	return hashes, nil
 }
 
@@ -0,0 +1,75 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package memory provides an in-memory implementation of the Skylog storage
+// API, intended for tests.
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/google/trillian/merkle/compact"
+	"github.com/google/trillian/skylog/storage"
+)
+
+// TreeStorage allows reading from and writing to a tree storage in memory. It
+// is safe for concurrent use.
+type TreeStorage struct {
+	mu    sync.RWMutex
+	nodes map[compact.NodeID][]byte
+}
+
+// NewTreeStorage returns a new empty TreeStorage.
+func NewTreeStorage() *TreeStorage {
+	return &TreeStorage{nodes: make(map[compact.NodeID][]byte)}
+}
+
+// Read fetches Merkle tree hashes of the passed in nodes from the storage.
+// The returned slice contains nil hashes for the nodes that are not found.
+func (t *TreeStorage) Read(ctx context.Context, ids []compact.NodeID) ([][]byte, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	hashes := make([][]byte, len(ids))
+	for i, id := range ids {
+		hashes[i] = t.nodes[id]
+	}
+	return hashes, nil
+}
+
+// Write stores all the passed-in nodes in the tree storage. It fails, without
+// writing anything, if any of the nodes is already stored with a different
+// hash.
+func (t *TreeStorage) Write(ctx context.Context, nodes []storage.Node) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, node := range nodes {
+		if hash, ok := t.nodes[node.ID]; ok && !bytes.Equal(hash, node.Hash) {
+			return fmt.Errorf("node %+v already has hash %x, can't overwrite with %x", node.ID, hash, node.Hash)
+		}
+	}
+	for _, node := range nodes {
+		t.nodes[node.ID] = append([]byte(nil), node.Hash...)
+	}
+	return nil
+}
+
+// Len returns the number of stored nodes.
+func (t *TreeStorage) Len() int {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return len(t.nodes)
+}
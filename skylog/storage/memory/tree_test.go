@@ -0,0 +1,53 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/google/trillian/merkle/compact"
+	"github.com/google/trillian/skylog/storage"
+)
+
+func TestTreeStorage(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTreeStorage()
+	id1, id2, id3 := compact.NewNodeID(0, 1), compact.NewNodeID(1, 0), compact.NewNodeID(5, 7)
+
+	if err := ts.Write(ctx, []storage.Node{{ID: id1, Hash: []byte("one")}, {ID: id2, Hash: []byte("two")}}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	// Rewriting with the same hash is allowed.
+	if err := ts.Write(ctx, []storage.Node{{ID: id2, Hash: []byte("two")}}); err != nil {
+		t.Errorf("Write(same hash): %v", err)
+	}
+	// Rewriting with a different hash is not, and has no side effects.
+	if err := ts.Write(ctx, []storage.Node{{ID: id3, Hash: []byte("three")}, {ID: id1, Hash: []byte("bad")}}); err == nil {
+		t.Error("Write(different hash): got nil, want error")
+	}
+	if got, want := ts.Len(), 2; got != want {
+		t.Errorf("Len: got %d, want %d", got, want)
+	}
+
+	got, err := ts.Read(ctx, []compact.NodeID{id2, id3, id1})
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if want := [][]byte{[]byte("two"), nil, []byte("one")}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Read: got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,321 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bolt
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/trillian"
+	"github.com/google/trillian/storage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	bbolt "go.etcd.io/bbolt"
+)
+
+// NewAdminStorage returns a bolt storage.AdminStorage implementation backed by db.
+func NewAdminStorage(db *bbolt.DB) storage.AdminStorage {
+	return &boltAdminStorage{db}
+}
+
+// boltAdminStorage implements storage.AdminStorage
+type boltAdminStorage struct {
+	db *bbolt.DB
+}
+
+func (s *boltAdminStorage) Snapshot(ctx context.Context) (storage.ReadOnlyAdminTX, error) {
+	return &adminTX{db: s.db}, nil
+}
+
+func (s *boltAdminStorage) ReadWriteTransaction(ctx context.Context, f storage.AdminTXFunc) error {
+	btx, err := s.db.Begin(true /* writable */)
+	if err != nil {
+		return err
+	}
+	tx := &adminTX{db: s.db, tx: btx}
+	defer tx.Close()
+	if err := f(ctx, tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *boltAdminStorage) CheckDatabaseAccessible(ctx context.Context) error {
+	return checkDatabaseAccessible(s.db)
+}
+
+type adminTX struct {
+	db *bbolt.DB
+	// tx is the bbolt read-write transaction of a read-write adminTX, or nil
+	// for a snapshot, which runs a separate bbolt transaction for each read.
+	tx *bbolt.Tx
+
+	// mu guards *direct* reads/writes on closed, which happen only on
+	// Commit/Rollback/IsClosed/Close methods.
+	mu     sync.RWMutex
+	closed bool
+}
+
+func (t *adminTX) Commit() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closed = true
+	if t.tx == nil {
+		return nil
+	}
+	return t.tx.Commit()
+}
+
+func (t *adminTX) Rollback() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closed = true
+	if t.tx == nil {
+		return nil
+	}
+	return t.tx.Rollback()
+}
+
+func (t *adminTX) IsClosed() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.closed
+}
+
+func (t *adminTX) Close() error {
+	// Acquire and release read lock manually, without defer, as if the txn
+	// is not closed Rollback() will attempt to acquire the rw lock.
+	t.mu.RLock()
+	closed := t.closed
+	t.mu.RUnlock()
+	if !closed {
+		err := t.Rollback()
+		if err != nil {
+			glog.Warningf("Rollback error on Close(): %v", err)
+		}
+		return err
+	}
+	return nil
+}
+
+// view calls f with the "Trees" bucket, as seen by the transaction.
+func (t *adminTX) view(f func(b *bbolt.Bucket) error) error {
+	if t.tx != nil {
+		return f(t.tx.Bucket(treesBucket))
+	}
+	return t.db.View(func(tx *bbolt.Tx) error {
+		return f(tx.Bucket(treesBucket))
+	})
+}
+
+// getTree reads the tree with the given ID from b.
+func getTree(b *bbolt.Bucket, treeID int64) (*trillian.Tree, error) {
+	v := b.Get(treeKey(treeID))
+	if v == nil {
+		return nil, status.Errorf(codes.NotFound, "tree %v not found", treeID)
+	}
+	var tree trillian.Tree
+	if err := proto.Unmarshal(v, &tree); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal tree %v: %v", treeID, err)
+	}
+	return &tree, nil
+}
+
+// putTree writes tree to the "Trees" bucket of the read-write transaction.
+func (t *adminTX) putTree(tree *trillian.Tree) error {
+	if t.tx == nil {
+		return errReadOnlyTXWrites
+	}
+	// Marshal a copy, so the tree returned to the caller is left untouched.
+	v, err := proto.Marshal(proto.Clone(tree))
+	if err != nil {
+		return err
+	}
+	return t.tx.Bucket(treesBucket).Put(treeKey(tree.TreeId), v)
+}
+
+func (t *adminTX) GetTree(ctx context.Context, treeID int64) (*trillian.Tree, error) {
+	var tree *trillian.Tree
+	err := t.view(func(b *bbolt.Bucket) error {
+		var err error
+		tree, err = getTree(b, treeID)
+		return err
+	})
+	return tree, err
+}
+
+// forEachTree calls f with each tree, in tree ID order.
+func (t *adminTX) forEachTree(includeDeleted bool, f func(tree *trillian.Tree)) error {
+	return t.view(func(b *bbolt.Bucket) error {
+		return b.ForEach(func(k, v []byte) error {
+			var tree trillian.Tree
+			if err := proto.Unmarshal(v, &tree); err != nil {
+				return fmt.Errorf("failed to unmarshal tree %v: %v", decodeInt64(k), err)
+			}
+			if includeDeleted || !tree.Deleted {
+				f(&tree)
+			}
+			return nil
+		})
+	})
+}
+
+func (t *adminTX) ListTreeIDs(ctx context.Context, includeDeleted bool) ([]int64, error) {
+	var ret []int64
+	err := t.forEachTree(includeDeleted, func(tree *trillian.Tree) {
+		ret = append(ret, tree.TreeId)
+	})
+	return ret, err
+}
+
+func (t *adminTX) ListTrees(ctx context.Context, includeDeleted bool) ([]*trillian.Tree, error) {
+	var ret []*trillian.Tree
+	err := t.forEachTree(includeDeleted, func(tree *trillian.Tree) {
+		ret = append(ret, tree)
+	})
+	return ret, err
+}
+
+func (t *adminTX) CreateTree(ctx context.Context, tree *trillian.Tree) (*trillian.Tree, error) {
+	if err := storage.ValidateTreeForCreation(ctx, tree); err != nil {
+		return nil, err
+	}
+	if err := validateStorageSettings(tree); err != nil {
+		return nil, err
+	}
+	if t.tx == nil {
+		return nil, errReadOnlyTXWrites
+	}
+
+	id, err := storage.NewTreeID()
+	if err != nil {
+		return nil, err
+	}
+	if t.tx.Bucket(treesBucket).Get(treeKey(id)) != nil {
+		return nil, status.Errorf(codes.AlreadyExists, "tree %v already exists", id)
+	}
+
+	now := time.Now()
+	newTree := proto.Clone(tree).(*trillian.Tree)
+	newTree.TreeId = id
+	if newTree.CreateTime, err = ptypes.TimestampProto(now); err != nil {
+		return nil, err
+	}
+	if newTree.UpdateTime, err = ptypes.TimestampProto(now); err != nil {
+		return nil, err
+	}
+
+	if err := t.putTree(newTree); err != nil {
+		return nil, err
+	}
+	if err := createDataBucket(t.tx, id); err != nil {
+		return nil, err
+	}
+	// Return the tree as stored, as later reads will.
+	return getTree(t.tx.Bucket(treesBucket), id)
+}
+
+func (t *adminTX) UpdateTree(ctx context.Context, treeID int64, updateFunc func(*trillian.Tree)) (*trillian.Tree, error) {
+	tree, err := t.GetTree(ctx, treeID)
+	if err != nil {
+		return nil, err
+	}
+
+	beforeUpdate := proto.Clone(tree).(*trillian.Tree)
+	updateFunc(tree)
+	if err := storage.ValidateTreeForUpdate(ctx, beforeUpdate, tree); err != nil {
+		return nil, err
+	}
+	if err := validateStorageSettings(tree); err != nil {
+		return nil, err
+	}
+
+	if tree.UpdateTime, err = ptypes.TimestampProto(time.Now()); err != nil {
+		return nil, err
+	}
+	if err := t.putTree(tree); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+func (t *adminTX) SoftDeleteTree(ctx context.Context, treeID int64) (*trillian.Tree, error) {
+	return t.updateDeleted(ctx, treeID, true /* deleted */)
+}
+
+func (t *adminTX) UndeleteTree(ctx context.Context, treeID int64) (*trillian.Tree, error) {
+	return t.updateDeleted(ctx, treeID, false /* deleted */)
+}
+
+// updateDeleted updates the Deleted and DeleteTime fields of the specified tree.
+func (t *adminTX) updateDeleted(ctx context.Context, treeID int64, deleted bool) (*trillian.Tree, error) {
+	tree, err := t.getTreeWithDeleted(ctx, treeID, !deleted)
+	if err != nil {
+		return nil, err
+	}
+	tree.Deleted = deleted
+	tree.DeleteTime = nil
+	if deleted {
+		if tree.DeleteTime, err = ptypes.TimestampProto(time.Now()); err != nil {
+			return nil, err
+		}
+	}
+	if err := t.putTree(tree); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+func (t *adminTX) HardDeleteTree(ctx context.Context, treeID int64) error {
+	if _, err := t.getTreeWithDeleted(ctx, treeID, true /* wantDeleted */); err != nil {
+		return err
+	}
+	if t.tx == nil {
+		return errReadOnlyTXWrites
+	}
+	if err := t.tx.Bucket(treesBucket).Delete(treeKey(treeID)); err != nil {
+		return err
+	}
+	return t.tx.Bucket(treeDataBucket).DeleteBucket(treeKey(treeID))
+}
+
+// getTreeWithDeleted returns the specified tree, or an error if its Deleted
+// field isn't wantDeleted.
+func (t *adminTX) getTreeWithDeleted(ctx context.Context, treeID int64, wantDeleted bool) (*trillian.Tree, error) {
+	tree, err := t.GetTree(ctx, treeID)
+	if err != nil {
+		return nil, err
+	}
+	switch deleted := tree.Deleted; {
+	case wantDeleted && !deleted:
+		return nil, status.Errorf(codes.FailedPrecondition, "tree %v is not soft deleted", treeID)
+	case !wantDeleted && deleted:
+		return nil, status.Errorf(codes.FailedPrecondition, "tree %v already soft deleted", treeID)
+	}
+	return tree, nil
+}
+
+func validateStorageSettings(tree *trillian.Tree) error {
+	if tree.StorageSettings != nil {
+		return fmt.Errorf("storage_settings not supported, but got %v", tree.StorageSettings)
+	}
+	return nil
+}
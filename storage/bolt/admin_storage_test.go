@@ -0,0 +1,79 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bolt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/testonly"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestBoltAdminStorage(t *testing.T) {
+	var closers []func()
+	defer func() {
+		for _, done := range closers {
+			done()
+		}
+	}()
+	tester := &testonly.AdminStorageTester{NewAdminStorage: func() storage.AdminStorage {
+		db, _, done := openTestDB(t)
+		closers = append(closers, done)
+		return NewAdminStorage(db)
+	}}
+	tester.RunAllTests(t)
+}
+
+func TestAdminTX_StorageSettingsNotSupported(t *testing.T) {
+	db, _, done := openTestDB(t)
+	defer done()
+
+	tree := *testonly.LogTree
+	tree.StorageSettings = testonly.LogTree.PrivateKey
+	if _, err := storage.CreateTree(context.Background(), NewAdminStorage(db), &tree); err == nil {
+		t.Error("CreateTree() with StorageSettings returned nil err")
+	}
+}
+
+func TestAdminTX_HardDeleteTreeRemovesData(t *testing.T) {
+	ctx := context.Background()
+	db, _, done := openTestDB(t)
+	defer done()
+	as := NewAdminStorage(db)
+	ls := NewLogStorage(db, nil)
+
+	tree := createTree(t, db, testonly.LogTree)
+	initLog(t, ls, tree)
+	if _, err := ls.QueueLeaves(ctx, tree, createLeaves(2), time.Now()); err != nil {
+		t.Fatalf("QueueLeaves(): %v", err)
+	}
+	if _, err := storage.SoftDeleteTree(ctx, as, tree.TreeId); err != nil {
+		t.Fatalf("SoftDeleteTree(): %v", err)
+	}
+	if err := storage.HardDeleteTree(ctx, as, tree.TreeId); err != nil {
+		t.Fatalf("HardDeleteTree(): %v", err)
+	}
+
+	if _, err := storage.GetTree(ctx, as, tree.TreeId); status.Code(err) != codes.NotFound {
+		t.Errorf("GetTree() after HardDeleteTree() returned err = %v, want code %s", err, codes.NotFound)
+	}
+	if _, err := ls.SnapshotForTree(ctx, tree); status.Code(err) != codes.NotFound {
+		t.Errorf("SnapshotForTree() after HardDeleteTree() returned err = %v, want code %s", err, codes.NotFound)
+	}
+}
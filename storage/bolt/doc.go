@@ -0,0 +1,41 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bolt provides an embedded, on-disk implementation of the admin-,
+// log- and map-storage interfaces, based on the bbolt key-value store.
+//
+// It is intended for small deployments which can't justify running a
+// separate database server, but which need their trees to survive restarts.
+// All the data is kept in a single file, which can only be opened by one
+// process at a time.
+//
+// The metadata of all trees is stored in the "Trees" bucket, keyed by tree
+// ID. The data of each tree lives in its own bucket, nested in the "TreeData"
+// bucket, which holds one bucket per "table" of the MySQL schema: subtrees,
+// leaf data, sequenced leaves, the unsequenced queue, tree heads, map leaves
+// and map heads. Integers in keys are encoded big-endian, so that iterating
+// over a bucket visits them in order. Merkle nodes are stored as SubtreeProtos,
+// and accessed through the SubtreeCache, like in the other implementations.
+//
+// bbolt allows a single read-write transaction at a time, and commits it with
+// an fsync, which makes all transactions crash-safe. Log and admin read-write
+// transactions hold a bbolt read-write transaction for their whole lifetime.
+// Map read-write transactions instead buffer their writes, and apply them in
+// a single bbolt transaction when they're committed: the map server may run
+// several transactions for the same map at once, and holding the bbolt
+// transaction would make them deadlock. Read-only transactions hold a bbolt
+// read-only transaction, so that they read a consistent snapshot of the tree.
+// As bbolt can't grow the database file while a read-only transaction is open,
+// they should be closed promptly.
+package bolt
@@ -0,0 +1,754 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/trillian"
+	"github.com/google/trillian/merkle/hashers"
+	"github.com/google/trillian/monitoring"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/cache"
+	"github.com/google/trillian/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	bbolt "go.etcd.io/bbolt"
+)
+
+const logIDLabel = "logid"
+
+var (
+	defaultLogStrata = []int{8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8}
+
+	once             sync.Once
+	queuedCounter    monitoring.Counter
+	queuedDupCounter monitoring.Counter
+	dequeuedCounter  monitoring.Counter
+)
+
+func createMetrics(mf monitoring.MetricFactory) {
+	queuedCounter = mf.NewCounter("bolt_queued_leaves", "Number of leaves queued", logIDLabel)
+	queuedDupCounter = mf.NewCounter("bolt_queued_dup_leaves", "Number of duplicate leaves queued", logIDLabel)
+	dequeuedCounter = mf.NewCounter("bolt_dequeued_leaves", "Number of leaves dequeued", logIDLabel)
+}
+
+func labelForTX(t *logTreeTX) string {
+	return strconv.FormatInt(t.treeID, 10)
+}
+
+// unsequencedKey returns the key of a queued leaf in the "Unsequenced"
+// bucket, which orders the queue by (QueueTimestampNanos, LeafIdentityHash).
+func unsequencedKey(queueTimestampNanos int64, leafIdentityHash []byte) []byte {
+	return append(encodeInt64(queueTimestampNanos), leafIdentityHash...)
+}
+
+// merkleHashKey returns the key of a sequenced leaf in the "MerkleLeafHash"
+// bucket, which indexes the sequenced leaves by Merkle leaf hash.
+func merkleHashKey(merkleLeafHash []byte, leafIndex int64) []byte {
+	return append(append([]byte{}, merkleLeafHash...), encodeInt64(leafIndex)...)
+}
+
+// countKeys returns the number of keys in b.
+func countKeys(b *bbolt.Bucket) int64 {
+	var n int64
+	c := b.Cursor()
+	for k, _ := c.First(); k != nil; k, _ = c.Next() {
+		n++
+	}
+	return n
+}
+
+type boltLogStorage struct {
+	db            *bbolt.DB
+	metricFactory monitoring.MetricFactory
+}
+
+// NewLogStorage creates a bolt LogStorage instance backed by db.
+func NewLogStorage(db *bbolt.DB, mf monitoring.MetricFactory) storage.LogStorage {
+	if mf == nil {
+		mf = monitoring.InertMetricFactory{}
+	}
+	return &boltLogStorage{
+		db:            db,
+		metricFactory: mf,
+	}
+}
+
+func (m *boltLogStorage) CheckDatabaseAccessible(ctx context.Context) error {
+	return checkDatabaseAccessible(m.db)
+}
+
+type readOnlyLogTX struct {
+	db *bbolt.DB
+}
+
+func (m *boltLogStorage) Snapshot(ctx context.Context) (storage.ReadOnlyLogTX, error) {
+	return &readOnlyLogTX{m.db}, nil
+}
+
+func (t *readOnlyLogTX) Commit() error {
+	return nil
+}
+
+func (t *readOnlyLogTX) Rollback() error {
+	return nil
+}
+
+func (t *readOnlyLogTX) Close() error {
+	return nil
+}
+
+func (t *readOnlyLogTX) GetActiveLogIDs(ctx context.Context) ([]int64, error) {
+	ids := []int64{}
+	err := (&adminTX{db: t.db}).forEachTree(false /* includeDeleted */, func(tree *trillian.Tree) {
+		switch tree.TreeType {
+		case trillian.TreeType_LOG, trillian.TreeType_PREORDERED_LOG:
+		default:
+			return
+		}
+		// Include logs that are DRAINING in the active list as we're still
+		// integrating leaves into them.
+		switch tree.TreeState {
+		case trillian.TreeState_ACTIVE, trillian.TreeState_DRAINING:
+			ids = append(ids, tree.TreeId)
+		}
+	})
+	return ids, err
+}
+
+func (t *readOnlyLogTX) GetUnsequencedCounts(ctx context.Context) (storage.CountByLogID, error) {
+	ret := make(storage.CountByLogID)
+	err := t.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(treeDataBucket).ForEach(func(k, _ []byte) error {
+			b := tx.Bucket(treeDataBucket).Bucket(k)
+			if n := countKeys(b.Bucket(unsequencedBucket)); n > 0 {
+				ret[decodeInt64(k)] = n
+			}
+			return nil
+		})
+	})
+	return ret, err
+}
+
+func (m *boltLogStorage) beginInternal(ctx context.Context, tree *trillian.Tree, readonly bool) (*logTreeTX, error) {
+	once.Do(func() {
+		createMetrics(m.metricFactory)
+	})
+	hasher, err := hashers.NewLogHasher(tree.HashStrategy)
+	if err != nil {
+		return nil, err
+	}
+
+	btx, err := m.db.Begin(!readonly)
+	if err != nil {
+		return nil, err
+	}
+	stCache := cache.NewLogSubtreeCache(defaultLogStrata, hasher)
+	ltx := &logTreeTX{
+		treeTX:   newTreeTX(m.db, btx, tree.TreeId, hasher.Size(), stCache),
+		ls:       m,
+		treeType: tree.TreeType,
+	}
+
+	ltx.slr, err = ltx.fetchLatestRoot(ctx)
+	if err == storage.ErrTreeNeedsInit {
+		return ltx, err
+	} else if err != nil {
+		ltx.Rollback()
+		return nil, err
+	}
+
+	if err := ltx.root.UnmarshalBinary(ltx.slr.LogRoot); err != nil {
+		ltx.Rollback()
+		return nil, err
+	}
+
+	ltx.treeTX.writeRevision = int64(ltx.root.Revision) + 1
+	return ltx, nil
+}
+
+func (m *boltLogStorage) ReadWriteTransaction(ctx context.Context, tree *trillian.Tree, f storage.LogTXFunc) error {
+	tx, err := m.beginInternal(ctx, tree, false /* readonly */)
+	if err != nil && err != storage.ErrTreeNeedsInit {
+		return err
+	}
+	defer tx.Close()
+	if err := f(ctx, tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (m *boltLogStorage) AddSequencedLeaves(ctx context.Context, tree *trillian.Tree, leaves []*trillian.LogLeaf, timestamp time.Time) ([]*trillian.QueuedLogLeaf, error) {
+	tx, err := m.beginInternal(ctx, tree, false /* readonly */)
+	if tx != nil {
+		// Ensure we don't leak the transaction. For example if we get an
+		// ErrTreeNeedsInit from beginInternal() or if AddSequencedLeaves fails
+		// below.
+		defer tx.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+	res, err := tx.AddSequencedLeaves(ctx, leaves, timestamp)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (m *boltLogStorage) SnapshotForTree(ctx context.Context, tree *trillian.Tree) (storage.ReadOnlyLogTreeTX, error) {
+	tx, err := m.beginInternal(ctx, tree, true /* readonly */)
+	if err != nil && err != storage.ErrTreeNeedsInit {
+		return nil, err
+	}
+	return tx, err
+}
+
+func (m *boltLogStorage) QueueLeaves(ctx context.Context, tree *trillian.Tree, leaves []*trillian.LogLeaf, queueTimestamp time.Time) ([]*trillian.QueuedLogLeaf, error) {
+	tx, err := m.beginInternal(ctx, tree, false /* readonly */)
+	if tx != nil {
+		// Ensure we don't leak the transaction. For example if we get an
+		// ErrTreeNeedsInit from beginInternal() or if QueueLeaves fails
+		// below.
+		defer tx.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+	existing, err := tx.QueueLeaves(ctx, leaves, queueTimestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	ret := make([]*trillian.QueuedLogLeaf, len(leaves))
+	for i, e := range existing {
+		if e != nil {
+			ret[i] = &trillian.QueuedLogLeaf{
+				Leaf:   e,
+				Status: status.Newf(codes.AlreadyExists, "leaf already exists: %v", e.LeafIdentityHash).Proto(),
+			}
+			continue
+		}
+		ret[i] = &trillian.QueuedLogLeaf{Leaf: leaves[i]}
+	}
+	return ret, nil
+}
+
+type logTreeTX struct {
+	treeTX
+	ls       *boltLogStorage
+	treeType trillian.TreeType
+	root     types.LogRootV1
+	slr      trillian.SignedLogRoot
+}
+
+func (t *logTreeTX) ReadRevision(ctx context.Context) (int64, error) {
+	t.treeTX.mu.Lock()
+	defer t.treeTX.mu.Unlock()
+	return int64(t.root.Revision), nil
+}
+
+func (t *logTreeTX) WriteRevision(ctx context.Context) (int64, error) {
+	t.treeTX.mu.Lock()
+	defer t.treeTX.mu.Unlock()
+	if t.treeTX.writeRevision < 0 {
+		return t.treeTX.writeRevision, errors.New("logTreeTX write revision not populated")
+	}
+	return t.treeTX.writeRevision, nil
+}
+
+func (t *logTreeTX) DequeueLeaves(ctx context.Context, limit int, cutoffTime time.Time) ([]*trillian.LogLeaf, error) {
+	t.treeTX.mu.Lock()
+	defer t.treeTX.mu.Unlock()
+
+	if t.treeType == trillian.TreeType_PREORDERED_LOG {
+		return t.getLeavesByRangeInternal(ctx, int64(t.root.TreeSize), int64(limit))
+	}
+
+	leaves := make([]*trillian.LogLeaf, 0, limit)
+	err := t.update(func(b *bbolt.Bucket) error {
+		ub := b.Bucket(unsequencedBucket)
+		var keys [][]byte
+		c := ub.Cursor()
+		for k, v := c.First(); k != nil && len(leaves) < limit; k, v = c.Next() {
+			queueTimestamp := decodeInt64(k[:8])
+			if queueTimestamp > cutoffTime.UnixNano() {
+				break
+			}
+			leafIDHash := append([]byte{}, k[8:]...)
+			if len(leafIDHash) != t.hashSizeBytes {
+				return errors.New("dequeued a leaf with incorrect hash size")
+			}
+			// Note: the LeafData and ExtraData being nil here is OK as this is
+			// only used by the sequencer, and the client supplied data was
+			// already written to the "LeafData" bucket when queueing the leaf.
+			queueTimestampProto, err := ptypes.TimestampProto(time.Unix(0, queueTimestamp))
+			if err != nil {
+				return fmt.Errorf("got invalid queue timestamp: %v", err)
+			}
+			leaves = append(leaves, &trillian.LogLeaf{
+				LeafIdentityHash: leafIDHash,
+				MerkleLeafHash:   append([]byte{}, v...),
+				QueueTimestamp:   queueTimestampProto,
+			})
+			keys = append(keys, append([]byte{}, k...))
+		}
+		// The convention is that if leaf processing succeeds (by committing
+		// this tx) then the unsequenced entries for them are removed.
+		for _, k := range keys {
+			if err := ub.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dequeuedCounter.Add(float64(len(leaves)), labelForTX(t))
+	return leaves, nil
+}
+
+func (t *logTreeTX) QueueLeaves(ctx context.Context, leaves []*trillian.LogLeaf, queueTimestamp time.Time) ([]*trillian.LogLeaf, error) {
+	t.treeTX.mu.Lock()
+	defer t.treeTX.mu.Unlock()
+
+	// Don't accept batches if any of the leaves are invalid.
+	for _, leaf := range leaves {
+		if len(leaf.LeafIdentityHash) != t.hashSizeBytes {
+			return nil, fmt.Errorf("queued leaf must have a leaf ID hash of length %d", t.hashSizeBytes)
+		}
+		var err error
+		leaf.QueueTimestamp, err = ptypes.TimestampProto(queueTimestamp)
+		if err != nil {
+			return nil, fmt.Errorf("got invalid queue timestamp: %v", err)
+		}
+	}
+	label := labelForTX(t)
+
+	existingLeaves := make([]*trillian.LogLeaf, len(leaves))
+	err := t.update(func(b *bbolt.Bucket) error {
+		lb, ub := b.Bucket(leafDataBucket), b.Bucket(unsequencedBucket)
+		for i, leaf := range leaves {
+			if v := lb.Get(leaf.LeafIdentityHash); v != nil {
+				var existing trillian.LogLeaf
+				if err := proto.Unmarshal(v, &existing); err != nil {
+					return fmt.Errorf("failed to retrieve existing leaf: %v", err)
+				}
+				existingLeaves[i] = &existing
+				queuedDupCounter.Inc(label)
+				continue
+			}
+			if err := putLeafData(lb, leaf); err != nil {
+				return err
+			}
+			key := unsequencedKey(queueTimestamp.UnixNano(), leaf.LeafIdentityHash)
+			if err := ub.Put(key, leaf.MerkleLeafHash); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	queuedCounter.Add(float64(len(leaves)), label)
+	return existingLeaves, nil
+}
+
+// putLeafData stores the client supplied data of leaf in the "LeafData"
+// bucket lb.
+func putLeafData(lb *bbolt.Bucket, leaf *trillian.LogLeaf) error {
+	v, err := proto.Marshal(&trillian.LogLeaf{
+		LeafIdentityHash: leaf.LeafIdentityHash,
+		LeafValue:        leaf.LeafValue,
+		ExtraData:        leaf.ExtraData,
+		QueueTimestamp:   leaf.QueueTimestamp,
+	})
+	if err != nil {
+		return err
+	}
+	return lb.Put(leaf.LeafIdentityHash, v)
+}
+
+// putSequencedLeaf stores the sequencing information of leaf in the
+// "SequencedLeafData" and "MerkleLeafHash" buckets of b. It returns false if
+// there already is a leaf with the same index.
+func putSequencedLeaf(b *bbolt.Bucket, leaf *trillian.LogLeaf) (bool, error) {
+	sb := b.Bucket(sequencedBucket)
+	key := encodeInt64(leaf.LeafIndex)
+	if sb.Get(key) != nil {
+		return false, nil
+	}
+	v, err := proto.Marshal(&trillian.LogLeaf{
+		LeafIdentityHash:   leaf.LeafIdentityHash,
+		MerkleLeafHash:     leaf.MerkleLeafHash,
+		LeafIndex:          leaf.LeafIndex,
+		IntegrateTimestamp: leaf.IntegrateTimestamp,
+	})
+	if err != nil {
+		return false, err
+	}
+	if err := sb.Put(key, v); err != nil {
+		return false, err
+	}
+	return true, b.Bucket(merkleHashBucket).Put(merkleHashKey(leaf.MerkleLeafHash, leaf.LeafIndex), []byte{})
+}
+
+func (t *logTreeTX) ExpireQueuedLeaves(ctx context.Context, limit int, cutoff time.Time) (int, error) {
+	t.treeTX.mu.Lock()
+	defer t.treeTX.mu.Unlock()
+
+	removed := 0
+	err := t.update(func(b *bbolt.Bucket) error {
+		lb, ub := b.Bucket(leafDataBucket), b.Bucket(unsequencedBucket)
+		var expired [][]byte
+		c := ub.Cursor()
+		for k, _ := c.First(); k != nil && len(expired) < limit; k, _ = c.Next() {
+			if decodeInt64(k[:8]) >= cutoff.UnixNano() {
+				break
+			}
+			expired = append(expired, append([]byte{}, k...))
+		}
+		for _, k := range expired {
+			if err := ub.Delete(k); err != nil {
+				return err
+			}
+			if err := lb.Delete(k[8:]); err != nil {
+				return err
+			}
+			removed++
+		}
+		return nil
+	})
+	return removed, err
+}
+
+func (t *logTreeTX) AddSequencedLeaves(ctx context.Context, leaves []*trillian.LogLeaf, timestamp time.Time) ([]*trillian.QueuedLogLeaf, error) {
+	t.treeTX.mu.Lock()
+	defer t.treeTX.mu.Unlock()
+
+	res := make([]*trillian.QueuedLogLeaf, len(leaves))
+	ok := status.New(codes.OK, "OK").Proto()
+	queueTimestamp, err := ptypes.TimestampProto(timestamp)
+	if err != nil {
+		return nil, fmt.Errorf("got invalid queue timestamp: %v", err)
+	}
+	integrateTimestamp, err := ptypes.TimestampProto(time.Unix(0, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	err = t.update(func(b *bbolt.Bucket) error {
+		lb := b.Bucket(leafDataBucket)
+		for i, leaf := range leaves {
+			if got, want := len(leaf.LeafIdentityHash), t.hashSizeBytes; got != want {
+				return status.Errorf(codes.FailedPrecondition, "leaves[%d] has incorrect hash size %d, want %d", i, got, want)
+			}
+			res[i] = &trillian.QueuedLogLeaf{Status: ok}
+
+			if lb.Get(leaf.LeafIdentityHash) != nil {
+				res[i].Status = status.New(codes.FailedPrecondition, "conflicting LeafIdentityHash").Proto()
+				continue
+			}
+			// Check the index first, so that nothing needs rolling back if it
+			// conflicts.
+			if b.Bucket(sequencedBucket).Get(encodeInt64(leaf.LeafIndex)) != nil {
+				res[i].Status = status.New(codes.FailedPrecondition, "conflicting LeafIndex").Proto()
+				continue
+			}
+			stored := proto.Clone(leaf).(*trillian.LogLeaf)
+			stored.QueueTimestamp = queueTimestamp
+			stored.IntegrateTimestamp = integrateTimestamp
+			if err := putLeafData(lb, stored); err != nil {
+				return err
+			}
+			if _, err := putSequencedLeaf(b, stored); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (t *logTreeTX) GetSequencedLeafCount(ctx context.Context) (int64, error) {
+	t.treeTX.mu.Lock()
+	defer t.treeTX.mu.Unlock()
+
+	var count int64
+	err := t.view(func(b *bbolt.Bucket) error {
+		count = countKeys(b.Bucket(sequencedBucket))
+		return nil
+	})
+	return count, err
+}
+
+// readSequencedLeaf returns the complete sequenced leaf stored under the
+// given value of the "SequencedLeafData" bucket, joined with its data in the
+// "LeafData" bucket.
+func readSequencedLeaf(b *bbolt.Bucket, v []byte) (*trillian.LogLeaf, error) {
+	var leaf trillian.LogLeaf
+	if err := proto.Unmarshal(v, &leaf); err != nil {
+		return nil, err
+	}
+	dv := b.Bucket(leafDataBucket).Get(leaf.LeafIdentityHash)
+	if dv == nil {
+		return nil, status.Errorf(codes.Internal, "no data for sequenced leaf %d", leaf.LeafIndex)
+	}
+	var data trillian.LogLeaf
+	if err := proto.Unmarshal(dv, &data); err != nil {
+		return nil, err
+	}
+	leaf.LeafValue = data.LeafValue
+	leaf.ExtraData = data.ExtraData
+	leaf.QueueTimestamp = data.QueueTimestamp
+	return &leaf, nil
+}
+
+func (t *logTreeTX) GetLeavesByIndex(ctx context.Context, leaves []int64) ([]*trillian.LogLeaf, error) {
+	t.treeTX.mu.Lock()
+	defer t.treeTX.mu.Unlock()
+
+	if t.treeType == trillian.TreeType_LOG {
+		treeSize := int64(t.root.TreeSize)
+		for _, leaf := range leaves {
+			if leaf < 0 {
+				return nil, status.Errorf(codes.InvalidArgument, "index %d is < 0", leaf)
+			}
+			if leaf >= treeSize {
+				return nil, status.Errorf(codes.OutOfRange, "invalid leaf index %d, want < TreeSize(%d)", leaf, treeSize)
+			}
+		}
+	}
+
+	ret := make([]*trillian.LogLeaf, 0, len(leaves))
+	err := t.view(func(b *bbolt.Bucket) error {
+		sb := b.Bucket(sequencedBucket)
+		for _, index := range leaves {
+			if index < 0 {
+				continue
+			}
+			v := sb.Get(encodeInt64(index))
+			if v == nil {
+				continue
+			}
+			leaf, err := readSequencedLeaf(b, v)
+			if err != nil {
+				return err
+			}
+			ret = append(ret, leaf)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if got, want := len(ret), len(leaves); got != want {
+		return nil, status.Errorf(codes.Internal, "len(ret): %d, want %d", got, want)
+	}
+	return ret, nil
+}
+
+func (t *logTreeTX) GetLeavesByRange(ctx context.Context, start, count int64) ([]*trillian.LogLeaf, error) {
+	t.treeTX.mu.Lock()
+	defer t.treeTX.mu.Unlock()
+	return t.getLeavesByRangeInternal(ctx, start, count)
+}
+
+func (t *logTreeTX) getLeavesByRangeInternal(ctx context.Context, start, count int64) ([]*trillian.LogLeaf, error) {
+	if count <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid count %d, want > 0", count)
+	}
+	if start < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid start %d, want >= 0", start)
+	}
+
+	if t.treeType == trillian.TreeType_LOG {
+		treeSize := int64(t.root.TreeSize)
+		if treeSize <= 0 {
+			return nil, status.Errorf(codes.OutOfRange, "empty tree")
+		} else if start >= treeSize {
+			return nil, status.Errorf(codes.OutOfRange, "invalid start %d, want < TreeSize(%d)", start, treeSize)
+		}
+		// Ensure no entries queried/returned beyond the tree.
+		if maxCount := treeSize - start; count > maxCount {
+			count = maxCount
+		}
+	}
+
+	ret := make([]*trillian.LogLeaf, 0, count)
+	err := t.view(func(b *bbolt.Bucket) error {
+		c := b.Bucket(sequencedBucket).Cursor()
+		wantIndex := start
+		for k, v := c.Seek(encodeInt64(start)); k != nil && wantIndex < start+count; k, v = c.Next() {
+			if index := decodeInt64(k); index != wantIndex {
+				if wantIndex < int64(t.root.TreeSize) {
+					return fmt.Errorf("got unexpected index %d, want %d", index, wantIndex)
+				}
+				break
+			}
+			leaf, err := readSequencedLeaf(b, v)
+			if err != nil {
+				return err
+			}
+			ret = append(ret, leaf)
+			wantIndex++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (t *logTreeTX) GetLeavesByHash(ctx context.Context, leafHashes [][]byte, orderBySequence bool) ([]*trillian.LogLeaf, error) {
+	t.treeTX.mu.Lock()
+	defer t.treeTX.mu.Unlock()
+
+	var ret []*trillian.LogLeaf
+	err := t.view(func(b *bbolt.Bucket) error {
+		sb, c := b.Bucket(sequencedBucket), b.Bucket(merkleHashBucket).Cursor()
+		seen := make(map[string]bool)
+		for _, hash := range leafHashes {
+			// Skip duplicate hashes, like the IN clause of an SQL query would.
+			if seen[string(hash)] {
+				continue
+			}
+			seen[string(hash)] = true
+			for k, _ := c.Seek(hash); k != nil && len(k) == len(hash)+8 && bytes.HasPrefix(k, hash); k, _ = c.Next() {
+				v := sb.Get(k[len(hash):])
+				if v == nil {
+					return status.Errorf(codes.Internal, "no sequenced leaf %d", decodeInt64(k[len(hash):]))
+				}
+				leaf, err := readSequencedLeaf(b, v)
+				if err != nil {
+					return err
+				}
+				ret = append(ret, leaf)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if orderBySequence {
+		sort.Slice(ret, func(i, j int) bool { return ret[i].LeafIndex < ret[j].LeafIndex })
+	}
+	return ret, nil
+}
+
+func (t *logTreeTX) LatestSignedLogRoot(ctx context.Context) (trillian.SignedLogRoot, error) {
+	t.treeTX.mu.Lock()
+	defer t.treeTX.mu.Unlock()
+
+	return t.slr, nil
+}
+
+// fetchLatestRoot reads the latest SignedLogRoot from the DB and returns it.
+func (t *logTreeTX) fetchLatestRoot(ctx context.Context) (trillian.SignedLogRoot, error) {
+	var slr trillian.SignedLogRoot
+	err := t.view(func(b *bbolt.Bucket) error {
+		// The tree heads are ordered by timestamp.
+		_, v := b.Bucket(treeHeadBucket).Cursor().Last()
+		if v == nil {
+			// It's possible there are no roots for this tree yet
+			return storage.ErrTreeNeedsInit
+		}
+		return proto.Unmarshal(v, &slr)
+	})
+	if err != nil {
+		return trillian.SignedLogRoot{}, err
+	}
+	// Roots stored before key rotation was supported have no key hint.
+	if len(slr.KeyHint) == 0 {
+		slr.KeyHint = types.SerializeKeyHint(t.treeID)
+	}
+	return slr, nil
+}
+
+func (t *logTreeTX) StoreSignedLogRoot(ctx context.Context, root trillian.SignedLogRoot) error {
+	t.treeTX.mu.Lock()
+	defer t.treeTX.mu.Unlock()
+
+	var logRoot types.LogRootV1
+	if err := logRoot.UnmarshalBinary(root.LogRoot); err != nil {
+		glog.Warningf("Failed to parse log root: %x %v", root.LogRoot, err)
+		return err
+	}
+	v, err := proto.Marshal(&root)
+	if err != nil {
+		return err
+	}
+	return t.update(func(b *bbolt.Bucket) error {
+		hb := b.Bucket(treeHeadBucket)
+		key := encodeInt64(int64(logRoot.TimestampNanos))
+		if hb.Get(key) != nil {
+			return fmt.Errorf("tree head with timestamp %d already exists", logRoot.TimestampNanos)
+		}
+		return hb.Put(key, v)
+	})
+}
+
+func (t *logTreeTX) UpdateSequencedLeaves(ctx context.Context, leaves []*trillian.LogLeaf) error {
+	t.treeTX.mu.Lock()
+	defer t.treeTX.mu.Unlock()
+
+	for _, leaf := range leaves {
+		// This should fail on insert but catch it early
+		if len(leaf.LeafIdentityHash) != t.hashSizeBytes {
+			return errors.New("sequenced leaf has incorrect hash size")
+		}
+		if _, err := ptypes.Timestamp(leaf.IntegrateTimestamp); err != nil {
+			return fmt.Errorf("got invalid integrate timestamp: %v", err)
+		}
+	}
+	return t.update(func(b *bbolt.Bucket) error {
+		for _, leaf := range leaves {
+			if ok, err := putSequencedLeaf(b, leaf); err != nil {
+				return err
+			} else if !ok {
+				return fmt.Errorf("sequenced leaf with index %d already exists", leaf.LeafIndex)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,461 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/trillian"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/testonly"
+	"github.com/google/trillian/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	fakeQueueTime     = time.Date(2016, 11, 10, 15, 16, 27, 0, time.UTC)
+	fakeIntegrateTime = time.Date(2016, 11, 10, 15, 16, 30, 0, time.UTC)
+)
+
+func TestBoltLogStorage(t *testing.T) {
+	var closers []func()
+	defer func() {
+		for _, done := range closers {
+			done()
+		}
+	}()
+	tester := &testonly.LogStorageTester{NewStorage: func() (storage.LogStorage, storage.AdminStorage) {
+		db, _, done := openTestDB(t)
+		closers = append(closers, done)
+		return NewLogStorage(db, nil), NewAdminStorage(db)
+	}}
+	tester.RunAllTests(t)
+}
+
+func TestQueueLeaves(t *testing.T) {
+	ctx := context.Background()
+	db, _, done := openTestDB(t)
+	defer done()
+	tree := createTree(t, db, testonly.LogTree)
+	s := NewLogStorage(db, nil)
+	initLog(t, s, tree)
+
+	leaves := createLeaves(3)
+	res, err := s.QueueLeaves(ctx, tree, leaves, fakeQueueTime)
+	if err != nil {
+		t.Fatalf("QueueLeaves(): %v", err)
+	}
+	for i, r := range res {
+		if r.Status != nil {
+			t.Errorf("QueueLeaves()[%d].Status = %v, want nil", i, r.Status)
+		}
+	}
+
+	// Queueing the same leaves again returns the stored ones.
+	dups := createLeaves(2)
+	dups[0].ExtraData = []byte("different extra data")
+	res, err = s.QueueLeaves(ctx, tree, dups, fakeQueueTime.Add(time.Second))
+	if err != nil {
+		t.Fatalf("QueueLeaves(): %v", err)
+	}
+	for i, r := range res {
+		if got, want := status.FromProto(r.Status).Code(), codes.AlreadyExists; got != want {
+			t.Errorf("QueueLeaves()[%d].Status = %v, want code %s", i, r.Status, want)
+		}
+		// The stored leaf data doesn't include the Merkle leaf hash.
+		want := proto.Clone(leaves[i]).(*trillian.LogLeaf)
+		want.MerkleLeafHash = nil
+		if !proto.Equal(r.Leaf, want) {
+			t.Errorf("QueueLeaves()[%d].Leaf = %v, want %v", i, r.Leaf, want)
+		}
+	}
+
+	// Leaves with a bad identity hash are rejected.
+	bad := createLeaves(1)
+	bad[0].LeafIdentityHash = []byte("short")
+	if _, err := s.QueueLeaves(ctx, tree, bad, fakeQueueTime); err == nil {
+		t.Error("QueueLeaves() with a bad identity hash returned nil err")
+	}
+
+	tx, err := s.Snapshot(ctx)
+	if err != nil {
+		t.Fatalf("Snapshot(): %v", err)
+	}
+	defer tx.Close()
+	counts, err := tx.GetUnsequencedCounts(ctx)
+	if err != nil {
+		t.Fatalf("GetUnsequencedCounts(): %v", err)
+	}
+	if got, want := counts, (storage.CountByLogID{tree.TreeId: 3}); len(got) != 1 || got[tree.TreeId] != want[tree.TreeId] {
+		t.Errorf("GetUnsequencedCounts() = %v, want %v", got, want)
+	}
+}
+
+func TestDequeueLeaves(t *testing.T) {
+	ctx := context.Background()
+	db, _, done := openTestDB(t)
+	defer done()
+	tree := createTree(t, db, testonly.LogTree)
+	s := NewLogStorage(db, nil)
+	initLog(t, s, tree)
+
+	// Queue the leaves at decreasing timestamps, one second apart.
+	leaves := createLeaves(4)
+	for i, leaf := range leaves {
+		ts := fakeQueueTime.Add(time.Duration(len(leaves)-i) * time.Second)
+		if _, err := s.QueueLeaves(ctx, tree, []*trillian.LogLeaf{leaf}, ts); err != nil {
+			t.Fatalf("QueueLeaves(): %v", err)
+		}
+	}
+
+	// The guard interval excludes the most recent leaf, and the limit the
+	// oldest but one.
+	cutoff := fakeQueueTime.Add(3 * time.Second)
+	runLogTX(t, s, tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		got, err := tx.DequeueLeaves(ctx, 2, cutoff)
+		if err != nil {
+			t.Fatalf("DequeueLeaves(): %v", err)
+		}
+		want := []*trillian.LogLeaf{leaves[3], leaves[2]}
+		if len(got) != len(want) {
+			t.Fatalf("DequeueLeaves() returned %d leaves, want %d", len(got), len(want))
+		}
+		for i := range got {
+			if !bytes.Equal(got[i].LeafIdentityHash, want[i].LeafIdentityHash) || !bytes.Equal(got[i].MerkleLeafHash, want[i].MerkleLeafHash) {
+				t.Errorf("DequeueLeaves()[%d] = %v, want %v", i, got[i], want[i])
+			}
+			if !proto.Equal(got[i].QueueTimestamp, want[i].QueueTimestamp) {
+				t.Errorf("DequeueLeaves()[%d].QueueTimestamp = %v, want %v", i, got[i].QueueTimestamp, want[i].QueueTimestamp)
+			}
+		}
+		return nil
+	})
+
+	// The dequeued leaves are gone, and the next one is now within the limit.
+	runLogTX(t, s, tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		got, err := tx.DequeueLeaves(ctx, 10, cutoff)
+		if err != nil {
+			t.Fatalf("DequeueLeaves(): %v", err)
+		}
+		if len(got) != 1 || !bytes.Equal(got[0].LeafIdentityHash, leaves[1].LeafIdentityHash) {
+			t.Errorf("DequeueLeaves() = %v, want [%v]", got, leaves[1])
+		}
+		return nil
+	})
+}
+
+// sequence dequeues all the queued leaves, and integrates them into the log
+// like the sequencer would, without computing the Merkle tree.
+func sequence(t *testing.T, s storage.LogStorage, tree *trillian.Tree) {
+	t.Helper()
+	integrateTimestamp, err := ptypes.TimestampProto(fakeIntegrateTime)
+	if err != nil {
+		t.Fatalf("TimestampProto(): %v", err)
+	}
+	runLogTX(t, s, tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		root, err := tx.LatestSignedLogRoot(ctx)
+		if err != nil {
+			t.Fatalf("LatestSignedLogRoot(): %v", err)
+		}
+		var logRoot1 types.LogRootV1
+		if err := logRoot1.UnmarshalBinary(root.LogRoot); err != nil {
+			t.Fatalf("UnmarshalBinary(): %v", err)
+		}
+		leaves, err := tx.DequeueLeaves(ctx, 100, fakeIntegrateTime)
+		if err != nil {
+			t.Fatalf("DequeueLeaves(): %v", err)
+		}
+		for i, leaf := range leaves {
+			leaf.LeafIndex = int64(logRoot1.TreeSize) + int64(i)
+			leaf.IntegrateTimestamp = integrateTimestamp
+		}
+		if err := tx.UpdateSequencedLeaves(ctx, leaves); err != nil {
+			t.Fatalf("UpdateSequencedLeaves(): %v", err)
+		}
+		return tx.StoreSignedLogRoot(ctx, logRoot(t, &types.LogRootV1{
+			TreeSize:       logRoot1.TreeSize + uint64(len(leaves)),
+			TimestampNanos: logRoot1.TimestampNanos + 1,
+			Revision:       logRoot1.Revision + 1,
+		}))
+	})
+}
+
+func TestSequencedLeaves(t *testing.T) {
+	ctx := context.Background()
+	db, _, done := openTestDB(t)
+	defer done()
+	tree := createTree(t, db, testonly.LogTree)
+	s := NewLogStorage(db, nil)
+	initLog(t, s, tree)
+
+	// The first and last leaves have the same Merkle leaf hash.
+	leaves := createLeaves(4)
+	leaves[3].MerkleLeafHash = leaves[0].MerkleLeafHash
+	if _, err := s.QueueLeaves(ctx, tree, leaves, fakeQueueTime); err != nil {
+		t.Fatalf("QueueLeaves(): %v", err)
+	}
+	sequence(t, s, tree)
+
+	tx, err := s.SnapshotForTree(ctx, tree)
+	if err != nil {
+		t.Fatalf("SnapshotForTree(): %v", err)
+	}
+	defer tx.Close()
+
+	if got, err := tx.GetSequencedLeafCount(ctx); err != nil || got != 4 {
+		t.Errorf("GetSequencedLeafCount() = (%d, %v), want (4, nil)", got, err)
+	}
+	if rev, err := tx.ReadRevision(ctx); err != nil || rev != 1 {
+		t.Errorf("ReadRevision() = (%d, %v), want (1, nil)", rev, err)
+	}
+
+	got, err := tx.GetLeavesByRange(ctx, 0, 10)
+	if err != nil {
+		t.Fatalf("GetLeavesByRange(): %v", err)
+	}
+	if len(got) != len(leaves) {
+		t.Fatalf("GetLeavesByRange() returned %d leaves, want %d", len(got), len(leaves))
+	}
+	byIdentity := make(map[string]*trillian.LogLeaf)
+	for i, leaf := range got {
+		if leaf.LeafIndex != int64(i) {
+			t.Errorf("GetLeavesByRange()[%d].LeafIndex = %d", i, leaf.LeafIndex)
+		}
+		if ts, err := ptypes.Timestamp(leaf.IntegrateTimestamp); err != nil || !ts.Equal(fakeIntegrateTime) {
+			t.Errorf("GetLeavesByRange()[%d].IntegrateTimestamp = %v", i, leaf.IntegrateTimestamp)
+		}
+		byIdentity[string(leaf.LeafIdentityHash)] = leaf
+	}
+	for _, want := range leaves {
+		leaf := byIdentity[string(want.LeafIdentityHash)]
+		if leaf == nil || !bytes.Equal(leaf.LeafValue, want.LeafValue) || !bytes.Equal(leaf.ExtraData, want.ExtraData) {
+			t.Errorf("GetLeavesByRange() returned %v, want leaf %v", leaf, want)
+		}
+	}
+
+	byIndex, err := tx.GetLeavesByIndex(ctx, []int64{3, 1})
+	if err != nil {
+		t.Fatalf("GetLeavesByIndex(): %v", err)
+	}
+	if len(byIndex) != 2 || !proto.Equal(byIndex[0], got[3]) || !proto.Equal(byIndex[1], got[1]) {
+		t.Errorf("GetLeavesByIndex() = %v, want [%v %v]", byIndex, got[3], got[1])
+	}
+
+	byHash, err := tx.GetLeavesByHash(ctx, [][]byte{leaves[0].MerkleLeafHash}, true /* orderBySequence */)
+	if err != nil {
+		t.Fatalf("GetLeavesByHash(): %v", err)
+	}
+	if len(byHash) != 2 || byHash[0].LeafIndex > byHash[1].LeafIndex {
+		t.Errorf("GetLeavesByHash() = %v, want 2 leaves in sequence order", byHash)
+	}
+	if byHash, err := tx.GetLeavesByHash(ctx, [][]byte{hash("unknown")}, false); err != nil || len(byHash) != 0 {
+		t.Errorf("GetLeavesByHash(unknown) = (%v, %v), want no leaves", byHash, err)
+	}
+
+	for _, test := range []struct {
+		desc         string
+		start, count int64
+		want         codes.Code
+	}{
+		{desc: "negativeStart", start: -1, count: 1, want: codes.InvalidArgument},
+		{desc: "zeroCount", start: 0, count: 0, want: codes.InvalidArgument},
+		{desc: "beyondTree", start: 4, count: 1, want: codes.OutOfRange},
+	} {
+		if _, err := tx.GetLeavesByRange(ctx, test.start, test.count); status.Code(err) != test.want {
+			t.Errorf("%s: GetLeavesByRange() returned err = %v, want code %s", test.desc, err, test.want)
+		}
+	}
+	if _, err := tx.GetLeavesByIndex(ctx, []int64{4}); status.Code(err) != codes.OutOfRange {
+		t.Errorf("GetLeavesByIndex(4) returned err = %v, want code %s", err, codes.OutOfRange)
+	}
+}
+
+func TestAddSequencedLeaves(t *testing.T) {
+	ctx := context.Background()
+	db, _, done := openTestDB(t)
+	defer done()
+	tree := createTree(t, db, testonly.PreorderedLogTree)
+	s := NewLogStorage(db, nil)
+	initLog(t, s, tree)
+
+	leaves := createLeaves(4)
+	for i, leaf := range leaves {
+		leaf.LeafIndex = int64(i)
+	}
+	res, err := s.AddSequencedLeaves(ctx, tree, leaves[:2], fakeQueueTime)
+	if err != nil {
+		t.Fatalf("AddSequencedLeaves(): %v", err)
+	}
+	for i, r := range res {
+		if got := status.FromProto(r.Status).Code(); got != codes.OK {
+			t.Errorf("AddSequencedLeaves()[%d].Status = %v, want OK", i, r.Status)
+		}
+	}
+
+	// leaves[2] has an existing index, leaves[0] an existing identity hash.
+	conflicting := []*trillian.LogLeaf{proto.Clone(leaves[2]).(*trillian.LogLeaf), leaves[0], leaves[3]}
+	conflicting[0].LeafIndex = 1
+	res, err = s.AddSequencedLeaves(ctx, tree, conflicting, fakeQueueTime)
+	if err != nil {
+		t.Fatalf("AddSequencedLeaves(): %v", err)
+	}
+	for i, want := range []codes.Code{codes.FailedPrecondition, codes.FailedPrecondition, codes.OK} {
+		if got := status.FromProto(res[i].Status).Code(); got != want {
+			t.Errorf("AddSequencedLeaves()[%d].Status = %v, want code %s", i, res[i].Status, want)
+		}
+	}
+
+	// The leaves are dequeued in order from the preordered log.
+	runLogTX(t, s, tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		got, err := tx.DequeueLeaves(ctx, 10, fakeIntegrateTime)
+		if err != nil {
+			t.Fatalf("DequeueLeaves(): %v", err)
+		}
+		// The conflicting leaf at index 2 was not added.
+		if len(got) != 2 || got[0].LeafIndex != 0 || got[1].LeafIndex != 1 {
+			t.Errorf("DequeueLeaves() = %v, want leaves 0 and 1", got)
+		}
+		if count, err := tx.GetSequencedLeafCount(ctx); err != nil || count != 3 {
+			t.Errorf("GetSequencedLeafCount() = (%d, %v), want (3, nil)", count, err)
+		}
+		return nil
+	})
+}
+
+func TestExpireQueuedLeaves(t *testing.T) {
+	ctx := context.Background()
+	db, _, done := openTestDB(t)
+	defer done()
+	tree := createTree(t, db, testonly.LogTree)
+	s := NewLogStorage(db, nil)
+	initLog(t, s, tree)
+
+	leaves := createLeaves(3)
+	for i, leaf := range leaves {
+		ts := fakeQueueTime.Add(time.Duration(i) * time.Second)
+		if _, err := s.QueueLeaves(ctx, tree, []*trillian.LogLeaf{leaf}, ts); err != nil {
+			t.Fatalf("QueueLeaves(): %v", err)
+		}
+	}
+	runLogTX(t, s, tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		n, err := tx.ExpireQueuedLeaves(ctx, 10, fakeQueueTime.Add(2*time.Second))
+		if err != nil || n != 2 {
+			t.Errorf("ExpireQueuedLeaves() = (%d, %v), want (2, nil)", n, err)
+		}
+		return nil
+	})
+
+	// The expired leaves can be queued again.
+	res, err := s.QueueLeaves(ctx, tree, leaves, fakeQueueTime.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("QueueLeaves(): %v", err)
+	}
+	for i, want := range []codes.Code{codes.OK, codes.OK, codes.AlreadyExists} {
+		if got := status.FromProto(res[i].Status).Code(); got != want {
+			t.Errorf("QueueLeaves()[%d].Status = %v, want code %s", i, res[i].Status, want)
+		}
+	}
+}
+
+func TestLatestSignedLogRoot(t *testing.T) {
+	ctx := context.Background()
+	db, _, done := openTestDB(t)
+	defer done()
+	tree := createTree(t, db, testonly.LogTree)
+	s := NewLogStorage(db, nil)
+
+	tx, err := s.SnapshotForTree(ctx, tree)
+	if err != storage.ErrTreeNeedsInit {
+		t.Errorf("SnapshotForTree() returned err = %v, want %v", err, storage.ErrTreeNeedsInit)
+	}
+	if tx != nil {
+		tx.Close()
+	}
+
+	root1 := logRoot(t, &types.LogRootV1{TimestampNanos: 98765, TreeSize: 16, Revision: 5, RootHash: hash("root1")})
+	root2 := logRoot(t, &types.LogRootV1{TimestampNanos: 98766, TreeSize: 16, Revision: 6, RootHash: hash("root2")})
+	runLogTX(t, s, tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		if err := tx.StoreSignedLogRoot(ctx, root1); err != nil {
+			t.Fatalf("StoreSignedLogRoot(): %v", err)
+		}
+		// Shouldn't be able to do it again.
+		if err := tx.StoreSignedLogRoot(ctx, root1); err == nil {
+			t.Error("StoreSignedLogRoot() allowed a duplicate root")
+		}
+		return tx.StoreSignedLogRoot(ctx, root2)
+	})
+
+	runLogTX(t, s, tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		got, err := tx.LatestSignedLogRoot(ctx)
+		if err != nil {
+			t.Fatalf("LatestSignedLogRoot(): %v", err)
+		}
+		want := root2
+		want.KeyHint = types.SerializeKeyHint(tree.TreeId)
+		if !proto.Equal(&got, &want) {
+			t.Errorf("LatestSignedLogRoot() = %v, want %v", got, want)
+		}
+		if rev, err := tx.WriteRevision(ctx); err != nil || rev != 7 {
+			t.Errorf("WriteRevision() = (%d, %v), want (7, nil)", rev, err)
+		}
+		return nil
+	})
+}
+
+func TestGetActiveLogIDs(t *testing.T) {
+	ctx := context.Background()
+	db, _, done := openTestDB(t)
+	defer done()
+	as := NewAdminStorage(db)
+
+	log := createTree(t, db, testonly.LogTree)
+	preordered := createTree(t, db, testonly.PreorderedLogTree)
+	createTree(t, db, testonly.MapTree)
+	frozen := createTree(t, db, testonly.LogTree)
+	if _, err := storage.UpdateTree(ctx, as, frozen.TreeId, func(tree *trillian.Tree) {
+		tree.TreeState = trillian.TreeState_FROZEN
+	}); err != nil {
+		t.Fatalf("UpdateTree(): %v", err)
+	}
+	deleted := createTree(t, db, testonly.LogTree)
+	if _, err := storage.SoftDeleteTree(ctx, as, deleted.TreeId); err != nil {
+		t.Fatalf("SoftDeleteTree(): %v", err)
+	}
+
+	tx, err := NewLogStorage(db, nil).Snapshot(ctx)
+	if err != nil {
+		t.Fatalf("Snapshot(): %v", err)
+	}
+	defer tx.Close()
+	got, err := tx.GetActiveLogIDs(ctx)
+	if err != nil {
+		t.Fatalf("GetActiveLogIDs(): %v", err)
+	}
+	want := map[int64]bool{log.TreeId: true, preordered.TreeId: true}
+	if len(got) != len(want) {
+		t.Fatalf("GetActiveLogIDs() = %v, want %v", got, want)
+	}
+	for _, id := range got {
+		if !want[id] {
+			t.Errorf("GetActiveLogIDs() = %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,470 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian"
+	"github.com/google/trillian/merkle/hashers"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/cache"
+	"github.com/google/trillian/storage/storagepb"
+	"github.com/google/trillian/types"
+
+	bbolt "go.etcd.io/bbolt"
+)
+
+var defaultMapStrata = []int{8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 176}
+
+type boltMapStorage struct {
+	db *bbolt.DB
+}
+
+// NewMapStorage creates a bolt MapStorage instance backed by db.
+func NewMapStorage(db *bbolt.DB) storage.MapStorage {
+	return &boltMapStorage{db: db}
+}
+
+func (m *boltMapStorage) CheckDatabaseAccessible(ctx context.Context) error {
+	return checkDatabaseAccessible(m.db)
+}
+
+// begin starts a new map transaction. Read-only transactions hold a bbolt
+// read-only transaction. Read-write transactions never hold a bbolt
+// transaction: their writes are buffered, and applied to the database by
+// Commit.
+func (m *boltMapStorage) begin(ctx context.Context, tree *trillian.Tree, readonly bool) (*mapTreeTX, error) {
+	hasher, err := hashers.NewMapHasher(tree.HashStrategy)
+	if err != nil {
+		return nil, err
+	}
+
+	var btx *bbolt.Tx
+	if readonly {
+		if btx, err = m.db.Begin(false /* writable */); err != nil {
+			return nil, err
+		}
+	}
+	stCache := cache.NewMapSubtreeCache(defaultMapStrata, tree.TreeId, hasher)
+	mtx := &mapTreeTX{
+		treeTX:       newTreeTX(m.db, btx, tree.TreeId, hasher.Size(), stCache),
+		ms:           m,
+		readRevision: -1,
+		leaves:       make(map[string][]byte),
+	}
+
+	if readonly {
+		// readRevision will be set later, by the first
+		// GetSignedMapRoot/LatestSignedMapRoot operation.
+		return mtx, nil
+	}
+
+	// A read-write transaction needs to know the current revision
+	// so it can write at revision+1.
+	root, err := mtx.LatestSignedMapRoot(ctx)
+	if err == storage.ErrTreeNeedsInit {
+		return mtx, err
+	} else if err != nil {
+		mtx.Close()
+		return nil, err
+	}
+
+	var mr types.MapRootV1
+	if err := mr.UnmarshalBinary(root.MapRoot); err != nil {
+		mtx.Close()
+		return nil, err
+	}
+
+	mtx.readRevision = int64(mr.Revision)
+	mtx.treeTX.writeRevision = int64(mr.Revision) + 1
+	return mtx, nil
+}
+
+func (m *boltMapStorage) SnapshotForTree(ctx context.Context, tree *trillian.Tree) (storage.ReadOnlyMapTreeTX, error) {
+	tx, err := m.begin(ctx, tree, true /* readonly */)
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+func (m *boltMapStorage) ReadWriteTransaction(ctx context.Context, tree *trillian.Tree, f storage.MapTXFunc) error {
+	tx, err := m.begin(ctx, tree, false /* readonly */)
+	if tx != nil {
+		defer tx.Close()
+	}
+	if err != nil && err != storage.ErrTreeNeedsInit {
+		return err
+	}
+	if err := f(ctx, tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+type mapTreeTX struct {
+	treeTX
+	ms           *boltMapStorage
+	readRevision int64
+	// leaves holds the map leaves set by the transaction, marshaled and keyed
+	// by index, until they're written by Commit.
+	leaves map[string][]byte
+	// root holds the marshaled SignedMapRoot stored by the transaction, if
+	// any, until it's written by Commit.
+	root    []byte
+	rootRev int64
+}
+
+func (m *mapTreeTX) ReadRevision(ctx context.Context) (int64, error) {
+	m.treeTX.mu.Lock()
+	defer m.treeTX.mu.Unlock()
+	return m.readRevision, nil
+}
+
+func (m *mapTreeTX) WriteRevision(ctx context.Context) (int64, error) {
+	m.treeTX.mu.Lock()
+	defer m.treeTX.mu.Unlock()
+	if m.treeTX.writeRevision < 0 {
+		return m.treeTX.writeRevision, errors.New("mapTreeTX write revision not populated")
+	}
+	return m.treeTX.writeRevision, nil
+}
+
+func (m *mapTreeTX) Set(ctx context.Context, keyHash []byte, value trillian.MapLeaf) error {
+	m.treeTX.mu.Lock()
+	defer m.treeTX.mu.Unlock()
+
+	if m.writeRevision < 0 {
+		return errors.New("mapTreeTX write revision not populated")
+	}
+	if _, ok := m.leaves[string(keyHash)]; ok {
+		return fmt.Errorf("map leaf %x already set at revision %d", keyHash, m.writeRevision)
+	}
+	if err := m.view(func(b *bbolt.Bucket) error {
+		if b.Bucket(mapLeafBucket).Get(revisionedKey(keyHash, m.writeRevision)) != nil {
+			return fmt.Errorf("map leaf %x already set at revision %d", keyHash, m.writeRevision)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+	flatValue, err := proto.Marshal(&value)
+	if err != nil {
+		return err
+	}
+	m.leaves[string(keyHash)] = flatValue
+	return nil
+}
+
+// Get returns a list of map leaves indicated by indexes.
+// If an index is not found, no corresponding entry is returned.
+// Each MapLeaf.Index is overwritten with the index the leaf was found at.
+func (m *mapTreeTX) Get(ctx context.Context, revision int64, indexes [][]byte) ([]*trillian.MapLeaf, error) {
+	m.treeTX.mu.Lock()
+	defer m.treeTX.mu.Unlock()
+
+	// If no indexes are requested, return an empty set.
+	if len(indexes) == 0 {
+		return []*trillian.MapLeaf{}, nil
+	}
+
+	ret := make([]*trillian.MapLeaf, 0, len(indexes))
+	seen := make(map[string]bool)
+	err := m.view(func(b *bbolt.Bucket) error {
+		lb := b.Bucket(mapLeafBucket)
+		for _, index := range indexes {
+			if seen[string(index)] {
+				continue
+			}
+			seen[string(index)] = true
+
+			flatData, ok := m.leaves[string(index)]
+			if !ok || revision < m.writeRevision {
+				flatData = getAtOrBelow(lb, index, revision)
+			}
+			if len(flatData) == 0 {
+				continue
+			}
+			var mapLeaf trillian.MapLeaf
+			if err := proto.Unmarshal(flatData, &mapLeaf); err != nil {
+				return err
+			}
+			mapLeaf.Index = append([]byte{}, index...)
+			ret = append(ret, &mapLeaf)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// ListLeaves returns up to limit of the map leaves at revision, in increasing
+// index order, starting from startIndex. The startIndex must either be empty,
+// or have the same length as the indexes of the map.
+func (m *mapTreeTX) ListLeaves(ctx context.Context, revision int64, startIndex []byte, limit int) ([]*trillian.MapLeaf, error) {
+	m.treeTX.mu.Lock()
+	defer m.treeTX.mu.Unlock()
+
+	// The leaves set by this transaction may hide committed ones, so read
+	// enough committed leaves to fill the page regardless.
+	pending := make(map[string][]byte)
+	if revision >= m.writeRevision {
+		for index, v := range m.leaves {
+			if index >= string(startIndex) {
+				pending[index] = v
+			}
+		}
+	}
+	values := make(map[string][]byte)
+	err := m.view(func(b *bbolt.Bucket) error {
+		// All revisions of a leaf are adjacent in the bucket, ordered by
+		// revision. As all indexes have the same length, the leaves are
+		// ordered by index.
+		c := b.Bucket(mapLeafBucket).Cursor()
+		var index, value []byte
+		found := 0
+		flush := func() {
+			if len(value) > 0 {
+				values[string(index)] = append([]byte{}, value...)
+				found++
+			}
+		}
+		for k, v := c.Seek(revisionedKeyPrefix(startIndex)); k != nil; k, v = c.Next() {
+			id, rev := k[1:len(k)-8], decodeInt64(k[len(k)-8:])
+			if !bytes.Equal(id, index) {
+				flush()
+				if found >= limit+len(pending) {
+					return nil
+				}
+				index, value = id, nil
+			}
+			if rev <= revision {
+				value = v
+			}
+		}
+		flush()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for index, v := range pending {
+		values[index] = v
+	}
+
+	indexes := make([]string, 0, len(values))
+	for index, v := range values {
+		if len(v) > 0 {
+			indexes = append(indexes, index)
+		}
+	}
+	sort.Strings(indexes)
+	if len(indexes) > limit {
+		indexes = indexes[:limit]
+	}
+	ret := make([]*trillian.MapLeaf, 0, len(indexes))
+	for _, index := range indexes {
+		var mapLeaf trillian.MapLeaf
+		if err := proto.Unmarshal(values[index], &mapLeaf); err != nil {
+			return nil, err
+		}
+		mapLeaf.Index = []byte(index)
+		ret = append(ret, &mapLeaf)
+	}
+	return ret, nil
+}
+
+// ListChangedIndexes returns up to limit of the indexes of the map leaves set
+// at revisions in (fromRevision, toRevision], in increasing order, starting
+// from startIndex. The startIndex must either be empty, or have the same
+// length as the indexes of the map.
+func (m *mapTreeTX) ListChangedIndexes(ctx context.Context, fromRevision, toRevision int64, startIndex []byte, limit int) ([][]byte, error) {
+	m.treeTX.mu.Lock()
+	defer m.treeTX.mu.Unlock()
+
+	changed := make(map[string]bool)
+	if fromRevision < m.writeRevision && m.writeRevision <= toRevision {
+		for index := range m.leaves {
+			if index >= string(startIndex) {
+				changed[index] = true
+			}
+		}
+	}
+	err := m.view(func(b *bbolt.Bucket) error {
+		c := b.Bucket(mapLeafBucket).Cursor()
+		found := 0
+		var last []byte
+		for k, _ := c.Seek(revisionedKeyPrefix(startIndex)); k != nil && found < limit; k, _ = c.Next() {
+			id, rev := k[1:len(k)-8], decodeInt64(k[len(k)-8:])
+			if rev <= fromRevision || rev > toRevision || bytes.Equal(id, last) {
+				continue
+			}
+			last = id
+			changed[string(id)] = true
+			found++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := make([]string, 0, len(changed))
+	for index := range changed {
+		indexes = append(indexes, index)
+	}
+	sort.Strings(indexes)
+	if len(indexes) > limit {
+		indexes = indexes[:limit]
+	}
+	ret := make([][]byte, 0, len(indexes))
+	for _, index := range indexes {
+		ret = append(ret, []byte(index))
+	}
+	return ret, nil
+}
+
+func (m *mapTreeTX) GetSignedMapRoot(ctx context.Context, revision int64) (trillian.SignedMapRoot, error) {
+	m.treeTX.mu.Lock()
+	defer m.treeTX.mu.Unlock()
+
+	var root trillian.SignedMapRoot
+	err := m.view(func(b *bbolt.Bucket) error {
+		v := b.Bucket(mapHeadBucket).Get(encodeInt64(revision))
+		if v == nil {
+			if revision == 0 {
+				return storage.ErrTreeNeedsInit
+			}
+			return fmt.Errorf("no SignedMapRoot for revision %d", revision)
+		}
+		return proto.Unmarshal(v, &root)
+	})
+	if err != nil {
+		return trillian.SignedMapRoot{}, err
+	}
+	m.readRevision = revision
+	return root, nil
+}
+
+func (m *mapTreeTX) LatestSignedMapRoot(ctx context.Context) (trillian.SignedMapRoot, error) {
+	m.treeTX.mu.Lock()
+	defer m.treeTX.mu.Unlock()
+
+	var root trillian.SignedMapRoot
+	err := m.view(func(b *bbolt.Bucket) error {
+		// The map heads are ordered by revision.
+		_, v := b.Bucket(mapHeadBucket).Cursor().Last()
+		if v == nil {
+			// It's possible there are no roots for this tree yet
+			return storage.ErrTreeNeedsInit
+		}
+		return proto.Unmarshal(v, &root)
+	})
+	if err != nil {
+		return trillian.SignedMapRoot{}, err
+	}
+
+	var mr types.MapRootV1
+	if err := mr.UnmarshalBinary(root.MapRoot); err != nil {
+		return trillian.SignedMapRoot{}, err
+	}
+	m.readRevision = int64(mr.Revision)
+	return root, nil
+}
+
+func (m *mapTreeTX) StoreSignedMapRoot(ctx context.Context, root trillian.SignedMapRoot) error {
+	m.treeTX.mu.Lock()
+	defer m.treeTX.mu.Unlock()
+
+	var r types.MapRootV1
+	if err := r.UnmarshalBinary(root.MapRoot); err != nil {
+		return err
+	}
+	if m.root != nil {
+		return fmt.Errorf("SignedMapRoot for revision %d already stored", m.rootRev)
+	}
+	v, err := proto.Marshal(&root)
+	if err != nil {
+		return err
+	}
+	m.root, m.rootRev = v, int64(r.Revision)
+	return nil
+}
+
+// Commit flushes the subtree cache, and writes all the data stored by the
+// transaction to the database in a single bbolt transaction.
+func (m *mapTreeTX) Commit() error {
+	m.treeTX.mu.Lock()
+	defer m.treeTX.mu.Unlock()
+
+	if m.closed {
+		return errors.New("transaction already closed")
+	}
+	m.closed = true
+	if m.tx != nil {
+		// Only read-only transactions hold a bbolt transaction.
+		return m.tx.Rollback()
+	}
+
+	var subtrees []*storagepb.SubtreeProto
+	if m.writeRevision > -1 {
+		if err := m.subtreeCache.Flush(func(st []*storagepb.SubtreeProto) error {
+			subtrees = append(subtrees, st...)
+			return nil
+		}); err != nil {
+			glog.Warningf("TX commit flush error: %v", err)
+			return err
+		}
+	}
+	if len(subtrees) == 0 && len(m.leaves) == 0 && m.root == nil {
+		return nil
+	}
+
+	return m.db.Update(func(tx *bbolt.Tx) error {
+		b, err := dataBucket(tx, m.treeID)
+		if err != nil {
+			return err
+		}
+		if err := putSubtrees(b, m.writeRevision, subtrees); err != nil {
+			return err
+		}
+		lb := b.Bucket(mapLeafBucket)
+		for index, v := range m.leaves {
+			if err := lb.Put(revisionedKey([]byte(index), m.writeRevision), v); err != nil {
+				return err
+			}
+		}
+		if m.root != nil {
+			hb := b.Bucket(mapHeadBucket)
+			key := encodeInt64(m.rootRev)
+			if hb.Get(key) != nil {
+				return fmt.Errorf("SignedMapRoot for revision %d already exists", m.rootRev)
+			}
+			if err := hb.Put(key, m.root); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
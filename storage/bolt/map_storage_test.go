@@ -0,0 +1,243 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/testonly"
+	"github.com/google/trillian/types"
+)
+
+func TestBoltMapStorage(t *testing.T) {
+	var closers []func()
+	defer func() {
+		for _, done := range closers {
+			done()
+		}
+	}()
+	tester := &testonly.MapStorageTester{NewStorage: func() (storage.MapStorage, storage.AdminStorage) {
+		db, _, done := openTestDB(t)
+		closers = append(closers, done)
+		return NewMapStorage(db), NewAdminStorage(db)
+	}}
+	tester.RunAllTests(t)
+}
+
+func mapRoot(t *testing.T, rev uint64) trillian.SignedMapRoot {
+	t.Helper()
+	b, err := (&types.MapRootV1{Revision: rev, TimestampNanos: rev + 1, RootHash: hash("root")}).MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary(): %v", err)
+	}
+	return trillian.SignedMapRoot{MapRoot: b, Signature: []byte("notempty")}
+}
+
+func runMapTX(t *testing.T, s storage.MapStorage, tree *trillian.Tree, f storage.MapTXFunc) {
+	t.Helper()
+	if err := s.ReadWriteTransaction(context.Background(), tree, f); err != nil {
+		t.Fatalf("ReadWriteTransaction(): %v", err)
+	}
+}
+
+// initMap stores the initial root of the map, at revision 0.
+func initMap(t *testing.T, s storage.MapStorage, tree *trillian.Tree) {
+	t.Helper()
+	runMapTX(t, s, tree, func(ctx context.Context, tx storage.MapTreeTX) error {
+		return tx.StoreSignedMapRoot(ctx, mapRoot(t, 0))
+	})
+}
+
+func TestMapSetGet(t *testing.T) {
+	ctx := context.Background()
+	db, _, done := openTestDB(t)
+	defer done()
+	tree := createTree(t, db, testonly.MapTree)
+	s := NewMapStorage(db)
+	initMap(t, s, tree)
+
+	index := hash("index")
+	for rev, value := range []string{"value 1", "value 2"} {
+		runMapTX(t, s, tree, func(ctx context.Context, tx storage.MapTreeTX) error {
+			leaf := trillian.MapLeaf{LeafHash: hash(value), LeafValue: []byte(value)}
+			if err := tx.Set(ctx, index, leaf); err != nil {
+				t.Fatalf("Set(): %v", err)
+			}
+			// The same index can't be set twice in a revision.
+			if err := tx.Set(ctx, index, leaf); err == nil {
+				t.Error("Set() twice in a revision returned nil err")
+			}
+			// The pending value is visible within the transaction.
+			if got, err := tx.Get(ctx, int64(rev+1), [][]byte{index}); err != nil || len(got) != 1 || !bytes.Equal(got[0].LeafValue, leaf.LeafValue) {
+				t.Errorf("Get() = (%v, %v), want [%v]", got, err, leaf)
+			}
+			return tx.StoreSignedMapRoot(ctx, mapRoot(t, uint64(rev+1)))
+		})
+	}
+
+	for _, test := range []struct {
+		rev  int64
+		want string
+	}{
+		{rev: 0},
+		{rev: 1, want: "value 1"},
+		{rev: 2, want: "value 2"},
+		{rev: 5, want: "value 2"},
+	} {
+		tx, err := s.SnapshotForTree(ctx, tree)
+		if err != nil {
+			t.Fatalf("SnapshotForTree(): %v", err)
+		}
+		got, err := tx.Get(ctx, test.rev, [][]byte{index, index, hash("unknown")})
+		if err != nil {
+			t.Errorf("Get(%d): %v", test.rev, err)
+		}
+		switch {
+		case test.want == "" && len(got) != 0:
+			t.Errorf("Get(%d) = %v, want no leaves", test.rev, got)
+		case test.want != "" && (len(got) != 1 || !bytes.Equal(got[0].LeafValue, []byte(test.want)) || !bytes.Equal(got[0].Index, index)):
+			t.Errorf("Get(%d) = %v, want leaf %q", test.rev, got, test.want)
+		}
+		tx.Close()
+	}
+}
+
+func TestMapRoots(t *testing.T) {
+	ctx := context.Background()
+	db, _, done := openTestDB(t)
+	defer done()
+	tree := createTree(t, db, testonly.MapTree)
+	s := NewMapStorage(db)
+
+	tx, err := s.SnapshotForTree(ctx, tree)
+	if err != nil {
+		t.Fatalf("SnapshotForTree(): %v", err)
+	}
+	if _, err := tx.LatestSignedMapRoot(ctx); err != storage.ErrTreeNeedsInit {
+		t.Errorf("LatestSignedMapRoot() returned err = %v, want %v", err, storage.ErrTreeNeedsInit)
+	}
+	tx.Close()
+
+	initMap(t, s, tree)
+	runMapTX(t, s, tree, func(ctx context.Context, tx storage.MapTreeTX) error {
+		if rev, err := tx.WriteRevision(ctx); err != nil || rev != 1 {
+			t.Errorf("WriteRevision() = (%d, %v), want (1, nil)", rev, err)
+		}
+		if err := tx.StoreSignedMapRoot(ctx, mapRoot(t, 1)); err != nil {
+			t.Fatalf("StoreSignedMapRoot(): %v", err)
+		}
+		// Shouldn't be able to do it again.
+		if err := tx.StoreSignedMapRoot(ctx, mapRoot(t, 1)); err == nil {
+			t.Error("StoreSignedMapRoot() allowed a duplicate root")
+		}
+		return nil
+	})
+
+	// Storing an existing revision fails on commit.
+	if err := s.ReadWriteTransaction(ctx, tree, func(ctx context.Context, tx storage.MapTreeTX) error {
+		return tx.StoreSignedMapRoot(ctx, mapRoot(t, 1))
+	}); err == nil {
+		t.Error("ReadWriteTransaction() storing a duplicate root returned nil err")
+	}
+
+	tx, err = s.SnapshotForTree(ctx, tree)
+	if err != nil {
+		t.Fatalf("SnapshotForTree(): %v", err)
+	}
+	defer tx.Close()
+	got, err := tx.LatestSignedMapRoot(ctx)
+	if want := mapRoot(t, 1); err != nil || !proto.Equal(&got, &want) {
+		t.Errorf("LatestSignedMapRoot() = (%v, %v), want (%v, nil)", got, err, want)
+	}
+	got, err = tx.GetSignedMapRoot(ctx, 0)
+	if want := mapRoot(t, 0); err != nil || !proto.Equal(&got, &want) {
+		t.Errorf("GetSignedMapRoot(0) = (%v, %v), want (%v, nil)", got, err, want)
+	}
+	if _, err := tx.GetSignedMapRoot(ctx, 2); err == nil {
+		t.Error("GetSignedMapRoot(2) returned nil err")
+	}
+}
+
+// TestMapSnapshotConsistent checks that a snapshot doesn't see the writes
+// committed after it started.
+func TestMapSnapshotConsistent(t *testing.T) {
+	ctx := context.Background()
+	db, _, done := openTestDB(t)
+	defer done()
+	tree := createTree(t, db, testonly.MapTree)
+	s := NewMapStorage(db)
+	initMap(t, s, tree)
+
+	tx, err := s.SnapshotForTree(ctx, tree)
+	if err != nil {
+		t.Fatalf("SnapshotForTree(): %v", err)
+	}
+	defer tx.Close()
+
+	index := hash("index")
+	runMapTX(t, s, tree, func(ctx context.Context, tx storage.MapTreeTX) error {
+		if err := tx.Set(ctx, index, trillian.MapLeaf{LeafHash: hash("value"), LeafValue: []byte("value")}); err != nil {
+			t.Fatalf("Set(): %v", err)
+		}
+		return tx.StoreSignedMapRoot(ctx, mapRoot(t, 1))
+	})
+
+	got, err := tx.LatestSignedMapRoot(ctx)
+	if want := mapRoot(t, 0); err != nil || !proto.Equal(&got, &want) {
+		t.Errorf("LatestSignedMapRoot() = (%v, %v), want (%v, nil)", got, err, want)
+	}
+	if leaves, err := tx.Get(ctx, 1, [][]byte{index}); err != nil || len(leaves) != 0 {
+		t.Errorf("Get(1) = (%v, %v), want no leaves", leaves, err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Errorf("Commit(): %v", err)
+	}
+}
+
+// TestMapNestedTransactions checks that a read-write transaction can commit
+// while another one is open, as the map server does when writing leaves.
+func TestMapNestedTransactions(t *testing.T) {
+	ctx := context.Background()
+	db, _, done := openTestDB(t)
+	defer done()
+	tree := createTree(t, db, testonly.MapTree)
+	s := NewMapStorage(db)
+	initMap(t, s, tree)
+
+	runMapTX(t, s, tree, func(ctx context.Context, tx storage.MapTreeTX) error {
+		runMapTX(t, s, tree, func(ctx context.Context, tx storage.MapTreeTX) error {
+			return tx.Set(ctx, hash("inner"), trillian.MapLeaf{LeafValue: []byte("inner")})
+		})
+		if err := tx.Set(ctx, hash("outer"), trillian.MapLeaf{LeafValue: []byte("outer")}); err != nil {
+			t.Fatalf("Set(): %v", err)
+		}
+		return tx.StoreSignedMapRoot(ctx, mapRoot(t, 1))
+	})
+
+	tx, err := s.SnapshotForTree(ctx, tree)
+	if err != nil {
+		t.Fatalf("SnapshotForTree(): %v", err)
+	}
+	defer tx.Close()
+	got, err := tx.Get(ctx, 1, [][]byte{hash("inner"), hash("outer")})
+	if err != nil || len(got) != 2 {
+		t.Errorf("Get() = (%v, %v), want 2 leaves", got, err)
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/trillian"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/testonly"
+	"github.com/google/trillian/types"
+
+	bbolt "go.etcd.io/bbolt"
+
+	_ "github.com/google/trillian/crypto/keys/der/proto" // Register PrivateKey handler.
+)
+
+// openTestDB opens a new database in a temporary directory. The returned
+// function closes it and removes the directory.
+func openTestDB(t *testing.T) (*bbolt.DB, string, func()) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "bolt")
+	if err != nil {
+		t.Fatalf("TempDir(): %v", err)
+	}
+	path := filepath.Join(dir, "trillian.db")
+	db, err := OpenDB(path)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("OpenDB(): %v", err)
+	}
+	return db, path, func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func createTree(t *testing.T, db *bbolt.DB, tree *trillian.Tree) *trillian.Tree {
+	t.Helper()
+	tree, err := storage.CreateTree(context.Background(), NewAdminStorage(db), tree)
+	if err != nil {
+		t.Fatalf("CreateTree(): %v", err)
+	}
+	return tree
+}
+
+func runLogTX(t *testing.T, s storage.LogStorage, tree *trillian.Tree, f storage.LogTXFunc) {
+	t.Helper()
+	if err := s.ReadWriteTransaction(context.Background(), tree, f); err != nil {
+		t.Fatalf("ReadWriteTransaction(): %v", err)
+	}
+}
+
+func logRoot(t *testing.T, root *types.LogRootV1) trillian.SignedLogRoot {
+	t.Helper()
+	b, err := root.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary(): %v", err)
+	}
+	return trillian.SignedLogRoot{LogRoot: b, LogRootSignature: []byte("notempty")}
+}
+
+// initLog stores the initial root of the log, at revision 0.
+func initLog(t *testing.T, s storage.LogStorage, tree *trillian.Tree) {
+	t.Helper()
+	runLogTX(t, s, tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		return tx.StoreSignedLogRoot(ctx, logRoot(t, &types.LogRootV1{TimestampNanos: 1}))
+	})
+}
+
+func hash(s string) []byte {
+	h := sha256.Sum256([]byte(s))
+	return h[:]
+}
+
+func createLeaves(n int) []*trillian.LogLeaf {
+	leaves := make([]*trillian.LogLeaf, 0, n)
+	for i := 0; i < n; i++ {
+		data := fmt.Sprintf("leaf %d", i)
+		leaves = append(leaves, &trillian.LogLeaf{
+			LeafIdentityHash: hash("id " + data),
+			MerkleLeafHash:   hash(data),
+			LeafValue:        []byte(data),
+			ExtraData:        []byte("extra " + data),
+		})
+	}
+	return leaves
+}
+
+func TestNodeRoundTrip(t *testing.T) {
+	db, _, done := openTestDB(t)
+	defer done()
+	tree := createTree(t, db, testonly.LogTree)
+	s := NewLogStorage(db, nil)
+	initLog(t, s, tree)
+
+	var nodes []storage.Node
+	for i := int64(0); i < 300; i++ {
+		id, err := storage.NewNodeIDForTreeCoords(0, i, 64)
+		if err != nil {
+			t.Fatalf("NewNodeIDForTreeCoords(): %v", err)
+		}
+		nodes = append(nodes, storage.Node{NodeID: id, Hash: hash(fmt.Sprint(i)), NodeRevision: 1})
+	}
+	ids := make([]storage.NodeID, len(nodes))
+	for i, n := range nodes {
+		ids[i] = n.NodeID
+	}
+
+	runLogTX(t, s, tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		// Need to read nodes before attempting to write.
+		if _, err := tx.GetMerkleNodes(ctx, 0, ids); err != nil {
+			t.Fatalf("GetMerkleNodes(): %v", err)
+		}
+		return tx.SetMerkleNodes(ctx, nodes)
+	})
+
+	runLogTX(t, s, tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		got, err := tx.GetMerkleNodes(ctx, 1, ids)
+		if err != nil {
+			t.Fatalf("GetMerkleNodes(): %v", err)
+		}
+		if len(got) != len(nodes) {
+			t.Fatalf("GetMerkleNodes() returned %d nodes, want %d", len(got), len(nodes))
+		}
+		for i := range got {
+			if !got[i].NodeID.Equivalent(nodes[i].NodeID) || !bytes.Equal(got[i].Hash, nodes[i].Hash) {
+				t.Errorf("node %d: got %v, want %v", i, got[i], nodes[i])
+			}
+		}
+		return nil
+	})
+
+	// The nodes didn't exist at the previous revision.
+	runLogTX(t, s, tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		if got, err := tx.GetMerkleNodes(ctx, 0, ids); err != nil || len(got) != 0 {
+			t.Errorf("GetMerkleNodes(0) = (%v, %v), want no nodes", got, err)
+		}
+		return nil
+	})
+}
+
+// TestReopen checks that the data committed to the database survives closing
+// and reopening it.
+func TestReopen(t *testing.T) {
+	ctx := context.Background()
+	db, path, done := openTestDB(t)
+	defer done()
+	tree := createTree(t, db, testonly.LogTree)
+	s := NewLogStorage(db, nil)
+	initLog(t, s, tree)
+	if _, err := s.QueueLeaves(ctx, tree, createLeaves(3), time.Now()); err != nil {
+		t.Fatalf("QueueLeaves(): %v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("Close(): %v", err)
+	}
+
+	db, err := OpenDB(path)
+	if err != nil {
+		t.Fatalf("OpenDB(): %v", err)
+	}
+	defer db.Close()
+	if _, err := storage.GetTree(ctx, NewAdminStorage(db), tree.TreeId); err != nil {
+		t.Errorf("GetTree() after reopening: %v", err)
+	}
+	tx, err := NewLogStorage(db, nil).Snapshot(ctx)
+	if err != nil {
+		t.Fatalf("Snapshot(): %v", err)
+	}
+	defer tx.Close()
+	counts, err := tx.GetUnsequencedCounts(ctx)
+	if err != nil {
+		t.Fatalf("GetUnsequencedCounts(): %v", err)
+	}
+	if got, want := counts[tree.TreeId], int64(3); got != want {
+		t.Errorf("GetUnsequencedCounts()[%d] = %d, want %d", tree.TreeId, got, want)
+	}
+}
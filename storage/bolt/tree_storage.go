@@ -0,0 +1,391 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/cache"
+	"github.com/google/trillian/storage/storagepb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	bbolt "go.etcd.io/bbolt"
+)
+
+// Names of the top-level buckets.
+var (
+	treesBucket    = []byte("Trees")
+	treeDataBucket = []byte("TreeData")
+)
+
+// Names of the buckets nested in the bucket of each tree.
+var (
+	subtreeBucket     = []byte("Subtree")
+	leafDataBucket    = []byte("LeafData")
+	sequencedBucket   = []byte("SequencedLeafData")
+	merkleHashBucket  = []byte("MerkleLeafHash")
+	unsequencedBucket = []byte("Unsequenced")
+	treeHeadBucket    = []byte("TreeHead")
+	mapLeafBucket     = []byte("MapLeaf")
+	mapHeadBucket     = []byte("MapHead")
+)
+
+// perTreeBuckets lists the buckets created for each tree.
+var perTreeBuckets = [][]byte{subtreeBucket, leafDataBucket, sequencedBucket, merkleHashBucket, unsequencedBucket, treeHeadBucket, mapLeafBucket, mapHeadBucket}
+
+// openTimeout is how long OpenDB waits for the lock on the database file,
+// which is held by any other process that has it open.
+const openTimeout = 10 * time.Second
+
+var errReadOnlyTXWrites = status.Error(codes.Internal, "write attempted in a read-only transaction")
+
+// OpenDB opens the bbolt database stored in the file at path, creating it if
+// it doesn't exist yet.
+func OpenDB(path string) (*bbolt.DB, error) {
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("could not open bolt database %q: %v", path, err)
+	}
+	if err := db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range [][]byte{treesBucket, treeDataBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("could not initialize bolt database %q: %v", path, err)
+	}
+	return db, nil
+}
+
+// checkDatabaseAccessible returns an error if the database can't be read.
+func checkDatabaseAccessible(db *bbolt.DB) error {
+	return db.View(func(tx *bbolt.Tx) error {
+		if tx.Bucket(treesBucket) == nil {
+			return fmt.Errorf("bucket %s not found", treesBucket)
+		}
+		return nil
+	})
+}
+
+// encodeInt64 returns the big-endian encoding of i, which sorts in the same
+// order as the non-negative values of i.
+func encodeInt64(i int64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(i))
+	return b
+}
+
+// decodeInt64 is the inverse of encodeInt64.
+func decodeInt64(b []byte) int64 {
+	return int64(binary.BigEndian.Uint64(b))
+}
+
+// treeKey returns the key of the tree with the given ID in the "Trees" and
+// "TreeData" buckets.
+func treeKey(treeID int64) []byte {
+	return encodeInt64(treeID)
+}
+
+// revisionedKeyPrefix returns the prefix shared by the keys of all revisions
+// of the item with the given ID. The ID is prefixed by its length, so that the
+// IDs of different lengths have distinct key ranges.
+func revisionedKeyPrefix(id []byte) []byte {
+	ret := make([]byte, 0, 1+len(id)+8)
+	ret = append(ret, byte(len(id)))
+	return append(ret, id...)
+}
+
+// revisionedKey returns the key of the given revision of the item with the
+// given ID, e.g. a subtree or a map leaf.
+func revisionedKey(id []byte, rev int64) []byte {
+	return append(revisionedKeyPrefix(id), encodeInt64(rev)...)
+}
+
+// getAtOrBelow returns the value of the most recent revision of the item with
+// the given ID at or below rev, or nil if there is none.
+func getAtOrBelow(b *bbolt.Bucket, id []byte, rev int64) []byte {
+	prefix, key := revisionedKeyPrefix(id), revisionedKey(id, rev)
+	c := b.Cursor()
+	k, v := c.Seek(key)
+	switch {
+	case k == nil:
+		k, v = c.Last()
+	case !bytes.Equal(k, key):
+		k, v = c.Prev()
+	}
+	if k == nil || !bytes.HasPrefix(k, prefix) {
+		return nil
+	}
+	return v
+}
+
+// dataBucket returns the bucket holding the data of the given tree.
+func dataBucket(tx *bbolt.Tx, treeID int64) (*bbolt.Bucket, error) {
+	b := tx.Bucket(treeDataBucket).Bucket(treeKey(treeID))
+	if b == nil {
+		return nil, status.Errorf(codes.NotFound, "tree %v not found", treeID)
+	}
+	return b, nil
+}
+
+// createDataBucket creates the bucket holding the data of the given tree, and
+// all its nested buckets.
+func createDataBucket(tx *bbolt.Tx, treeID int64) error {
+	b, err := tx.Bucket(treeDataBucket).CreateBucket(treeKey(treeID))
+	if err != nil {
+		return err
+	}
+	for _, name := range perTreeBuckets {
+		if _, err := b.CreateBucket(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// treeTX is the state shared by log and map transactions.
+type treeTX struct {
+	// mu guards the fields below, as bbolt transactions are not safe for
+	// concurrent use.
+	mu sync.Mutex
+
+	db *bbolt.DB
+	// tx is the bbolt transaction held by the transaction, or nil if it runs
+	// a separate bbolt transaction for each access. Snapshots hold a read-only
+	// bbolt transaction, so that all their reads see the same state.
+	tx            *bbolt.Tx
+	treeID        int64
+	hashSizeBytes int
+	subtreeCache  cache.SubtreeCache
+	writeRevision int64
+	closed        bool
+}
+
+func newTreeTX(db *bbolt.DB, tx *bbolt.Tx, treeID int64, hashSizeBytes int, subtreeCache cache.SubtreeCache) treeTX {
+	return treeTX{
+		db:            db,
+		tx:            tx,
+		treeID:        treeID,
+		hashSizeBytes: hashSizeBytes,
+		subtreeCache:  subtreeCache,
+		writeRevision: -1,
+	}
+}
+
+// view calls f with the bucket holding the tree data, as seen by the
+// transaction.
+func (t *treeTX) view(f func(b *bbolt.Bucket) error) error {
+	if t.tx != nil {
+		b, err := dataBucket(t.tx, t.treeID)
+		if err != nil {
+			return err
+		}
+		return f(b)
+	}
+	return t.db.View(func(tx *bbolt.Tx) error {
+		b, err := dataBucket(tx, t.treeID)
+		if err != nil {
+			return err
+		}
+		return f(b)
+	})
+}
+
+// update calls f with the bucket holding the tree data, in the read-write
+// transaction held by the transaction.
+func (t *treeTX) update(f func(b *bbolt.Bucket) error) error {
+	if t.tx == nil || !t.tx.Writable() {
+		return errReadOnlyTXWrites
+	}
+	return t.view(f)
+}
+
+func (t *treeTX) getSubtree(ctx context.Context, treeRevision int64, nodeID storage.NodeID) (*storagepb.SubtreeProto, error) {
+	s, err := t.getSubtrees(ctx, treeRevision, []storage.NodeID{nodeID})
+	if err != nil {
+		return nil, err
+	}
+	switch len(s) {
+	case 0:
+		return nil, nil
+	case 1:
+		return s[0], nil
+	default:
+		return nil, fmt.Errorf("got %d subtrees, but expected 1", len(s))
+	}
+}
+
+func (t *treeTX) getSubtrees(ctx context.Context, treeRevision int64, nodeIDs []storage.NodeID) ([]*storagepb.SubtreeProto, error) {
+	if len(nodeIDs) == 0 {
+		return nil, nil
+	}
+	for _, nodeID := range nodeIDs {
+		if nodeID.PrefixLenBits%8 != 0 {
+			return nil, fmt.Errorf("invalid subtree ID - not multiple of 8: %d", nodeID.PrefixLenBits)
+		}
+	}
+
+	ret := make([]*storagepb.SubtreeProto, 0, len(nodeIDs))
+	err := t.view(func(b *bbolt.Bucket) error {
+		sb := b.Bucket(subtreeBucket)
+		for _, nodeID := range nodeIDs {
+			v := getAtOrBelow(sb, nodeID.Path[:nodeID.PrefixLenBits/8], treeRevision)
+			if v == nil {
+				continue
+			}
+			var subtree storagepb.SubtreeProto
+			if err := proto.Unmarshal(v, &subtree); err != nil {
+				glog.Warningf("Failed to unmarshal SubtreeProto: %s", err)
+				return err
+			}
+			if subtree.Prefix == nil {
+				subtree.Prefix = []byte{}
+			}
+			ret = append(ret, &subtree)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The InternalNodes cache is possibly nil here, but the SubtreeCache (which called
+	// this method) will re-populate it.
+	return ret, nil
+}
+
+// putSubtrees stores the passed in subtrees at the given revision.
+func putSubtrees(b *bbolt.Bucket, rev int64, subtrees []*storagepb.SubtreeProto) error {
+	sb := b.Bucket(subtreeBucket)
+	for _, s := range subtrees {
+		if s.Prefix == nil {
+			return fmt.Errorf("nil prefix on %v", s)
+		}
+		v, err := proto.Marshal(s)
+		if err != nil {
+			return err
+		}
+		if err := sb.Put(revisionedKey(s.Prefix, rev), v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getSubtreesAtRev returns a GetSubtreesFunc which reads at the passed in rev.
+func (t *treeTX) getSubtreesAtRev(ctx context.Context, rev int64) cache.GetSubtreesFunc {
+	return func(ids []storage.NodeID) ([]*storagepb.SubtreeProto, error) {
+		return t.getSubtrees(ctx, rev, ids)
+	}
+}
+
+// GetMerkleNodes returns the requests nodes at (or below) the passed in treeRevision.
+func (t *treeTX) GetMerkleNodes(ctx context.Context, treeRevision int64, nodeIDs []storage.NodeID) ([]storage.Node, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.subtreeCache.GetNodes(nodeIDs, t.getSubtreesAtRev(ctx, treeRevision))
+}
+
+func (t *treeTX) SetMerkleNodes(ctx context.Context, nodes []storage.Node) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, n := range nodes {
+		err := t.subtreeCache.SetNodeHash(n.NodeID, n.Hash,
+			func(nID storage.NodeID) (*storagepb.SubtreeProto, error) {
+				return t.getSubtree(ctx, t.writeRevision, nID)
+			})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Commit flushes the subtree cache, and commits the bbolt transaction, if the
+// transaction holds one.
+func (t *treeTX) Commit() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return fmt.Errorf("transaction already closed")
+	}
+	t.closed = true
+	if t.tx == nil {
+		return nil
+	}
+	if !t.tx.Writable() {
+		// Read-only bbolt transactions can't be committed.
+		return t.tx.Rollback()
+	}
+	if t.writeRevision > -1 {
+		if err := t.subtreeCache.Flush(func(st []*storagepb.SubtreeProto) error {
+			return t.update(func(b *bbolt.Bucket) error {
+				return putSubtrees(b, t.writeRevision, st)
+			})
+		}); err != nil {
+			glog.Warningf("TX commit flush error: %v", err)
+			t.tx.Rollback()
+			return err
+		}
+	}
+	return t.tx.Commit()
+}
+
+func (t *treeTX) Rollback() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return fmt.Errorf("transaction already closed")
+	}
+	t.closed = true
+	if t.tx == nil {
+		return nil
+	}
+	return t.tx.Rollback()
+}
+
+func (t *treeTX) Close() error {
+	t.mu.Lock()
+	closed := t.closed
+	t.mu.Unlock()
+	if !closed {
+		err := t.Rollback()
+		if err != nil {
+			glog.Warningf("Rollback error on Close(): %v", err)
+		}
+		return err
+	}
+	return nil
+}
+
+func (t *treeTX) IsOpen() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return !t.closed
+}
@@ -425,6 +425,10 @@ func newTreeInfo(tree *trillian.Tree, treeID int64, now time.Time) (*spannerpb.T
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "malformed MaxRootDuration: %v", err)
 	}
+	queueTTLMillis, err := storage.QueueTTLMillis(tree)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 
 	info := &spannerpb.TreeInfo{
 		TreeId:                treeID,
@@ -440,6 +444,8 @@ func newTreeInfo(tree *trillian.Tree, treeID int64, now time.Time) (*spannerpb.T
 		PrivateKey:            tree.GetPrivateKey(),
 		PublicKeyDer:          tree.GetPublicKey().GetDer(),
 		MaxRootDurationMillis: int64(maxRootDuration / time.Millisecond),
+		QueueTtlMillis:        queueTTLMillis,
+		MaxQueueDepth:         tree.MaxQueueDepth,
 	}
 
 	switch tree.TreeType {
@@ -526,6 +532,10 @@ func (t *adminTX) UpdateTree(ctx context.Context, treeID int64, updateFunc func(
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "malformed MaxRootDuration: %v", err)
 	}
+	queueTTLMillis, err := storage.QueueTTLMillis(tree)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
 
 	// Update (just) the mutable fields in treeInfo.
 	now := TimeNow()
@@ -534,7 +544,12 @@ func (t *adminTX) UpdateTree(ctx context.Context, treeID int64, updateFunc func(
 	info.Description = tree.Description
 	info.UpdateTimeNanos = now.UnixNano()
 	info.MaxRootDurationMillis = int64(maxRootDuration / time.Millisecond)
+	info.QueueTtlMillis = queueTTLMillis
+	info.MaxQueueDepth = tree.MaxQueueDepth
 	info.PrivateKey = tree.PrivateKey
+	info.PublicKeyDer = tree.GetPublicKey().GetDer()
+	info.KeyId = tree.KeyId
+	info.PreviousKeys = toSpannerTreeKeys(tree.PreviousKeys)
 
 	if err := t.updateTreeInfo(ctx, info); err != nil {
 		return nil, err
@@ -642,6 +657,17 @@ func (t *adminTX) UndeleteTree(ctx context.Context, treeID int64) (*trillian.Tre
 	return toTrillianTree(info)
 }
 
+func toSpannerTreeKeys(keys []*trillian.TreeKey) []*spannerpb.TreeKey {
+	var spannerKeys []*spannerpb.TreeKey
+	for _, key := range keys {
+		spannerKeys = append(spannerKeys, &spannerpb.TreeKey{
+			KeyId:        key.KeyId,
+			PublicKeyDer: key.GetPublicKey().GetDer(),
+		})
+	}
+	return spannerKeys
+}
+
 func toTrillianTree(info *spannerpb.TreeInfo) (*trillian.Tree, error) {
 	createdPB, err := ptypes.TimestampProto(time.Unix(0, info.CreateTimeNanos))
 	if err != nil {
@@ -660,6 +686,17 @@ func toTrillianTree(info *spannerpb.TreeInfo) (*trillian.Tree, error) {
 		PrivateKey:      info.PrivateKey,
 		PublicKey:       &keyspb.PublicKey{Der: info.PublicKeyDer},
 		MaxRootDuration: ptypes.DurationProto(time.Duration(info.MaxRootDurationMillis) * time.Millisecond),
+		MaxQueueDepth:   info.MaxQueueDepth,
+		KeyId:           info.KeyId,
+	}
+	for _, key := range info.PreviousKeys {
+		tree.PreviousKeys = append(tree.PreviousKeys, &trillian.TreeKey{
+			KeyId:     key.KeyId,
+			PublicKey: &keyspb.PublicKey{Der: key.PublicKeyDer},
+		})
+	}
+	if info.QueueTtlMillis > 0 {
+		tree.QueueTtl = ptypes.DurationProto(time.Duration(info.QueueTtlMillis) * time.Millisecond)
 	}
 
 	ts, ok := treeStateReverseMap[info.TreeState]
@@ -336,10 +336,16 @@ func (tx *logTX) LatestSignedLogRoot(ctx context.Context) (trillian.SignedLogRoo
 		return trillian.SignedLogRoot{}, err
 	}
 
+	// Tree heads stored before key rotation was supported have no key hint.
+	keyHint := currentSTH.KeyHint
+	if len(keyHint) == 0 {
+		keyHint = types.SerializeKeyHint(tx.treeID)
+	}
+
 	// We already read the latest root as part of starting the transaction (in
 	// order to calculate the writeRevision), so we just return that data here:
 	return trillian.SignedLogRoot{
-		KeyHint:          types.SerializeKeyHint(tx.treeID),
+		KeyHint:          keyHint,
 		LogRoot:          logRoot,
 		LogRootSignature: currentSTH.Signature,
 	}, nil
@@ -372,6 +378,7 @@ func (tx *logTX) StoreSignedLogRoot(ctx context.Context, root trillian.SignedLog
 			"RootSignature",
 			"TreeRevision",
 			"TreeMetadata",
+			"KeyHint",
 		},
 		[]interface{}{
 			int64(tx.treeID),
@@ -381,6 +388,7 @@ func (tx *logTX) StoreSignedLogRoot(ctx context.Context, root trillian.SignedLog
 			root.LogRootSignature,
 			writeRev,
 			logRoot.Metadata,
+			root.KeyHint,
 		})
 
 	stx, ok := tx.stx.(*spanner.ReadWriteTransaction)
@@ -505,6 +513,50 @@ func (tx *logTX) DequeueLeaves(ctx context.Context, limit int, cutoff time.Time)
 	return ret, nil
 }
 
+// ExpireQueuedLeaves removes up to limit leaves queued before cutoff from
+// the to-be-sequenced queue, along with their leaf data.
+func (tx *logTX) ExpireQueuedLeaves(ctx context.Context, limit int, cutoff time.Time) (int, error) {
+	stx, ok := tx.stx.(*spanner.ReadWriteTransaction)
+	if !ok {
+		return 0, ErrWrongTXType
+	}
+
+	stmt := spanner.NewStatement(`
+			SELECT Bucket, QueueTimestampNanos, MerkleLeafHash, LeafIdentityHash
+			FROM Unsequenced u
+			WHERE u.TreeID = @tree_id
+			AND u.QueueTimestampNanos < @cutoff
+			LIMIT @max_num
+			`)
+	stmt.Params["tree_id"] = tx.treeID
+	stmt.Params["cutoff"] = cutoff.UnixNano()
+	stmt.Params["max_num"] = limit
+
+	var m []*spanner.Mutation
+	rows := tx.stx.Query(ctx, stmt)
+	if err := rows.Do(func(r *spanner.Row) error {
+		var bucket, timestamp int64
+		var merkleHash, identityHash []byte
+		if err := r.Columns(&bucket, &timestamp, &merkleHash, &identityHash); err != nil {
+			return err
+		}
+		if tx.dequeued[string(identityHash)] != nil {
+			// Already being sequenced by this transaction.
+			return nil
+		}
+		m = append(m,
+			spanner.Delete(unseqTable, spanner.Key{tx.treeID, bucket, timestamp, merkleHash}),
+			spanner.Delete(leafDataTbl, spanner.Key{tx.treeID, identityHash}))
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+	if err := stx.BufferWrite(m); err != nil {
+		return 0, err
+	}
+	return len(m) / 2, nil
+}
+
 // UpdateSequencedLeaves stores the sequence numbers assigned to the leaves,
 // and integrates them into the tree.
 func (tx *logTX) UpdateSequencedLeaves(ctx context.Context, leaves []*trillian.LogLeaf) error {
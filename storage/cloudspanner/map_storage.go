@@ -337,6 +337,81 @@ func (tx *mapTX) Get(ctx context.Context, revision int64, indexes [][]byte) ([]*
 	return ret, nil
 }
 
+// ListLeaves returns up to limit of the map leaves at revision, in increasing
+// index order, starting from startIndex.
+// An error will be returned if there is a problem with the underlying
+// storage.
+func (tx *mapTX) ListLeaves(ctx context.Context, revision int64, startIndex []byte, limit int) ([]*trillian.MapLeaf, error) {
+	cols := []string{colLeafIndex, colMapRevision, colLeafHash, colLeafValue, colExtraData}
+	keys := spanner.KeyRange{
+		Start: spanner.Key{tx.treeID, startIndex},
+		End:   spanner.Key{tx.treeID},
+		Kind:  spanner.ClosedClosed,
+	}
+	ret := make([]*trillian.MapLeaf, 0, limit)
+	var lastIndex []byte
+	rows := tx.stx.Read(ctx, mapLeafDataTbl, keys, cols)
+	err := rows.Do(func(r *spanner.Row) error {
+		var rev int64
+		var leaf trillian.MapLeaf
+		if err := r.Columns(&leaf.Index, &rev, &leaf.LeafHash, &leaf.LeafValue, &leaf.ExtraData); err != nil {
+			return err
+		}
+		// Leaves are stored by index, then descending revision, so the first
+		// row of an index which satisfies this condition is good:
+		if rev > revision || (lastIndex != nil && bytes.Equal(leaf.Index, lastIndex)) {
+			return nil
+		}
+		lastIndex = leaf.Index
+		ret = append(ret, &leaf)
+		if len(ret) >= limit {
+			return errFinished
+		}
+		return nil
+	})
+	if err != nil && err != errFinished {
+		glog.Errorf("failed to read MapLeafData rows for rev %d from index %x: %v", revision, startIndex, err)
+		return nil, err
+	}
+	return ret, nil
+}
+
+// ListChangedIndexes returns up to limit of the indexes of the map leaves set
+// at revisions in (fromRevision, toRevision], in increasing order, starting
+// from startIndex.
+// An error will be returned if there is a problem with the underlying
+// storage.
+func (tx *mapTX) ListChangedIndexes(ctx context.Context, fromRevision, toRevision int64, startIndex []byte, limit int) ([][]byte, error) {
+	cols := []string{colLeafIndex, colMapRevision}
+	keys := spanner.KeyRange{
+		Start: spanner.Key{tx.treeID, startIndex},
+		End:   spanner.Key{tx.treeID},
+		Kind:  spanner.ClosedClosed,
+	}
+	var ret [][]byte
+	rows := tx.stx.Read(ctx, mapLeafDataTbl, keys, cols)
+	err := rows.Do(func(r *spanner.Row) error {
+		var index []byte
+		var rev int64
+		if err := r.Columns(&index, &rev); err != nil {
+			return err
+		}
+		if rev <= fromRevision || rev > toRevision || (len(ret) > 0 && bytes.Equal(index, ret[len(ret)-1])) {
+			return nil
+		}
+		ret = append(ret, index)
+		if len(ret) >= limit {
+			return errFinished
+		}
+		return nil
+	})
+	if err != nil && err != errFinished {
+		glog.Errorf("failed to read MapLeafData rows for revs (%d, %d] from index %x: %v", fromRevision, toRevision, startIndex, err)
+		return nil, err
+	}
+	return ret, nil
+}
+
 // GetSignedMapRoot returns the SignedMapRoot for revision.
 // An error will be returned if there is a problem with the underlying storage.
 func (tx *mapTX) GetSignedMapRoot(ctx context.Context, revision int64) (trillian.SignedMapRoot, error) {
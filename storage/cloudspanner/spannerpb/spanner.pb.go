@@ -325,10 +325,19 @@ type TreeInfo struct {
 	// If true the tree was soft deleted.
 	Deleted bool `protobuf:"varint,18,opt,name=deleted,proto3" json:"deleted,omitempty"`
 	// Time of tree deletion, if any.
-	DeleteTimeNanos      int64    `protobuf:"varint,19,opt,name=delete_time_nanos,json=deleteTimeNanos,proto3" json:"delete_time_nanos,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	DeleteTimeNanos int64 `protobuf:"varint,19,opt,name=delete_time_nanos,json=deleteTimeNanos,proto3" json:"delete_time_nanos,omitempty"`
+	// queue_ttl_millis is the maximum time a leaf may remain unsequenced before
+	// it's expired. If zero, queued leaves never expire.
+	QueueTtlMillis int64 `protobuf:"varint,20,opt,name=queue_ttl_millis,json=queueTtlMillis,proto3" json:"queue_ttl_millis,omitempty"`
+	// max_queue_depth is the maximum number of unsequenced leaves that may be
+	// queued. If zero, the queue depth is unlimited.
+	MaxQueueDepth int64 `protobuf:"varint,21,opt,name=max_queue_depth,json=maxQueueDepth,proto3" json:"max_queue_depth,omitempty"`
+	// previous_keys are the keys that were used to sign the tree before it was
+	// rotated to the key identified by key_id, oldest first.
+	PreviousKeys         []*TreeKey `protobuf:"bytes,22,rep,name=previous_keys,json=previousKeys,proto3" json:"previous_keys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
 }
 
 func (m *TreeInfo) Reset()         { *m = TreeInfo{} }
@@ -505,6 +514,27 @@ func (m *TreeInfo) GetDeleteTimeNanos() int64 {
 	return 0
 }
 
+func (m *TreeInfo) GetQueueTtlMillis() int64 {
+	if m != nil {
+		return m.QueueTtlMillis
+	}
+	return 0
+}
+
+func (m *TreeInfo) GetMaxQueueDepth() int64 {
+	if m != nil {
+		return m.MaxQueueDepth
+	}
+	return 0
+}
+
+func (m *TreeInfo) GetPreviousKeys() []*TreeKey {
+	if m != nil {
+		return m.PreviousKeys
+	}
+	return nil
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*TreeInfo) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
@@ -513,6 +543,57 @@ func (*TreeInfo) XXX_OneofWrappers() []interface{} {
 	}
 }
 
+// TreeKey is a public key that was used to sign a tree.
+// Mirrors trillian.TreeKey.
+type TreeKey struct {
+	// key_id is the version of the key.
+	KeyId int64 `protobuf:"varint,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	// public_key_der is the key in DER-encoded PKIX form.
+	PublicKeyDer         []byte   `protobuf:"bytes,2,opt,name=public_key_der,json=publicKeyDer,proto3" json:"public_key_der,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TreeKey) Reset()         { *m = TreeKey{} }
+func (m *TreeKey) String() string { return proto.CompactTextString(m) }
+func (*TreeKey) ProtoMessage()    {}
+func (*TreeKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_879d3e919e93c6ba, []int{3}
+}
+
+func (m *TreeKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TreeKey.Unmarshal(m, b)
+}
+func (m *TreeKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TreeKey.Marshal(b, m, deterministic)
+}
+func (m *TreeKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TreeKey.Merge(m, src)
+}
+func (m *TreeKey) XXX_Size() int {
+	return xxx_messageInfo_TreeKey.Size(m)
+}
+func (m *TreeKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_TreeKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TreeKey proto.InternalMessageInfo
+
+func (m *TreeKey) GetKeyId() int64 {
+	if m != nil {
+		return m.KeyId
+	}
+	return 0
+}
+
+func (m *TreeKey) GetPublicKeyDer() []byte {
+	if m != nil {
+		return m.PublicKeyDer
+	}
+	return nil
+}
+
 // TreeHead is the storage format for Trillian's commitment to a particular
 // tree state.
 type TreeHead struct {
@@ -529,8 +610,11 @@ type TreeHead struct {
 	// (not present) represented by the data in this TreeHead.
 	Signature []byte `protobuf:"bytes,10,opt,name=signature,proto3" json:"signature,omitempty"`
 	// tree_revision identifies the revision at which the TreeHead was created.
-	TreeRevision         int64    `protobuf:"varint,6,opt,name=tree_revision,json=treeRevision,proto3" json:"tree_revision,omitempty"`
-	Metadata             []byte   `protobuf:"bytes,9,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	TreeRevision int64  `protobuf:"varint,6,opt,name=tree_revision,json=treeRevision,proto3" json:"tree_revision,omitempty"`
+	Metadata     []byte `protobuf:"bytes,9,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// key_hint identifies the key that produced signature. Unset for tree heads
+	// signed before key rotation was supported.
+	KeyHint              []byte   `protobuf:"bytes,11,opt,name=key_hint,json=keyHint,proto3" json:"key_hint,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -540,7 +624,7 @@ func (m *TreeHead) Reset()         { *m = TreeHead{} }
 func (m *TreeHead) String() string { return proto.CompactTextString(m) }
 func (*TreeHead) ProtoMessage()    {}
 func (*TreeHead) Descriptor() ([]byte, []int) {
-	return fileDescriptor_879d3e919e93c6ba, []int{3}
+	return fileDescriptor_879d3e919e93c6ba, []int{4}
 }
 
 func (m *TreeHead) XXX_Unmarshal(b []byte) error {
@@ -610,6 +694,13 @@ func (m *TreeHead) GetMetadata() []byte {
 	return nil
 }
 
+func (m *TreeHead) GetKeyHint() []byte {
+	if m != nil {
+		return m.KeyHint
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterEnum("spannerpb.TreeState", TreeState_name, TreeState_value)
 	proto.RegisterEnum("spannerpb.TreeType", TreeType_name, TreeType_value)
@@ -619,71 +710,79 @@ func init() {
 	proto.RegisterType((*LogStorageConfig)(nil), "spannerpb.LogStorageConfig")
 	proto.RegisterType((*MapStorageConfig)(nil), "spannerpb.MapStorageConfig")
 	proto.RegisterType((*TreeInfo)(nil), "spannerpb.TreeInfo")
+	proto.RegisterType((*TreeKey)(nil), "spannerpb.TreeKey")
 	proto.RegisterType((*TreeHead)(nil), "spannerpb.TreeHead")
 }
 
 func init() { proto.RegisterFile("spanner.proto", fileDescriptor_879d3e919e93c6ba) }
 
 var fileDescriptor_879d3e919e93c6ba = []byte{
-	// 958 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x55, 0xed, 0x6e, 0x1a, 0x47,
-	0x14, 0xf5, 0x1a, 0x0c, 0xcb, 0x35, 0xd8, 0xe3, 0x71, 0xdc, 0xac, 0x93, 0x56, 0x42, 0x6e, 0x2b,
-	0x51, 0x54, 0x41, 0xeb, 0xc8, 0x8e, 0xa2, 0x54, 0xaa, 0xd6, 0x18, 0x07, 0x9b, 0xb0, 0x44, 0xb3,
-	0xeb, 0x56, 0xc9, 0x9f, 0xd5, 0xc0, 0x8e, 0x61, 0xe5, 0xfd, 0xea, 0xee, 0x6c, 0x14, 0xf2, 0x0c,
-	0xfd, 0xd3, 0xc7, 0xec, 0x5b, 0x54, 0x33, 0xb3, 0x60, 0x8c, 0xd5, 0x7f, 0x33, 0xe7, 0x9c, 0x7b,
-	0xaf, 0xe7, 0xfa, 0x9c, 0x05, 0x1a, 0x59, 0x42, 0xa3, 0x88, 0xa5, 0x9d, 0x24, 0x8d, 0x79, 0x8c,
-	0x6b, 0xc5, 0x35, 0x99, 0xbc, 0x38, 0x9e, 0xc5, 0xf1, 0x2c, 0x60, 0x5d, 0x49, 0x4c, 0xf2, 0xbb,
-	0x2e, 0x8d, 0x16, 0x4a, 0x75, 0x12, 0x00, 0x7a, 0x1f, 0xcf, 0x6c, 0x1e, 0xa7, 0x74, 0xc6, 0x7a,
-	0x71, 0x74, 0xe7, 0xcf, 0x70, 0x1b, 0x0e, 0xa2, 0x3c, 0x74, 0xf3, 0x28, 0x63, 0x7f, 0xb9, 0x93,
-	0x7c, 0x7a, 0xcf, 0x78, 0x66, 0x68, 0x4d, 0xad, 0x55, 0x22, 0xfb, 0x51, 0x1e, 0xde, 0x0a, 0xfc,
-	0x42, 0xc1, 0xf8, 0x67, 0xc0, 0x42, 0x1b, 0xb2, 0xf4, 0x3e, 0x60, 0x2b, 0xf1, 0xb6, 0x14, 0xa3,
-	0x28, 0x0f, 0x47, 0x92, 0x28, 0xd4, 0x27, 0x18, 0xd0, 0x88, 0x26, 0x8f, 0xa6, 0x9d, 0xfc, 0x5d,
-	0x05, 0xdd, 0x49, 0x19, 0xbb, 0x8e, 0xee, 0x62, 0xfc, 0x1c, 0xaa, 0x3c, 0x65, 0xcc, 0xf5, 0xbd,
-	0x62, 0x60, 0x45, 0x5c, 0xaf, 0x3d, 0x7c, 0x04, 0x95, 0x7b, 0xb6, 0x10, 0xb8, 0xea, 0xbd, 0x73,
-	0xcf, 0x16, 0xd7, 0x1e, 0xc6, 0x50, 0x8e, 0x68, 0xc8, 0x8c, 0x52, 0x53, 0x6b, 0xd5, 0x88, 0x3c,
-	0xe3, 0x26, 0xec, 0x7a, 0x2c, 0x9b, 0xa6, 0x7e, 0xc2, 0xfd, 0x38, 0x32, 0xca, 0x92, 0x5a, 0x87,
-	0xf0, 0x2f, 0x50, 0x93, 0x53, 0xf8, 0x22, 0x61, 0xc6, 0x4e, 0x53, 0x6b, 0xed, 0x9d, 0x1e, 0x76,
-	0x56, 0xeb, 0xea, 0x88, 0xbf, 0xc6, 0x59, 0x24, 0x8c, 0xe8, 0xbc, 0x38, 0xe1, 0x57, 0x00, 0xb2,
-	0x22, 0xe3, 0x94, 0x33, 0x43, 0x97, 0x25, 0xcf, 0x36, 0x4a, 0x6c, 0xc1, 0x11, 0xd9, 0x59, 0x1e,
-	0xf1, 0x6f, 0xd0, 0x98, 0xd3, 0x6c, 0xee, 0x66, 0x3c, 0xa5, 0x9c, 0xcd, 0x16, 0x46, 0x4d, 0xd6,
-	0x3d, 0x5f, 0xab, 0x1b, 0xd0, 0x6c, 0x6e, 0x17, 0x34, 0xa9, 0xcf, 0xd7, 0x6e, 0xf8, 0x77, 0xd8,
-	0x93, 0xd5, 0x34, 0x98, 0xc5, 0xa9, 0xcf, 0xe7, 0xa1, 0x01, 0xb2, 0xdc, 0xd8, 0x28, 0x37, 0x97,
-	0x3c, 0x91, 0xd3, 0x56, 0x57, 0x6c, 0xc1, 0x61, 0xe6, 0xcf, 0x22, 0xca, 0xf3, 0x94, 0xad, 0x75,
-	0xd9, 0x95, 0x5d, 0xbe, 0x5b, 0xeb, 0x62, 0x2f, 0x55, 0x0f, 0xad, 0x70, 0xf6, 0x04, 0x13, 0xb6,
-	0x98, 0xa6, 0x8c, 0x72, 0xe6, 0x72, 0x3f, 0x64, 0x6e, 0x44, 0xa3, 0x38, 0x33, 0x1a, 0xca, 0x16,
-	0x8a, 0x70, 0xfc, 0x90, 0x59, 0x02, 0x16, 0xda, 0x3c, 0xf1, 0x36, 0xb4, 0x7b, 0x4a, 0xab, 0x88,
-	0x07, 0xed, 0x19, 0xec, 0x26, 0xa9, 0xff, 0x59, 0x88, 0xef, 0xd9, 0xc2, 0xd8, 0x6f, 0x6a, 0xad,
-	0xdd, 0xd3, 0x67, 0x1d, 0xe5, 0xd9, 0xce, 0xd2, 0xb3, 0x1d, 0x33, 0x5a, 0x10, 0x28, 0x84, 0x43,
-	0xb6, 0xc0, 0x3f, 0xc0, 0x5e, 0x92, 0x4f, 0x02, 0x7f, 0x2a, 0xaa, 0x5c, 0x8f, 0xa5, 0x06, 0x6a,
-	0x6a, 0xad, 0x3a, 0xa9, 0x2b, 0x74, 0xc8, 0x16, 0x97, 0x2c, 0xc5, 0x43, 0xc0, 0x41, 0x3c, 0x73,
-	0x33, 0x65, 0x39, 0x77, 0x2a, 0x3d, 0x67, 0x54, 0xe4, 0x8c, 0x97, 0x6b, 0x3b, 0xd8, 0x0c, 0xc1,
-	0x60, 0x8b, 0xa0, 0x60, 0x33, 0x18, 0x43, 0xc0, 0x21, 0x4d, 0x36, 0x9b, 0x55, 0x9f, 0x34, 0xdb,
-	0xf4, 0xb8, 0x68, 0x16, 0x6e, 0x60, 0xf8, 0x35, 0x18, 0x21, 0xfd, 0xe2, 0xa6, 0x71, 0xcc, 0x5d,
-	0x2f, 0x4f, 0xa9, 0x70, 0xa6, 0x1b, 0xfa, 0x41, 0xe0, 0x67, 0xc6, 0x81, 0xdc, 0xd4, 0x51, 0x48,
-	0xbf, 0x90, 0x38, 0xe6, 0x97, 0x05, 0x3b, 0x92, 0x24, 0x36, 0xa0, 0xea, 0xb1, 0x80, 0x71, 0xe6,
-	0x19, 0xb8, 0xa9, 0xb5, 0x74, 0xb2, 0xbc, 0x8a, 0xad, 0xab, 0xe3, 0xfa, 0xd6, 0x0f, 0xd5, 0xd6,
-	0x15, 0xb1, 0xda, 0xfa, 0x05, 0x82, 0xbd, 0xc7, 0xef, 0xb8, 0x29, 0xeb, 0x75, 0xd4, 0x38, 0xf9,
-	0x57, 0x53, 0x71, 0x1c, 0x30, 0xea, 0xfd, 0x7f, 0x1c, 0x8f, 0x41, 0xe7, 0x59, 0x31, 0x40, 0x05,
-	0xb2, 0xca, 0x33, 0xf5, 0xef, 0x7c, 0x59, 0x84, 0x2b, 0xf3, 0xbf, 0xaa, 0x5c, 0x96, 0x54, 0x8e,
-	0x6c, 0xff, 0x2b, 0x13, 0xa4, 0x7c, 0xb0, 0x70, 0xaa, 0x4c, 0x66, 0x9d, 0xe8, 0x02, 0x10, 0x46,
-	0xc6, 0xdf, 0x42, 0x6d, 0x65, 0x3b, 0x69, 0xf6, 0x3a, 0x79, 0x00, 0xf0, 0xf7, 0xd0, 0x90, 0x7d,
-	0x53, 0xf6, 0xd9, 0xcf, 0x44, 0xb0, 0x2b, 0xb2, 0x77, 0x5d, 0x80, 0xa4, 0xc0, 0xf0, 0x0b, 0xd0,
-	0x43, 0xc6, 0xa9, 0x47, 0x39, 0x95, 0x69, 0xab, 0x93, 0xd5, 0xfd, 0xa6, 0xac, 0xef, 0xa0, 0xca,
-	0x4d, 0x59, 0xd7, 0x51, 0xed, 0xa6, 0xac, 0x57, 0x91, 0xde, 0x7e, 0x0b, 0xb5, 0x55, 0x70, 0xf1,
-	0x37, 0x80, 0x6f, 0xad, 0xa1, 0x35, 0xfe, 0xd3, 0x72, 0x1d, 0xd2, 0xef, 0xbb, 0xb6, 0x63, 0x3a,
-	0x7d, 0xb4, 0x85, 0x01, 0x2a, 0x66, 0xcf, 0xb9, 0xfe, 0xa3, 0x8f, 0x34, 0x71, 0xbe, 0x22, 0xe3,
-	0x4f, 0x7d, 0x0b, 0x6d, 0xb7, 0x7f, 0x52, 0x7b, 0x92, 0x9f, 0x87, 0x5d, 0xa8, 0x16, 0xb5, 0x68,
-	0x0b, 0x57, 0xa1, 0xf4, 0x7e, 0xfc, 0x0e, 0x69, 0xe2, 0x30, 0x32, 0x3f, 0xa0, 0xed, 0xf6, 0x3f,
-	0x1a, 0xd4, 0xd7, 0x93, 0x8e, 0x8f, 0xe1, 0x68, 0x39, 0x6b, 0x60, 0xda, 0x03, 0xd7, 0x76, 0x88,
-	0xe9, 0xf4, 0xdf, 0x7d, 0x44, 0x5b, 0xb8, 0x0e, 0x3a, 0xb9, 0xea, 0xb9, 0xe7, 0x6f, 0xce, 0x4f,
-	0x91, 0x86, 0x0f, 0x61, 0xdf, 0xe9, 0xdb, 0x8e, 0x3b, 0x32, 0x3f, 0x48, 0x65, 0x9f, 0xa0, 0x6d,
-	0x51, 0x3d, 0xbe, 0xb8, 0xe9, 0xf7, 0x1c, 0x97, 0x5c, 0xf5, 0x84, 0xd0, 0xb5, 0x07, 0xe6, 0xe9,
-	0xd9, 0x39, 0x2a, 0xe1, 0x23, 0x38, 0xe8, 0x8d, 0xad, 0xeb, 0xa1, 0x2d, 0xa0, 0xb3, 0x5f, 0x4f,
-	0x5d, 0x01, 0x97, 0xf1, 0x01, 0x34, 0x1e, 0x60, 0x01, 0xed, 0xb4, 0x7f, 0x84, 0xc6, 0xa3, 0xaf,
-	0x07, 0xd6, 0xa1, 0x6c, 0x8d, 0xad, 0xe2, 0xc5, 0x85, 0xac, 0xdc, 0x7e, 0x0d, 0xf8, 0xe9, 0xe7,
-	0x01, 0x37, 0xa0, 0x66, 0x5a, 0x63, 0xeb, 0xe3, 0x68, 0x7c, 0x6b, 0xab, 0x17, 0x13, 0xdb, 0x44,
-	0x1a, 0xae, 0xc1, 0x4e, 0xbf, 0x77, 0x69, 0x9b, 0xa8, 0x74, 0xf1, 0xf6, 0xd3, 0x9b, 0x99, 0xcf,
-	0xe7, 0xf9, 0xa4, 0x33, 0x8d, 0xc3, 0x6e, 0xf1, 0x03, 0xc4, 0x53, 0x61, 0x61, 0x1a, 0x75, 0x0b,
-	0xeb, 0x75, 0xa7, 0x41, 0x9c, 0x7b, 0x45, 0x70, 0xba, 0xab, 0x00, 0x4d, 0x2a, 0x32, 0xf5, 0xaf,
-	0xfe, 0x0b, 0x00, 0x00, 0xff, 0xff, 0xba, 0x57, 0xcd, 0x18, 0xd3, 0x06, 0x00, 0x00,
+	// 1061 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x55, 0x5d, 0x6f, 0xda, 0x48,
+	0x14, 0x8d, 0x81, 0x80, 0xb9, 0x18, 0xe2, 0x4c, 0x9a, 0xd6, 0x6d, 0x77, 0x25, 0x94, 0xfd, 0x10,
+	0x8b, 0x56, 0xb0, 0x4b, 0xd5, 0x44, 0x55, 0x57, 0x5a, 0x39, 0x84, 0x94, 0x84, 0x02, 0xdd, 0xb1,
+	0xb3, 0xab, 0xf6, 0xc5, 0x1a, 0xf0, 0x04, 0x2c, 0xfc, 0x55, 0x7b, 0x5c, 0xc5, 0x7d, 0xde, 0x3f,
+	0xb0, 0x4f, 0xfb, 0x77, 0x57, 0x33, 0x36, 0x84, 0x10, 0xed, 0xdb, 0xcc, 0xb9, 0xe7, 0xde, 0xeb,
+	0xb9, 0xdc, 0x73, 0x80, 0x7a, 0x1c, 0x12, 0xdf, 0xa7, 0x51, 0x27, 0x8c, 0x02, 0x16, 0xa0, 0x6a,
+	0x7e, 0x0d, 0x67, 0x2f, 0x9e, 0x2f, 0x82, 0x60, 0xe1, 0xd2, 0xae, 0x08, 0xcc, 0x92, 0xdb, 0x2e,
+	0xf1, 0xd3, 0x8c, 0x75, 0xe2, 0x82, 0xfa, 0x3e, 0x58, 0x18, 0x2c, 0x88, 0xc8, 0x82, 0xf6, 0x03,
+	0xff, 0xd6, 0x59, 0xa0, 0x36, 0x1c, 0xfa, 0x89, 0x67, 0x25, 0x7e, 0x4c, 0x3f, 0x5b, 0xb3, 0x64,
+	0xbe, 0xa2, 0x2c, 0xd6, 0xa4, 0xa6, 0xd4, 0x2a, 0xe2, 0x03, 0x3f, 0xf1, 0x6e, 0x38, 0x7e, 0x9e,
+	0xc1, 0xe8, 0x67, 0x40, 0x9c, 0xeb, 0xd1, 0x68, 0xe5, 0xd2, 0x0d, 0xb9, 0x20, 0xc8, 0xaa, 0x9f,
+	0x78, 0x63, 0x11, 0xc8, 0xd9, 0x27, 0x08, 0xd4, 0x31, 0x09, 0x1f, 0x74, 0x3b, 0xf9, 0x57, 0x06,
+	0xd9, 0x8c, 0x28, 0xbd, 0xf2, 0x6f, 0x03, 0xf4, 0x0c, 0x2a, 0x2c, 0xa2, 0xd4, 0x72, 0xec, 0xbc,
+	0x61, 0x99, 0x5f, 0xaf, 0x6c, 0x74, 0x0c, 0xe5, 0x15, 0x4d, 0x39, 0x9e, 0xd5, 0xde, 0x5f, 0xd1,
+	0xf4, 0xca, 0x46, 0x08, 0x4a, 0x3e, 0xf1, 0xa8, 0x56, 0x6c, 0x4a, 0xad, 0x2a, 0x16, 0x67, 0xd4,
+	0x84, 0x9a, 0x4d, 0xe3, 0x79, 0xe4, 0x84, 0xcc, 0x09, 0x7c, 0xad, 0x24, 0x42, 0xdb, 0x10, 0xfa,
+	0x05, 0xaa, 0xa2, 0x0b, 0x4b, 0x43, 0xaa, 0xed, 0x37, 0xa5, 0x56, 0xa3, 0x77, 0xd4, 0xd9, 0x8c,
+	0xab, 0xc3, 0xbf, 0xc6, 0x4c, 0x43, 0x8a, 0x65, 0x96, 0x9f, 0xd0, 0x2b, 0x00, 0x91, 0x11, 0x33,
+	0xc2, 0xa8, 0x26, 0x8b, 0x94, 0x27, 0x3b, 0x29, 0x06, 0x8f, 0xe1, 0x2a, 0x5b, 0x1f, 0xd1, 0x6f,
+	0x50, 0x5f, 0x92, 0x78, 0x69, 0xc5, 0x2c, 0x22, 0x8c, 0x2e, 0x52, 0xad, 0x2a, 0xf2, 0x9e, 0x6d,
+	0xe5, 0x0d, 0x49, 0xbc, 0x34, 0xf2, 0x30, 0x56, 0x96, 0x5b, 0x37, 0xf4, 0x3b, 0x34, 0x44, 0x36,
+	0x71, 0x17, 0x41, 0xe4, 0xb0, 0xa5, 0xa7, 0x81, 0x48, 0xd7, 0x76, 0xd2, 0xf5, 0x75, 0x1c, 0xd7,
+	0x97, 0xdb, 0x57, 0x34, 0x81, 0xa3, 0xd8, 0x59, 0xf8, 0x84, 0x25, 0x11, 0xdd, 0xaa, 0x52, 0x13,
+	0x55, 0xbe, 0xdd, 0xaa, 0x62, 0xac, 0x59, 0xf7, 0xa5, 0x50, 0xfc, 0x08, 0xe3, 0x6b, 0x31, 0x8f,
+	0x28, 0x61, 0xd4, 0x62, 0x8e, 0x47, 0x2d, 0x9f, 0xf8, 0x41, 0xac, 0xd5, 0xb3, 0xb5, 0xc8, 0x02,
+	0xa6, 0xe3, 0xd1, 0x09, 0x87, 0x39, 0x37, 0x09, 0xed, 0x1d, 0x6e, 0x23, 0xe3, 0x66, 0x81, 0x7b,
+	0xee, 0x6b, 0xa8, 0x85, 0x91, 0xf3, 0x85, 0x93, 0x57, 0x34, 0xd5, 0x0e, 0x9a, 0x52, 0xab, 0xd6,
+	0x7b, 0xd2, 0xc9, 0x76, 0xb6, 0xb3, 0xde, 0xd9, 0x8e, 0xee, 0xa7, 0x18, 0x72, 0xe2, 0x88, 0xa6,
+	0xe8, 0x7b, 0x68, 0x84, 0xc9, 0xcc, 0x75, 0xe6, 0x3c, 0xcb, 0xb2, 0x69, 0xa4, 0xa9, 0x4d, 0xa9,
+	0xa5, 0x60, 0x25, 0x43, 0x47, 0x34, 0xbd, 0xa0, 0x11, 0x1a, 0x01, 0x72, 0x83, 0x85, 0x15, 0x67,
+	0x2b, 0x67, 0xcd, 0xc5, 0xce, 0x69, 0x65, 0xd1, 0xe3, 0xe5, 0xd6, 0x0c, 0x76, 0x45, 0x30, 0xdc,
+	0xc3, 0xaa, 0xbb, 0x83, 0xf1, 0x62, 0x1e, 0x09, 0x77, 0x8b, 0x55, 0x1e, 0x15, 0xdb, 0xdd, 0x71,
+	0x5e, 0xcc, 0xdb, 0xc1, 0xd0, 0x19, 0x68, 0x1e, 0xb9, 0xb3, 0xa2, 0x20, 0x60, 0x96, 0x9d, 0x44,
+	0x84, 0x6f, 0xa6, 0xe5, 0x39, 0xae, 0xeb, 0xc4, 0xda, 0xa1, 0x98, 0xd4, 0xb1, 0x47, 0xee, 0x70,
+	0x10, 0xb0, 0x8b, 0x3c, 0x3a, 0x16, 0x41, 0xa4, 0x41, 0xc5, 0xa6, 0x2e, 0x65, 0xd4, 0xd6, 0x50,
+	0x53, 0x6a, 0xc9, 0x78, 0x7d, 0xe5, 0x53, 0xcf, 0x8e, 0xdb, 0x53, 0x3f, 0xca, 0xa6, 0x9e, 0x05,
+	0xee, 0xa7, 0xde, 0x02, 0xf5, 0x73, 0x42, 0x13, 0x6a, 0x31, 0xe6, 0xae, 0xdb, 0x3e, 0x11, 0xd4,
+	0x86, 0xc0, 0x4d, 0xe6, 0xe6, 0xfd, 0x7e, 0x84, 0x03, 0xfe, 0xa1, 0x19, 0xdb, 0xa6, 0x21, 0x5b,
+	0x6a, 0xc7, 0x82, 0x58, 0xf7, 0xc8, 0xdd, 0x1f, 0x1c, 0xbd, 0xe0, 0x20, 0x3a, 0x83, 0x7a, 0x18,
+	0xd1, 0x2f, 0x4e, 0x90, 0xc4, 0xfc, 0x27, 0x89, 0xb5, 0xa7, 0xcd, 0x62, 0xab, 0xd6, 0x43, 0x3b,
+	0x32, 0x19, 0xd1, 0x14, 0x2b, 0x6b, 0xe2, 0x88, 0xa6, 0xf1, 0xb9, 0x0a, 0x8d, 0x87, 0x23, 0xbd,
+	0x2e, 0xc9, 0x8a, 0x5a, 0x3f, 0xb9, 0x84, 0x4a, 0x9e, 0xb0, 0x25, 0x7f, 0x69, 0x5b, 0xfe, 0x8f,
+	0x77, 0xa0, 0xf0, 0x78, 0x07, 0x4e, 0xfe, 0x2e, 0x64, 0x0e, 0x33, 0xa4, 0xc4, 0xfe, 0x7f, 0x87,
+	0x79, 0x0e, 0x32, 0x8b, 0xf3, 0x99, 0x65, 0x1e, 0x53, 0x61, 0x71, 0x36, 0xab, 0x97, 0xb9, 0x5f,
+	0xc4, 0xce, 0xd7, 0xcc, 0x6a, 0x8a, 0x99, 0x35, 0x18, 0xce, 0x57, 0xca, 0x83, 0xe2, 0x37, 0xe4,
+	0xe2, 0x13, 0x66, 0xa3, 0x60, 0x99, 0x03, 0x5c, 0x9b, 0xe8, 0x1b, 0xa8, 0x6e, 0x94, 0x24, 0xf4,
+	0xab, 0xe0, 0x7b, 0x00, 0x7d, 0x07, 0x75, 0x51, 0x97, 0x0f, 0x23, 0xe6, 0x5e, 0x55, 0x16, 0xb5,
+	0x15, 0x0e, 0xe2, 0x1c, 0x43, 0x2f, 0x40, 0xf6, 0x28, 0x23, 0x36, 0x61, 0x44, 0x18, 0x88, 0x82,
+	0x37, 0x77, 0xfe, 0xcd, 0xfc, 0xe1, 0x4b, 0xc7, 0x67, 0x42, 0xd7, 0x0a, 0xae, 0xac, 0x68, 0x3a,
+	0x74, 0x7c, 0x76, 0x5d, 0x92, 0xf7, 0xd5, 0xf2, 0x75, 0x49, 0x96, 0xd5, 0xea, 0x75, 0x49, 0xae,
+	0xa8, 0x72, 0xfb, 0x2d, 0x54, 0x37, 0x36, 0x85, 0x9e, 0x02, 0xba, 0x99, 0x8c, 0x26, 0xd3, 0xbf,
+	0x26, 0x96, 0x89, 0x07, 0x03, 0xcb, 0x30, 0x75, 0x73, 0xa0, 0xee, 0x21, 0x80, 0xb2, 0xde, 0x37,
+	0xaf, 0xfe, 0x1c, 0xa8, 0x12, 0x3f, 0x5f, 0xe2, 0xe9, 0xa7, 0xc1, 0x44, 0x2d, 0xb4, 0x7f, 0xca,
+	0x46, 0x28, 0xcc, 0xb0, 0x06, 0x95, 0x3c, 0x57, 0xdd, 0x43, 0x15, 0x28, 0xbe, 0x9f, 0xbe, 0x53,
+	0x25, 0x7e, 0x18, 0xeb, 0x1f, 0xd4, 0x42, 0xfb, 0x1f, 0x09, 0x94, 0x6d, 0x5f, 0x43, 0xcf, 0xe1,
+	0x78, 0xdd, 0x6b, 0xa8, 0x1b, 0x43, 0xcb, 0x30, 0xb1, 0x6e, 0x0e, 0xde, 0x7d, 0x54, 0xf7, 0x90,
+	0x02, 0x32, 0xbe, 0xec, 0x5b, 0xa7, 0x6f, 0x4e, 0x7b, 0xaa, 0x84, 0x8e, 0xe0, 0xc0, 0x1c, 0x18,
+	0xa6, 0x35, 0xd6, 0x3f, 0x08, 0xe6, 0x00, 0xab, 0x05, 0x9e, 0x3d, 0x3d, 0xbf, 0x1e, 0xf4, 0x4d,
+	0x0b, 0x5f, 0xf6, 0x39, 0xd1, 0x32, 0x86, 0x7a, 0xef, 0xf5, 0xa9, 0x5a, 0x44, 0xc7, 0x70, 0xd8,
+	0x9f, 0x4e, 0xae, 0x46, 0x06, 0x87, 0x5e, 0xff, 0xda, 0xb3, 0x38, 0x5c, 0x42, 0x87, 0x50, 0xbf,
+	0x87, 0x39, 0xb4, 0xdf, 0xfe, 0x01, 0xea, 0x0f, 0xbc, 0x12, 0xc9, 0x50, 0x9a, 0x4c, 0x27, 0xf9,
+	0x8b, 0x73, 0x5a, 0xa9, 0x7d, 0x06, 0xe8, 0xb1, 0x19, 0xa2, 0x3a, 0x54, 0xf5, 0xc9, 0x74, 0xf2,
+	0x71, 0x3c, 0xbd, 0x31, 0xb2, 0x17, 0x63, 0x43, 0x57, 0x25, 0x54, 0x85, 0xfd, 0x41, 0xff, 0xc2,
+	0xd0, 0xd5, 0xe2, 0xf9, 0xdb, 0x4f, 0x6f, 0x16, 0x0e, 0x5b, 0x26, 0xb3, 0xce, 0x3c, 0xf0, 0xba,
+	0xf9, 0xdf, 0x2d, 0x8b, 0xb8, 0x80, 0x88, 0xdf, 0xcd, 0xb7, 0xbb, 0x3b, 0x77, 0x83, 0xc4, 0xce,
+	0xd5, 0xd0, 0xdd, 0xa8, 0x62, 0x56, 0x16, 0x1e, 0xf7, 0xea, 0xbf, 0x01, 0x00, 0xb2, 0xe5, 0x98,
+	0x78, 0xc1, 0x07, 0x00, 0x00,
 }
@@ -0,0 +1,86 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cloudspanner
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"testing"
+
+	"cloud.google.com/go/spanner"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/testonly"
+)
+
+// The tests in this file delete all the data in the database they run
+// against, so it must be one used only for testing.
+var testDatabase = flag.String("test_cloud_spanner_database", "", "Cloud Spanner database to run the storage tests against, e.g. projects/my-project/instances/my-instance/databases/my-db. The tests are skipped if it's empty.")
+
+// allTables lists the tables of spanner.sdl.
+var allTables = []string{
+	"TreeRoots",
+	"TreeHeads",
+	"SubtreeData",
+	"LeafData",
+	"SequencedLeafData",
+	"Unsequenced",
+	"MapLeafData",
+}
+
+// openTestDBOrSkip returns a client for the test database, or skips the test
+// if none is configured.
+func openTestDBOrSkip(t *testing.T) *spanner.Client {
+	t.Helper()
+	if *testDatabase == "" {
+		t.Skip("Skipping test as --test_cloud_spanner_database is not set")
+	}
+	client, err := spanner.NewClient(context.Background(), *testDatabase)
+	if err != nil {
+		t.Fatalf("spanner.NewClient(%q): %v", *testDatabase, err)
+	}
+	return client
+}
+
+// cleanTestDB deletes all the rows of the test database.
+func cleanTestDB(client *spanner.Client) {
+	muts := make([]*spanner.Mutation, 0, len(allTables))
+	for _, table := range allTables {
+		muts = append(muts, spanner.Delete(table, spanner.AllKeys()))
+	}
+	if _, err := client.Apply(context.Background(), muts); err != nil {
+		panic(fmt.Sprintf("Failed to clean the test database: %v", err))
+	}
+}
+
+func TestCloudSpannerLogStorage(t *testing.T) {
+	client := openTestDBOrSkip(t)
+	defer client.Close()
+	tester := &testonly.LogStorageTester{NewStorage: func() (storage.LogStorage, storage.AdminStorage) {
+		cleanTestDB(client)
+		return NewLogStorage(client), NewAdminStorage(client)
+	}}
+	tester.RunAllTests(t)
+}
+
+func TestCloudSpannerMapStorage(t *testing.T) {
+	client := openTestDBOrSkip(t)
+	defer client.Close()
+	tester := &testonly.MapStorageTester{NewStorage: func() (storage.MapStorage, storage.AdminStorage) {
+		cleanTestDB(client)
+		return NewMapStorage(context.Background(), client), NewAdminStorage(client)
+	}}
+	tester.RunAllTests(t)
+}
@@ -0,0 +1,227 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commitlog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/trillian"
+	"github.com/google/trillian/merkle/compact"
+	"github.com/google/trillian/merkle/hashers"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/types"
+)
+
+const (
+	// maxTreeDepth sets an upper limit on the size of Log trees.
+	maxTreeDepth = 64
+	// leafReadBatch is the number of leaves read from the commit log at once
+	// while catching up.
+	leafReadBatch = 1000
+)
+
+// ErrDiverged is returned when the latest root in the local database is not
+// the root with the same revision in the commit log. The local database must
+// then be rebuilt from the commit log.
+var ErrDiverged = errors.New("commitlog: local database has diverged from the commit log")
+
+// CatchUp brings the local database up to date with the roots of tree in the
+// commit log, one root per transaction. For each root, the leaves it covers
+// are copied from the commit log, and the Merkle tree nodes are recomputed
+// and checked against the root hash.
+//
+// Serving replicas call CatchUp to follow the master sequencer. Calling it
+// with an empty local database rebuilds it from the commit log.
+func (s *LogStorage) CatchUp(ctx context.Context, tree *trillian.Tree) error {
+	for {
+		applied := false
+		err := s.local.ReadWriteTransaction(ctx, tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+			var err error
+			applied, err = s.applyNextRoot(ctx, tree, tx)
+			return err
+		})
+		if err != nil || !applied {
+			return err
+		}
+	}
+}
+
+// applyNextRoot stores the root following the latest root in the local
+// database, if there is one in the commit log, and reports whether it did.
+func (s *LogStorage) applyNextRoot(ctx context.Context, tree *trillian.Tree, tx storage.LogTreeTX) (bool, error) {
+	ri := s.rootIndex(tree.TreeId)
+	current, err := tx.LatestSignedLogRoot(ctx)
+	if err != nil && err != storage.ErrTreeNeedsInit {
+		return false, err
+	}
+
+	if len(current.LogRoot) == 0 {
+		// The local database is empty, so start with the initial root.
+		slr, root, err := ri.get(ctx, s.cl, 0)
+		if err != nil || slr == nil {
+			return false, err
+		}
+		if root.TreeSize != 0 {
+			return false, fmt.Errorf("%d: initial root has tree size %d", tree.TreeId, root.TreeSize)
+		}
+		return true, tx.StoreSignedLogRoot(ctx, *slr)
+	}
+
+	var localRoot types.LogRootV1
+	if err := localRoot.UnmarshalBinary(current.LogRoot); err != nil {
+		return false, err
+	}
+	slr, _, err := ri.get(ctx, s.cl, int64(localRoot.Revision))
+	if err != nil {
+		return false, err
+	}
+	if slr == nil || !bytes.Equal(slr.LogRoot, current.LogRoot) {
+		glog.Errorf("%d: local root for revision %d is not in the commit log", tree.TreeId, localRoot.Revision)
+		return false, ErrDiverged
+	}
+
+	slr, root, err := ri.get(ctx, s.cl, int64(localRoot.Revision)+1)
+	if err != nil || slr == nil {
+		return false, err
+	}
+	if root.TreeSize < localRoot.TreeSize {
+		return false, fmt.Errorf("%d: root for revision %d has tree size %d, smaller than %d", tree.TreeId, root.Revision, root.TreeSize, localRoot.TreeSize)
+	}
+	if rev, err := tx.WriteRevision(ctx); err != nil {
+		return false, err
+	} else if rev != int64(root.Revision) {
+		return false, fmt.Errorf("%d: got write revision %d, want %d", tree.TreeId, rev, root.Revision)
+	}
+
+	hasher, err := hashers.NewLogHasher(tree.HashStrategy)
+	if err != nil {
+		return false, err
+	}
+	cr, err := initCompactRange(ctx, hasher, &localRoot, tx)
+	if err != nil {
+		return false, fmt.Errorf("%d: compact range init failed: %v", tree.TreeId, err)
+	}
+	integrateAt, err := ptypes.TimestampProto(time.Unix(0, int64(root.TimestampNanos)))
+	if err != nil {
+		return false, err
+	}
+	nodeMap := make(map[compact.NodeID][]byte)
+	store := func(id compact.NodeID, hash []byte) { nodeMap[id] = hash }
+	for size := localRoot.TreeSize; size < root.TreeSize; {
+		count := root.TreeSize - size
+		if count > leafReadBatch {
+			count = leafReadBatch
+		}
+		leaves, err := s.readLeaves(ctx, tree.TreeId, int64(size), int(count))
+		if err != nil {
+			return false, err
+		}
+		if len(leaves) == 0 {
+			return false, fmt.Errorf("%d: root for revision %d has tree size %d, but the commit log has %d leaves", tree.TreeId, root.Revision, root.TreeSize, size)
+		}
+		for _, leaf := range leaves {
+			leaf.IntegrateTimestamp = integrateAt
+			store(compact.NewNodeID(0, uint64(leaf.LeafIndex)), leaf.MerkleLeafHash)
+			if err := cr.Append(leaf.MerkleLeafHash, store); err != nil {
+				return false, err
+			}
+		}
+		if err := queueLocally(ctx, tx, leaves); err != nil {
+			return false, err
+		}
+		if err := tx.UpdateSequencedLeaves(ctx, leaves); err != nil {
+			return false, err
+		}
+		size += uint64(len(leaves))
+	}
+
+	hash, err := cr.GetRootHash(store)
+	if err != nil {
+		return false, err
+	}
+	if cr.End() == 0 {
+		hash = hasher.EmptyRoot()
+	}
+	if !bytes.Equal(hash, root.RootHash) {
+		return false, fmt.Errorf("%d: root hash mismatch at revision %d: leaves give %x, root has %x", tree.TreeId, root.Revision, hash, root.RootHash)
+	}
+	nodes := make([]storage.Node, 0, len(nodeMap))
+	for id, hash := range nodeMap {
+		nodeID, err := storage.NewNodeIDForTreeCoords(int64(id.Level), int64(id.Index), maxTreeDepth)
+		if err != nil {
+			return false, err
+		}
+		nodes = append(nodes, storage.Node{NodeID: nodeID, Hash: hash, NodeRevision: int64(root.Revision)})
+	}
+	if err := tx.SetMerkleNodes(ctx, nodes); err != nil {
+		return false, err
+	}
+	if err := tx.StoreSignedLogRoot(ctx, *slr); err != nil {
+		return false, err
+	}
+	glog.V(1).Infof("%d: caught up with root for revision %d, size %d", tree.TreeId, root.Revision, root.TreeSize)
+	return true, nil
+}
+
+// initCompactRange builds a compact range from the nodes in the local
+// database, which must match root.
+func initCompactRange(ctx context.Context, hasher hashers.LogHasher, root *types.LogRootV1, tx storage.TreeTX) (*compact.Range, error) {
+	fact := compact.RangeFactory{Hash: hasher.HashChildren}
+	if root.TreeSize == 0 {
+		return fact.NewEmptyRange(0), nil
+	}
+
+	ids := compact.RangeNodesForPrefix(root.TreeSize)
+	storIDs := make([]storage.NodeID, len(ids))
+	for i, id := range ids {
+		nodeID, err := storage.NewNodeIDForTreeCoords(int64(id.Level), int64(id.Index), maxTreeDepth)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create nodeID: %v", err)
+		}
+		storIDs[i] = nodeID
+	}
+	nodes, err := tx.GetMerkleNodes(ctx, int64(root.Revision), storIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Merkle nodes: %v", err)
+	}
+	if got, want := len(nodes), len(storIDs); got != want {
+		return nil, fmt.Errorf("failed to get %d nodes at rev %d, got %d", want, root.Revision, got)
+	}
+	hashes := make([][]byte, len(nodes))
+	for i, node := range nodes {
+		if !node.NodeID.Equivalent(storIDs[i]) {
+			return nil, fmt.Errorf("node ID mismatch at %d", i)
+		}
+		hashes[i] = node.Hash
+	}
+	cr, err := fact.NewRange(0, root.TreeSize, hashes)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := cr.GetRootHash(nil)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(hash, root.RootHash) {
+		return nil, fmt.Errorf("root hash mismatch: got %x, want %x", hash, root.RootHash)
+	}
+	return cr, nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package commitlog provides log storage which uses an ordered, append-only
+// commit log as the source of truth, as described in
+// docs/storage/commit_log/commit_log_based_storage_design.md.
+//
+// Each log has two topics in the commit log. Queued leaves are appended to the
+// leaves topic, in the order they'll be sequenced. Leaves are deduplicated by
+// their identity hash before they're appended, and as concurrent writers can
+// still append the same leaf twice, only the first entry with each identity
+// hash is valid. The sequence number of a leaf is its position among the
+// valid entries. Signed log roots are appended to the roots topic, each tagged
+// with the offset its writer expected it to be stored at. Entries which end up
+// at a different offset, because another sequencer appended a root first, are
+// ignored.
+//
+// Reads are served from a local database, which is an ordinary
+// storage.LogStorage. The local database is disposable: it can be rebuilt
+// from the commit log with LogStorage.CatchUp, which also brings a serving
+// replica up to date with the roots written by the master sequencer.
+package commitlog
+
+import (
+	"context"
+	"fmt"
+)
+
+// Log is an ordered, append-only log of entries, split into topics. Entries
+// are identified by their offset in the topic, starting at zero, and are never
+// modified once appended.
+type Log interface {
+	// Append adds entries to the end of topic, and returns the offset of the
+	// first one. The entries are appended atomically: they're either all
+	// stored at consecutive offsets, or none of them are.
+	Append(ctx context.Context, topic string, entries [][]byte) (int64, error)
+	// Read returns up to count entries of topic, starting at offset. Fewer
+	// entries are returned if the topic ends earlier.
+	Read(ctx context.Context, topic string, offset int64, count int) ([][]byte, error)
+	// Size returns the number of entries in topic.
+	Size(ctx context.Context, topic string) (int64, error)
+}
+
+// leavesTopic returns the name of the topic holding the leaves of a log.
+func leavesTopic(treeID int64) string {
+	return fmt.Sprintf("leaves-%d", treeID)
+}
+
+// rootsTopic returns the name of the topic holding the roots of a log.
+func rootsTopic(treeID int64) string {
+	return fmt.Sprintf("roots-%d", treeID)
+}
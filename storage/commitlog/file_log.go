@@ -0,0 +1,235 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commitlog
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+
+	"github.com/golang/glog"
+)
+
+const (
+	// entryHeaderSize is the size of the length prefix of each entry in a file.
+	entryHeaderSize = 4
+	// batchContinued is set in the length prefix of each entry of a batch but
+	// the last one, so that interrupted batches can be detected.
+	batchContinued = 1 << 31
+	// maxEntrySize is the largest entry which fits in a length prefix.
+	maxEntrySize = batchContinued - 1
+)
+
+var topicRE = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// FileLog is a Log which keeps each topic in a file in a directory, with
+// each entry prefixed by its 4-byte big-endian length. The top bit of the
+// length marks the entries followed by another entry of the same batch. It is
+// intended for tests and single-process deployments: the files must not be
+// written by more than one FileLog at a time.
+type FileLog struct {
+	dir string
+
+	mu     sync.Mutex // Protects topics
+	topics map[string]*fileTopic
+}
+
+// fileTopic holds an open topic file, and the positions of its entries.
+type fileTopic struct {
+	f      *os.File
+	starts []int64 // File position of each entry
+	end    int64   // File position after the last entry
+}
+
+// NewFileLog returns a FileLog which keeps its topics in dir, creating it if
+// needed.
+func NewFileLog(dir string) (*FileLog, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &FileLog{dir: dir, topics: make(map[string]*fileTopic)}, nil
+}
+
+// topic returns the open topic with the given name. If the topic does not
+// exist, it is created if create is true, and nil is returned otherwise.
+// Must be called with l.mu held.
+func (l *FileLog) topic(name string, create bool) (*fileTopic, error) {
+	if t, ok := l.topics[name]; ok {
+		return t, nil
+	}
+	if !topicRE.MatchString(name) {
+		return nil, fmt.Errorf("invalid topic name %q", name)
+	}
+	flags := os.O_RDWR
+	if create {
+		flags |= os.O_CREATE
+	}
+	f, err := os.OpenFile(filepath.Join(l.dir, name), flags, 0644)
+	if os.IsNotExist(err) && !create {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	t, err := openTopic(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("topic %q: %v", name, err)
+	}
+	l.topics[name] = t
+	return t, nil
+}
+
+// openTopic indexes the entries in f. A partially written batch at the end of
+// the file, left by an interrupted Append, is removed.
+func openTopic(f *os.File) (*fileTopic, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+	t := &fileTopic{f: f}
+	hdr := make([]byte, entryHeaderSize)
+	var batch []int64 // Starts of the entries of the batch being read
+	for pos := t.end; pos+entryHeaderSize <= size; {
+		if _, err := f.ReadAt(hdr, pos); err != nil {
+			return nil, err
+		}
+		length := binary.BigEndian.Uint32(hdr)
+		next := pos + entryHeaderSize + int64(length&maxEntrySize)
+		if next > size {
+			break
+		}
+		batch = append(batch, pos)
+		pos = next
+		if length&batchContinued == 0 {
+			t.starts = append(t.starts, batch...)
+			t.end = pos
+			batch = batch[:0]
+		}
+	}
+	if t.end < size {
+		glog.Warningf("%s: truncating partial batch at position %d", f.Name(), t.end)
+		if err := f.Truncate(t.end); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// Append adds entries to the end of topic, and returns the offset of the
+// first one. The entries are synced to disk before Append returns. If Append
+// fails, or the process dies before it returns, none of the entries are kept.
+func (l *FileLog) Append(ctx context.Context, topic string, entries [][]byte) (int64, error) {
+	size := 0
+	for _, data := range entries {
+		if len(data) > maxEntrySize {
+			return 0, fmt.Errorf("entry too large: %d bytes", len(data))
+		}
+		size += entryHeaderSize + len(data)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	t, err := l.topic(topic, true)
+	if err != nil {
+		return 0, err
+	}
+	buf := make([]byte, 0, size)
+	starts := make([]int64, 0, len(entries))
+	for i, data := range entries {
+		length := uint32(len(data))
+		if i < len(entries)-1 {
+			length |= batchContinued
+		}
+		starts = append(starts, t.end+int64(len(buf)))
+		buf = append(buf, 0, 0, 0, 0)
+		binary.BigEndian.PutUint32(buf[len(buf)-entryHeaderSize:], length)
+		buf = append(buf, data...)
+	}
+	if _, err := t.f.WriteAt(buf, t.end); err != nil {
+		return 0, t.discard(err)
+	}
+	if err := t.f.Sync(); err != nil {
+		return 0, t.discard(err)
+	}
+	offset := int64(len(t.starts))
+	t.starts = append(t.starts, starts...)
+	t.end += int64(len(buf))
+	return offset, nil
+}
+
+// discard removes anything written after the last complete batch of the
+// topic, following the failed write err.
+func (t *fileTopic) discard(err error) error {
+	if terr := t.f.Truncate(t.end); terr != nil {
+		glog.Errorf("%s: failed to truncate after write error: %v", t.f.Name(), terr)
+	}
+	return err
+}
+
+// Read returns up to count entries of topic, starting at offset.
+func (l *FileLog) Read(ctx context.Context, topic string, offset int64, count int) ([][]byte, error) {
+	if offset < 0 || count < 0 {
+		return nil, fmt.Errorf("invalid range: offset %d, count %d", offset, count)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	t, err := l.topic(topic, false)
+	if err != nil || t == nil {
+		return nil, err
+	}
+	var entries [][]byte
+	for i := offset; i < int64(len(t.starts)) && len(entries) < count; i++ {
+		end := t.end
+		if i+1 < int64(len(t.starts)) {
+			end = t.starts[i+1]
+		}
+		buf := make([]byte, end-t.starts[i])
+		if _, err := t.f.ReadAt(buf, t.starts[i]); err != nil && err != io.EOF {
+			return nil, err
+		}
+		entries = append(entries, buf[entryHeaderSize:])
+	}
+	return entries, nil
+}
+
+// Size returns the number of entries in topic.
+func (l *FileLog) Size(ctx context.Context, topic string) (int64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	t, err := l.topic(topic, false)
+	if err != nil || t == nil {
+		return 0, err
+	}
+	return int64(len(t.starts)), nil
+}
+
+// Close closes the files of all of the topics.
+func (l *FileLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var firstErr error
+	for name, t := range l.topics {
+		if err := t.f.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(l.topics, name)
+	}
+	return firstErr
+}
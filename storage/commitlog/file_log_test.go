@@ -0,0 +1,136 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commitlog
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func tempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "commitlog")
+	if err != nil {
+		t.Fatalf("TempDir(): %v", err)
+	}
+	return dir
+}
+
+func entry(i int) []byte {
+	return []byte(fmt.Sprintf("entry %d", i))
+}
+
+func TestFileLog(t *testing.T) {
+	ctx := context.Background()
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	l, err := NewFileLog(dir)
+	if err != nil {
+		t.Fatalf("NewFileLog(): %v", err)
+	}
+	if size, err := l.Size(ctx, "topic"); err != nil || size != 0 {
+		t.Errorf("Size() of missing topic: %d, %v, want 0, nil", size, err)
+	}
+	if got, err := l.Read(ctx, "topic", 0, 10); err != nil || len(got) != 0 {
+		t.Errorf("Read() of missing topic: %v, %v, want no entries", got, err)
+	}
+	if _, err := l.Append(ctx, "../topic", [][]byte{entry(0)}); err == nil {
+		t.Error("Append() with invalid topic name: got nil, want error")
+	}
+
+	var want [][]byte
+	for i := 0; i < 2; i++ {
+		offset, err := l.Append(ctx, "topic", [][]byte{entry(i)})
+		if err != nil || offset != int64(i) {
+			t.Fatalf("Append(%d): %d, %v, want %d, nil", i, offset, err, i)
+		}
+		want = append(want, entry(i))
+	}
+	batch := [][]byte{entry(2), entry(3), entry(4)}
+	if offset, err := l.Append(ctx, "topic", batch); err != nil || offset != 2 {
+		t.Fatalf("Append(batch): %d, %v, want 2, nil", offset, err)
+	}
+	want = append(want, batch...)
+	if _, err := l.Append(ctx, "other", [][]byte{{}}); err != nil {
+		t.Fatalf("Append(other): %v", err)
+	}
+
+	check := func(l *FileLog) {
+		t.Helper()
+		if size, err := l.Size(ctx, "topic"); err != nil || size != int64(len(want)) {
+			t.Errorf("Size(): %d, %v, want %d, nil", size, err, len(want))
+		}
+		for _, tc := range []struct {
+			offset int64
+			count  int
+			want   [][]byte
+		}{
+			{offset: 0, count: 100, want: want},
+			{offset: 1, count: 2, want: want[1:3]},
+			{offset: 4, count: 1, want: want[4:5]},
+			{offset: int64(len(want)), count: 2},
+			{offset: 0, count: 0},
+		} {
+			got, err := l.Read(ctx, "topic", tc.offset, tc.count)
+			if err != nil {
+				t.Errorf("Read(%d, %d): %v", tc.offset, tc.count, err)
+			} else if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Read(%d, %d): %q, want %q", tc.offset, tc.count, got, tc.want)
+			}
+		}
+		if got, err := l.Read(ctx, "other", 0, 2); err != nil || len(got) != 1 || len(got[0]) != 0 {
+			t.Errorf("Read(other): %q, %v, want one empty entry", got, err)
+		}
+	}
+	check(l)
+	if _, err := l.Read(ctx, "topic", -1, 1); err == nil {
+		t.Error("Read() at negative offset: got nil, want error")
+	}
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close(): %v", err)
+	}
+
+	// Simulate an Append interrupted part way through a batch, after writing a
+	// complete entry and part of another.
+	f, err := os.OpenFile(filepath.Join(dir, "topic"), os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatalf("OpenFile(): %v", err)
+	}
+	if _, err := f.Write([]byte{0x80, 0, 0, 1, 'x', 0, 0, 0, 10, 'x'}); err != nil {
+		t.Fatalf("Write(): %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("Close(): %v", err)
+	}
+
+	l, err = NewFileLog(dir)
+	if err != nil {
+		t.Fatalf("NewFileLog(): %v", err)
+	}
+	defer l.Close()
+	check(l)
+	offset, err := l.Append(ctx, "topic", [][]byte{entry(5)})
+	if err != nil || offset != 5 {
+		t.Fatalf("Append() after reopening: %d, %v, want 5, nil", offset, err)
+	}
+	want = append(want, entry(5))
+	check(l)
+}
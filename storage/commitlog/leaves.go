@@ -0,0 +1,169 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commitlog
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian"
+)
+
+// leafIndex tracks the valid entries in the leaves topic of a log, i.e. the
+// first ones with each leaf identity hash. The sequence number of a leaf is
+// its position among the valid entries.
+type leafIndex struct {
+	topic string
+
+	mu      sync.Mutex       // Protects everything below
+	size    int64            // Number of entries of the topic read so far
+	offsets []int64          // Offsets of the valid leaves, by sequence number
+	seqs    map[string]int64 // Sequence numbers of the valid leaves, by identity hash
+}
+
+func newLeafIndex(topic string) *leafIndex {
+	return &leafIndex{topic: topic, seqs: make(map[string]int64)}
+}
+
+// update reads the entries appended to the topic since the last update. Must
+// be called with li.mu held.
+func (li *leafIndex) update(ctx context.Context, cl Log) error {
+	for {
+		entries, err := cl.Read(ctx, li.topic, li.size, leafReadBatch)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		for _, entry := range entries {
+			offset := li.size
+			var leaf trillian.LogLeaf
+			if err := proto.Unmarshal(entry, &leaf); err != nil {
+				return fmt.Errorf("%s: corrupt leaf at offset %d: %v", li.topic, offset, err)
+			}
+			li.size++
+			id := string(leaf.LeafIdentityHash)
+			if seq, ok := li.seqs[id]; ok {
+				// Another writer appended the same leaf first.
+				glog.V(1).Infof("%s: ignoring duplicate of leaf %d at offset %d", li.topic, seq, offset)
+				continue
+			}
+			li.seqs[id] = int64(len(li.offsets))
+			li.offsets = append(li.offsets, offset)
+		}
+	}
+}
+
+// count returns the number of valid leaves in the topic.
+func (li *leafIndex) count(ctx context.Context, cl Log) (int64, error) {
+	li.mu.Lock()
+	defer li.mu.Unlock()
+	if err := li.update(ctx, cl); err != nil {
+		return 0, err
+	}
+	return int64(len(li.offsets)), nil
+}
+
+// read returns up to count valid leaves, starting with sequence number start.
+func (li *leafIndex) read(ctx context.Context, cl Log, start int64, count int) ([]*trillian.LogLeaf, error) {
+	li.mu.Lock()
+	defer li.mu.Unlock()
+	if err := li.update(ctx, cl); err != nil {
+		return nil, err
+	}
+	return li.readLocked(ctx, cl, start, count)
+}
+
+// readLocked is read, for callers which hold li.mu and have updated the index.
+func (li *leafIndex) readLocked(ctx context.Context, cl Log, start int64, count int) ([]*trillian.LogLeaf, error) {
+	end := start + int64(count)
+	if end > int64(len(li.offsets)) {
+		end = int64(len(li.offsets))
+	}
+	if start < 0 || start >= end {
+		return nil, nil
+	}
+	// Read the range of the topic holding the leaves, including any duplicates
+	// in between, and skip the duplicates.
+	first, last := li.offsets[start], li.offsets[end-1]
+	entries, err := cl.Read(ctx, li.topic, first, int(last-first+1))
+	if err != nil {
+		return nil, err
+	}
+	leaves := make([]*trillian.LogLeaf, 0, end-start)
+	for seq := start; seq < end; seq++ {
+		i := li.offsets[seq] - first
+		if i >= int64(len(entries)) {
+			return nil, fmt.Errorf("%s: leaf at offset %d disappeared", li.topic, li.offsets[seq])
+		}
+		var leaf trillian.LogLeaf
+		if err := proto.Unmarshal(entries[i], &leaf); err != nil {
+			return nil, fmt.Errorf("%s: corrupt leaf at offset %d: %v", li.topic, li.offsets[seq], err)
+		}
+		leaf.LeafIndex = seq
+		leaves = append(leaves, &leaf)
+	}
+	return leaves, nil
+}
+
+// append adds the leaves which aren't in the topic yet to it, in a single
+// batch. It returns the existing leaf for each of the leaves which were
+// already in the topic, or earlier in the batch, and nil for the others.
+func (li *leafIndex) append(ctx context.Context, cl Log, leaves []*trillian.LogLeaf) ([]*trillian.LogLeaf, error) {
+	li.mu.Lock()
+	defer li.mu.Unlock()
+	if err := li.update(ctx, cl); err != nil {
+		return nil, err
+	}
+
+	existing := make([]*trillian.LogLeaf, len(leaves))
+	inBatch := make(map[string]*trillian.LogLeaf)
+	var entries [][]byte
+	for i, leaf := range leaves {
+		id := string(leaf.LeafIdentityHash)
+		if seq, ok := li.seqs[id]; ok {
+			stored, err := li.readLocked(ctx, cl, seq, 1)
+			if err != nil {
+				return nil, err
+			}
+			if len(stored) != 1 {
+				return nil, fmt.Errorf("%s: failed to read leaf %d", li.topic, seq)
+			}
+			existing[i] = stored[0]
+			continue
+		}
+		if first, ok := inBatch[id]; ok {
+			existing[i] = first
+			continue
+		}
+		inBatch[id] = leaf
+		data, err := proto.Marshal(leaf)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, data)
+	}
+	if len(entries) == 0 {
+		return existing, nil
+	}
+	if _, err := cl.Append(ctx, li.topic, entries); err != nil {
+		return nil, fmt.Errorf("failed to append leaves to %s: %v", li.topic, err)
+	}
+	return existing, nil
+}
@@ -0,0 +1,306 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commitlog
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/trillian"
+	"github.com/google/trillian/merkle/hashers"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// LogStorage is a storage.LogStorage which sequences leaves and roots in a
+// commit log, and serves reads from a local database.
+//
+// Only LOG trees are supported. Leaves are deduplicated by their identity
+// hash in the commit log, so the local database is never asked to store a
+// duplicate leaf.
+type LogStorage struct {
+	cl    Log
+	local storage.LogStorage
+
+	mu     sync.Mutex // Protects roots and leaves
+	roots  map[int64]*rootIndex
+	leaves map[int64]*leafIndex
+}
+
+// NewLogStorage returns a LogStorage which uses cl as the source of truth,
+// and local as the local database.
+func NewLogStorage(cl Log, local storage.LogStorage) *LogStorage {
+	return &LogStorage{
+		cl:     cl,
+		local:  local,
+		roots:  make(map[int64]*rootIndex),
+		leaves: make(map[int64]*leafIndex),
+	}
+}
+
+// rootIndex returns the index of the roots topic of the given log.
+func (s *LogStorage) rootIndex(treeID int64) *rootIndex {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ri, ok := s.roots[treeID]
+	if !ok {
+		ri = &rootIndex{topic: rootsTopic(treeID)}
+		s.roots[treeID] = ri
+	}
+	return ri
+}
+
+// leafIndex returns the index of the leaves topic of the given log.
+func (s *LogStorage) leafIndex(treeID int64) *leafIndex {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	li, ok := s.leaves[treeID]
+	if !ok {
+		li = newLeafIndex(leavesTopic(treeID))
+		s.leaves[treeID] = li
+	}
+	return li
+}
+
+func checkTreeType(tree *trillian.Tree) error {
+	if tree.TreeType != trillian.TreeType_LOG {
+		return status.Errorf(codes.FailedPrecondition, "commit log storage does not support %v trees", tree.TreeType)
+	}
+	return nil
+}
+
+// CheckDatabaseAccessible checks that the local database is accessible.
+func (s *LogStorage) CheckDatabaseAccessible(ctx context.Context) error {
+	return s.local.CheckDatabaseAccessible(ctx)
+}
+
+// Snapshot starts a read-only transaction not tied to any particular tree.
+func (s *LogStorage) Snapshot(ctx context.Context) (storage.ReadOnlyLogTX, error) {
+	tx, err := s.local.Snapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &readOnlyLogTX{ReadOnlyLogTX: tx, s: s}, nil
+}
+
+// SnapshotForTree starts a read-only transaction on the local database. It
+// may be behind the commit log, until the next call to CatchUp.
+func (s *LogStorage) SnapshotForTree(ctx context.Context, tree *trillian.Tree) (storage.ReadOnlyLogTreeTX, error) {
+	return s.local.SnapshotForTree(ctx, tree)
+}
+
+// ReadWriteTransaction brings the local database up to date with the commit
+// log, and then calls f with a transaction on it. Leaves dequeued through the
+// transaction are read from the commit log, and stored roots are appended to
+// it.
+func (s *LogStorage) ReadWriteTransaction(ctx context.Context, tree *trillian.Tree, f storage.LogTXFunc) error {
+	if err := checkTreeType(tree); err != nil {
+		return err
+	}
+	if err := s.CatchUp(ctx, tree); err != nil {
+		return err
+	}
+	return s.local.ReadWriteTransaction(ctx, tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		return f(ctx, &logTreeTX{LogTreeTX: tx, s: s, tree: tree})
+	})
+}
+
+// QueueLeaves appends the leaves which are not in the commit log yet to it,
+// which assigns their sequence numbers. The others are reported as already
+// existing.
+func (s *LogStorage) QueueLeaves(ctx context.Context, tree *trillian.Tree, leaves []*trillian.LogLeaf, queueTimestamp time.Time) ([]*trillian.QueuedLogLeaf, error) {
+	if err := checkTreeType(tree); err != nil {
+		return nil, err
+	}
+	existing, err := s.appendLeaves(ctx, tree, leaves, queueTimestamp)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*trillian.QueuedLogLeaf, len(leaves))
+	for i, e := range existing {
+		if e != nil {
+			ret[i] = &trillian.QueuedLogLeaf{
+				Leaf:   e,
+				Status: status.Newf(codes.AlreadyExists, "leaf already exists: %v", e.LeafIdentityHash).Proto(),
+			}
+			continue
+		}
+		ret[i] = &trillian.QueuedLogLeaf{Leaf: leaves[i]}
+	}
+	return ret, nil
+}
+
+// AddSequencedLeaves is not supported, as only LOG trees are.
+func (s *LogStorage) AddSequencedLeaves(ctx context.Context, tree *trillian.Tree, leaves []*trillian.LogLeaf, timestamp time.Time) ([]*trillian.QueuedLogLeaf, error) {
+	return nil, status.Errorf(codes.Unimplemented, "AddSequencedLeaves is not implemented")
+}
+
+// appendLeaves appends the leaves which are not in the commit log yet to it,
+// in a single batch. It returns the existing leaf for each of the others, and
+// nil for the appended ones.
+func (s *LogStorage) appendLeaves(ctx context.Context, tree *trillian.Tree, leaves []*trillian.LogLeaf, queueTimestamp time.Time) ([]*trillian.LogLeaf, error) {
+	hasher, err := hashers.NewLogHasher(tree.HashStrategy)
+	if err != nil {
+		return nil, err
+	}
+	ts, err := ptypes.TimestampProto(queueTimestamp)
+	if err != nil {
+		return nil, fmt.Errorf("got invalid queue timestamp: %v", err)
+	}
+	// Don't accept batches if any of the leaves are invalid.
+	for _, leaf := range leaves {
+		if len(leaf.LeafIdentityHash) != hasher.Size() {
+			return nil, fmt.Errorf("queued leaf must have a leaf ID hash of length %d", hasher.Size())
+		}
+	}
+	for _, leaf := range leaves {
+		leaf.QueueTimestamp = ts
+	}
+	return s.leafIndex(tree.TreeId).append(ctx, s.cl, leaves)
+}
+
+type readOnlyLogTX struct {
+	storage.ReadOnlyLogTX
+	s *LogStorage
+}
+
+// GetUnsequencedCounts returns the number of leaves in the commit log of each
+// active log which are not covered by its latest root.
+func (t *readOnlyLogTX) GetUnsequencedCounts(ctx context.Context) (storage.CountByLogID, error) {
+	ids, err := t.GetActiveLogIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ret := make(storage.CountByLogID)
+	for _, id := range ids {
+		size, err := t.s.leafIndex(id).count(ctx, t.s.cl)
+		if err != nil {
+			return nil, err
+		}
+		_, root, err := t.s.rootIndex(id).latest(ctx, t.s.cl)
+		if err != nil {
+			return nil, err
+		}
+		if root != nil {
+			size -= int64(root.TreeSize)
+		}
+		if size > 0 {
+			ret[id] = size
+		}
+	}
+	return ret, nil
+}
+
+// logTreeTX is a transaction on the local database, which reads leaves from
+// and writes roots to the commit log.
+type logTreeTX struct {
+	storage.LogTreeTX
+	s    *LogStorage
+	tree *trillian.Tree
+}
+
+// QueueLeaves appends the leaves which are not in the commit log yet to it.
+// Note that this happens immediately, rather than when the transaction is
+// committed.
+func (t *logTreeTX) QueueLeaves(ctx context.Context, leaves []*trillian.LogLeaf, queueTimestamp time.Time) ([]*trillian.LogLeaf, error) {
+	return t.s.appendLeaves(ctx, t.tree, leaves, queueTimestamp)
+}
+
+// DequeueLeaves returns up to limit leaves from the commit log, following the
+// current tree size. As the order of the leaves is fixed, it stops at the
+// first leaf queued after cutoff.
+func (t *logTreeTX) DequeueLeaves(ctx context.Context, limit int, cutoff time.Time) ([]*trillian.LogLeaf, error) {
+	slr, err := t.LatestSignedLogRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var root types.LogRootV1
+	if err := root.UnmarshalBinary(slr.LogRoot); err != nil {
+		return nil, err
+	}
+	leaves, err := t.s.readLeaves(ctx, t.tree.TreeId, int64(root.TreeSize), limit)
+	if err != nil {
+		return nil, err
+	}
+	for i, leaf := range leaves {
+		ts, err := ptypes.Timestamp(leaf.QueueTimestamp)
+		if err != nil {
+			return nil, fmt.Errorf("got invalid queue timestamp: %v", err)
+		}
+		if ts.After(cutoff) {
+			leaves = leaves[:i]
+			break
+		}
+	}
+	if err := queueLocally(ctx, t.LogTreeTX, leaves); err != nil {
+		return nil, err
+	}
+	return leaves, nil
+}
+
+// StoreSignedLogRoot appends the root to the commit log, and then stores it
+// in the local database. It fails if another sequencer has appended a root
+// for the same revision.
+func (t *logTreeTX) StoreSignedLogRoot(ctx context.Context, slr trillian.SignedLogRoot) error {
+	var root types.LogRootV1
+	if err := root.UnmarshalBinary(slr.LogRoot); err != nil {
+		return err
+	}
+	if err := t.s.rootIndex(t.tree.TreeId).append(ctx, t.s.cl, &slr, root.Revision); err != nil {
+		return err
+	}
+	return t.LogTreeTX.StoreSignedLogRoot(ctx, slr)
+}
+
+// AddSequencedLeaves is not supported, as only LOG trees are.
+func (t *logTreeTX) AddSequencedLeaves(ctx context.Context, leaves []*trillian.LogLeaf, timestamp time.Time) ([]*trillian.QueuedLogLeaf, error) {
+	return nil, status.Errorf(codes.Unimplemented, "AddSequencedLeaves is not implemented")
+}
+
+// ExpireQueuedLeaves is not supported, as the leaves in the commit log
+// already have their sequence numbers and can't be removed from it.
+func (t *logTreeTX) ExpireQueuedLeaves(ctx context.Context, limit int, cutoff time.Time) (int, error) {
+	return 0, status.Errorf(codes.Unimplemented, "ExpireQueuedLeaves is not implemented: queue_ttl is not supported by commit log storage")
+}
+
+// readLeaves returns up to count leaves of the given log from the commit log,
+// starting at index start.
+func (s *LogStorage) readLeaves(ctx context.Context, treeID, start int64, count int) ([]*trillian.LogLeaf, error) {
+	return s.leafIndex(treeID).read(ctx, s.cl, start, count)
+}
+
+// queueLocally queues leaves read from the commit log in the local database,
+// so that they can be sequenced with UpdateSequencedLeaves.
+func queueLocally(ctx context.Context, tx storage.LogTreeTX, leaves []*trillian.LogLeaf) error {
+	for _, leaf := range leaves {
+		ts, err := ptypes.Timestamp(leaf.QueueTimestamp)
+		if err != nil {
+			return fmt.Errorf("got invalid queue timestamp: %v", err)
+		}
+		existing, err := tx.QueueLeaves(ctx, []*trillian.LogLeaf{leaf}, ts)
+		if err != nil {
+			return err
+		}
+		if len(existing) != 1 || existing[0] != nil {
+			return fmt.Errorf("local database did not queue leaf %d, as it already has a leaf with its identity hash", leaf.LeafIndex)
+		}
+	}
+	return nil
+}
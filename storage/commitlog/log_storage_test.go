@@ -0,0 +1,457 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commitlog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian"
+	"github.com/google/trillian/crypto"
+	"github.com/google/trillian/log"
+	"github.com/google/trillian/merkle/rfc6962"
+	"github.com/google/trillian/quota"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/bolt"
+	"github.com/google/trillian/storage/memory"
+	"github.com/google/trillian/trees"
+	"github.com/google/trillian/types"
+	"github.com/google/trillian/util/clock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	_ "github.com/google/trillian/crypto/keys/der/proto" // PrivateKey proto handler
+	stestonly "github.com/google/trillian/storage/testonly"
+)
+
+// testLog is a log in commit log storage.
+type testLog struct {
+	cl      *FileLog
+	storage *LogStorage
+	tree    *trillian.Tree
+	signer  *crypto.Signer
+	size    int
+}
+
+// newTestLog creates a log in a new memory local database, using the commit
+// log in dir. The log is not initialized.
+func newTestLog(ctx context.Context, t *testing.T, dir string) *testLog {
+	t.Helper()
+	ts := memory.NewTreeStorage()
+	return newTestLogWithLocal(ctx, t, dir, memory.NewAdminStorage(ts), memory.NewLogStorage(ts, nil))
+}
+
+// newTestLogWithLocal creates a log in the given local database, using the
+// commit log in dir. The log is not initialized.
+func newTestLogWithLocal(ctx context.Context, t *testing.T, dir string, admin storage.AdminStorage, local storage.LogStorage) *testLog {
+	t.Helper()
+	cl, err := NewFileLog(dir)
+	if err != nil {
+		t.Fatalf("NewFileLog(): %v", err)
+	}
+	tree, err := storage.CreateTree(ctx, admin, stestonly.LogTree)
+	if err != nil {
+		t.Fatalf("CreateTree(): %v", err)
+	}
+	signer, err := trees.Signer(ctx, tree)
+	if err != nil {
+		t.Fatalf("Signer(): %v", err)
+	}
+	return &testLog{
+		cl:      cl,
+		storage: NewLogStorage(cl, local),
+		tree:    tree,
+		signer:  signer,
+	}
+}
+
+func (l *testLog) init(ctx context.Context, t *testing.T) {
+	t.Helper()
+	root, err := l.signer.SignLogRoot(&types.LogRootV1{RootHash: rfc6962.DefaultHasher.EmptyRoot()})
+	if err != nil {
+		t.Fatalf("SignLogRoot(): %v", err)
+	}
+	if err := l.storage.ReadWriteTransaction(ctx, l.tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		return tx.StoreSignedLogRoot(ctx, *root)
+	}); err != nil {
+		t.Fatalf("StoreSignedLogRoot(): %v", err)
+	}
+}
+
+func leafValue(index int) []byte {
+	return []byte(fmt.Sprintf("leaf %d", index))
+}
+
+func testLeaf(index int) *trillian.LogLeaf {
+	value := leafValue(index)
+	hash := rfc6962.DefaultHasher.HashLeaf(value)
+	return &trillian.LogLeaf{LeafValue: value, LeafIdentityHash: hash, MerkleLeafHash: hash}
+}
+
+// queue adds leaves to the log until it has size leaves, without sequencing
+// them.
+func (l *testLog) queue(ctx context.Context, t *testing.T, size int) {
+	t.Helper()
+	var leaves []*trillian.LogLeaf
+	for i := l.size; i < size; i++ {
+		leaves = append(leaves, testLeaf(i))
+	}
+	if _, err := l.storage.QueueLeaves(ctx, l.tree, leaves, time.Now()); err != nil {
+		t.Fatalf("QueueLeaves(): %v", err)
+	}
+	l.size = size
+}
+
+// sequence integrates up to limit queued leaves, and checks that want of them
+// were integrated.
+func (l *testLog) sequence(ctx context.Context, t *testing.T, limit, want int) {
+	t.Helper()
+	seq := log.NewSequencer(rfc6962.DefaultHasher, clock.System, l.storage, l.signer, nil, quota.Noop())
+	if n, err := seq.IntegrateBatch(ctx, l.tree, limit, 0, 0); err != nil || n != want {
+		t.Fatalf("IntegrateBatch(): %d, %v, want %d leaves", n, err, want)
+	}
+}
+
+// root returns the latest root in the local database.
+func (l *testLog) root(ctx context.Context, t *testing.T) (trillian.SignedLogRoot, *types.LogRootV1) {
+	t.Helper()
+	tx, err := l.storage.SnapshotForTree(ctx, l.tree)
+	if err != nil {
+		t.Fatalf("SnapshotForTree(): %v", err)
+	}
+	defer tx.Close()
+	slr, err := tx.LatestSignedLogRoot(ctx)
+	if err != nil {
+		t.Fatalf("LatestSignedLogRoot(): %v", err)
+	}
+	var root types.LogRootV1
+	if err := root.UnmarshalBinary(slr.LogRoot); err != nil {
+		t.Fatalf("UnmarshalBinary(): %v", err)
+	}
+	return slr, &root
+}
+
+// checkLeaves checks that the local database has the first size leaves.
+func (l *testLog) checkLeaves(ctx context.Context, t *testing.T, size int) {
+	t.Helper()
+	tx, err := l.storage.SnapshotForTree(ctx, l.tree)
+	if err != nil {
+		t.Fatalf("SnapshotForTree(): %v", err)
+	}
+	defer tx.Close()
+	leaves, err := tx.GetLeavesByRange(ctx, 0, int64(size))
+	if err != nil {
+		t.Fatalf("GetLeavesByRange(): %v", err)
+	}
+	if len(leaves) != size {
+		t.Fatalf("GetLeavesByRange(): got %d leaves, want %d", len(leaves), size)
+	}
+	for i, leaf := range leaves {
+		if leaf.LeafIndex != int64(i) || !bytes.Equal(leaf.LeafValue, leafValue(i)) {
+			t.Errorf("GetLeavesByRange(): leaf %d has index %d and value %q, want value %q", i, leaf.LeafIndex, leaf.LeafValue, leafValue(i))
+		}
+	}
+}
+
+func (l *testLog) unsequenced(ctx context.Context, t *testing.T) int64 {
+	t.Helper()
+	tx, err := l.storage.Snapshot(ctx)
+	if err != nil {
+		t.Fatalf("Snapshot(): %v", err)
+	}
+	defer tx.Close()
+	counts, err := tx.GetUnsequencedCounts(ctx)
+	if err != nil {
+		t.Fatalf("GetUnsequencedCounts(): %v", err)
+	}
+	return counts[l.tree.TreeId]
+}
+
+// copyTopics copies the topics of one log to those of another, as if the
+// commit log had been restored from a backup under a different tree ID.
+func copyTopics(ctx context.Context, t *testing.T, from, to *testLog) {
+	t.Helper()
+	for _, topic := range []func(int64) string{leavesTopic, rootsTopic} {
+		entries, err := from.cl.Read(ctx, topic(from.tree.TreeId), 0, 1<<20)
+		if err != nil {
+			t.Fatalf("Read(): %v", err)
+		}
+		if _, err := to.cl.Append(ctx, topic(to.tree.TreeId), entries); err != nil {
+			t.Fatalf("Append(): %v", err)
+		}
+	}
+	to.size = from.size
+}
+
+func TestSequenceAndRebuild(t *testing.T) {
+	ctx := context.Background()
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	master := newTestLog(ctx, t, dir)
+	defer master.cl.Close()
+	master.init(ctx, t)
+	master.queue(ctx, t, 30)
+	if got, want := master.unsequenced(ctx, t), int64(30); got != want {
+		t.Errorf("GetUnsequencedCounts(): got %d, want %d", got, want)
+	}
+	master.sequence(ctx, t, 20, 20)
+	master.sequence(ctx, t, 20, 10)
+	master.sequence(ctx, t, 20, 0)
+	master.queue(ctx, t, 35)
+	master.sequence(ctx, t, 20, 5)
+	if got := master.unsequenced(ctx, t); got != 0 {
+		t.Errorf("GetUnsequencedCounts(): got %d, want 0", got)
+	}
+	masterSLR, root := master.root(ctx, t)
+	if got, want := root.TreeSize, uint64(35); got != want {
+		t.Fatalf("TreeSize: got %d, want %d", got, want)
+	}
+	master.checkLeaves(ctx, t, 35)
+
+	// Rebuild a replica from the commit log.
+	replica := newTestLog(ctx, t, filepath.Join(dir, "replica"))
+	defer replica.cl.Close()
+	copyTopics(ctx, t, master, replica)
+	if err := replica.storage.CatchUp(ctx, replica.tree); err != nil {
+		t.Fatalf("CatchUp(): %v", err)
+	}
+	replicaSLR, _ := replica.root(ctx, t)
+	if !bytes.Equal(replicaSLR.LogRoot, masterSLR.LogRoot) {
+		t.Errorf("replica has root %x, want %x", replicaSLR.LogRoot, masterSLR.LogRoot)
+	}
+	replica.checkLeaves(ctx, t, 35)
+	if err := replica.storage.CatchUp(ctx, replica.tree); err != nil {
+		t.Errorf("CatchUp() when up to date: %v", err)
+	}
+
+	// The replica can take over sequencing.
+	replica.queue(ctx, t, 40)
+	replica.sequence(ctx, t, 20, 5)
+	if _, root := replica.root(ctx, t); root.TreeSize != 40 {
+		t.Errorf("TreeSize: got %d, want 40", root.TreeSize)
+	}
+	replica.checkLeaves(ctx, t, 40)
+}
+
+// TestDuplicateLeaves checks that duplicate leaves are not sequenced, using a
+// local database which deduplicates leaves itself.
+func TestDuplicateLeaves(t *testing.T) {
+	ctx := context.Background()
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	db, err := bolt.OpenDB(filepath.Join(dir, "local.db"))
+	if err != nil {
+		t.Fatalf("OpenDB(): %v", err)
+	}
+	defer db.Close()
+	l := newTestLogWithLocal(ctx, t, filepath.Join(dir, "log"), bolt.NewAdminStorage(db), bolt.NewLogStorage(db, nil))
+	defer l.cl.Close()
+	l.init(ctx, t)
+
+	queue := func(leaves []int, wantExisting map[int]int64) {
+		t.Helper()
+		var batch []*trillian.LogLeaf
+		for _, i := range leaves {
+			batch = append(batch, testLeaf(i))
+		}
+		queued, err := l.storage.QueueLeaves(ctx, l.tree, batch, time.Now())
+		if err != nil {
+			t.Fatalf("QueueLeaves(): %v", err)
+		}
+		for i, q := range queued {
+			index, exists := wantExisting[i]
+			if got := codes.Code(q.GetStatus().GetCode()); exists != (got == codes.AlreadyExists) {
+				t.Errorf("QueueLeaves(): leaf %d has status %v, want existing: %v", i, got, exists)
+			}
+			if exists && !bytes.Equal(q.Leaf.LeafValue, leafValue(leaves[i])) {
+				t.Errorf("QueueLeaves(): leaf %d is %q, want %q", i, q.Leaf.LeafValue, leafValue(leaves[i]))
+			}
+			if exists && index >= 0 && q.Leaf.LeafIndex != index {
+				t.Errorf("QueueLeaves(): leaf %d has index %d, want %d", i, q.Leaf.LeafIndex, index)
+			}
+		}
+	}
+	// Leaf 1 is repeated in the batch, and the copy is not yet in the log.
+	queue([]int{0, 1, 2, 1, 3}, map[int]int64{3: -1})
+	queue([]int{2, 4}, map[int]int64{0: 2})
+
+	// Another writer may append a leaf which it hasn't seen in the log yet.
+	data, err := proto.Marshal(testLeaf(3))
+	if err != nil {
+		t.Fatalf("Marshal(): %v", err)
+	}
+	if _, err := l.cl.Append(ctx, leavesTopic(l.tree.TreeId), [][]byte{data}); err != nil {
+		t.Fatalf("Append(): %v", err)
+	}
+	queue([]int{5}, nil)
+	l.size = 6
+
+	if got, want := l.unsequenced(ctx, t), int64(6); got != want {
+		t.Errorf("GetUnsequencedCounts(): got %d, want %d", got, want)
+	}
+	l.sequence(ctx, t, 4, 4)
+	l.sequence(ctx, t, 4, 2)
+	l.checkLeaves(ctx, t, 6)
+
+	replica := newTestLog(ctx, t, filepath.Join(dir, "replica"))
+	defer replica.cl.Close()
+	copyTopics(ctx, t, l, replica)
+	if err := replica.storage.CatchUp(ctx, replica.tree); err != nil {
+		t.Fatalf("CatchUp(): %v", err)
+	}
+	replica.checkLeaves(ctx, t, 6)
+}
+
+func TestCatchUpEmpty(t *testing.T) {
+	ctx := context.Background()
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	l := newTestLog(ctx, t, dir)
+	defer l.cl.Close()
+	if err := l.storage.CatchUp(ctx, l.tree); err != nil {
+		t.Fatalf("CatchUp() with no roots: %v", err)
+	}
+	l.init(ctx, t)
+	l.queue(ctx, t, 3)
+	l.sequence(ctx, t, 10, 3)
+	l.checkLeaves(ctx, t, 3)
+}
+
+func TestCatchUpDiverged(t *testing.T) {
+	ctx := context.Background()
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	l := newTestLog(ctx, t, dir)
+	defer l.cl.Close()
+	l.init(ctx, t)
+
+	// Store a root in the local database only.
+	root, err := l.signer.SignLogRoot(&types.LogRootV1{RootHash: rfc6962.DefaultHasher.EmptyRoot(), TimestampNanos: 1, Revision: 1})
+	if err != nil {
+		t.Fatalf("SignLogRoot(): %v", err)
+	}
+	if err := l.storage.local.ReadWriteTransaction(ctx, l.tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		return tx.StoreSignedLogRoot(ctx, *root)
+	}); err != nil {
+		t.Fatalf("StoreSignedLogRoot(): %v", err)
+	}
+	if err := l.storage.CatchUp(ctx, l.tree); err != ErrDiverged {
+		t.Errorf("CatchUp(): %v, want %v", err, ErrDiverged)
+	}
+}
+
+func TestCatchUpBadRootHash(t *testing.T) {
+	ctx := context.Background()
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	l := newTestLog(ctx, t, dir)
+	defer l.cl.Close()
+	l.init(ctx, t)
+	l.queue(ctx, t, 2)
+
+	root, err := l.signer.SignLogRoot(&types.LogRootV1{TreeSize: 2, RootHash: make([]byte, 32), TimestampNanos: 1, Revision: 1})
+	if err != nil {
+		t.Fatalf("SignLogRoot(): %v", err)
+	}
+	if err := l.storage.rootIndex(l.tree.TreeId).append(ctx, l.cl, root, 1); err != nil {
+		t.Fatalf("append(): %v", err)
+	}
+	if err := l.storage.CatchUp(ctx, l.tree); err == nil {
+		t.Error("CatchUp() with bad root hash: got nil, want error")
+	}
+}
+
+func TestClashingRoots(t *testing.T) {
+	ctx := context.Background()
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	l := newTestLog(ctx, t, dir)
+	defer l.cl.Close()
+	l.init(ctx, t)
+	l.queue(ctx, t, 4)
+
+	// Another sequencer's root for revision 1, which lost the race to be
+	// stored at offset 1.
+	root, err := l.signer.SignLogRoot(&types.LogRootV1{TreeSize: 4, RootHash: make([]byte, 32), TimestampNanos: 1, Revision: 1})
+	if err != nil {
+		t.Fatalf("SignLogRoot(): %v", err)
+	}
+	entry, err := encodeRoot(0, root)
+	if err != nil {
+		t.Fatalf("encodeRoot(): %v", err)
+	}
+	if _, err := l.cl.Append(ctx, rootsTopic(l.tree.TreeId), [][]byte{entry}); err != nil {
+		t.Fatalf("Append(): %v", err)
+	}
+
+	l.sequence(ctx, t, 10, 4)
+	l.checkLeaves(ctx, t, 4)
+
+	// A root for a revision which already has one is rejected.
+	if err := l.storage.rootIndex(l.tree.TreeId).append(ctx, l.cl, root, 1); err == nil {
+		t.Error("append() for existing revision: got nil, want error")
+	}
+
+	replica := newTestLog(ctx, t, filepath.Join(dir, "replica"))
+	defer replica.cl.Close()
+	copyTopics(ctx, t, l, replica)
+	if err := replica.storage.CatchUp(ctx, replica.tree); err != nil {
+		t.Fatalf("CatchUp(): %v", err)
+	}
+	replica.checkLeaves(ctx, t, 4)
+}
+
+func TestMapTreeUnsupported(t *testing.T) {
+	ctx := context.Background()
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	l := newTestLog(ctx, t, dir)
+	defer l.cl.Close()
+	tree := *l.tree
+	tree.TreeType = trillian.TreeType_MAP
+	if err := l.storage.ReadWriteTransaction(ctx, &tree, func(context.Context, storage.LogTreeTX) error { return nil }); err == nil {
+		t.Error("ReadWriteTransaction() on map tree: got nil, want error")
+	}
+}
+
+func TestExpireQueuedLeavesUnimplemented(t *testing.T) {
+	ctx := context.Background()
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	l := newTestLog(ctx, t, dir)
+	defer l.cl.Close()
+	l.init(ctx, t)
+	err := l.storage.ReadWriteTransaction(ctx, l.tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		_, err := tx.ExpireQueuedLeaves(ctx, 10, time.Now())
+		return err
+	})
+	if got, want := status.Code(err), codes.Unimplemented; got != want {
+		t.Errorf("ExpireQueuedLeaves(): %v, want code %v", err, want)
+	}
+}
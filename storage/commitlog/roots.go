@@ -0,0 +1,166 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package commitlog
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian"
+	"github.com/google/trillian/types"
+)
+
+// rootReadBatch is the number of entries of a roots topic read at once.
+const rootReadBatch = 100
+
+// encodeRoot returns the roots topic entry for slr, which its writer expects
+// to be stored at offset.
+func encodeRoot(offset int64, slr *trillian.SignedLogRoot) ([]byte, error) {
+	data, err := proto.Marshal(slr)
+	if err != nil {
+		return nil, err
+	}
+	entry := make([]byte, 8+len(data))
+	binary.BigEndian.PutUint64(entry, uint64(offset))
+	copy(entry[8:], data)
+	return entry, nil
+}
+
+// decodeRoot parses a roots topic entry, returning the offset its writer
+// expected it to be stored at, the signed root and its contents.
+func decodeRoot(entry []byte) (int64, *trillian.SignedLogRoot, *types.LogRootV1, error) {
+	if len(entry) < 8 {
+		return 0, nil, nil, fmt.Errorf("root entry too short: %d bytes", len(entry))
+	}
+	var slr trillian.SignedLogRoot
+	if err := proto.Unmarshal(entry[8:], &slr); err != nil {
+		return 0, nil, nil, err
+	}
+	var root types.LogRootV1
+	if err := root.UnmarshalBinary(slr.LogRoot); err != nil {
+		return 0, nil, nil, err
+	}
+	return int64(binary.BigEndian.Uint64(entry)), &slr, &root, nil
+}
+
+// rootIndex tracks the valid entries in the roots topic of a log, i.e. the
+// ones stored at the offset their writer expected. The valid roots have
+// consecutive revisions, starting at zero.
+type rootIndex struct {
+	topic string
+
+	mu      sync.Mutex // Protects everything below
+	size    int64      // Number of entries of the topic read so far
+	offsets []int64    // Offsets of the valid roots, by revision
+}
+
+// update reads the entries appended to the topic since the last update. Must
+// be called with ri.mu held.
+func (ri *rootIndex) update(ctx context.Context, cl Log) error {
+	for {
+		entries, err := cl.Read(ctx, ri.topic, ri.size, rootReadBatch)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		for _, entry := range entries {
+			offset := ri.size
+			expected, _, root, err := decodeRoot(entry)
+			if err != nil {
+				return fmt.Errorf("%s: corrupt root at offset %d: %v", ri.topic, offset, err)
+			}
+			ri.size++
+			if expected != offset {
+				// Another sequencer appended a root first, so this one lost.
+				glog.V(1).Infof("%s: ignoring root for revision %d at offset %d, expected at offset %d", ri.topic, root.Revision, offset, expected)
+				continue
+			}
+			if got, want := root.Revision, uint64(len(ri.offsets)); got != want {
+				return fmt.Errorf("%s: root at offset %d has revision %d, want %d", ri.topic, offset, got, want)
+			}
+			ri.offsets = append(ri.offsets, offset)
+		}
+	}
+}
+
+// get returns the valid root with the given revision, or nil if there is no
+// such root yet.
+func (ri *rootIndex) get(ctx context.Context, cl Log, revision int64) (*trillian.SignedLogRoot, *types.LogRootV1, error) {
+	ri.mu.Lock()
+	defer ri.mu.Unlock()
+	if err := ri.update(ctx, cl); err != nil {
+		return nil, nil, err
+	}
+	if revision < 0 || revision >= int64(len(ri.offsets)) {
+		return nil, nil, nil
+	}
+	entries, err := cl.Read(ctx, ri.topic, ri.offsets[revision], 1)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(entries) != 1 {
+		return nil, nil, fmt.Errorf("%s: root at offset %d disappeared", ri.topic, ri.offsets[revision])
+	}
+	_, slr, root, err := decodeRoot(entries[0])
+	return slr, root, err
+}
+
+// latest returns the valid root with the highest revision, or nil if there are
+// no roots yet.
+func (ri *rootIndex) latest(ctx context.Context, cl Log) (*trillian.SignedLogRoot, *types.LogRootV1, error) {
+	ri.mu.Lock()
+	err := ri.update(ctx, cl)
+	revision := int64(len(ri.offsets)) - 1
+	ri.mu.Unlock()
+	if err != nil {
+		return nil, nil, err
+	}
+	return ri.get(ctx, cl, revision)
+}
+
+// append adds slr, which must be for the revision following the latest valid
+// root, to the topic. It fails if another sequencer appends a root first.
+func (ri *rootIndex) append(ctx context.Context, cl Log, slr *trillian.SignedLogRoot, revision uint64) error {
+	ri.mu.Lock()
+	defer ri.mu.Unlock()
+	if err := ri.update(ctx, cl); err != nil {
+		return err
+	}
+	if got := uint64(len(ri.offsets)); got != revision {
+		return fmt.Errorf("%s: can't add root for revision %d, as the next revision is %d: is another sequencer active?", ri.topic, revision, got)
+	}
+	expected := ri.size
+	entry, err := encodeRoot(expected, slr)
+	if err != nil {
+		return err
+	}
+	offset, err := cl.Append(ctx, ri.topic, [][]byte{entry})
+	if err != nil {
+		return err
+	}
+	if offset != expected {
+		// The entry will be ignored by the next update.
+		return fmt.Errorf("%s: root for revision %d stored at offset %d, expected offset %d: is another sequencer active?", ri.topic, revision, offset, expected)
+	}
+	ri.offsets = append(ri.offsets, offset)
+	ri.size = offset + 1
+	return nil
+}
@@ -119,6 +119,12 @@ type LogTreeTX interface {
 	// UpdateSequencedLeaves associates the leaves with the sequence numbers
 	// assigned to them.
 	UpdateSequencedLeaves(ctx context.Context, leaves []*trillian.LogLeaf) error
+
+	// ExpireQueuedLeaves removes up to limit leaves that were queued strictly
+	// before cutoff and have not yet been dequeued for integration, and returns
+	// the number of leaves removed. Expired leaves may be submitted again.
+	// Only applicable to LOG trees.
+	ExpireQueuedLeaves(ctx context.Context, limit int, cutoff time.Time) (int, error)
 }
 
 // ReadOnlyLogStorage represents a narrowed read-only view into a LogStorage.
@@ -197,4 +203,8 @@ type LogMetadata interface {
 	// GetActiveLogIDs returns a list of the IDs of all the logs that are
 	// configured in storage and are eligible to have entries sequenced.
 	GetActiveLogIDs(ctx context.Context) ([]int64, error)
+
+	// GetUnsequencedCounts returns the number of unsequenced leaves queued for
+	// each log. Logs with an empty queue may be absent from the result.
+	GetUnsequencedCounts(ctx context.Context) (CountByLogID, error)
 }
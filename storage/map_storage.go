@@ -52,6 +52,14 @@ type ReadOnlyMapTreeTX interface {
 	// exist.  i.e. requesting a set of unknown keys would result in a
 	// zero-length array being returned.
 	Get(ctx context.Context, revision int64, keyHashes [][]byte) ([]*trillian.MapLeaf, error)
+	// ListLeaves returns up to limit of the leaves that exist at the specified
+	// revision, in increasing index order, starting with the first leaf whose
+	// index is >= startIndex. An empty startIndex lists from the first leaf.
+	ListLeaves(ctx context.Context, revision int64, startIndex []byte, limit int) ([]*trillian.MapLeaf, error)
+	// ListChangedIndexes returns up to limit of the indexes of the leaves that
+	// were set at any revision in (fromRevision, toRevision], in increasing
+	// order, starting with the first index that is >= startIndex.
+	ListChangedIndexes(ctx context.Context, fromRevision, toRevision int64, startIndex []byte, limit int) ([][]byte, error)
 }
 
 // MapTreeTX is the transactional interface for reading/modifying a Map.
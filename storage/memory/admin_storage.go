@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/google/trillian"
 	"github.com/google/trillian/storage"
@@ -111,7 +112,8 @@ func (t *adminTX) GetTree(ctx context.Context, treeID int64) (*trillian.Tree, er
 	tree.RLock()
 	defer tree.RUnlock()
 
-	return tree.meta, nil
+	// Return a copy, so that callers can't modify the stored tree.
+	return proto.Clone(tree.meta).(*trillian.Tree), nil
 }
 
 func (t *adminTX) ListTreeIDs(ctx context.Context, includeDeleted bool) ([]int64, error) {
@@ -131,7 +133,7 @@ func (t *adminTX) ListTrees(ctx context.Context, includeDeleted bool) ([]*trilli
 
 	var ret []*trillian.Tree
 	for _, v := range t.ms.trees {
-		ret = append(ret, v.meta)
+		ret = append(ret, proto.Clone(v.meta).(*trillian.Tree))
 	}
 	return ret, nil
 }
@@ -176,10 +178,10 @@ func (t *adminTX) UpdateTree(ctx context.Context, treeID int64, updateFunc func(
 	mTree.mu.Lock()
 	defer mTree.mu.Unlock()
 
-	tree := mTree.meta
-	beforeUpdate := *tree
+	// Only keep the update if it's valid.
+	tree := proto.Clone(mTree.meta).(*trillian.Tree)
 	updateFunc(tree)
-	if err := storage.ValidateTreeForUpdate(ctx, &beforeUpdate, tree); err != nil {
+	if err := storage.ValidateTreeForUpdate(ctx, mTree.meta, tree); err != nil {
 		return nil, err
 	}
 	if err := validateStorageSettings(tree); err != nil {
@@ -191,7 +193,8 @@ func (t *adminTX) UpdateTree(ctx context.Context, treeID int64, updateFunc func(
 	if err != nil {
 		return nil, err
 	}
-	return tree, nil
+	mTree.meta = tree
+	return proto.Clone(tree).(*trillian.Tree), nil
 }
 
 func (t *adminTX) SoftDeleteTree(ctx context.Context, treeID int64) (*trillian.Tree, error) {
@@ -12,8 +12,8 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package memory provides a simple in-process implementation of the tree- and
-// log-storage interfaces.
+// Package memory provides a simple in-process implementation of the tree-,
+// log- and map-storage interfaces.
 //
 // This implementation is intended SOLELY for use in integration tests which
 // exercise properties of the higher levels of Trillian componened - e.g.
@@ -29,5 +29,9 @@
 // transaction exclusively locking the tree until it's committed or
 // rolled-back.
 //
+// MapStorage transactions each work on a snapshot of the tree taken when they
+// begin, and apply their writes to the tree when they're committed. This
+// allows the map server to run several transactions for the same map at once.
+//
 // Currently, the Admin Storage does not honor transactional semantics.
 package memory
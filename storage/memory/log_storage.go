@@ -24,6 +24,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/golang/protobuf/ptypes"
 	"github.com/google/btree"
 	"github.com/google/trillian"
 	"github.com/google/trillian/merkle/hashers"
@@ -147,6 +148,21 @@ func (t *readOnlyLogTX) GetActiveLogIDs(ctx context.Context) ([]int64, error) {
 	return getActiveLogIDs(t.ms.trees), nil
 }
 
+func (t *readOnlyLogTX) GetUnsequencedCounts(ctx context.Context) (storage.CountByLogID, error) {
+	t.ms.mu.RLock()
+	defer t.ms.mu.RUnlock()
+
+	ret := make(storage.CountByLogID)
+	for id, tree := range t.ms.trees {
+		tree.RLock()
+		if n := tree.store.Get(unseqKey(id)).(*kv).v.(*list.List).Len(); n > 0 {
+			ret[id] = int64(n)
+		}
+		tree.RUnlock()
+	}
+	return ret, nil
+}
+
 func (m *memoryLogStorage) beginInternal(ctx context.Context, tree *trillian.Tree, readonly bool) (storage.LogTreeTX, error) {
 	once.Do(func() {
 		createMetrics(m.metricFactory)
@@ -204,6 +220,11 @@ func (m *memoryLogStorage) AddSequencedLeaves(ctx context.Context, tree *trillia
 func (m *memoryLogStorage) SnapshotForTree(ctx context.Context, tree *trillian.Tree) (storage.ReadOnlyLogTreeTX, error) {
 	tx, err := m.beginInternal(ctx, tree, true /* readonly */)
 	if err != nil {
+		if tx != nil {
+			// Release the tree lock held by the transaction, e.g. if we
+			// get an ErrTreeNeedsInit from beginInternal().
+			tx.Close()
+		}
 		return nil, err
 	}
 	return tx.(storage.ReadOnlyLogTreeTX), err
@@ -282,6 +303,11 @@ func (t *logTreeTX) QueueLeaves(ctx context.Context, leaves []*trillian.LogLeaf,
 		if len(leaf.LeafIdentityHash) != t.hashSizeBytes {
 			return nil, fmt.Errorf("queued leaf must have a leaf ID hash of length %d", t.hashSizeBytes)
 		}
+		var err error
+		leaf.QueueTimestamp, err = ptypes.TimestampProto(queueTimestamp)
+		if err != nil {
+			return nil, fmt.Errorf("got invalid queue timestamp: %v", err)
+		}
 	}
 	queuedCounter.Add(float64(len(leaves)), labelForTX(t))
 	// No deduping in this storage!
@@ -293,6 +319,24 @@ func (t *logTreeTX) QueueLeaves(ctx context.Context, leaves []*trillian.LogLeaf,
 	return make([]*trillian.LogLeaf, len(leaves)), nil
 }
 
+func (t *logTreeTX) ExpireQueuedLeaves(ctx context.Context, limit int, cutoff time.Time) (int, error) {
+	q := t.tx.Get(unseqKey(t.treeID)).(*kv).v.(*list.List)
+	toRemove := make([]*list.Element, 0, limit)
+	for e := q.Front(); e != nil && len(toRemove) < limit; e = e.Next() {
+		ts, err := ptypes.Timestamp(e.Value.(*trillian.LogLeaf).QueueTimestamp)
+		if err != nil {
+			return 0, fmt.Errorf("got invalid queue timestamp: %v", err)
+		}
+		if ts.Before(cutoff) {
+			toRemove = append(toRemove, e)
+		}
+	}
+	for _, e := range toRemove {
+		q.Remove(e)
+	}
+	return len(toRemove), nil
+}
+
 func (t *logTreeTX) AddSequencedLeaves(ctx context.Context, leaves []*trillian.LogLeaf, timestamp time.Time) ([]*trillian.QueuedLogLeaf, error) {
 	return nil, status.Errorf(codes.Unimplemented, "AddSequencedLeaves is not implemented")
 }
@@ -333,7 +377,7 @@ func (t *logTreeTX) GetLeavesByHash(ctx context.Context, leafHashes [][]byte, or
 	m := t.tx.Get(hashToSeqKey(t.treeID)).(*kv).v.(map[string][]int64)
 
 	ret := make([]*trillian.LogLeaf, 0, len(leafHashes))
-	for hash := range leafHashes {
+	for _, hash := range leafHashes {
 		seq, ok := m[string(hash)]
 		if !ok {
 			continue
@@ -0,0 +1,36 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"testing"
+
+	"github.com/google/trillian/storage"
+
+	storageto "github.com/google/trillian/storage/testonly"
+)
+
+// TestMemoryLogStorage runs the LogStorage tests supported by this storage.
+// It doesn't dedupe queued leaves, or honor the dequeue cutoff time, and
+// doesn't implement AddSequencedLeaves.
+func TestMemoryLogStorage(t *testing.T) {
+	tester := &storageto.LogStorageTester{NewStorage: func() (storage.LogStorage, storage.AdminStorage) {
+		ts := NewTreeStorage()
+		return NewLogStorage(ts, nil), NewAdminStorage(ts)
+	}}
+	t.Run("TestQueueLeaves", tester.TestQueueLeaves)
+	t.Run("TestSequencedLeaves", tester.TestSequencedLeaves)
+	t.Run("TestLatestSignedLogRoot", tester.TestLatestSignedLogRoot)
+}
@@ -0,0 +1,475 @@
+// Copyright 2018 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/btree"
+	"github.com/google/trillian"
+	"github.com/google/trillian/merkle/hashers"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/cache"
+	"github.com/google/trillian/storage/storagepb"
+	"github.com/google/trillian/types"
+)
+
+var defaultMapStrata = []int{8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 176}
+
+// mapLeavesPrefix returns the key prefix shared by all map leaves of a tree.
+func mapLeavesPrefix(treeID int64) string {
+	return fmt.Sprintf("/%d/mapleaf/", treeID)
+}
+
+// mapLeafPrefix returns the key prefix shared by all revisions of the map leaf
+// with the given index.
+func mapLeafPrefix(treeID int64, index []byte) string {
+	return fmt.Sprintf("%s%x/", mapLeavesPrefix(treeID), index)
+}
+
+// mapLeafKey formats a key for use in a tree's BTree store.
+// The associated Item value will be the MapLeaf set at the given index and
+// revision.
+func mapLeafKey(treeID int64, index []byte, rev int64) btree.Item {
+	return &kv{k: fmt.Sprintf("%s%020d", mapLeafPrefix(treeID, index), rev)}
+}
+
+// mapRootPrefix returns the key prefix shared by all SignedMapRoots of a tree.
+func mapRootPrefix(treeID int64) string {
+	return fmt.Sprintf("/%d/smr/", treeID)
+}
+
+// mapRootKey formats a key for use in a tree's BTree store.
+// The associated Item value will be the SignedMapRoot with the given revision.
+func mapRootKey(treeID, rev int64) btree.Item {
+	return &kv{k: fmt.Sprintf("%s%020d", mapRootPrefix(treeID), rev)}
+}
+
+type memoryMapStorage struct {
+	*TreeStorage
+}
+
+// NewMapStorage creates an in-memory MapStorage instance.
+func NewMapStorage(ts *TreeStorage) storage.MapStorage {
+	return &memoryMapStorage{TreeStorage: ts}
+}
+
+func (m *memoryMapStorage) CheckDatabaseAccessible(ctx context.Context) error {
+	return nil
+}
+
+// begin starts a new map transaction.
+//
+// Unlike the log transactions, map transactions do not hold the tree lock
+// for their whole lifetime: the map server may run several read-write
+// transactions for the same tree concurrently (one per subtree being
+// updated), so each transaction works on its own copy of the store and
+// applies its writes to the shared one when committed. Commit fails if
+// another transaction has since stored a map root, or written any of the
+// same items.
+func (m *memoryMapStorage) begin(ctx context.Context, tree *trillian.Tree, readonly bool) (*mapTreeTX, error) {
+	hasher, err := hashers.NewMapHasher(tree.HashStrategy)
+	if err != nil {
+		return nil, err
+	}
+	mt := m.getTree(tree.TreeId)
+	if mt == nil {
+		return nil, fmt.Errorf("tree %d not found", tree.TreeId)
+	}
+
+	// Clone modifies the copy-on-write state of the original, so it needs
+	// exclusive access to the tree.
+	mt.Lock()
+	snapshot := mt.store.Clone()
+	mt.Unlock()
+
+	mtx := &mapTreeTX{
+		treeTX: treeTX{
+			ts:            m.TreeStorage,
+			tx:            snapshot,
+			tree:          mt,
+			treeID:        tree.TreeId,
+			hashSizeBytes: hasher.Size(),
+			subtreeCache:  cache.NewMapSubtreeCache(defaultMapStrata, tree.TreeId, hasher),
+			writeRevision: -1,
+			unlock:        func() {},
+		},
+		ms:           m,
+		readRevision: -1,
+		baseRoot:     latestMapRoot(snapshot, tree.TreeId),
+	}
+
+	if readonly {
+		// readRevision will be set later, by the first
+		// GetSignedMapRoot/LatestSignedMapRoot operation.
+		return mtx, nil
+	}
+
+	// A read-write transaction needs to know the current revision
+	// so it can write at revision+1.
+	root, err := mtx.LatestSignedMapRoot(ctx)
+	if err == storage.ErrTreeNeedsInit {
+		return mtx, err
+	} else if err != nil {
+		mtx.Close()
+		return nil, err
+	}
+
+	var mr types.MapRootV1
+	if err := mr.UnmarshalBinary(root.MapRoot); err != nil {
+		mtx.Close()
+		return nil, err
+	}
+
+	mtx.readRevision = int64(mr.Revision)
+	mtx.treeTX.writeRevision = int64(mr.Revision) + 1
+	return mtx, nil
+}
+
+func (m *memoryMapStorage) SnapshotForTree(ctx context.Context, tree *trillian.Tree) (storage.ReadOnlyMapTreeTX, error) {
+	tx, err := m.begin(ctx, tree, true /* readonly */)
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+func (m *memoryMapStorage) ReadWriteTransaction(ctx context.Context, tree *trillian.Tree, f storage.MapTXFunc) error {
+	tx, err := m.begin(ctx, tree, false /* readonly */)
+	if tx != nil {
+		defer tx.Close()
+	}
+	if err != nil && err != storage.ErrTreeNeedsInit {
+		return err
+	}
+	if err := f(ctx, tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+type mapTreeTX struct {
+	treeTX
+	ms *memoryMapStorage
+
+	// mu guards the fields below, as well as reads and writes of the
+	// transaction's copy of the store.
+	mu           sync.Mutex
+	readRevision int64
+	// writes holds the items written by this transaction, in order, so that
+	// they can be applied to the shared store on Commit.
+	writes []btree.Item
+	// baseRoot is the latest map root when the transaction began, or nil.
+	baseRoot btree.Item
+	// prev holds the item each written key had when the transaction began,
+	// or nil if it had none.
+	prev map[string]btree.Item
+}
+
+func (m *mapTreeTX) ReadRevision(ctx context.Context) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.readRevision, nil
+}
+
+func (m *mapTreeTX) WriteRevision(ctx context.Context) (int64, error) {
+	if m.treeTX.writeRevision < 0 {
+		return m.treeTX.writeRevision, errors.New("mapTreeTX write revision not populated")
+	}
+	return m.treeTX.writeRevision, nil
+}
+
+// put inserts item into the transaction's view of the store, and records it
+// to be written on Commit. Callers must hold m.mu.
+func (m *mapTreeTX) put(item btree.Item) {
+	old := m.tx.ReplaceOrInsert(item)
+	if m.prev == nil {
+		m.prev = make(map[string]btree.Item)
+	}
+	k := item.(*kv).k
+	if _, ok := m.prev[k]; !ok {
+		m.prev[k] = old
+	}
+	m.writes = append(m.writes, item)
+}
+
+// checkConflicts returns an error if another transaction has stored a map
+// root, or written any of the items written by this transaction, since it
+// began. Callers must hold the tree lock.
+func (m *mapTreeTX) checkConflicts() error {
+	if len(m.writes) == 0 {
+		return nil
+	}
+	if latestMapRoot(m.tree.store, m.treeID) != m.baseRoot {
+		return fmt.Errorf("map %d: a new root was stored by a concurrent transaction", m.treeID)
+	}
+	for k, old := range m.prev {
+		if m.tree.store.Get(&kv{k: k}) != old {
+			return fmt.Errorf("map %d: %s was written by a concurrent transaction", m.treeID, k)
+		}
+	}
+	return nil
+}
+
+// latestMapRoot returns the item holding the latest SignedMapRoot of the tree
+// in store, or nil if there is none.
+func latestMapRoot(store *btree.BTree, treeID int64) btree.Item {
+	prefix := mapRootPrefix(treeID)
+	var root btree.Item
+	store.DescendLessOrEqual(mapRootKey(treeID, math.MaxInt64), func(i btree.Item) bool {
+		if strings.HasPrefix(i.(*kv).k, prefix) {
+			root = i
+		}
+		return false
+	})
+	return root
+}
+
+// GetMerkleNodes returns the requests nodes at (or below) the passed in treeRevision.
+func (m *mapTreeTX) GetMerkleNodes(ctx context.Context, treeRevision int64, nodeIDs []storage.NodeID) ([]storage.Node, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.treeTX.GetMerkleNodes(ctx, treeRevision, nodeIDs)
+}
+
+func (m *mapTreeTX) SetMerkleNodes(ctx context.Context, nodes []storage.Node) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.treeTX.SetMerkleNodes(ctx, nodes)
+}
+
+func (m *mapTreeTX) Set(ctx context.Context, keyHash []byte, value trillian.MapLeaf) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.writeRevision < 0 {
+		return errors.New("mapTreeTX write revision not populated")
+	}
+	k := mapLeafKey(m.treeID, keyHash, m.writeRevision)
+	if m.tx.Has(k) {
+		return fmt.Errorf("map leaf %x already set at revision %d", keyHash, m.writeRevision)
+	}
+	k.(*kv).v = proto.Clone(&value).(*trillian.MapLeaf)
+	m.put(k)
+	return nil
+}
+
+// Get returns a list of map leaves indicated by indexes.
+// If an index is not found, no corresponding entry is returned.
+// Each MapLeaf.Index is overwritten with the index the leaf was found at.
+func (m *mapTreeTX) Get(ctx context.Context, revision int64, indexes [][]byte) ([]*trillian.MapLeaf, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if revision < 0 {
+		revision = math.MaxInt64
+	}
+	ret := make([]*trillian.MapLeaf, 0, len(indexes))
+	for _, index := range indexes {
+		prefix := mapLeafPrefix(m.treeID, index)
+		var leaf *trillian.MapLeaf
+		// Find the most recent value at or below the requested revision.
+		m.tx.DescendLessOrEqual(mapLeafKey(m.treeID, index, revision), func(i btree.Item) bool {
+			if e := i.(*kv); strings.HasPrefix(e.k, prefix) {
+				leaf = e.v.(*trillian.MapLeaf)
+			}
+			return false
+		})
+		if leaf == nil || proto.Size(leaf) == 0 {
+			continue
+		}
+		// Return a copy of the proto to protect against the caller modifying the stored one.
+		l := proto.Clone(leaf).(*trillian.MapLeaf)
+		l.Index = index
+		ret = append(ret, l)
+	}
+	return ret, nil
+}
+
+// ListLeaves returns up to limit of the map leaves at revision, in increasing
+// index order, starting from startIndex.
+func (m *mapTreeTX) ListLeaves(ctx context.Context, revision int64, startIndex []byte, limit int) ([]*trillian.MapLeaf, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if revision < 0 {
+		revision = math.MaxInt64
+	}
+	// All revisions of a leaf are adjacent in the BTree, ordered by revision,
+	// and the hex encoded indexes sort in the same order as the indexes.
+	prefix := mapLeavesPrefix(m.treeID)
+	ret := make([]*trillian.MapLeaf, 0, limit)
+	var index string
+	var leaf *trillian.MapLeaf
+	emit := func() {
+		if leaf == nil || proto.Size(leaf) == 0 {
+			return
+		}
+		// Return a copy of the proto to protect against the caller modifying the stored one.
+		l := proto.Clone(leaf).(*trillian.MapLeaf)
+		l.Index, _ = hex.DecodeString(index)
+		ret = append(ret, l)
+	}
+	m.tx.AscendGreaterOrEqual(&kv{k: mapLeafPrefix(m.treeID, startIndex)}, func(i btree.Item) bool {
+		e := i.(*kv)
+		if !strings.HasPrefix(e.k, prefix) {
+			return false
+		}
+		parts := strings.SplitN(strings.TrimPrefix(e.k, prefix), "/", 2)
+		if parts[0] != index {
+			emit()
+			if len(ret) >= limit {
+				return false
+			}
+			index, leaf = parts[0], nil
+		}
+		if rev, err := strconv.ParseInt(parts[1], 10, 64); err == nil && rev <= revision {
+			leaf = e.v.(*trillian.MapLeaf)
+		}
+		return true
+	})
+	if len(ret) < limit {
+		emit()
+	}
+	return ret, nil
+}
+
+// ListChangedIndexes returns up to limit of the indexes of the map leaves set
+// at revisions in (fromRevision, toRevision], in increasing order, starting
+// from startIndex.
+func (m *mapTreeTX) ListChangedIndexes(ctx context.Context, fromRevision, toRevision int64, startIndex []byte, limit int) ([][]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	prefix := mapLeavesPrefix(m.treeID)
+	var ret [][]byte
+	var last string
+	m.tx.AscendGreaterOrEqual(&kv{k: mapLeafPrefix(m.treeID, startIndex)}, func(i btree.Item) bool {
+		e := i.(*kv)
+		if !strings.HasPrefix(e.k, prefix) || len(ret) >= limit {
+			return false
+		}
+		parts := strings.SplitN(strings.TrimPrefix(e.k, prefix), "/", 2)
+		rev, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil || rev <= fromRevision || rev > toRevision || parts[0] == last {
+			return true
+		}
+		last = parts[0]
+		index, _ := hex.DecodeString(parts[0])
+		ret = append(ret, index)
+		return true
+	})
+	return ret, nil
+}
+
+func (m *mapTreeTX) GetSignedMapRoot(ctx context.Context, revision int64) (trillian.SignedMapRoot, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r := m.tx.Get(mapRootKey(m.treeID, revision))
+	if r == nil {
+		if revision == 0 {
+			return trillian.SignedMapRoot{}, storage.ErrTreeNeedsInit
+		}
+		return trillian.SignedMapRoot{}, fmt.Errorf("no SignedMapRoot for revision %d", revision)
+	}
+	m.readRevision = revision
+	return *proto.Clone(r.(*kv).v.(*trillian.SignedMapRoot)).(*trillian.SignedMapRoot), nil
+}
+
+func (m *mapTreeTX) LatestSignedMapRoot(ctx context.Context) (trillian.SignedMapRoot, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item := latestMapRoot(m.tx, m.treeID)
+	// It's possible there are no roots for this tree yet
+	if item == nil {
+		return trillian.SignedMapRoot{}, storage.ErrTreeNeedsInit
+	}
+	root := item.(*kv).v.(*trillian.SignedMapRoot)
+
+	var mr types.MapRootV1
+	if err := mr.UnmarshalBinary(root.MapRoot); err != nil {
+		return trillian.SignedMapRoot{}, err
+	}
+	m.readRevision = int64(mr.Revision)
+	return *proto.Clone(root).(*trillian.SignedMapRoot), nil
+}
+
+func (m *mapTreeTX) StoreSignedMapRoot(ctx context.Context, root trillian.SignedMapRoot) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var r types.MapRootV1
+	if err := r.UnmarshalBinary(root.MapRoot); err != nil {
+		return err
+	}
+	k := mapRootKey(m.treeID, int64(r.Revision))
+	if m.tx.Has(k) {
+		return fmt.Errorf("SignedMapRoot for revision %d already exists", r.Revision)
+	}
+	k.(*kv).v = proto.Clone(&root).(*trillian.SignedMapRoot)
+	m.put(k)
+	return nil
+}
+
+// Commit flushes the subtree cache, and applies all the writes made by the
+// transaction to the shared store, unless they conflict with the writes of a
+// transaction committed since this one began.
+func (m *mapTreeTX) Commit() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.writeRevision > -1 {
+		if err := m.subtreeCache.Flush(func(st []*storagepb.SubtreeProto) error {
+			for _, s := range st {
+				if s.Prefix == nil {
+					return fmt.Errorf("nil prefix on %v", s)
+				}
+				k := subtreeKey(m.treeID, m.writeRevision, storage.NewNodeIDFromHash(s.Prefix))
+				k.(*kv).v = s
+				m.put(k)
+			}
+			return nil
+		}); err != nil {
+			glog.Warningf("TX commit flush error: %v", err)
+			return err
+		}
+	}
+	m.closed = true
+
+	m.tree.Lock()
+	defer m.tree.Unlock()
+	if err := m.checkConflicts(); err != nil {
+		return err
+	}
+	for _, w := range m.writes {
+		m.tree.store.ReplaceOrInsert(w)
+	}
+	m.writes = nil
+	return nil
+}
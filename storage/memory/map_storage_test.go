@@ -0,0 +1,351 @@
+// Copyright 2018 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/testonly"
+	"github.com/google/trillian/types"
+
+	tcrypto "github.com/google/trillian/crypto"
+	storageto "github.com/google/trillian/storage/testonly"
+)
+
+var fixedSigner = tcrypto.NewSigner(0, testonly.NewSignerWithFixedSig(nil, []byte("notempty")), crypto.SHA256)
+
+func mustSignMapRoot(t *testing.T, root *types.MapRootV1) *trillian.SignedMapRoot {
+	t.Helper()
+	r, err := fixedSigner.SignMapRoot(root)
+	if err != nil {
+		t.Fatalf("SignMapRoot(): %v", err)
+	}
+	return r
+}
+
+func createInitializedMapForTests(ctx context.Context, t *testing.T, ts *TreeStorage) *trillian.Tree {
+	t.Helper()
+	tree, err := storage.CreateTree(ctx, NewAdminStorage(ts), storageto.MapTree)
+	if err != nil {
+		t.Fatalf("CreateTree(): %v", err)
+	}
+	s := NewMapStorage(ts)
+	err = s.ReadWriteTransaction(ctx, tree, func(ctx context.Context, tx storage.MapTreeTX) error {
+		return tx.StoreSignedMapRoot(ctx, *mustSignMapRoot(t, &types.MapRootV1{RootHash: []byte("rootHash")}))
+	})
+	if err != nil {
+		t.Fatalf("ReadWriteTransaction() = %v", err)
+	}
+	return tree
+}
+
+func runMapTX(ctx context.Context, s storage.MapStorage, tree *trillian.Tree, t *testing.T, f storage.MapTXFunc) {
+	t.Helper()
+	if err := s.ReadWriteTransaction(ctx, tree, f); err != nil {
+		t.Fatalf("ReadWriteTransaction(): %v", err)
+	}
+}
+
+// storeRoot writes a new root at the transaction's write revision, so that
+// the next transaction writes at a new revision.
+func storeRoot(ctx context.Context, t *testing.T, tx storage.MapTreeTX) {
+	t.Helper()
+	rev, err := tx.WriteRevision(ctx)
+	if err != nil {
+		t.Fatalf("WriteRevision(): %v", err)
+	}
+	root := mustSignMapRoot(t, &types.MapRootV1{TimestampNanos: uint64(rev), Revision: uint64(rev), RootHash: []byte("rootHash")})
+	if err := tx.StoreSignedMapRoot(ctx, *root); err != nil {
+		t.Fatalf("StoreSignedMapRoot(): %v", err)
+	}
+}
+
+func TestMemoryMapStorage(t *testing.T) {
+	tester := &storageto.MapStorageTester{NewStorage: func() (storage.MapStorage, storage.AdminStorage) {
+		ts := NewTreeStorage()
+		return NewMapStorage(ts), NewAdminStorage(ts)
+	}}
+	tester.RunAllTests(t)
+}
+
+func TestMapUninitialized(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTreeStorage()
+	tree, err := storage.CreateTree(ctx, NewAdminStorage(ts), storageto.MapTree)
+	if err != nil {
+		t.Fatalf("CreateTree(): %v", err)
+	}
+	s := NewMapStorage(ts)
+
+	tx, err := s.SnapshotForTree(ctx, tree)
+	if err != nil {
+		t.Fatalf("SnapshotForTree(): %v", err)
+	}
+	defer tx.Close()
+	if _, err := tx.LatestSignedMapRoot(ctx); err != storage.ErrTreeNeedsInit {
+		t.Errorf("LatestSignedMapRoot() = %v, want %v", err, storage.ErrTreeNeedsInit)
+	}
+	if _, err := tx.GetSignedMapRoot(ctx, 0); err != storage.ErrTreeNeedsInit {
+		t.Errorf("GetSignedMapRoot(0) = %v, want %v", err, storage.ErrTreeNeedsInit)
+	}
+
+	if _, err := s.SnapshotForTree(ctx, &trillian.Tree{TreeId: -1, HashStrategy: trillian.HashStrategy_TEST_MAP_HASHER}); err == nil {
+		t.Error("SnapshotForTree(unknown tree) = nil, want error")
+	}
+}
+
+func TestMapSetGetMultipleRevisions(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTreeStorage()
+	tree := createInitializedMapForTests(ctx, t, ts)
+	s := NewMapStorage(ts)
+
+	index := []byte("A Key Hash")
+	leaves := []*trillian.MapLeaf{
+		{Index: index, LeafHash: []byte{1}, LeafValue: []byte{1}, ExtraData: []byte{1}},
+		{Index: index, LeafHash: []byte{2}, LeafValue: []byte{2}, ExtraData: []byte{2}},
+		{Index: index, LeafHash: []byte{3}, LeafValue: []byte{3}, ExtraData: []byte{3}},
+	}
+	// Write each leaf at revisions 1, 2, 3 in turn.
+	for _, leaf := range leaves {
+		runMapTX(ctx, s, tree, t, func(ctx context.Context, tx storage.MapTreeTX) error {
+			if err := tx.Set(ctx, index, *leaf); err != nil {
+				t.Fatalf("Set(): %v", err)
+			}
+			storeRoot(ctx, t, tx)
+			return nil
+		})
+	}
+
+	for _, tc := range []struct {
+		rev  int64
+		want *trillian.MapLeaf
+	}{
+		{rev: 0},
+		{rev: 1, want: leaves[0]},
+		{rev: 2, want: leaves[1]},
+		{rev: 3, want: leaves[2]},
+		{rev: 10, want: leaves[2]},
+		{rev: -1, want: leaves[2]},
+	} {
+		tx, err := s.SnapshotForTree(ctx, tree)
+		if err != nil {
+			t.Fatalf("SnapshotForTree(): %v", err)
+		}
+		got, err := tx.Get(ctx, tc.rev, [][]byte{index, []byte("unknown")})
+		if err != nil {
+			t.Fatalf("Get(%d): %v", tc.rev, err)
+		}
+		if tc.want == nil {
+			if len(got) != 0 {
+				t.Errorf("Get(%d) = %v, want no leaves", tc.rev, got)
+			}
+		} else if len(got) != 1 || !proto.Equal(got[0], tc.want) {
+			t.Errorf("Get(%d) = %v, want %v", tc.rev, got, tc.want)
+		}
+		tx.Close()
+	}
+}
+
+func TestMapSetSameKeyInSameRevisionFails(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTreeStorage()
+	tree := createInitializedMapForTests(ctx, t, ts)
+	s := NewMapStorage(ts)
+
+	index := []byte("A Key Hash")
+	leaf := trillian.MapLeaf{Index: index, LeafValue: []byte("A Value")}
+	runMapTX(ctx, s, tree, t, func(ctx context.Context, tx storage.MapTreeTX) error {
+		if err := tx.Set(ctx, index, leaf); err != nil {
+			t.Fatalf("Set(): %v", err)
+		}
+		return nil
+	})
+	runMapTX(ctx, s, tree, t, func(ctx context.Context, tx storage.MapTreeTX) error {
+		if err := tx.Set(ctx, index, leaf); err == nil {
+			t.Fatal("Set() succeeded for the second time at the same revision")
+		}
+		return nil
+	})
+}
+
+func TestMapRoots(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTreeStorage()
+	tree := createInitializedMapForTests(ctx, t, ts)
+	s := NewMapStorage(ts)
+
+	root5 := mustSignMapRoot(t, &types.MapRootV1{TimestampNanos: 98765, Revision: 5, RootHash: []byte("rootHash5")})
+	root6 := mustSignMapRoot(t, &types.MapRootV1{TimestampNanos: 98766, Revision: 6, RootHash: []byte("rootHash6")})
+	runMapTX(ctx, s, tree, t, func(ctx context.Context, tx storage.MapTreeTX) error {
+		for _, r := range []*trillian.SignedMapRoot{root5, root6} {
+			if err := tx.StoreSignedMapRoot(ctx, *r); err != nil {
+				t.Fatalf("StoreSignedMapRoot(): %v", err)
+			}
+		}
+		if err := tx.StoreSignedMapRoot(ctx, *root5); err == nil {
+			t.Error("StoreSignedMapRoot() allowed a duplicate root")
+		}
+		return nil
+	})
+
+	tx, err := s.SnapshotForTree(ctx, tree)
+	if err != nil {
+		t.Fatalf("SnapshotForTree(): %v", err)
+	}
+	defer tx.Close()
+	latest, err := tx.LatestSignedMapRoot(ctx)
+	if err != nil {
+		t.Fatalf("LatestSignedMapRoot(): %v", err)
+	}
+	if !proto.Equal(&latest, root6) {
+		t.Errorf("LatestSignedMapRoot() = %v, want %v", latest, root6)
+	}
+	if rev, _ := tx.ReadRevision(ctx); rev != 6 {
+		t.Errorf("ReadRevision() = %d, want 6", rev)
+	}
+	got, err := tx.GetSignedMapRoot(ctx, 5)
+	if err != nil {
+		t.Fatalf("GetSignedMapRoot(5): %v", err)
+	}
+	if !proto.Equal(&got, root5) {
+		t.Errorf("GetSignedMapRoot(5) = %v, want %v", got, root5)
+	}
+	if _, err := tx.GetSignedMapRoot(ctx, 4); err == nil {
+		t.Error("GetSignedMapRoot(4) = nil, want error")
+	}
+}
+
+func TestMapConcurrentTransactions(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTreeStorage()
+	tree := createInitializedMapForTests(ctx, t, ts)
+	s := NewMapStorage(ts)
+
+	// The map server may run a transaction per subtree while the outer
+	// transaction is still open; both sets of writes must survive.
+	inner, outer := []byte("inner"), []byte("outer")
+	runMapTX(ctx, s, tree, t, func(ctx context.Context, tx storage.MapTreeTX) error {
+		runMapTX(ctx, s, tree, t, func(ctx context.Context, tx storage.MapTreeTX) error {
+			return tx.Set(ctx, inner, trillian.MapLeaf{LeafValue: inner})
+		})
+		if err := tx.Set(ctx, outer, trillian.MapLeaf{LeafValue: outer}); err != nil {
+			t.Fatalf("Set(): %v", err)
+		}
+		storeRoot(ctx, t, tx)
+		return nil
+	})
+
+	tx, err := s.SnapshotForTree(ctx, tree)
+	if err != nil {
+		t.Fatalf("SnapshotForTree(): %v", err)
+	}
+	defer tx.Close()
+	got, err := tx.Get(ctx, 1, [][]byte{inner, outer})
+	if err != nil {
+		t.Fatalf("Get(): %v", err)
+	}
+	if len(got) != 2 {
+		t.Errorf("Get() returned %d leaves, want 2", len(got))
+	}
+}
+
+func TestMapRootSubtreeRevisions(t *testing.T) {
+	ctx := context.Background()
+	ts := NewTreeStorage()
+	tree := createInitializedMapForTests(ctx, t, ts)
+	s := NewMapStorage(ts)
+
+	// Nodes near the root live in the subtree with an empty prefix, which must
+	// survive being read back by later revisions.
+	id := storage.NewNodeIDFromPrefix(nil, 8, 3, 8, 256)
+	for _, hash := range [][]byte{make([]byte, 32), bytes.Repeat([]byte{1}, 32)} {
+		runMapTX(ctx, s, tree, t, func(ctx context.Context, tx storage.MapTreeTX) error {
+			rev, err := tx.WriteRevision(ctx)
+			if err != nil {
+				t.Fatalf("WriteRevision(): %v", err)
+			}
+			if err := tx.SetMerkleNodes(ctx, []storage.Node{{NodeID: id, Hash: hash, NodeRevision: rev}}); err != nil {
+				t.Fatalf("SetMerkleNodes(): %v", err)
+			}
+			storeRoot(ctx, t, tx)
+			return nil
+		})
+	}
+}
+
+func TestMapConflictingTransactions(t *testing.T) {
+	ctx := context.Background()
+	leaf := trillian.MapLeaf{LeafValue: []byte("value")}
+
+	for _, test := range []struct {
+		desc  string
+		inner func(ctx context.Context, tx storage.MapTreeTX) error
+		outer func(ctx context.Context, tx storage.MapTreeTX) error
+	}{
+		{
+			desc: "sameRoot",
+			inner: func(ctx context.Context, tx storage.MapTreeTX) error {
+				storeRoot(ctx, t, tx)
+				return nil
+			},
+			outer: func(ctx context.Context, tx storage.MapTreeTX) error {
+				storeRoot(ctx, t, tx)
+				return nil
+			},
+		},
+		{
+			desc: "sameLeaf",
+			inner: func(ctx context.Context, tx storage.MapTreeTX) error {
+				return tx.Set(ctx, []byte("index"), leaf)
+			},
+			outer: func(ctx context.Context, tx storage.MapTreeTX) error {
+				return tx.Set(ctx, []byte("index"), leaf)
+			},
+		},
+		{
+			desc: "newRoot",
+			inner: func(ctx context.Context, tx storage.MapTreeTX) error {
+				storeRoot(ctx, t, tx)
+				return nil
+			},
+			outer: func(ctx context.Context, tx storage.MapTreeTX) error {
+				return tx.Set(ctx, []byte("index"), leaf)
+			},
+		},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			ts := NewTreeStorage()
+			tree := createInitializedMapForTests(ctx, t, ts)
+			s := NewMapStorage(ts)
+
+			// Both transactions begin at the same revision, and the inner one
+			// commits first.
+			err := s.ReadWriteTransaction(ctx, tree, func(ctx context.Context, tx storage.MapTreeTX) error {
+				runMapTX(ctx, s, tree, t, test.inner)
+				return test.outer(ctx, tx)
+			})
+			if err == nil {
+				t.Error("ReadWriteTransaction() = nil, want conflict error")
+			}
+		})
+	}
+}
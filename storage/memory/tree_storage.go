@@ -77,8 +77,8 @@ func (t *tree) RUnlock() {
 	t.mu.RUnlock()
 }
 
-// TreeStorage is shared between the memoryLog and (forthcoming) memoryMap-
-// Storage implementations, and contains functionality which is common to both,
+// TreeStorage is shared between the memoryLog and memoryMap- Storage
+// implementations, and contains functionality which is common to both,
 type TreeStorage struct {
 	// mu only protects access to the trees map.
 	mu    sync.RWMutex
@@ -199,6 +199,10 @@ func (t *treeTX) getSubtrees(ctx context.Context, treeRevision int64, nodeIDs []
 			// Return a copy of the proto to protect against the caller modifying the stored one.
 			p := s.(*kv).v.(*storagepb.SubtreeProto)
 			v := proto.Clone(p).(*storagepb.SubtreeProto)
+			// Clone drops the empty prefix of the root subtree.
+			if v.Prefix == nil {
+				v.Prefix = []byte{}
+			}
 			ret = append(ret, v)
 			break
 		}
@@ -468,6 +468,21 @@ func (mr *MockLogTreeTXMockRecorder) DequeueLeaves(arg0, arg1, arg2 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DequeueLeaves", reflect.TypeOf((*MockLogTreeTX)(nil).DequeueLeaves), arg0, arg1, arg2)
 }
 
+// ExpireQueuedLeaves mocks base method
+func (m *MockLogTreeTX) ExpireQueuedLeaves(arg0 context.Context, arg1 int, arg2 time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireQueuedLeaves", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireQueuedLeaves indicates an expected call of ExpireQueuedLeaves
+func (mr *MockLogTreeTXMockRecorder) ExpireQueuedLeaves(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireQueuedLeaves", reflect.TypeOf((*MockLogTreeTX)(nil).ExpireQueuedLeaves), arg0, arg1, arg2)
+}
+
 // GetLeavesByHash mocks base method
 func (m *MockLogTreeTX) GetLeavesByHash(arg0 context.Context, arg1 [][]byte, arg2 bool) ([]*trillian.LogLeaf, error) {
 	m.ctrl.T.Helper()
@@ -864,6 +879,36 @@ func (mr *MockMapTreeTXMockRecorder) LatestSignedMapRoot(arg0 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LatestSignedMapRoot", reflect.TypeOf((*MockMapTreeTX)(nil).LatestSignedMapRoot), arg0)
 }
 
+// ListChangedIndexes mocks base method
+func (m *MockMapTreeTX) ListChangedIndexes(arg0 context.Context, arg1, arg2 int64, arg3 []byte, arg4 int) ([][]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChangedIndexes", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([][]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChangedIndexes indicates an expected call of ListChangedIndexes
+func (mr *MockMapTreeTXMockRecorder) ListChangedIndexes(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChangedIndexes", reflect.TypeOf((*MockMapTreeTX)(nil).ListChangedIndexes), arg0, arg1, arg2, arg3, arg4)
+}
+
+// ListLeaves mocks base method
+func (m *MockMapTreeTX) ListLeaves(arg0 context.Context, arg1 int64, arg2 []byte, arg3 int) ([]*trillian.MapLeaf, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLeaves", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*trillian.MapLeaf)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLeaves indicates an expected call of ListLeaves
+func (mr *MockMapTreeTXMockRecorder) ListLeaves(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLeaves", reflect.TypeOf((*MockMapTreeTX)(nil).ListLeaves), arg0, arg1, arg2, arg3)
+}
+
 // ReadRevision mocks base method
 func (m *MockMapTreeTX) ReadRevision(arg0 context.Context) (int64, error) {
 	m.ctrl.T.Helper()
@@ -1140,6 +1185,21 @@ func (mr *MockReadOnlyLogTXMockRecorder) GetActiveLogIDs(arg0 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveLogIDs", reflect.TypeOf((*MockReadOnlyLogTX)(nil).GetActiveLogIDs), arg0)
 }
 
+// GetUnsequencedCounts mocks base method
+func (m *MockReadOnlyLogTX) GetUnsequencedCounts(arg0 context.Context) (CountByLogID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnsequencedCounts", arg0)
+	ret0, _ := ret[0].(CountByLogID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnsequencedCounts indicates an expected call of GetUnsequencedCounts
+func (mr *MockReadOnlyLogTXMockRecorder) GetUnsequencedCounts(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnsequencedCounts", reflect.TypeOf((*MockReadOnlyLogTX)(nil).GetUnsequencedCounts), arg0)
+}
+
 // Rollback mocks base method
 func (m *MockReadOnlyLogTX) Rollback() error {
 	m.ctrl.T.Helper()
@@ -1463,6 +1523,36 @@ func (mr *MockReadOnlyMapTreeTXMockRecorder) LatestSignedMapRoot(arg0 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LatestSignedMapRoot", reflect.TypeOf((*MockReadOnlyMapTreeTX)(nil).LatestSignedMapRoot), arg0)
 }
 
+// ListChangedIndexes mocks base method
+func (m *MockReadOnlyMapTreeTX) ListChangedIndexes(arg0 context.Context, arg1, arg2 int64, arg3 []byte, arg4 int) ([][]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChangedIndexes", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([][]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChangedIndexes indicates an expected call of ListChangedIndexes
+func (mr *MockReadOnlyMapTreeTXMockRecorder) ListChangedIndexes(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChangedIndexes", reflect.TypeOf((*MockReadOnlyMapTreeTX)(nil).ListChangedIndexes), arg0, arg1, arg2, arg3, arg4)
+}
+
+// ListLeaves mocks base method
+func (m *MockReadOnlyMapTreeTX) ListLeaves(arg0 context.Context, arg1 int64, arg2 []byte, arg3 int) ([]*trillian.MapLeaf, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLeaves", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]*trillian.MapLeaf)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLeaves indicates an expected call of ListLeaves
+func (mr *MockReadOnlyMapTreeTXMockRecorder) ListLeaves(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLeaves", reflect.TypeOf((*MockReadOnlyMapTreeTX)(nil).ListLeaves), arg0, arg1, arg2, arg3)
+}
+
 // ReadRevision mocks base method
 func (m *MockReadOnlyMapTreeTX) ReadRevision(arg0 context.Context) (int64, error) {
 	m.ctrl.T.Helper()
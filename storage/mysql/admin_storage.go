@@ -54,13 +54,17 @@ const (
 			PublicKey,
 			MaxRootDurationMillis,
 			Deleted,
-			DeleteTimeMillis
+			DeleteTimeMillis,
+			QueueTTLMillis,
+			MaxQueueDepth,
+			KeyId,
+			PreviousKeys
 		FROM Trees`
 	selectNonDeletedTrees = selectTrees + nonDeletedWhere
 	selectTreeByID        = selectTrees + " WHERE TreeId = ?"
 
 	updateTreeSQL = `UPDATE Trees
-		SET TreeState = ?, TreeType = ?, DisplayName = ?, Description = ?, UpdateTimeMillis = ?, MaxRootDurationMillis = ?, PrivateKey = ?
+		SET TreeState = ?, TreeType = ?, DisplayName = ?, Description = ?, UpdateTimeMillis = ?, MaxRootDurationMillis = ?, PrivateKey = ?, QueueTTLMillis = ?, MaxQueueDepth = ?, PublicKey = ?, KeyId = ?, PreviousKeys = ?
 		WHERE TreeId = ?`
 )
 
@@ -260,6 +264,10 @@ func (t *adminTX) CreateTree(ctx context.Context, tree *trillian.Tree) (*trillia
 	if err != nil {
 		return nil, fmt.Errorf("could not parse MaxRootDuration: %v", err)
 	}
+	queueTTLMillis, err := storage.QueueTTLMillis(&newTree)
+	if err != nil {
+		return nil, err
+	}
 
 	insertTreeStmt, err := t.tx.PrepareContext(
 		ctx,
@@ -276,8 +284,10 @@ func (t *adminTX) CreateTree(ctx context.Context, tree *trillian.Tree) (*trillia
 			UpdateTimeMillis,
 			PrivateKey,
 			PublicKey,
-			MaxRootDurationMillis)
-		VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+			MaxRootDurationMillis,
+			QueueTTLMillis,
+			MaxQueueDepth)
+		VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
 	if err != nil {
 		return nil, err
 	}
@@ -303,6 +313,8 @@ func (t *adminTX) CreateTree(ctx context.Context, tree *trillian.Tree) (*trillia
 		privateKey,
 		newTree.PublicKey.GetDer(),
 		rootDuration/time.Millisecond,
+		queueTTLMillis,
+		newTree.MaxQueueDepth,
 	)
 	if err != nil {
 		return nil, err
@@ -372,11 +384,19 @@ func (t *adminTX) UpdateTree(ctx context.Context, treeID int64, updateFunc func(
 	if err != nil {
 		return nil, fmt.Errorf("could not parse MaxRootDuration: %v", err)
 	}
+	queueTTLMillis, err := storage.QueueTTLMillis(tree)
+	if err != nil {
+		return nil, err
+	}
 
 	privateKey, err := proto.Marshal(tree.PrivateKey)
 	if err != nil {
 		return nil, fmt.Errorf("could not marshal PrivateKey: %v", err)
 	}
+	previousKeys, err := storage.MarshalPreviousKeys(tree)
+	if err != nil {
+		return nil, err
+	}
 
 	stmt, err := t.tx.PrepareContext(ctx, updateTreeSQL)
 	if err != nil {
@@ -393,6 +413,11 @@ func (t *adminTX) UpdateTree(ctx context.Context, treeID int64, updateFunc func(
 		nowMillis,
 		rootDuration/time.Millisecond,
 		privateKey,
+		queueTTLMillis,
+		tree.MaxQueueDepth,
+		tree.PublicKey.GetDer(),
+		tree.KeyId,
+		previousKeys,
 		tree.TreeId); err != nil {
 		return nil, err
 	}
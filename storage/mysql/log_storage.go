@@ -50,8 +50,9 @@ const (
 		  AND TreeState IN(?,?)
 		  AND (Deleted IS NULL OR Deleted = 'false')`
 
-	selectSequencedLeafCountSQL  = "SELECT COUNT(*) FROM SequencedLeafData WHERE TreeId=?"
-	selectLatestSignedLogRootSQL = `SELECT TreeHeadTimestamp,TreeSize,RootHash,TreeRevision,RootSignature
+	selectSequencedLeafCountSQL   = "SELECT COUNT(*) FROM SequencedLeafData WHERE TreeId=?"
+	selectUnsequencedLeafCountSQL = "SELECT TreeId, COUNT(1) FROM Unsequenced GROUP BY TreeId"
+	selectLatestSignedLogRootSQL  = `SELECT TreeHeadTimestamp,TreeSize,RootHash,TreeRevision,RootSignature,KeyHint
 			FROM TreeHead WHERE TreeId=?
 			ORDER BY TreeHeadTimestamp DESC LIMIT 1`
 
@@ -82,6 +83,19 @@ const (
 	orderBySequenceNumberSQL                     = " ORDER BY s.SequenceNumber"
 	selectLeavesByMerkleHashOrderedBySequenceSQL = selectLeavesByMerkleHashSQL + orderBySequenceNumberSQL
 
+	selectExpiredQueuedLeavesSQL = `SELECT LeafIdentityHash,QueueTimestampNanos
+			FROM Unsequenced
+			WHERE TreeId=?
+			AND Bucket=0
+			AND QueueTimestampNanos<?
+			ORDER BY QueueTimestampNanos,LeafIdentityHash ASC LIMIT ?`
+	deleteExpiredUnsequencedSQL = "DELETE FROM Unsequenced WHERE TreeId=? AND Bucket=0 AND QueueTimestampNanos=? AND LeafIdentityHash=?"
+	// Only delete LeafData that hasn't been sequenced; SequencedLeafData rows
+	// reference it and would otherwise be removed by the cascade.
+	deleteExpiredLeafDataSQL = `DELETE FROM LeafData
+			WHERE TreeId=? AND LeafIdentityHash=?
+			AND NOT EXISTS(SELECT 1 FROM SequencedLeafData s WHERE s.TreeId=? AND s.LeafIdentityHash=?)`
+
 	// Error code returned by driver when inserting a duplicate row
 	errNumDuplicate = 1062
 
@@ -234,6 +248,26 @@ func (t *readOnlyLogTX) GetActiveLogIDs(ctx context.Context) ([]int64, error) {
 	return ids, rows.Err()
 }
 
+func (t *readOnlyLogTX) GetUnsequencedCounts(ctx context.Context) (storage.CountByLogID, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	rows, err := t.tx.QueryContext(ctx, selectUnsequencedLeafCountSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	ret := make(storage.CountByLogID)
+	for rows.Next() {
+		var logID, count int64
+		if err := rows.Scan(&logID, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan row from unsequenced counts: %v", err)
+		}
+		ret[logID] = count
+	}
+	return ret, rows.Err()
+}
+
 func (m *mySQLLogStorage) beginInternal(ctx context.Context, tree *trillian.Tree) (storage.LogTreeTX, error) {
 	once.Do(func() {
 		createMetrics(m.metricFactory)
@@ -437,6 +471,54 @@ func (t *logTreeTX) DequeueLeaves(ctx context.Context, limit int, cutoffTime tim
 	return leaves, nil
 }
 
+func (t *logTreeTX) ExpireQueuedLeaves(ctx context.Context, limit int, cutoff time.Time) (int, error) {
+	t.treeTX.mu.Lock()
+	defer t.treeTX.mu.Unlock()
+
+	rows, err := t.tx.QueryContext(ctx, selectExpiredQueuedLeavesSQL, t.treeID, cutoff.UnixNano(), limit)
+	if err != nil {
+		glog.Warningf("Failed to select expired leaves: %s", err)
+		return 0, err
+	}
+	type queuedLeaf struct {
+		leafIdentityHash    []byte
+		queueTimestampNanos int64
+	}
+	var expired []queuedLeaf
+	for rows.Next() {
+		var ql queuedLeaf
+		if err := rows.Scan(&ql.leafIdentityHash, &ql.queueTimestampNanos); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		expired = append(expired, ql)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return 0, err
+	}
+	rows.Close()
+
+	removed := 0
+	for _, ql := range expired {
+		result, err := t.tx.ExecContext(ctx, deleteExpiredUnsequencedSQL, t.treeID, ql.queueTimestampNanos, ql.leafIdentityHash)
+		if err != nil {
+			return removed, err
+		}
+		if n, err := result.RowsAffected(); err != nil {
+			return removed, err
+		} else if n == 0 {
+			// Dequeued by the sequencer in the meantime.
+			continue
+		}
+		if _, err := t.tx.ExecContext(ctx, deleteExpiredLeafDataSQL, t.treeID, ql.leafIdentityHash, t.treeID, ql.leafIdentityHash); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
 // sortLeavesForInsert returns a slice containing the passed in leaves sorted
 // by LeafIdentityHash, and paired with their original positions.
 // QueueLeaves and AddSequencedLeaves use this to make the order that LeafData
@@ -828,10 +910,10 @@ func (t *logTreeTX) LatestSignedLogRoot(ctx context.Context) (trillian.SignedLog
 // fetchLatestRoot reads the latest SignedLogRoot from the DB and returns it.
 func (t *logTreeTX) fetchLatestRoot(ctx context.Context) (trillian.SignedLogRoot, error) {
 	var timestamp, treeSize, treeRevision int64
-	var rootHash, rootSignatureBytes []byte
+	var rootHash, rootSignatureBytes, keyHint []byte
 	if err := t.tx.QueryRowContext(
 		ctx, selectLatestSignedLogRootSQL, t.treeID).Scan(
-		&timestamp, &treeSize, &rootHash, &treeRevision, &rootSignatureBytes,
+		&timestamp, &treeSize, &rootHash, &treeRevision, &rootSignatureBytes, &keyHint,
 	); err == sql.ErrNoRows {
 		// It's possible there are no roots for this tree yet
 		return trillian.SignedLogRoot{}, storage.ErrTreeNeedsInit
@@ -848,8 +930,12 @@ func (t *logTreeTX) fetchLatestRoot(ctx context.Context) (trillian.SignedLogRoot
 		return trillian.SignedLogRoot{}, err
 	}
 
+	// Roots stored before key rotation was supported have no key hint.
+	if len(keyHint) == 0 {
+		keyHint = types.SerializeKeyHint(t.treeID)
+	}
 	return trillian.SignedLogRoot{
-		KeyHint:          types.SerializeKeyHint(t.treeID),
+		KeyHint:          keyHint,
 		LogRoot:          logRoot,
 		LogRootSignature: rootSignatureBytes,
 	}, nil
@@ -877,7 +963,8 @@ func (t *logTreeTX) StoreSignedLogRoot(ctx context.Context, root trillian.Signed
 		logRoot.TreeSize,
 		logRoot.RootHash,
 		logRoot.Revision,
-		root.LogRootSignature)
+		root.LogRootSignature,
+		root.KeyHint)
 	if err != nil {
 		glog.Warningf("Failed to store signed root: %s", err)
 	}
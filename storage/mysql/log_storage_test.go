@@ -139,6 +139,14 @@ func TestMySQLLogStorage_CheckDatabaseAccessible(t *testing.T) {
 	}
 }
 
+func TestMysqlLogStorage(t *testing.T) {
+	tester := &testonly.LogStorageTester{NewStorage: func() (storage.LogStorage, storage.AdminStorage) {
+		cleanTestDB(DB)
+		return NewLogStorage(DB, nil), NewAdminStorage(DB)
+	}}
+	tester.RunAllTests(t)
+}
+
 func TestSnapshot(t *testing.T) {
 	cleanTestDB(DB)
 
@@ -725,6 +733,96 @@ func TestDequeueLeavesTimeOrdering(t *testing.T) {
 	}
 }
 
+func TestExpireQueuedLeaves(t *testing.T) {
+	cleanTestDB(DB)
+	tree := createTreeOrPanic(DB, testonly.LogTree)
+	s := NewLogStorage(DB, nil)
+
+	oldLeaves := createTestLeaves(leavesToInsert, 0)
+	newLeaves := createTestLeaves(leavesToInsert, leavesToInsert)
+	cutoff := fakeQueueTime.Add(-time.Minute)
+
+	runLogTX(s, tree, t, func(ctx context.Context, tx storage.LogTreeTX) error {
+		if _, err := tx.QueueLeaves(ctx, oldLeaves, fakeQueueTime.Add(-time.Hour)); err != nil {
+			t.Fatalf("QueueLeaves(old) = %v", err)
+		}
+		if _, err := tx.QueueLeaves(ctx, newLeaves, fakeQueueTime); err != nil {
+			t.Fatalf("QueueLeaves(new) = %v", err)
+		}
+		return nil
+	})
+
+	runLogTX(s, tree, t, func(ctx context.Context, tx storage.LogTreeTX) error {
+		// Expire in two batches to exercise the limit.
+		for _, want := range []int{leavesToInsert - 1, 1, 0} {
+			got, err := tx.ExpireQueuedLeaves(ctx, leavesToInsert-1, cutoff)
+			if err != nil {
+				t.Fatalf("ExpireQueuedLeaves() = (_, %v)", err)
+			}
+			if got != want {
+				t.Errorf("ExpireQueuedLeaves() = %d, want %d", got, want)
+			}
+		}
+		return nil
+	})
+
+	runLogTX(s, tree, t, func(ctx context.Context, tx storage.LogTreeTX) error {
+		// Expired leaves must not be reported as duplicates when resubmitted.
+		existing, err := tx.QueueLeaves(ctx, oldLeaves[:1], fakeQueueTime)
+		if err != nil {
+			t.Fatalf("QueueLeaves(resubmitted) = %v", err)
+		}
+		if existing[0] != nil {
+			t.Errorf("QueueLeaves(resubmitted) reported duplicate: %v", existing[0])
+		}
+		return nil
+	})
+
+	runLogTX(s, tree, t, func(ctx context.Context, tx storage.LogTreeTX) error {
+		leaves, err := tx.DequeueLeaves(ctx, 99, fakeDequeueCutoffTime)
+		if err != nil {
+			t.Fatalf("DequeueLeaves() = %v", err)
+		}
+		if got, want := len(leaves), leavesToInsert+1; got != want {
+			t.Errorf("DequeueLeaves() returned %d leaves, want %d", got, want)
+		}
+		return nil
+	})
+}
+
+func TestGetUnsequencedCounts(t *testing.T) {
+	ctx := context.Background()
+	cleanTestDB(DB)
+	tree1 := createTreeOrPanic(DB, testonly.LogTree)
+	tree2 := createTreeOrPanic(DB, testonly.LogTree)
+	s := NewLogStorage(DB, nil)
+
+	runLogTX(s, tree1, t, func(ctx context.Context, tx storage.LogTreeTX) error {
+		_, err := tx.QueueLeaves(ctx, createTestLeaves(leavesToInsert, 0), fakeQueueTime)
+		return err
+	})
+
+	tx, err := s.Snapshot(ctx)
+	if err != nil {
+		t.Fatalf("Snapshot() = (_, %v), want = (_, nil)", err)
+	}
+	defer tx.Close()
+	counts, err := tx.GetUnsequencedCounts(ctx)
+	if err != nil {
+		t.Fatalf("GetUnsequencedCounts() = (_, %v), want = (_, nil)", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Errorf("Commit() = %v, want = nil", err)
+	}
+
+	if got, want := counts[tree1.TreeId], int64(leavesToInsert); got != want {
+		t.Errorf("GetUnsequencedCounts()[%v] = %v, want %v", tree1.TreeId, got, want)
+	}
+	if got, want := counts[tree2.TreeId], int64(0); got != want {
+		t.Errorf("GetUnsequencedCounts()[%v] = %v, want %v", tree2.TreeId, got, want)
+	}
+}
+
 func TestGetLeavesByHashNotPresent(t *testing.T) {
 	cleanTestDB(DB)
 	tree := createTreeOrPanic(DB, testonly.LogTree)
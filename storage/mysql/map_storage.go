@@ -52,6 +52,28 @@ const (
  ON t1.TreeId=t2.TreeId
  AND t1.KeyHash=t2.KeyHash
  AND t1.MapRevision=t2.maxrev`
+	selectMapLeavesFromSQL = `
+ SELECT t1.KeyHash, t1.MapRevision, t1.LeafValue
+ FROM MapLeaf t1
+ INNER JOIN
+ (
+	SELECT TreeId, KeyHash, MAX(MapRevision) as maxrev
+	FROM MapLeaf t0
+	WHERE t0.TreeId = ? AND t0.KeyHash >= ? AND t0.MapRevision <= ?
+	GROUP BY t0.TreeId, t0.KeyHash
+	ORDER BY t0.KeyHash
+	LIMIT ?
+ ) t2
+ ON t1.TreeId=t2.TreeId
+ AND t1.KeyHash=t2.KeyHash
+ AND t1.MapRevision=t2.maxrev
+ ORDER BY t1.KeyHash`
+	selectChangedMapLeavesSQL = `
+ SELECT DISTINCT KeyHash
+ FROM MapLeaf
+ WHERE TreeId = ? AND KeyHash >= ? AND MapRevision > ? AND MapRevision <= ?
+ ORDER BY KeyHash
+ LIMIT ?`
 )
 
 var defaultMapStrata = []int{8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 176}
@@ -263,6 +285,112 @@ func (m *mapTreeTX) Get(ctx context.Context, revision int64, indexes [][]byte) (
 	return ret, nil
 }
 
+// ListLeaves returns up to limit of the map leaves at revision, in increasing
+// index order, starting from startIndex.
+func (m *mapTreeTX) ListLeaves(ctx context.Context, revision int64, startIndex []byte, limit int) ([]*trillian.MapLeaf, error) {
+	m.treeTX.mu.Lock()
+	defer m.treeTX.mu.Unlock()
+
+	stmt, err := m.tx.PrepareContext(ctx, selectMapLeavesFromSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	if startIndex == nil {
+		startIndex = []byte{}
+	}
+	// Each query reads the latest values of the next keys of the map, which
+	// include deleted leaves, so keep reading until the page is full or there
+	// are no more keys.
+	ret := make([]*trillian.MapLeaf, 0, limit)
+	for len(ret) < limit && startIndex != nil {
+		var leaves []*trillian.MapLeaf
+		leaves, startIndex, err = m.listLeavesFrom(ctx, stmt, revision, startIndex, limit-len(ret))
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, leaves...)
+	}
+	return ret, nil
+}
+
+// listLeavesFrom returns the non-empty map leaves at revision among the first
+// limit keys starting from startIndex, along with the index to continue from,
+// or nil if there are no more keys.
+func (m *mapTreeTX) listLeavesFrom(ctx context.Context, stmt *sql.Stmt, revision int64, startIndex []byte, limit int) ([]*trillian.MapLeaf, []byte, error) {
+	rows, err := stmt.QueryContext(ctx, m.treeID, startIndex, revision, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var ret []*trillian.MapLeaf
+	var lastKeyHash []byte
+	keys := 0
+	for rows.Next() {
+		var mapKeyHash []byte
+		var mapRevision int64
+		var flatData []byte
+		if err := rows.Scan(&mapKeyHash, &mapRevision, &flatData); err != nil {
+			return nil, nil, err
+		}
+		lastKeyHash = mapKeyHash
+		keys++
+		if len(flatData) == 0 {
+			// The leaf was deleted.
+			continue
+		}
+		var mapLeaf trillian.MapLeaf
+		if err := proto.Unmarshal(flatData, &mapLeaf); err != nil {
+			return nil, nil, err
+		}
+		mapLeaf.Index = mapKeyHash
+		ret = append(ret, &mapLeaf)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	if keys < limit {
+		return ret, nil, nil
+	}
+	// Appending a zero byte gives the smallest key after lastKeyHash.
+	return ret, append(lastKeyHash, 0), nil
+}
+
+// ListChangedIndexes returns up to limit of the indexes of the map leaves set
+// at revisions in (fromRevision, toRevision], in increasing order, starting
+// from startIndex.
+func (m *mapTreeTX) ListChangedIndexes(ctx context.Context, fromRevision, toRevision int64, startIndex []byte, limit int) ([][]byte, error) {
+	m.treeTX.mu.Lock()
+	defer m.treeTX.mu.Unlock()
+
+	stmt, err := m.tx.PrepareContext(ctx, selectChangedMapLeavesSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	if startIndex == nil {
+		startIndex = []byte{}
+	}
+	rows, err := stmt.QueryContext(ctx, m.treeID, startIndex, fromRevision, toRevision, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ret [][]byte
+	for rows.Next() {
+		var mapKeyHash []byte
+		if err := rows.Scan(&mapKeyHash); err != nil {
+			return nil, err
+		}
+		ret = append(ret, mapKeyHash)
+	}
+	return ret, rows.Err()
+}
+
 func (m *mapTreeTX) GetSignedMapRoot(ctx context.Context, revision int64) (trillian.SignedMapRoot, error) {
 	m.treeTX.mu.Lock()
 	defer m.treeTX.mu.Unlock()
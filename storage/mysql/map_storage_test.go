@@ -56,6 +56,14 @@ func TestMySQLMapStorage_CheckDatabaseAccessible(t *testing.T) {
 	}
 }
 
+func TestMysqlMapStorage(t *testing.T) {
+	tester := &storageto.MapStorageTester{NewStorage: func() (storage.MapStorage, storage.AdminStorage) {
+		cleanTestDB(DB)
+		return NewMapStorage(DB), NewAdminStorage(DB)
+	}}
+	tester.RunAllTests(t)
+}
+
 func TestMapSnapshot(t *testing.T) {
 	testdb.SkipIfNoMySQL(t)
 
@@ -22,6 +22,10 @@ CREATE TABLE IF NOT EXISTS Trees(
   PublicKey             MEDIUMBLOB NOT NULL,
   Deleted               BOOLEAN,
   DeleteTimeMillis      BIGINT,
+  QueueTTLMillis        BIGINT NOT NULL DEFAULT 0,
+  MaxQueueDepth         BIGINT NOT NULL DEFAULT 0,
+  KeyId                 BIGINT NOT NULL DEFAULT 0,
+  PreviousKeys          MEDIUMBLOB,
   PRIMARY KEY(TreeId)
 );
 
@@ -56,6 +60,7 @@ CREATE TABLE IF NOT EXISTS TreeHead(
   RootHash             VARBINARY(255) NOT NULL,
   RootSignature        VARBINARY(1024) NOT NULL,
   TreeRevision         BIGINT,
+  KeyHint              VARBINARY(255),
   PRIMARY KEY(TreeId, TreeHeadTimestamp),
   FOREIGN KEY(TreeId) REFERENCES Trees(TreeId) ON DELETE CASCADE
 );
@@ -49,7 +49,11 @@ const (
 		public_key,
 		max_root_duration_millis,
 		deleted,
-		delete_time_millis
+		delete_time_millis,
+		queue_ttl_millis,
+		max_queue_depth,
+		key_id,
+		previous_keys
 	FROM trees`
 
 	nonDeletedWhere       = " WHERE deleted = false"
@@ -73,8 +77,10 @@ const (
 		update_time_millis,
 		private_key,
 		public_key,
-		max_root_duration_millis)
-	VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+		max_root_duration_millis,
+		queue_ttl_millis,
+		max_queue_depth)
+	VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`
 
 	insertTreeControlSQL = `INSERT INTO tree_control(
 		tree_id,
@@ -84,8 +90,9 @@ const (
 	VALUES($1, $2, $3, $4)`
 
 	updateTreeSQL = `UPDATE trees SET tree_state = $1, tree_type = $2, display_name = $3, 
-		description = $4, update_time_millis = $5, max_root_duration_millis = $6, private_key = $7
-		WHERE tree_id = $8`
+		description = $4, update_time_millis = $5, max_root_duration_millis = $6, private_key = $7,
+		queue_ttl_millis = $8, max_queue_depth = $9, public_key = $10, key_id = $11, previous_keys = $12
+		WHERE tree_id = $13`
 
 	softDeleteSQL = "UPDATE trees SET deleted = $1, delete_time_millis = $2 WHERE tree_id = $3"
 
@@ -299,6 +306,10 @@ func (t *adminTX) CreateTree(ctx context.Context, tree *trillian.Tree) (*trillia
 	if err != nil {
 		return nil, fmt.Errorf("could not parse MaxRootDuration: %v", err)
 	}
+	queueTTLMillis, err := storage.QueueTTLMillis(&newTree)
+	if err != nil {
+		return nil, err
+	}
 
 	insertTreeStmt, err := t.tx.PrepareContext(ctx, insertSQL)
 	if err != nil {
@@ -326,6 +337,8 @@ func (t *adminTX) CreateTree(ctx context.Context, tree *trillian.Tree) (*trillia
 		privateKey,
 		newTree.PublicKey.GetDer(),
 		rootDuration/time.Millisecond,
+		queueTTLMillis,
+		newTree.MaxQueueDepth,
 	)
 	if err != nil {
 		return nil, err
@@ -376,11 +389,19 @@ func (t *adminTX) UpdateTree(ctx context.Context, treeID int64, updateFunc func(
 	if err != nil {
 		return nil, fmt.Errorf("could not parse MaxRootDuration: %v", err)
 	}
+	queueTTLMillis, err := storage.QueueTTLMillis(tree)
+	if err != nil {
+		return nil, err
+	}
 
 	privateKey, err := proto.Marshal(tree.PrivateKey)
 	if err != nil {
 		return nil, fmt.Errorf("could not marshal PrivateKey: %v", err)
 	}
+	previousKeys, err := storage.MarshalPreviousKeys(tree)
+	if err != nil {
+		return nil, err
+	}
 
 	stmt, err := t.tx.PrepareContext(ctx, updateTreeSQL)
 	if err != nil {
@@ -397,6 +418,11 @@ func (t *adminTX) UpdateTree(ctx context.Context, treeID int64, updateFunc func(
 		nowMillis,
 		rootDuration/time.Millisecond,
 		privateKey,
+		queueTTLMillis,
+		tree.MaxQueueDepth,
+		tree.PublicKey.GetDer(),
+		tree.KeyId,
+		previousKeys,
 		tree.TreeId); err != nil {
 		return nil, err
 	}
@@ -48,6 +48,20 @@ const (
 
 	selectSequencedLeafCountSQL   = "SELECT COUNT(*) FROM sequenced_leaf_data WHERE tree_id=$1"
 	selectUnsequencedLeafCountSQL = "SELECT tree_id, COUNT(1) FROM unsequenced GROUP BY tree_id"
+	deleteExpiredUnsequencedSQL   = `DELETE FROM unsequenced
+                        WHERE (tree_id,bucket,queue_timestamp_nanos,leaf_identity_hash) IN (
+                          SELECT tree_id,bucket,queue_timestamp_nanos,leaf_identity_hash
+                          FROM unsequenced
+                          WHERE tree_id=$1
+                          AND bucket=0
+                          AND queue_timestamp_nanos<$2
+                          ORDER BY queue_timestamp_nanos,leaf_identity_hash ASC LIMIT $3)
+                        RETURNING leaf_identity_hash`
+	// Only delete leaf_data that hasn't been sequenced; sequenced_leaf_data rows
+	// reference it and would otherwise be removed by the cascade.
+	deleteExpiredLeafDataSQL = `DELETE FROM leaf_data
+                        WHERE tree_id=$1 AND leaf_identity_hash=$2
+                        AND NOT EXISTS(SELECT 1 FROM sequenced_leaf_data s WHERE s.tree_id=$1 AND s.leaf_identity_hash=$2)`
 	//selectLatestSignedLogRootSQL  = `SELECT tree_head_timestamp,tree_size,root_hash,tree_revision,root_signature
 	//              FROM tree_head WHERE tree_id=$1
 	//              ORDER BY tree_head_timestamp DESC LIMIT 1`
@@ -435,6 +449,35 @@ func (t *logTreeTX) DequeueLeaves(ctx context.Context, limit int, cutoffTime tim
 	return leaves, nil
 }
 
+func (t *logTreeTX) ExpireQueuedLeaves(ctx context.Context, limit int, cutoff time.Time) (int, error) {
+	rows, err := t.tx.QueryContext(ctx, deleteExpiredUnsequencedSQL, t.treeID, cutoff.UnixNano(), limit)
+	if err != nil {
+		glog.Warningf("Failed to delete expired leaves: %s", err)
+		return 0, err
+	}
+	var expired [][]byte
+	for rows.Next() {
+		var leafIDHash []byte
+		if err := rows.Scan(&leafIDHash); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		expired = append(expired, leafIDHash)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return 0, err
+	}
+	rows.Close()
+
+	for _, leafIDHash := range expired {
+		if _, err := t.tx.ExecContext(ctx, deleteExpiredLeafDataSQL, t.treeID, leafIDHash); err != nil {
+			return 0, err
+		}
+	}
+	return len(expired), nil
+}
+
 // sortLeavesForInsert returns a slice containing the passed in leaves sorted
 // by LeafIdentityHash, and paired with their original positions.
 // QueueLeaves and AddSequencedLeaves use this to make the order that LeafData
@@ -817,21 +860,25 @@ func (t *logTreeTX) LatestSignedLogRoot(ctx context.Context) (trillian.SignedLog
 // fetchLatestRoot reads the latest SignedLogRoot from the DB and returns it.
 func (t *logTreeTX) fetchLatestRoot(ctx context.Context) (trillian.SignedLogRoot, error) {
 	//	var timestamp, treeSize, treeRevision int64
-	var rootSignatureBytes []byte
+	var rootSignatureBytes, keyHint []byte
 	var jsonObj []byte
 
 	t.tx.QueryRowContext(
 		ctx,
-		"select current_tree_data,root_signature from trees where tree_id = $1",
-		t.treeID).Scan(&jsonObj, &rootSignatureBytes)
+		"select current_tree_data,root_signature,root_key_hint from trees where tree_id = $1",
+		t.treeID).Scan(&jsonObj, &rootSignatureBytes, &keyHint)
 	if jsonObj == nil { //this fixes the createtree workflow
 		return trillian.SignedLogRoot{}, storage.ErrTreeNeedsInit
 	}
 	var logRoot types.LogRootV1
 	json.Unmarshal(jsonObj, &logRoot)
 	newRoot, _ := logRoot.MarshalBinary()
+	// Roots stored before key rotation was supported have no key hint.
+	if len(keyHint) == 0 {
+		keyHint = types.SerializeKeyHint(t.treeID)
+	}
 	return trillian.SignedLogRoot{
-		KeyHint:          types.SerializeKeyHint(t.treeID),
+		KeyHint:          keyHint,
 		LogRoot:          newRoot,
 		LogRootSignature: rootSignatureBytes,
 	}, nil
@@ -851,9 +898,10 @@ func (t *logTreeTX) StoreSignedLogRoot(ctx context.Context, root trillian.Signed
 	data, _ := json.Marshal(logRoot)
 	t.tx.ExecContext(
 		ctx,
-		"update trees set current_tree_data = $1,root_signature = $2 where tree_id = $3",
+		"update trees set current_tree_data = $1,root_signature = $2,root_key_hint = $3 where tree_id = $4",
 		data,
 		root.LogRootSignature,
+		root.KeyHint,
 		t.treeID)
 	res, err := t.tx.ExecContext(
 		ctx,
@@ -863,7 +911,8 @@ func (t *logTreeTX) StoreSignedLogRoot(ctx context.Context, root trillian.Signed
 		logRoot.TreeSize,
 		logRoot.RootHash,
 		logRoot.Revision,
-		root.LogRootSignature)
+		root.LogRootSignature,
+		root.KeyHint)
 	if err != nil {
 		glog.Warningf("Failed to store signed root: %s", err)
 	}
@@ -135,6 +135,14 @@ func TestMySQLLogStorage_CheckDatabaseAccessible(t *testing.T) {
 	}
 }
 
+func TestPgLogStorage(t *testing.T) {
+	tester := &testonly.LogStorageTester{NewStorage: func() (storage.LogStorage, storage.AdminStorage) {
+		cleanTestDB(db, t)
+		return NewLogStorage(db, nil), NewAdminStorage(db)
+	}}
+	tester.RunAllTests(t)
+}
+
 func TestSnapshot(t *testing.T) {
 	cleanTestDB(db, t)
 
@@ -719,6 +727,63 @@ func TestDequeueLeavesTimeOrdering(t *testing.T) {
 	}
 }
 
+func TestExpireQueuedLeaves(t *testing.T) {
+	cleanTestDB(db, t)
+	tree := createTreeOrPanic(db, testonly.LogTree)
+	s := NewLogStorage(db, nil)
+
+	oldLeaves := createTestLeaves(leavesToInsert, 0)
+	newLeaves := createTestLeaves(leavesToInsert, leavesToInsert)
+	cutoff := fakeQueueTime.Add(-time.Minute)
+
+	runLogTX(s, tree, t, func(ctx context.Context, tx storage.LogTreeTX) error {
+		if _, err := tx.QueueLeaves(ctx, oldLeaves, fakeQueueTime.Add(-time.Hour)); err != nil {
+			t.Fatalf("QueueLeaves(old) = %v", err)
+		}
+		if _, err := tx.QueueLeaves(ctx, newLeaves, fakeQueueTime); err != nil {
+			t.Fatalf("QueueLeaves(new) = %v", err)
+		}
+		return nil
+	})
+
+	runLogTX(s, tree, t, func(ctx context.Context, tx storage.LogTreeTX) error {
+		// Expire in two batches to exercise the limit.
+		for _, want := range []int{leavesToInsert - 1, 1, 0} {
+			got, err := tx.ExpireQueuedLeaves(ctx, leavesToInsert-1, cutoff)
+			if err != nil {
+				t.Fatalf("ExpireQueuedLeaves() = (_, %v)", err)
+			}
+			if got != want {
+				t.Errorf("ExpireQueuedLeaves() = %d, want %d", got, want)
+			}
+		}
+		return nil
+	})
+
+	runLogTX(s, tree, t, func(ctx context.Context, tx storage.LogTreeTX) error {
+		// Expired leaves must not be reported as duplicates when resubmitted.
+		existing, err := tx.QueueLeaves(ctx, oldLeaves[:1], fakeQueueTime)
+		if err != nil {
+			t.Fatalf("QueueLeaves(resubmitted) = %v", err)
+		}
+		if existing[0] != nil {
+			t.Errorf("QueueLeaves(resubmitted) reported duplicate: %v", existing[0])
+		}
+		return nil
+	})
+
+	runLogTX(s, tree, t, func(ctx context.Context, tx storage.LogTreeTX) error {
+		leaves, err := tx.DequeueLeaves(ctx, 99, fakeDequeueCutoffTime)
+		if err != nil {
+			t.Fatalf("DequeueLeaves() = %v", err)
+		}
+		if got, want := len(leaves), leavesToInsert+1; got != want {
+			t.Errorf("DequeueLeaves() returned %d leaves, want %d", got, want)
+		}
+		return nil
+	})
+}
+
 func TestGetLeavesByHashNotPresent(t *testing.T) {
 	cleanTestDB(db, t)
 	tree := createTreeOrPanic(db, testonly.LogTree)
@@ -0,0 +1,466 @@
+// Copyright 2018 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian"
+	"github.com/google/trillian/merkle/hashers"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/cache"
+	"github.com/google/trillian/types"
+)
+
+const (
+	insertMapHeadSQL = `INSERT INTO map_head(tree_id, map_head_timestamp, root_hash, map_revision, root_signature, mapper_data)
+	VALUES($1, $2, $3, $4, $5, $6)`
+	selectLatestSignedMapRootSQL = `SELECT map_head_timestamp, root_hash, map_revision, root_signature, mapper_data
+		 FROM map_head WHERE tree_id=$1
+		 ORDER BY map_head_timestamp DESC LIMIT 1`
+	selectGetSignedMapRootSQL = `SELECT map_head_timestamp, root_hash, map_revision, root_signature, mapper_data
+		 FROM map_head WHERE tree_id=$1 AND map_revision=$2`
+	insertMapLeafSQL = `INSERT INTO map_leaf(tree_id, key_hash, map_revision, leaf_value) VALUES ($1, $2, $3, $4)`
+	selectMapLeafSQL = `
+		SELECT t1.key_hash, t1.map_revision, t1.leaf_value
+		FROM map_leaf t1
+		INNER JOIN (
+			SELECT tree_id, key_hash, max(map_revision) AS max_revision
+			FROM map_leaf t0
+			WHERE t0.key_hash IN (` + placeholderSQL + `) AND
+			t0.tree_id = <param> AND t0.map_revision <= <param>
+			GROUP BY t0.tree_id, t0.key_hash
+		) t2
+		ON t1.tree_id = t2.tree_id
+		AND t1.key_hash = t2.key_hash
+		AND t1.map_revision = t2.max_revision`
+	selectMapLeavesFromSQL = `
+		SELECT t1.key_hash, t1.map_revision, t1.leaf_value
+		FROM map_leaf t1
+		INNER JOIN (
+			SELECT tree_id, key_hash, max(map_revision) AS max_revision
+			FROM map_leaf t0
+			WHERE t0.tree_id = $1 AND t0.key_hash >= $2 AND t0.map_revision <= $3
+			GROUP BY t0.tree_id, t0.key_hash
+			ORDER BY t0.key_hash
+			LIMIT $4
+		) t2
+		ON t1.tree_id = t2.tree_id
+		AND t1.key_hash = t2.key_hash
+		AND t1.map_revision = t2.max_revision
+		ORDER BY t1.key_hash`
+	selectChangedMapLeavesSQL = `
+		SELECT DISTINCT key_hash
+		FROM map_leaf
+		WHERE tree_id = $1 AND key_hash >= $2 AND map_revision > $3 AND map_revision <= $4
+		ORDER BY key_hash
+		LIMIT $5`
+)
+
+var defaultMapStrata = []int{8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 176}
+
+type postgresMapStorage struct {
+	*pgTreeStorage
+	admin storage.AdminStorage
+}
+
+// NewMapStorage creates a storage.MapStorage instance for the specified PostgreSQL URL.
+// It assumes storage.AdminStorage is backed by the same PostgreSQL database as well.
+func NewMapStorage(db *sql.DB) storage.MapStorage {
+	return &postgresMapStorage{
+		admin:         NewAdminStorage(db),
+		pgTreeStorage: newTreeStorage(db),
+	}
+}
+
+func (m *postgresMapStorage) CheckDatabaseAccessible(ctx context.Context) error {
+	return m.db.PingContext(ctx)
+}
+
+func (m *postgresMapStorage) getMapLeafStmt(ctx context.Context, num int) (*sql.Stmt, error) {
+	stmt := &statementSkeleton{
+		sql:               selectMapLeafSQL,
+		firstInsertion:    "%s",
+		firstPlaceholders: 1,
+		restInsertion:     "%s",
+		restPlaceholders:  1,
+		num:               num,
+	}
+	return m.getStmt(ctx, stmt)
+}
+
+func (m *postgresMapStorage) begin(ctx context.Context, tree *trillian.Tree, readonly bool) (storage.MapTreeTX, error) {
+	hasher, err := hashers.NewMapHasher(tree.HashStrategy)
+	if err != nil {
+		return nil, err
+	}
+
+	stCache := cache.NewMapSubtreeCache(defaultMapStrata, tree.TreeId, hasher)
+	ttx, err := m.beginTreeTx(ctx, tree, hasher.Size(), stCache)
+	if err != nil {
+		return nil, err
+	}
+
+	mtx := &mapTreeTX{
+		treeTX:       ttx,
+		ms:           m,
+		readRevision: -1,
+	}
+
+	if readonly {
+		// readRevision will be set later, by the first
+		// GetSignedMapRoot/LatestSignedMapRoot operation.
+		return mtx, nil
+	}
+
+	// A read-write transaction needs to know the current revision
+	// so it can write at revision+1.
+	root, err := mtx.LatestSignedMapRoot(ctx)
+	if err != nil && err != storage.ErrTreeNeedsInit {
+		mtx.Close()
+		return nil, err
+	}
+	if err == storage.ErrTreeNeedsInit {
+		return mtx, err
+	}
+
+	var mr types.MapRootV1
+	if err := mr.UnmarshalBinary(root.MapRoot); err != nil {
+		mtx.Close()
+		return nil, err
+	}
+
+	mtx.readRevision = int64(mr.Revision)
+	mtx.treeTX.writeRevision = int64(mr.Revision) + 1
+	return mtx, nil
+}
+
+func (m *postgresMapStorage) SnapshotForTree(ctx context.Context, tree *trillian.Tree) (storage.ReadOnlyMapTreeTX, error) {
+	return m.begin(ctx, tree, true /* readonly */)
+}
+
+func (m *postgresMapStorage) ReadWriteTransaction(ctx context.Context, tree *trillian.Tree, f storage.MapTXFunc) error {
+	tx, err := m.begin(ctx, tree, false /* readonly */)
+	if tx != nil {
+		defer tx.Close()
+	}
+	if err != nil && err != storage.ErrTreeNeedsInit {
+		return err
+	}
+	if err := f(ctx, tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+type mapTreeTX struct {
+	treeTX
+	ms           *postgresMapStorage
+	readRevision int64
+}
+
+func (m *mapTreeTX) ReadRevision(ctx context.Context) (int64, error) {
+	m.treeTX.mu.Lock()
+	defer m.treeTX.mu.Unlock()
+
+	return m.readRevision, nil
+}
+
+func (m *mapTreeTX) WriteRevision(ctx context.Context) (int64, error) {
+	m.treeTX.mu.Lock()
+	defer m.treeTX.mu.Unlock()
+
+	if m.treeTX.writeRevision < 0 {
+		return m.treeTX.writeRevision, errors.New("mapTreeTX write revision not populated")
+	}
+	return m.treeTX.writeRevision, nil
+}
+
+func (m *mapTreeTX) Set(ctx context.Context, keyHash []byte, value trillian.MapLeaf) error {
+	m.treeTX.mu.Lock()
+	defer m.treeTX.mu.Unlock()
+
+	flatValue, err := proto.Marshal(&value)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := m.tx.PrepareContext(ctx, insertMapLeafSQL)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	_, err = stmt.ExecContext(ctx, m.treeID, keyHash, m.writeRevision, flatValue)
+	return err
+}
+
+// Get returns a list of map leaves indicated by indexes.
+// If an index is not found, no corresponding entry is returned.
+// Each MapLeaf.Index is overwritten with the index the leaf was found at.
+func (m *mapTreeTX) Get(ctx context.Context, revision int64, indexes [][]byte) ([]*trillian.MapLeaf, error) {
+	m.treeTX.mu.Lock()
+	defer m.treeTX.mu.Unlock()
+
+	// If no indexes are requested, return an empty set.
+	if len(indexes) == 0 {
+		return []*trillian.MapLeaf{}, nil
+	}
+	stmt, err := m.ms.getMapLeafStmt(ctx, len(indexes))
+	if err != nil {
+		return nil, err
+	}
+	stx := m.tx.StmtContext(ctx, stmt)
+	defer stx.Close()
+
+	args := make([]interface{}, 0, len(indexes)+2)
+	for _, index := range indexes {
+		args = append(args, index)
+	}
+	args = append(args, m.treeID)
+	args = append(args, revision)
+
+	rows, err := stx.QueryContext(ctx, args...)
+	// It's possible there are no values for any of these keys yet
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ret := make([]*trillian.MapLeaf, 0, len(indexes))
+	for rows.Next() {
+		var mapKeyHash []byte
+		var mapRevision int64
+		var flatData []byte
+		if err := rows.Scan(&mapKeyHash, &mapRevision, &flatData); err != nil {
+			return nil, err
+		}
+		if len(flatData) == 0 {
+			continue
+		}
+		var mapLeaf trillian.MapLeaf
+		if err := proto.Unmarshal(flatData, &mapLeaf); err != nil {
+			return nil, err
+		}
+		mapLeaf.Index = mapKeyHash
+		ret = append(ret, &mapLeaf)
+	}
+	return ret, rows.Err()
+}
+
+// ListLeaves returns up to limit of the map leaves at revision, in increasing
+// index order, starting from startIndex.
+func (m *mapTreeTX) ListLeaves(ctx context.Context, revision int64, startIndex []byte, limit int) ([]*trillian.MapLeaf, error) {
+	m.treeTX.mu.Lock()
+	defer m.treeTX.mu.Unlock()
+
+	stmt, err := m.tx.PrepareContext(ctx, selectMapLeavesFromSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	if startIndex == nil {
+		startIndex = []byte{}
+	}
+	// Each query reads the latest values of the next keys of the map, which
+	// include deleted leaves, so keep reading until the page is full or there
+	// are no more keys.
+	ret := make([]*trillian.MapLeaf, 0, limit)
+	for len(ret) < limit && startIndex != nil {
+		var leaves []*trillian.MapLeaf
+		leaves, startIndex, err = m.listLeavesFrom(ctx, stmt, revision, startIndex, limit-len(ret))
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, leaves...)
+	}
+	return ret, nil
+}
+
+// listLeavesFrom returns the non-empty map leaves at revision among the first
+// limit keys starting from startIndex, along with the index to continue from,
+// or nil if there are no more keys.
+func (m *mapTreeTX) listLeavesFrom(ctx context.Context, stmt *sql.Stmt, revision int64, startIndex []byte, limit int) ([]*trillian.MapLeaf, []byte, error) {
+	rows, err := stmt.QueryContext(ctx, m.treeID, startIndex, revision, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var ret []*trillian.MapLeaf
+	var lastKeyHash []byte
+	keys := 0
+	for rows.Next() {
+		var mapKeyHash []byte
+		var mapRevision int64
+		var flatData []byte
+		if err := rows.Scan(&mapKeyHash, &mapRevision, &flatData); err != nil {
+			return nil, nil, err
+		}
+		lastKeyHash = mapKeyHash
+		keys++
+		if len(flatData) == 0 {
+			// The leaf was deleted.
+			continue
+		}
+		var mapLeaf trillian.MapLeaf
+		if err := proto.Unmarshal(flatData, &mapLeaf); err != nil {
+			return nil, nil, err
+		}
+		mapLeaf.Index = mapKeyHash
+		ret = append(ret, &mapLeaf)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	if keys < limit {
+		return ret, nil, nil
+	}
+	// Appending a zero byte gives the smallest key after lastKeyHash.
+	return ret, append(lastKeyHash, 0), nil
+}
+
+// ListChangedIndexes returns up to limit of the indexes of the map leaves set
+// at revisions in (fromRevision, toRevision], in increasing order, starting
+// from startIndex.
+func (m *mapTreeTX) ListChangedIndexes(ctx context.Context, fromRevision, toRevision int64, startIndex []byte, limit int) ([][]byte, error) {
+	m.treeTX.mu.Lock()
+	defer m.treeTX.mu.Unlock()
+
+	stmt, err := m.tx.PrepareContext(ctx, selectChangedMapLeavesSQL)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	if startIndex == nil {
+		startIndex = []byte{}
+	}
+	rows, err := stmt.QueryContext(ctx, m.treeID, startIndex, fromRevision, toRevision, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ret [][]byte
+	for rows.Next() {
+		var mapKeyHash []byte
+		if err := rows.Scan(&mapKeyHash); err != nil {
+			return nil, err
+		}
+		ret = append(ret, mapKeyHash)
+	}
+	return ret, rows.Err()
+}
+
+func (m *mapTreeTX) GetSignedMapRoot(ctx context.Context, revision int64) (trillian.SignedMapRoot, error) {
+	m.treeTX.mu.Lock()
+	defer m.treeTX.mu.Unlock()
+
+	var timestamp, mapRevision int64
+	var rootHash, rootSignatureBytes []byte
+	var mapperMetaBytes []byte
+
+	stmt, err := m.tx.PrepareContext(ctx, selectGetSignedMapRootSQL)
+	if err != nil {
+		return trillian.SignedMapRoot{}, err
+	}
+	defer stmt.Close()
+
+	err = stmt.QueryRowContext(ctx, m.treeID, revision).Scan(
+		&timestamp, &rootHash, &mapRevision, &rootSignatureBytes, &mapperMetaBytes)
+	if err != nil {
+		if revision == 0 {
+			return trillian.SignedMapRoot{}, storage.ErrTreeNeedsInit
+		}
+		return trillian.SignedMapRoot{}, err
+	}
+	m.readRevision = mapRevision
+	return m.signedMapRoot(timestamp, mapRevision, rootHash, rootSignatureBytes, mapperMetaBytes)
+}
+
+func (m *mapTreeTX) LatestSignedMapRoot(ctx context.Context) (trillian.SignedMapRoot, error) {
+	m.treeTX.mu.Lock()
+	defer m.treeTX.mu.Unlock()
+
+	var timestamp, mapRevision int64
+	var rootHash, rootSignatureBytes []byte
+	var mapperMetaBytes []byte
+
+	stmt, err := m.tx.PrepareContext(ctx, selectLatestSignedMapRootSQL)
+	if err != nil {
+		return trillian.SignedMapRoot{}, err
+	}
+	defer stmt.Close()
+
+	err = stmt.QueryRowContext(ctx, m.treeID).Scan(
+		&timestamp, &rootHash, &mapRevision, &rootSignatureBytes, &mapperMetaBytes)
+
+	// It's possible there are no roots for this tree yet
+	if err == sql.ErrNoRows {
+		return trillian.SignedMapRoot{}, storage.ErrTreeNeedsInit
+	} else if err != nil {
+		return trillian.SignedMapRoot{}, err
+	}
+	m.readRevision = mapRevision
+	return m.signedMapRoot(timestamp, mapRevision, rootHash, rootSignatureBytes, mapperMetaBytes)
+}
+
+func (m *mapTreeTX) signedMapRoot(timestamp, mapRevision int64, rootHash, rootSignature, mapperMeta []byte) (trillian.SignedMapRoot, error) {
+	mapRoot, err := (&types.MapRootV1{
+		RootHash:       rootHash,
+		TimestampNanos: uint64(timestamp),
+		Revision:       uint64(mapRevision),
+		Metadata:       mapperMeta,
+	}).MarshalBinary()
+	if err != nil {
+		return trillian.SignedMapRoot{}, err
+	}
+
+	return trillian.SignedMapRoot{
+		MapRoot:   mapRoot,
+		Signature: rootSignature,
+	}, nil
+}
+
+func (m *mapTreeTX) StoreSignedMapRoot(ctx context.Context, root trillian.SignedMapRoot) error {
+	m.treeTX.mu.Lock()
+	defer m.treeTX.mu.Unlock()
+
+	var r types.MapRootV1
+	if err := r.UnmarshalBinary(root.MapRoot); err != nil {
+		return err
+	}
+
+	stmt, err := m.tx.PrepareContext(ctx, insertMapHeadSQL)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	res, err := stmt.ExecContext(ctx, m.treeID, int64(r.TimestampNanos), r.RootHash, int64(r.Revision), root.Signature, r.Metadata)
+	if err != nil {
+		glog.Warningf("Failed to store signed map root: %s", err)
+	}
+
+	return checkResultOkAndRowCountIs(res, err, 1)
+}
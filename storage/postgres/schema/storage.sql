@@ -28,8 +28,13 @@ CREATE TABLE IF NOT EXISTS trees (
   public_key               BYTEA NOT NULL,
   deleted                  BOOLEAN NOT NULL DEFAULT FALSE,
   delete_time_millis       BIGINT,
+  queue_ttl_millis         BIGINT NOT NULL DEFAULT 0,
+  max_queue_depth          BIGINT NOT NULL DEFAULT 0,
+  key_id                   BIGINT NOT NULL DEFAULT 0,
+  previous_keys            BYTEA,
   current_tree_data	   json,
   root_signature	   BYTEA,
+  root_key_hint            BYTEA,
   PRIMARY KEY(tree_id)
 );--end
 
@@ -62,6 +67,7 @@ CREATE TABLE IF NOT EXISTS tree_head(
   root_hash              BYTEA NOT NULL,
   root_signature         BYTEA NOT NULL,
   tree_revision          BIGINT,
+  key_hint               BYTEA,
   PRIMARY KEY(tree_id, tree_revision),
   FOREIGN KEY(tree_id) REFERENCES trees(tree_id) ON DELETE CASCADE
 );--end
@@ -142,6 +148,34 @@ CREATE TABLE IF NOT EXISTS unsequenced(
   PRIMARY KEY (tree_id, bucket, queue_timestamp_nanos, leaf_identity_hash)
 );--end
 
+-- ---------------------------------------------
+-- Map specific stuff here
+-- ---------------------------------------------
+
+CREATE TABLE IF NOT EXISTS map_leaf(
+  tree_id               BIGINT NOT NULL,
+  key_hash              BYTEA NOT NULL,
+  map_revision          BIGINT NOT NULL,
+  leaf_value            BYTEA NOT NULL,
+  PRIMARY KEY(tree_id, key_hash, map_revision),
+  FOREIGN KEY(tree_id) REFERENCES trees(tree_id) ON DELETE CASCADE
+);--end
+
+CREATE TABLE IF NOT EXISTS map_head(
+  tree_id               BIGINT NOT NULL,
+  map_head_timestamp    BIGINT,
+  root_hash             BYTEA NOT NULL,
+  map_revision          BIGINT,
+  root_signature        BYTEA NOT NULL,
+  mapper_data           BYTEA,
+  PRIMARY KEY(tree_id, map_head_timestamp),
+  FOREIGN KEY(tree_id) REFERENCES trees(tree_id) ON DELETE CASCADE
+);--end
+
+-- The MapHeadRevisionIdx is used to enforce that there is only one SMR at any
+-- map revision
+CREATE UNIQUE INDEX MapHeadRevisionIdx ON map_head(tree_id, map_revision);--end
+
 CREATE OR REPLACE FUNCTION public.insert_leaf_data_ignore_duplicates(tree_id bigint, leaf_identity_hash bytea, leaf_value bytea, extra_data bytea, queue_timestamp_nanos bigint)
  RETURNS boolean
  LANGUAGE plpgsql
@@ -51,8 +51,8 @@ const (
 		ON subtree.subtree_id = x.subtree_id
 		AND subtree.subtree_revision = x.max_revision
 		AND subtree.tree_id = <param>`
-	insertTreeHeadSQL = `INSERT INTO tree_head(tree_id,tree_head_timestamp,tree_size,root_hash,tree_revision,root_signature)
-                 VALUES($1,$2,$3,$4,$5,$6)`
+	insertTreeHeadSQL = `INSERT INTO tree_head(tree_id,tree_head_timestamp,tree_size,root_hash,tree_revision,root_signature,key_hint)
+                 VALUES($1,$2,$3,$4,$5,$6,$7)`
 )
 
 // pgTreeStorage contains the pgLogStorage implementation.
@@ -199,6 +199,7 @@ func (p *pgTreeStorage) beginTreeTx(ctx context.Context, tree *trillian.Tree, ha
 	}
 	return treeTX{
 		tx:            t,
+		mu:            &sync.Mutex{},
 		ts:            p,
 		treeID:        tree.TreeId,
 		treeType:      tree.TreeType,
@@ -209,6 +210,8 @@ func (p *pgTreeStorage) beginTreeTx(ctx context.Context, tree *trillian.Tree, ha
 }
 
 type treeTX struct {
+	// mu ensures that tx can only be used for one query/exec at a time.
+	mu            *sync.Mutex
 	closed        bool
 	tx            *sql.Tx
 	ts            *pgTreeStorage
@@ -367,6 +370,9 @@ func (t *treeTX) storeSubtrees(ctx context.Context, subtrees []*storagepb.Subtre
 }
 
 func (t *treeTX) Commit() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	if t.writeRevision > -1 {
 		if err := t.subtreeCache.Flush(func(st []*storagepb.SubtreeProto) error {
 			return t.storeSubtrees(context.TODO(), st)
@@ -384,6 +390,13 @@ func (t *treeTX) Commit() error {
 }
 
 func (t *treeTX) Rollback() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.rollbackInternal()
+}
+
+func (t *treeTX) rollbackInternal() error {
 	t.closed = true
 	if err := t.tx.Rollback(); err != nil {
 		glog.Warningf("TX rollback error: %s, stack:\n%s", err, string(debug.Stack()))
@@ -393,8 +406,11 @@ func (t *treeTX) Rollback() error {
 }
 
 func (t *treeTX) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	if !t.closed {
-		err := t.Rollback()
+		err := t.rollbackInternal()
 		if err != nil {
 			glog.Warningf("Rollback error on Close(): %v", err)
 		}
@@ -404,10 +420,16 @@ func (t *treeTX) Close() error {
 }
 
 func (t *treeTX) GetMerkleNodes(ctx context.Context, treeRevision int64, nodeIDs []storage.NodeID) ([]storage.Node, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	return t.subtreeCache.GetNodes(nodeIDs, t.getSubtreesAtRev(ctx, treeRevision))
 }
 
 func (t *treeTX) SetMerkleNodes(ctx context.Context, nodes []storage.Node) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	for _, n := range nodes {
 		err := t.subtreeCache.SetNodeHash(n.NodeID, n.Hash,
 			func(nID storage.NodeID) (*storagepb.SubtreeProto, error) {
@@ -421,6 +443,9 @@ func (t *treeTX) SetMerkleNodes(ctx context.Context, nodes []storage.Node) error
 }
 
 func (t *treeTX) IsOpen() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
 	return !t.closed
 }
 
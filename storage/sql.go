@@ -24,6 +24,8 @@ import (
 	"github.com/golang/protobuf/ptypes/any"
 	"github.com/google/trillian"
 	"github.com/google/trillian/crypto/keyspb"
+	"github.com/google/trillian/storage/storagepb"
+
 	spb "github.com/google/trillian/crypto/sigpb"
 )
 
@@ -44,6 +46,32 @@ func SetNullStringIfValid(src sql.NullString, dest *string) {
 	}
 }
 
+// QueueTTLMillis returns the tree's queue TTL in milliseconds, as stored in
+// the Trees table. An unset QueueTtl is treated as zero (no expiry).
+func QueueTTLMillis(tree *trillian.Tree) (int64, error) {
+	if tree.QueueTtl == nil {
+		return 0, nil
+	}
+	ttl, err := ptypes.Duration(tree.QueueTtl)
+	if err != nil {
+		return 0, fmt.Errorf("could not parse QueueTtl: %v", err)
+	}
+	return int64(ttl / time.Millisecond), nil
+}
+
+// MarshalPreviousKeys returns the tree's previous keys serialized as stored in
+// the Trees table, or nil if the tree has none.
+func MarshalPreviousKeys(tree *trillian.Tree) ([]byte, error) {
+	if len(tree.PreviousKeys) == 0 {
+		return nil, nil
+	}
+	b, err := proto.Marshal(&storagepb.TreeKeys{Keys: tree.PreviousKeys})
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal PreviousKeys: %v", err)
+	}
+	return b, nil
+}
+
 // Row defines a common interface between sql.Row and sql.Rows(!)
 type Row interface {
 	Scan(dest ...interface{}) error
@@ -60,6 +88,8 @@ func ReadTree(row Row) (*trillian.Tree, error) {
 	var privateKey, publicKey []byte
 	var deleted sql.NullBool
 	var deleteMillis sql.NullInt64
+	var queueTTLMillis, maxQueueDepth int64
+	var previousKeys []byte
 	err := row.Scan(
 		&tree.TreeId,
 		&treeState,
@@ -76,6 +106,10 @@ func ReadTree(row Row) (*trillian.Tree, error) {
 		&maxRootDurationMillis,
 		&deleted,
 		&deleteMillis,
+		&queueTTLMillis,
+		&maxQueueDepth,
+		&tree.KeyId,
+		&previousKeys,
 	)
 	if err != nil {
 		return nil, err
@@ -133,12 +167,23 @@ func ReadTree(row Row) (*trillian.Tree, error) {
 		return nil, fmt.Errorf("failed to parse update time: %v", err)
 	}
 	tree.MaxRootDuration = ptypes.DurationProto(time.Duration(maxRootDurationMillis * int64(time.Millisecond)))
+	if queueTTLMillis > 0 {
+		tree.QueueTtl = ptypes.DurationProto(time.Duration(queueTTLMillis * int64(time.Millisecond)))
+	}
+	tree.MaxQueueDepth = maxQueueDepth
 
 	tree.PrivateKey = &any.Any{}
 	if err := proto.Unmarshal(privateKey, tree.PrivateKey); err != nil {
 		return nil, fmt.Errorf("could not unmarshal PrivateKey: %v", err)
 	}
 	tree.PublicKey = &keyspb.PublicKey{Der: publicKey}
+	if len(previousKeys) > 0 {
+		var keys storagepb.TreeKeys
+		if err := proto.Unmarshal(previousKeys, &keys); err != nil {
+			return nil, fmt.Errorf("could not unmarshal PreviousKeys: %v", err)
+		}
+		tree.PreviousKeys = keys.Keys
+	}
 
 	tree.Deleted = deleted.Valid && deleted.Bool
 	if tree.Deleted && deleteMillis.Valid {
@@ -6,6 +6,7 @@ package storagepb
 import (
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
+	trillian "github.com/google/trillian"
 	math "math"
 )
 
@@ -158,35 +159,79 @@ func (m *SubtreeProto) GetInternalNodeCount() uint32 {
 	return 0
 }
 
+// TreeKeys is the serialized form of Tree.previous_keys. It's used only for
+// persistence in storage.
+type TreeKeys struct {
+	Keys                 []*trillian.TreeKey `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *TreeKeys) Reset()         { *m = TreeKeys{} }
+func (m *TreeKeys) String() string { return proto.CompactTextString(m) }
+func (*TreeKeys) ProtoMessage()    {}
+func (*TreeKeys) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0d2c4ccf1453ffdb, []int{2}
+}
+
+func (m *TreeKeys) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TreeKeys.Unmarshal(m, b)
+}
+func (m *TreeKeys) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TreeKeys.Marshal(b, m, deterministic)
+}
+func (m *TreeKeys) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TreeKeys.Merge(m, src)
+}
+func (m *TreeKeys) XXX_Size() int {
+	return xxx_messageInfo_TreeKeys.Size(m)
+}
+func (m *TreeKeys) XXX_DiscardUnknown() {
+	xxx_messageInfo_TreeKeys.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TreeKeys proto.InternalMessageInfo
+
+func (m *TreeKeys) GetKeys() []*trillian.TreeKey {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*NodeIDProto)(nil), "storagepb.NodeIDProto")
 	proto.RegisterType((*SubtreeProto)(nil), "storagepb.SubtreeProto")
 	proto.RegisterMapType((map[string][]byte)(nil), "storagepb.SubtreeProto.InternalNodesEntry")
 	proto.RegisterMapType((map[string][]byte)(nil), "storagepb.SubtreeProto.LeavesEntry")
+	proto.RegisterType((*TreeKeys)(nil), "storagepb.TreeKeys")
 }
 
 func init() { proto.RegisterFile("storage.proto", fileDescriptor_0d2c4ccf1453ffdb) }
 
 var fileDescriptor_0d2c4ccf1453ffdb = []byte{
-	// 308 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x91, 0x4d, 0x4b, 0xc3, 0x40,
-	0x10, 0x86, 0x49, 0xd3, 0x06, 0x3b, 0x6d, 0xfc, 0x58, 0x45, 0x96, 0x7a, 0x09, 0x15, 0x24, 0x78,
-	0xc8, 0x41, 0x2f, 0x7e, 0x5c, 0xc4, 0x0f, 0xb0, 0x50, 0x44, 0xd7, 0x1f, 0x10, 0x36, 0x66, 0x34,
-	0xc1, 0xb0, 0x1b, 0x76, 0x37, 0xc5, 0xfe, 0x11, 0x7f, 0xaf, 0x64, 0xb3, 0x48, 0x44, 0x3c, 0x78,
-	0x9b, 0x77, 0xe6, 0x9d, 0x27, 0x93, 0x77, 0x21, 0xd4, 0x46, 0x2a, 0xfe, 0x86, 0x49, 0xad, 0xa4,
-	0x91, 0x64, 0xec, 0x64, 0x9d, 0xcd, 0x17, 0x30, 0x79, 0x90, 0x39, 0x2e, 0x6e, 0x1f, 0xed, 0x84,
-	0xc0, 0xb0, 0xe6, 0xa6, 0xa0, 0x5e, 0xe4, 0xc5, 0x53, 0x66, 0x6b, 0x72, 0x04, 0x5b, 0xb5, 0xc2,
-	0xd7, 0xf2, 0x23, 0xad, 0x50, 0xa4, 0x59, 0x69, 0x34, 0x1d, 0x44, 0x5e, 0x3c, 0x62, 0x61, 0xd7,
-	0x5e, 0xa2, 0xb8, 0x2e, 0x8d, 0x9e, 0x7f, 0xfa, 0x30, 0x7d, 0x6e, 0x32, 0xa3, 0x10, 0x3b, 0xd8,
-	0x3e, 0x04, 0x9d, 0xc3, 0xe1, 0x9c, 0x22, 0x7b, 0x30, 0xca, 0xb1, 0x36, 0x85, 0xc3, 0x74, 0x82,
-	0x1c, 0xc0, 0x58, 0x49, 0x69, 0xd2, 0x82, 0xeb, 0x82, 0xfa, 0x76, 0x61, 0xa3, 0x6d, 0xdc, 0x73,
-	0x5d, 0x90, 0x4b, 0x08, 0x2a, 0xe4, 0x2b, 0xd4, 0x74, 0x18, 0xf9, 0xf1, 0xe4, 0xe4, 0x30, 0xf9,
-	0xfe, 0x85, 0xa4, 0xff, 0xcd, 0x64, 0x69, 0x5d, 0x77, 0xc2, 0xa8, 0x35, 0x73, 0x2b, 0xe4, 0x09,
-	0x36, 0x4b, 0x61, 0x50, 0x09, 0x5e, 0xa5, 0x42, 0xe6, 0xa8, 0xe9, 0xc8, 0x42, 0x8e, 0xff, 0x82,
-	0x2c, 0x9c, 0xbb, 0x4d, 0xc6, 0xb1, 0xc2, 0xb2, 0xdf, 0x23, 0x09, 0xec, 0xfe, 0x40, 0xa6, 0x2f,
-	0xb2, 0x11, 0x86, 0x06, 0x91, 0x17, 0x87, 0x6c, 0xa7, 0xef, 0xbd, 0x69, 0x07, 0xb3, 0x73, 0x98,
-	0xf4, 0x2e, 0x23, 0xdb, 0xe0, 0xbf, 0xe3, 0xda, 0xc6, 0x32, 0x66, 0x6d, 0xd9, 0x66, 0xb2, 0xe2,
-	0x55, 0x83, 0x36, 0x93, 0x29, 0xeb, 0xc4, 0xc5, 0xe0, 0xcc, 0x9b, 0x5d, 0x01, 0xf9, 0x7d, 0xcf,
-	0x7f, 0x08, 0x59, 0x60, 0x5f, 0xfd, 0xf4, 0x2b, 0x00, 0x00, 0xff, 0xff, 0xf4, 0xae, 0x38, 0x20,
-	0x06, 0x02, 0x00, 0x00,
+	// 344 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x92, 0x4f, 0x4b, 0xeb, 0x40,
+	0x14, 0xc5, 0x49, 0xd3, 0x86, 0xf6, 0xb6, 0xe9, 0x7b, 0x9d, 0xf7, 0x78, 0x84, 0xbe, 0x4d, 0xa8,
+	0x28, 0xc1, 0x45, 0x40, 0xdd, 0xf8, 0x67, 0x23, 0xfe, 0x01, 0x8b, 0x45, 0x34, 0xba, 0x0f, 0x13,
+	0x7b, 0x35, 0x43, 0xc3, 0x4c, 0x98, 0x99, 0x16, 0xf3, 0x45, 0xfc, 0xbc, 0x92, 0xc9, 0x50, 0x22,
+	0xe2, 0xc2, 0xdd, 0x9c, 0x7b, 0xcf, 0xfd, 0xe5, 0xe6, 0x70, 0xc1, 0x57, 0x5a, 0x48, 0xfa, 0x8a,
+	0x71, 0x29, 0x85, 0x16, 0x64, 0x60, 0x65, 0x99, 0x4d, 0xc7, 0x5a, 0xb2, 0xa2, 0x60, 0x94, 0x37,
+	0xad, 0xd9, 0x1c, 0x86, 0x77, 0x62, 0x89, 0xf3, 0xab, 0x7b, 0xe3, 0x24, 0xd0, 0x2d, 0xa9, 0xce,
+	0x03, 0x27, 0x74, 0xa2, 0x51, 0x62, 0xde, 0x64, 0x0f, 0x7e, 0x95, 0x12, 0x5f, 0xd8, 0x5b, 0x5a,
+	0x20, 0x4f, 0x33, 0xa6, 0x55, 0xd0, 0x09, 0x9d, 0xa8, 0x97, 0xf8, 0x4d, 0x79, 0x81, 0xfc, 0x82,
+	0x69, 0x35, 0x7b, 0x77, 0x61, 0xf4, 0xb8, 0xce, 0xb4, 0x44, 0x6c, 0x60, 0xff, 0xc0, 0x6b, 0x1c,
+	0x16, 0x67, 0x15, 0xf9, 0x0b, 0xbd, 0x25, 0x96, 0x3a, 0xb7, 0x98, 0x46, 0x90, 0xff, 0x30, 0x90,
+	0x42, 0xe8, 0x34, 0xa7, 0x2a, 0x0f, 0x5c, 0x33, 0xd0, 0xaf, 0x0b, 0x37, 0x54, 0xe5, 0xe4, 0x0c,
+	0xbc, 0x02, 0xe9, 0x06, 0x55, 0xd0, 0x0d, 0xdd, 0x68, 0x78, 0xb8, 0x13, 0x6f, 0x7f, 0x29, 0x6e,
+	0x7f, 0x33, 0x5e, 0x18, 0xd7, 0x35, 0xd7, 0xb2, 0x4a, 0xec, 0x08, 0x79, 0x80, 0x31, 0xe3, 0x1a,
+	0x25, 0xa7, 0x45, 0xca, 0xc5, 0x12, 0x55, 0xd0, 0x33, 0x90, 0xfd, 0xef, 0x20, 0x73, 0xeb, 0xae,
+	0x93, 0xb1, 0x2c, 0x9f, 0xb5, 0x6b, 0x24, 0x86, 0x3f, 0x9f, 0x90, 0xe9, 0xb3, 0x58, 0x73, 0x1d,
+	0x78, 0xa1, 0x13, 0xf9, 0xc9, 0xa4, 0xed, 0xbd, 0xac, 0x1b, 0xd3, 0x13, 0x18, 0xb6, 0x36, 0x23,
+	0xbf, 0xc1, 0x5d, 0x61, 0x65, 0x62, 0x19, 0x24, 0xf5, 0xb3, 0xce, 0x64, 0x43, 0x8b, 0x35, 0x9a,
+	0x4c, 0x46, 0x49, 0x23, 0x4e, 0x3b, 0xc7, 0xce, 0xf4, 0x1c, 0xc8, 0xd7, 0x7d, 0x7e, 0x42, 0x98,
+	0x1d, 0x40, 0xff, 0x49, 0x22, 0xde, 0x62, 0xa5, 0xc8, 0x2e, 0x74, 0x57, 0x58, 0xa9, 0xc0, 0x31,
+	0x09, 0x4c, 0xe2, 0xed, 0x39, 0x58, 0x47, 0x62, 0xda, 0x99, 0x67, 0xae, 0xe3, 0xe8, 0x63, 0x00,
+	0x6a, 0x26, 0xe6, 0xbe, 0x49, 0x02, 0x00, 0x00,
 }
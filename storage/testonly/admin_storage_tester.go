@@ -198,6 +198,10 @@ func (tester *AdminStorageTester) TestCreateTree(t *testing.T) {
 	validTreeWithoutOptionals.DisplayName = ""
 	validTreeWithoutOptionals.Description = ""
 
+	validTreeWithQueueLimits := *LogTree
+	validTreeWithQueueLimits.QueueTtl = ptypes.DurationProto(24 * time.Hour)
+	validTreeWithQueueLimits.MaxQueueDepth = 100
+
 	tests := []struct {
 		desc    string
 		tree    *trillian.Tree
@@ -224,6 +228,10 @@ func (tester *AdminStorageTester) TestCreateTree(t *testing.T) {
 			desc: "validTreeWithoutOptionals",
 			tree: &validTreeWithoutOptionals,
 		},
+		{
+			desc: "validTreeWithQueueLimits",
+			tree: &validTreeWithQueueLimits,
+		},
 	}
 
 	ctx := context.Background()
@@ -287,10 +295,14 @@ func (tester *AdminStorageTester) TestUpdateTree(t *testing.T) {
 	validLog.TreeState = trillian.TreeState_FROZEN
 	validLog.DisplayName = "Frozen Tree"
 	validLog.Description = "A Frozen Tree"
+	validLog.QueueTtl = ptypes.DurationProto(1 * time.Hour)
+	validLog.MaxQueueDepth = 1000
 	validLogFunc := func(tree *trillian.Tree) {
 		tree.TreeState = validLog.TreeState
 		tree.DisplayName = validLog.DisplayName
 		tree.Description = validLog.Description
+		tree.QueueTtl = validLog.QueueTtl
+		tree.MaxQueueDepth = validLog.MaxQueueDepth
 	}
 
 	validLogWithoutOptionalsFunc := func(tree *trillian.Tree) {
@@ -333,6 +345,20 @@ func (tester *AdminStorageTester) TestUpdateTree(t *testing.T) {
 		})
 	}
 
+	keyRotatedTree := *LogTree
+	keyRotatedTree.PrivateKey = testonly.MustMarshalAny(t, &keyspb.PrivateKey{
+		Der: ktestonly.MustMarshalPrivatePEMToDER(testonly.DemoPrivateKey, testonly.DemoPrivateKeyPass),
+	})
+	keyRotatedTree.PublicKey = &keyspb.PublicKey{Der: ktestonly.MustMarshalPublicPEMToDER(testonly.DemoPublicKey)}
+	keyRotatedTree.KeyId = 1
+	keyRotatedTree.PreviousKeys = []*trillian.TreeKey{{KeyId: 0, PublicKey: LogTree.PublicKey}}
+	keyRotatedFunc := func(tree *trillian.Tree) {
+		tree.PrivateKey = keyRotatedTree.PrivateKey
+		tree.PublicKey = keyRotatedTree.PublicKey
+		tree.KeyId = keyRotatedTree.KeyId
+		tree.PreviousKeys = keyRotatedTree.PreviousKeys
+	}
+
 	// Test for an unknown tree outside the loop: it makes the test logic simpler
 	if _, err := storage.UpdateTree(ctx, s, -1, func(tree *trillian.Tree) {}); err == nil {
 		t.Error("UpdateTree() for treeID -1 returned nil err")
@@ -386,6 +412,12 @@ func (tester *AdminStorageTester) TestUpdateTree(t *testing.T) {
 			updateFunc: privateKeyChangedAndKeyMaterialDifferentFunc,
 			wantErr:    true,
 		},
+		{
+			desc:       "keyRotated",
+			create:     &referenceLog,
+			updateFunc: keyRotatedFunc,
+			want:       &keyRotatedTree,
+		},
 	}
 	for _, test := range tests {
 		createdTree, err := storage.CreateTree(ctx, s, test.create)
@@ -0,0 +1,574 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testonly
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/trillian"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	fakeQueueTime     = time.Date(2016, 11, 10, 15, 16, 27, 0, time.UTC)
+	fakeIntegrateTime = time.Date(2016, 11, 10, 15, 16, 30, 0, time.UTC)
+)
+
+// LogStorageTester runs a suite of tests against LogStorage implementations.
+type LogStorageTester struct {
+	// NewStorage returns a LogStorage, and an AdminStorage sharing its
+	// database, both pointing to a clean test database.
+	NewStorage func() (storage.LogStorage, storage.AdminStorage)
+}
+
+// RunAllTests runs all LogStorage tests.
+func (tester *LogStorageTester) RunAllTests(t *testing.T) {
+	t.Run("TestQueueLeaves", tester.TestQueueLeaves)
+	t.Run("TestQueueDuplicateLeaves", tester.TestQueueDuplicateLeaves)
+	t.Run("TestDequeueLeaves", tester.TestDequeueLeaves)
+	t.Run("TestDequeueLeavesGuardInterval", tester.TestDequeueLeavesGuardInterval)
+	t.Run("TestSequencedLeaves", tester.TestSequencedLeaves)
+	t.Run("TestGetLeavesErrors", tester.TestGetLeavesErrors)
+	t.Run("TestAddSequencedLeaves", tester.TestAddSequencedLeaves)
+	t.Run("TestLatestSignedLogRoot", tester.TestLatestSignedLogRoot)
+	t.Run("TestDuplicateSignedLogRoot", tester.TestDuplicateSignedLogRoot)
+}
+
+// TestQueueLeaves tests that queued leaves are counted, and can be dequeued
+// with their queue timestamp.
+func (tester *LogStorageTester) TestQueueLeaves(t *testing.T) {
+	ctx := context.Background()
+	s, as := tester.NewStorage()
+	tree := createLogForTests(ctx, t, s, as, LogTree)
+
+	leaves := createTestLeaves(10, 0)
+	queueLeavesOrFail(ctx, t, s, tree, leaves, fakeQueueTime)
+
+	tx, err := s.Snapshot(ctx)
+	if err != nil {
+		t.Fatalf("Snapshot() = %v", err)
+	}
+	defer tx.Close()
+	counts, err := tx.GetUnsequencedCounts(ctx)
+	if err != nil {
+		t.Fatalf("GetUnsequencedCounts() = %v", err)
+	}
+	if got, want := counts[tree.TreeId], int64(len(leaves)); got != want {
+		t.Errorf("GetUnsequencedCounts()[%v] = %v, want %v", tree.TreeId, got, want)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Errorf("Commit() = %v", err)
+	}
+
+	runLogTX(ctx, t, s, tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		dequeued, err := tx.DequeueLeaves(ctx, 99, fakeQueueTime)
+		if err != nil {
+			t.Fatalf("DequeueLeaves() = %v", err)
+		}
+		checkLeavesByIdentityHash(t, "DequeueLeaves()", dequeued, leaves)
+		for i, leaf := range dequeued {
+			if ts, err := ptypes.Timestamp(leaf.QueueTimestamp); err != nil || !ts.Equal(fakeQueueTime) {
+				t.Errorf("DequeueLeaves()[%d].QueueTimestamp = %v, want %v", i, leaf.QueueTimestamp, fakeQueueTime)
+			}
+		}
+		return nil
+	})
+}
+
+// TestQueueDuplicateLeaves tests that queueing a leaf with the identity hash
+// of an existing leaf returns the existing leaf, with an AlreadyExists status.
+func (tester *LogStorageTester) TestQueueDuplicateLeaves(t *testing.T) {
+	ctx := context.Background()
+	s, as := tester.NewStorage()
+	tree := createLogForTests(ctx, t, s, as, LogTree)
+
+	leaves := createTestLeaves(3, 0)
+	queueLeavesOrFail(ctx, t, s, tree, leaves, fakeQueueTime)
+
+	// [dup, new, dup, new]
+	newLeaves := createTestLeaves(2, 10)
+	dup := createTestLeaves(3, 0)[2]
+	dup.ExtraData = []byte("different extra data")
+	batch := []*trillian.LogLeaf{createTestLeaves(1, 0)[0], newLeaves[0], dup, newLeaves[1]}
+	want := []*trillian.LogLeaf{leaves[0], nil, leaves[2], nil}
+
+	res, err := s.QueueLeaves(ctx, tree, batch, fakeQueueTime.Add(time.Second))
+	if err != nil {
+		t.Fatalf("QueueLeaves() = %v", err)
+	}
+	if got, want := len(res), len(batch); got != want {
+		t.Fatalf("QueueLeaves() returned %d results, want %d", got, want)
+	}
+	for i, r := range res {
+		if want[i] == nil {
+			if got := status.FromProto(r.Status).Code(); got != codes.OK {
+				t.Errorf("QueueLeaves()[%d].Status = %v, want code %v", i, r.Status, codes.OK)
+			}
+			continue
+		}
+		if got := status.FromProto(r.Status).Code(); got != codes.AlreadyExists {
+			t.Errorf("QueueLeaves()[%d].Status = %v, want code %v", i, r.Status, codes.AlreadyExists)
+		}
+		if r.Leaf == nil || !bytes.Equal(r.Leaf.LeafIdentityHash, want[i].LeafIdentityHash) || !bytes.Equal(r.Leaf.ExtraData, want[i].ExtraData) {
+			t.Errorf("QueueLeaves()[%d].Leaf = %v, want existing leaf %v", i, r.Leaf, want[i])
+		}
+	}
+
+	// Only the new leaves were added to the queue.
+	runLogTX(ctx, t, s, tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		dequeued, err := tx.DequeueLeaves(ctx, 99, fakeQueueTime.Add(time.Minute))
+		if err != nil {
+			t.Fatalf("DequeueLeaves() = %v", err)
+		}
+		checkLeavesByIdentityHash(t, "DequeueLeaves()", dequeued, append(leaves, newLeaves...))
+		return nil
+	})
+}
+
+// TestDequeueLeaves tests that leaves are dequeued in queue timestamp order,
+// up to the limit, and that committed dequeued leaves are not returned again.
+func (tester *LogStorageTester) TestDequeueLeaves(t *testing.T) {
+	ctx := context.Background()
+	s, as := tester.NewStorage()
+	tree := createLogForTests(ctx, t, s, as, LogTree)
+
+	leaves := createTestLeaves(2, 0)
+	queueLeavesOrFail(ctx, t, s, tree, leaves, fakeQueueTime)
+	// These are one second earlier so should be dequeued first.
+	leaves2 := createTestLeaves(2, 2)
+	queueLeavesOrFail(ctx, t, s, tree, leaves2, fakeQueueTime.Add(-time.Second))
+
+	for _, want := range [][]*trillian.LogLeaf{leaves2, leaves, nil} {
+		runLogTX(ctx, t, s, tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+			dequeued, err := tx.DequeueLeaves(ctx, 2, fakeQueueTime)
+			if err != nil {
+				t.Fatalf("DequeueLeaves() = %v", err)
+			}
+			checkLeavesByIdentityHash(t, "DequeueLeaves()", dequeued, want)
+			return nil
+		})
+	}
+}
+
+// TestDequeueLeavesGuardInterval tests that leaves queued after the cutoff
+// time are not dequeued.
+func (tester *LogStorageTester) TestDequeueLeavesGuardInterval(t *testing.T) {
+	ctx := context.Background()
+	s, as := tester.NewStorage()
+	tree := createLogForTests(ctx, t, s, as, LogTree)
+
+	leaves := createTestLeaves(3, 0)
+	queueLeavesOrFail(ctx, t, s, tree, leaves, fakeQueueTime)
+
+	runLogTX(ctx, t, s, tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		dequeued, err := tx.DequeueLeaves(ctx, 99, fakeQueueTime.Add(-time.Second))
+		if err != nil {
+			t.Fatalf("DequeueLeaves() = %v", err)
+		}
+		if len(dequeued) != 0 {
+			t.Errorf("DequeueLeaves() returned %d leaves in the guard interval, want 0", len(dequeued))
+		}
+		dequeued, err = tx.DequeueLeaves(ctx, 99, fakeQueueTime.Add(time.Second))
+		if err != nil {
+			t.Fatalf("DequeueLeaves() = %v", err)
+		}
+		checkLeavesByIdentityHash(t, "DequeueLeaves()", dequeued, leaves)
+		return nil
+	})
+}
+
+// TestSequencedLeaves tests that leaves integrated by UpdateSequencedLeaves
+// can be read back by index, range and Merkle leaf hash.
+func (tester *LogStorageTester) TestSequencedLeaves(t *testing.T) {
+	ctx := context.Background()
+	s, as := tester.NewStorage()
+	tree := createLogForTests(ctx, t, s, as, LogTree)
+
+	// The first and last leaves have the same Merkle leaf hash.
+	leaves := createTestLeaves(5, 0)
+	leaves[4].MerkleLeafHash = leaves[0].MerkleLeafHash
+	queueLeavesOrFail(ctx, t, s, tree, leaves, fakeQueueTime)
+	sequenceLeaves(ctx, t, s, tree)
+
+	tx, err := s.SnapshotForTree(ctx, tree)
+	if err != nil {
+		t.Fatalf("SnapshotForTree() = %v", err)
+	}
+	defer tx.Close()
+
+	if got, err := tx.GetSequencedLeafCount(ctx); err != nil || got != int64(len(leaves)) {
+		t.Errorf("GetSequencedLeafCount() = (%v, %v), want (%v, nil)", got, err, len(leaves))
+	}
+
+	// The leaves were sequenced in an unspecified order, so find out which
+	// leaf went where.
+	byRange, err := tx.GetLeavesByRange(ctx, 0, int64(len(leaves)))
+	if err != nil {
+		t.Fatalf("GetLeavesByRange() = %v", err)
+	}
+	if got, want := len(byRange), len(leaves); got != want {
+		t.Fatalf("GetLeavesByRange() returned %d leaves, want %d", got, want)
+	}
+	byIdentity := make(map[string]*trillian.LogLeaf)
+	for _, leaf := range leaves {
+		byIdentity[string(leaf.LeafIdentityHash)] = leaf
+	}
+	want := make([]*trillian.LogLeaf, 0, len(byRange))
+	for i, leaf := range byRange {
+		w, ok := byIdentity[string(leaf.LeafIdentityHash)]
+		if !ok {
+			t.Fatalf("GetLeavesByRange()[%d] = %v, want one of the queued leaves", i, leaf)
+		}
+		delete(byIdentity, string(leaf.LeafIdentityHash))
+		w.LeafIndex = int64(i)
+		want = append(want, w)
+		if ts, err := ptypes.Timestamp(leaf.IntegrateTimestamp); err != nil || !ts.Equal(fakeIntegrateTime) {
+			t.Errorf("GetLeavesByRange()[%d].IntegrateTimestamp = %v, want %v", i, leaf.IntegrateTimestamp, fakeIntegrateTime)
+		}
+	}
+	checkLeavesInOrder(t, "GetLeavesByRange()", byRange, want)
+
+	if got, err := tx.GetLeavesByRange(ctx, 1, 2); err != nil {
+		t.Errorf("GetLeavesByRange(1, 2) = %v", err)
+	} else {
+		checkLeavesInOrder(t, "GetLeavesByRange(1, 2)", got, byRange[1:3])
+	}
+
+	if got, err := tx.GetLeavesByIndex(ctx, []int64{3, 0}); err != nil {
+		t.Errorf("GetLeavesByIndex() = %v", err)
+	} else {
+		checkLeavesInOrder(t, "GetLeavesByIndex()", got, []*trillian.LogLeaf{byRange[3], byRange[0]})
+	}
+
+	got, err := tx.GetLeavesByHash(ctx, [][]byte{leaves[0].MerkleLeafHash}, true /* orderBySequence */)
+	if err != nil {
+		t.Fatalf("GetLeavesByHash() = %v", err)
+	}
+	checkLeavesByIdentityHash(t, "GetLeavesByHash()", got, []*trillian.LogLeaf{leaves[0], leaves[4]})
+	if len(got) == 2 && got[0].LeafIndex > got[1].LeafIndex {
+		t.Errorf("GetLeavesByHash() returned leaves %d and %d, want sequence order", got[0].LeafIndex, got[1].LeafIndex)
+	}
+	if got, err := tx.GetLeavesByHash(ctx, [][]byte{leafHash("unknown")}, false); err != nil || len(got) != 0 {
+		t.Errorf("GetLeavesByHash(unknown) = (%v, %v), want no leaves", got, err)
+	}
+}
+
+// TestGetLeavesErrors tests the errors returned for out of range reads.
+func (tester *LogStorageTester) TestGetLeavesErrors(t *testing.T) {
+	ctx := context.Background()
+	s, as := tester.NewStorage()
+	tree := createLogForTests(ctx, t, s, as, LogTree)
+	queueLeavesOrFail(ctx, t, s, tree, createTestLeaves(3, 0), fakeQueueTime)
+	sequenceLeaves(ctx, t, s, tree)
+
+	tx, err := s.SnapshotForTree(ctx, tree)
+	if err != nil {
+		t.Fatalf("SnapshotForTree() = %v", err)
+	}
+	defer tx.Close()
+
+	for _, test := range []struct {
+		start, count int64
+		want         codes.Code
+	}{
+		{start: -1, count: 1, want: codes.InvalidArgument},
+		{start: 0, count: 0, want: codes.InvalidArgument},
+		{start: 0, count: -1, want: codes.InvalidArgument},
+		{start: 3, count: 1, want: codes.OutOfRange},
+	} {
+		if _, err := tx.GetLeavesByRange(ctx, test.start, test.count); status.Code(err) != test.want {
+			t.Errorf("GetLeavesByRange(%d, %d) = %v, want code %v", test.start, test.count, err, test.want)
+		}
+	}
+	for _, test := range []struct {
+		index int64
+		want  codes.Code
+	}{
+		{index: -1, want: codes.InvalidArgument},
+		{index: 3, want: codes.OutOfRange},
+	} {
+		if _, err := tx.GetLeavesByIndex(ctx, []int64{0, test.index}); status.Code(err) != test.want {
+			t.Errorf("GetLeavesByIndex(%d) = %v, want code %v", test.index, err, test.want)
+		}
+	}
+}
+
+// TestAddSequencedLeaves tests the statuses returned by AddSequencedLeaves
+// for a PREORDERED_LOG, and that conflicting leaves are not stored.
+func (tester *LogStorageTester) TestAddSequencedLeaves(t *testing.T) {
+	ctx := context.Background()
+	s, as := tester.NewStorage()
+	tree := createLogForTests(ctx, t, s, as, PreorderedLogTree)
+
+	leaves := createTestLeaves(6, 0)
+	// Add them out of order, with a gap.
+	addSequencedLeavesOrFail(ctx, t, s, tree, []*trillian.LogLeaf{leaves[3], leaves[1], leaves[0]}, []codes.Code{codes.OK, codes.OK, codes.OK})
+
+	identityDup := createTestLeaves(1, 5)[0]
+	identityDup.LeafIdentityHash = leaves[1].LeafIdentityHash
+	indexDup := createTestLeaves(1, 10)[0]
+	indexDup.LeafIndex = 3
+	addSequencedLeavesOrFail(ctx, t, s, tree,
+		[]*trillian.LogLeaf{leaves[2], identityDup, indexDup, leaves[4]},
+		[]codes.Code{codes.OK, codes.FailedPrecondition, codes.FailedPrecondition, codes.OK})
+
+	runLogTX(ctx, t, s, tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		got, err := tx.GetLeavesByRange(ctx, 0, 10)
+		if err != nil {
+			t.Fatalf("GetLeavesByRange() = %v", err)
+		}
+		checkLeavesInOrder(t, "GetLeavesByRange()", got, leaves[:5])
+		if got, err := tx.GetSequencedLeafCount(ctx); err != nil || got != 5 {
+			t.Errorf("GetSequencedLeafCount() = (%v, %v), want (5, nil)", got, err)
+		}
+		return nil
+	})
+}
+
+// TestLatestSignedLogRoot tests that the most recently stored root is
+// returned, and that reading an uninitialized log fails.
+func (tester *LogStorageTester) TestLatestSignedLogRoot(t *testing.T) {
+	ctx := context.Background()
+	s, as := tester.NewStorage()
+	tree, err := storage.CreateTree(ctx, as, LogTree)
+	if err != nil {
+		t.Fatalf("CreateTree() = %v", err)
+	}
+
+	tx, err := s.SnapshotForTree(ctx, tree)
+	if err != storage.ErrTreeNeedsInit {
+		t.Errorf("SnapshotForTree() on uninitialized log = %v, want %v", err, storage.ErrTreeNeedsInit)
+	}
+	// Some storages return a transaction along with ErrTreeNeedsInit.
+	if tx != nil {
+		tx.Close()
+	}
+
+	roots := []*trillian.SignedLogRoot{
+		signedLogRoot(t, &types.LogRootV1{TimestampNanos: 98765, TreeSize: 16, Revision: 5, RootHash: leafHash("root5")}),
+		signedLogRoot(t, &types.LogRootV1{TimestampNanos: 98766, TreeSize: 17, Revision: 6, RootHash: leafHash("root6")}),
+	}
+	for _, root := range roots {
+		runLogTX(ctx, t, s, tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+			return tx.StoreSignedLogRoot(ctx, *root)
+		})
+	}
+
+	tx, err = s.SnapshotForTree(ctx, tree)
+	if err != nil {
+		t.Fatalf("SnapshotForTree() = %v", err)
+	}
+	defer tx.Close()
+	got, err := tx.LatestSignedLogRoot(ctx)
+	if err != nil {
+		t.Fatalf("LatestSignedLogRoot() = %v", err)
+	}
+	// Compare the signed contents only, as some storages add a KeyHint.
+	if want := roots[1]; !bytes.Equal(got.LogRoot, want.LogRoot) || !bytes.Equal(got.LogRootSignature, want.LogRootSignature) {
+		t.Errorf("LatestSignedLogRoot() = %v, want %v", got, want)
+	}
+	if rev, err := tx.ReadRevision(ctx); err != nil || rev != 6 {
+		t.Errorf("ReadRevision() = (%v, %v), want (6, nil)", rev, err)
+	}
+}
+
+// TestDuplicateSignedLogRoot tests that a root can't be stored twice.
+func (tester *LogStorageTester) TestDuplicateSignedLogRoot(t *testing.T) {
+	ctx := context.Background()
+	s, as := tester.NewStorage()
+	tree := createLogForTests(ctx, t, s, as, LogTree)
+
+	root := signedLogRoot(t, &types.LogRootV1{TimestampNanos: 98765, TreeSize: 16, Revision: 1})
+	runLogTX(ctx, t, s, tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		return tx.StoreSignedLogRoot(ctx, *root)
+	})
+	if err := s.ReadWriteTransaction(ctx, tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		return tx.StoreSignedLogRoot(ctx, *root)
+	}); err == nil {
+		t.Error("StoreSignedLogRoot() allowed a duplicate root")
+	}
+}
+
+// createLogForTests creates a new log from the template, and stores an
+// initial empty root so that leaves can be queued.
+func createLogForTests(ctx context.Context, t *testing.T, s storage.LogStorage, as storage.AdminStorage, template *trillian.Tree) *trillian.Tree {
+	t.Helper()
+	tree, err := storage.CreateTree(ctx, as, template)
+	if err != nil {
+		t.Fatalf("CreateTree() = %v", err)
+	}
+	root := signedLogRoot(t, &types.LogRootV1{TimestampNanos: 100, RootHash: leafHash("")})
+	runLogTX(ctx, t, s, tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		return tx.StoreSignedLogRoot(ctx, *root)
+	})
+	return tree
+}
+
+func runLogTX(ctx context.Context, t *testing.T, s storage.LogStorage, tree *trillian.Tree, f storage.LogTXFunc) {
+	t.Helper()
+	if err := s.ReadWriteTransaction(ctx, tree, f); err != nil {
+		t.Fatalf("ReadWriteTransaction() = %v", err)
+	}
+}
+
+func signedLogRoot(t *testing.T, root *types.LogRootV1) *trillian.SignedLogRoot {
+	t.Helper()
+	logRoot, err := root.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() = %v", err)
+	}
+	return &trillian.SignedLogRoot{LogRoot: logRoot, LogRootSignature: []byte("notempty")}
+}
+
+func leafHash(s string) []byte {
+	h := sha256.Sum256([]byte(s))
+	return h[:]
+}
+
+// createTestLeaves returns n leaves with distinct identity and Merkle leaf
+// hashes, and LeafIndex values starting at startIndex.
+func createTestLeaves(n, startIndex int64) []*trillian.LogLeaf {
+	leaves := make([]*trillian.LogLeaf, 0, n)
+	for i := startIndex; i < startIndex+n; i++ {
+		data := fmt.Sprintf("leaf %d", i)
+		leaves = append(leaves, &trillian.LogLeaf{
+			LeafIdentityHash: leafHash("identity " + data),
+			MerkleLeafHash:   leafHash(data),
+			LeafValue:        []byte(data),
+			ExtraData:        []byte("extra " + data),
+			LeafIndex:        i,
+		})
+	}
+	return leaves
+}
+
+func queueLeavesOrFail(ctx context.Context, t *testing.T, s storage.LogStorage, tree *trillian.Tree, leaves []*trillian.LogLeaf, queueTime time.Time) {
+	t.Helper()
+	res, err := s.QueueLeaves(ctx, tree, leaves, queueTime)
+	if err != nil {
+		t.Fatalf("QueueLeaves() = %v", err)
+	}
+	for i, r := range res {
+		if got := status.FromProto(r.Status).Code(); got != codes.OK {
+			t.Fatalf("QueueLeaves()[%d].Status = %v, want code %v", i, r.Status, codes.OK)
+		}
+	}
+}
+
+func addSequencedLeavesOrFail(ctx context.Context, t *testing.T, s storage.LogStorage, tree *trillian.Tree, leaves []*trillian.LogLeaf, want []codes.Code) {
+	t.Helper()
+	res, err := s.AddSequencedLeaves(ctx, tree, leaves, fakeQueueTime)
+	if err != nil {
+		t.Fatalf("AddSequencedLeaves() = %v", err)
+	}
+	if got, want := len(res), len(leaves); got != want {
+		t.Fatalf("AddSequencedLeaves() returned %d results, want %d", got, want)
+	}
+	for i, r := range res {
+		if got := status.FromProto(r.Status).Code(); got != want[i] {
+			t.Errorf("AddSequencedLeaves()[%d].Status = %v, want code %v", i, r.Status, want[i])
+		}
+	}
+}
+
+// sequenceLeaves integrates all the queued leaves into the log, like the
+// sequencer does, but without updating the Merkle tree.
+func sequenceLeaves(ctx context.Context, t *testing.T, s storage.LogStorage, tree *trillian.Tree) {
+	t.Helper()
+	integrateTimestamp, err := ptypes.TimestampProto(fakeIntegrateTime)
+	if err != nil {
+		t.Fatalf("TimestampProto() = %v", err)
+	}
+	runLogTX(ctx, t, s, tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		slr, err := tx.LatestSignedLogRoot(ctx)
+		if err != nil {
+			t.Fatalf("LatestSignedLogRoot() = %v", err)
+		}
+		var root types.LogRootV1
+		if err := root.UnmarshalBinary(slr.LogRoot); err != nil {
+			t.Fatalf("UnmarshalBinary() = %v", err)
+		}
+		leaves, err := tx.DequeueLeaves(ctx, 99, fakeIntegrateTime)
+		if err != nil {
+			t.Fatalf("DequeueLeaves() = %v", err)
+		}
+		for i, leaf := range leaves {
+			leaf.LeafIndex = int64(root.TreeSize) + int64(i)
+			leaf.IntegrateTimestamp = integrateTimestamp
+		}
+		if err := tx.UpdateSequencedLeaves(ctx, leaves); err != nil {
+			t.Fatalf("UpdateSequencedLeaves() = %v", err)
+		}
+		return tx.StoreSignedLogRoot(ctx, *signedLogRoot(t, &types.LogRootV1{
+			TimestampNanos: root.TimestampNanos + 1,
+			TreeSize:       root.TreeSize + uint64(len(leaves)),
+			Revision:       root.Revision + 1,
+			RootHash:       root.RootHash,
+		}))
+	})
+}
+
+// checkLeavesInOrder checks that got holds the same leaves as want, in the
+// same order, ignoring timestamps.
+func checkLeavesInOrder(t *testing.T, desc string, got, want []*trillian.LogLeaf) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("%s returned %d leaves, want %d", desc, len(got), len(want))
+		return
+	}
+	for i := range got {
+		g, w := got[i], want[i]
+		if g.LeafIndex != w.LeafIndex ||
+			!bytes.Equal(g.LeafIdentityHash, w.LeafIdentityHash) ||
+			!bytes.Equal(g.MerkleLeafHash, w.MerkleLeafHash) ||
+			!bytes.Equal(g.LeafValue, w.LeafValue) ||
+			!bytes.Equal(g.ExtraData, w.ExtraData) {
+			t.Errorf("%s[%d] = %v, want %v", desc, i, g, w)
+		}
+	}
+}
+
+// checkLeavesByIdentityHash checks that got holds leaves with the same
+// identity and Merkle leaf hashes as want, in any order. The other fields are
+// ignored, as DequeueLeaves doesn't have to return them.
+func checkLeavesByIdentityHash(t *testing.T, desc string, got, want []*trillian.LogLeaf) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("%s returned %d leaves, want %d", desc, len(got), len(want))
+		return
+	}
+	sorted := func(leaves []*trillian.LogLeaf) []*trillian.LogLeaf {
+		s := append([]*trillian.LogLeaf(nil), leaves...)
+		sort.Slice(s, func(i, j int) bool { return bytes.Compare(s[i].LeafIdentityHash, s[j].LeafIdentityHash) < 0 })
+		return s
+	}
+	got, want = sorted(got), sorted(want)
+	for i := range got {
+		g, w := got[i], want[i]
+		if !bytes.Equal(g.LeafIdentityHash, w.LeafIdentityHash) || !bytes.Equal(g.MerkleLeafHash, w.MerkleLeafHash) {
+			t.Errorf("%s returned %v, want %v", desc, g, w)
+		}
+	}
+}
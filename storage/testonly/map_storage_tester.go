@@ -0,0 +1,399 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package testonly
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/types"
+)
+
+// MapStorageTester runs a suite of tests against MapStorage implementations.
+type MapStorageTester struct {
+	// NewStorage returns a MapStorage, and an AdminStorage sharing its
+	// database, both pointing to a clean test database.
+	NewStorage func() (storage.MapStorage, storage.AdminStorage)
+}
+
+// RunAllTests runs all MapStorage tests.
+func (tester *MapStorageTester) RunAllTests(t *testing.T) {
+	t.Run("TestMapSetGetMultipleRevisions", tester.TestMapSetGetMultipleRevisions)
+	t.Run("TestMapSetSameKeyInSameRevisionFails", tester.TestMapSetSameKeyInSameRevisionFails)
+	t.Run("TestMapGet0Results", tester.TestMapGet0Results)
+	t.Run("TestMapListLeaves", tester.TestMapListLeaves)
+	t.Run("TestMapListChangedIndexes", tester.TestMapListChangedIndexes)
+	t.Run("TestMapUninitialized", tester.TestMapUninitialized)
+	t.Run("TestMapRoots", tester.TestMapRoots)
+	t.Run("TestDuplicateSignedMapRoot", tester.TestDuplicateSignedMapRoot)
+}
+
+// TestMapSetGetMultipleRevisions tests that Get returns the leaf values as of
+// the requested revision.
+func (tester *MapStorageTester) TestMapSetGetMultipleRevisions(t *testing.T) {
+	ctx := context.Background()
+	s, as := tester.NewStorage()
+	tree := createMapForTests(ctx, t, s, as)
+
+	index := leafHash("A Key Hash")
+	leaves := []*trillian.MapLeaf{
+		{Index: index, LeafHash: []byte{1}, LeafValue: []byte{1}, ExtraData: []byte{1}},
+		{Index: index, LeafHash: []byte{2}, LeafValue: []byte{2}, ExtraData: []byte{2}},
+		{Index: index, LeafHash: []byte{3}, LeafValue: []byte{3}, ExtraData: []byte{3}},
+	}
+	// Write each leaf at revisions 1, 2, 3 in turn.
+	for _, leaf := range leaves {
+		runMapTX(ctx, t, s, tree, func(ctx context.Context, tx storage.MapTreeTX) error {
+			if err := tx.Set(ctx, index, *leaf); err != nil {
+				t.Fatalf("Set() = %v", err)
+			}
+			storeNextMapRoot(ctx, t, tx)
+			return nil
+		})
+	}
+
+	for _, test := range []struct {
+		rev  int64
+		want *trillian.MapLeaf
+	}{
+		{rev: 0},
+		{rev: 1, want: leaves[0]},
+		{rev: 2, want: leaves[1]},
+		{rev: 3, want: leaves[2]},
+		{rev: 10, want: leaves[2]},
+	} {
+		tx, err := s.SnapshotForTree(ctx, tree)
+		if err != nil {
+			t.Fatalf("SnapshotForTree() = %v", err)
+		}
+		got, err := tx.Get(ctx, test.rev, [][]byte{index, leafHash("unknown")})
+		if err != nil {
+			t.Fatalf("Get(%d) = %v", test.rev, err)
+		}
+		if test.want == nil {
+			if len(got) != 0 {
+				t.Errorf("Get(%d) = %v, want no leaves", test.rev, got)
+			}
+		} else if len(got) != 1 || !proto.Equal(got[0], test.want) {
+			t.Errorf("Get(%d) = %v, want %v", test.rev, got, test.want)
+		}
+		tx.Close()
+	}
+}
+
+// TestMapSetSameKeyInSameRevisionFails tests that a leaf can't be set twice
+// at the same revision.
+func (tester *MapStorageTester) TestMapSetSameKeyInSameRevisionFails(t *testing.T) {
+	ctx := context.Background()
+	s, as := tester.NewStorage()
+	tree := createMapForTests(ctx, t, s, as)
+
+	index := leafHash("A Key Hash")
+	leaf := trillian.MapLeaf{Index: index, LeafValue: []byte("A Value")}
+	runMapTX(ctx, t, s, tree, func(ctx context.Context, tx storage.MapTreeTX) error {
+		if err := tx.Set(ctx, index, leaf); err != nil {
+			t.Fatalf("Set() = %v", err)
+		}
+		return nil
+	})
+	// No root was stored, so this transaction writes at the same revision.
+	if err := s.ReadWriteTransaction(ctx, tree, func(ctx context.Context, tx storage.MapTreeTX) error {
+		return tx.Set(ctx, index, leaf)
+	}); err == nil {
+		t.Error("Set() succeeded for the second time at the same revision")
+	}
+}
+
+// TestMapGet0Results tests that Get returns no leaves for no indexes, or for
+// indexes that were never set.
+func (tester *MapStorageTester) TestMapGet0Results(t *testing.T) {
+	ctx := context.Background()
+	s, as := tester.NewStorage()
+	tree := createMapForTests(ctx, t, s, as)
+
+	for _, indexes := range [][][]byte{nil, {leafHash("This doesn't exist.")}} {
+		runMapTX(ctx, t, s, tree, func(ctx context.Context, tx storage.MapTreeTX) error {
+			leaves, err := tx.Get(ctx, 1, indexes)
+			if err != nil {
+				t.Fatalf("Get(%x) = %v", indexes, err)
+			}
+			if len(leaves) != 0 {
+				t.Errorf("Get(%x) = %v, want no leaves", indexes, leaves)
+			}
+			return nil
+		})
+	}
+}
+
+// TestMapListLeaves tests that ListLeaves returns the leaves as of the
+// requested revision, in index order, one page at a time.
+func (tester *MapStorageTester) TestMapListLeaves(t *testing.T) {
+	ctx := context.Background()
+	s, as := tester.NewStorage()
+	tree := createMapForTests(ctx, t, s, as)
+
+	leaves, updated := writeListTestLeaves(ctx, t, s, tree)
+
+	for _, test := range []struct {
+		desc       string
+		rev        int64
+		startIndex []byte
+		limit      int
+		want       []*trillian.MapLeaf
+	}{
+		{desc: "rev0", rev: 0, limit: 10},
+		{desc: "rev1", rev: 1, limit: 10, want: leaves[:3]},
+		{desc: "rev2", rev: 2, limit: 10, want: []*trillian.MapLeaf{leaves[0], updated, leaves[2], leaves[3]}},
+		{desc: "first-page", rev: 2, limit: 2, want: []*trillian.MapLeaf{leaves[0], updated}},
+		{desc: "second-page", rev: 2, startIndex: leaves[2].Index, limit: 2, want: leaves[2:]},
+		{desc: "past-end", rev: 2, startIndex: bytes.Repeat([]byte{0xff}, len(leaves[0].Index)), limit: 2},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			tx, err := s.SnapshotForTree(ctx, tree)
+			if err != nil {
+				t.Fatalf("SnapshotForTree() = %v", err)
+			}
+			defer tx.Close()
+			got, err := tx.ListLeaves(ctx, test.rev, test.startIndex, test.limit)
+			if err != nil {
+				t.Fatalf("ListLeaves(%d, %x, %d) = %v", test.rev, test.startIndex, test.limit, err)
+			}
+			if len(got) != len(test.want) {
+				t.Fatalf("ListLeaves(%d, %x, %d) returned %d leaves, want %d", test.rev, test.startIndex, test.limit, len(got), len(test.want))
+			}
+			for i, leaf := range got {
+				if !proto.Equal(leaf, test.want[i]) {
+					t.Errorf("ListLeaves(%d, %x, %d)[%d] = %v, want %v", test.rev, test.startIndex, test.limit, i, leaf, test.want[i])
+				}
+			}
+		})
+	}
+}
+
+// TestMapListChangedIndexes tests that ListChangedIndexes returns the indexes
+// of the leaves set within the requested revisions, one page at a time.
+func (tester *MapStorageTester) TestMapListChangedIndexes(t *testing.T) {
+	ctx := context.Background()
+	s, as := tester.NewStorage()
+	tree := createMapForTests(ctx, t, s, as)
+	leaves, _ := writeListTestLeaves(ctx, t, s, tree)
+	var all [][]byte
+	for _, leaf := range leaves {
+		all = append(all, leaf.Index)
+	}
+
+	for _, test := range []struct {
+		desc       string
+		from, to   int64
+		startIndex []byte
+		limit      int
+		want       [][]byte
+	}{
+		{desc: "rev1", from: 0, to: 1, limit: 10, want: all[:3]},
+		{desc: "rev2", from: 1, to: 2, limit: 10, want: [][]byte{all[1], all[3]}},
+		{desc: "all", from: 0, to: 2, limit: 10, want: all},
+		{desc: "first-page", from: 0, to: 2, limit: 2, want: all[:2]},
+		{desc: "second-page", from: 0, to: 2, startIndex: all[2], limit: 2, want: all[2:]},
+		{desc: "future", from: 2, to: 5, limit: 10},
+	} {
+		t.Run(test.desc, func(t *testing.T) {
+			tx, err := s.SnapshotForTree(ctx, tree)
+			if err != nil {
+				t.Fatalf("SnapshotForTree() = %v", err)
+			}
+			defer tx.Close()
+			got, err := tx.ListChangedIndexes(ctx, test.from, test.to, test.startIndex, test.limit)
+			if err != nil {
+				t.Fatalf("ListChangedIndexes(%d, %d, %x, %d) = %v", test.from, test.to, test.startIndex, test.limit, err)
+			}
+			if len(got) != len(test.want) {
+				t.Fatalf("ListChangedIndexes(%d, %d, %x, %d) = %x, want %x", test.from, test.to, test.startIndex, test.limit, got, test.want)
+			}
+			for i := range got {
+				if !bytes.Equal(got[i], test.want[i]) {
+					t.Errorf("ListChangedIndexes(%d, %d, %x, %d) = %x, want %x", test.from, test.to, test.startIndex, test.limit, got, test.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+// TestMapUninitialized tests that reading the roots of a map without any
+// fails with ErrTreeNeedsInit.
+func (tester *MapStorageTester) TestMapUninitialized(t *testing.T) {
+	ctx := context.Background()
+	s, as := tester.NewStorage()
+	tree, err := storage.CreateTree(ctx, as, MapTree)
+	if err != nil {
+		t.Fatalf("CreateTree() = %v", err)
+	}
+
+	tx, err := s.SnapshotForTree(ctx, tree)
+	if err != nil {
+		t.Fatalf("SnapshotForTree() = %v", err)
+	}
+	defer tx.Close()
+	if _, err := tx.LatestSignedMapRoot(ctx); err != storage.ErrTreeNeedsInit {
+		t.Errorf("LatestSignedMapRoot() = %v, want %v", err, storage.ErrTreeNeedsInit)
+	}
+	if _, err := tx.GetSignedMapRoot(ctx, 0); err != storage.ErrTreeNeedsInit {
+		t.Errorf("GetSignedMapRoot(0) = %v, want %v", err, storage.ErrTreeNeedsInit)
+	}
+}
+
+// TestMapRoots tests that stored roots can be read back by revision, and that
+// the latest one is returned by LatestSignedMapRoot.
+func (tester *MapStorageTester) TestMapRoots(t *testing.T) {
+	ctx := context.Background()
+	s, as := tester.NewStorage()
+	tree := createMapForTests(ctx, t, s, as)
+
+	roots := []*trillian.SignedMapRoot{
+		signedMapRoot(t, &types.MapRootV1{TimestampNanos: 98765, Revision: 5, RootHash: leafHash("root5")}),
+		signedMapRoot(t, &types.MapRootV1{TimestampNanos: 98766, Revision: 6, RootHash: leafHash("root6")}),
+	}
+	for _, root := range roots {
+		runMapTX(ctx, t, s, tree, func(ctx context.Context, tx storage.MapTreeTX) error {
+			return tx.StoreSignedMapRoot(ctx, *root)
+		})
+	}
+
+	tx, err := s.SnapshotForTree(ctx, tree)
+	if err != nil {
+		t.Fatalf("SnapshotForTree() = %v", err)
+	}
+	defer tx.Close()
+	latest, err := tx.LatestSignedMapRoot(ctx)
+	if err != nil {
+		t.Fatalf("LatestSignedMapRoot() = %v", err)
+	}
+	if !mapRootsEqual(&latest, roots[1]) {
+		t.Errorf("LatestSignedMapRoot() = %v, want %v", latest, roots[1])
+	}
+	if rev, err := tx.ReadRevision(ctx); err != nil || rev != 6 {
+		t.Errorf("ReadRevision() = (%v, %v), want (6, nil)", rev, err)
+	}
+	got, err := tx.GetSignedMapRoot(ctx, 5)
+	if err != nil {
+		t.Fatalf("GetSignedMapRoot(5) = %v", err)
+	}
+	if !mapRootsEqual(&got, roots[0]) {
+		t.Errorf("GetSignedMapRoot(5) = %v, want %v", got, roots[0])
+	}
+	if _, err := tx.GetSignedMapRoot(ctx, 4); err == nil {
+		t.Error("GetSignedMapRoot(4) = nil, want error")
+	}
+}
+
+// TestDuplicateSignedMapRoot tests that a root can't be stored twice for the
+// same revision.
+func (tester *MapStorageTester) TestDuplicateSignedMapRoot(t *testing.T) {
+	ctx := context.Background()
+	s, as := tester.NewStorage()
+	tree := createMapForTests(ctx, t, s, as)
+
+	root := signedMapRoot(t, &types.MapRootV1{TimestampNanos: 98765, Revision: 1, RootHash: leafHash("root1")})
+	runMapTX(ctx, t, s, tree, func(ctx context.Context, tx storage.MapTreeTX) error {
+		return tx.StoreSignedMapRoot(ctx, *root)
+	})
+	if err := s.ReadWriteTransaction(ctx, tree, func(ctx context.Context, tx storage.MapTreeTX) error {
+		return tx.StoreSignedMapRoot(ctx, *root)
+	}); err == nil {
+		t.Error("StoreSignedMapRoot() allowed a duplicate root")
+	}
+}
+
+// createMapForTests creates a new map, and stores an initial root at
+// revision 0.
+func createMapForTests(ctx context.Context, t *testing.T, s storage.MapStorage, as storage.AdminStorage) *trillian.Tree {
+	t.Helper()
+	tree, err := storage.CreateTree(ctx, as, MapTree)
+	if err != nil {
+		t.Fatalf("CreateTree() = %v", err)
+	}
+	root := signedMapRoot(t, &types.MapRootV1{TimestampNanos: 100, RootHash: MapTreeEmptyRootHash})
+	runMapTX(ctx, t, s, tree, func(ctx context.Context, tx storage.MapTreeTX) error {
+		return tx.StoreSignedMapRoot(ctx, *root)
+	})
+	return tree
+}
+
+// writeListTestLeaves writes three leaves, in index order, at revision 1. At
+// revision 2 it updates the second of them, and writes a fourth leaf with the
+// largest index. It returns the four leaves as first written, and the update.
+func writeListTestLeaves(ctx context.Context, t *testing.T, s storage.MapStorage, tree *trillian.Tree) ([]*trillian.MapLeaf, *trillian.MapLeaf) {
+	t.Helper()
+	var leaves []*trillian.MapLeaf
+	for _, key := range []string{"A", "B", "C", "D"} {
+		index := leafHash(key)
+		leaves = append(leaves, &trillian.MapLeaf{Index: index, LeafHash: index, LeafValue: []byte(key)})
+	}
+	sort.Slice(leaves, func(i, j int) bool { return bytes.Compare(leaves[i].Index, leaves[j].Index) < 0 })
+	updated := &trillian.MapLeaf{Index: leaves[1].Index, LeafHash: []byte("new hash"), LeafValue: []byte("new value")}
+
+	for _, revLeaves := range [][]*trillian.MapLeaf{leaves[:3], {updated, leaves[3]}} {
+		runMapTX(ctx, t, s, tree, func(ctx context.Context, tx storage.MapTreeTX) error {
+			for _, leaf := range revLeaves {
+				if err := tx.Set(ctx, leaf.Index, *leaf); err != nil {
+					t.Fatalf("Set() = %v", err)
+				}
+			}
+			storeNextMapRoot(ctx, t, tx)
+			return nil
+		})
+	}
+	return leaves, updated
+}
+
+func runMapTX(ctx context.Context, t *testing.T, s storage.MapStorage, tree *trillian.Tree, f storage.MapTXFunc) {
+	t.Helper()
+	if err := s.ReadWriteTransaction(ctx, tree, f); err != nil {
+		t.Fatalf("ReadWriteTransaction() = %v", err)
+	}
+}
+
+func signedMapRoot(t *testing.T, root *types.MapRootV1) *trillian.SignedMapRoot {
+	t.Helper()
+	mapRoot, err := root.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() = %v", err)
+	}
+	return &trillian.SignedMapRoot{MapRoot: mapRoot, Signature: []byte("notempty")}
+}
+
+// storeNextMapRoot stores a root at the transaction's write revision, so that
+// the next transaction writes at a new revision.
+func storeNextMapRoot(ctx context.Context, t *testing.T, tx storage.MapTreeTX) {
+	t.Helper()
+	rev, err := tx.WriteRevision(ctx)
+	if err != nil {
+		t.Fatalf("WriteRevision() = %v", err)
+	}
+	root := signedMapRoot(t, &types.MapRootV1{TimestampNanos: uint64(100 + rev), Revision: uint64(rev), RootHash: leafHash("root")})
+	if err := tx.StoreSignedMapRoot(ctx, *root); err != nil {
+		t.Fatalf("StoreSignedMapRoot() = %v", err)
+	}
+}
+
+// mapRootsEqual compares the signed contents of two map roots.
+func mapRootsEqual(a, b *trillian.SignedMapRoot) bool {
+	return bytes.Equal(a.MapRoot, b.MapRoot) && bytes.Equal(a.Signature, b.Signature)
+}
@@ -54,6 +54,10 @@ func ValidateTreeForCreation(ctx context.Context, tree *trillian.Tree) error {
 		return status.Errorf(codes.InvalidArgument, "invalid deleted: %v", tree.Deleted)
 	case tree.DeleteTime != nil:
 		return status.Errorf(codes.InvalidArgument, "invalid delete_time: %+v (must be nil)", tree.DeleteTime)
+	case tree.KeyId != 0:
+		return status.Errorf(codes.InvalidArgument, "invalid key_id: %v (must be 0)", tree.KeyId)
+	case len(tree.PreviousKeys) != 0:
+		return status.Error(codes.InvalidArgument, "invalid previous_keys: must be empty")
 	}
 
 	return validateMutableTreeFields(ctx, tree)
@@ -105,16 +109,62 @@ func ValidateTreeForUpdate(ctx context.Context, storedTree, newTree *trillian.Tr
 		return status.Error(codes.InvalidArgument, "readonly field changed: create_time")
 	case !proto.Equal(storedTree.UpdateTime, newTree.UpdateTime):
 		return status.Error(codes.InvalidArgument, "readonly field changed: update_time")
-	case !proto.Equal(storedTree.PublicKey, newTree.PublicKey):
-		return status.Error(codes.InvalidArgument, "readonly field changed: public_key")
 	case storedTree.Deleted != newTree.Deleted:
 		return status.Error(codes.InvalidArgument, "readonly field changed: deleted")
 	case !proto.Equal(storedTree.DeleteTime, newTree.DeleteTime):
 		return status.Error(codes.InvalidArgument, "readonly field changed: delete_time")
 	}
+	if err := validateKeyUpdate(storedTree, newTree); err != nil {
+		return err
+	}
 	return validateMutableTreeFields(ctx, newTree)
 }
 
+// validateKeyUpdate returns nil iff the key fields of storedTree can be
+// updated to those of newTree. The public key may only change if a new key
+// version is added, i.e. key_id is incremented and the stored public key is
+// appended to previous_keys.
+func validateKeyUpdate(storedTree, newTree *trillian.Tree) error {
+	if storedTree.KeyId == newTree.KeyId {
+		switch {
+		case !proto.Equal(storedTree.PublicKey, newTree.PublicKey):
+			return status.Error(codes.InvalidArgument, "readonly field changed: public_key")
+		case !treeKeysEqual(storedTree.PreviousKeys, newTree.PreviousKeys):
+			return status.Error(codes.InvalidArgument, "readonly field changed: previous_keys")
+		}
+		return nil
+	}
+
+	if newTree.KeyId != storedTree.KeyId+1 {
+		return status.Errorf(codes.InvalidArgument, "invalid key_id: %v (want %v)", newTree.KeyId, storedTree.KeyId+1)
+	}
+	wantPrevious := append(storedTree.PreviousKeys[:len(storedTree.PreviousKeys):len(storedTree.PreviousKeys)], &trillian.TreeKey{
+		KeyId:     storedTree.KeyId,
+		PublicKey: storedTree.PublicKey,
+	})
+	if !treeKeysEqual(wantPrevious, newTree.PreviousKeys) {
+		return status.Error(codes.InvalidArgument, "previous_keys must be the stored previous_keys plus the replaced key")
+	}
+	for _, key := range wantPrevious {
+		if bytes.Equal(key.GetPublicKey().GetDer(), newTree.PublicKey.GetDer()) {
+			return status.Errorf(codes.InvalidArgument, "public_key was already used by key version %v", key.KeyId)
+		}
+	}
+	return nil
+}
+
+func treeKeysEqual(a, b []*trillian.TreeKey) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !proto.Equal(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
 func validateMutableTreeFields(ctx context.Context, tree *trillian.Tree) error {
 	if tree.TreeState == trillian.TreeState_UNKNOWN_TREE_STATE {
 		return status.Errorf(codes.InvalidArgument, "invalid tree_state: %v", tree.TreeState)
@@ -124,6 +174,16 @@ func validateMutableTreeFields(ctx context.Context, tree *trillian.Tree) error {
 	} else if duration < 0 {
 		return status.Errorf(codes.InvalidArgument, "max_root_duration negative: %v", tree.MaxRootDuration)
 	}
+	if tree.QueueTtl != nil {
+		if ttl, err := ptypes.Duration(tree.QueueTtl); err != nil {
+			return status.Errorf(codes.InvalidArgument, "queue_ttl malformed: %v", tree.QueueTtl)
+		} else if ttl < 0 {
+			return status.Errorf(codes.InvalidArgument, "queue_ttl negative: %v", tree.QueueTtl)
+		}
+	}
+	if tree.MaxQueueDepth < 0 {
+		return status.Errorf(codes.InvalidArgument, "max_queue_depth negative: %v", tree.MaxQueueDepth)
+	}
 
 	// Implementations may vary, so let's assume storage_settings is mutable.
 	// Other than checking that it's a valid Any there isn't much to do at this layer, though.
@@ -117,6 +117,12 @@ func TestValidateTreeForCreation(t *testing.T) {
 	deleteTimeTree := newTree()
 	deleteTimeTree.DeleteTime = ptypes.TimestampNow()
 
+	keyIDTree := newTree()
+	keyIDTree.KeyId = 1
+
+	previousKeysTree := newTree()
+	previousKeysTree.PreviousKeys = []*trillian.TreeKey{{PublicKey: keyIDTree.PublicKey}}
+
 	tests := []struct {
 		desc    string
 		tree    *trillian.Tree
@@ -219,6 +225,16 @@ func TestValidateTreeForCreation(t *testing.T) {
 			tree:    deleteTimeTree,
 			wantErr: true,
 		},
+		{
+			desc:    "keyIDTree",
+			tree:    keyIDTree,
+			wantErr: true,
+		},
+		{
+			desc:    "previousKeysTree",
+			tree:    previousKeysTree,
+			wantErr: true,
+		},
 	}
 	for _, test := range tests {
 		err := ValidateTreeForCreation(ctx, test.tree)
@@ -284,6 +300,27 @@ func TestValidateTreeForUpdate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			desc: "validQueueLimits",
+			updatefn: func(tree *trillian.Tree) {
+				tree.QueueTtl = ptypes.DurationProto(24 * time.Hour)
+				tree.MaxQueueDepth = 1000
+			},
+		},
+		{
+			desc: "invalidQueueTTL",
+			updatefn: func(tree *trillian.Tree) {
+				tree.QueueTtl = ptypes.DurationProto(-1 * time.Hour)
+			},
+			wantErr: true,
+		},
+		{
+			desc: "invalidMaxQueueDepth",
+			updatefn: func(tree *trillian.Tree) {
+				tree.MaxQueueDepth = -1
+			},
+			wantErr: true,
+		},
 		{
 			desc: "differentPrivateKeyProtoButSameKeyMaterial",
 			updatefn: func(tree *trillian.Tree) {
@@ -327,7 +364,51 @@ func TestValidateTreeForUpdate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			desc:     "addKeyVersion",
+			updatefn: addDemoKeyVersion,
+		},
+		{
+			desc: "addKeyVersionWrongKeyID",
+			updatefn: func(tree *trillian.Tree) {
+				addDemoKeyVersion(tree)
+				tree.KeyId++
+			},
+			wantErr: true,
+		},
+		{
+			desc: "addKeyVersionWithoutPreviousKey",
+			updatefn: func(tree *trillian.Tree) {
+				addDemoKeyVersion(tree)
+				tree.PreviousKeys = nil
+			},
+			wantErr: true,
+		},
+		{
+			desc: "addKeyVersionReusingPublicKey",
+			updatefn: func(tree *trillian.Tree) {
+				tree.PreviousKeys = []*trillian.TreeKey{{KeyId: tree.KeyId, PublicKey: tree.PublicKey}}
+				tree.KeyId++
+			},
+			wantErr: true,
+		},
 		// Changes on readonly fields
+		{
+			desc: "PublicKey",
+			updatefn: func(tree *trillian.Tree) {
+				addDemoKeyVersion(tree)
+				tree.KeyId = 0
+				tree.PreviousKeys = nil
+			},
+			wantErr: true,
+		},
+		{
+			desc: "PreviousKeys",
+			updatefn: func(tree *trillian.Tree) {
+				tree.PreviousKeys = []*trillian.TreeKey{{KeyId: 7, PublicKey: tree.PublicKey}}
+			},
+			wantErr: true,
+		},
 		{
 			desc: "TreeId",
 			updatefn: func(tree *trillian.Tree) {
@@ -436,6 +517,21 @@ func TestValidateTreeForUpdate(t *testing.T) {
 	}
 }
 
+// addDemoKeyVersion replaces the key of tree with testonly.DemoPrivateKey,
+// as a new key version.
+func addDemoKeyVersion(tree *trillian.Tree) {
+	key, err := ptypes.MarshalAny(&keyspb.PrivateKey{
+		Der: ktestonly.MustMarshalPrivatePEMToDER(testonly.DemoPrivateKey, testonly.DemoPrivateKeyPass),
+	})
+	if err != nil {
+		panic(err)
+	}
+	tree.PreviousKeys = append(tree.PreviousKeys, &trillian.TreeKey{KeyId: tree.KeyId, PublicKey: tree.PublicKey})
+	tree.KeyId++
+	tree.PrivateKey = key
+	tree.PublicKey = &keyspb.PublicKey{Der: ktestonly.MustMarshalPublicPEMToDER(testonly.DemoPublicKey)}
+}
+
 // newTree returns a valid log tree for tests.
 func newTree() *trillian.Tree {
 	privateKey, err := ptypes.MarshalAny(&keyspb.PEMKeyFile{
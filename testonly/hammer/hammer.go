@@ -183,7 +183,7 @@ func HitMap(cfg MapConfig) error {
 				glog.Errorf("note: leaving ephemeral tree %d intact after error %v", cfg.MapID, firstErr)
 				return
 			}
-			if err := destroyMap(ctx, cfg.Admin, cfg.MapID); err != nil {
+			if err := destroyTree(ctx, cfg.Admin, cfg.MapID); err != nil {
 				glog.Errorf("failed to destroy map with treeID %d: %v", cfg.MapID, err)
 			}
 		}()
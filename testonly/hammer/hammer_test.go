@@ -21,12 +21,21 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/trillian"
+	"github.com/google/trillian/client"
+	"github.com/google/trillian/extension"
 	"github.com/google/trillian/monitoring"
+	"github.com/google/trillian/quota"
+	"github.com/google/trillian/storage/memory"
 	"github.com/google/trillian/storage/testdb"
 	"github.com/google/trillian/testonly/integration"
+	"github.com/google/trillian/types"
 
-	_ "github.com/google/trillian/merkle/coniks"    // register CONIKS_SHA512_256
-	_ "github.com/google/trillian/merkle/maphasher" // register TEST_MAP_HASHER
+	stestonly "github.com/google/trillian/storage/testonly"
+
+	_ "github.com/google/trillian/crypto/keys/der/proto" // Register PrivateKey ProtoHandler
+	_ "github.com/google/trillian/merkle/coniks"         // register CONIKS_SHA512_256
+	_ "github.com/google/trillian/merkle/maphasher"      // register TEST_MAP_HASHER
 )
 
 var (
@@ -79,3 +88,84 @@ func TestInProcessMapHammer(t *testing.T) {
 		t.Fatalf("hammer failure: %v", err)
 	}
 }
+
+func TestInProcessLogHammer(t *testing.T) {
+	ctx := context.Background()
+	ts := memory.NewTreeStorage()
+	registry := extension.Registry{
+		AdminStorage: memory.NewAdminStorage(ts),
+		LogStorage:   memory.NewLogStorage(ts, nil),
+		QuotaManager: quota.Noop(),
+	}
+	env, err := integration.NewLogEnvWithRegistry(ctx, 1, registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.Close()
+
+	bias := LogBias{
+		Bias: map[LogEntrypointName]int{
+			QueueLeavesName:         10,
+			AddSequencedLeavesName:  2,
+			GetLeavesByRangeName:    10,
+			GetInclusionProofName:   10,
+			GetConsistencyProofName: 10,
+			GetSLRName:              10,
+		},
+		InvalidChance: map[LogEntrypointName]int{
+			QueueLeavesName:         10,
+			AddSequencedLeavesName:  10,
+			GetLeavesByRangeName:    10,
+			GetInclusionProofName:   10,
+			GetConsistencyProofName: 10,
+			GetSLRName:              0,
+		},
+	}
+
+	tree, err := client.CreateAndInitTree(ctx, &trillian.CreateTreeRequest{Tree: stestonly.LogTree}, env.Admin, nil, env.Log)
+	if err != nil {
+		t.Fatalf("Failed to create log: %v", err)
+	}
+	logID := tree.TreeId
+
+	seed := time.Now().UTC().UnixNano() & 0xFFFFFFFF
+	cfg := LogConfig{
+		LogID:         logID,
+		Client:        env.Log,
+		Admin:         env.Admin,
+		MetricFactory: monitoring.InertMetricFactory{},
+		RandSource:    rand.NewSource(seed),
+		EPBias:        bias,
+		LeafSize:      100,
+		MinLeaves:     1,
+		MaxLeaves:     20,
+		Operations:    *operations * 5,
+	}
+	if err := HitLog(cfg); err != nil {
+		t.Fatalf("hammer failure (seed %#x): %v", seed, err)
+	}
+
+	// Wait for the queued leaves to be sequenced, and hammer the log again so
+	// that there is some content to check.
+	for i := 0; ; i++ {
+		if i > 20 {
+			t.Fatalf("log %d not sequenced after %d attempts", logID, i)
+		}
+		rsp, err := env.Log.GetLatestSignedLogRoot(ctx, &trillian.GetLatestSignedLogRootRequest{LogId: logID})
+		if err != nil {
+			t.Fatalf("GetLatestSignedLogRoot(): %v", err)
+		}
+		var root types.LogRootV1
+		if err := root.UnmarshalBinary(rsp.GetSignedLogRoot().GetLogRoot()); err != nil {
+			t.Fatalf("UnmarshalBinary(): %v", err)
+		}
+		if root.TreeSize > 0 {
+			break
+		}
+		time.Sleep(integration.SequencerInterval)
+	}
+	cfg.RandSource = rand.NewSource(seed + 1)
+	if err := HitLog(cfg); err != nil {
+		t.Fatalf("hammer failure (seed %#x): %v", seed+1, err)
+	}
+}
@@ -0,0 +1,776 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hammer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/google/trillian"
+	"github.com/google/trillian/client"
+	"github.com/google/trillian/merkle"
+	"github.com/google/trillian/monitoring"
+	"github.com/google/trillian/testonly"
+	"github.com/google/trillian/types"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	// Maximum number of leaves to fetch per request when catching up with the log.
+	logSyncBatch = 1000
+)
+
+var (
+	// Log metrics are all per-log (label "logid"), and per-entrypoint (label "ep").
+	logOnce        sync.Once
+	logReqs        monitoring.Counter   // logid, ep => value
+	logErrs        monitoring.Counter   // logid, ep => value
+	logRsps        monitoring.Counter   // logid, ep => value
+	logRspLatency  monitoring.Histogram // logid, ep => distribution-of-values
+	logInvalidReqs monitoring.Counter   // logid, ep => value
+)
+
+// setupLogMetrics initializes all the exported log metrics.
+func setupLogMetrics(mf monitoring.MetricFactory) {
+	logReqs = mf.NewCounter("log_reqs", "Number of valid log requests sent", "logid", "ep")
+	logErrs = mf.NewCounter("log_errs", "Number of error responses received for valid log requests", "logid", "ep")
+	logRsps = mf.NewCounter("log_rsps", "Number of responses received for valid log requests", "logid", "ep")
+	logRspLatency = mf.NewHistogram("log_rsp_latency", "Latency of responses received for valid log requests in seconds", "logid", "ep")
+	logInvalidReqs = mf.NewCounter("log_invalid_reqs", "Number of deliberately-invalid log requests sent", "logid", "ep")
+}
+
+// LogEntrypointName identifies a Log RPC entrypoint
+type LogEntrypointName string
+
+// Constants for log entrypoint names, as exposed in statistics/logging.
+const (
+	QueueLeavesName         = LogEntrypointName("QueueLeaves")
+	AddSequencedLeavesName  = LogEntrypointName("AddSequencedLeaves")
+	GetLeavesByRangeName    = LogEntrypointName("GetLeavesByRange")
+	GetInclusionProofName   = LogEntrypointName("GetInclusionProof")
+	GetConsistencyProofName = LogEntrypointName("GetConsistencyProof")
+	GetSLRName              = LogEntrypointName("GetSLR")
+)
+
+var logEntrypoints = []LogEntrypointName{QueueLeavesName, AddSequencedLeavesName, GetLeavesByRangeName, GetInclusionProofName, GetConsistencyProofName, GetSLRName}
+
+// Constants for invalid log operation choices.
+const (
+	EmptyLeaves     = Choice("EmptyLeaves")
+	EmptyValue      = Choice("EmptyValue")
+	IndexGap        = Choice("IndexGap")
+	IndexIsNegative = Choice("IndexIsNegative")
+	IndexTooBig     = Choice("IndexTooBig")
+	CountIsZero     = Choice("CountIsZero")
+	SizeIsZero      = Choice("SizeIsZero")
+	SizesReversed   = Choice("SizesReversed")
+	WrongTreeType   = Choice("WrongTreeType")
+)
+
+// LogBias indicates the bias for selecting different log operations.
+type LogBias struct {
+	Bias  map[LogEntrypointName]int
+	total int
+	// InvalidChance gives the odds of performing an invalid operation, as the N in 1-in-N.
+	InvalidChance map[LogEntrypointName]int
+}
+
+// choose randomly picks an operation to perform according to the biases.
+func (hb *LogBias) choose(r *rand.Rand) LogEntrypointName {
+	if hb.total == 0 {
+		for _, ep := range logEntrypoints {
+			hb.total += hb.Bias[ep]
+		}
+	}
+	which := r.Intn(hb.total)
+	for _, ep := range logEntrypoints {
+		which -= hb.Bias[ep]
+		if which < 0 {
+			return ep
+		}
+	}
+	panic("random choice out of range")
+}
+
+// invalid randomly chooses whether an operation should be invalid.
+func (hb *LogBias) invalid(ep LogEntrypointName, r *rand.Rand) bool {
+	chance := hb.InvalidChance[ep]
+	if chance <= 0 {
+		return false
+	}
+	return (r.Intn(chance) == 0)
+}
+
+// LogConfig provides configuration for a stress/load test of a log.
+//
+// Only one of QueueLeaves and AddSequencedLeaves is valid for a given log,
+// depending on whether it is pre-ordered. Choosing the other one results in a
+// deliberately-invalid request.
+type LogConfig struct {
+	LogID int64 // 0 to use an ephemeral tree
+	// Preordered indicates whether an ephemeral tree should be a
+	// PREORDERED_LOG rather than a LOG.
+	Preordered           bool
+	MetricFactory        monitoring.MetricFactory
+	Client               trillian.TrillianLogClient
+	Admin                trillian.TrillianAdminClient
+	RandSource           rand.Source
+	EPBias               LogBias
+	LeafSize             uint
+	MinLeaves, MaxLeaves int
+	Operations           uint64
+	EmitInterval         time.Duration
+	RetryErrors          bool
+	OperationDeadline    time.Duration
+	// KeepFailedTree indicates whether ephemeral trees should be left intact
+	// after a failed hammer run.
+	KeepFailedTree bool
+}
+
+// String conforms with Stringer for LogConfig.
+func (c LogConfig) String() string {
+	return fmt.Sprintf("logID:%d biases:{%v} #operations:%d emit every:%v retryErrors? %t",
+		c.LogID, c.EPBias, c.Operations, c.EmitInterval, c.RetryErrors)
+}
+
+// HitLog performs load/stress operations according to given config.
+func HitLog(cfg LogConfig) error {
+	ctx := context.Background()
+	var firstErr error
+
+	if cfg.LogID == 0 {
+		// No logID provided, so create an ephemeral tree to test against.
+		var err error
+		cfg.LogID, err = makeNewLog(ctx, cfg.Admin, cfg.Client, cfg.Preordered)
+		if err != nil {
+			return fmt.Errorf("failed to create ephemeral tree: %v", err)
+		}
+		glog.Infof("testing against ephemeral tree %d", cfg.LogID)
+		defer func() {
+			if firstErr != nil && cfg.KeepFailedTree {
+				glog.Errorf("note: leaving ephemeral tree %d intact after error %v", cfg.LogID, firstErr)
+				return
+			}
+			if err := destroyTree(ctx, cfg.Admin, cfg.LogID); err != nil {
+				glog.Errorf("failed to destroy log with treeID %d: %v", cfg.LogID, err)
+			}
+		}()
+	}
+
+	s, err := newLogHammerState(ctx, &cfg)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(cfg.EmitInterval)
+	go func(c <-chan time.Time) {
+		for range c {
+			glog.Info(s.String())
+		}
+	}(ticker.C)
+
+	count, err := s.performOperations(ctx)
+	firstErr = err
+	glog.Infof("%d: performed %d operations on log", cfg.LogID, count)
+	if firstErr != nil {
+		glog.Infof("%d: first error encountered: %v", cfg.LogID, firstErr)
+	}
+	ticker.Stop()
+
+	// Emit final statistics
+	glog.Info(s.String())
+	return firstErr
+}
+
+// logHammerState tracks the operations that have been performed during a
+// test run against a log.
+type logHammerState struct {
+	cfg        *LogConfig
+	preordered bool
+	verifier   *client.LogVerifier
+	merkle     merkle.LogVerifier
+
+	start time.Time
+
+	// prng is not thread-safe and should only be used from the main hammer
+	// goroutine for reproducability.
+	prng *rand.Rand
+
+	// tree holds the leaves of the log that have been checked against a
+	// verified log root, and is only used from the main hammer goroutine.
+	tree *merkle.InMemoryMerkleTree
+	// nextIndex is the index of the next leaf to add to a pre-ordered log.
+	nextIndex int64
+
+	mu sync.RWMutex // Protects everything below
+
+	// root is the latest verified log root.
+	root types.LogRootV1
+	// pending holds the values of the leaves that have been submitted to the
+	// log, but not yet seen in it.
+	pending map[string]bool
+
+	// Counter for generating unique values.
+	valueIdx int
+}
+
+func newLogHammerState(ctx context.Context, cfg *LogConfig) (*logHammerState, error) {
+	tree, err := cfg.Admin.GetTree(ctx, &trillian.GetTreeRequest{TreeId: cfg.LogID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree information: %v", err)
+	}
+	glog.Infof("%d: hammering tree with configuration %+v", cfg.LogID, tree)
+	verifier, err := client.NewLogVerifierFromTree(tree)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree verifier: %v", err)
+	}
+
+	mf := cfg.MetricFactory
+	if mf == nil {
+		mf = monitoring.InertMetricFactory{}
+	}
+	logOnce.Do(func() { setupLogMetrics(mf) })
+	if cfg.EmitInterval == 0 {
+		cfg.EmitInterval = defaultEmitSeconds * time.Second
+	}
+	if cfg.MinLeaves < 0 {
+		return nil, fmt.Errorf("invalid MinLeaves %d", cfg.MinLeaves)
+	}
+	if cfg.MaxLeaves < cfg.MinLeaves {
+		return nil, fmt.Errorf("invalid MaxLeaves %d is less than MinLeaves %d", cfg.MaxLeaves, cfg.MinLeaves)
+	}
+	if int(cfg.LeafSize) < minValueLen {
+		return nil, fmt.Errorf("invalid LeafSize %d is smaller than min %d", cfg.LeafSize, minValueLen)
+	}
+	if cfg.OperationDeadline == 0 {
+		cfg.OperationDeadline = 60 * time.Second
+	}
+
+	s := &logHammerState{
+		cfg:        cfg,
+		preordered: tree.TreeType == trillian.TreeType_PREORDERED_LOG,
+		verifier:   verifier,
+		merkle:     merkle.NewLogVerifier(verifier.Hasher),
+		start:      time.Now(),
+		prng:       rand.New(cfg.RandSource),
+		tree:       merkle.NewInMemoryMerkleTree(verifier.Hasher),
+		pending:    make(map[string]bool),
+	}
+	// Catch up with the existing contents of the log, so that new leaves of
+	// a pre-ordered log are added at the right index.
+	if err := s.updateRoot(ctx); err != nil {
+		return nil, fmt.Errorf("failed to get initial log root: %v", err)
+	}
+	s.nextIndex = s.tree.LeafCount()
+	return s, nil
+}
+
+func (s *logHammerState) performOperations(ctx context.Context) (uint64, error) {
+	count := uint64(0)
+	for ; count < s.cfg.Operations; count++ {
+		if err := ctx.Err(); err != nil {
+			return count, nil
+		}
+		if err := s.retryOneOp(ctx); err != nil {
+			return count, err
+		}
+	}
+	return count, nil
+}
+
+func (s *logHammerState) nextValue() []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.valueIdx++
+	result := make([]byte, s.cfg.LeafSize)
+	copy(result, fmt.Sprintf(valueFormat, s.valueIdx))
+	return result
+}
+
+func (s *logHammerState) label() string {
+	return strconv.FormatInt(s.cfg.LogID, 10)
+}
+
+func (s *logHammerState) String() string {
+	interval := time.Since(s.start)
+	details := ""
+	totalReqs := 0
+	totalInvalidReqs := 0
+	totalErrs := 0
+	for _, ep := range logEntrypoints {
+		reqCount := int(logReqs.Value(s.label(), string(ep)))
+		totalReqs += reqCount
+		if s.cfg.EPBias.Bias[ep] > 0 {
+			details += fmt.Sprintf(" %s=%d/%d", ep, int(logRsps.Value(s.label(), string(ep))), reqCount)
+		}
+		totalInvalidReqs += int(logInvalidReqs.Value(s.label(), string(ep)))
+		totalErrs += int(logErrs.Value(s.label(), string(ep)))
+	}
+	s.mu.RLock()
+	size, pending := s.root.TreeSize, len(s.pending)
+	s.mu.RUnlock()
+	return fmt.Sprintf("%d: lastSLR.size=%d pending=%d ops: total=%d (%f ops/sec) invalid=%d errs=%v%s", s.cfg.LogID, size, pending, totalReqs, float64(totalReqs)/interval.Seconds(), totalInvalidReqs, totalErrs, details)
+}
+
+func (s *logHammerState) chooseLeafCount(prng *rand.Rand) int {
+	delta := 1 + s.cfg.MaxLeaves - s.cfg.MinLeaves
+	return s.cfg.MinLeaves + prng.Intn(delta)
+}
+
+// applicable indicates whether valid requests can be made to ep, given the
+// type of the log.
+func (s *logHammerState) applicable(ep LogEntrypointName) bool {
+	switch ep {
+	case QueueLeavesName:
+		return !s.preordered
+	case AddSequencedLeavesName:
+		return s.preordered
+	}
+	return true
+}
+
+func (s *logHammerState) retryOneOp(ctx context.Context) (err error) {
+	ep := s.cfg.EPBias.choose(s.prng)
+	if s.cfg.EPBias.invalid(ep, s.prng) || !s.applicable(ep) {
+		glog.V(3).Infof("%d: perform invalid %s operation", s.cfg.LogID, ep)
+		logInvalidReqs.Inc(s.label(), string(ep))
+		return s.performInvalidOp(ctx, ep, s.prng)
+	}
+
+	glog.V(3).Infof("%d: perform %s operation", s.cfg.LogID, ep)
+	defer func(start time.Time) {
+		logRspLatency.Observe(time.Since(start).Seconds(), s.label(), string(ep))
+	}(time.Now())
+
+	deadline := time.Now().Add(s.cfg.OperationDeadline)
+	ctx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+
+	var firstErr error
+	seed := s.prng.Int63()
+	done := false
+	for !done {
+		// Always re-create the same per-operation rand.Rand so any retries are exactly the same.
+		prng := rand.New(rand.NewSource(seed))
+		logReqs.Inc(s.label(), string(ep))
+		err := s.performOp(ctx, ep, prng)
+
+		switch err.(type) {
+		case nil:
+			logRsps.Inc(s.label(), string(ep))
+			if firstErr != nil {
+				glog.Warningf("%d: retry of op %v succeeded, previous error: %v", s.cfg.LogID, ep, firstErr)
+			}
+			firstErr = nil
+			done = true
+		case errSkip:
+			firstErr = nil
+			done = true
+		case testonly.ErrInvariant:
+			// Ensure invariant failures are not ignorable.  They indicate a design assumption
+			// being broken or incorrect, so must be seen.
+			firstErr = err
+			done = true
+		default:
+			logErrs.Inc(s.label(), string(ep))
+			if firstErr == nil {
+				firstErr = err
+			}
+			if s.cfg.RetryErrors {
+				glog.Warningf("%d: op %v failed (will retry): %v", s.cfg.LogID, ep, err)
+			} else {
+				done = true
+			}
+		}
+
+		if time.Now().After(deadline) {
+			glog.Warningf("%d: gave up on operation %v after %v, returning first err %v", s.cfg.LogID, ep, s.cfg.OperationDeadline, firstErr)
+			done = true
+		}
+	}
+	return firstErr
+}
+
+func (s *logHammerState) performOp(ctx context.Context, ep LogEntrypointName, prng *rand.Rand) error {
+	switch ep {
+	case QueueLeavesName:
+		return s.queueLeaves(ctx, prng)
+	case AddSequencedLeavesName:
+		return s.addSequencedLeaves(ctx, prng)
+	case GetLeavesByRangeName:
+		return s.getLeavesByRange(ctx, prng)
+	case GetInclusionProofName:
+		return s.getInclusionProof(ctx, prng)
+	case GetConsistencyProofName:
+		return s.getConsistencyProof(ctx, prng)
+	case GetSLRName:
+		return s.getSLR(ctx, prng)
+	default:
+		return fmt.Errorf("internal error: unknown entrypoint %s selected for valid request", ep)
+	}
+}
+
+func (s *logHammerState) performInvalidOp(ctx context.Context, ep LogEntrypointName, prng *rand.Rand) error {
+	switch ep {
+	case QueueLeavesName:
+		return s.queueLeavesInvalid(ctx, prng)
+	case AddSequencedLeavesName:
+		return s.addSequencedLeavesInvalid(ctx, prng)
+	case GetLeavesByRangeName:
+		return s.getLeavesByRangeInvalid(ctx, prng)
+	case GetInclusionProofName:
+		return s.getInclusionProofInvalid(ctx, prng)
+	case GetConsistencyProofName:
+		return s.getConsistencyProofInvalid(ctx, prng)
+	case GetSLRName:
+		return fmt.Errorf("no invalid request possible for entrypoint %s", ep)
+	default:
+		return fmt.Errorf("internal error: unknown entrypoint %s selected for invalid request", ep)
+	}
+}
+
+// newLeaves returns leaves with fresh values, starting at the given index.
+func (s *logHammerState) newLeaves(prng *rand.Rand, index int64) []*trillian.LogLeaf {
+	n := s.chooseLeafCount(prng)
+	if n == 0 {
+		n = 1
+	}
+	leaves := make([]*trillian.LogLeaf, n)
+	for i := range leaves {
+		leaves[i] = &trillian.LogLeaf{LeafValue: s.nextValue(), LeafIndex: index + int64(i)}
+	}
+	return leaves
+}
+
+// checkQueued checks the results of adding leaves to the log, and records
+// the leaves as pending.
+func (s *logHammerState) checkQueued(label string, leaves []*trillian.LogLeaf, queued []*trillian.QueuedLogLeaf) error {
+	if got, want := len(queued), len(leaves); got != want {
+		return fmt.Errorf("%s(): got %d results, want %d", label, got, want)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, q := range queued {
+		// Values are only unique within a run, so earlier runs against the
+		// same log may have added them already.
+		if code := codes.Code(q.GetStatus().GetCode()); code != codes.OK && code != codes.AlreadyExists {
+			return fmt.Errorf("%s(): leaf %d got status %v", label, i, q.GetStatus())
+		}
+		if !s.preordered && !bytes.Equal(q.GetLeaf().GetLeafValue(), leaves[i].LeafValue) {
+			return fmt.Errorf("%s(): leaf %d has value %x, want %x", label, i, q.GetLeaf().GetLeafValue(), leaves[i].LeafValue)
+		}
+		s.pending[string(leaves[i].LeafValue)] = true
+	}
+	return nil
+}
+
+func (s *logHammerState) queueLeaves(ctx context.Context, prng *rand.Rand) error {
+	leaves := s.newLeaves(prng, 0)
+	req := &trillian.QueueLeavesRequest{LogId: s.cfg.LogID, Leaves: leaves}
+	rsp, err := s.cfg.Client.QueueLeaves(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to queue-leaves(count=%d): %v", len(leaves), err)
+	}
+	if err := s.checkQueued("queue-leaves", leaves, rsp.QueuedLeaves); err != nil {
+		return err
+	}
+	glog.V(2).Infof("%d: queued %d leaves", s.cfg.LogID, len(leaves))
+	return nil
+}
+
+func (s *logHammerState) addSequencedLeaves(ctx context.Context, prng *rand.Rand) error {
+	leaves := s.newLeaves(prng, s.nextIndex)
+	req := &trillian.AddSequencedLeavesRequest{LogId: s.cfg.LogID, Leaves: leaves}
+	rsp, err := s.cfg.Client.AddSequencedLeaves(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to add-sequenced-leaves(count=%d, index=%d): %v", len(leaves), s.nextIndex, err)
+	}
+	if err := s.checkQueued("add-sequenced-leaves", leaves, rsp.Results); err != nil {
+		return err
+	}
+	s.nextIndex += int64(len(leaves))
+	glog.V(2).Infof("%d: added %d leaves from index %d", s.cfg.LogID, len(leaves), leaves[0].LeafIndex)
+	return nil
+}
+
+func (s *logHammerState) queueLeavesInvalid(ctx context.Context, prng *rand.Rand) error {
+	choices := []Choice{EmptyLeaves, EmptyValue}
+	if s.preordered {
+		choices = append(choices, WrongTreeType)
+	}
+	choice := choices[prng.Intn(len(choices))]
+	if !s.applicable(QueueLeavesName) {
+		choice = WrongTreeType
+	}
+
+	req := trillian.QueueLeavesRequest{LogId: s.cfg.LogID}
+	switch choice {
+	case EmptyValue:
+		req.Leaves = []*trillian.LogLeaf{{}}
+	case WrongTreeType:
+		req.Leaves = []*trillian.LogLeaf{{LeafValue: []byte("value-for-invalid-req")}}
+	}
+	rsp, err := s.cfg.Client.QueueLeaves(ctx, &req)
+	if err == nil {
+		return fmt.Errorf("unexpected success: queue-leaves(%v: %+v): %+v", choice, req, rsp)
+	}
+	glog.V(2).Infof("%d: expected failure: queue-leaves(%v: %+v): %+v", s.cfg.LogID, choice, req, rsp)
+	return nil
+}
+
+func (s *logHammerState) addSequencedLeavesInvalid(ctx context.Context, prng *rand.Rand) error {
+	choices := []Choice{EmptyLeaves, EmptyValue, IndexGap, IndexIsNegative}
+	choice := choices[prng.Intn(len(choices))]
+	if !s.applicable(AddSequencedLeavesName) {
+		choice = WrongTreeType
+	}
+
+	value := []byte("value-for-invalid-req")
+	req := trillian.AddSequencedLeavesRequest{LogId: s.cfg.LogID}
+	switch choice {
+	case EmptyValue:
+		req.Leaves = []*trillian.LogLeaf{{LeafIndex: s.nextIndex}}
+	case IndexGap:
+		req.Leaves = []*trillian.LogLeaf{
+			{LeafValue: value, LeafIndex: s.nextIndex},
+			{LeafValue: value, LeafIndex: s.nextIndex + 2},
+		}
+	case IndexIsNegative:
+		req.Leaves = []*trillian.LogLeaf{{LeafValue: value, LeafIndex: -1}}
+	case WrongTreeType:
+		req.Leaves = []*trillian.LogLeaf{{LeafValue: value, LeafIndex: s.nextIndex}}
+	}
+	rsp, err := s.cfg.Client.AddSequencedLeaves(ctx, &req)
+	if err == nil {
+		return fmt.Errorf("unexpected success: add-sequenced-leaves(%v: %+v): %+v", choice, req, rsp)
+	}
+	glog.V(2).Infof("%d: expected failure: add-sequenced-leaves(%v: %+v): %+v", s.cfg.LogID, choice, req, rsp)
+	return nil
+}
+
+func (s *logHammerState) getLeavesByRange(ctx context.Context, prng *rand.Rand) error {
+	size := s.tree.LeafCount()
+	if size == 0 {
+		glog.V(3).Infof("%d: skipping get-leaves-by-range as no data yet", s.cfg.LogID)
+		return errSkip{}
+	}
+	start := prng.Int63n(size)
+	count := int64(s.chooseLeafCount(prng))
+	if count == 0 {
+		count = 1
+	}
+	req := &trillian.GetLeavesByRangeRequest{LogId: s.cfg.LogID, StartIndex: start, Count: count}
+	rsp, err := s.cfg.Client.GetLeavesByRange(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to get-leaves-by-range(start=%d, count=%d): %v", start, count, err)
+	}
+	if len(rsp.Leaves) == 0 || int64(len(rsp.Leaves)) > count {
+		return fmt.Errorf("get-leaves-by-range(start=%d, count=%d): got %d leaves", start, count, len(rsp.Leaves))
+	}
+	for i, leaf := range rsp.Leaves {
+		index := start + int64(i)
+		if err := s.checkLeaf(leaf, index); err != nil {
+			return fmt.Errorf("get-leaves-by-range(start=%d, count=%d): %v", start, count, err)
+		}
+		if index >= size {
+			// Not verified locally yet.
+			continue
+		}
+		if want := s.tree.LeafHash(index + 1); !bytes.Equal(leaf.MerkleLeafHash, want) {
+			return fmt.Errorf("get-leaves-by-range(start=%d, count=%d): leaf %d has hash %x, want %x", start, count, index, leaf.MerkleLeafHash, want)
+		}
+	}
+	glog.V(2).Infof("%d: got %d leaves from index %d", s.cfg.LogID, len(rsp.Leaves), start)
+	return nil
+}
+
+func (s *logHammerState) getLeavesByRangeInvalid(ctx context.Context, prng *rand.Rand) error {
+	choices := []Choice{IndexIsNegative, CountIsZero}
+	choice := choices[prng.Intn(len(choices))]
+
+	req := trillian.GetLeavesByRangeRequest{LogId: s.cfg.LogID, StartIndex: 0, Count: 1}
+	switch choice {
+	case IndexIsNegative:
+		req.StartIndex = -invalidStretch
+	case CountIsZero:
+		req.Count = 0
+	}
+	rsp, err := s.cfg.Client.GetLeavesByRange(ctx, &req)
+	if err == nil {
+		return fmt.Errorf("unexpected success: get-leaves-by-range(%v: %+v): %+v", choice, req, rsp)
+	}
+	glog.V(2).Infof("%d: expected failure: get-leaves-by-range(%v: %+v): %+v", s.cfg.LogID, choice, req, rsp)
+	return nil
+}
+
+func (s *logHammerState) getInclusionProof(ctx context.Context, prng *rand.Rand) error {
+	size := s.tree.LeafCount()
+	if size == 0 {
+		glog.V(3).Infof("%d: skipping get-inclusion-proof as no data yet", s.cfg.LogID)
+		return errSkip{}
+	}
+	treeSize := 1 + prng.Int63n(size)
+	index := prng.Int63n(treeSize)
+	req := &trillian.GetInclusionProofRequest{LogId: s.cfg.LogID, LeafIndex: index, TreeSize: treeSize}
+	rsp, err := s.cfg.Client.GetInclusionProof(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to get-inclusion-proof(index=%d, size=%d): %v", index, treeSize, err)
+	}
+	root := s.tree.RootAtSnapshot(treeSize).Hash()
+	if err := s.merkle.VerifyInclusionProof(index, treeSize, rsp.GetProof().GetHashes(), root, s.tree.LeafHash(index+1)); err != nil {
+		return fmt.Errorf("get-inclusion-proof(index=%d, size=%d): failed to verify proof: %v", index, treeSize, err)
+	}
+	glog.V(2).Infof("%d: verified inclusion proof for index %d at size %d", s.cfg.LogID, index, treeSize)
+	return nil
+}
+
+func (s *logHammerState) getInclusionProofInvalid(ctx context.Context, prng *rand.Rand) error {
+	choices := []Choice{IndexIsNegative, IndexTooBig, SizeIsZero}
+	choice := choices[prng.Intn(len(choices))]
+
+	req := trillian.GetInclusionProofRequest{LogId: s.cfg.LogID, LeafIndex: 0, TreeSize: 1}
+	switch choice {
+	case IndexIsNegative:
+		req.LeafIndex = -invalidStretch
+	case IndexTooBig:
+		req.LeafIndex = req.TreeSize + invalidStretch
+	case SizeIsZero:
+		req.TreeSize = 0
+	}
+	rsp, err := s.cfg.Client.GetInclusionProof(ctx, &req)
+	if err == nil {
+		return fmt.Errorf("unexpected success: get-inclusion-proof(%v: %+v): %+v", choice, req, rsp)
+	}
+	glog.V(2).Infof("%d: expected failure: get-inclusion-proof(%v: %+v): %+v", s.cfg.LogID, choice, req, rsp)
+	return nil
+}
+
+func (s *logHammerState) getConsistencyProof(ctx context.Context, prng *rand.Rand) error {
+	size := s.tree.LeafCount()
+	if size == 0 {
+		glog.V(3).Infof("%d: skipping get-consistency-proof as no data yet", s.cfg.LogID)
+		return errSkip{}
+	}
+	second := 1 + prng.Int63n(size)
+	first := 1 + prng.Int63n(second)
+	req := &trillian.GetConsistencyProofRequest{LogId: s.cfg.LogID, FirstTreeSize: first, SecondTreeSize: second}
+	rsp, err := s.cfg.Client.GetConsistencyProof(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to get-consistency-proof(first=%d, second=%d): %v", first, second, err)
+	}
+	root1 := s.tree.RootAtSnapshot(first).Hash()
+	root2 := s.tree.RootAtSnapshot(second).Hash()
+	if err := s.merkle.VerifyConsistencyProof(first, second, root1, root2, rsp.GetProof().GetHashes()); err != nil {
+		return fmt.Errorf("get-consistency-proof(first=%d, second=%d): failed to verify proof: %v", first, second, err)
+	}
+	glog.V(2).Infof("%d: verified consistency proof from size %d to %d", s.cfg.LogID, first, second)
+	return nil
+}
+
+func (s *logHammerState) getConsistencyProofInvalid(ctx context.Context, prng *rand.Rand) error {
+	choices := []Choice{SizeIsZero, SizesReversed}
+	choice := choices[prng.Intn(len(choices))]
+
+	req := trillian.GetConsistencyProofRequest{LogId: s.cfg.LogID, FirstTreeSize: 1, SecondTreeSize: 1}
+	switch choice {
+	case SizeIsZero:
+		req.FirstTreeSize = 0
+	case SizesReversed:
+		req.FirstTreeSize = req.SecondTreeSize + invalidStretch
+	}
+	rsp, err := s.cfg.Client.GetConsistencyProof(ctx, &req)
+	if err == nil {
+		return fmt.Errorf("unexpected success: get-consistency-proof(%v: %+v): %+v", choice, req, rsp)
+	}
+	glog.V(2).Infof("%d: expected failure: get-consistency-proof(%v: %+v): %+v", s.cfg.LogID, choice, req, rsp)
+	return nil
+}
+
+func (s *logHammerState) getSLR(ctx context.Context, prng *rand.Rand) error {
+	return s.updateRoot(ctx)
+}
+
+// updateRoot fetches and verifies the latest log root, and then fetches the
+// leaves added since the previous one, checking that they produce the same
+// root hash.
+func (s *logHammerState) updateRoot(ctx context.Context) error {
+	s.mu.RLock()
+	trusted := s.root
+	s.mu.RUnlock()
+
+	req := &trillian.GetLatestSignedLogRootRequest{LogId: s.cfg.LogID, FirstTreeSize: int64(trusted.TreeSize)}
+	rsp, err := s.cfg.Client.GetLatestSignedLogRoot(ctx, req)
+	if err != nil {
+		return fmt.Errorf("failed to get-slr: %v", err)
+	}
+	root, err := s.verifier.VerifyRoot(&trusted, rsp.SignedLogRoot, rsp.GetProof().GetHashes())
+	if err != nil {
+		return fmt.Errorf("failed to verify root: %v", err)
+	}
+
+	for size := s.tree.LeafCount(); size < int64(root.TreeSize); size = s.tree.LeafCount() {
+		count := int64(root.TreeSize) - size
+		if count > logSyncBatch {
+			count = logSyncBatch
+		}
+		req := &trillian.GetLeavesByRangeRequest{LogId: s.cfg.LogID, StartIndex: size, Count: count}
+		rsp, err := s.cfg.Client.GetLeavesByRange(ctx, req)
+		if err != nil {
+			return fmt.Errorf("failed to get-leaves-by-range(start=%d, count=%d): %v", size, count, err)
+		}
+		if len(rsp.Leaves) == 0 {
+			return fmt.Errorf("get-leaves-by-range(start=%d, count=%d): got no leaves", size, count)
+		}
+		if int64(len(rsp.Leaves)) > count {
+			rsp.Leaves = rsp.Leaves[:count]
+		}
+		s.mu.Lock()
+		for i, leaf := range rsp.Leaves {
+			if err := s.checkLeaf(leaf, size+int64(i)); err != nil {
+				s.mu.Unlock()
+				return err
+			}
+			s.tree.AddLeaf(leaf.LeafValue)
+			delete(s.pending, string(leaf.LeafValue))
+		}
+		s.mu.Unlock()
+	}
+
+	if got := s.tree.RootAtSnapshot(int64(root.TreeSize)).Hash(); !bytes.Equal(got, root.RootHash) {
+		return testonly.NewErrInvariant(fmt.Sprintf("root hash at size %d is %x, but leaves give %x", root.TreeSize, root.RootHash, got))
+	}
+	s.mu.Lock()
+	s.root = *root
+	s.mu.Unlock()
+	glog.V(2).Infof("%d: got SLR(time=%q, size=%d)", s.cfg.LogID, time.Unix(0, int64(root.TimestampNanos)), root.TreeSize)
+	return nil
+}
+
+// checkLeaf checks that leaf is correctly indexed and hashed.
+func (s *logHammerState) checkLeaf(leaf *trillian.LogLeaf, index int64) error {
+	if leaf.LeafIndex != index {
+		return fmt.Errorf("got leaf with index %d, want %d", leaf.LeafIndex, index)
+	}
+	if want := s.verifier.Hasher.HashLeaf(leaf.LeafValue); !bytes.Equal(leaf.MerkleLeafHash, want) {
+		return fmt.Errorf("leaf %d has hash %x, want %x", index, leaf.MerkleLeafHash, want)
+	}
+	return nil
+}
@@ -132,15 +132,38 @@ func ReplayFile(ctx context.Context, r io.Reader, cl trillian.TrillianMapClient,
 	}
 }
 
+// ReplayLogFile reads recorded gRPC requests and re-issues them using the given
+// log client.  If a request has a LogId field, and its value is present in
+// logmap, then the LogId field is replaced before replay.
+func ReplayLogFile(ctx context.Context, r io.Reader, cl trillian.TrillianLogClient, logmap map[int64]int64) {
+	for {
+		a, err := readMessage(r)
+		if err != nil {
+			if err != io.EOF {
+				glog.Errorf("Error reading message: %v", err)
+			}
+			return
+		}
+		glog.V(2).Infof("Replay %q", a.TypeUrl)
+		replayLogMessage(ctx, cl, a, logmap)
+	}
+}
+
 // convertMessage modifies msg in-place so that the contents of a "MapId" field
 // are updated according to mapmap.
 func convertMessage(msg proto.Message, mapmap map[int64]int64) {
-	// Look for a "MapId" field that we can overwrite if needed.
+	convertField(msg, "MapId", mapmap)
+}
+
+// convertField modifies msg in-place so that the contents of the named int64
+// field are updated according to idmap.
+func convertField(msg proto.Message, name string, idmap map[int64]int64) {
+	// Look for a field that we can overwrite if needed.
 	pVal := reflect.ValueOf(msg)
-	if fieldVal := pVal.Elem().FieldByName("MapId"); fieldVal.CanSet() {
+	if fieldVal := pVal.Elem().FieldByName(name); fieldVal.CanSet() {
 		from := fieldVal.Int()
-		if to, ok := mapmap[from]; ok {
-			glog.V(2).Infof("Replacing msg.MapId=%d with %d in %T", from, to, msg)
+		if to, ok := idmap[from]; ok {
+			glog.V(2).Infof("Replacing msg.%s=%d with %d in %T", name, from, to, msg)
 			fieldVal.SetInt(to)
 		}
 	}
@@ -178,3 +201,38 @@ func replayMessage(ctx context.Context, cl trillian.TrillianMapClient, a *any.An
 	}
 	return err
 }
+
+func replayLogMessage(ctx context.Context, cl trillian.TrillianLogClient, a *any.Any, logmap map[int64]int64) error {
+	var da ptypes.DynamicAny
+	if err := ptypes.UnmarshalAny(a, &da); err != nil {
+		return fmt.Errorf("failed to unmarshal from any.Any: %v", err)
+	}
+	req := da.Message
+	convertField(req, "LogId", logmap)
+	glog.V(2).Infof("Request req=%T %+v", req, req)
+	var err error
+	var rsp proto.Message
+	if cl != nil {
+		switch req := req.(type) {
+		case *trillian.QueueLeavesRequest:
+			rsp, err = cl.QueueLeaves(ctx, req)
+		case *trillian.AddSequencedLeavesRequest:
+			rsp, err = cl.AddSequencedLeaves(ctx, req)
+		case *trillian.GetLeavesByRangeRequest:
+			rsp, err = cl.GetLeavesByRange(ctx, req)
+		case *trillian.GetInclusionProofRequest:
+			rsp, err = cl.GetInclusionProof(ctx, req)
+		case *trillian.GetConsistencyProofRequest:
+			rsp, err = cl.GetConsistencyProof(ctx, req)
+		case *trillian.GetLatestSignedLogRootRequest:
+			rsp, err = cl.GetLatestSignedLogRoot(ctx, req)
+		case *trillian.InitLogRequest:
+			rsp, err = cl.InitLog(ctx, req)
+		}
+		if rsp != nil {
+			glog.V(1).Infof("Request:  req=%T %+v", req, req)
+			glog.V(1).Infof("Response: rsp=%T %+v err=%v", rsp, rsp, err)
+		}
+	}
+	return err
+}
@@ -154,3 +154,38 @@ func TestConvertMsg(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertLogMsg(t *testing.T) {
+	logmap := map[int64]int64{999: 123}
+	var tests = []struct {
+		desc string
+		in   proto.Message
+		want proto.Message
+	}{
+		{
+			desc: "queue-leaves-req-mapped",
+			in:   &trillian.QueueLeavesRequest{LogId: 999},
+			want: &trillian.QueueLeavesRequest{LogId: 123},
+		},
+		{
+			desc: "get-slr-req-unmapped",
+			in:   &trillian.GetLatestSignedLogRootRequest{LogId: 456},
+			want: &trillian.GetLatestSignedLogRootRequest{LogId: 456},
+		},
+		{
+			desc: "map-id-instead",
+			in:   &trillian.GetSignedMapRootRequest{MapId: 999},
+			want: &trillian.GetSignedMapRootRequest{MapId: 999},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.desc, func(t *testing.T) {
+			got := test.in
+			convertField(got, "LogId", logmap)
+			if !proto.Equal(got, test.want) {
+				t.Fatalf("convertField(%+v)=%+v; want %+v", test.in, got, test.want)
+			}
+		})
+	}
+}
@@ -27,11 +27,11 @@ import (
 	"github.com/google/trillian/crypto/sigpb"
 )
 
-func destroyMap(ctx context.Context, adminClient trillian.TrillianAdminClient, mapID int64) error {
-	req := &trillian.DeleteTreeRequest{TreeId: mapID}
-	glog.Infof("Soft-delete transient Trillian Map with TreeID=%d", mapID)
+func destroyTree(ctx context.Context, adminClient trillian.TrillianAdminClient, treeID int64) error {
+	req := &trillian.DeleteTreeRequest{TreeId: treeID}
+	glog.Infof("Soft-delete transient Trillian tree with TreeID=%d", treeID)
 	if _, err := adminClient.DeleteTree(ctx, req); err != nil {
-		return fmt.Errorf("failed to DeleteTree(%d): %v", mapID, err)
+		return fmt.Errorf("failed to DeleteTree(%d): %v", treeID, err)
 	}
 	return nil
 }
@@ -66,3 +66,38 @@ func makeNewMap(ctx context.Context, adminClient trillian.TrillianAdminClient, m
 
 	return tree.TreeId, nil
 }
+
+func makeNewLog(ctx context.Context, adminClient trillian.TrillianAdminClient, logClient trillian.TrillianLogClient, preordered bool) (int64, error) {
+	nowSec := time.Now().UnixNano() / int64(time.Second)
+	treeType := trillian.TreeType_LOG
+	if preordered {
+		treeType = trillian.TreeType_PREORDERED_LOG
+	}
+	req := &trillian.CreateTreeRequest{
+		Tree: &trillian.Tree{
+			TreeState:          trillian.TreeState_ACTIVE,
+			TreeType:           treeType,
+			HashStrategy:       trillian.HashStrategy_RFC6962_SHA256,
+			HashAlgorithm:      sigpb.DigitallySigned_SHA256,
+			SignatureAlgorithm: sigpb.DigitallySigned_ECDSA,
+			DisplayName:        fmt.Sprintf("loghammer-%d", nowSec),
+			Description:        "Transient tree for LogHammer test",
+			MaxRootDuration:    ptypes.DurationProto(time.Second * 3600),
+		},
+		KeySpec: &keyspb.Specification{
+			Params: &keyspb.Specification_EcdsaParams{
+				EcdsaParams: &keyspb.Specification_ECDSA{
+					Curve: keyspb.Specification_ECDSA_P256,
+				},
+			},
+		},
+	}
+
+	tree, err := client.CreateAndInitTree(ctx, req, adminClient, nil, logClient)
+	if err != nil {
+		return -1, fmt.Errorf("client.CreateAndInitTree(%v) failed with err: %v", req, err)
+	}
+	glog.Infof("Made new Trillian Log with TreeID=%d", tree.TreeId)
+
+	return tree.TreeId, nil
+}
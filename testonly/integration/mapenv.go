@@ -106,7 +106,7 @@ func NewMapEnvWithRegistry(registry extension.Registry, singleTX bool) (*MapEnv,
 		return nil, err
 	}
 
-	ti := interceptor.New(registry.AdminStorage, registry.QuotaManager, false /* quotaDryRun */, registry.MetricFactory)
+	ti := interceptor.New(registry.AdminStorage, registry.QuotaManager, registry.Authorizer, false /* quotaDryRun */, registry.MetricFactory)
 
 	// Create Map Server.
 	grpcServer := grpc.NewServer(
@@ -114,6 +114,10 @@ func NewMapEnvWithRegistry(registry extension.Registry, singleTX bool) (*MapEnv,
 			interceptor.ErrorWrapper,
 			ti.UnaryInterceptor,
 		)),
+		grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(
+			interceptor.StreamErrorWrapper,
+			ti.StreamInterceptor,
+		)),
 	)
 	mapServer := server.NewTrillianMapServer(registry, server.TrillianMapServerOptions{UseSingleTransaction: singleTX})
 	trillian.RegisterTrillianMapServer(grpcServer, mapServer)
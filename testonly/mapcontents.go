@@ -33,6 +33,11 @@ type ErrInvariant struct {
 	msg string
 }
 
+// NewErrInvariant returns an ErrInvariant with the given details.
+func NewErrInvariant(msg string) ErrInvariant {
+	return ErrInvariant{msg: msg}
+}
+
 func (e ErrInvariant) Error() string {
 	return fmt.Sprintf("Invariant check failed: %v", e.msg)
 }
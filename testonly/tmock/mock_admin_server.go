@@ -34,6 +34,21 @@ func (m *MockTrillianAdminServer) EXPECT() *MockTrillianAdminServerMockRecorder
 	return m.recorder
 }
 
+// AddTreeKey mocks base method
+func (m *MockTrillianAdminServer) AddTreeKey(arg0 context.Context, arg1 *trillian.AddTreeKeyRequest) (*trillian.Tree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTreeKey", arg0, arg1)
+	ret0, _ := ret[0].(*trillian.Tree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddTreeKey indicates an expected call of AddTreeKey
+func (mr *MockTrillianAdminServerMockRecorder) AddTreeKey(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTreeKey", reflect.TypeOf((*MockTrillianAdminServer)(nil).AddTreeKey), arg0, arg1)
+}
+
 // CreateTree mocks base method
 func (m *MockTrillianAdminServer) CreateTree(arg0 context.Context, arg1 *trillian.CreateTreeRequest) (*trillian.Tree, error) {
 	m.ctrl.T.Helper()
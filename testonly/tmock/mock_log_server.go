@@ -34,6 +34,21 @@ func (m *MockTrillianLogServer) EXPECT() *MockTrillianLogServerMockRecorder {
 	return m.recorder
 }
 
+// AddLogRootCosignature mocks base method
+func (m *MockTrillianLogServer) AddLogRootCosignature(arg0 context.Context, arg1 *trillian.AddLogRootCosignatureRequest) (*trillian.AddLogRootCosignatureResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddLogRootCosignature", arg0, arg1)
+	ret0, _ := ret[0].(*trillian.AddLogRootCosignatureResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddLogRootCosignature indicates an expected call of AddLogRootCosignature
+func (mr *MockTrillianLogServerMockRecorder) AddLogRootCosignature(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddLogRootCosignature", reflect.TypeOf((*MockTrillianLogServer)(nil).AddLogRootCosignature), arg0, arg1)
+}
+
 // AddSequencedLeaf mocks base method
 func (m *MockTrillianLogServer) AddSequencedLeaf(arg0 context.Context, arg1 *trillian.AddSequencedLeafRequest) (*trillian.AddSequencedLeafResponse, error) {
 	m.ctrl.T.Helper()
@@ -64,6 +79,21 @@ func (mr *MockTrillianLogServerMockRecorder) AddSequencedLeaves(arg0, arg1 inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSequencedLeaves", reflect.TypeOf((*MockTrillianLogServer)(nil).AddSequencedLeaves), arg0, arg1)
 }
 
+// GetBatchInclusionProof mocks base method
+func (m *MockTrillianLogServer) GetBatchInclusionProof(arg0 context.Context, arg1 *trillian.GetBatchInclusionProofRequest) (*trillian.GetBatchInclusionProofResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBatchInclusionProof", arg0, arg1)
+	ret0, _ := ret[0].(*trillian.GetBatchInclusionProofResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBatchInclusionProof indicates an expected call of GetBatchInclusionProof
+func (mr *MockTrillianLogServerMockRecorder) GetBatchInclusionProof(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBatchInclusionProof", reflect.TypeOf((*MockTrillianLogServer)(nil).GetBatchInclusionProof), arg0, arg1)
+}
+
 // GetConsistencyProof mocks base method
 func (m *MockTrillianLogServer) GetConsistencyProof(arg0 context.Context, arg1 *trillian.GetConsistencyProofRequest) (*trillian.GetConsistencyProofResponse, error) {
 	m.ctrl.T.Helper()
@@ -184,6 +214,21 @@ func (mr *MockTrillianLogServerMockRecorder) GetLeavesByRange(arg0, arg1 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLeavesByRange", reflect.TypeOf((*MockTrillianLogServer)(nil).GetLeavesByRange), arg0, arg1)
 }
 
+// GetRangeInclusionProof mocks base method
+func (m *MockTrillianLogServer) GetRangeInclusionProof(arg0 context.Context, arg1 *trillian.GetRangeInclusionProofRequest) (*trillian.GetRangeInclusionProofResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRangeInclusionProof", arg0, arg1)
+	ret0, _ := ret[0].(*trillian.GetRangeInclusionProofResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRangeInclusionProof indicates an expected call of GetRangeInclusionProof
+func (mr *MockTrillianLogServerMockRecorder) GetRangeInclusionProof(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRangeInclusionProof", reflect.TypeOf((*MockTrillianLogServer)(nil).GetRangeInclusionProof), arg0, arg1)
+}
+
 // GetSequencedLeafCount mocks base method
 func (m *MockTrillianLogServer) GetSequencedLeafCount(arg0 context.Context, arg1 *trillian.GetSequencedLeafCountRequest) (*trillian.GetSequencedLeafCountResponse, error) {
 	m.ctrl.T.Helper()
@@ -243,3 +288,17 @@ func (mr *MockTrillianLogServerMockRecorder) QueueLeaves(arg0, arg1 interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueLeaves", reflect.TypeOf((*MockTrillianLogServer)(nil).QueueLeaves), arg0, arg1)
 }
+
+// StreamLeaves mocks base method
+func (m *MockTrillianLogServer) StreamLeaves(arg0 *trillian.StreamLeavesRequest, arg1 trillian.TrillianLog_StreamLeavesServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamLeaves", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamLeaves indicates an expected call of StreamLeaves
+func (mr *MockTrillianLogServerMockRecorder) StreamLeaves(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamLeaves", reflect.TypeOf((*MockTrillianLogServer)(nil).StreamLeaves), arg0, arg1)
+}
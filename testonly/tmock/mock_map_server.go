@@ -124,6 +124,20 @@ func (mr *MockTrillianMapServerMockRecorder) GetLeavesByRevisionNoProof(arg0, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLeavesByRevisionNoProof", reflect.TypeOf((*MockTrillianMapServer)(nil).GetLeavesByRevisionNoProof), arg0, arg1)
 }
 
+// GetMapDiff mocks base method
+func (m *MockTrillianMapServer) GetMapDiff(arg0 *trillian.GetMapDiffRequest, arg1 trillian.TrillianMap_GetMapDiffServer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMapDiff", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetMapDiff indicates an expected call of GetMapDiff
+func (mr *MockTrillianMapServerMockRecorder) GetMapDiff(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMapDiff", reflect.TypeOf((*MockTrillianMapServer)(nil).GetMapDiff), arg0, arg1)
+}
+
 // GetSignedMapRoot mocks base method
 func (m *MockTrillianMapServer) GetSignedMapRoot(arg0 context.Context, arg1 *trillian.GetSignedMapRootRequest) (*trillian.GetSignedMapRootResponse, error) {
 	m.ctrl.T.Helper()
@@ -169,6 +183,21 @@ func (mr *MockTrillianMapServerMockRecorder) InitMap(arg0, arg1 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InitMap", reflect.TypeOf((*MockTrillianMapServer)(nil).InitMap), arg0, arg1)
 }
 
+// ListLeaves mocks base method
+func (m *MockTrillianMapServer) ListLeaves(arg0 context.Context, arg1 *trillian.ListMapLeavesRequest) (*trillian.ListMapLeavesResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLeaves", arg0, arg1)
+	ret0, _ := ret[0].(*trillian.ListMapLeavesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLeaves indicates an expected call of ListLeaves
+func (mr *MockTrillianMapServerMockRecorder) ListLeaves(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLeaves", reflect.TypeOf((*MockTrillianMapServer)(nil).ListLeaves), arg0, arg1)
+}
+
 // SetLeaves mocks base method
 func (m *MockTrillianMapServer) SetLeaves(arg0 context.Context, arg1 *trillian.SetMapLeavesRequest) (*trillian.SetMapLeavesResponse, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,257 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tiles
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math/bits"
+	"path/filepath"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian"
+	"github.com/google/trillian/merkle/hashers"
+)
+
+// Fetcher returns the contents of the exported file at path, e.g. by
+// fetching it from a web server.
+type Fetcher func(ctx context.Context, path string) ([]byte, error)
+
+// DirFetcher returns a Fetcher which reads the files exported to dir.
+func DirFetcher(dir string) Fetcher {
+	return func(ctx context.Context, path string) ([]byte, error) {
+		return ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(path)))
+	}
+}
+
+// Client builds proofs from the tiles of an exported log. The proofs are the
+// same as the ones served by the log server, so they can be checked with
+// merkle.LogVerifier against roots verified with client.LogVerifier.
+//
+// Tree sizes passed to a Client must be sizes the log was exported at, as
+// the partial tiles at the right edge of the tree only exist for those.
+type Client struct {
+	fetch  Fetcher
+	hasher hashers.LogHasher
+}
+
+// NewClient returns a Client which reads tiles with fetch, and hashes them
+// with hasher.
+func NewClient(fetch Fetcher, hasher hashers.LogHasher) *Client {
+	return &Client{fetch: fetch, hasher: hasher}
+}
+
+// Checkpoint returns the latest exported root. Its signature is not
+// verified.
+func (c *Client) Checkpoint(ctx context.Context) (*trillian.SignedLogRoot, error) {
+	data, err := c.fetch(ctx, CheckpointPath)
+	if err != nil {
+		return nil, err
+	}
+	var slr trillian.SignedLogRoot
+	if err := proto.Unmarshal(data, &slr); err != nil {
+		return nil, err
+	}
+	return &slr, nil
+}
+
+// Leaf returns the value of the leaf at index in the tree of the given size.
+func (c *Client) Leaf(ctx context.Context, index, size int64) ([]byte, error) {
+	if index < 0 || index >= size {
+		return nil, fmt.Errorf("leaf index %d out of range for tree size %d", index, size)
+	}
+	n := index / TileWidth
+	path := DataTilePath(n, tileWidth(n, size))
+	data, err := c.fetch(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	values, err := ParseDataTile(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if i := index % TileWidth; i < int64(len(values)) {
+		return values[i], nil
+	}
+	return nil, fmt.Errorf("%s: got %d leaves, want %d", path, len(values), tileWidth(n, size))
+}
+
+// InclusionProof returns the proof that the leaf at index is included in the
+// tree of the given size.
+func (c *Client) InclusionProof(ctx context.Context, index, size int64) ([][]byte, error) {
+	if index < 0 || index >= size {
+		return nil, fmt.Errorf("leaf index %d out of range for tree size %d", index, size)
+	}
+	r := c.newReader(size)
+	return r.inclusionProof(ctx, index, 0, size)
+}
+
+// ConsistencyProof returns the proof that the tree of size size1 is a prefix
+// of the tree of size size2.
+func (c *Client) ConsistencyProof(ctx context.Context, size1, size2 int64) ([][]byte, error) {
+	if size1 < 0 || size1 > size2 {
+		return nil, fmt.Errorf("tree size %d out of range [0, %d]", size1, size2)
+	}
+	if size1 == 0 || size1 == size2 {
+		return nil, nil
+	}
+	r := c.newReader(size2)
+	return r.consistencyProof(ctx, size1, 0, size2, true)
+}
+
+// reader computes the hashes of the nodes of a tree of a fixed size, caching
+// the tiles it reads.
+type reader struct {
+	*Client
+	size  int64
+	tiles map[string][][]byte
+}
+
+func (c *Client) newReader(size int64) *reader {
+	return &reader{Client: c, size: size, tiles: make(map[string][][]byte)}
+}
+
+// inclusionProof implements PATH from RFC 6962, section 2.1.1, for the
+// subtree of leaves [begin, end).
+func (r *reader) inclusionProof(ctx context.Context, index, begin, end int64) ([][]byte, error) {
+	if end-begin == 1 {
+		return nil, nil
+	}
+	mid := begin + split(end-begin)
+	var proof [][]byte
+	var hash []byte
+	var err error
+	if index < mid {
+		proof, err = r.inclusionProof(ctx, index, begin, mid)
+		if err == nil {
+			hash, err = r.rangeHash(ctx, mid, end)
+		}
+	} else {
+		proof, err = r.inclusionProof(ctx, index, mid, end)
+		if err == nil {
+			hash, err = r.rangeHash(ctx, begin, mid)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append(proof, hash), nil
+}
+
+// consistencyProof implements SUBPROOF from RFC 6962, section 2.1.2, for the
+// subtree of leaves [begin, end), where size is relative to begin.
+func (r *reader) consistencyProof(ctx context.Context, size, begin, end int64, complete bool) ([][]byte, error) {
+	if size == end-begin {
+		if complete {
+			return nil, nil
+		}
+		hash, err := r.rangeHash(ctx, begin, end)
+		if err != nil {
+			return nil, err
+		}
+		return [][]byte{hash}, nil
+	}
+	mid := begin + split(end-begin)
+	var proof [][]byte
+	var hash []byte
+	var err error
+	if begin+size <= mid {
+		proof, err = r.consistencyProof(ctx, size, begin, mid, complete)
+		if err == nil {
+			hash, err = r.rangeHash(ctx, mid, end)
+		}
+	} else {
+		proof, err = r.consistencyProof(ctx, begin+size-mid, mid, end, false)
+		if err == nil {
+			hash, err = r.rangeHash(ctx, begin, mid)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append(proof, hash), nil
+}
+
+// rangeHash returns the hash of the subtree of leaves [begin, end), as
+// defined by MTH in RFC 6962, section 2.1. begin must be a multiple of the
+// largest power of two not greater than end-begin.
+func (r *reader) rangeHash(ctx context.Context, begin, end int64) ([]byte, error) {
+	n := end - begin
+	if n&(n-1) == 0 {
+		level := uint(bits.TrailingZeros64(uint64(n)))
+		return r.nodeHash(ctx, level, begin>>level)
+	}
+	mid := begin + split(n)
+	left, err := r.rangeHash(ctx, begin, mid)
+	if err != nil {
+		return nil, err
+	}
+	right, err := r.rangeHash(ctx, mid, end)
+	if err != nil {
+		return nil, err
+	}
+	return r.hasher.HashChildren(left, right), nil
+}
+
+// nodeHash returns the hash of the complete node at the given level and
+// index, computed from the tile holding its descendants at the nearest tile
+// level.
+func (r *reader) nodeHash(ctx context.Context, level uint, index int64) ([]byte, error) {
+	tileLevel, height := int64(level/TileHeight), level%TileHeight
+	first := index << height
+	n := first / TileWidth
+	width := tileWidth(n, r.size>>uint(tileLevel*TileHeight))
+	hashes, err := r.tile(ctx, tileLevel, n, width)
+	if err != nil {
+		return nil, err
+	}
+	start, count := first%TileWidth, int64(1)<<height
+	if start+count > int64(len(hashes)) {
+		return nil, fmt.Errorf("node at level %d index %d not in tile %s", level, index, TilePath(tileLevel, n, width))
+	}
+	hashes = append([][]byte(nil), hashes[start:start+count]...)
+	for len(hashes) > 1 {
+		for i := 0; i < len(hashes)/2; i++ {
+			hashes[i] = r.hasher.HashChildren(hashes[2*i], hashes[2*i+1])
+		}
+		hashes = hashes[:len(hashes)/2]
+	}
+	return hashes[0], nil
+}
+
+// tile returns the hashes held in the tile with index n at the given level.
+func (r *reader) tile(ctx context.Context, level, n int64, width int) ([][]byte, error) {
+	path := TilePath(level, n, width)
+	if hashes, ok := r.tiles[path]; ok {
+		return hashes, nil
+	}
+	data, err := r.fetch(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	hashes, err := splitHashes(data, width, r.hasher.Size())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	r.tiles[path] = hashes
+	return hashes, nil
+}
+
+// split returns the largest power of two smaller than n, which must be
+// greater than 1.
+func split(n int64) int64 {
+	return 1 << uint(bits.Len64(uint64(n-1))-1)
+}
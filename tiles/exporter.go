@@ -0,0 +1,207 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tiles
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/glog"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/trillian"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/types"
+)
+
+// maxTreeDepth is the depth of the node IDs of log trees.
+const maxTreeDepth = 64
+
+// Exporter writes the tiles of a log to a directory.
+type Exporter struct {
+	storage storage.ReadOnlyLogStorage
+	tree    *trillian.Tree
+	dir     string
+}
+
+// NewExporter returns an Exporter which reads tree from ls and writes its
+// tiles under dir.
+func NewExporter(ls storage.ReadOnlyLogStorage, tree *trillian.Tree, dir string) *Exporter {
+	return &Exporter{storage: ls, tree: tree, dir: dir}
+}
+
+// Export writes the tiles covering the latest root of the log which haven't
+// been written by previous calls, and then updates the checkpoint. It returns
+// the exported root.
+func (e *Exporter) Export(ctx context.Context) (*types.LogRootV1, error) {
+	prev, err := e.readCheckpoint()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %v", err)
+	}
+
+	tx, err := e.storage.SnapshotForTree(ctx, e.tree)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Close()
+	slr, err := tx.LatestSignedLogRoot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var root types.LogRootV1
+	if err := root.UnmarshalBinary(slr.LogRoot); err != nil {
+		return nil, err
+	}
+	if root.TreeSize < prev.TreeSize {
+		return nil, fmt.Errorf("latest root has size %d, smaller than the exported %d", root.TreeSize, prev.TreeSize)
+	}
+	rev, err := tx.ReadRevision(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	size, prevSize := int64(root.TreeSize), int64(prev.TreeSize)
+	for level := int64(0); size>>uint(level*TileHeight) > 0; level++ {
+		levelSize := size >> uint(level*TileHeight)
+		for n := (prevSize >> uint(level*TileHeight)) / TileWidth; ; n++ {
+			width := tileWidth(n, levelSize)
+			if width == 0 {
+				break
+			}
+			if err := e.exportTile(ctx, tx, rev, level, n, width); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for n := prevSize / TileWidth; ; n++ {
+		width := tileWidth(n, size)
+		if width == 0 {
+			break
+		}
+		if err := e.exportDataTile(ctx, tx, n, width); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	data, err := proto.Marshal(&slr)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.writeFile(CheckpointPath, data); err != nil {
+		return nil, err
+	}
+	return &root, nil
+}
+
+// readCheckpoint returns the root exported by the previous call to Export,
+// or a zero root if there is none.
+func (e *Exporter) readCheckpoint() (*types.LogRootV1, error) {
+	var root types.LogRootV1
+	data, err := ioutil.ReadFile(filepath.Join(e.dir, CheckpointPath))
+	if os.IsNotExist(err) {
+		return &root, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var slr trillian.SignedLogRoot
+	if err := proto.Unmarshal(data, &slr); err != nil {
+		return nil, err
+	}
+	if err := root.UnmarshalBinary(slr.LogRoot); err != nil {
+		return nil, err
+	}
+	return &root, nil
+}
+
+// exportTile writes the tile with index n at the given level, unless it
+// already exists.
+func (e *Exporter) exportTile(ctx context.Context, tx storage.NodeReader, rev, level, n int64, width int) error {
+	path := TilePath(level, n, width)
+	if e.exists(path) {
+		return nil
+	}
+	ids := make([]storage.NodeID, width)
+	for i := range ids {
+		id, err := storage.NewNodeIDForTreeCoords(level*TileHeight, n*TileWidth+int64(i), maxTreeDepth)
+		if err != nil {
+			return err
+		}
+		ids[i] = id
+	}
+	nodes, err := tx.GetMerkleNodes(ctx, rev, ids)
+	if err != nil {
+		return fmt.Errorf("failed to read nodes of tile %s: %v", path, err)
+	}
+	if len(nodes) != len(ids) {
+		return fmt.Errorf("got %d nodes for tile %s, want %d", len(nodes), path, len(ids))
+	}
+	hashes := make([][]byte, len(nodes))
+	for i, node := range nodes {
+		if !node.NodeID.Equivalent(ids[i]) {
+			return fmt.Errorf("got node %v for tile %s, want %v", node.NodeID.CoordString(), path, ids[i].CoordString())
+		}
+		hashes[i] = node.Hash
+	}
+	glog.V(1).Infof("%d: writing %s", e.tree.TreeId, path)
+	return e.writeFile(path, joinHashes(hashes))
+}
+
+// exportDataTile writes the data tile with index n, unless it already exists.
+func (e *Exporter) exportDataTile(ctx context.Context, tx storage.ReadOnlyLogTreeTX, n int64, width int) error {
+	path := DataTilePath(n, width)
+	if e.exists(path) {
+		return nil
+	}
+	leaves, err := tx.GetLeavesByRange(ctx, n*TileWidth, int64(width))
+	if err != nil {
+		return fmt.Errorf("failed to read leaves of tile %s: %v", path, err)
+	}
+	if len(leaves) != width {
+		return fmt.Errorf("got %d leaves for tile %s, want %d", len(leaves), path, width)
+	}
+	values := make([][]byte, len(leaves))
+	for i, leaf := range leaves {
+		if want := n*TileWidth + int64(i); leaf.LeafIndex != want {
+			return fmt.Errorf("got leaf %d for tile %s, want %d", leaf.LeafIndex, path, want)
+		}
+		values[i] = leaf.LeafValue
+	}
+	glog.V(1).Infof("%d: writing %s", e.tree.TreeId, path)
+	return e.writeFile(path, EncodeDataTile(values))
+}
+
+func (e *Exporter) exists(path string) bool {
+	_, err := os.Stat(filepath.Join(e.dir, path))
+	return err == nil
+}
+
+// writeFile writes data to path atomically, so readers never see a partially
+// written file.
+func (e *Exporter) writeFile(path string, data []byte) error {
+	file := filepath.Join(e.dir, path)
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	tmp := file + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
@@ -0,0 +1,209 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tiles
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/trillian"
+	"github.com/google/trillian/client"
+	"github.com/google/trillian/log"
+	"github.com/google/trillian/merkle"
+	"github.com/google/trillian/merkle/rfc6962"
+	"github.com/google/trillian/quota"
+	"github.com/google/trillian/storage"
+	"github.com/google/trillian/storage/memory"
+	"github.com/google/trillian/trees"
+	"github.com/google/trillian/types"
+	"github.com/google/trillian/util/clock"
+
+	stestonly "github.com/google/trillian/storage/testonly"
+)
+
+// testLog is a log in memory storage, sequenced as leaves are added.
+type testLog struct {
+	storage storage.LogStorage
+	tree    *trillian.Tree
+	seq     *log.Sequencer
+	size    int
+}
+
+func newTestLog(ctx context.Context, t *testing.T) *testLog {
+	t.Helper()
+	ts := memory.NewTreeStorage()
+	ls := memory.NewLogStorage(ts, nil)
+	tree, err := storage.CreateTree(ctx, memory.NewAdminStorage(ts), stestonly.LogTree)
+	if err != nil {
+		t.Fatalf("CreateTree(): %v", err)
+	}
+	signer, err := trees.Signer(ctx, tree)
+	if err != nil {
+		t.Fatalf("Signer(): %v", err)
+	}
+	root, err := signer.SignLogRoot(&types.LogRootV1{RootHash: rfc6962.DefaultHasher.EmptyRoot()})
+	if err != nil {
+		t.Fatalf("SignLogRoot(): %v", err)
+	}
+	if err := ls.ReadWriteTransaction(ctx, tree, func(ctx context.Context, tx storage.LogTreeTX) error {
+		return tx.StoreSignedLogRoot(ctx, *root)
+	}); err != nil {
+		t.Fatalf("StoreSignedLogRoot(): %v", err)
+	}
+	seq := log.NewSequencer(rfc6962.DefaultHasher, clock.System, ls, signer, nil, quota.Noop())
+	return &testLog{storage: ls, tree: tree, seq: seq}
+}
+
+func leafValue(index int64) []byte {
+	return []byte(fmt.Sprintf("leaf %d", index))
+}
+
+// grow adds leaves to the log until it has size leaves.
+func (l *testLog) grow(ctx context.Context, t *testing.T, size int) {
+	t.Helper()
+	var leaves []*trillian.LogLeaf
+	for i := l.size; i < size; i++ {
+		value := leafValue(int64(i))
+		hash := rfc6962.DefaultHasher.HashLeaf(value)
+		leaves = append(leaves, &trillian.LogLeaf{LeafValue: value, LeafIdentityHash: hash, MerkleLeafHash: hash})
+	}
+	if len(leaves) == 0 {
+		return
+	}
+	if _, err := l.storage.QueueLeaves(ctx, l.tree, leaves, time.Now()); err != nil {
+		t.Fatalf("QueueLeaves(): %v", err)
+	}
+	if n, err := l.seq.IntegrateBatch(ctx, l.tree, len(leaves), 0, 0); err != nil || n != len(leaves) {
+		t.Fatalf("IntegrateBatch(): %d, %v, want %d leaves", n, err, len(leaves))
+	}
+	l.size = size
+}
+
+// readFiles returns the contents of all of the files under dir, by path.
+func readFiles(t *testing.T, dir string) map[string][]byte {
+	t.Helper()
+	files := make(map[string][]byte)
+	if err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		files[path] = data
+		return nil
+	}); err != nil {
+		t.Fatalf("Walk(): %v", err)
+	}
+	return files
+}
+
+func TestExport(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "tiles")
+	if err != nil {
+		t.Fatalf("TempDir(): %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	l := newTestLog(ctx, t)
+	e := NewExporter(l.storage, l.tree, dir)
+	c := NewClient(DirFetcher(dir), rfc6962.DefaultHasher)
+	logVerifier, err := client.NewLogVerifierFromTree(l.tree)
+	if err != nil {
+		t.Fatalf("NewLogVerifierFromTree(): %v", err)
+	}
+	merkleVerifier := merkle.NewLogVerifier(rfc6962.DefaultHasher)
+
+	// The roots exported so far, all of which must stay provable.
+	var roots []*types.LogRootV1
+	prevFiles := make(map[string][]byte)
+	for _, size := range []int{0, 5, 256, 256, 300, 70000, 70001} {
+		l.grow(ctx, t, size)
+		root, err := e.Export(ctx)
+		if err != nil {
+			t.Fatalf("Export() at size %d: %v", size, err)
+		}
+		if got, want := root.TreeSize, uint64(size); got != want {
+			t.Fatalf("Export(): root of size %d, want %d", got, want)
+		}
+
+		files := readFiles(t, dir)
+		for path, data := range prevFiles {
+			if filepath.Base(path) == CheckpointPath {
+				continue
+			}
+			if !bytes.Equal(files[path], data) {
+				t.Errorf("size %d: %s was modified", size, path)
+			}
+		}
+		prevFiles = files
+
+		slr, err := c.Checkpoint(ctx)
+		if err != nil {
+			t.Fatalf("Checkpoint(): %v", err)
+		}
+		checkpoint, err := logVerifier.VerifyRoot(&types.LogRootV1{}, slr, nil)
+		if err != nil {
+			t.Fatalf("VerifyRoot(): %v", err)
+		}
+		if checkpoint.TreeSize != root.TreeSize || !bytes.Equal(checkpoint.RootHash, root.RootHash) {
+			t.Fatalf("Checkpoint(): %+v, want %+v", checkpoint, root)
+		}
+		roots = append(roots, root)
+
+		for _, old := range roots {
+			proof, err := c.ConsistencyProof(ctx, int64(old.TreeSize), int64(root.TreeSize))
+			if err != nil {
+				t.Fatalf("ConsistencyProof(%d, %d): %v", old.TreeSize, root.TreeSize, err)
+			}
+			if err := merkleVerifier.VerifyConsistencyProof(int64(old.TreeSize), int64(root.TreeSize), old.RootHash, root.RootHash, proof); err != nil {
+				t.Errorf("VerifyConsistencyProof(%d, %d): %v", old.TreeSize, root.TreeSize, err)
+			}
+
+			for _, index := range []int64{0, int64(old.TreeSize) / 2, int64(old.TreeSize) - 1} {
+				if index < 0 || index >= int64(old.TreeSize) {
+					continue
+				}
+				value, err := c.Leaf(ctx, index, int64(old.TreeSize))
+				if err != nil {
+					t.Fatalf("Leaf(%d, %d): %v", index, old.TreeSize, err)
+				}
+				if want := leafValue(index); !bytes.Equal(value, want) {
+					t.Errorf("Leaf(%d, %d): %q, want %q", index, old.TreeSize, value, want)
+				}
+				proof, err := c.InclusionProof(ctx, index, int64(old.TreeSize))
+				if err != nil {
+					t.Fatalf("InclusionProof(%d, %d): %v", index, old.TreeSize, err)
+				}
+				leafHash := rfc6962.DefaultHasher.HashLeaf(value)
+				if err := merkleVerifier.VerifyInclusionProof(index, int64(old.TreeSize), proof, old.RootHash, leafHash); err != nil {
+					t.Errorf("VerifyInclusionProof(%d, %d): %v", index, old.TreeSize, err)
+				}
+			}
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, TilePath(2, 0, 1))); err != nil {
+		t.Errorf("level 2 tile not exported: %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tiles exports a Trillian log as a set of static, immutable files,
+// which can be served by any web server or CDN. Clients can verify inclusion
+// and consistency proofs against the log's signed roots using only the
+// exported files, without calling the gRPC API.
+//
+// The layout follows the "tlog tiles" scheme used by the Go checksum
+// database. A tile of height 8 at level L holds up to 256 consecutive hashes
+// of the tree nodes at height 8*L, i.e. the leaf hashes for level 0, the roots
+// of complete 256-leaf subtrees for level 1, and so on. Tile N at level L is
+// stored at tile/8/L/N, where N is written in groups of three digits, as in
+// tile/8/0/x001/x234/067 for N=1234067. A tile with only W < 256 hashes (at
+// the right edge of the tree) is stored at tile/8/L/N.p/W. The leaf values
+// themselves are stored in data tiles at tile/8/data/N, each holding the
+// leaves hashed by tile N of level 0, encoded as described in EncodeDataTile.
+//
+// Tiles are never modified once written: as the log grows, new full and
+// partial tiles are added beside the old ones. The latest exported root is
+// stored at "checkpoint", as a serialized trillian.SignedLogRoot, and is
+// updated after all of the tiles it covers have been written.
+package tiles
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+const (
+	// TileHeight is the height of the tiles, which matches the height of the
+	// subtrees that Trillian stores log nodes in.
+	TileHeight = 8
+	// TileWidth is the number of hashes in a full tile.
+	TileWidth = 1 << TileHeight
+
+	// CheckpointPath is the path of the latest exported root.
+	CheckpointPath = "checkpoint"
+)
+
+// TilePath returns the path of the tile with index n at the given level,
+// holding width hashes.
+func TilePath(level, n int64, width int) string {
+	return tilePath(fmt.Sprint(level), n, width)
+}
+
+// DataTilePath returns the path of the data tile with index n, holding width
+// leaves.
+func DataTilePath(n int64, width int) string {
+	return tilePath("data", n, width)
+}
+
+func tilePath(level string, n int64, width int) string {
+	nStr := fmt.Sprintf("%03d", n%1000)
+	for n >= 1000 {
+		n /= 1000
+		nStr = fmt.Sprintf("x%03d/%s", n%1000, nStr)
+	}
+	path := fmt.Sprintf("tile/%d/%s/%s", TileHeight, level, nStr)
+	if width < TileWidth {
+		path += fmt.Sprintf(".p/%d", width)
+	}
+	return path
+}
+
+// tileWidth returns the number of hashes in tile n at a level which has
+// levelSize nodes, or 0 if there is no such tile.
+func tileWidth(n, levelSize int64) int {
+	width := levelSize - n*TileWidth
+	switch {
+	case width <= 0:
+		return 0
+	case width > TileWidth:
+		return TileWidth
+	}
+	return int(width)
+}
+
+// EncodeDataTile returns the contents of a data tile holding the given leaf
+// values. Each value is encoded as its length, as a 4-byte big-endian integer,
+// followed by the value itself.
+func EncodeDataTile(values [][]byte) []byte {
+	size := 0
+	for _, value := range values {
+		size += 4 + len(value)
+	}
+	data := make([]byte, 0, size)
+	for _, value := range values {
+		var length [4]byte
+		binary.BigEndian.PutUint32(length[:], uint32(len(value)))
+		data = append(data, length[:]...)
+		data = append(data, value...)
+	}
+	return data
+}
+
+// ParseDataTile returns the leaf values held in a data tile.
+func ParseDataTile(data []byte) ([][]byte, error) {
+	var values [][]byte
+	for len(data) > 0 {
+		if len(data) < 4 {
+			return nil, fmt.Errorf("truncated length of leaf %d", len(values))
+		}
+		size := binary.BigEndian.Uint32(data)
+		data = data[4:]
+		if uint64(len(data)) < uint64(size) {
+			return nil, fmt.Errorf("truncated value of leaf %d: got %d bytes, want %d", len(values), len(data), size)
+		}
+		values = append(values, data[:size])
+		data = data[size:]
+	}
+	return values, nil
+}
+
+// splitHashes splits the contents of a tile into width hashes of hashSize
+// bytes each.
+func splitHashes(data []byte, width, hashSize int) ([][]byte, error) {
+	if len(data) != width*hashSize {
+		return nil, fmt.Errorf("tile has %d bytes, want %d", len(data), width*hashSize)
+	}
+	hashes := make([][]byte, width)
+	for i := range hashes {
+		hashes[i] = data[i*hashSize : (i+1)*hashSize]
+	}
+	return hashes, nil
+}
+
+// joinHashes returns the contents of a tile holding hashes.
+func joinHashes(hashes [][]byte) []byte {
+	return bytes.Join(hashes, nil)
+}
@@ -0,0 +1,59 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tiles
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTilePath(t *testing.T) {
+	for _, test := range []struct {
+		level, n int64
+		width    int
+		want     string
+	}{
+		{level: 0, n: 0, width: 256, want: "tile/8/0/000"},
+		{level: 0, n: 0, width: 5, want: "tile/8/0/000.p/5"},
+		{level: 1, n: 999, width: 256, want: "tile/8/1/999"},
+		{level: 2, n: 1000, width: 256, want: "tile/8/2/x001/000"},
+		{level: 0, n: 1234067, width: 1, want: "tile/8/0/x001/x234/067.p/1"},
+	} {
+		if got := TilePath(test.level, test.n, test.width); got != test.want {
+			t.Errorf("TilePath(%d, %d, %d)=%q, want %q", test.level, test.n, test.width, got, test.want)
+		}
+	}
+	if got, want := DataTilePath(1234067, 256), "tile/8/data/x001/x234/067"; got != want {
+		t.Errorf("DataTilePath()=%q, want %q", got, want)
+	}
+}
+
+func TestDataTile(t *testing.T) {
+	values := [][]byte{[]byte("one"), {}, []byte("three")}
+	data := EncodeDataTile(values)
+	got, err := ParseDataTile(data)
+	if err != nil {
+		t.Fatalf("ParseDataTile(): %v", err)
+	}
+	if !reflect.DeepEqual(got, values) {
+		t.Errorf("ParseDataTile()=%q, want %q", got, values)
+	}
+
+	for _, size := range []int{2, 5, len(data) - 1} {
+		if _, err := ParseDataTile(data[:size]); err == nil {
+			t.Errorf("ParseDataTile() of %d bytes: nil, want error", size)
+		}
+	}
+}
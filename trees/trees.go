@@ -27,6 +27,7 @@ import (
 	"github.com/google/trillian/crypto/sigpb"
 	"github.com/google/trillian/monitoring"
 	"github.com/google/trillian/storage"
+	"github.com/google/trillian/types"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -212,7 +213,9 @@ func Signer(ctx context.Context, tree *trillian.Tree) (*tcrypto.Signer, error) {
 		return nil, fmt.Errorf("%s signature not supported by signer of type %T", tree.SignatureAlgorithm, signer)
 	}
 
-	return tcrypto.NewSigner(tree.GetTreeId(), signer, hash), nil
+	tSigner := tcrypto.NewSigner(tree.GetTreeId(), signer, hash)
+	tSigner.KeyHint = types.SerializeKeyVersionHint(tree.GetTreeId(), tree.GetKeyId())
+	return tSigner, nil
 }
 
 func spanFor(ctx context.Context, name string) (context.Context, func()) {
@@ -33,6 +33,7 @@ import (
 	"github.com/google/trillian/crypto/sigpb"
 	"github.com/google/trillian/storage"
 	"github.com/google/trillian/storage/testonly"
+	"github.com/google/trillian/types"
 	"github.com/kylelemons/godebug/pretty"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -373,6 +374,7 @@ func TestSigner(t *testing.T) {
 		desc         string
 		sigAlgo      sigpb.DigitallySigned_SignatureAlgorithm
 		signer       crypto.Signer
+		keyID        int64
 		newSignerErr error
 		wantErr      bool
 	}{
@@ -391,6 +393,12 @@ func TestSigner(t *testing.T) {
 			sigAlgo: sigpb.DigitallySigned_RSA,
 			signer:  rsaKey,
 		},
+		{
+			desc:    "rotatedKey",
+			sigAlgo: sigpb.DigitallySigned_ECDSA,
+			signer:  ecdsaKey,
+			keyID:   3,
+		},
 		{
 			desc:    "keyMismatch1",
 			sigAlgo: sigpb.DigitallySigned_ECDSA,
@@ -418,6 +426,7 @@ func TestSigner(t *testing.T) {
 			tree.HashAlgorithm = sigpb.DigitallySigned_SHA256
 			tree.HashStrategy = trillian.HashStrategy_RFC6962_SHA256
 			tree.SignatureAlgorithm = test.sigAlgo
+			tree.KeyId = test.keyID
 
 			var wantKeyProto ptypes.DynamicAny
 			if err := ptypes.UnmarshalAny(tree.PrivateKey, &wantKeyProto); err != nil {
@@ -440,6 +449,7 @@ func TestSigner(t *testing.T) {
 			}
 
 			want := tcrypto.NewSigner(0, test.signer, crypto.SHA256)
+			want.KeyHint = types.SerializeKeyVersionHint(0, test.keyID)
 			if diff := pretty.Compare(signer, want); diff != "" {
 				t.Fatalf("post-Signer(_, %s) diff:\n%v", test.sigAlgo, diff)
 			}
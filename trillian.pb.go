@@ -257,13 +257,14 @@ type Tree struct {
 	// Private keys are write-only: they're never returned by RPCs.
 	// The private_key message can be changed after a tree is created, but the
 	// underlying key must remain the same - this is to enable migrating a key
-	// from one provider to another.
+	// from one provider to another. The key itself can only be replaced through
+	// TrillianAdmin.AddTreeKey.
 	PrivateKey *any.Any `protobuf:"bytes,12,opt,name=private_key,json=privateKey,proto3" json:"private_key,omitempty"`
 	// Storage-specific settings.
 	// Varies according to the storage implementation backing Trillian.
 	StorageSettings *any.Any `protobuf:"bytes,13,opt,name=storage_settings,json=storageSettings,proto3" json:"storage_settings,omitempty"`
 	// The public key used for verifying tree heads and entry timestamps.
-	// Readonly.
+	// Readonly (replaced by TrillianAdmin.AddTreeKey).
 	PublicKey *keyspb.PublicKey `protobuf:"bytes,14,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
 	// Interval after which a new signed root is produced even if there have been
 	// no submission.  If zero, this behavior is disabled.
@@ -281,10 +282,27 @@ type Tree struct {
 	Deleted bool `protobuf:"varint,19,opt,name=deleted,proto3" json:"deleted,omitempty"`
 	// Time of tree deletion, if any.
 	// Readonly.
-	DeleteTime           *timestamp.Timestamp `protobuf:"bytes,20,opt,name=delete_time,json=deleteTime,proto3" json:"delete_time,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+	DeleteTime *timestamp.Timestamp `protobuf:"bytes,20,opt,name=delete_time,json=deleteTime,proto3" json:"delete_time,omitempty"`
+	// Maximum time a leaf may remain in the unsequenced queue of a LOG tree
+	// before it's expired and removed. If zero, queued leaves never expire.
+	QueueTtl *duration.Duration `protobuf:"bytes,21,opt,name=queue_ttl,json=queueTtl,proto3" json:"queue_ttl,omitempty"`
+	// Maximum number of unsequenced leaves that may be queued for a LOG tree.
+	// QueueLeaf(s) requests are rejected with RESOURCE_EXHAUSTED while the queue
+	// is at or above this depth. If zero, the queue depth is unlimited.
+	MaxQueueDepth int64 `protobuf:"varint,22,opt,name=max_queue_depth,json=maxQueueDepth,proto3" json:"max_queue_depth,omitempty"`
+	// ID of the key version that private_key and public_key belong to. The key
+	// a tree is created with has ID zero, and each key added with
+	// TrillianAdmin.AddTreeKey gets the next ID.
+	// Readonly (assigned by TrillianAdmin.AddTreeKey).
+	KeyId int64 `protobuf:"varint,23,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	// Public keys of the key versions that preceded the current one, oldest
+	// first. They're kept so that roots signed before a key rotation can still
+	// be verified.
+	// Readonly (assigned by TrillianAdmin.AddTreeKey).
+	PreviousKeys         []*TreeKey `protobuf:"bytes,24,rep,name=previous_keys,json=previousKeys,proto3" json:"previous_keys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
 }
 
 func (m *Tree) Reset()         { *m = Tree{} }
@@ -424,6 +442,84 @@ func (m *Tree) GetDeleteTime() *timestamp.Timestamp {
 	return nil
 }
 
+func (m *Tree) GetQueueTtl() *duration.Duration {
+	if m != nil {
+		return m.QueueTtl
+	}
+	return nil
+}
+
+func (m *Tree) GetMaxQueueDepth() int64 {
+	if m != nil {
+		return m.MaxQueueDepth
+	}
+	return 0
+}
+
+func (m *Tree) GetKeyId() int64 {
+	if m != nil {
+		return m.KeyId
+	}
+	return 0
+}
+
+func (m *Tree) GetPreviousKeys() []*TreeKey {
+	if m != nil {
+		return m.PreviousKeys
+	}
+	return nil
+}
+
+// TreeKey is a version of the signing key of a tree.
+type TreeKey struct {
+	// ID of the key version within its tree. See Tree.key_id.
+	KeyId int64 `protobuf:"varint,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	// The public key of the key version.
+	PublicKey            *keyspb.PublicKey `protobuf:"bytes,2,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *TreeKey) Reset()         { *m = TreeKey{} }
+func (m *TreeKey) String() string { return proto.CompactTextString(m) }
+func (*TreeKey) ProtoMessage()    {}
+func (*TreeKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_364603a4e17a2a56, []int{1}
+}
+
+func (m *TreeKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TreeKey.Unmarshal(m, b)
+}
+func (m *TreeKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TreeKey.Marshal(b, m, deterministic)
+}
+func (m *TreeKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TreeKey.Merge(m, src)
+}
+func (m *TreeKey) XXX_Size() int {
+	return xxx_messageInfo_TreeKey.Size(m)
+}
+func (m *TreeKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_TreeKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TreeKey proto.InternalMessageInfo
+
+func (m *TreeKey) GetKeyId() int64 {
+	if m != nil {
+		return m.KeyId
+	}
+	return 0
+}
+
+func (m *TreeKey) GetPublicKey() *keyspb.PublicKey {
+	if m != nil {
+		return m.PublicKey
+	}
+	return nil
+}
+
 type SignedEntryTimestamp struct {
 	TimestampNanos       int64                  `protobuf:"varint,1,opt,name=timestamp_nanos,json=timestampNanos,proto3" json:"timestamp_nanos,omitempty"`
 	LogId                int64                  `protobuf:"varint,2,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
@@ -437,7 +533,7 @@ func (m *SignedEntryTimestamp) Reset()         { *m = SignedEntryTimestamp{} }
 func (m *SignedEntryTimestamp) String() string { return proto.CompactTextString(m) }
 func (*SignedEntryTimestamp) ProtoMessage()    {}
 func (*SignedEntryTimestamp) Descriptor() ([]byte, []int) {
-	return fileDescriptor_364603a4e17a2a56, []int{1}
+	return fileDescriptor_364603a4e17a2a56, []int{2}
 }
 
 func (m *SignedEntryTimestamp) XXX_Unmarshal(b []byte) error {
@@ -485,7 +581,9 @@ type SignedLogRoot struct {
 	// key_hint is not authenticated and may be incorrect or missing, in which
 	// case all known public keys may be used to verify the signature.
 	// When directly communicating with a Trillian gRPC server, the key_hint will
-	// typically contain the LogID encoded as a big-endian 64-bit integer;
+	// typically contain the LogID encoded as a big-endian 64-bit integer,
+	// followed by the Tree.key_id of the signing key encoded the same way if the
+	// key has been rotated (see TrillianAdmin.AddTreeKey);
 	// however, in other contexts the key_hint is likely to have different
 	// contents (e.g. it could be a GUID, a URL + TreeID, or it could be
 	// derived from the public key itself).
@@ -535,7 +633,7 @@ func (m *SignedLogRoot) Reset()         { *m = SignedLogRoot{} }
 func (m *SignedLogRoot) String() string { return proto.CompactTextString(m) }
 func (*SignedLogRoot) ProtoMessage()    {}
 func (*SignedLogRoot) Descriptor() ([]byte, []int) {
-	return fileDescriptor_364603a4e17a2a56, []int{2}
+	return fileDescriptor_364603a4e17a2a56, []int{3}
 }
 
 func (m *SignedLogRoot) XXX_Unmarshal(b []byte) error {
@@ -607,7 +705,7 @@ func (m *SignedMapRoot) Reset()         { *m = SignedMapRoot{} }
 func (m *SignedMapRoot) String() string { return proto.CompactTextString(m) }
 func (*SignedMapRoot) ProtoMessage()    {}
 func (*SignedMapRoot) Descriptor() ([]byte, []int) {
-	return fileDescriptor_364603a4e17a2a56, []int{3}
+	return fileDescriptor_364603a4e17a2a56, []int{4}
 }
 
 func (m *SignedMapRoot) XXX_Unmarshal(b []byte) error {
@@ -649,6 +747,7 @@ func init() {
 	proto.RegisterEnum("trillian.TreeState", TreeState_name, TreeState_value)
 	proto.RegisterEnum("trillian.TreeType", TreeType_name, TreeType_value)
 	proto.RegisterType((*Tree)(nil), "trillian.Tree")
+	proto.RegisterType((*TreeKey)(nil), "trillian.TreeKey")
 	proto.RegisterType((*SignedEntryTimestamp)(nil), "trillian.SignedEntryTimestamp")
 	proto.RegisterType((*SignedLogRoot)(nil), "trillian.SignedLogRoot")
 	proto.RegisterType((*SignedMapRoot)(nil), "trillian.SignedMapRoot")
@@ -657,71 +756,77 @@ func init() {
 func init() { proto.RegisterFile("trillian.proto", fileDescriptor_364603a4e17a2a56) }
 
 var fileDescriptor_364603a4e17a2a56 = []byte{
-	// 1052 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x55, 0x5b, 0x6f, 0xe2, 0x46,
-	0x14, 0x5e, 0x83, 0x01, 0x33, 0x5c, 0x32, 0x99, 0xec, 0xc5, 0xa1, 0x55, 0x97, 0x46, 0x95, 0x4a,
-	0xa3, 0x8a, 0x74, 0x69, 0x13, 0xa9, 0xda, 0x87, 0xca, 0x09, 0x4e, 0x80, 0x24, 0x80, 0xc6, 0xee,
-	0x56, 0x9b, 0x97, 0x91, 0x81, 0xa9, 0xb1, 0xe2, 0x9b, 0xec, 0xa1, 0x5a, 0xff, 0x84, 0xaa, 0x7d,
-	0xdf, 0xbf, 0x5b, 0xcd, 0xf8, 0x42, 0x42, 0x76, 0xbb, 0x2f, 0xc9, 0x9c, 0xf3, 0x5d, 0xce, 0x19,
-	0xcf, 0x19, 0x06, 0xb4, 0x59, 0xe4, 0xb8, 0xae, 0x63, 0xf9, 0xfd, 0x30, 0x0a, 0x58, 0x80, 0x94,
-	0x3c, 0xee, 0x74, 0x96, 0x51, 0x12, 0xb2, 0xe0, 0xe4, 0x9e, 0x26, 0x71, 0xb8, 0xc8, 0xfe, 0xa5,
-	0xac, 0x8e, 0x9a, 0x61, 0xb1, 0x63, 0x87, 0x8b, 0xf4, 0x6f, 0x86, 0x1c, 0xda, 0x41, 0x60, 0xbb,
-	0xf4, 0x44, 0x44, 0x8b, 0xcd, 0x9f, 0x27, 0x96, 0x9f, 0x64, 0xd0, 0x37, 0xbb, 0xd0, 0x6a, 0x13,
-	0x59, 0xcc, 0x09, 0xb2, 0xd2, 0x9d, 0xd7, 0xbb, 0x38, 0x73, 0x3c, 0x1a, 0x33, 0xcb, 0x0b, 0x53,
-	0xc2, 0xd1, 0xdf, 0x35, 0x20, 0x9b, 0x11, 0xa5, 0xe8, 0x15, 0xa8, 0xb1, 0x88, 0x52, 0xe2, 0xac,
-	0x54, 0xa9, 0x2b, 0xf5, 0xca, 0xb8, 0xca, 0xc3, 0xf1, 0x0a, 0x0d, 0x00, 0x10, 0x40, 0xcc, 0x2c,
-	0x46, 0xd5, 0x52, 0x57, 0xea, 0xb5, 0x07, 0x07, 0xfd, 0x62, 0x8b, 0x5c, 0x6c, 0x70, 0x08, 0xd7,
-	0x59, 0xbe, 0x44, 0x27, 0x40, 0x04, 0x84, 0x25, 0x21, 0x55, 0xcb, 0x42, 0x82, 0x1e, 0x4b, 0xcc,
-	0x24, 0xa4, 0x58, 0x61, 0xd9, 0x0a, 0xbd, 0x05, 0xad, 0xb5, 0x15, 0xaf, 0x49, 0xcc, 0x22, 0x8b,
-	0x51, 0x3b, 0x51, 0x65, 0x21, 0x7a, 0xb9, 0x15, 0x8d, 0xac, 0x78, 0x6d, 0x64, 0x28, 0x6e, 0xae,
-	0x1f, 0x44, 0xe8, 0x1a, 0xb4, 0x85, 0xd8, 0x72, 0xed, 0x20, 0x72, 0xd8, 0xda, 0x53, 0x2b, 0x42,
-	0xfd, 0x5d, 0x3f, 0xfd, 0x8a, 0x43, 0xc7, 0x76, 0x98, 0xe5, 0xba, 0x89, 0xe1, 0xd8, 0x3e, 0x5d,
-	0x09, 0x2b, 0x2d, 0xe7, 0x62, 0x51, 0xb8, 0x08, 0xd1, 0x1d, 0x38, 0x88, 0x1d, 0xdb, 0xb7, 0xd8,
-	0x26, 0xa2, 0x0f, 0x1c, 0xab, 0xc2, 0xf1, 0x87, 0xcf, 0x38, 0x1a, 0xb9, 0x62, 0x6b, 0x8b, 0xe2,
-	0x27, 0x39, 0xf4, 0x2d, 0x68, 0xae, 0x9c, 0x38, 0x74, 0xad, 0x84, 0xf8, 0x96, 0x47, 0x55, 0xa5,
-	0x2b, 0xf5, 0xea, 0xb8, 0x91, 0xe5, 0xa6, 0x96, 0x47, 0x51, 0x17, 0x34, 0x56, 0x34, 0x5e, 0x46,
-	0x4e, 0xc8, 0x4f, 0x51, 0xad, 0x67, 0x8c, 0x6d, 0x0a, 0x9d, 0x82, 0x46, 0x18, 0x39, 0x7f, 0x59,
-	0x8c, 0x92, 0x7b, 0x9a, 0xa8, 0xcd, 0xae, 0xd4, 0x6b, 0x0c, 0x9e, 0xf7, 0xd3, 0x83, 0xee, 0xe7,
-	0x07, 0xdd, 0xd7, 0xfc, 0x04, 0x83, 0x8c, 0x78, 0x4d, 0x13, 0xf4, 0x1b, 0x80, 0x31, 0x0b, 0x22,
-	0xcb, 0xa6, 0x24, 0xa6, 0x8c, 0x39, 0xbe, 0x1d, 0xab, 0xad, 0xff, 0xd1, 0xee, 0x65, 0x6c, 0x23,
-	0x23, 0xa3, 0x9f, 0x00, 0x08, 0x37, 0x0b, 0xd7, 0x59, 0x8a, 0xb2, 0x6d, 0x21, 0xdd, 0xef, 0x67,
-	0x23, 0x3c, 0x17, 0xc8, 0x35, 0x4d, 0x70, 0x3d, 0xcc, 0x97, 0x48, 0x07, 0xfb, 0x9e, 0xf5, 0x81,
-	0x44, 0x41, 0xc0, 0x48, 0x3e, 0x97, 0xea, 0x9e, 0x10, 0x1e, 0x3e, 0xa9, 0x39, 0xcc, 0x08, 0x78,
-	0xcf, 0xb3, 0x3e, 0xe0, 0x20, 0x60, 0x79, 0x02, 0xbd, 0x05, 0x8d, 0x65, 0x44, 0xf9, 0x7e, 0xf9,
-	0xf0, 0xaa, 0x50, 0x18, 0x74, 0x9e, 0x18, 0x98, 0xf9, 0x64, 0x63, 0x90, 0xd2, 0x79, 0x82, 0x8b,
-	0x37, 0xe1, 0xaa, 0x10, 0xef, 0x7f, 0x59, 0x9c, 0xd2, 0x85, 0x58, 0x05, 0xb5, 0x15, 0x75, 0x29,
-	0xa3, 0x2b, 0xf5, 0xa0, 0x2b, 0xf5, 0x14, 0x9c, 0x87, 0xdc, 0x36, 0x5d, 0xa6, 0xb6, 0xcf, 0xbf,
-	0x6c, 0x9b, 0xd2, 0x79, 0x62, 0x22, 0x2b, 0x08, 0x1e, 0x4c, 0x64, 0xa5, 0x06, 0x95, 0x89, 0xac,
-	0x00, 0xd8, 0x98, 0xc8, 0x4a, 0x03, 0x36, 0x8f, 0xfe, 0x95, 0xc0, 0xf3, 0x74, 0xa0, 0x74, 0x9f,
-	0x45, 0x49, 0x21, 0x46, 0xdf, 0x83, 0xbd, 0xe2, 0xde, 0x12, 0xdf, 0xf2, 0x83, 0x38, 0xbb, 0xa3,
-	0xed, 0x22, 0x3d, 0xe5, 0x59, 0xf4, 0x02, 0x54, 0xdd, 0xc0, 0xe6, 0x77, 0xb8, 0x24, 0xf0, 0x8a,
-	0x1b, 0xd8, 0xe3, 0x15, 0xfa, 0x05, 0xd4, 0x8b, 0x69, 0x14, 0xd7, 0xb1, 0x31, 0x78, 0xf9, 0xe9,
-	0x49, 0xc6, 0x5b, 0xe2, 0xd1, 0x47, 0x09, 0xb4, 0xd2, 0xec, 0x4d, 0x60, 0xf3, 0x13, 0x41, 0x87,
-	0x40, 0xb9, 0xa7, 0x09, 0x59, 0x3b, 0x3e, 0x53, 0x6b, 0x5d, 0xa9, 0xd7, 0xc4, 0xb5, 0x7b, 0x9a,
-	0x8c, 0x1c, 0x5f, 0x40, 0xbc, 0x32, 0x3f, 0x6b, 0x31, 0xd6, 0x4d, 0x5c, 0x73, 0x33, 0xd5, 0x8f,
-	0x00, 0xe5, 0x10, 0xd9, 0xb6, 0x51, 0x17, 0x24, 0x98, 0x91, 0x8a, 0x0b, 0x34, 0x91, 0x15, 0x09,
-	0x96, 0x26, 0xb2, 0x52, 0x82, 0xe5, 0x89, 0xac, 0x94, 0xa1, 0x3c, 0x91, 0x15, 0x19, 0x56, 0x26,
-	0xb2, 0x52, 0x81, 0xd5, 0x89, 0xac, 0x54, 0x61, 0xed, 0x28, 0xca, 0x1b, 0xbb, 0xb5, 0xc2, 0xbc,
-	0x31, 0xcf, 0x0a, 0xd3, 0xea, 0xa9, 0x71, 0xcd, 0xcb, 0xa0, 0xaf, 0x1f, 0xee, 0x5d, 0x16, 0xd8,
-	0x36, 0xf1, 0xc9, 0x6a, 0x45, 0x9d, 0xe2, 0x88, 0x14, 0x58, 0x3f, 0x1e, 0x82, 0x56, 0xf6, 0x19,
-	0x2e, 0x83, 0xc8, 0xb3, 0x18, 0xfa, 0x0a, 0xbc, 0xba, 0x99, 0x5d, 0x11, 0x3c, 0x9b, 0x99, 0xe4,
-	0x72, 0x86, 0x6f, 0x35, 0x93, 0xfc, 0x3e, 0xbd, 0x9e, 0xce, 0xfe, 0x98, 0xc2, 0x67, 0xe8, 0x25,
-	0x40, 0xbb, 0xe0, 0xbb, 0x37, 0x50, 0xe2, 0x2e, 0x59, 0xcf, 0x5b, 0x97, 0x5b, 0x6d, 0xfe, 0x79,
-	0x97, 0x5d, 0x50, 0xb8, 0x7c, 0x94, 0x40, 0xf3, 0xe1, 0xef, 0x21, 0x3a, 0x04, 0x2f, 0x32, 0x15,
-	0x19, 0x69, 0xc6, 0x88, 0x18, 0x26, 0xd6, 0x4c, 0xfd, 0xea, 0x3d, 0x7c, 0x86, 0x10, 0x68, 0xe3,
-	0xcb, 0x8b, 0xb3, 0x5f, 0xcf, 0x06, 0xc4, 0x18, 0x69, 0x83, 0xd3, 0x33, 0x28, 0xa1, 0x03, 0xb0,
-	0x67, 0xea, 0x86, 0x49, 0xb8, 0x39, 0xe7, 0xeb, 0x18, 0x96, 0xb8, 0xc7, 0xec, 0x7c, 0xa2, 0x5f,
-	0x98, 0x64, 0x87, 0x5f, 0x46, 0x2f, 0xc0, 0xfe, 0xc5, 0x6c, 0x3a, 0xbe, 0x36, 0x78, 0xea, 0xf4,
-	0xcd, 0x80, 0xf0, 0xb4, 0x8c, 0xf6, 0x41, 0x6b, 0x9b, 0xe6, 0xa9, 0xca, 0xf1, 0x3f, 0x12, 0xa8,
-	0x17, 0x2f, 0x02, 0xef, 0x3f, 0x6f, 0xcb, 0xc4, 0xba, 0x4e, 0x0c, 0x53, 0x33, 0x75, 0xf8, 0x0c,
-	0x01, 0x50, 0xd5, 0x2e, 0xcc, 0xf1, 0x3b, 0x1d, 0x4a, 0x7c, 0x7d, 0x89, 0x67, 0x77, 0xfa, 0x14,
-	0x96, 0xd0, 0x6b, 0xf0, 0x6a, 0xa8, 0xcf, 0xb1, 0x7e, 0xa1, 0x99, 0xfa, 0x90, 0x18, 0xb3, 0x4b,
-	0x93, 0x0c, 0xf5, 0x1b, 0xdd, 0xd4, 0x87, 0xb0, 0xdc, 0x29, 0x29, 0xd2, 0x0e, 0x61, 0xa4, 0xe1,
-	0x61, 0x41, 0x90, 0x05, 0xa1, 0x09, 0x94, 0x21, 0xd6, 0xc6, 0xd3, 0xf1, 0xf4, 0x0a, 0x56, 0x8e,
-	0xaf, 0x80, 0x92, 0xbf, 0x35, 0x7c, 0x0f, 0x8f, 0x7a, 0x31, 0xdf, 0xcf, 0x79, 0x2b, 0x35, 0x50,
-	0xbe, 0x99, 0x5d, 0x41, 0x89, 0x2f, 0x6e, 0xb5, 0x39, 0x2c, 0xf1, 0x0f, 0x36, 0xc7, 0xfa, 0x0c,
-	0x0f, 0x75, 0xac, 0x0f, 0x09, 0x07, 0xcb, 0xe7, 0x23, 0x70, 0xb8, 0x0c, 0xbc, 0xfc, 0x7a, 0x3f,
-	0x7e, 0xde, 0xcf, 0x5b, 0x66, 0x16, 0xcf, 0x79, 0x38, 0x97, 0xee, 0x3a, 0xb6, 0xc3, 0xd6, 0x9b,
-	0x45, 0x7f, 0x19, 0x78, 0x27, 0xd9, 0xfb, 0x9b, 0x4b, 0x16, 0x55, 0xa1, 0xf9, 0xf9, 0xbf, 0x00,
-	0x00, 0x00, 0xff, 0xff, 0x7f, 0x1e, 0xf7, 0xb2, 0x24, 0x08, 0x00, 0x00,
+	// 1151 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x56, 0xdd, 0x52, 0xdb, 0x46,
+	0x14, 0x8e, 0x6c, 0x61, 0xcb, 0xc7, 0x36, 0x2c, 0x4b, 0x00, 0xe1, 0x76, 0x1a, 0x97, 0xe9, 0xb4,
+	0x34, 0xd3, 0x31, 0x8d, 0xdb, 0x30, 0xd3, 0xc9, 0x45, 0x47, 0xc1, 0x02, 0xdb, 0x80, 0xed, 0xae,
+	0xd5, 0x74, 0x92, 0x9b, 0x1d, 0x81, 0xb7, 0xb2, 0x06, 0xfd, 0x55, 0x5a, 0x67, 0xa2, 0x67, 0x68,
+	0xef, 0xf3, 0x2a, 0x7d, 0xbc, 0xce, 0xae, 0x24, 0xdb, 0x40, 0x13, 0x6e, 0x60, 0xcf, 0xf9, 0x7e,
+	0xce, 0xd9, 0x5f, 0x19, 0x36, 0x79, 0xec, 0x7a, 0x9e, 0x6b, 0x07, 0x9d, 0x28, 0x0e, 0x79, 0x88,
+	0xb5, 0x22, 0x6e, 0xb5, 0x6e, 0xe2, 0x34, 0xe2, 0xe1, 0xf1, 0x2d, 0x4b, 0x93, 0xe8, 0x3a, 0xff,
+	0x97, 0xb1, 0x5a, 0x7a, 0x8e, 0x25, 0xae, 0x13, 0x5d, 0x67, 0x7f, 0x73, 0xe4, 0xc0, 0x09, 0x43,
+	0xc7, 0x63, 0xc7, 0x32, 0xba, 0x5e, 0xfc, 0x79, 0x6c, 0x07, 0x69, 0x0e, 0x7d, 0x75, 0x1f, 0x9a,
+	0x2d, 0x62, 0x9b, 0xbb, 0x61, 0x5e, 0xba, 0xf5, 0xec, 0x3e, 0xce, 0x5d, 0x9f, 0x25, 0xdc, 0xf6,
+	0xa3, 0x8c, 0x70, 0xf8, 0xaf, 0x06, 0xaa, 0x15, 0x33, 0x86, 0xf7, 0xa1, 0xca, 0x63, 0xc6, 0xa8,
+	0x3b, 0xd3, 0x95, 0xb6, 0x72, 0x54, 0x26, 0x15, 0x11, 0x0e, 0x66, 0xb8, 0x0b, 0x20, 0x81, 0x84,
+	0xdb, 0x9c, 0xe9, 0xa5, 0xb6, 0x72, 0xb4, 0xd9, 0xdd, 0xe9, 0x2c, 0xa7, 0x28, 0xc4, 0x53, 0x01,
+	0x91, 0x1a, 0x2f, 0x86, 0xf8, 0x18, 0x64, 0x40, 0x79, 0x1a, 0x31, 0xbd, 0x2c, 0x25, 0xf8, 0xae,
+	0xc4, 0x4a, 0x23, 0x46, 0x34, 0x9e, 0x8f, 0xf0, 0x2b, 0x68, 0xce, 0xed, 0x64, 0x4e, 0x13, 0x1e,
+	0xdb, 0x9c, 0x39, 0xa9, 0xae, 0x4a, 0xd1, 0xde, 0x4a, 0xd4, 0xb7, 0x93, 0xf9, 0x34, 0x47, 0x49,
+	0x63, 0xbe, 0x16, 0xe1, 0x0b, 0xd8, 0x94, 0x62, 0xdb, 0x73, 0xc2, 0xd8, 0xe5, 0x73, 0x5f, 0xdf,
+	0x90, 0xea, 0x6f, 0x3a, 0xd9, 0x2a, 0xf6, 0x5c, 0xc7, 0xe5, 0xb6, 0xe7, 0xa5, 0x53, 0xd7, 0x09,
+	0xd8, 0x4c, 0x5a, 0x19, 0x05, 0x97, 0x34, 0xe7, 0xeb, 0x21, 0x7e, 0x07, 0x3b, 0x89, 0xeb, 0x04,
+	0x36, 0x5f, 0xc4, 0x6c, 0xcd, 0xb1, 0x22, 0x1d, 0xbf, 0xff, 0x84, 0xe3, 0xb4, 0x50, 0xac, 0x6c,
+	0x71, 0xf2, 0x20, 0x87, 0xbf, 0x86, 0xc6, 0xcc, 0x4d, 0x22, 0xcf, 0x4e, 0x69, 0x60, 0xfb, 0x4c,
+	0xd7, 0xda, 0xca, 0x51, 0x8d, 0xd4, 0xf3, 0xdc, 0xc8, 0xf6, 0x19, 0x6e, 0x43, 0x7d, 0xc6, 0x92,
+	0x9b, 0xd8, 0x8d, 0xc4, 0x2e, 0xea, 0xb5, 0x9c, 0xb1, 0x4a, 0xe1, 0x97, 0x50, 0x8f, 0x62, 0xf7,
+	0xbd, 0xcd, 0x19, 0xbd, 0x65, 0xa9, 0xde, 0x68, 0x2b, 0x47, 0xf5, 0xee, 0xd3, 0x4e, 0xb6, 0xd1,
+	0x9d, 0x62, 0xa3, 0x3b, 0x46, 0x90, 0x12, 0xc8, 0x89, 0x17, 0x2c, 0xc5, 0xbf, 0x02, 0x4a, 0x78,
+	0x18, 0xdb, 0x0e, 0xa3, 0x09, 0xe3, 0xdc, 0x0d, 0x9c, 0x44, 0x6f, 0x7e, 0x46, 0xbb, 0x95, 0xb3,
+	0xa7, 0x39, 0x19, 0xff, 0x08, 0x10, 0x2d, 0xae, 0x3d, 0xf7, 0x46, 0x96, 0xdd, 0x94, 0xd2, 0xed,
+	0x4e, 0x7e, 0x84, 0x27, 0x12, 0xb9, 0x60, 0x29, 0xa9, 0x45, 0xc5, 0x10, 0x9b, 0xb0, 0xed, 0xdb,
+	0x1f, 0x68, 0x1c, 0x86, 0x9c, 0x16, 0xe7, 0x52, 0xdf, 0x92, 0xc2, 0x83, 0x07, 0x35, 0x7b, 0x39,
+	0x81, 0x6c, 0xf9, 0xf6, 0x07, 0x12, 0x86, 0xbc, 0x48, 0xe0, 0x57, 0x50, 0xbf, 0x89, 0x99, 0x98,
+	0xaf, 0x38, 0xbc, 0x3a, 0x92, 0x06, 0xad, 0x07, 0x06, 0x56, 0x71, 0xb2, 0x09, 0x64, 0x74, 0x91,
+	0x10, 0xe2, 0x45, 0x34, 0x5b, 0x8a, 0xb7, 0x1f, 0x17, 0x67, 0x74, 0x29, 0xd6, 0xa1, 0x3a, 0x63,
+	0x1e, 0xe3, 0x6c, 0xa6, 0xef, 0xb4, 0x95, 0x23, 0x8d, 0x14, 0xa1, 0xb0, 0xcd, 0x86, 0x99, 0xed,
+	0xd3, 0xc7, 0x6d, 0x33, 0xba, 0xb4, 0x3d, 0x81, 0xda, 0x5f, 0x0b, 0xb6, 0x60, 0x94, 0x73, 0x4f,
+	0xdf, 0x7d, 0x6c, 0x3d, 0x34, 0xc9, 0xb5, 0xb8, 0x87, 0xbf, 0x05, 0xb1, 0x36, 0x34, 0xd3, 0xce,
+	0x58, 0xc4, 0xe7, 0xfa, 0x9e, 0xbc, 0xaa, 0x4d, 0xdf, 0xfe, 0xf0, 0x9b, 0xc8, 0xf6, 0x44, 0x12,
+	0xef, 0x42, 0xe5, 0x96, 0xa5, 0xe2, 0x26, 0xef, 0x4b, 0x78, 0xe3, 0x96, 0xa5, 0x83, 0x19, 0x3e,
+	0x81, 0x66, 0x14, 0xb3, 0xf7, 0x6e, 0xb8, 0x48, 0xc4, 0x16, 0x26, 0xba, 0xde, 0x2e, 0xcb, 0x3d,
+	0xbc, 0x73, 0x31, 0xc5, 0x1e, 0x36, 0x0a, 0xde, 0x05, 0x4b, 0x93, 0xa1, 0xaa, 0x61, 0xb4, 0x33,
+	0x54, 0xb5, 0x2a, 0xd2, 0x86, 0xaa, 0x06, 0xa8, 0x3e, 0x54, 0xb5, 0x3a, 0x6a, 0x1c, 0x12, 0xa8,
+	0xe6, 0x82, 0xb5, 0x8a, 0xca, 0x7a, 0xc5, 0xbb, 0x47, 0xa6, 0xf4, 0xf8, 0x91, 0x39, 0xfc, 0x47,
+	0x81, 0xa7, 0xd9, 0x9d, 0x32, 0x03, 0x1e, 0xa7, 0xcb, 0xf5, 0xc3, 0xdf, 0xc1, 0xd6, 0xf2, 0xe9,
+	0xa2, 0x81, 0x1d, 0x84, 0x49, 0x5e, 0x6a, 0x73, 0x99, 0x1e, 0x89, 0xac, 0x68, 0xc5, 0x0b, 0x1d,
+	0xd1, 0x4a, 0x29, 0x6b, 0xc5, 0x0b, 0x9d, 0xc1, 0x0c, 0xff, 0x0c, 0xb5, 0xe5, 0x85, 0x94, 0x2f,
+	0x52, 0xbd, 0xbb, 0xf7, 0xff, 0x97, 0x99, 0xac, 0x88, 0x87, 0x1f, 0x15, 0x68, 0x66, 0xd9, 0xcb,
+	0xd0, 0x11, 0x87, 0x12, 0x1f, 0x80, 0x26, 0x66, 0x3a, 0x77, 0x03, 0xae, 0x57, 0xdb, 0xca, 0x51,
+	0x83, 0x54, 0x6f, 0x59, 0xda, 0x77, 0x03, 0x09, 0x89, 0xca, 0xe2, 0xb8, 0xcb, 0x9b, 0xdd, 0x20,
+	0x55, 0x2f, 0x57, 0xfd, 0x00, 0xb8, 0x80, 0xe8, 0xaa, 0x8d, 0x9a, 0x24, 0xa1, 0x9c, 0xb4, 0x7c,
+	0x43, 0x86, 0xaa, 0xa6, 0xa0, 0xd2, 0x50, 0xd5, 0x4a, 0xa8, 0x3c, 0x54, 0xb5, 0x32, 0x52, 0x87,
+	0xaa, 0xa6, 0xa2, 0x8d, 0xa1, 0xaa, 0x6d, 0xa0, 0xca, 0x50, 0xd5, 0x2a, 0xa8, 0x7a, 0x18, 0x17,
+	0x8d, 0x5d, 0xd9, 0x51, 0xd1, 0x98, 0x6f, 0x47, 0x59, 0xf5, 0xcc, 0xb8, 0xea, 0xe7, 0xd0, 0x97,
+	0xeb, 0x73, 0x57, 0x25, 0x56, 0x4b, 0x3e, 0x5b, 0x6d, 0x59, 0x67, 0xb9, 0xed, 0x1a, 0xaa, 0x3d,
+	0xef, 0x41, 0x33, 0x5f, 0x86, 0xb3, 0x30, 0xf6, 0x6d, 0x8e, 0xbf, 0x80, 0xfd, 0xcb, 0xf1, 0x39,
+	0x25, 0xe3, 0xb1, 0x45, 0xcf, 0xc6, 0xe4, 0xca, 0xb0, 0xe8, 0xef, 0xa3, 0x8b, 0xd1, 0xf8, 0x8f,
+	0x11, 0x7a, 0x82, 0xf7, 0x00, 0xdf, 0x07, 0xdf, 0xbc, 0x40, 0x8a, 0x70, 0xc9, 0x7b, 0x5e, 0xb9,
+	0x5c, 0x19, 0x93, 0x4f, 0xbb, 0xdc, 0x07, 0xa5, 0xcb, 0x47, 0x05, 0x1a, 0xeb, 0x9f, 0x04, 0x7c,
+	0x00, 0xbb, 0xb9, 0x8a, 0xf6, 0x8d, 0x69, 0x9f, 0x4e, 0x2d, 0x62, 0x58, 0xe6, 0xf9, 0x5b, 0xf4,
+	0x04, 0x63, 0xd8, 0x24, 0x67, 0xa7, 0x27, 0xbf, 0x9c, 0x74, 0xe9, 0xb4, 0x6f, 0x74, 0x5f, 0x9e,
+	0x20, 0x05, 0xef, 0xc0, 0x96, 0x65, 0x4e, 0x2d, 0x2a, 0xcc, 0x05, 0xdf, 0x24, 0xa8, 0x24, 0x3c,
+	0xc6, 0xaf, 0x87, 0xe6, 0xa9, 0x45, 0xef, 0xf1, 0xcb, 0x78, 0x17, 0xb6, 0x4f, 0xc7, 0xa3, 0xc1,
+	0xc5, 0x54, 0xa4, 0x5e, 0xbe, 0xe8, 0x52, 0x91, 0x56, 0xf1, 0x36, 0x34, 0x57, 0x69, 0x91, 0xda,
+	0x78, 0xfe, 0xb7, 0x02, 0xb5, 0xe5, 0x47, 0x51, 0xf4, 0x5f, 0xb4, 0x65, 0x11, 0xd3, 0xa4, 0x53,
+	0xcb, 0xb0, 0x4c, 0xf4, 0x04, 0x03, 0x54, 0x8c, 0x53, 0x6b, 0xf0, 0xc6, 0x44, 0x8a, 0x18, 0x9f,
+	0x91, 0xf1, 0x3b, 0x73, 0x84, 0x4a, 0xf8, 0x19, 0xec, 0xf7, 0xcc, 0x09, 0x31, 0x4f, 0x0d, 0xcb,
+	0xec, 0xd1, 0xe9, 0xf8, 0xcc, 0xa2, 0x3d, 0xf3, 0xd2, 0xb4, 0xcc, 0x1e, 0x2a, 0xb7, 0x4a, 0x9a,
+	0x72, 0x8f, 0xd0, 0x37, 0x48, 0x6f, 0x49, 0x50, 0x25, 0xa1, 0x01, 0x5a, 0x8f, 0x18, 0x83, 0xd1,
+	0x60, 0x74, 0x8e, 0x36, 0x9e, 0x9f, 0x83, 0x56, 0x7c, 0x6e, 0xc5, 0x1c, 0xee, 0xf4, 0x62, 0xbd,
+	0x9d, 0x88, 0x56, 0xaa, 0x50, 0xbe, 0x1c, 0x9f, 0x23, 0x45, 0x0c, 0xae, 0x8c, 0x09, 0x2a, 0x89,
+	0x05, 0x9b, 0x10, 0x73, 0x4c, 0x7a, 0x26, 0x31, 0x7b, 0x54, 0x80, 0xe5, 0xd7, 0x7d, 0x38, 0xb8,
+	0x09, 0xfd, 0xe2, 0x99, 0xba, 0xfb, 0x0b, 0xe7, 0x75, 0xd3, 0xca, 0xe3, 0x89, 0x08, 0x27, 0xca,
+	0xbb, 0x96, 0xe3, 0xf2, 0xf9, 0xe2, 0xba, 0x73, 0x13, 0xfa, 0xc7, 0xf9, 0x4f, 0x90, 0x42, 0x72,
+	0x5d, 0x91, 0x9a, 0x9f, 0xfe, 0x1b, 0x00, 0x1f, 0x39, 0x83, 0x22, 0x27, 0x09, 0x00, 0x00,
 }
@@ -7,6 +7,7 @@ import (
 	context "context"
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
+	any "github.com/golang/protobuf/ptypes/any"
 	keyspb "github.com/google/trillian/crypto/keyspb"
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	field_mask "google.golang.org/genproto/protobuf/field_mask"
@@ -337,6 +338,66 @@ func (m *UndeleteTreeRequest) GetTreeId() int64 {
 	return 0
 }
 
+// AddTreeKey request.
+type AddTreeKeyRequest struct {
+	// ID of the tree to add a key to.
+	TreeId int64 `protobuf:"varint,1,opt,name=tree_id,json=treeId,proto3" json:"tree_id,omitempty"`
+	// The new private key of the tree. Must use the tree's signature_algorithm.
+	PrivateKey *any.Any `protobuf:"bytes,2,opt,name=private_key,json=privateKey,proto3" json:"private_key,omitempty"`
+	// Describes how the new private key should be generated.
+	// Only needs to be set if private_key is not set.
+	KeySpec              *keyspb.Specification `protobuf:"bytes,3,opt,name=key_spec,json=keySpec,proto3" json:"key_spec,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *AddTreeKeyRequest) Reset()         { *m = AddTreeKeyRequest{} }
+func (m *AddTreeKeyRequest) String() string { return proto.CompactTextString(m) }
+func (*AddTreeKeyRequest) ProtoMessage()    {}
+func (*AddTreeKeyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_aac35e28a5dd9ee3, []int{7}
+}
+
+func (m *AddTreeKeyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddTreeKeyRequest.Unmarshal(m, b)
+}
+func (m *AddTreeKeyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddTreeKeyRequest.Marshal(b, m, deterministic)
+}
+func (m *AddTreeKeyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddTreeKeyRequest.Merge(m, src)
+}
+func (m *AddTreeKeyRequest) XXX_Size() int {
+	return xxx_messageInfo_AddTreeKeyRequest.Size(m)
+}
+func (m *AddTreeKeyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddTreeKeyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddTreeKeyRequest proto.InternalMessageInfo
+
+func (m *AddTreeKeyRequest) GetTreeId() int64 {
+	if m != nil {
+		return m.TreeId
+	}
+	return 0
+}
+
+func (m *AddTreeKeyRequest) GetPrivateKey() *any.Any {
+	if m != nil {
+		return m.PrivateKey
+	}
+	return nil
+}
+
+func (m *AddTreeKeyRequest) GetKeySpec() *keyspb.Specification {
+	if m != nil {
+		return m.KeySpec
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*ListTreesRequest)(nil), "trillian.ListTreesRequest")
 	proto.RegisterType((*ListTreesResponse)(nil), "trillian.ListTreesResponse")
@@ -345,47 +406,51 @@ func init() {
 	proto.RegisterType((*UpdateTreeRequest)(nil), "trillian.UpdateTreeRequest")
 	proto.RegisterType((*DeleteTreeRequest)(nil), "trillian.DeleteTreeRequest")
 	proto.RegisterType((*UndeleteTreeRequest)(nil), "trillian.UndeleteTreeRequest")
+	proto.RegisterType((*AddTreeKeyRequest)(nil), "trillian.AddTreeKeyRequest")
 }
 
 func init() { proto.RegisterFile("trillian_admin_api.proto", fileDescriptor_aac35e28a5dd9ee3) }
 
 var fileDescriptor_aac35e28a5dd9ee3 = []byte{
-	// 548 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x54, 0xe1, 0x6a, 0x13, 0x4d,
-	0x14, 0xfd, 0xb6, 0x2d, 0x6d, 0xbe, 0x9b, 0x1a, 0xcc, 0x94, 0x62, 0xba, 0x56, 0x8c, 0xa3, 0x42,
-	0x8d, 0xb2, 0x6b, 0x23, 0x22, 0x54, 0xfc, 0xd1, 0x2a, 0x15, 0x41, 0x21, 0xac, 0x29, 0x82, 0x20,
-	0xcb, 0x66, 0xf7, 0x26, 0x1d, 0x93, 0xec, 0xac, 0x3b, 0x13, 0x25, 0x88, 0x7f, 0x7c, 0x05, 0x5f,
-	0xc6, 0xf7, 0xf0, 0x15, 0x7c, 0x10, 0x99, 0xd9, 0xd9, 0xee, 0xa6, 0xdb, 0x48, 0xf1, 0xd7, 0xee,
-	0xdc, 0x73, 0xef, 0x3d, 0x77, 0xce, 0xb9, 0x0c, 0xb4, 0x64, 0xca, 0x26, 0x13, 0x16, 0xc4, 0x7e,
-	0x10, 0x4d, 0x59, 0xec, 0x07, 0x09, 0x73, 0x92, 0x94, 0x4b, 0x4e, 0x6a, 0x39, 0x62, 0x37, 0xf2,
-	0xbf, 0x0c, 0xb1, 0xed, 0x30, 0x9d, 0x27, 0x92, 0xbb, 0x63, 0x9c, 0x8b, 0x64, 0x60, 0x3e, 0x06,
-	0xdb, 0x1d, 0x71, 0x3e, 0x9a, 0xa0, 0x1b, 0x24, 0xcc, 0x0d, 0xe2, 0x98, 0xcb, 0x40, 0x32, 0x1e,
-	0x0b, 0x83, 0xb6, 0x0d, 0xaa, 0x4f, 0x83, 0xd9, 0xd0, 0x1d, 0x32, 0x9c, 0x44, 0xfe, 0x34, 0x10,
-	0xe3, 0x2c, 0x83, 0x3e, 0x86, 0xab, 0xaf, 0x99, 0x90, 0xfd, 0x14, 0x51, 0x78, 0xf8, 0x69, 0x86,
-	0x42, 0x92, 0x5b, 0xb0, 0x29, 0x4e, 0xf9, 0x17, 0x3f, 0xc2, 0x09, 0x4a, 0x8c, 0x5a, 0x56, 0xdb,
-	0xda, 0xab, 0x79, 0x75, 0x15, 0x7b, 0x91, 0x85, 0xe8, 0x13, 0x68, 0x96, 0xca, 0x44, 0xc2, 0x63,
-	0x81, 0x84, 0xc2, 0x9a, 0x4c, 0x11, 0x5b, 0x56, 0x7b, 0x75, 0xaf, 0xde, 0x6d, 0x38, 0x67, 0xd7,
-	0x50, 0x69, 0x9e, 0xc6, 0xe8, 0x3d, 0x68, 0xbc, 0x44, 0x5d, 0x97, 0xb3, 0x5d, 0x83, 0x0d, 0x85,
-	0xf8, 0x2c, 0x23, 0x5a, 0xf5, 0xd6, 0xd5, 0xf1, 0x55, 0x44, 0x19, 0x34, 0x9f, 0xa7, 0x18, 0x48,
-	0x2c, 0x67, 0x17, 0x1c, 0xd6, 0x32, 0x0e, 0xf2, 0x10, 0x6a, 0x63, 0x9c, 0xfb, 0x22, 0xc1, 0xb0,
-	0xb5, 0xa2, 0xf3, 0xb6, 0x1d, 0x23, 0xda, 0xdb, 0x04, 0x43, 0x36, 0x64, 0xa1, 0x56, 0xc9, 0xdb,
-	0x18, 0xe3, 0x5c, 0x45, 0xa8, 0x84, 0xe6, 0x49, 0x12, 0xfd, 0x03, 0xd5, 0x53, 0xa8, 0xcf, 0x74,
-	0xa1, 0xd6, 0xd4, 0xb0, 0xd9, 0x4e, 0x26, 0xbb, 0x93, 0xcb, 0xee, 0x1c, 0x2b, 0xd9, 0xdf, 0x04,
-	0x62, 0xec, 0x41, 0x96, 0xae, 0xfe, 0xe9, 0x03, 0x68, 0x66, 0x7a, 0x5e, 0x4a, 0x0e, 0x07, 0xb6,
-	0x4e, 0xe2, 0xe8, 0xd2, 0xf9, 0xdd, 0x9f, 0x6b, 0x70, 0xa5, 0x6f, 0x46, 0x3e, 0x54, 0xbb, 0x46,
-	0x8e, 0xe1, 0xff, 0x33, 0xd3, 0x88, 0x5d, 0xdc, 0xe7, 0xfc, 0x02, 0xd8, 0xd7, 0x2f, 0xc4, 0x32,
-	0x97, 0xe9, 0x7f, 0xe4, 0x1d, 0x6c, 0x18, 0x0f, 0x49, 0xab, 0xc8, 0x5c, 0xb4, 0xd5, 0x3e, 0xa7,
-	0x17, 0xa5, 0xdf, 0x7f, 0xfd, 0xfe, 0xb1, 0xb2, 0x4b, 0x6c, 0xf7, 0xf3, 0xfe, 0x00, 0x65, 0xb0,
-	0xef, 0xaa, 0x39, 0x85, 0xfb, 0xd5, 0x4c, 0xff, 0xac, 0xf3, 0x8d, 0xf4, 0x01, 0x0a, 0xc7, 0x49,
-	0x69, 0x8a, 0xca, 0x1e, 0x54, 0xda, 0xef, 0xe8, 0xf6, 0x5b, 0xb4, 0xb1, 0xd8, 0xfe, 0xc0, 0xea,
-	0x10, 0x04, 0x28, 0xcc, 0x2d, 0x77, 0xad, 0x58, 0x5e, 0xe9, 0xda, 0xd1, 0x5d, 0xef, 0x74, 0x6f,
-	0x5e, 0x34, 0xb4, 0x53, 0x4c, 0xae, 0x68, 0x3e, 0x00, 0x14, 0x6e, 0x96, 0x69, 0x2a, 0x1e, 0x2f,
-	0xd3, 0xa6, 0xf3, 0x37, 0x6d, 0x3e, 0xc2, 0x66, 0xd9, 0x7e, 0x72, 0xa3, 0x74, 0x8f, 0xea, 0x5a,
-	0x54, 0x28, 0xee, 0x6b, 0x8a, 0xbb, 0x9d, 0xdb, 0xcb, 0x29, 0x0e, 0x66, 0xa6, 0xcf, 0x51, 0x0f,
-	0x76, 0x42, 0x3e, 0xcd, 0xb7, 0x78, 0xf1, 0x35, 0x3a, 0xda, 0x5e, 0x58, 0xaa, 0xc3, 0x84, 0xf5,
-	0x54, 0xb8, 0x67, 0xbd, 0xb7, 0x47, 0x4c, 0x9e, 0xce, 0x06, 0x4e, 0xc8, 0xa7, 0xae, 0x79, 0x77,
-	0xf2, 0xd2, 0xc1, 0xba, 0xae, 0x7d, 0xf4, 0x27, 0x00, 0x00, 0xff, 0xff, 0x58, 0xc1, 0xa5, 0x85,
-	0xff, 0x04, 0x00, 0x00,
+	// 604 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x54, 0x61, 0x6b, 0xd3, 0x50,
+	0x14, 0x5d, 0xd6, 0xb1, 0xd6, 0xdb, 0x59, 0xec, 0x9b, 0xc3, 0x36, 0x4e, 0xac, 0x51, 0x61, 0x56,
+	0x49, 0x5c, 0x65, 0x08, 0x1b, 0x7e, 0xe8, 0x94, 0x89, 0x4c, 0xa1, 0xc4, 0x0e, 0x41, 0x90, 0x90,
+	0x26, 0xb7, 0xdd, 0x33, 0x6d, 0x12, 0x93, 0xd7, 0x49, 0x10, 0xbf, 0xf8, 0x17, 0xc4, 0x5f, 0xe6,
+	0x5f, 0xf0, 0x2f, 0xf8, 0x5d, 0x5e, 0xf2, 0xb2, 0xa4, 0xcd, 0xea, 0xca, 0x3e, 0x35, 0xb9, 0xe7,
+	0xdc, 0x7b, 0xde, 0x3d, 0x3d, 0x79, 0xd0, 0x60, 0x01, 0x1d, 0x8f, 0xa9, 0xe9, 0x1a, 0xa6, 0x3d,
+	0xa1, 0xae, 0x61, 0xfa, 0x54, 0xf5, 0x03, 0x8f, 0x79, 0xa4, 0x92, 0x22, 0x72, 0x2d, 0x7d, 0x4a,
+	0x10, 0x59, 0xb6, 0x82, 0xc8, 0x67, 0x9e, 0xe6, 0x60, 0x14, 0xfa, 0x03, 0xf1, 0x23, 0xb0, 0xed,
+	0x91, 0xe7, 0x8d, 0xc6, 0xa8, 0x99, 0x3e, 0xd5, 0x4c, 0xd7, 0xf5, 0x98, 0xc9, 0xa8, 0xe7, 0x86,
+	0x02, 0x6d, 0x0a, 0x34, 0x7e, 0x1b, 0x4c, 0x87, 0x9a, 0xe9, 0x46, 0x02, 0x6a, 0xcd, 0x43, 0x43,
+	0x8a, 0x63, 0xdb, 0x98, 0x98, 0xa1, 0x93, 0x30, 0x94, 0x3d, 0xb8, 0xf1, 0x96, 0x86, 0xac, 0x1f,
+	0x20, 0x86, 0x3a, 0x7e, 0x99, 0x62, 0xc8, 0xc8, 0x3d, 0xd8, 0x08, 0x4f, 0xbd, 0xaf, 0x86, 0x8d,
+	0x63, 0x64, 0x68, 0x37, 0xa4, 0x96, 0xb4, 0x53, 0xd1, 0xab, 0xbc, 0xf6, 0x2a, 0x29, 0x29, 0xcf,
+	0xa1, 0x9e, 0x6b, 0x0b, 0x7d, 0xcf, 0x0d, 0x91, 0x28, 0xb0, 0xc6, 0x02, 0xc4, 0x86, 0xd4, 0x2a,
+	0xed, 0x54, 0x3b, 0x35, 0xf5, 0x7c, 0x43, 0x4e, 0xd3, 0x63, 0x4c, 0x79, 0x04, 0xb5, 0xd7, 0x18,
+	0xf7, 0xa5, 0x6a, 0xb7, 0xa0, 0xcc, 0x11, 0x83, 0x26, 0x42, 0x25, 0x7d, 0x9d, 0xbf, 0xbe, 0xb1,
+	0x15, 0x0a, 0xf5, 0x97, 0x01, 0x9a, 0x0c, 0xf3, 0xec, 0x4c, 0x43, 0x5a, 0xa4, 0x41, 0x9e, 0x42,
+	0xc5, 0xc1, 0xc8, 0x08, 0x7d, 0xb4, 0x1a, 0xab, 0x31, 0x6f, 0x4b, 0x15, 0x7e, 0xbe, 0xf7, 0xd1,
+	0xa2, 0x43, 0x6a, 0xc5, 0x06, 0xea, 0x65, 0x07, 0x23, 0x5e, 0x51, 0x18, 0xd4, 0x4f, 0x7c, 0xfb,
+	0x0a, 0x52, 0x07, 0x50, 0x9d, 0xc6, 0x8d, 0xb1, 0xa7, 0x42, 0x4d, 0x56, 0x13, 0xdb, 0xd5, 0xd4,
+	0x76, 0xf5, 0x88, 0xdb, 0xfe, 0xce, 0x0c, 0x1d, 0x1d, 0x12, 0x3a, 0x7f, 0x56, 0x9e, 0x40, 0x3d,
+	0xf1, 0x73, 0x29, 0x3b, 0x54, 0xd8, 0x3c, 0x71, 0xed, 0xe5, 0xf9, 0xbf, 0x24, 0xa8, 0x77, 0x6d,
+	0x9b, 0x73, 0x8f, 0x31, 0xba, 0x8c, 0x4e, 0xf6, 0xa0, 0xea, 0x07, 0xf4, 0x8c, 0xaf, 0xe2, 0x60,
+	0x24, 0x36, 0xb9, 0x59, 0xd8, 0xa4, 0xeb, 0x46, 0x3a, 0x08, 0xe2, 0x31, 0x46, 0x33, 0x5e, 0x97,
+	0x96, 0xf1, 0xba, 0xf3, 0x77, 0x0d, 0xae, 0xf7, 0x85, 0x95, 0x5d, 0xfe, 0x79, 0x90, 0x23, 0xb8,
+	0x76, 0x1e, 0x26, 0x22, 0x67, 0x3e, 0xcf, 0x07, 0x53, 0xbe, 0x7d, 0x21, 0x96, 0xa4, 0x4f, 0x59,
+	0x21, 0x1f, 0xa0, 0x2c, 0xb2, 0x45, 0x1a, 0x19, 0x73, 0x36, 0x6e, 0xf2, 0xdc, 0xff, 0xa8, 0x28,
+	0x3f, 0x7e, 0xff, 0xf9, 0xb9, 0xba, 0x4d, 0x64, 0xed, 0x6c, 0x77, 0x80, 0xcc, 0xdc, 0xd5, 0x18,
+	0x1f, 0xab, 0x7d, 0x13, 0x36, 0xbd, 0x68, 0x7f, 0x27, 0x7d, 0x80, 0x2c, 0x89, 0x24, 0x77, 0x8a,
+	0x42, 0x3e, 0x0b, 0xe3, 0x9b, 0xf1, 0xf8, 0x4d, 0xa5, 0x36, 0x3b, 0x7e, 0x5f, 0x6a, 0x13, 0x04,
+	0xc8, 0x42, 0x97, 0x9f, 0x5a, 0x88, 0x62, 0x61, 0x6a, 0x3b, 0x9e, 0xfa, 0xa0, 0x73, 0xf7, 0xa2,
+	0x43, 0xab, 0xd9, 0xc9, 0xb9, 0xcc, 0x27, 0x80, 0x2c, 0x65, 0x79, 0x99, 0x42, 0xf6, 0x16, 0x79,
+	0xd3, 0xfe, 0x9f, 0x37, 0x9f, 0x61, 0x23, 0x1f, 0x4b, 0x72, 0x27, 0xb7, 0x87, 0x6b, 0x5f, 0x2a,
+	0xf1, 0x38, 0x96, 0x78, 0xd8, 0xbe, 0xbf, 0x58, 0x62, 0x7f, 0x2a, 0xe6, 0x90, 0x03, 0x80, 0x2c,
+	0xd1, 0xf9, 0x55, 0x0a, 0x39, 0x2f, 0xe8, 0xac, 0x1c, 0xf6, 0xa0, 0x69, 0x79, 0x93, 0x34, 0xd0,
+	0xb3, 0xb7, 0xef, 0xe1, 0xd6, 0x4c, 0x22, 0xbb, 0x3e, 0xed, 0xf1, 0x72, 0x4f, 0xfa, 0x28, 0x8f,
+	0x28, 0x3b, 0x9d, 0x0e, 0x54, 0xcb, 0x9b, 0x68, 0xe2, 0x32, 0x4d, 0x5b, 0x07, 0xeb, 0x71, 0xef,
+	0xb3, 0x7f, 0x03, 0x00, 0x1d, 0x5f, 0xeb, 0x05, 0xef, 0x05, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -420,6 +485,11 @@ type TrillianAdminClient interface {
 	// A soft-deleted tree may be undeleted for a certain period, after which
 	// it'll be permanently deleted.
 	UndeleteTree(ctx context.Context, in *UndeleteTreeRequest, opts ...grpc.CallOption) (*Tree, error)
+	// Adds a new signing key to a tree, and makes it the key used to sign all
+	// subsequent tree heads. The public key that it replaces is kept in
+	// Tree.previous_keys, so that earlier tree heads remain verifiable.
+	// Returns the updated tree.
+	AddTreeKey(ctx context.Context, in *AddTreeKeyRequest, opts ...grpc.CallOption) (*Tree, error)
 }
 
 type trillianAdminClient struct {
@@ -484,6 +554,15 @@ func (c *trillianAdminClient) UndeleteTree(ctx context.Context, in *UndeleteTree
 	return out, nil
 }
 
+func (c *trillianAdminClient) AddTreeKey(ctx context.Context, in *AddTreeKeyRequest, opts ...grpc.CallOption) (*Tree, error) {
+	out := new(Tree)
+	err := c.cc.Invoke(ctx, "/trillian.TrillianAdmin/AddTreeKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TrillianAdminServer is the server API for TrillianAdmin service.
 type TrillianAdminServer interface {
 	// Lists all trees the requester has access to.
@@ -506,6 +585,11 @@ type TrillianAdminServer interface {
 	// A soft-deleted tree may be undeleted for a certain period, after which
 	// it'll be permanently deleted.
 	UndeleteTree(context.Context, *UndeleteTreeRequest) (*Tree, error)
+	// Adds a new signing key to a tree, and makes it the key used to sign all
+	// subsequent tree heads. The public key that it replaces is kept in
+	// Tree.previous_keys, so that earlier tree heads remain verifiable.
+	// Returns the updated tree.
+	AddTreeKey(context.Context, *AddTreeKeyRequest) (*Tree, error)
 }
 
 // UnimplementedTrillianAdminServer can be embedded to have forward compatible implementations.
@@ -530,6 +614,9 @@ func (*UnimplementedTrillianAdminServer) DeleteTree(ctx context.Context, req *De
 func (*UnimplementedTrillianAdminServer) UndeleteTree(ctx context.Context, req *UndeleteTreeRequest) (*Tree, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UndeleteTree not implemented")
 }
+func (*UnimplementedTrillianAdminServer) AddTreeKey(ctx context.Context, req *AddTreeKeyRequest) (*Tree, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddTreeKey not implemented")
+}
 
 func RegisterTrillianAdminServer(s *grpc.Server, srv TrillianAdminServer) {
 	s.RegisterService(&_TrillianAdmin_serviceDesc, srv)
@@ -643,6 +730,24 @@ func _TrillianAdmin_UndeleteTree_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TrillianAdmin_AddTreeKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddTreeKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrillianAdminServer).AddTreeKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trillian.TrillianAdmin/AddTreeKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrillianAdminServer).AddTreeKey(ctx, req.(*AddTreeKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _TrillianAdmin_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "trillian.TrillianAdmin",
 	HandlerType: (*TrillianAdminServer)(nil),
@@ -671,6 +776,10 @@ var _TrillianAdmin_serviceDesc = grpc.ServiceDesc{
 			MethodName: "UndeleteTree",
 			Handler:    _TrillianAdmin_UndeleteTree_Handler,
 		},
+		{
+			MethodName: "AddTreeKey",
+			Handler:    _TrillianAdmin_AddTreeKey_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "trillian_admin_api.proto",
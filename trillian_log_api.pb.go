@@ -390,6 +390,131 @@ func (m *GetInclusionProofResponse) GetSignedLogRoot() *SignedLogRoot {
 	return nil
 }
 
+type GetBatchInclusionProofRequest struct {
+	LogId int64 `protobuf:"varint,1,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
+	// leaf_index lists the leaves to prove. Each must be < tree_size.
+	LeafIndex            []int64   `protobuf:"varint,2,rep,packed,name=leaf_index,json=leafIndex,proto3" json:"leaf_index,omitempty"`
+	TreeSize             int64     `protobuf:"varint,3,opt,name=tree_size,json=treeSize,proto3" json:"tree_size,omitempty"`
+	ChargeTo             *ChargeTo `protobuf:"bytes,4,opt,name=charge_to,json=chargeTo,proto3" json:"charge_to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *GetBatchInclusionProofRequest) Reset()         { *m = GetBatchInclusionProofRequest{} }
+func (m *GetBatchInclusionProofRequest) String() string { return proto.CompactTextString(m) }
+func (*GetBatchInclusionProofRequest) ProtoMessage()    {}
+func (*GetBatchInclusionProofRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5ad20a6a54aa5af3, []int{7}
+}
+
+func (m *GetBatchInclusionProofRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBatchInclusionProofRequest.Unmarshal(m, b)
+}
+func (m *GetBatchInclusionProofRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBatchInclusionProofRequest.Marshal(b, m, deterministic)
+}
+func (m *GetBatchInclusionProofRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBatchInclusionProofRequest.Merge(m, src)
+}
+func (m *GetBatchInclusionProofRequest) XXX_Size() int {
+	return xxx_messageInfo_GetBatchInclusionProofRequest.Size(m)
+}
+func (m *GetBatchInclusionProofRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBatchInclusionProofRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBatchInclusionProofRequest proto.InternalMessageInfo
+
+func (m *GetBatchInclusionProofRequest) GetLogId() int64 {
+	if m != nil {
+		return m.LogId
+	}
+	return 0
+}
+
+func (m *GetBatchInclusionProofRequest) GetLeafIndex() []int64 {
+	if m != nil {
+		return m.LeafIndex
+	}
+	return nil
+}
+
+func (m *GetBatchInclusionProofRequest) GetTreeSize() int64 {
+	if m != nil {
+		return m.TreeSize
+	}
+	return 0
+}
+
+func (m *GetBatchInclusionProofRequest) GetChargeTo() *ChargeTo {
+	if m != nil {
+		return m.ChargeTo
+	}
+	return nil
+}
+
+type GetBatchInclusionProofResponse struct {
+	// leaf_index holds the distinct requested leaf indices in increasing order.
+	// Like hashes, it is empty if the requested tree_size was larger than that
+	// available at the server.
+	LeafIndex []int64 `protobuf:"varint,1,rep,packed,name=leaf_index,json=leafIndex,proto3" json:"leaf_index,omitempty"`
+	// hashes is the batch inclusion proof for the leaves in leaf_index. It holds
+	// the inclusion proof for each leaf in turn, from the bottom to the root,
+	// leaving out the nodes that appeared in the proof of an earlier leaf.
+	Hashes               [][]byte       `protobuf:"bytes,2,rep,name=hashes,proto3" json:"hashes,omitempty"`
+	SignedLogRoot        *SignedLogRoot `protobuf:"bytes,3,opt,name=signed_log_root,json=signedLogRoot,proto3" json:"signed_log_root,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *GetBatchInclusionProofResponse) Reset()         { *m = GetBatchInclusionProofResponse{} }
+func (m *GetBatchInclusionProofResponse) String() string { return proto.CompactTextString(m) }
+func (*GetBatchInclusionProofResponse) ProtoMessage()    {}
+func (*GetBatchInclusionProofResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5ad20a6a54aa5af3, []int{8}
+}
+
+func (m *GetBatchInclusionProofResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBatchInclusionProofResponse.Unmarshal(m, b)
+}
+func (m *GetBatchInclusionProofResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBatchInclusionProofResponse.Marshal(b, m, deterministic)
+}
+func (m *GetBatchInclusionProofResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBatchInclusionProofResponse.Merge(m, src)
+}
+func (m *GetBatchInclusionProofResponse) XXX_Size() int {
+	return xxx_messageInfo_GetBatchInclusionProofResponse.Size(m)
+}
+func (m *GetBatchInclusionProofResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBatchInclusionProofResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBatchInclusionProofResponse proto.InternalMessageInfo
+
+func (m *GetBatchInclusionProofResponse) GetLeafIndex() []int64 {
+	if m != nil {
+		return m.LeafIndex
+	}
+	return nil
+}
+
+func (m *GetBatchInclusionProofResponse) GetHashes() [][]byte {
+	if m != nil {
+		return m.Hashes
+	}
+	return nil
+}
+
+func (m *GetBatchInclusionProofResponse) GetSignedLogRoot() *SignedLogRoot {
+	if m != nil {
+		return m.SignedLogRoot
+	}
+	return nil
+}
+
 type GetInclusionProofByHashRequest struct {
 	LogId int64 `protobuf:"varint,1,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
 	// The leaf hash field provides the Merkle tree hash of the leaf entry
@@ -407,7 +532,7 @@ func (m *GetInclusionProofByHashRequest) Reset()         { *m = GetInclusionProo
 func (m *GetInclusionProofByHashRequest) String() string { return proto.CompactTextString(m) }
 func (*GetInclusionProofByHashRequest) ProtoMessage()    {}
 func (*GetInclusionProofByHashRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{7}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{9}
 }
 
 func (m *GetInclusionProofByHashRequest) XXX_Unmarshal(b []byte) error {
@@ -479,7 +604,7 @@ func (m *GetInclusionProofByHashResponse) Reset()         { *m = GetInclusionPro
 func (m *GetInclusionProofByHashResponse) String() string { return proto.CompactTextString(m) }
 func (*GetInclusionProofByHashResponse) ProtoMessage()    {}
 func (*GetInclusionProofByHashResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{8}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{10}
 }
 
 func (m *GetInclusionProofByHashResponse) XXX_Unmarshal(b []byte) error {
@@ -528,7 +653,7 @@ func (m *GetConsistencyProofRequest) Reset()         { *m = GetConsistencyProofR
 func (m *GetConsistencyProofRequest) String() string { return proto.CompactTextString(m) }
 func (*GetConsistencyProofRequest) ProtoMessage()    {}
 func (*GetConsistencyProofRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{9}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{11}
 }
 
 func (m *GetConsistencyProofRequest) XXX_Unmarshal(b []byte) error {
@@ -595,7 +720,7 @@ func (m *GetConsistencyProofResponse) Reset()         { *m = GetConsistencyProof
 func (m *GetConsistencyProofResponse) String() string { return proto.CompactTextString(m) }
 func (*GetConsistencyProofResponse) ProtoMessage()    {}
 func (*GetConsistencyProofResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{10}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{12}
 }
 
 func (m *GetConsistencyProofResponse) XXX_Unmarshal(b []byte) error {
@@ -635,7 +760,12 @@ type GetLatestSignedLogRootRequest struct {
 	ChargeTo *ChargeTo `protobuf:"bytes,2,opt,name=charge_to,json=chargeTo,proto3" json:"charge_to,omitempty"`
 	// If first_tree_size is non-zero, the response will include a consistency
 	// proof between first_tree_size and the new tree size (if not smaller).
-	FirstTreeSize        int64    `protobuf:"varint,3,opt,name=first_tree_size,json=firstTreeSize,proto3" json:"first_tree_size,omitempty"`
+	FirstTreeSize int64 `protobuf:"varint,3,opt,name=first_tree_size,json=firstTreeSize,proto3" json:"first_tree_size,omitempty"`
+	// If cosigned is true, the response contains the most recent log root that
+	// has been cosigned by witnesses, which may be older than the latest log
+	// root. A NOT_FOUND error is returned if no root has been cosigned yet, or
+	// if the cosignatures were sent to another replica of the log server.
+	Cosigned             bool     `protobuf:"varint,4,opt,name=cosigned,proto3" json:"cosigned,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -645,7 +775,7 @@ func (m *GetLatestSignedLogRootRequest) Reset()         { *m = GetLatestSignedLo
 func (m *GetLatestSignedLogRootRequest) String() string { return proto.CompactTextString(m) }
 func (*GetLatestSignedLogRootRequest) ProtoMessage()    {}
 func (*GetLatestSignedLogRootRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{11}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{13}
 }
 
 func (m *GetLatestSignedLogRootRequest) XXX_Unmarshal(b []byte) error {
@@ -687,22 +817,32 @@ func (m *GetLatestSignedLogRootRequest) GetFirstTreeSize() int64 {
 	return 0
 }
 
+func (m *GetLatestSignedLogRootRequest) GetCosigned() bool {
+	if m != nil {
+		return m.Cosigned
+	}
+	return false
+}
+
 type GetLatestSignedLogRootResponse struct {
 	SignedLogRoot *SignedLogRoot `protobuf:"bytes,2,opt,name=signed_log_root,json=signedLogRoot,proto3" json:"signed_log_root,omitempty"`
 	// proof is filled in with a consistency proof if first_tree_size in
 	// GetLatestSignedLogRootRequest is non-zero (and within the tree size
 	// available at the server).
-	Proof                *Proof   `protobuf:"bytes,3,opt,name=proof,proto3" json:"proof,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Proof *Proof `protobuf:"bytes,3,opt,name=proof,proto3" json:"proof,omitempty"`
+	// cosignatures holds the witness cosignatures of signed_log_root known to
+	// the server, ordered by witness name. It may be empty.
+	Cosignatures         []*Cosignature `protobuf:"bytes,4,rep,name=cosignatures,proto3" json:"cosignatures,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
 }
 
 func (m *GetLatestSignedLogRootResponse) Reset()         { *m = GetLatestSignedLogRootResponse{} }
 func (m *GetLatestSignedLogRootResponse) String() string { return proto.CompactTextString(m) }
 func (*GetLatestSignedLogRootResponse) ProtoMessage()    {}
 func (*GetLatestSignedLogRootResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{12}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{14}
 }
 
 func (m *GetLatestSignedLogRootResponse) XXX_Unmarshal(b []byte) error {
@@ -737,6 +877,13 @@ func (m *GetLatestSignedLogRootResponse) GetProof() *Proof {
 	return nil
 }
 
+func (m *GetLatestSignedLogRootResponse) GetCosignatures() []*Cosignature {
+	if m != nil {
+		return m.Cosignatures
+	}
+	return nil
+}
+
 // DO NOT USE - FOR DEBUGGING/TEST ONLY
 //
 // (Use GetLatestSignedLogRoot then de-serialize the Log Root and use
@@ -753,7 +900,7 @@ func (m *GetSequencedLeafCountRequest) Reset()         { *m = GetSequencedLeafCo
 func (m *GetSequencedLeafCountRequest) String() string { return proto.CompactTextString(m) }
 func (*GetSequencedLeafCountRequest) ProtoMessage()    {}
 func (*GetSequencedLeafCountRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{13}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{15}
 }
 
 func (m *GetSequencedLeafCountRequest) XXX_Unmarshal(b []byte) error {
@@ -799,7 +946,7 @@ func (m *GetSequencedLeafCountResponse) Reset()         { *m = GetSequencedLeafC
 func (m *GetSequencedLeafCountResponse) String() string { return proto.CompactTextString(m) }
 func (*GetSequencedLeafCountResponse) ProtoMessage()    {}
 func (*GetSequencedLeafCountResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{14}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{16}
 }
 
 func (m *GetSequencedLeafCountResponse) XXX_Unmarshal(b []byte) error {
@@ -841,7 +988,7 @@ func (m *GetEntryAndProofRequest) Reset()         { *m = GetEntryAndProofRequest
 func (m *GetEntryAndProofRequest) String() string { return proto.CompactTextString(m) }
 func (*GetEntryAndProofRequest) ProtoMessage()    {}
 func (*GetEntryAndProofRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{15}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{17}
 }
 
 func (m *GetEntryAndProofRequest) XXX_Unmarshal(b []byte) error {
@@ -903,7 +1050,7 @@ func (m *GetEntryAndProofResponse) Reset()         { *m = GetEntryAndProofRespon
 func (m *GetEntryAndProofResponse) String() string { return proto.CompactTextString(m) }
 func (*GetEntryAndProofResponse) ProtoMessage()    {}
 func (*GetEntryAndProofResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{16}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{18}
 }
 
 func (m *GetEntryAndProofResponse) XXX_Unmarshal(b []byte) error {
@@ -957,7 +1104,7 @@ func (m *InitLogRequest) Reset()         { *m = InitLogRequest{} }
 func (m *InitLogRequest) String() string { return proto.CompactTextString(m) }
 func (*InitLogRequest) ProtoMessage()    {}
 func (*InitLogRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{17}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{19}
 }
 
 func (m *InitLogRequest) XXX_Unmarshal(b []byte) error {
@@ -1003,7 +1150,7 @@ func (m *InitLogResponse) Reset()         { *m = InitLogResponse{} }
 func (m *InitLogResponse) String() string { return proto.CompactTextString(m) }
 func (*InitLogResponse) ProtoMessage()    {}
 func (*InitLogResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{18}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{20}
 }
 
 func (m *InitLogResponse) XXX_Unmarshal(b []byte) error {
@@ -1044,7 +1191,7 @@ func (m *QueueLeavesRequest) Reset()         { *m = QueueLeavesRequest{} }
 func (m *QueueLeavesRequest) String() string { return proto.CompactTextString(m) }
 func (*QueueLeavesRequest) ProtoMessage()    {}
 func (*QueueLeavesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{19}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{21}
 }
 
 func (m *QueueLeavesRequest) XXX_Unmarshal(b []byte) error {
@@ -1098,7 +1245,7 @@ func (m *QueueLeavesResponse) Reset()         { *m = QueueLeavesResponse{} }
 func (m *QueueLeavesResponse) String() string { return proto.CompactTextString(m) }
 func (*QueueLeavesResponse) ProtoMessage()    {}
 func (*QueueLeavesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{20}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{22}
 }
 
 func (m *QueueLeavesResponse) XXX_Unmarshal(b []byte) error {
@@ -1139,7 +1286,7 @@ func (m *AddSequencedLeavesRequest) Reset()         { *m = AddSequencedLeavesReq
 func (m *AddSequencedLeavesRequest) String() string { return proto.CompactTextString(m) }
 func (*AddSequencedLeavesRequest) ProtoMessage()    {}
 func (*AddSequencedLeavesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{21}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{23}
 }
 
 func (m *AddSequencedLeavesRequest) XXX_Unmarshal(b []byte) error {
@@ -1193,7 +1340,7 @@ func (m *AddSequencedLeavesResponse) Reset()         { *m = AddSequencedLeavesRe
 func (m *AddSequencedLeavesResponse) String() string { return proto.CompactTextString(m) }
 func (*AddSequencedLeavesResponse) ProtoMessage()    {}
 func (*AddSequencedLeavesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{22}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{24}
 }
 
 func (m *AddSequencedLeavesResponse) XXX_Unmarshal(b []byte) error {
@@ -1234,7 +1381,7 @@ func (m *GetLeavesByIndexRequest) Reset()         { *m = GetLeavesByIndexRequest
 func (m *GetLeavesByIndexRequest) String() string { return proto.CompactTextString(m) }
 func (*GetLeavesByIndexRequest) ProtoMessage()    {}
 func (*GetLeavesByIndexRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{23}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{25}
 }
 
 func (m *GetLeavesByIndexRequest) XXX_Unmarshal(b []byte) error {
@@ -1289,7 +1436,7 @@ func (m *GetLeavesByIndexResponse) Reset()         { *m = GetLeavesByIndexRespon
 func (m *GetLeavesByIndexResponse) String() string { return proto.CompactTextString(m) }
 func (*GetLeavesByIndexResponse) ProtoMessage()    {}
 func (*GetLeavesByIndexResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{24}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{26}
 }
 
 func (m *GetLeavesByIndexResponse) XXX_Unmarshal(b []byte) error {
@@ -1338,7 +1485,7 @@ func (m *GetLeavesByRangeRequest) Reset()         { *m = GetLeavesByRangeRequest
 func (m *GetLeavesByRangeRequest) String() string { return proto.CompactTextString(m) }
 func (*GetLeavesByRangeRequest) ProtoMessage()    {}
 func (*GetLeavesByRangeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{25}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{27}
 }
 
 func (m *GetLeavesByRangeRequest) XXX_Unmarshal(b []byte) error {
@@ -1403,7 +1550,7 @@ func (m *GetLeavesByRangeResponse) Reset()         { *m = GetLeavesByRangeRespon
 func (m *GetLeavesByRangeResponse) String() string { return proto.CompactTextString(m) }
 func (*GetLeavesByRangeResponse) ProtoMessage()    {}
 func (*GetLeavesByRangeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{26}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{28}
 }
 
 func (m *GetLeavesByRangeResponse) XXX_Unmarshal(b []byte) error {
@@ -1438,6 +1585,138 @@ func (m *GetLeavesByRangeResponse) GetSignedLogRoot() *SignedLogRoot {
 	return nil
 }
 
+type GetRangeInclusionProofRequest struct {
+	LogId int64 `protobuf:"varint,1,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
+	// The range is the count leaves starting at start_index. It must lie within
+	// the first tree_size leaves.
+	StartIndex           int64     `protobuf:"varint,2,opt,name=start_index,json=startIndex,proto3" json:"start_index,omitempty"`
+	Count                int64     `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	TreeSize             int64     `protobuf:"varint,4,opt,name=tree_size,json=treeSize,proto3" json:"tree_size,omitempty"`
+	ChargeTo             *ChargeTo `protobuf:"bytes,5,opt,name=charge_to,json=chargeTo,proto3" json:"charge_to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *GetRangeInclusionProofRequest) Reset()         { *m = GetRangeInclusionProofRequest{} }
+func (m *GetRangeInclusionProofRequest) String() string { return proto.CompactTextString(m) }
+func (*GetRangeInclusionProofRequest) ProtoMessage()    {}
+func (*GetRangeInclusionProofRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5ad20a6a54aa5af3, []int{29}
+}
+
+func (m *GetRangeInclusionProofRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetRangeInclusionProofRequest.Unmarshal(m, b)
+}
+func (m *GetRangeInclusionProofRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetRangeInclusionProofRequest.Marshal(b, m, deterministic)
+}
+func (m *GetRangeInclusionProofRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetRangeInclusionProofRequest.Merge(m, src)
+}
+func (m *GetRangeInclusionProofRequest) XXX_Size() int {
+	return xxx_messageInfo_GetRangeInclusionProofRequest.Size(m)
+}
+func (m *GetRangeInclusionProofRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetRangeInclusionProofRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetRangeInclusionProofRequest proto.InternalMessageInfo
+
+func (m *GetRangeInclusionProofRequest) GetLogId() int64 {
+	if m != nil {
+		return m.LogId
+	}
+	return 0
+}
+
+func (m *GetRangeInclusionProofRequest) GetStartIndex() int64 {
+	if m != nil {
+		return m.StartIndex
+	}
+	return 0
+}
+
+func (m *GetRangeInclusionProofRequest) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *GetRangeInclusionProofRequest) GetTreeSize() int64 {
+	if m != nil {
+		return m.TreeSize
+	}
+	return 0
+}
+
+func (m *GetRangeInclusionProofRequest) GetChargeTo() *ChargeTo {
+	if m != nil {
+		return m.ChargeTo
+	}
+	return nil
+}
+
+type GetRangeInclusionProofResponse struct {
+	// left_hashes are the hashes of the compact range of leaves
+	// [0, start_index), ordered left to right.
+	LeftHashes [][]byte `protobuf:"bytes,1,rep,name=left_hashes,json=leftHashes,proto3" json:"left_hashes,omitempty"`
+	// right_hashes are the hashes of the compact range of leaves
+	// [start_index+count, tree_size), ordered left to right.
+	RightHashes          [][]byte       `protobuf:"bytes,2,rep,name=right_hashes,json=rightHashes,proto3" json:"right_hashes,omitempty"`
+	SignedLogRoot        *SignedLogRoot `protobuf:"bytes,3,opt,name=signed_log_root,json=signedLogRoot,proto3" json:"signed_log_root,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *GetRangeInclusionProofResponse) Reset()         { *m = GetRangeInclusionProofResponse{} }
+func (m *GetRangeInclusionProofResponse) String() string { return proto.CompactTextString(m) }
+func (*GetRangeInclusionProofResponse) ProtoMessage()    {}
+func (*GetRangeInclusionProofResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5ad20a6a54aa5af3, []int{30}
+}
+
+func (m *GetRangeInclusionProofResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetRangeInclusionProofResponse.Unmarshal(m, b)
+}
+func (m *GetRangeInclusionProofResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetRangeInclusionProofResponse.Marshal(b, m, deterministic)
+}
+func (m *GetRangeInclusionProofResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetRangeInclusionProofResponse.Merge(m, src)
+}
+func (m *GetRangeInclusionProofResponse) XXX_Size() int {
+	return xxx_messageInfo_GetRangeInclusionProofResponse.Size(m)
+}
+func (m *GetRangeInclusionProofResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetRangeInclusionProofResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetRangeInclusionProofResponse proto.InternalMessageInfo
+
+func (m *GetRangeInclusionProofResponse) GetLeftHashes() [][]byte {
+	if m != nil {
+		return m.LeftHashes
+	}
+	return nil
+}
+
+func (m *GetRangeInclusionProofResponse) GetRightHashes() [][]byte {
+	if m != nil {
+		return m.RightHashes
+	}
+	return nil
+}
+
+func (m *GetRangeInclusionProofResponse) GetSignedLogRoot() *SignedLogRoot {
+	if m != nil {
+		return m.SignedLogRoot
+	}
+	return nil
+}
+
 type GetLeavesByHashRequest struct {
 	LogId int64 `protobuf:"varint,1,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
 	// The Merkle leaf hash of the leaf to be retrieved.
@@ -1455,7 +1734,7 @@ func (m *GetLeavesByHashRequest) Reset()         { *m = GetLeavesByHashRequest{}
 func (m *GetLeavesByHashRequest) String() string { return proto.CompactTextString(m) }
 func (*GetLeavesByHashRequest) ProtoMessage()    {}
 func (*GetLeavesByHashRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{27}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{31}
 }
 
 func (m *GetLeavesByHashRequest) XXX_Unmarshal(b []byte) error {
@@ -1516,7 +1795,7 @@ func (m *GetLeavesByHashResponse) Reset()         { *m = GetLeavesByHashResponse
 func (m *GetLeavesByHashResponse) String() string { return proto.CompactTextString(m) }
 func (*GetLeavesByHashResponse) ProtoMessage()    {}
 func (*GetLeavesByHashResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{28}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{32}
 }
 
 func (m *GetLeavesByHashResponse) XXX_Unmarshal(b []byte) error {
@@ -1551,6 +1830,261 @@ func (m *GetLeavesByHashResponse) GetSignedLogRoot() *SignedLogRoot {
 	return nil
 }
 
+// Cosignature is a witness's signature of a log root. By cosigning a root, a
+// witness attests that it has verified the root to be consistent with all the
+// roots of the log it has seen before.
+type Cosignature struct {
+	// witness is the name of the witness that produced the signature.
+	Witness string `protobuf:"bytes,1,opt,name=witness,proto3" json:"witness,omitempty"`
+	// signature is the witness's signature over the log ID and the log_root of
+	// the cosigned SignedLogRoot. See crypto.Signer.CosignLogRoot.
+	Signature            []byte   `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Cosignature) Reset()         { *m = Cosignature{} }
+func (m *Cosignature) String() string { return proto.CompactTextString(m) }
+func (*Cosignature) ProtoMessage()    {}
+func (*Cosignature) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5ad20a6a54aa5af3, []int{33}
+}
+
+func (m *Cosignature) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Cosignature.Unmarshal(m, b)
+}
+func (m *Cosignature) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Cosignature.Marshal(b, m, deterministic)
+}
+func (m *Cosignature) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Cosignature.Merge(m, src)
+}
+func (m *Cosignature) XXX_Size() int {
+	return xxx_messageInfo_Cosignature.Size(m)
+}
+func (m *Cosignature) XXX_DiscardUnknown() {
+	xxx_messageInfo_Cosignature.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Cosignature proto.InternalMessageInfo
+
+func (m *Cosignature) GetWitness() string {
+	if m != nil {
+		return m.Witness
+	}
+	return ""
+}
+
+func (m *Cosignature) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type AddLogRootCosignatureRequest struct {
+	LogId int64 `protobuf:"varint,1,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
+	// signed_log_root is the log root being cosigned, as returned by the log.
+	SignedLogRoot        *SignedLogRoot `protobuf:"bytes,2,opt,name=signed_log_root,json=signedLogRoot,proto3" json:"signed_log_root,omitempty"`
+	Cosignature          *Cosignature   `protobuf:"bytes,3,opt,name=cosignature,proto3" json:"cosignature,omitempty"`
+	ChargeTo             *ChargeTo      `protobuf:"bytes,4,opt,name=charge_to,json=chargeTo,proto3" json:"charge_to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *AddLogRootCosignatureRequest) Reset()         { *m = AddLogRootCosignatureRequest{} }
+func (m *AddLogRootCosignatureRequest) String() string { return proto.CompactTextString(m) }
+func (*AddLogRootCosignatureRequest) ProtoMessage()    {}
+func (*AddLogRootCosignatureRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5ad20a6a54aa5af3, []int{34}
+}
+
+func (m *AddLogRootCosignatureRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddLogRootCosignatureRequest.Unmarshal(m, b)
+}
+func (m *AddLogRootCosignatureRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddLogRootCosignatureRequest.Marshal(b, m, deterministic)
+}
+func (m *AddLogRootCosignatureRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddLogRootCosignatureRequest.Merge(m, src)
+}
+func (m *AddLogRootCosignatureRequest) XXX_Size() int {
+	return xxx_messageInfo_AddLogRootCosignatureRequest.Size(m)
+}
+func (m *AddLogRootCosignatureRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddLogRootCosignatureRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddLogRootCosignatureRequest proto.InternalMessageInfo
+
+func (m *AddLogRootCosignatureRequest) GetLogId() int64 {
+	if m != nil {
+		return m.LogId
+	}
+	return 0
+}
+
+func (m *AddLogRootCosignatureRequest) GetSignedLogRoot() *SignedLogRoot {
+	if m != nil {
+		return m.SignedLogRoot
+	}
+	return nil
+}
+
+func (m *AddLogRootCosignatureRequest) GetCosignature() *Cosignature {
+	if m != nil {
+		return m.Cosignature
+	}
+	return nil
+}
+
+func (m *AddLogRootCosignatureRequest) GetChargeTo() *ChargeTo {
+	if m != nil {
+		return m.ChargeTo
+	}
+	return nil
+}
+
+type AddLogRootCosignatureResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AddLogRootCosignatureResponse) Reset()         { *m = AddLogRootCosignatureResponse{} }
+func (m *AddLogRootCosignatureResponse) String() string { return proto.CompactTextString(m) }
+func (*AddLogRootCosignatureResponse) ProtoMessage()    {}
+func (*AddLogRootCosignatureResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5ad20a6a54aa5af3, []int{35}
+}
+
+func (m *AddLogRootCosignatureResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AddLogRootCosignatureResponse.Unmarshal(m, b)
+}
+func (m *AddLogRootCosignatureResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AddLogRootCosignatureResponse.Marshal(b, m, deterministic)
+}
+func (m *AddLogRootCosignatureResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AddLogRootCosignatureResponse.Merge(m, src)
+}
+func (m *AddLogRootCosignatureResponse) XXX_Size() int {
+	return xxx_messageInfo_AddLogRootCosignatureResponse.Size(m)
+}
+func (m *AddLogRootCosignatureResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AddLogRootCosignatureResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AddLogRootCosignatureResponse proto.InternalMessageInfo
+
+type StreamLeavesRequest struct {
+	LogId int64 `protobuf:"varint,1,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
+	// The index of the first leaf to return.
+	StartIndex           int64     `protobuf:"varint,2,opt,name=start_index,json=startIndex,proto3" json:"start_index,omitempty"`
+	ChargeTo             *ChargeTo `protobuf:"bytes,3,opt,name=charge_to,json=chargeTo,proto3" json:"charge_to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *StreamLeavesRequest) Reset()         { *m = StreamLeavesRequest{} }
+func (m *StreamLeavesRequest) String() string { return proto.CompactTextString(m) }
+func (*StreamLeavesRequest) ProtoMessage()    {}
+func (*StreamLeavesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5ad20a6a54aa5af3, []int{36}
+}
+
+func (m *StreamLeavesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StreamLeavesRequest.Unmarshal(m, b)
+}
+func (m *StreamLeavesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StreamLeavesRequest.Marshal(b, m, deterministic)
+}
+func (m *StreamLeavesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamLeavesRequest.Merge(m, src)
+}
+func (m *StreamLeavesRequest) XXX_Size() int {
+	return xxx_messageInfo_StreamLeavesRequest.Size(m)
+}
+func (m *StreamLeavesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamLeavesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamLeavesRequest proto.InternalMessageInfo
+
+func (m *StreamLeavesRequest) GetLogId() int64 {
+	if m != nil {
+		return m.LogId
+	}
+	return 0
+}
+
+func (m *StreamLeavesRequest) GetStartIndex() int64 {
+	if m != nil {
+		return m.StartIndex
+	}
+	return 0
+}
+
+func (m *StreamLeavesRequest) GetChargeTo() *ChargeTo {
+	if m != nil {
+		return m.ChargeTo
+	}
+	return nil
+}
+
+type StreamLeavesResponse struct {
+	// signed_log_root is set when the server has seen a new log root since the
+	// previous response, and covers all the leaves in this and subsequent
+	// responses until the next signed_log_root is sent.
+	SignedLogRoot *SignedLogRoot `protobuf:"bytes,1,opt,name=signed_log_root,json=signedLogRoot,proto3" json:"signed_log_root,omitempty"`
+	// Log leaves following on from those in the previous response, in order.
+	Leaves               []*LogLeaf `protobuf:"bytes,2,rep,name=leaves,proto3" json:"leaves,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *StreamLeavesResponse) Reset()         { *m = StreamLeavesResponse{} }
+func (m *StreamLeavesResponse) String() string { return proto.CompactTextString(m) }
+func (*StreamLeavesResponse) ProtoMessage()    {}
+func (*StreamLeavesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_5ad20a6a54aa5af3, []int{37}
+}
+
+func (m *StreamLeavesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StreamLeavesResponse.Unmarshal(m, b)
+}
+func (m *StreamLeavesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StreamLeavesResponse.Marshal(b, m, deterministic)
+}
+func (m *StreamLeavesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StreamLeavesResponse.Merge(m, src)
+}
+func (m *StreamLeavesResponse) XXX_Size() int {
+	return xxx_messageInfo_StreamLeavesResponse.Size(m)
+}
+func (m *StreamLeavesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StreamLeavesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StreamLeavesResponse proto.InternalMessageInfo
+
+func (m *StreamLeavesResponse) GetSignedLogRoot() *SignedLogRoot {
+	if m != nil {
+		return m.SignedLogRoot
+	}
+	return nil
+}
+
+func (m *StreamLeavesResponse) GetLeaves() []*LogLeaf {
+	if m != nil {
+		return m.Leaves
+	}
+	return nil
+}
+
 // QueuedLogLeaf provides the result of submitting an entry to the log.
 // TODO(pavelkalinnikov): Consider renaming it to AddLogLeafResult or the like.
 type QueuedLogLeaf struct {
@@ -1576,7 +2110,7 @@ func (m *QueuedLogLeaf) Reset()         { *m = QueuedLogLeaf{} }
 func (m *QueuedLogLeaf) String() string { return proto.CompactTextString(m) }
 func (*QueuedLogLeaf) ProtoMessage()    {}
 func (*QueuedLogLeaf) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{29}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{38}
 }
 
 func (m *QueuedLogLeaf) XXX_Unmarshal(b []byte) error {
@@ -1685,7 +2219,7 @@ func (m *LogLeaf) Reset()         { *m = LogLeaf{} }
 func (m *LogLeaf) String() string { return proto.CompactTextString(m) }
 func (*LogLeaf) ProtoMessage()    {}
 func (*LogLeaf) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{30}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{39}
 }
 
 func (m *LogLeaf) XXX_Unmarshal(b []byte) error {
@@ -1772,7 +2306,7 @@ func (m *Proof) Reset()         { *m = Proof{} }
 func (m *Proof) String() string { return proto.CompactTextString(m) }
 func (*Proof) ProtoMessage()    {}
 func (*Proof) Descriptor() ([]byte, []int) {
-	return fileDescriptor_5ad20a6a54aa5af3, []int{31}
+	return fileDescriptor_5ad20a6a54aa5af3, []int{40}
 }
 
 func (m *Proof) XXX_Unmarshal(b []byte) error {
@@ -1815,6 +2349,8 @@ func init() {
 	proto.RegisterType((*AddSequencedLeafResponse)(nil), "trillian.AddSequencedLeafResponse")
 	proto.RegisterType((*GetInclusionProofRequest)(nil), "trillian.GetInclusionProofRequest")
 	proto.RegisterType((*GetInclusionProofResponse)(nil), "trillian.GetInclusionProofResponse")
+	proto.RegisterType((*GetBatchInclusionProofRequest)(nil), "trillian.GetBatchInclusionProofRequest")
+	proto.RegisterType((*GetBatchInclusionProofResponse)(nil), "trillian.GetBatchInclusionProofResponse")
 	proto.RegisterType((*GetInclusionProofByHashRequest)(nil), "trillian.GetInclusionProofByHashRequest")
 	proto.RegisterType((*GetInclusionProofByHashResponse)(nil), "trillian.GetInclusionProofByHashResponse")
 	proto.RegisterType((*GetConsistencyProofRequest)(nil), "trillian.GetConsistencyProofRequest")
@@ -1835,8 +2371,15 @@ func init() {
 	proto.RegisterType((*GetLeavesByIndexResponse)(nil), "trillian.GetLeavesByIndexResponse")
 	proto.RegisterType((*GetLeavesByRangeRequest)(nil), "trillian.GetLeavesByRangeRequest")
 	proto.RegisterType((*GetLeavesByRangeResponse)(nil), "trillian.GetLeavesByRangeResponse")
+	proto.RegisterType((*GetRangeInclusionProofRequest)(nil), "trillian.GetRangeInclusionProofRequest")
+	proto.RegisterType((*GetRangeInclusionProofResponse)(nil), "trillian.GetRangeInclusionProofResponse")
 	proto.RegisterType((*GetLeavesByHashRequest)(nil), "trillian.GetLeavesByHashRequest")
 	proto.RegisterType((*GetLeavesByHashResponse)(nil), "trillian.GetLeavesByHashResponse")
+	proto.RegisterType((*Cosignature)(nil), "trillian.Cosignature")
+	proto.RegisterType((*AddLogRootCosignatureRequest)(nil), "trillian.AddLogRootCosignatureRequest")
+	proto.RegisterType((*AddLogRootCosignatureResponse)(nil), "trillian.AddLogRootCosignatureResponse")
+	proto.RegisterType((*StreamLeavesRequest)(nil), "trillian.StreamLeavesRequest")
+	proto.RegisterType((*StreamLeavesResponse)(nil), "trillian.StreamLeavesResponse")
 	proto.RegisterType((*QueuedLogLeaf)(nil), "trillian.QueuedLogLeaf")
 	proto.RegisterType((*LogLeaf)(nil), "trillian.LogLeaf")
 	proto.RegisterType((*Proof)(nil), "trillian.Proof")
@@ -1845,104 +2388,122 @@ func init() {
 func init() { proto.RegisterFile("trillian_log_api.proto", fileDescriptor_5ad20a6a54aa5af3) }
 
 var fileDescriptor_5ad20a6a54aa5af3 = []byte{
-	// 1540 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x58, 0x5b, 0x6f, 0xdc, 0x44,
-	0x14, 0xae, 0xe3, 0x5c, 0x4f, 0x9a, 0x6c, 0x32, 0xa1, 0xcd, 0xc6, 0x69, 0xda, 0xd4, 0x69, 0xda,
-	0x6d, 0x28, 0x31, 0x29, 0x42, 0xa0, 0xa8, 0x02, 0x35, 0x29, 0x0a, 0xa1, 0x0b, 0x14, 0x27, 0x42,
-	0x15, 0x3c, 0x58, 0x5e, 0xef, 0xc4, 0xb1, 0xd8, 0x78, 0xb6, 0xf6, 0x6c, 0xd4, 0x6d, 0x55, 0x89,
-	0x8b, 0xca, 0xe5, 0x01, 0x78, 0x80, 0x87, 0xbe, 0x70, 0x79, 0x43, 0xfc, 0x01, 0x7e, 0x06, 0x42,
-	0xe2, 0x2f, 0xf0, 0xc0, 0xcf, 0x40, 0x9e, 0x19, 0xaf, 0x2f, 0x6b, 0x7b, 0x77, 0x4b, 0x5b, 0x78,
-	0x5b, 0x9f, 0x39, 0x73, 0xce, 0x77, 0xbe, 0x99, 0x73, 0xe6, 0x9c, 0x85, 0xd3, 0xd4, 0x73, 0x1a,
-	0x0d, 0xc7, 0x74, 0x8d, 0x06, 0xb1, 0x0d, 0xb3, 0xe9, 0xac, 0x37, 0x3d, 0x42, 0x09, 0x1a, 0x0f,
-	0xe5, 0xca, 0x19, 0x9b, 0x10, 0xbb, 0x81, 0x35, 0xb3, 0xe9, 0x68, 0xa6, 0xeb, 0x12, 0x6a, 0x52,
-	0x87, 0xb8, 0x3e, 0xd7, 0x53, 0xce, 0x89, 0x55, 0xf6, 0x55, 0x6b, 0x1d, 0x68, 0xd4, 0x39, 0xc2,
-	0x3e, 0x35, 0x8f, 0x9a, 0x42, 0x61, 0x5e, 0x28, 0x78, 0x4d, 0x4b, 0xf3, 0xa9, 0x49, 0x5b, 0xe1,
-	0xce, 0xe9, 0xd0, 0x03, 0xff, 0x56, 0xcf, 0xc2, 0xf8, 0xf6, 0xa1, 0xe9, 0xd9, 0x78, 0x9f, 0x20,
-	0x04, 0xc3, 0x2d, 0x1f, 0x7b, 0x65, 0x69, 0x59, 0xae, 0x4c, 0xe8, 0xec, 0xb7, 0xfa, 0x89, 0x04,
-	0x33, 0xef, 0xb5, 0x70, 0x0b, 0x57, 0xb1, 0x79, 0xa0, 0xe3, 0x3b, 0x2d, 0xec, 0x53, 0x74, 0x0a,
-	0x46, 0x03, 0xdc, 0x4e, 0xbd, 0x2c, 0x2d, 0x4b, 0x15, 0x59, 0x1f, 0x69, 0x10, 0x7b, 0xb7, 0x8e,
-	0x56, 0x61, 0xb8, 0x81, 0xcd, 0x83, 0xf2, 0xd0, 0xb2, 0x54, 0x99, 0xbc, 0x3a, 0xbb, 0xde, 0x71,
-	0x55, 0x25, 0x36, 0xdb, 0xce, 0x96, 0x91, 0x06, 0x13, 0x16, 0x73, 0x69, 0x50, 0x52, 0x96, 0x99,
-	0x2e, 0x8a, 0x74, 0x43, 0x34, 0xfa, 0xb8, 0x25, 0x7e, 0xa9, 0x6f, 0xc3, 0x6c, 0x0c, 0x82, 0xdf,
-	0x24, 0xae, 0x8f, 0xd1, 0xab, 0x30, 0x79, 0x27, 0x10, 0xd6, 0x8d, 0x98, 0xcf, 0xf9, 0xc8, 0x0e,
-	0xdb, 0x51, 0x0f, 0x3d, 0x03, 0xd7, 0x0d, 0x7e, 0xab, 0x5f, 0x4a, 0x30, 0x7f, 0xbd, 0x5e, 0xdf,
-	0x0b, 0x82, 0x71, 0x2d, 0x2e, 0xfc, 0x8f, 0x22, 0xbb, 0x09, 0xe5, 0x6e, 0x24, 0x22, 0x40, 0x0d,
-	0x46, 0x3d, 0xec, 0xb7, 0x1a, 0xb4, 0x57, 0x6c, 0x42, 0x4d, 0xfd, 0x51, 0x82, 0xf2, 0x0e, 0xa6,
-	0xbb, 0xae, 0xd5, 0x68, 0xf9, 0x0e, 0x71, 0x6f, 0x79, 0x84, 0xf4, 0x0a, 0x6c, 0x09, 0x20, 0x40,
-	0x6e, 0x38, 0x6e, 0x1d, 0xdf, 0x65, 0x8e, 0x64, 0x7d, 0x22, 0x90, 0xec, 0x06, 0x02, 0xb4, 0x08,
-	0x13, 0xd4, 0xc3, 0xd8, 0xf0, 0x9d, 0x7b, 0x98, 0x05, 0x24, 0xeb, 0xe3, 0x81, 0x60, 0xcf, 0xb9,
-	0x87, 0x93, 0xd1, 0x0e, 0xf7, 0x11, 0xed, 0x67, 0x12, 0x2c, 0x64, 0x00, 0x14, 0xf1, 0xae, 0xc2,
-	0x48, 0x33, 0x10, 0x88, 0x70, 0x4b, 0x91, 0x29, 0xae, 0xc7, 0x57, 0xd1, 0xeb, 0x50, 0xf2, 0x1d,
-	0xdb, 0x0d, 0xce, 0x9d, 0xd8, 0x86, 0x47, 0x08, 0x15, 0x4c, 0xc7, 0xf8, 0xd9, 0x63, 0x0a, 0x55,
-	0x62, 0xeb, 0x84, 0x50, 0x7d, 0xca, 0x8f, 0x7f, 0xaa, 0xbf, 0x4b, 0x70, 0xb6, 0x0b, 0xc5, 0x56,
-	0xfb, 0x4d, 0xd3, 0x3f, 0xec, 0x41, 0xd6, 0x22, 0x30, 0x6a, 0x8c, 0x43, 0xd3, 0x3f, 0x64, 0x28,
-	0x4f, 0xea, 0xe3, 0x81, 0x20, 0xd8, 0x5a, 0x4c, 0xd5, 0x1a, 0xcc, 0x12, 0xaf, 0x8e, 0x3d, 0xa3,
-	0xd6, 0x36, 0x7c, 0x71, 0xda, 0x8c, 0xb2, 0x71, 0xbd, 0xc4, 0x16, 0xb6, 0xda, 0xe1, 0x25, 0x48,
-	0xd2, 0x3a, 0xd2, 0x07, 0xad, 0x5f, 0x49, 0x70, 0x2e, 0x37, 0xa0, 0x6e, 0x72, 0xe5, 0xa7, 0x49,
-	0xee, 0x6f, 0x12, 0x28, 0x3b, 0x98, 0x6e, 0x13, 0xd7, 0x77, 0x7c, 0x8a, 0x5d, 0xab, 0xdd, 0xcf,
-	0x2d, 0xbc, 0x08, 0xa5, 0x03, 0xc7, 0xf3, 0xa9, 0x11, 0x31, 0xc8, 0xaf, 0xe2, 0x14, 0x13, 0xef,
-	0x87, 0x34, 0x56, 0x60, 0xc6, 0xc7, 0x16, 0x71, 0xeb, 0x46, 0x9a, 0xea, 0x69, 0x2e, 0xdf, 0x7f,
-	0xec, 0xbb, 0xf9, 0x50, 0x82, 0xc5, 0x4c, 0xe0, 0xcf, 0xf8, 0x76, 0x7e, 0x2b, 0xc1, 0xd2, 0x0e,
-	0xa6, 0x55, 0x93, 0x62, 0x9f, 0x26, 0x35, 0x8b, 0x39, 0x4c, 0x44, 0x3c, 0xd4, 0x3b, 0xe2, 0x2c,
-	0xd2, 0xe5, 0x0c, 0xd2, 0x83, 0x72, 0x79, 0x36, 0x0f, 0x91, 0x20, 0x27, 0x23, 0xea, 0xa1, 0x41,
-	0xa2, 0x8e, 0xd8, 0x95, 0x8b, 0xd8, 0x55, 0x0f, 0xe0, 0xcc, 0x0e, 0xa6, 0x89, 0x72, 0xb9, 0x4d,
-	0x5a, 0xee, 0x93, 0xa6, 0x46, 0x7d, 0x8d, 0x9d, 0x41, 0x96, 0x1f, 0x11, 0x70, 0x58, 0x36, 0xad,
-	0x40, 0x1a, 0x2f, 0x9b, 0x4c, 0x4d, 0xfd, 0x41, 0x82, 0xf9, 0x1d, 0x4c, 0xdf, 0x70, 0xa9, 0xd7,
-	0xbe, 0xee, 0xd6, 0xff, 0x77, 0x85, 0xf8, 0x57, 0xfe, 0x52, 0xa4, 0xf0, 0x0d, 0x76, 0xd3, 0xc3,
-	0x27, 0x51, 0x2e, 0x7e, 0x12, 0x33, 0xae, 0xc6, 0xf0, 0x40, 0x09, 0x71, 0x1b, 0xa6, 0x77, 0x5d,
-	0x87, 0x06, 0x9f, 0x4f, 0xf8, 0x94, 0x6f, 0x40, 0xa9, 0x63, 0x59, 0xc4, 0xbe, 0x01, 0x63, 0x96,
-	0x87, 0x4d, 0x8a, 0xb9, 0xed, 0x02, 0x94, 0xa1, 0x9e, 0xfa, 0x85, 0x04, 0x28, 0xec, 0x4e, 0x8e,
-	0xb1, 0xdf, 0x03, 0xe4, 0x65, 0x18, 0x6d, 0x30, 0x3d, 0x51, 0x88, 0x33, 0x78, 0x13, 0x0a, 0x83,
-	0x37, 0x13, 0x7b, 0x30, 0x97, 0x00, 0x22, 0x62, 0xba, 0x06, 0x53, 0x51, 0xa3, 0x14, 0x79, 0xce,
-	0x6d, 0x27, 0x4e, 0x76, 0x5a, 0xa5, 0x63, 0xec, 0xab, 0xdf, 0x48, 0xb0, 0x90, 0x6a, 0x51, 0x9e,
-	0x5e, 0x94, 0xfd, 0xdc, 0xdd, 0x77, 0x41, 0xc9, 0xc2, 0x13, 0x1d, 0x20, 0xef, 0x86, 0x7a, 0x86,
-	0x19, 0xea, 0xa9, 0x1f, 0xf3, 0x64, 0xe5, 0x86, 0xb6, 0xda, 0x2c, 0xdf, 0x06, 0x4c, 0x56, 0x39,
-	0x99, 0xac, 0x03, 0xbf, 0xe0, 0x9f, 0xf3, 0x7c, 0x4c, 0x41, 0x10, 0x21, 0x0d, 0x40, 0xe6, 0xbf,
-	0x7e, 0x7d, 0x1e, 0x25, 0xb9, 0xd0, 0x4d, 0xd7, 0xc6, 0x3d, 0xb8, 0x38, 0x07, 0x93, 0x3e, 0x35,
-	0x3d, 0x9a, 0xa8, 0x5c, 0xc0, 0x44, 0x9c, 0x8d, 0xe7, 0x60, 0x84, 0x97, 0x49, 0x5e, 0xb6, 0xf8,
-	0xc7, 0xe0, 0xe7, 0x9e, 0xe2, 0x48, 0x40, 0xeb, 0xe2, 0x48, 0x7a, 0x0c, 0x8e, 0x06, 0x7a, 0xab,
-	0x82, 0xe2, 0x79, 0x3a, 0x06, 0x64, 0xf0, 0xbe, 0x51, 0x4e, 0xf4, 0x8d, 0x99, 0xad, 0xa1, 0xfc,
-	0x84, 0x5a, 0xc3, 0x87, 0xc9, 0xf3, 0x4c, 0xb4, 0x84, 0xcf, 0xf2, 0x5e, 0xd5, 0x60, 0x2a, 0x91,
-	0x7d, 0x9d, 0xd7, 0x43, 0x2a, 0x7e, 0x3d, 0xd6, 0x60, 0x94, 0x4f, 0xaf, 0x9d, 0x82, 0xce, 0xe7,
-	0xda, 0x75, 0xaf, 0x69, 0xad, 0xef, 0xb1, 0x15, 0x5d, 0x68, 0xa8, 0x7f, 0x0c, 0xc1, 0x58, 0x68,
-	0xbe, 0x02, 0x33, 0x47, 0xd8, 0xfb, 0xa8, 0x81, 0x8d, 0x88, 0x78, 0x89, 0x35, 0xec, 0xd3, 0x5c,
-	0x5e, 0x0d, 0xe9, 0x0f, 0x53, 0xf9, 0xd8, 0x6c, 0xb4, 0xb0, 0x68, 0xea, 0xd9, 0x69, 0xbd, 0x1f,
-	0x08, 0x82, 0x65, 0x7c, 0x97, 0x7a, 0xa6, 0x51, 0x37, 0xa9, 0xc9, 0x82, 0x3e, 0xa9, 0x4f, 0x30,
-	0xc9, 0x0d, 0x93, 0x9a, 0xa9, 0x42, 0x30, 0x9c, 0x7e, 0xb5, 0xaf, 0x00, 0xe2, 0xcb, 0x75, 0xec,
-	0x52, 0x87, 0xb6, 0x39, 0x90, 0x11, 0x66, 0x65, 0x86, 0xa9, 0x89, 0x05, 0x06, 0x65, 0x1b, 0x4a,
-	0xac, 0xf4, 0x1a, 0x9d, 0x61, 0xbe, 0x3c, 0xca, 0xa2, 0x56, 0xc2, 0xa8, 0xc3, 0x71, 0x7f, 0x7d,
-	0x3f, 0xd4, 0xd0, 0xa7, 0xd9, 0x96, 0xce, 0x37, 0xba, 0x09, 0x73, 0x8e, 0x4b, 0xb1, 0xed, 0x99,
-	0x34, 0x6e, 0x68, 0xac, 0xa7, 0x21, 0xd4, 0xd9, 0xd6, 0x91, 0xa9, 0x37, 0x60, 0x84, 0xbd, 0xf9,
-	0xa9, 0x38, 0xa5, 0x74, 0x9c, 0xa7, 0x61, 0x34, 0x88, 0x0c, 0xfb, 0x65, 0x99, 0xdd, 0x6e, 0xf1,
-	0xf5, 0xd6, 0xf0, 0xf8, 0xd0, 0x8c, 0x7c, 0xf5, 0xef, 0x29, 0x98, 0xdc, 0x17, 0xe7, 0x5b, 0x25,
-	0x36, 0x72, 0x61, 0xa2, 0x33, 0xce, 0x23, 0x25, 0x55, 0x9f, 0x63, 0xc3, 0xb8, 0xb2, 0x98, 0xb9,
-	0xc6, 0xaf, 0xaf, 0x5a, 0xf9, 0xf4, 0xcf, 0xbf, 0xbe, 0x1b, 0x52, 0xd5, 0x25, 0xed, 0x78, 0xa3,
-	0x86, 0xa9, 0xb9, 0xa1, 0x35, 0x88, 0xed, 0x6b, 0xf7, 0x79, 0x02, 0x3e, 0xd0, 0xf8, 0xd5, 0xdd,
-	0x94, 0xd6, 0xd0, 0xd7, 0x12, 0xcc, 0xa4, 0xa7, 0x6c, 0x74, 0x3e, 0xb2, 0x9d, 0xf3, 0x5f, 0x80,
-	0xa2, 0x16, 0xa9, 0x08, 0x14, 0x57, 0x19, 0x8a, 0x2b, 0xea, 0xa5, 0x62, 0x14, 0x61, 0x62, 0xd7,
-	0x03, 0x3c, 0x3f, 0x4b, 0x30, 0xdb, 0x35, 0xaf, 0xa1, 0x98, 0xb7, 0xbc, 0x21, 0x5e, 0x59, 0x29,
-	0xd4, 0x11, 0x90, 0xb6, 0x18, 0xa4, 0x6b, 0x68, 0xb3, 0x10, 0x92, 0x76, 0x3f, 0x3a, 0xd0, 0x07,
-	0x9b, 0x4e, 0x68, 0xca, 0xe0, 0xcd, 0xdd, 0x2f, 0xbc, 0x6e, 0x64, 0x8d, 0x94, 0xa8, 0x52, 0x00,
-	0x22, 0x51, 0x0e, 0x95, 0xcb, 0x7d, 0x68, 0x0a, 0xd0, 0xaf, 0x30, 0xd0, 0x1b, 0x48, 0x2b, 0xe6,
-	0x31, 0xc2, 0x59, 0xe3, 0xc9, 0x84, 0xbe, 0x97, 0x60, 0x2e, 0x63, 0x6e, 0x43, 0x17, 0x12, 0xbe,
-	0x73, 0xe6, 0x51, 0x65, 0xb5, 0x87, 0x96, 0x40, 0xf7, 0x22, 0x43, 0xb7, 0x86, 0x2a, 0xd9, 0xe8,
-	0x36, 0xad, 0x68, 0xa3, 0x20, 0xf0, 0x91, 0x78, 0x24, 0xba, 0x87, 0x26, 0x74, 0x29, 0xe1, 0x33,
-	0x7f, 0xd0, 0x53, 0x2a, 0xbd, 0x15, 0x05, 0xbe, 0xe7, 0x19, 0xbe, 0x55, 0xb4, 0x92, 0xc3, 0x5e,
-	0x50, 0xb1, 0xfd, 0xcd, 0x06, 0xb3, 0x80, 0x7e, 0x92, 0xe0, 0x54, 0xe6, 0x74, 0x83, 0x2e, 0x26,
-	0x1c, 0xe6, 0x8e, 0x59, 0xca, 0xa5, 0x9e, 0x7a, 0x02, 0xd7, 0xcb, 0x0c, 0x97, 0x86, 0x5e, 0xe8,
-	0x33, 0x3b, 0xf8, 0x3c, 0xc5, 0x12, 0x36, 0x3d, 0x9e, 0xc4, 0x13, 0x36, 0x67, 0xb4, 0x52, 0xd4,
-	0x22, 0x95, 0x64, 0xc2, 0xa2, 0xb5, 0xfe, 0xb3, 0x03, 0x59, 0x30, 0x26, 0x06, 0x05, 0x54, 0x8e,
-	0x5c, 0x24, 0xa7, 0x12, 0x65, 0x21, 0x63, 0x45, 0xf8, 0x5c, 0x61, 0x3e, 0x97, 0xd4, 0xc5, 0x9c,
-	0xeb, 0xe3, 0xb8, 0x0e, 0x45, 0x55, 0x98, 0x8c, 0x75, 0xef, 0xe8, 0x4c, 0x77, 0xed, 0x8b, 0xfa,
-	0x6e, 0x65, 0x29, 0x67, 0x55, 0x38, 0x3c, 0x81, 0x4c, 0x40, 0xdd, 0x5d, 0x32, 0x5a, 0xc9, 0xad,
-	0x68, 0x31, 0xdb, 0x17, 0x8a, 0x95, 0x3a, 0x2e, 0x3e, 0x64, 0x87, 0x94, 0xe8, 0x59, 0x53, 0x87,
-	0x94, 0xd5, 0x52, 0xa7, 0x0e, 0x29, 0xb3, 0xe5, 0xed, 0x32, 0xce, 0x9a, 0xbd, 0x1c, 0xe3, 0xf1,
-	0x1e, 0x35, 0xc7, 0x78, 0xa2, 0x57, 0x54, 0x4f, 0xa0, 0xdb, 0x50, 0x4a, 0x35, 0x45, 0x68, 0x39,
-	0x73, 0x63, 0xbc, 0x98, 0x9d, 0x2f, 0xd0, 0x08, 0x2d, 0x6f, 0xbd, 0x03, 0x0b, 0x16, 0x39, 0x0a,
-	0x5f, 0xd9, 0xe4, 0x5f, 0xed, 0x5b, 0x73, 0xb1, 0x47, 0xf0, 0x7a, 0xd3, 0xb9, 0x15, 0x08, 0x6f,
-	0x49, 0x1f, 0x28, 0xb6, 0x43, 0x0f, 0x5b, 0xb5, 0x75, 0x8b, 0x1c, 0x69, 0xe2, 0x5f, 0xfb, 0x70,
-	0x63, 0x6d, 0x94, 0xed, 0x7c, 0xe9, 0x9f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xe5, 0xa4, 0xd4, 0xe5,
-	0x30, 0x18, 0x00, 0x00,
+	// 1832 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x59, 0xcd, 0x6f, 0x1b, 0x4f,
+	0x19, 0x66, 0x62, 0x27, 0xb1, 0x5f, 0xe7, 0x73, 0xf2, 0x4b, 0xe2, 0x6c, 0x92, 0x26, 0xdd, 0x34,
+	0x8d, 0x1b, 0x4a, 0xdc, 0x14, 0xa1, 0x42, 0x54, 0x81, 0x92, 0xb4, 0x4a, 0x43, 0x03, 0xb4, 0x9b,
+	0x08, 0x55, 0x70, 0x58, 0x6d, 0xd6, 0x13, 0x67, 0xc1, 0xd9, 0x75, 0x77, 0xc7, 0xa1, 0x69, 0x55,
+	0x04, 0x45, 0xe5, 0xe3, 0x00, 0x17, 0x38, 0xf4, 0xc2, 0x87, 0x84, 0x10, 0xaa, 0xb8, 0x73, 0xe1,
+	0xc8, 0x1d, 0x21, 0xf1, 0x0f, 0x70, 0xe0, 0x0f, 0x41, 0x3b, 0x33, 0xfb, 0xe9, 0xfd, 0xb0, 0x9b,
+	0xb4, 0x70, 0xf3, 0xce, 0xbc, 0xf3, 0xce, 0xf3, 0x3e, 0x33, 0xef, 0xcc, 0x33, 0xaf, 0x61, 0x86,
+	0xda, 0x46, 0xab, 0x65, 0x68, 0xa6, 0xda, 0xb2, 0x9a, 0xaa, 0xd6, 0x36, 0x36, 0xda, 0xb6, 0x45,
+	0x2d, 0x5c, 0xf2, 0xda, 0xa5, 0x85, 0xa6, 0x65, 0x35, 0x5b, 0xa4, 0xae, 0xb5, 0x8d, 0xba, 0x66,
+	0x9a, 0x16, 0xd5, 0xa8, 0x61, 0x99, 0x0e, 0xb7, 0x93, 0x96, 0x44, 0x2f, 0xfb, 0x3a, 0xee, 0x9c,
+	0xd4, 0xa9, 0x71, 0x46, 0x1c, 0xaa, 0x9d, 0xb5, 0x85, 0xc1, 0xac, 0x30, 0xb0, 0xdb, 0x7a, 0xdd,
+	0xa1, 0x1a, 0xed, 0x78, 0x23, 0xc7, 0xbc, 0x19, 0xf8, 0xb7, 0x7c, 0x0d, 0x4a, 0xbb, 0xa7, 0x9a,
+	0xdd, 0x24, 0x47, 0x16, 0xc6, 0x50, 0xec, 0x38, 0xc4, 0xae, 0xa2, 0xe5, 0x42, 0xad, 0xac, 0xb0,
+	0xdf, 0xf2, 0x8f, 0x11, 0x4c, 0x3c, 0xed, 0x90, 0x0e, 0x39, 0x20, 0xda, 0x89, 0x42, 0x9e, 0x77,
+	0x88, 0x43, 0xf1, 0x34, 0x0c, 0xb9, 0xb8, 0x8d, 0x46, 0x15, 0x2d, 0xa3, 0x5a, 0x41, 0x19, 0x6c,
+	0x59, 0xcd, 0xfd, 0x06, 0x5e, 0x85, 0x62, 0x8b, 0x68, 0x27, 0xd5, 0x81, 0x65, 0x54, 0xab, 0xdc,
+	0x9d, 0xdc, 0xf0, 0xa7, 0x3a, 0xb0, 0x9a, 0x6c, 0x38, 0xeb, 0xc6, 0x75, 0x28, 0xeb, 0x6c, 0x4a,
+	0x95, 0x5a, 0xd5, 0x02, 0xb3, 0xc5, 0x81, 0xad, 0x87, 0x46, 0x29, 0xe9, 0xe2, 0x97, 0xfc, 0x0d,
+	0x98, 0x0c, 0x41, 0x70, 0xda, 0x96, 0xe9, 0x10, 0xfc, 0x65, 0xa8, 0x3c, 0x77, 0x1b, 0x1b, 0x6a,
+	0x68, 0xce, 0xd9, 0xc0, 0x0f, 0x1b, 0xd1, 0xf0, 0x66, 0x06, 0x6e, 0xeb, 0xfe, 0x96, 0x7f, 0x8e,
+	0x60, 0x76, 0xbb, 0xd1, 0x38, 0x74, 0x83, 0x31, 0x75, 0xd2, 0xf8, 0x1f, 0x46, 0xf6, 0x18, 0xaa,
+	0xdd, 0x48, 0x44, 0x80, 0x75, 0x18, 0xb2, 0x89, 0xd3, 0x69, 0xd1, 0xbc, 0xd8, 0x84, 0x99, 0xfc,
+	0x3b, 0x04, 0xd5, 0x3d, 0x42, 0xf7, 0x4d, 0xbd, 0xd5, 0x71, 0x0c, 0xcb, 0x7c, 0x62, 0x5b, 0x56,
+	0x5e, 0x60, 0x8b, 0x00, 0x2e, 0x72, 0xd5, 0x30, 0x1b, 0xe4, 0x05, 0x9b, 0xa8, 0xa0, 0x94, 0xdd,
+	0x96, 0x7d, 0xb7, 0x01, 0xcf, 0x43, 0x99, 0xda, 0x84, 0xa8, 0x8e, 0xf1, 0x92, 0xb0, 0x80, 0x0a,
+	0x4a, 0xc9, 0x6d, 0x38, 0x34, 0x5e, 0x92, 0x68, 0xb4, 0xc5, 0x1e, 0xa2, 0xfd, 0x09, 0x82, 0xb9,
+	0x04, 0x80, 0x22, 0xde, 0x55, 0x18, 0x6c, 0xbb, 0x0d, 0x22, 0xdc, 0xf1, 0xc0, 0x15, 0xb7, 0xe3,
+	0xbd, 0xf8, 0x6b, 0x30, 0xee, 0x18, 0x4d, 0xd3, 0x5d, 0x77, 0xab, 0xa9, 0xda, 0x96, 0x45, 0xab,
+	0x85, 0x38, 0x3f, 0x87, 0xcc, 0xe0, 0xc0, 0x6a, 0x2a, 0x96, 0x45, 0x95, 0x51, 0x27, 0xfc, 0x29,
+	0xff, 0x11, 0xc1, 0xe2, 0x1e, 0xa1, 0x3b, 0x1a, 0xd5, 0x4f, 0x2f, 0xc5, 0x55, 0xe1, 0x63, 0x72,
+	0xf5, 0x0e, 0xc1, 0xb5, 0x34, 0x94, 0x82, 0xb0, 0x28, 0x1e, 0x14, 0xc7, 0x33, 0x03, 0x43, 0xa7,
+	0x9a, 0x73, 0x4a, 0x1c, 0x06, 0x75, 0x44, 0x11, 0x5f, 0x97, 0x27, 0xf0, 0x1f, 0x1c, 0x5a, 0x14,
+	0xd5, 0xce, 0xc5, 0x23, 0xcd, 0x39, 0xcd, 0x61, 0x70, 0x1e, 0x18, 0x3e, 0xd5, 0x45, 0xc2, 0x96,
+	0x79, 0x44, 0x29, 0xb9, 0x0d, 0xee, 0xd0, 0x6c, 0xfe, 0xd6, 0x61, 0xd2, 0xb2, 0x1b, 0xc4, 0x56,
+	0x8f, 0x2f, 0x54, 0x47, 0xa4, 0x0b, 0xe3, 0xb1, 0xa4, 0x8c, 0xb3, 0x8e, 0x9d, 0x0b, 0x2f, 0x8b,
+	0xa2, 0x5c, 0x0f, 0xf6, 0xc0, 0xf5, 0x2f, 0x10, 0x2c, 0xa5, 0x06, 0xd4, 0xbd, 0x3b, 0x0b, 0x1f,
+	0x73, 0x77, 0xfe, 0x15, 0x81, 0xb4, 0x47, 0xe8, 0xae, 0x65, 0x3a, 0x86, 0x43, 0x89, 0xa9, 0x5f,
+	0xf4, 0xb2, 0x35, 0x6f, 0xc2, 0xf8, 0x89, 0x61, 0x3b, 0x54, 0x0d, 0x18, 0xe4, 0xb9, 0x3c, 0xca,
+	0x9a, 0x8f, 0x3c, 0x1a, 0x6b, 0x30, 0xe1, 0x10, 0xdd, 0x32, 0x1b, 0x6a, 0x9c, 0xea, 0x31, 0xde,
+	0x7e, 0xf4, 0xc1, 0x1b, 0xf6, 0x2d, 0x82, 0xf9, 0x44, 0xe0, 0x9f, 0x38, 0xbd, 0xff, 0xc2, 0xd3,
+	0xfb, 0x40, 0xa3, 0xc4, 0xa1, 0x51, 0xcb, 0x6c, 0x0e, 0x23, 0x11, 0x0f, 0xe4, 0x47, 0x9c, 0x44,
+	0x7a, 0x21, 0x89, 0x74, 0x09, 0x4a, 0xba, 0xc5, 0x41, 0x8a, 0x2d, 0xeb, 0x7f, 0xcb, 0x7f, 0xe7,
+	0xb9, 0x94, 0x88, 0x56, 0x10, 0x97, 0xc0, 0xc8, 0x40, 0x3f, 0x8c, 0x04, 0xcc, 0x17, 0x32, 0x99,
+	0xff, 0x0a, 0x8c, 0x70, 0x58, 0x1a, 0xed, 0xd8, 0xc4, 0xa9, 0x16, 0xd9, 0x46, 0x9f, 0x0e, 0x51,
+	0x10, 0xf4, 0x2a, 0x11, 0x53, 0xf9, 0x04, 0x16, 0xf6, 0x08, 0x8d, 0x5c, 0x63, 0xbb, 0x56, 0xc7,
+	0xbc, 0x6a, 0xc6, 0xe5, 0xaf, 0xc2, 0x62, 0xca, 0x3c, 0xb1, 0x23, 0x51, 0x77, 0x5b, 0xc3, 0xd7,
+	0x19, 0x33, 0x93, 0x7f, 0x8b, 0x60, 0x76, 0x8f, 0xd0, 0x87, 0x26, 0xb5, 0x2f, 0xb6, 0xcd, 0xc6,
+	0xff, 0xdd, 0x05, 0xf9, 0x9e, 0xdf, 0xe0, 0x31, 0x7c, 0xfd, 0x25, 0x90, 0x27, 0x55, 0x0a, 0xd9,
+	0x52, 0x25, 0x61, 0x57, 0x15, 0xfb, 0xca, 0xb3, 0x67, 0x30, 0xb6, 0x6f, 0x1a, 0xd4, 0xfd, 0xbc,
+	0xe2, 0x55, 0x7e, 0x00, 0xe3, 0xbe, 0x67, 0x11, 0xfb, 0x26, 0x0c, 0xeb, 0x36, 0xd1, 0x28, 0xe1,
+	0xbe, 0x33, 0x50, 0x7a, 0x76, 0xf2, 0xcf, 0x10, 0x60, 0x4f, 0x35, 0x9e, 0x13, 0x27, 0x07, 0xe4,
+	0x2d, 0x18, 0x6a, 0x31, 0x3b, 0x71, 0xbe, 0x27, 0xf0, 0x26, 0x0c, 0xfa, 0x17, 0x79, 0x87, 0x30,
+	0x15, 0x01, 0x22, 0x62, 0xba, 0x0f, 0xa3, 0x81, 0x80, 0x0d, 0x66, 0x4e, 0x95, 0x79, 0x23, 0xbe,
+	0x84, 0x3d, 0x27, 0x8e, 0xfc, 0x2b, 0x04, 0x73, 0x31, 0xe9, 0xf8, 0xf1, 0xa2, 0xec, 0x65, 0xef,
+	0x7e, 0x0b, 0xa4, 0x24, 0x3c, 0xc1, 0x02, 0x72, 0x95, 0x9a, 0x1b, 0xa6, 0x67, 0x27, 0xff, 0x88,
+	0x27, 0x2b, 0x77, 0xb4, 0x73, 0xc1, 0xf2, 0xed, 0x72, 0x0a, 0xad, 0x6f, 0x61, 0xf0, 0x53, 0x9e,
+	0x8f, 0x31, 0x08, 0x22, 0xa4, 0x3e, 0xc8, 0xbc, 0xf4, 0xa5, 0xf6, 0x2e, 0xca, 0x85, 0xa2, 0x99,
+	0x4d, 0x92, 0xc3, 0xc5, 0x12, 0x54, 0x1c, 0xaa, 0xd9, 0x34, 0x72, 0x72, 0x01, 0x6b, 0xe2, 0x6c,
+	0x7c, 0x06, 0x83, 0xfc, 0x98, 0xe4, 0xc7, 0x16, 0xff, 0xe8, 0x7f, 0xdd, 0x63, 0x1c, 0x09, 0x68,
+	0x5d, 0x1c, 0xa1, 0x0f, 0xe0, 0xa8, 0xaf, 0x6b, 0x4e, 0xfe, 0x1b, 0xbf, 0xf8, 0x19, 0x80, 0xbe,
+	0x74, 0xfd, 0x07, 0x32, 0x15, 0x39, 0xfa, 0x8b, 0x59, 0x47, 0x7f, 0x2f, 0x5b, 0xed, 0x4f, 0x5c,
+	0x08, 0x24, 0xa2, 0x17, 0x64, 0x2e, 0x41, 0xa5, 0x45, 0x4e, 0xa8, 0x2a, 0x54, 0x3d, 0x62, 0xaa,
+	0x1e, 0xdc, 0xa6, 0x47, 0xac, 0x05, 0x5f, 0x87, 0x11, 0xdb, 0x68, 0x9e, 0xfa, 0x16, 0x5c, 0xf7,
+	0x57, 0x58, 0xdb, 0xa3, 0x2b, 0x12, 0xff, 0xef, 0x11, 0xcc, 0x84, 0x96, 0xbb, 0x7f, 0xd1, 0x5f,
+	0x88, 0x88, 0xfe, 0x44, 0x5d, 0x5f, 0xb8, 0x22, 0x5d, 0xff, 0x36, 0x9a, 0x35, 0x11, 0x3d, 0xff,
+	0x29, 0xb3, 0xf7, 0x21, 0x54, 0x42, 0xda, 0x09, 0x57, 0x61, 0xf8, 0x07, 0x06, 0x35, 0x89, 0xe3,
+	0x30, 0xa2, 0xca, 0x8a, 0xf7, 0x89, 0x17, 0xa0, 0xec, 0x9b, 0x89, 0xf7, 0x51, 0xd0, 0x20, 0xff,
+	0x1b, 0xc1, 0xc2, 0x76, 0xc3, 0xf3, 0x1a, 0xf2, 0x98, 0xb3, 0x00, 0x97, 0x16, 0x90, 0xf7, 0xa0,
+	0x12, 0x92, 0x7b, 0x22, 0xf8, 0x14, 0x61, 0x18, 0xb6, 0xec, 0xff, 0x30, 0x59, 0x82, 0xc5, 0x94,
+	0x08, 0xf9, 0xb2, 0xc9, 0x3f, 0x84, 0xa9, 0x43, 0x6a, 0x13, 0xed, 0xac, 0xa7, 0xfb, 0x2e, 0x37,
+	0xb3, 0xfb, 0xbe, 0xcb, 0xdf, 0x20, 0xf8, 0x2c, 0x0a, 0x20, 0x5d, 0xa5, 0xa3, 0xbe, 0x48, 0xee,
+	0x7d, 0x43, 0xca, 0xc7, 0x30, 0x1a, 0xb9, 0x33, 0x7d, 0xcd, 0x87, 0xb2, 0x35, 0xdf, 0x3a, 0x0c,
+	0xf1, 0x5a, 0xa0, 0x2f, 0xc3, 0x78, 0x95, 0x70, 0xc3, 0x6e, 0xeb, 0x1b, 0x87, 0xac, 0x47, 0x11,
+	0x16, 0xf2, 0x3f, 0x07, 0x60, 0xd8, 0x73, 0x5f, 0x83, 0x89, 0x33, 0x62, 0x7f, 0xbf, 0x45, 0xd4,
+	0x20, 0x91, 0x11, 0xdb, 0x9d, 0x63, 0xbc, 0xfd, 0xc0, 0x4b, 0x67, 0xef, 0x02, 0x3e, 0xd7, 0x5a,
+	0x1d, 0x7f, 0x07, 0xbb, 0x2d, 0xdf, 0x76, 0x1b, 0xdc, 0x6e, 0xf2, 0x82, 0xda, 0x9a, 0xda, 0xd0,
+	0xa8, 0xc6, 0xf8, 0x1e, 0x51, 0xca, 0xac, 0xe5, 0x81, 0x46, 0xb5, 0xd8, 0xf5, 0x5d, 0x8c, 0x6b,
+	0xed, 0xdb, 0x80, 0x79, 0x77, 0x83, 0x98, 0xd4, 0xa0, 0x17, 0x1c, 0xc8, 0x20, 0xf3, 0x32, 0xc1,
+	0xcc, 0x44, 0x07, 0x83, 0xb2, 0x0b, 0xe3, 0x4c, 0x30, 0xa9, 0x7e, 0x69, 0xb4, 0x3a, 0xc4, 0xa2,
+	0x96, 0xbc, 0xa8, 0xbd, 0xe2, 0xe9, 0xc6, 0x91, 0x67, 0xa1, 0x8c, 0xb1, 0x21, 0xfe, 0x37, 0x7e,
+	0x0c, 0x53, 0x86, 0x49, 0x49, 0xd3, 0xd6, 0x68, 0xd8, 0xd1, 0x70, 0xae, 0x23, 0xec, 0x0f, 0xf3,
+	0xdb, 0xe4, 0x07, 0x30, 0xc8, 0x0e, 0xf4, 0xae, 0xc2, 0x0d, 0x4a, 0x2b, 0xdc, 0x14, 0xc2, 0x85,
+	0x9b, 0xaf, 0x17, 0x4b, 0x03, 0x13, 0x85, 0xbb, 0x6f, 0x27, 0xa1, 0x72, 0x24, 0xd6, 0xf7, 0xc0,
+	0x6a, 0x62, 0x13, 0xca, 0x7e, 0x71, 0x14, 0x4b, 0x31, 0x55, 0x15, 0x2a, 0x6d, 0x4a, 0xf3, 0x89,
+	0x7d, 0x22, 0xaf, 0x6a, 0x6f, 0xfe, 0xf5, 0x9f, 0x5f, 0x0f, 0xc8, 0xf2, 0x62, 0xfd, 0x7c, 0xf3,
+	0x98, 0x50, 0x6d, 0xb3, 0xde, 0xb2, 0x9a, 0x4e, 0xfd, 0x15, 0xcf, 0xaa, 0xd7, 0x75, 0xbe, 0xf3,
+	0xb6, 0xd0, 0x3a, 0xfe, 0x25, 0x82, 0x89, 0x78, 0xcd, 0x12, 0x5f, 0x0f, 0x7c, 0xa7, 0x54, 0x56,
+	0x25, 0x39, 0xcb, 0x44, 0xa0, 0xb8, 0xcb, 0x50, 0xdc, 0x96, 0xd7, 0xb2, 0x51, 0x78, 0x17, 0x45,
+	0xc3, 0xc5, 0xf3, 0x07, 0x04, 0x93, 0x5d, 0xc5, 0x1b, 0x1c, 0x9a, 0x2d, 0xad, 0x24, 0x2a, 0xad,
+	0x64, 0xda, 0x08, 0x48, 0x3b, 0x0c, 0xd2, 0x7d, 0xbc, 0x95, 0x09, 0xa9, 0xfe, 0x2a, 0x58, 0xd0,
+	0xd7, 0x5b, 0x86, 0xe7, 0x4a, 0xe5, 0x4f, 0xb2, 0x3f, 0xf3, 0x7b, 0x28, 0xa9, 0xbe, 0x84, 0x6b,
+	0x19, 0x20, 0x22, 0xd7, 0xab, 0x74, 0xab, 0x07, 0x4b, 0x01, 0xfa, 0x1e, 0x03, 0xbd, 0x89, 0xeb,
+	0xd9, 0x3c, 0x06, 0x38, 0x8f, 0x79, 0x32, 0xe1, 0x33, 0x98, 0x49, 0x2e, 0x3a, 0xe2, 0xb5, 0xc8,
+	0xec, 0xe9, 0xc5, 0x53, 0xa9, 0x96, 0x6f, 0x28, 0x50, 0x7e, 0x0e, 0xff, 0x06, 0xc1, 0x54, 0x42,
+	0xcd, 0x08, 0xdf, 0x88, 0xf8, 0x48, 0xa9, 0x85, 0x49, 0xab, 0x39, 0x56, 0x62, 0x9a, 0x3b, 0x8c,
+	0x8c, 0x75, 0x5c, 0x4b, 0x26, 0x63, 0x4b, 0x0f, 0x06, 0x8a, 0xf5, 0x7a, 0x27, 0x34, 0x4e, 0x77,
+	0x51, 0x26, 0x46, 0x43, 0x7a, 0x91, 0x49, 0xaa, 0xe5, 0x1b, 0x0a, 0x7c, 0x9f, 0x67, 0xf8, 0x56,
+	0xf1, 0x4a, 0xca, 0x62, 0xb9, 0x77, 0x89, 0xb3, 0xd5, 0x62, 0x1e, 0xf0, 0xef, 0x11, 0x4c, 0x27,
+	0x96, 0x40, 0xf0, 0xcd, 0xc8, 0x84, 0xa9, 0xb5, 0x18, 0x69, 0x2d, 0xd7, 0x4e, 0xe0, 0xfa, 0x12,
+	0xc3, 0x55, 0xc7, 0x5f, 0xe8, 0x31, 0x19, 0x79, 0xd1, 0x85, 0x9d, 0x0f, 0xf1, 0x1a, 0x46, 0xf8,
+	0x7c, 0x48, 0xa9, 0xbf, 0x48, 0x72, 0x96, 0x49, 0xf4, 0x7c, 0xc0, 0xeb, 0xbd, 0x27, 0x23, 0xd6,
+	0x61, 0x58, 0x54, 0x13, 0x70, 0x35, 0x98, 0x22, 0x5a, 0xba, 0x90, 0xe6, 0x12, 0x7a, 0xc4, 0x9c,
+	0x2b, 0x6c, 0xce, 0x45, 0x79, 0x3e, 0x65, 0xfb, 0x18, 0xa6, 0x41, 0xf1, 0x01, 0x54, 0x42, 0x4f,
+	0x7c, 0xbc, 0xd0, 0x7d, 0xd4, 0x06, 0x62, 0x45, 0x5a, 0x4c, 0xe9, 0xf5, 0xd3, 0x42, 0x03, 0xdc,
+	0xfd, 0x94, 0xc6, 0x2b, 0xa9, 0x07, 0x68, 0xc8, 0xf7, 0x8d, 0x6c, 0x23, 0x7f, 0x8a, 0xef, 0xb2,
+	0x45, 0x8a, 0x3c, 0x6c, 0x63, 0x8b, 0x94, 0xf4, 0xee, 0x96, 0xe4, 0x2c, 0x93, 0x14, 0xe7, 0xec,
+	0x49, 0x93, 0xe2, 0x3c, 0xfc, 0x90, 0x95, 0xe4, 0x2c, 0x13, 0xdf, 0x39, 0x3f, 0xa2, 0x12, 0xde,
+	0x49, 0xb1, 0xdc, 0x4c, 0x7f, 0x07, 0x4a, 0xb5, 0x7c, 0x43, 0x7f, 0xba, 0x67, 0x30, 0x1e, 0x7b,
+	0x42, 0xe0, 0xe5, 0x44, 0x9c, 0xe1, 0xa3, 0xfa, 0x7a, 0x86, 0x85, 0xef, 0xf9, 0x29, 0x8c, 0x84,
+	0x95, 0x24, 0x0e, 0x6d, 0x8b, 0x04, 0x89, 0x2b, 0x5d, 0x4b, 0xeb, 0xf6, 0x1c, 0xde, 0x41, 0xf8,
+	0x7b, 0x30, 0x9d, 0x28, 0x9f, 0xc3, 0x87, 0x43, 0xd6, 0x0b, 0x42, 0x5a, 0xcb, 0xb5, 0xf3, 0x66,
+	0xdb, 0xf9, 0x26, 0xcc, 0xe9, 0xd6, 0x99, 0x27, 0x81, 0xa2, 0xff, 0x2a, 0xef, 0x4c, 0x85, 0x14,
+	0xca, 0x76, 0xdb, 0x78, 0xe2, 0x36, 0x3e, 0x41, 0xdf, 0x91, 0x9a, 0x06, 0x3d, 0xed, 0x1c, 0x6f,
+	0xe8, 0xd6, 0x59, 0x9d, 0x0f, 0xac, 0x7b, 0x03, 0x8f, 0x87, 0xd8, 0xc8, 0x2f, 0xfe, 0x77, 0x00,
+	0xfb, 0x80, 0xa3, 0x1e, 0x1b, 0x1f, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1975,6 +2536,13 @@ type TrillianLogClient interface {
 	// If any of the leaves that match the given Merkle has have a leaf index that
 	// is beyond the requested tree size, the corresponding proof entry will be empty.
 	GetInclusionProofByHash(ctx context.Context, in *GetInclusionProofByHashRequest, opts ...grpc.CallOption) (*GetInclusionProofByHashResponse, error)
+	// GetBatchInclusionProof returns a single inclusion proof for several leaves,
+	// given by index, in a particular tree. Proof nodes shared by the leaves are
+	// only returned once.
+	//
+	// If the requested tree_size is larger than the server is aware of, the
+	// response will include the latest known log root and an empty proof.
+	GetBatchInclusionProof(ctx context.Context, in *GetBatchInclusionProofRequest, opts ...grpc.CallOption) (*GetBatchInclusionProofResponse, error)
 	// GetConsistencyProof returns a consistency proof between different sizes of
 	// a particular tree.
 	//
@@ -2018,9 +2586,40 @@ type TrillianLogClient interface {
 	// GetLeavesByRange returns a batch of leaves whose leaf indices are in a
 	// sequential range.
 	GetLeavesByRange(ctx context.Context, in *GetLeavesByRangeRequest, opts ...grpc.CallOption) (*GetLeavesByRangeResponse, error)
+	// GetRangeInclusionProof returns a proof that a contiguous range of leaves,
+	// as returned by GetLeavesByRange, is included in a particular tree. The
+	// proof holds the compact ranges of the leaves before and after the range,
+	// which a client merges with the range's own leaves to compute the root.
+	//
+	// If the requested tree_size is larger than the server is aware of, the
+	// response will include the latest known log root and an empty proof.
+	GetRangeInclusionProof(ctx context.Context, in *GetRangeInclusionProofRequest, opts ...grpc.CallOption) (*GetRangeInclusionProofResponse, error)
 	// GetLeavesByHash returns a batch of leaves which are identified by their
 	// Merkle leaf hash values.
 	GetLeavesByHash(ctx context.Context, in *GetLeavesByHashRequest, opts ...grpc.CallOption) (*GetLeavesByHashResponse, error)
+	// StreamLeaves returns the sequenced leaves of a log in order, starting from
+	// start_index, and keeps the stream open to send further leaves as they are
+	// integrated into the log.
+	//
+	// Each new signed log root seen by the server is sent ahead of the leaves
+	// that it covers, so clients can verify the leaves as they arrive. A client
+	// can resume a broken stream by sending a new request whose start_index is
+	// the index following the last leaf received.
+	StreamLeaves(ctx context.Context, in *StreamLeavesRequest, opts ...grpc.CallOption) (TrillianLog_StreamLeavesClient, error)
+	// AddLogRootCosignature submits a witness's cosignature of a signed log
+	// root. The server keeps the cosignatures of the most recent root cosigned
+	// by its configured witnesses, and returns them from GetLatestSignedLogRoot.
+	//
+	// Cosignatures from unknown witnesses, with invalid signatures, or for roots
+	// older than the most recently cosigned one are rejected. Roots signed with
+	// a previous key of the log are verified with the key named by their
+	// key_hint.
+	//
+	// Cosignatures are only kept in the memory of the server which receives
+	// them: they are not shared with other replicas of the log server, and are
+	// lost when it restarts. Witnesses and the clients which read cosignatures
+	// must therefore use a single log server replica.
+	AddLogRootCosignature(ctx context.Context, in *AddLogRootCosignatureRequest, opts ...grpc.CallOption) (*AddLogRootCosignatureResponse, error)
 }
 
 type trillianLogClient struct {
@@ -2067,6 +2666,15 @@ func (c *trillianLogClient) GetInclusionProofByHash(ctx context.Context, in *Get
 	return out, nil
 }
 
+func (c *trillianLogClient) GetBatchInclusionProof(ctx context.Context, in *GetBatchInclusionProofRequest, opts ...grpc.CallOption) (*GetBatchInclusionProofResponse, error) {
+	out := new(GetBatchInclusionProofResponse)
+	err := c.cc.Invoke(ctx, "/trillian.TrillianLog/GetBatchInclusionProof", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *trillianLogClient) GetConsistencyProof(ctx context.Context, in *GetConsistencyProofRequest, opts ...grpc.CallOption) (*GetConsistencyProofResponse, error) {
 	out := new(GetConsistencyProofResponse)
 	err := c.cc.Invoke(ctx, "/trillian.TrillianLog/GetConsistencyProof", in, out, opts...)
@@ -2148,6 +2756,15 @@ func (c *trillianLogClient) GetLeavesByRange(ctx context.Context, in *GetLeavesB
 	return out, nil
 }
 
+func (c *trillianLogClient) GetRangeInclusionProof(ctx context.Context, in *GetRangeInclusionProofRequest, opts ...grpc.CallOption) (*GetRangeInclusionProofResponse, error) {
+	out := new(GetRangeInclusionProofResponse)
+	err := c.cc.Invoke(ctx, "/trillian.TrillianLog/GetRangeInclusionProof", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *trillianLogClient) GetLeavesByHash(ctx context.Context, in *GetLeavesByHashRequest, opts ...grpc.CallOption) (*GetLeavesByHashResponse, error) {
 	out := new(GetLeavesByHashResponse)
 	err := c.cc.Invoke(ctx, "/trillian.TrillianLog/GetLeavesByHash", in, out, opts...)
@@ -2157,6 +2774,47 @@ func (c *trillianLogClient) GetLeavesByHash(ctx context.Context, in *GetLeavesBy
 	return out, nil
 }
 
+func (c *trillianLogClient) StreamLeaves(ctx context.Context, in *StreamLeavesRequest, opts ...grpc.CallOption) (TrillianLog_StreamLeavesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TrillianLog_serviceDesc.Streams[0], "/trillian.TrillianLog/StreamLeaves", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &trillianLogStreamLeavesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TrillianLog_StreamLeavesClient interface {
+	Recv() (*StreamLeavesResponse, error)
+	grpc.ClientStream
+}
+
+type trillianLogStreamLeavesClient struct {
+	grpc.ClientStream
+}
+
+func (x *trillianLogStreamLeavesClient) Recv() (*StreamLeavesResponse, error) {
+	m := new(StreamLeavesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *trillianLogClient) AddLogRootCosignature(ctx context.Context, in *AddLogRootCosignatureRequest, opts ...grpc.CallOption) (*AddLogRootCosignatureResponse, error) {
+	out := new(AddLogRootCosignatureResponse)
+	err := c.cc.Invoke(ctx, "/trillian.TrillianLog/AddLogRootCosignature", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TrillianLogServer is the server API for TrillianLog service.
 type TrillianLogServer interface {
 	// QueueLeaf adds a single leaf to the queue of pending leaves for a normal
@@ -2177,6 +2835,13 @@ type TrillianLogServer interface {
 	// If any of the leaves that match the given Merkle has have a leaf index that
 	// is beyond the requested tree size, the corresponding proof entry will be empty.
 	GetInclusionProofByHash(context.Context, *GetInclusionProofByHashRequest) (*GetInclusionProofByHashResponse, error)
+	// GetBatchInclusionProof returns a single inclusion proof for several leaves,
+	// given by index, in a particular tree. Proof nodes shared by the leaves are
+	// only returned once.
+	//
+	// If the requested tree_size is larger than the server is aware of, the
+	// response will include the latest known log root and an empty proof.
+	GetBatchInclusionProof(context.Context, *GetBatchInclusionProofRequest) (*GetBatchInclusionProofResponse, error)
 	// GetConsistencyProof returns a consistency proof between different sizes of
 	// a particular tree.
 	//
@@ -2220,9 +2885,40 @@ type TrillianLogServer interface {
 	// GetLeavesByRange returns a batch of leaves whose leaf indices are in a
 	// sequential range.
 	GetLeavesByRange(context.Context, *GetLeavesByRangeRequest) (*GetLeavesByRangeResponse, error)
+	// GetRangeInclusionProof returns a proof that a contiguous range of leaves,
+	// as returned by GetLeavesByRange, is included in a particular tree. The
+	// proof holds the compact ranges of the leaves before and after the range,
+	// which a client merges with the range's own leaves to compute the root.
+	//
+	// If the requested tree_size is larger than the server is aware of, the
+	// response will include the latest known log root and an empty proof.
+	GetRangeInclusionProof(context.Context, *GetRangeInclusionProofRequest) (*GetRangeInclusionProofResponse, error)
 	// GetLeavesByHash returns a batch of leaves which are identified by their
 	// Merkle leaf hash values.
 	GetLeavesByHash(context.Context, *GetLeavesByHashRequest) (*GetLeavesByHashResponse, error)
+	// StreamLeaves returns the sequenced leaves of a log in order, starting from
+	// start_index, and keeps the stream open to send further leaves as they are
+	// integrated into the log.
+	//
+	// Each new signed log root seen by the server is sent ahead of the leaves
+	// that it covers, so clients can verify the leaves as they arrive. A client
+	// can resume a broken stream by sending a new request whose start_index is
+	// the index following the last leaf received.
+	StreamLeaves(*StreamLeavesRequest, TrillianLog_StreamLeavesServer) error
+	// AddLogRootCosignature submits a witness's cosignature of a signed log
+	// root. The server keeps the cosignatures of the most recent root cosigned
+	// by its configured witnesses, and returns them from GetLatestSignedLogRoot.
+	//
+	// Cosignatures from unknown witnesses, with invalid signatures, or for roots
+	// older than the most recently cosigned one are rejected. Roots signed with
+	// a previous key of the log are verified with the key named by their
+	// key_hint.
+	//
+	// Cosignatures are only kept in the memory of the server which receives
+	// them: they are not shared with other replicas of the log server, and are
+	// lost when it restarts. Witnesses and the clients which read cosignatures
+	// must therefore use a single log server replica.
+	AddLogRootCosignature(context.Context, *AddLogRootCosignatureRequest) (*AddLogRootCosignatureResponse, error)
 }
 
 // UnimplementedTrillianLogServer can be embedded to have forward compatible implementations.
@@ -2241,6 +2937,9 @@ func (*UnimplementedTrillianLogServer) GetInclusionProof(ctx context.Context, re
 func (*UnimplementedTrillianLogServer) GetInclusionProofByHash(ctx context.Context, req *GetInclusionProofByHashRequest) (*GetInclusionProofByHashResponse, error) {
 	return nil, status1.Errorf(codes.Unimplemented, "method GetInclusionProofByHash not implemented")
 }
+func (*UnimplementedTrillianLogServer) GetBatchInclusionProof(ctx context.Context, req *GetBatchInclusionProofRequest) (*GetBatchInclusionProofResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method GetBatchInclusionProof not implemented")
+}
 func (*UnimplementedTrillianLogServer) GetConsistencyProof(ctx context.Context, req *GetConsistencyProofRequest) (*GetConsistencyProofResponse, error) {
 	return nil, status1.Errorf(codes.Unimplemented, "method GetConsistencyProof not implemented")
 }
@@ -2268,9 +2967,18 @@ func (*UnimplementedTrillianLogServer) GetLeavesByIndex(ctx context.Context, req
 func (*UnimplementedTrillianLogServer) GetLeavesByRange(ctx context.Context, req *GetLeavesByRangeRequest) (*GetLeavesByRangeResponse, error) {
 	return nil, status1.Errorf(codes.Unimplemented, "method GetLeavesByRange not implemented")
 }
+func (*UnimplementedTrillianLogServer) GetRangeInclusionProof(ctx context.Context, req *GetRangeInclusionProofRequest) (*GetRangeInclusionProofResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method GetRangeInclusionProof not implemented")
+}
 func (*UnimplementedTrillianLogServer) GetLeavesByHash(ctx context.Context, req *GetLeavesByHashRequest) (*GetLeavesByHashResponse, error) {
 	return nil, status1.Errorf(codes.Unimplemented, "method GetLeavesByHash not implemented")
 }
+func (*UnimplementedTrillianLogServer) StreamLeaves(req *StreamLeavesRequest, srv TrillianLog_StreamLeavesServer) error {
+	return status1.Errorf(codes.Unimplemented, "method StreamLeaves not implemented")
+}
+func (*UnimplementedTrillianLogServer) AddLogRootCosignature(ctx context.Context, req *AddLogRootCosignatureRequest) (*AddLogRootCosignatureResponse, error) {
+	return nil, status1.Errorf(codes.Unimplemented, "method AddLogRootCosignature not implemented")
+}
 
 func RegisterTrillianLogServer(s *grpc.Server, srv TrillianLogServer) {
 	s.RegisterService(&_TrillianLog_serviceDesc, srv)
@@ -2348,6 +3056,24 @@ func _TrillianLog_GetInclusionProofByHash_Handler(srv interface{}, ctx context.C
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TrillianLog_GetBatchInclusionProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBatchInclusionProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrillianLogServer).GetBatchInclusionProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trillian.TrillianLog/GetBatchInclusionProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrillianLogServer).GetBatchInclusionProof(ctx, req.(*GetBatchInclusionProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _TrillianLog_GetConsistencyProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetConsistencyProofRequest)
 	if err := dec(in); err != nil {
@@ -2510,6 +3236,24 @@ func _TrillianLog_GetLeavesByRange_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TrillianLog_GetRangeInclusionProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRangeInclusionProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrillianLogServer).GetRangeInclusionProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trillian.TrillianLog/GetRangeInclusionProof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrillianLogServer).GetRangeInclusionProof(ctx, req.(*GetRangeInclusionProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _TrillianLog_GetLeavesByHash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetLeavesByHashRequest)
 	if err := dec(in); err != nil {
@@ -2528,6 +3272,45 @@ func _TrillianLog_GetLeavesByHash_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TrillianLog_StreamLeaves_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamLeavesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TrillianLogServer).StreamLeaves(m, &trillianLogStreamLeavesServer{stream})
+}
+
+type TrillianLog_StreamLeavesServer interface {
+	Send(*StreamLeavesResponse) error
+	grpc.ServerStream
+}
+
+type trillianLogStreamLeavesServer struct {
+	grpc.ServerStream
+}
+
+func (x *trillianLogStreamLeavesServer) Send(m *StreamLeavesResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TrillianLog_AddLogRootCosignature_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddLogRootCosignatureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrillianLogServer).AddLogRootCosignature(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trillian.TrillianLog/AddLogRootCosignature",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrillianLogServer).AddLogRootCosignature(ctx, req.(*AddLogRootCosignatureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _TrillianLog_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "trillian.TrillianLog",
 	HandlerType: (*TrillianLogServer)(nil),
@@ -2548,6 +3331,10 @@ var _TrillianLog_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetInclusionProofByHash",
 			Handler:    _TrillianLog_GetInclusionProofByHash_Handler,
 		},
+		{
+			MethodName: "GetBatchInclusionProof",
+			Handler:    _TrillianLog_GetBatchInclusionProof_Handler,
+		},
 		{
 			MethodName: "GetConsistencyProof",
 			Handler:    _TrillianLog_GetConsistencyProof_Handler,
@@ -2584,11 +3371,25 @@ var _TrillianLog_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetLeavesByRange",
 			Handler:    _TrillianLog_GetLeavesByRange_Handler,
 		},
+		{
+			MethodName: "GetRangeInclusionProof",
+			Handler:    _TrillianLog_GetRangeInclusionProof_Handler,
+		},
 		{
 			MethodName: "GetLeavesByHash",
 			Handler:    _TrillianLog_GetLeavesByHash_Handler,
 		},
+		{
+			MethodName: "AddLogRootCosignature",
+			Handler:    _TrillianLog_AddLogRootCosignature_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamLeaves",
+			Handler:       _TrillianLog_StreamLeaves_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "trillian_log_api.proto",
 }
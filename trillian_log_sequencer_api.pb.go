@@ -8,6 +8,8 @@ import (
 	fmt "fmt"
 	proto "github.com/golang/protobuf/proto"
 	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
 	math "math"
 )
 
@@ -22,19 +24,480 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+type IntegrateBatchRequest struct {
+	LogId                int64    `protobuf:"varint,1,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *IntegrateBatchRequest) Reset()         { *m = IntegrateBatchRequest{} }
+func (m *IntegrateBatchRequest) String() string { return proto.CompactTextString(m) }
+func (*IntegrateBatchRequest) ProtoMessage()    {}
+func (*IntegrateBatchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f32c68ea33658ef4, []int{0}
+}
+
+func (m *IntegrateBatchRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IntegrateBatchRequest.Unmarshal(m, b)
+}
+func (m *IntegrateBatchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IntegrateBatchRequest.Marshal(b, m, deterministic)
+}
+func (m *IntegrateBatchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IntegrateBatchRequest.Merge(m, src)
+}
+func (m *IntegrateBatchRequest) XXX_Size() int {
+	return xxx_messageInfo_IntegrateBatchRequest.Size(m)
+}
+func (m *IntegrateBatchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_IntegrateBatchRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IntegrateBatchRequest proto.InternalMessageInfo
+
+func (m *IntegrateBatchRequest) GetLogId() int64 {
+	if m != nil {
+		return m.LogId
+	}
+	return 0
+}
+
+type IntegrateBatchResponse struct {
+	// Number of leaves integrated into the log.
+	IntegratedLeafCount  int64    `protobuf:"varint,1,opt,name=integrated_leaf_count,json=integratedLeafCount,proto3" json:"integrated_leaf_count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *IntegrateBatchResponse) Reset()         { *m = IntegrateBatchResponse{} }
+func (m *IntegrateBatchResponse) String() string { return proto.CompactTextString(m) }
+func (*IntegrateBatchResponse) ProtoMessage()    {}
+func (*IntegrateBatchResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f32c68ea33658ef4, []int{1}
+}
+
+func (m *IntegrateBatchResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IntegrateBatchResponse.Unmarshal(m, b)
+}
+func (m *IntegrateBatchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IntegrateBatchResponse.Marshal(b, m, deterministic)
+}
+func (m *IntegrateBatchResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IntegrateBatchResponse.Merge(m, src)
+}
+func (m *IntegrateBatchResponse) XXX_Size() int {
+	return xxx_messageInfo_IntegrateBatchResponse.Size(m)
+}
+func (m *IntegrateBatchResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_IntegrateBatchResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IntegrateBatchResponse proto.InternalMessageInfo
+
+func (m *IntegrateBatchResponse) GetIntegratedLeafCount() int64 {
+	if m != nil {
+		return m.IntegratedLeafCount
+	}
+	return 0
+}
+
+type GetMastershipRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetMastershipRequest) Reset()         { *m = GetMastershipRequest{} }
+func (m *GetMastershipRequest) String() string { return proto.CompactTextString(m) }
+func (*GetMastershipRequest) ProtoMessage()    {}
+func (*GetMastershipRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f32c68ea33658ef4, []int{2}
+}
+
+func (m *GetMastershipRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetMastershipRequest.Unmarshal(m, b)
+}
+func (m *GetMastershipRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetMastershipRequest.Marshal(b, m, deterministic)
+}
+func (m *GetMastershipRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetMastershipRequest.Merge(m, src)
+}
+func (m *GetMastershipRequest) XXX_Size() int {
+	return xxx_messageInfo_GetMastershipRequest.Size(m)
+}
+func (m *GetMastershipRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetMastershipRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetMastershipRequest proto.InternalMessageInfo
+
+// LogMastership describes the status of a single log on a signer.
+type LogMastership struct {
+	LogId int64 `protobuf:"varint,1,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
+	// Whether the signer is currently master for the log.
+	IsMaster bool `protobuf:"varint,2,opt,name=is_master,json=isMaster,proto3" json:"is_master,omitempty"`
+	// Whether sequencing of the log is paused on the signer.
+	Paused               bool     `protobuf:"varint,3,opt,name=paused,proto3" json:"paused,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogMastership) Reset()         { *m = LogMastership{} }
+func (m *LogMastership) String() string { return proto.CompactTextString(m) }
+func (*LogMastership) ProtoMessage()    {}
+func (*LogMastership) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f32c68ea33658ef4, []int{3}
+}
+
+func (m *LogMastership) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogMastership.Unmarshal(m, b)
+}
+func (m *LogMastership) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogMastership.Marshal(b, m, deterministic)
+}
+func (m *LogMastership) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogMastership.Merge(m, src)
+}
+func (m *LogMastership) XXX_Size() int {
+	return xxx_messageInfo_LogMastership.Size(m)
+}
+func (m *LogMastership) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogMastership.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogMastership proto.InternalMessageInfo
+
+func (m *LogMastership) GetLogId() int64 {
+	if m != nil {
+		return m.LogId
+	}
+	return 0
+}
+
+func (m *LogMastership) GetIsMaster() bool {
+	if m != nil {
+		return m.IsMaster
+	}
+	return false
+}
+
+func (m *LogMastership) GetPaused() bool {
+	if m != nil {
+		return m.Paused
+	}
+	return false
+}
+
+type GetMastershipResponse struct {
+	// Status of each log, ordered by log ID.
+	Logs                 []*LogMastership `protobuf:"bytes,1,rep,name=logs,proto3" json:"logs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *GetMastershipResponse) Reset()         { *m = GetMastershipResponse{} }
+func (m *GetMastershipResponse) String() string { return proto.CompactTextString(m) }
+func (*GetMastershipResponse) ProtoMessage()    {}
+func (*GetMastershipResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f32c68ea33658ef4, []int{4}
+}
+
+func (m *GetMastershipResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetMastershipResponse.Unmarshal(m, b)
+}
+func (m *GetMastershipResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetMastershipResponse.Marshal(b, m, deterministic)
+}
+func (m *GetMastershipResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetMastershipResponse.Merge(m, src)
+}
+func (m *GetMastershipResponse) XXX_Size() int {
+	return xxx_messageInfo_GetMastershipResponse.Size(m)
+}
+func (m *GetMastershipResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetMastershipResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetMastershipResponse proto.InternalMessageInfo
+
+func (m *GetMastershipResponse) GetLogs() []*LogMastership {
+	if m != nil {
+		return m.Logs
+	}
+	return nil
+}
+
+type GetQueueSizeRequest struct {
+	LogId                int64    `protobuf:"varint,1,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetQueueSizeRequest) Reset()         { *m = GetQueueSizeRequest{} }
+func (m *GetQueueSizeRequest) String() string { return proto.CompactTextString(m) }
+func (*GetQueueSizeRequest) ProtoMessage()    {}
+func (*GetQueueSizeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f32c68ea33658ef4, []int{5}
+}
+
+func (m *GetQueueSizeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetQueueSizeRequest.Unmarshal(m, b)
+}
+func (m *GetQueueSizeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetQueueSizeRequest.Marshal(b, m, deterministic)
+}
+func (m *GetQueueSizeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetQueueSizeRequest.Merge(m, src)
+}
+func (m *GetQueueSizeRequest) XXX_Size() int {
+	return xxx_messageInfo_GetQueueSizeRequest.Size(m)
+}
+func (m *GetQueueSizeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetQueueSizeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetQueueSizeRequest proto.InternalMessageInfo
+
+func (m *GetQueueSizeRequest) GetLogId() int64 {
+	if m != nil {
+		return m.LogId
+	}
+	return 0
+}
+
+type GetQueueSizeResponse struct {
+	// Number of leaves waiting to be sequenced.
+	QueueSize            int64    `protobuf:"varint,1,opt,name=queue_size,json=queueSize,proto3" json:"queue_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetQueueSizeResponse) Reset()         { *m = GetQueueSizeResponse{} }
+func (m *GetQueueSizeResponse) String() string { return proto.CompactTextString(m) }
+func (*GetQueueSizeResponse) ProtoMessage()    {}
+func (*GetQueueSizeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f32c68ea33658ef4, []int{6}
+}
+
+func (m *GetQueueSizeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetQueueSizeResponse.Unmarshal(m, b)
+}
+func (m *GetQueueSizeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetQueueSizeResponse.Marshal(b, m, deterministic)
+}
+func (m *GetQueueSizeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetQueueSizeResponse.Merge(m, src)
+}
+func (m *GetQueueSizeResponse) XXX_Size() int {
+	return xxx_messageInfo_GetQueueSizeResponse.Size(m)
+}
+func (m *GetQueueSizeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetQueueSizeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetQueueSizeResponse proto.InternalMessageInfo
+
+func (m *GetQueueSizeResponse) GetQueueSize() int64 {
+	if m != nil {
+		return m.QueueSize
+	}
+	return 0
+}
+
+type PauseSequencingRequest struct {
+	LogId                int64    `protobuf:"varint,1,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PauseSequencingRequest) Reset()         { *m = PauseSequencingRequest{} }
+func (m *PauseSequencingRequest) String() string { return proto.CompactTextString(m) }
+func (*PauseSequencingRequest) ProtoMessage()    {}
+func (*PauseSequencingRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f32c68ea33658ef4, []int{7}
+}
+
+func (m *PauseSequencingRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PauseSequencingRequest.Unmarshal(m, b)
+}
+func (m *PauseSequencingRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PauseSequencingRequest.Marshal(b, m, deterministic)
+}
+func (m *PauseSequencingRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PauseSequencingRequest.Merge(m, src)
+}
+func (m *PauseSequencingRequest) XXX_Size() int {
+	return xxx_messageInfo_PauseSequencingRequest.Size(m)
+}
+func (m *PauseSequencingRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PauseSequencingRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PauseSequencingRequest proto.InternalMessageInfo
+
+func (m *PauseSequencingRequest) GetLogId() int64 {
+	if m != nil {
+		return m.LogId
+	}
+	return 0
+}
+
+type PauseSequencingResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PauseSequencingResponse) Reset()         { *m = PauseSequencingResponse{} }
+func (m *PauseSequencingResponse) String() string { return proto.CompactTextString(m) }
+func (*PauseSequencingResponse) ProtoMessage()    {}
+func (*PauseSequencingResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f32c68ea33658ef4, []int{8}
+}
+
+func (m *PauseSequencingResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PauseSequencingResponse.Unmarshal(m, b)
+}
+func (m *PauseSequencingResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PauseSequencingResponse.Marshal(b, m, deterministic)
+}
+func (m *PauseSequencingResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PauseSequencingResponse.Merge(m, src)
+}
+func (m *PauseSequencingResponse) XXX_Size() int {
+	return xxx_messageInfo_PauseSequencingResponse.Size(m)
+}
+func (m *PauseSequencingResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PauseSequencingResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PauseSequencingResponse proto.InternalMessageInfo
+
+type ResumeSequencingRequest struct {
+	LogId                int64    `protobuf:"varint,1,opt,name=log_id,json=logId,proto3" json:"log_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResumeSequencingRequest) Reset()         { *m = ResumeSequencingRequest{} }
+func (m *ResumeSequencingRequest) String() string { return proto.CompactTextString(m) }
+func (*ResumeSequencingRequest) ProtoMessage()    {}
+func (*ResumeSequencingRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f32c68ea33658ef4, []int{9}
+}
+
+func (m *ResumeSequencingRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResumeSequencingRequest.Unmarshal(m, b)
+}
+func (m *ResumeSequencingRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResumeSequencingRequest.Marshal(b, m, deterministic)
+}
+func (m *ResumeSequencingRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResumeSequencingRequest.Merge(m, src)
+}
+func (m *ResumeSequencingRequest) XXX_Size() int {
+	return xxx_messageInfo_ResumeSequencingRequest.Size(m)
+}
+func (m *ResumeSequencingRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResumeSequencingRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResumeSequencingRequest proto.InternalMessageInfo
+
+func (m *ResumeSequencingRequest) GetLogId() int64 {
+	if m != nil {
+		return m.LogId
+	}
+	return 0
+}
+
+type ResumeSequencingResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResumeSequencingResponse) Reset()         { *m = ResumeSequencingResponse{} }
+func (m *ResumeSequencingResponse) String() string { return proto.CompactTextString(m) }
+func (*ResumeSequencingResponse) ProtoMessage()    {}
+func (*ResumeSequencingResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f32c68ea33658ef4, []int{10}
+}
+
+func (m *ResumeSequencingResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResumeSequencingResponse.Unmarshal(m, b)
+}
+func (m *ResumeSequencingResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResumeSequencingResponse.Marshal(b, m, deterministic)
+}
+func (m *ResumeSequencingResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResumeSequencingResponse.Merge(m, src)
+}
+func (m *ResumeSequencingResponse) XXX_Size() int {
+	return xxx_messageInfo_ResumeSequencingResponse.Size(m)
+}
+func (m *ResumeSequencingResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResumeSequencingResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResumeSequencingResponse proto.InternalMessageInfo
+
+func init() {
+	proto.RegisterType((*IntegrateBatchRequest)(nil), "trillian.IntegrateBatchRequest")
+	proto.RegisterType((*IntegrateBatchResponse)(nil), "trillian.IntegrateBatchResponse")
+	proto.RegisterType((*GetMastershipRequest)(nil), "trillian.GetMastershipRequest")
+	proto.RegisterType((*LogMastership)(nil), "trillian.LogMastership")
+	proto.RegisterType((*GetMastershipResponse)(nil), "trillian.GetMastershipResponse")
+	proto.RegisterType((*GetQueueSizeRequest)(nil), "trillian.GetQueueSizeRequest")
+	proto.RegisterType((*GetQueueSizeResponse)(nil), "trillian.GetQueueSizeResponse")
+	proto.RegisterType((*PauseSequencingRequest)(nil), "trillian.PauseSequencingRequest")
+	proto.RegisterType((*PauseSequencingResponse)(nil), "trillian.PauseSequencingResponse")
+	proto.RegisterType((*ResumeSequencingRequest)(nil), "trillian.ResumeSequencingRequest")
+	proto.RegisterType((*ResumeSequencingResponse)(nil), "trillian.ResumeSequencingResponse")
+}
+
 func init() { proto.RegisterFile("trillian_log_sequencer_api.proto", fileDescriptor_f32c68ea33658ef4) }
 
 var fileDescriptor_f32c68ea33658ef4 = []byte{
-	// 129 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x52, 0x28, 0x29, 0xca, 0xcc,
-	0xc9, 0xc9, 0x4c, 0xcc, 0x8b, 0xcf, 0xc9, 0x4f, 0x8f, 0x2f, 0x4e, 0x2d, 0x2c, 0x4d, 0xcd, 0x4b,
-	0x4e, 0x2d, 0x8a, 0x4f, 0x2c, 0xc8, 0xd4, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0x80, 0xa9,
-	0x30, 0x12, 0xe3, 0x12, 0x09, 0x81, 0xb2, 0x7d, 0xf2, 0xd3, 0x83, 0x61, 0x6a, 0x9d, 0xc2, 0xb9,
-	0x24, 0x93, 0xf3, 0x73, 0xf5, 0xd2, 0xf3, 0xf3, 0xd3, 0x73, 0x52, 0xf5, 0x60, 0xca, 0x21, 0xda,
-	0x9d, 0x64, 0xb0, 0x69, 0x71, 0x2c, 0xc8, 0x0c, 0x00, 0xc9, 0x06, 0x30, 0x46, 0x49, 0xa5, 0x67,
-	0x96, 0x64, 0x94, 0x26, 0xe9, 0x25, 0xe7, 0xe7, 0xea, 0x43, 0x4c, 0xd0, 0x87, 0x99, 0x90, 0xc4,
-	0x06, 0x36, 0xc2, 0x18, 0x10, 0x00, 0x00, 0xff, 0xff, 0x86, 0xa5, 0x47, 0x41, 0xa5, 0x00, 0x00,
-	0x00,
+	// 462 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x54, 0xcb, 0x6e, 0xd3, 0x40,
+	0x14, 0xc5, 0x04, 0xa2, 0xf4, 0x42, 0x01, 0x4d, 0xf3, 0x70, 0x0d, 0x6d, 0xcd, 0xac, 0x22, 0x81,
+	0x1c, 0x14, 0xc4, 0x07, 0x10, 0x90, 0xaa, 0x4a, 0x41, 0x04, 0x07, 0x04, 0xa2, 0x0b, 0xcb, 0x75,
+	0x6e, 0x27, 0x23, 0x39, 0x1e, 0xc7, 0x33, 0xde, 0xf4, 0x0f, 0xf9, 0x2b, 0xe4, 0x57, 0x1c, 0xbb,
+	0x76, 0xdb, 0xa5, 0xe7, 0x3c, 0xee, 0xe3, 0x5c, 0x19, 0x4c, 0x15, 0x71, 0xdf, 0xe7, 0x6e, 0xe0,
+	0xf8, 0x82, 0x39, 0x12, 0xb7, 0x31, 0x06, 0x1e, 0x46, 0x8e, 0x1b, 0x72, 0x2b, 0x8c, 0x84, 0x12,
+	0xa4, 0x57, 0x30, 0xa8, 0x05, 0x83, 0x8b, 0x40, 0x21, 0x8b, 0x5c, 0x85, 0x33, 0x57, 0x79, 0x6b,
+	0x3b, 0xa1, 0x4b, 0x45, 0x06, 0xd0, 0x4d, 0xd4, 0x7c, 0xa5, 0x6b, 0xa6, 0x36, 0xee, 0xd8, 0x4f,
+	0x7d, 0xc1, 0x2e, 0x56, 0x74, 0x0e, 0xc3, 0x3a, 0x5f, 0x86, 0x22, 0x90, 0x48, 0xa6, 0x30, 0xe0,
+	0x05, 0xb2, 0x72, 0x7c, 0x74, 0xaf, 0x1d, 0x4f, 0xc4, 0x81, 0xca, 0xf5, 0x47, 0x25, 0x38, 0x47,
+	0xf7, 0xfa, 0x4b, 0x02, 0xd1, 0x21, 0xf4, 0xcf, 0x51, 0x7d, 0x73, 0xa5, 0xc2, 0x48, 0xae, 0x79,
+	0x98, 0x17, 0xa7, 0x97, 0x70, 0x38, 0x17, 0xac, 0x7c, 0x6f, 0xe9, 0x86, 0xbc, 0x86, 0x03, 0x2e,
+	0x9d, 0x4d, 0xca, 0xd3, 0x1f, 0x9b, 0xda, 0xb8, 0x67, 0xf7, 0xb8, 0xcc, 0x74, 0x64, 0x08, 0xdd,
+	0xd0, 0x8d, 0x25, 0xae, 0xf4, 0x4e, 0x8a, 0xe4, 0x5f, 0xf4, 0x2b, 0x0c, 0x6a, 0x45, 0xf3, 0x09,
+	0xde, 0xc1, 0x13, 0x5f, 0x30, 0xa9, 0x6b, 0x66, 0x67, 0xfc, 0x6c, 0x3a, 0xb2, 0x8a, 0x25, 0x59,
+	0x95, 0x5e, 0xec, 0x94, 0x44, 0xdf, 0xc3, 0xd1, 0x39, 0xaa, 0x1f, 0x31, 0xc6, 0xb8, 0xe4, 0x37,
+	0x78, 0xcf, 0xda, 0x3e, 0x41, 0xbf, 0xca, 0xce, 0x4b, 0x9e, 0x00, 0x6c, 0x93, 0x47, 0x47, 0xf2,
+	0x1b, 0xcc, 0x25, 0x07, 0xdb, 0x82, 0x46, 0x27, 0x30, 0x5c, 0x24, 0x4d, 0x2f, 0xb3, 0x0c, 0x79,
+	0xc0, 0xee, 0xa9, 0x73, 0x0c, 0xa3, 0x5b, 0x82, 0xac, 0x14, 0xfd, 0x00, 0x23, 0x1b, 0x65, 0xbc,
+	0x79, 0xb8, 0x99, 0x01, 0xfa, 0x6d, 0x45, 0xe6, 0x36, 0xfd, 0xd7, 0x81, 0xfe, 0xcf, 0x7c, 0x3f,
+	0x73, 0xc1, 0x96, 0xc5, 0x91, 0x91, 0x5f, 0xf0, 0xa2, 0x7a, 0x20, 0xe4, 0xac, 0x5c, 0x64, 0xe3,
+	0xa9, 0x19, 0x66, 0x3b, 0x21, 0xef, 0xfd, 0x11, 0xb1, 0xe1, 0xb0, 0x12, 0x1a, 0x39, 0x2d, 0x45,
+	0x4d, 0x27, 0x64, 0x9c, 0xb5, 0xe2, 0x3b, 0xcf, 0xef, 0xf0, 0x7c, 0x3f, 0x14, 0x72, 0x52, 0x91,
+	0xd4, 0xa3, 0x35, 0x4e, 0xdb, 0xe0, 0x9d, 0xe1, 0x1f, 0x78, 0x59, 0xdb, 0x3e, 0xd9, 0x9b, 0xad,
+	0x39, 0x49, 0xe3, 0xed, 0x1d, 0x8c, 0x9d, 0xf3, 0x25, 0xbc, 0xaa, 0x47, 0x41, 0xf6, 0x84, 0x2d,
+	0xc1, 0x1a, 0xf4, 0x2e, 0x4a, 0x61, 0x3e, 0xfb, 0x0d, 0xc7, 0x9e, 0xd8, 0x58, 0x4c, 0x08, 0xe6,
+	0x63, 0xa9, 0x48, 0x7f, 0x15, 0xb3, 0x37, 0x4d, 0x29, 0x7f, 0x0e, 0xf9, 0x22, 0x41, 0x17, 0xda,
+	0x5f, 0x83, 0x71, 0xb5, 0x8e, 0xaf, 0x2c, 0x4f, 0x6c, 0x26, 0x99, 0xc3, 0xa4, 0x70, 0xb8, 0xea,
+	0xa6, 0x16, 0x1f, 0xff, 0x0f, 0x00, 0x3d, 0x85, 0x2e, 0x13, 0x91, 0x04, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -49,6 +512,26 @@ const _ = grpc.SupportPackageIsVersion4
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type TrillianLogSequencerClient interface {
+	// IntegrateBatch immediately integrates a batch of queued leaves into a log,
+	// rather than waiting for the next sequencing pass. Fails with
+	// FAILED_PRECONDITION if the signer is not master for the log, or if
+	// sequencing is paused for it.
+	IntegrateBatch(ctx context.Context, in *IntegrateBatchRequest, opts ...grpc.CallOption) (*IntegrateBatchResponse, error)
+	// GetMastership returns the mastership status of the signer for each log
+	// that it runs elections for.
+	GetMastership(ctx context.Context, in *GetMastershipRequest, opts ...grpc.CallOption) (*GetMastershipResponse, error)
+	// GetQueueSize returns the number of leaves queued for a log that are yet
+	// to be sequenced.
+	GetQueueSize(ctx context.Context, in *GetQueueSizeRequest, opts ...grpc.CallOption) (*GetQueueSizeResponse, error)
+	// PauseSequencing stops the signer from sequencing a log until
+	// ResumeSequencing is called. The signer keeps mastership for the log.
+	//
+	// Pauses are per signer instance, and are not persisted: a pause is lost
+	// when the signer restarts, and is ignored by any other signer that becomes
+	// master for the log. To stop sequencing a log durably, freeze its tree.
+	PauseSequencing(ctx context.Context, in *PauseSequencingRequest, opts ...grpc.CallOption) (*PauseSequencingResponse, error)
+	// ResumeSequencing undoes the effect of PauseSequencing for a log.
+	ResumeSequencing(ctx context.Context, in *ResumeSequencingRequest, opts ...grpc.CallOption) (*ResumeSequencingResponse, error)
 }
 
 type trillianLogSequencerClient struct {
@@ -59,22 +542,214 @@ func NewTrillianLogSequencerClient(cc *grpc.ClientConn) TrillianLogSequencerClie
 	return &trillianLogSequencerClient{cc}
 }
 
+func (c *trillianLogSequencerClient) IntegrateBatch(ctx context.Context, in *IntegrateBatchRequest, opts ...grpc.CallOption) (*IntegrateBatchResponse, error) {
+	out := new(IntegrateBatchResponse)
+	err := c.cc.Invoke(ctx, "/trillian.TrillianLogSequencer/IntegrateBatch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trillianLogSequencerClient) GetMastership(ctx context.Context, in *GetMastershipRequest, opts ...grpc.CallOption) (*GetMastershipResponse, error) {
+	out := new(GetMastershipResponse)
+	err := c.cc.Invoke(ctx, "/trillian.TrillianLogSequencer/GetMastership", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trillianLogSequencerClient) GetQueueSize(ctx context.Context, in *GetQueueSizeRequest, opts ...grpc.CallOption) (*GetQueueSizeResponse, error) {
+	out := new(GetQueueSizeResponse)
+	err := c.cc.Invoke(ctx, "/trillian.TrillianLogSequencer/GetQueueSize", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trillianLogSequencerClient) PauseSequencing(ctx context.Context, in *PauseSequencingRequest, opts ...grpc.CallOption) (*PauseSequencingResponse, error) {
+	out := new(PauseSequencingResponse)
+	err := c.cc.Invoke(ctx, "/trillian.TrillianLogSequencer/PauseSequencing", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trillianLogSequencerClient) ResumeSequencing(ctx context.Context, in *ResumeSequencingRequest, opts ...grpc.CallOption) (*ResumeSequencingResponse, error) {
+	out := new(ResumeSequencingResponse)
+	err := c.cc.Invoke(ctx, "/trillian.TrillianLogSequencer/ResumeSequencing", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // TrillianLogSequencerServer is the server API for TrillianLogSequencer service.
 type TrillianLogSequencerServer interface {
+	// IntegrateBatch immediately integrates a batch of queued leaves into a log,
+	// rather than waiting for the next sequencing pass. Fails with
+	// FAILED_PRECONDITION if the signer is not master for the log, or if
+	// sequencing is paused for it.
+	IntegrateBatch(context.Context, *IntegrateBatchRequest) (*IntegrateBatchResponse, error)
+	// GetMastership returns the mastership status of the signer for each log
+	// that it runs elections for.
+	GetMastership(context.Context, *GetMastershipRequest) (*GetMastershipResponse, error)
+	// GetQueueSize returns the number of leaves queued for a log that are yet
+	// to be sequenced.
+	GetQueueSize(context.Context, *GetQueueSizeRequest) (*GetQueueSizeResponse, error)
+	// PauseSequencing stops the signer from sequencing a log until
+	// ResumeSequencing is called. The signer keeps mastership for the log.
+	//
+	// Pauses are per signer instance, and are not persisted: a pause is lost
+	// when the signer restarts, and is ignored by any other signer that becomes
+	// master for the log. To stop sequencing a log durably, freeze its tree.
+	PauseSequencing(context.Context, *PauseSequencingRequest) (*PauseSequencingResponse, error)
+	// ResumeSequencing undoes the effect of PauseSequencing for a log.
+	ResumeSequencing(context.Context, *ResumeSequencingRequest) (*ResumeSequencingResponse, error)
 }
 
 // UnimplementedTrillianLogSequencerServer can be embedded to have forward compatible implementations.
 type UnimplementedTrillianLogSequencerServer struct {
 }
 
+func (*UnimplementedTrillianLogSequencerServer) IntegrateBatch(ctx context.Context, req *IntegrateBatchRequest) (*IntegrateBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IntegrateBatch not implemented")
+}
+func (*UnimplementedTrillianLogSequencerServer) GetMastership(ctx context.Context, req *GetMastershipRequest) (*GetMastershipResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMastership not implemented")
+}
+func (*UnimplementedTrillianLogSequencerServer) GetQueueSize(ctx context.Context, req *GetQueueSizeRequest) (*GetQueueSizeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQueueSize not implemented")
+}
+func (*UnimplementedTrillianLogSequencerServer) PauseSequencing(ctx context.Context, req *PauseSequencingRequest) (*PauseSequencingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseSequencing not implemented")
+}
+func (*UnimplementedTrillianLogSequencerServer) ResumeSequencing(ctx context.Context, req *ResumeSequencingRequest) (*ResumeSequencingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeSequencing not implemented")
+}
+
 func RegisterTrillianLogSequencerServer(s *grpc.Server, srv TrillianLogSequencerServer) {
 	s.RegisterService(&_TrillianLogSequencer_serviceDesc, srv)
 }
 
+func _TrillianLogSequencer_IntegrateBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IntegrateBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrillianLogSequencerServer).IntegrateBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trillian.TrillianLogSequencer/IntegrateBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrillianLogSequencerServer).IntegrateBatch(ctx, req.(*IntegrateBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrillianLogSequencer_GetMastership_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMastershipRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrillianLogSequencerServer).GetMastership(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trillian.TrillianLogSequencer/GetMastership",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrillianLogSequencerServer).GetMastership(ctx, req.(*GetMastershipRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrillianLogSequencer_GetQueueSize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetQueueSizeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrillianLogSequencerServer).GetQueueSize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trillian.TrillianLogSequencer/GetQueueSize",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrillianLogSequencerServer).GetQueueSize(ctx, req.(*GetQueueSizeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrillianLogSequencer_PauseSequencing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseSequencingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrillianLogSequencerServer).PauseSequencing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trillian.TrillianLogSequencer/PauseSequencing",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrillianLogSequencerServer).PauseSequencing(ctx, req.(*PauseSequencingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrillianLogSequencer_ResumeSequencing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeSequencingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrillianLogSequencerServer).ResumeSequencing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trillian.TrillianLogSequencer/ResumeSequencing",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrillianLogSequencerServer).ResumeSequencing(ctx, req.(*ResumeSequencingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _TrillianLogSequencer_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "trillian.TrillianLogSequencer",
 	HandlerType: (*TrillianLogSequencerServer)(nil),
-	Methods:     []grpc.MethodDesc{},
-	Streams:     []grpc.StreamDesc{},
-	Metadata:    "trillian_log_sequencer_api.proto",
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "IntegrateBatch",
+			Handler:    _TrillianLogSequencer_IntegrateBatch_Handler,
+		},
+		{
+			MethodName: "GetMastership",
+			Handler:    _TrillianLogSequencer_GetMastership_Handler,
+		},
+		{
+			MethodName: "GetQueueSize",
+			Handler:    _TrillianLogSequencer_GetQueueSize_Handler,
+		},
+		{
+			MethodName: "PauseSequencing",
+			Handler:    _TrillianLogSequencer_PauseSequencing_Handler,
+		},
+		{
+			MethodName: "ResumeSequencing",
+			Handler:    _TrillianLogSequencer_ResumeSequencing_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "trillian_log_sequencer_api.proto",
 }
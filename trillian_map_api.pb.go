@@ -199,8 +199,11 @@ func (m *MapLeafInclusion) GetInclusion() [][]byte {
 }
 
 type GetMapLeavesRequest struct {
-	MapId                int64    `protobuf:"varint,1,opt,name=map_id,json=mapId,proto3" json:"map_id,omitempty"`
-	Index                [][]byte `protobuf:"bytes,2,rep,name=index,proto3" json:"index,omitempty"`
+	MapId int64    `protobuf:"varint,1,opt,name=map_id,json=mapId,proto3" json:"map_id,omitempty"`
+	Index [][]byte `protobuf:"bytes,2,rep,name=index,proto3" json:"index,omitempty"`
+	// compact_proof requests a single batch_inclusion proof for all of the
+	// leaves in the response, instead of a full inclusion proof per leaf.
+	CompactProof         bool     `protobuf:"varint,4,opt,name=compact_proof,json=compactProof,proto3" json:"compact_proof,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -245,6 +248,13 @@ func (m *GetMapLeavesRequest) GetIndex() [][]byte {
 	return nil
 }
 
+func (m *GetMapLeavesRequest) GetCompactProof() bool {
+	if m != nil {
+		return m.CompactProof
+	}
+	return false
+}
+
 type GetMapLeafRequest struct {
 	MapId                int64    `protobuf:"varint,1,opt,name=map_id,json=mapId,proto3" json:"map_id,omitempty"`
 	Index                []byte   `protobuf:"bytes,2,opt,name=index,proto3" json:"index,omitempty"`
@@ -353,7 +363,10 @@ type GetMapLeavesByRevisionRequest struct {
 	MapId int64    `protobuf:"varint,1,opt,name=map_id,json=mapId,proto3" json:"map_id,omitempty"`
 	Index [][]byte `protobuf:"bytes,2,rep,name=index,proto3" json:"index,omitempty"`
 	// revision >= 0.
-	Revision             int64    `protobuf:"varint,3,opt,name=revision,proto3" json:"revision,omitempty"`
+	Revision int64 `protobuf:"varint,3,opt,name=revision,proto3" json:"revision,omitempty"`
+	// compact_proof requests a single batch_inclusion proof for all of the
+	// leaves in the response, instead of a full inclusion proof per leaf.
+	CompactProof         bool     `protobuf:"varint,4,opt,name=compact_proof,json=compactProof,proto3" json:"compact_proof,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -405,6 +418,13 @@ func (m *GetMapLeavesByRevisionRequest) GetRevision() int64 {
 	return 0
 }
 
+func (m *GetMapLeavesByRevisionRequest) GetCompactProof() bool {
+	if m != nil {
+		return m.CompactProof
+	}
+	return false
+}
+
 type GetMapLeafResponse struct {
 	MapLeafInclusion     *MapLeafInclusion `protobuf:"bytes,1,opt,name=map_leaf_inclusion,json=mapLeafInclusion,proto3" json:"map_leaf_inclusion,omitempty"`
 	MapRoot              *SignedMapRoot    `protobuf:"bytes,2,opt,name=map_root,json=mapRoot,proto3" json:"map_root,omitempty"`
@@ -453,11 +473,17 @@ func (m *GetMapLeafResponse) GetMapRoot() *SignedMapRoot {
 }
 
 type GetMapLeavesResponse struct {
-	MapLeafInclusion     []*MapLeafInclusion `protobuf:"bytes,2,rep,name=map_leaf_inclusion,json=mapLeafInclusion,proto3" json:"map_leaf_inclusion,omitempty"`
-	MapRoot              *SignedMapRoot      `protobuf:"bytes,3,opt,name=map_root,json=mapRoot,proto3" json:"map_root,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
-	XXX_unrecognized     []byte              `json:"-"`
-	XXX_sizecache        int32               `json:"-"`
+	MapLeafInclusion []*MapLeafInclusion `protobuf:"bytes,2,rep,name=map_leaf_inclusion,json=mapLeafInclusion,proto3" json:"map_leaf_inclusion,omitempty"`
+	MapRoot          *SignedMapRoot      `protobuf:"bytes,3,opt,name=map_root,json=mapRoot,proto3" json:"map_root,omitempty"`
+	// batch_inclusion is set instead of the per-leaf inclusion proofs when a
+	// compact proof was requested. It holds each sibling hash needed to verify
+	// all of the leaves together exactly once, ordered from the leaves up to the
+	// root and, within a level, by increasing index. Empty entries stand for
+	// empty subtrees.
+	BatchInclusion       [][]byte `protobuf:"bytes,4,rep,name=batch_inclusion,json=batchInclusion,proto3" json:"batch_inclusion,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *GetMapLeavesResponse) Reset()         { *m = GetMapLeavesResponse{} }
@@ -499,6 +525,13 @@ func (m *GetMapLeavesResponse) GetMapRoot() *SignedMapRoot {
 	return nil
 }
 
+func (m *GetMapLeavesResponse) GetBatchInclusion() [][]byte {
+	if m != nil {
+		return m.BatchInclusion
+	}
+	return nil
+}
+
 // GetLastInRangeByRevisionRequest specifies a range in the map at a revision.
 // The range is defined as the entire subtree below a particular point in the
 // Merkle tree. Another way of saying this is that the range matches all leaves
@@ -568,6 +601,312 @@ func (m *GetLastInRangeByRevisionRequest) GetPrefixBits() int32 {
 	return 0
 }
 
+// ListMapLeavesRequest specifies a page of the leaves of a map at a revision.
+type ListMapLeavesRequest struct {
+	MapId int64 `protobuf:"varint,1,opt,name=map_id,json=mapId,proto3" json:"map_id,omitempty"`
+	// revision >= 0.
+	Revision int64 `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	// start_index is the index of the first leaf to return. Leaves are returned
+	// in increasing index order, so an empty start_index lists from the first
+	// leaf of the map.
+	StartIndex []byte `protobuf:"bytes,3,opt,name=start_index,json=startIndex,proto3" json:"start_index,omitempty"`
+	// page_size is the maximum number of leaves to return. If zero, the server
+	// picks a default. The server may return fewer leaves than requested.
+	PageSize             int32    `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListMapLeavesRequest) Reset()         { *m = ListMapLeavesRequest{} }
+func (m *ListMapLeavesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListMapLeavesRequest) ProtoMessage()    {}
+func (*ListMapLeavesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_28d34dfba22a7ce2, []int{10}
+}
+
+func (m *ListMapLeavesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListMapLeavesRequest.Unmarshal(m, b)
+}
+func (m *ListMapLeavesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListMapLeavesRequest.Marshal(b, m, deterministic)
+}
+func (m *ListMapLeavesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListMapLeavesRequest.Merge(m, src)
+}
+func (m *ListMapLeavesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListMapLeavesRequest.Size(m)
+}
+func (m *ListMapLeavesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListMapLeavesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListMapLeavesRequest proto.InternalMessageInfo
+
+func (m *ListMapLeavesRequest) GetMapId() int64 {
+	if m != nil {
+		return m.MapId
+	}
+	return 0
+}
+
+func (m *ListMapLeavesRequest) GetRevision() int64 {
+	if m != nil {
+		return m.Revision
+	}
+	return 0
+}
+
+func (m *ListMapLeavesRequest) GetStartIndex() []byte {
+	if m != nil {
+		return m.StartIndex
+	}
+	return nil
+}
+
+func (m *ListMapLeavesRequest) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+type ListMapLeavesResponse struct {
+	// leaves holds the non-empty leaves of the map in increasing index order.
+	Leaves []*MapLeaf `protobuf:"bytes,1,rep,name=leaves,proto3" json:"leaves,omitempty"`
+	// next_index is the start_index to use to request the next page of leaves,
+	// or empty if there are no more leaves.
+	NextIndex []byte `protobuf:"bytes,2,opt,name=next_index,json=nextIndex,proto3" json:"next_index,omitempty"`
+	// map_root is the root of the map at the requested revision, which can be
+	// recomputed from the full set of leaves.
+	MapRoot              *SignedMapRoot `protobuf:"bytes,3,opt,name=map_root,json=mapRoot,proto3" json:"map_root,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *ListMapLeavesResponse) Reset()         { *m = ListMapLeavesResponse{} }
+func (m *ListMapLeavesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListMapLeavesResponse) ProtoMessage()    {}
+func (*ListMapLeavesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_28d34dfba22a7ce2, []int{11}
+}
+
+func (m *ListMapLeavesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListMapLeavesResponse.Unmarshal(m, b)
+}
+func (m *ListMapLeavesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListMapLeavesResponse.Marshal(b, m, deterministic)
+}
+func (m *ListMapLeavesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListMapLeavesResponse.Merge(m, src)
+}
+func (m *ListMapLeavesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListMapLeavesResponse.Size(m)
+}
+func (m *ListMapLeavesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListMapLeavesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListMapLeavesResponse proto.InternalMessageInfo
+
+func (m *ListMapLeavesResponse) GetLeaves() []*MapLeaf {
+	if m != nil {
+		return m.Leaves
+	}
+	return nil
+}
+
+func (m *ListMapLeavesResponse) GetNextIndex() []byte {
+	if m != nil {
+		return m.NextIndex
+	}
+	return nil
+}
+
+func (m *ListMapLeavesResponse) GetMapRoot() *SignedMapRoot {
+	if m != nil {
+		return m.MapRoot
+	}
+	return nil
+}
+
+// GetMapDiffRequest specifies two revisions of a map to compare.
+type GetMapDiffRequest struct {
+	MapId int64 `protobuf:"varint,1,opt,name=map_id,json=mapId,proto3" json:"map_id,omitempty"`
+	// from_revision >= 0.
+	FromRevision int64 `protobuf:"varint,2,opt,name=from_revision,json=fromRevision,proto3" json:"from_revision,omitempty"`
+	// to_revision > from_revision.
+	ToRevision           int64    `protobuf:"varint,3,opt,name=to_revision,json=toRevision,proto3" json:"to_revision,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetMapDiffRequest) Reset()         { *m = GetMapDiffRequest{} }
+func (m *GetMapDiffRequest) String() string { return proto.CompactTextString(m) }
+func (*GetMapDiffRequest) ProtoMessage()    {}
+func (*GetMapDiffRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_28d34dfba22a7ce2, []int{12}
+}
+
+func (m *GetMapDiffRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetMapDiffRequest.Unmarshal(m, b)
+}
+func (m *GetMapDiffRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetMapDiffRequest.Marshal(b, m, deterministic)
+}
+func (m *GetMapDiffRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetMapDiffRequest.Merge(m, src)
+}
+func (m *GetMapDiffRequest) XXX_Size() int {
+	return xxx_messageInfo_GetMapDiffRequest.Size(m)
+}
+func (m *GetMapDiffRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetMapDiffRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetMapDiffRequest proto.InternalMessageInfo
+
+func (m *GetMapDiffRequest) GetMapId() int64 {
+	if m != nil {
+		return m.MapId
+	}
+	return 0
+}
+
+func (m *GetMapDiffRequest) GetFromRevision() int64 {
+	if m != nil {
+		return m.FromRevision
+	}
+	return 0
+}
+
+func (m *GetMapDiffRequest) GetToRevision() int64 {
+	if m != nil {
+		return m.ToRevision
+	}
+	return 0
+}
+
+// MapLeafDiff holds a leaf whose value changed between two map revisions.
+type MapLeafDiff struct {
+	Index []byte `protobuf:"bytes,1,opt,name=index,proto3" json:"index,omitempty"`
+	// old_leaf is the leaf at from_revision, with its inclusion proof in the
+	// from_root. Its leaf_value is empty if the leaf had not been set.
+	OldLeaf *MapLeafInclusion `protobuf:"bytes,2,opt,name=old_leaf,json=oldLeaf,proto3" json:"old_leaf,omitempty"`
+	// new_leaf is the leaf at to_revision, with its inclusion proof in the
+	// to_root.
+	NewLeaf              *MapLeafInclusion `protobuf:"bytes,3,opt,name=new_leaf,json=newLeaf,proto3" json:"new_leaf,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *MapLeafDiff) Reset()         { *m = MapLeafDiff{} }
+func (m *MapLeafDiff) String() string { return proto.CompactTextString(m) }
+func (*MapLeafDiff) ProtoMessage()    {}
+func (*MapLeafDiff) Descriptor() ([]byte, []int) {
+	return fileDescriptor_28d34dfba22a7ce2, []int{13}
+}
+
+func (m *MapLeafDiff) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MapLeafDiff.Unmarshal(m, b)
+}
+func (m *MapLeafDiff) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MapLeafDiff.Marshal(b, m, deterministic)
+}
+func (m *MapLeafDiff) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MapLeafDiff.Merge(m, src)
+}
+func (m *MapLeafDiff) XXX_Size() int {
+	return xxx_messageInfo_MapLeafDiff.Size(m)
+}
+func (m *MapLeafDiff) XXX_DiscardUnknown() {
+	xxx_messageInfo_MapLeafDiff.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MapLeafDiff proto.InternalMessageInfo
+
+func (m *MapLeafDiff) GetIndex() []byte {
+	if m != nil {
+		return m.Index
+	}
+	return nil
+}
+
+func (m *MapLeafDiff) GetOldLeaf() *MapLeafInclusion {
+	if m != nil {
+		return m.OldLeaf
+	}
+	return nil
+}
+
+func (m *MapLeafDiff) GetNewLeaf() *MapLeafInclusion {
+	if m != nil {
+		return m.NewLeaf
+	}
+	return nil
+}
+
+type GetMapDiffResponse struct {
+	// from_root and to_root are the roots of the map at the requested
+	// revisions. They are only set in the first response of the stream.
+	FromRoot *SignedMapRoot `protobuf:"bytes,1,opt,name=from_root,json=fromRoot,proto3" json:"from_root,omitempty"`
+	ToRoot   *SignedMapRoot `protobuf:"bytes,2,opt,name=to_root,json=toRoot,proto3" json:"to_root,omitempty"`
+	// diffs holds the changed leaves, in increasing index order.
+	Diffs                []*MapLeafDiff `protobuf:"bytes,3,rep,name=diffs,proto3" json:"diffs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *GetMapDiffResponse) Reset()         { *m = GetMapDiffResponse{} }
+func (m *GetMapDiffResponse) String() string { return proto.CompactTextString(m) }
+func (*GetMapDiffResponse) ProtoMessage()    {}
+func (*GetMapDiffResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_28d34dfba22a7ce2, []int{14}
+}
+
+func (m *GetMapDiffResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetMapDiffResponse.Unmarshal(m, b)
+}
+func (m *GetMapDiffResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetMapDiffResponse.Marshal(b, m, deterministic)
+}
+func (m *GetMapDiffResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetMapDiffResponse.Merge(m, src)
+}
+func (m *GetMapDiffResponse) XXX_Size() int {
+	return xxx_messageInfo_GetMapDiffResponse.Size(m)
+}
+func (m *GetMapDiffResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetMapDiffResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetMapDiffResponse proto.InternalMessageInfo
+
+func (m *GetMapDiffResponse) GetFromRoot() *SignedMapRoot {
+	if m != nil {
+		return m.FromRoot
+	}
+	return nil
+}
+
+func (m *GetMapDiffResponse) GetToRoot() *SignedMapRoot {
+	if m != nil {
+		return m.ToRoot
+	}
+	return nil
+}
+
+func (m *GetMapDiffResponse) GetDiffs() []*MapLeafDiff {
+	if m != nil {
+		return m.Diffs
+	}
+	return nil
+}
+
 type SetMapLeavesRequest struct {
 	MapId int64 `protobuf:"varint,1,opt,name=map_id,json=mapId,proto3" json:"map_id,omitempty"`
 	// The leaves being set must have unique Index values within the request.
@@ -587,7 +926,7 @@ func (m *SetMapLeavesRequest) Reset()         { *m = SetMapLeavesRequest{} }
 func (m *SetMapLeavesRequest) String() string { return proto.CompactTextString(m) }
 func (*SetMapLeavesRequest) ProtoMessage()    {}
 func (*SetMapLeavesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_28d34dfba22a7ce2, []int{10}
+	return fileDescriptor_28d34dfba22a7ce2, []int{15}
 }
 
 func (m *SetMapLeavesRequest) XXX_Unmarshal(b []byte) error {
@@ -647,7 +986,7 @@ func (m *SetMapLeavesResponse) Reset()         { *m = SetMapLeavesResponse{} }
 func (m *SetMapLeavesResponse) String() string { return proto.CompactTextString(m) }
 func (*SetMapLeavesResponse) ProtoMessage()    {}
 func (*SetMapLeavesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_28d34dfba22a7ce2, []int{11}
+	return fileDescriptor_28d34dfba22a7ce2, []int{16}
 }
 
 func (m *SetMapLeavesResponse) XXX_Unmarshal(b []byte) error {
@@ -699,7 +1038,7 @@ func (m *WriteMapLeavesRequest) Reset()         { *m = WriteMapLeavesRequest{} }
 func (m *WriteMapLeavesRequest) String() string { return proto.CompactTextString(m) }
 func (*WriteMapLeavesRequest) ProtoMessage()    {}
 func (*WriteMapLeavesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_28d34dfba22a7ce2, []int{12}
+	return fileDescriptor_28d34dfba22a7ce2, []int{17}
 }
 
 func (m *WriteMapLeavesRequest) XXX_Unmarshal(b []byte) error {
@@ -762,7 +1101,7 @@ func (m *WriteMapLeavesResponse) Reset()         { *m = WriteMapLeavesResponse{}
 func (m *WriteMapLeavesResponse) String() string { return proto.CompactTextString(m) }
 func (*WriteMapLeavesResponse) ProtoMessage()    {}
 func (*WriteMapLeavesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_28d34dfba22a7ce2, []int{13}
+	return fileDescriptor_28d34dfba22a7ce2, []int{18}
 }
 
 func (m *WriteMapLeavesResponse) XXX_Unmarshal(b []byte) error {
@@ -801,7 +1140,7 @@ func (m *GetSignedMapRootRequest) Reset()         { *m = GetSignedMapRootRequest
 func (m *GetSignedMapRootRequest) String() string { return proto.CompactTextString(m) }
 func (*GetSignedMapRootRequest) ProtoMessage()    {}
 func (*GetSignedMapRootRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_28d34dfba22a7ce2, []int{14}
+	return fileDescriptor_28d34dfba22a7ce2, []int{19}
 }
 
 func (m *GetSignedMapRootRequest) XXX_Unmarshal(b []byte) error {
@@ -841,7 +1180,7 @@ func (m *GetSignedMapRootByRevisionRequest) Reset()         { *m = GetSignedMapR
 func (m *GetSignedMapRootByRevisionRequest) String() string { return proto.CompactTextString(m) }
 func (*GetSignedMapRootByRevisionRequest) ProtoMessage()    {}
 func (*GetSignedMapRootByRevisionRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_28d34dfba22a7ce2, []int{15}
+	return fileDescriptor_28d34dfba22a7ce2, []int{20}
 }
 
 func (m *GetSignedMapRootByRevisionRequest) XXX_Unmarshal(b []byte) error {
@@ -887,7 +1226,7 @@ func (m *GetSignedMapRootResponse) Reset()         { *m = GetSignedMapRootRespon
 func (m *GetSignedMapRootResponse) String() string { return proto.CompactTextString(m) }
 func (*GetSignedMapRootResponse) ProtoMessage()    {}
 func (*GetSignedMapRootResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_28d34dfba22a7ce2, []int{16}
+	return fileDescriptor_28d34dfba22a7ce2, []int{21}
 }
 
 func (m *GetSignedMapRootResponse) XXX_Unmarshal(b []byte) error {
@@ -926,7 +1265,7 @@ func (m *InitMapRequest) Reset()         { *m = InitMapRequest{} }
 func (m *InitMapRequest) String() string { return proto.CompactTextString(m) }
 func (*InitMapRequest) ProtoMessage()    {}
 func (*InitMapRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_28d34dfba22a7ce2, []int{17}
+	return fileDescriptor_28d34dfba22a7ce2, []int{22}
 }
 
 func (m *InitMapRequest) XXX_Unmarshal(b []byte) error {
@@ -965,7 +1304,7 @@ func (m *InitMapResponse) Reset()         { *m = InitMapResponse{} }
 func (m *InitMapResponse) String() string { return proto.CompactTextString(m) }
 func (*InitMapResponse) ProtoMessage()    {}
 func (*InitMapResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_28d34dfba22a7ce2, []int{18}
+	return fileDescriptor_28d34dfba22a7ce2, []int{23}
 }
 
 func (m *InitMapResponse) XXX_Unmarshal(b []byte) error {
@@ -1004,6 +1343,11 @@ func init() {
 	proto.RegisterType((*GetMapLeafResponse)(nil), "trillian.GetMapLeafResponse")
 	proto.RegisterType((*GetMapLeavesResponse)(nil), "trillian.GetMapLeavesResponse")
 	proto.RegisterType((*GetLastInRangeByRevisionRequest)(nil), "trillian.GetLastInRangeByRevisionRequest")
+	proto.RegisterType((*ListMapLeavesRequest)(nil), "trillian.ListMapLeavesRequest")
+	proto.RegisterType((*ListMapLeavesResponse)(nil), "trillian.ListMapLeavesResponse")
+	proto.RegisterType((*GetMapDiffRequest)(nil), "trillian.GetMapDiffRequest")
+	proto.RegisterType((*MapLeafDiff)(nil), "trillian.MapLeafDiff")
+	proto.RegisterType((*GetMapDiffResponse)(nil), "trillian.GetMapDiffResponse")
 	proto.RegisterType((*SetMapLeavesRequest)(nil), "trillian.SetMapLeavesRequest")
 	proto.RegisterType((*SetMapLeavesResponse)(nil), "trillian.SetMapLeavesResponse")
 	proto.RegisterType((*WriteMapLeavesRequest)(nil), "trillian.WriteMapLeavesRequest")
@@ -1018,69 +1362,86 @@ func init() {
 func init() { proto.RegisterFile("trillian_map_api.proto", fileDescriptor_28d34dfba22a7ce2) }
 
 var fileDescriptor_28d34dfba22a7ce2 = []byte{
-	// 981 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x57, 0xdd, 0x6e, 0x1b, 0x45,
-	0x14, 0xee, 0x7a, 0xfd, 0x97, 0x63, 0x94, 0xba, 0x93, 0x34, 0x75, 0x37, 0x09, 0x49, 0xa7, 0x8a,
-	0xd2, 0xa8, 0x92, 0x97, 0x18, 0xc4, 0x45, 0x84, 0x10, 0x44, 0x11, 0xf9, 0x51, 0x12, 0x45, 0x6b,
-	0x68, 0xa5, 0x0a, 0xc9, 0x4c, 0xec, 0xb1, 0x3d, 0xd2, 0x7a, 0x67, 0xd9, 0x9d, 0x44, 0x81, 0xaa,
-	0x37, 0x5c, 0x70, 0xc7, 0x0d, 0x70, 0x87, 0x94, 0x37, 0xe9, 0x53, 0xf0, 0x0a, 0x3c, 0x08, 0x9a,
-	0x99, 0xf5, 0xae, 0xd7, 0x5e, 0x3b, 0x56, 0x0a, 0x77, 0x3b, 0xe7, 0xff, 0x9b, 0xef, 0x9c, 0x33,
-	0x36, 0xac, 0x88, 0x80, 0xb9, 0x2e, 0x23, 0x5e, 0x6b, 0x40, 0xfc, 0x16, 0xf1, 0x59, 0xdd, 0x0f,
-	0xb8, 0xe0, 0xa8, 0x3c, 0x94, 0x5b, 0x8b, 0xc3, 0x2f, 0xad, 0xb1, 0xd6, 0x7a, 0x9c, 0xf7, 0x5c,
-	0x6a, 0x13, 0x9f, 0xd9, 0xc4, 0xf3, 0xb8, 0x20, 0x82, 0x71, 0x2f, 0xd4, 0x5a, 0xfc, 0x33, 0x94,
-	0xce, 0x88, 0x7f, 0x4a, 0x49, 0x17, 0x2d, 0x43, 0x81, 0x79, 0x1d, 0x7a, 0x53, 0x33, 0x36, 0x8d,
-	0x17, 0x1f, 0x39, 0xfa, 0x80, 0x56, 0x61, 0xc1, 0xa5, 0xa4, 0xdb, 0xea, 0x93, 0xb0, 0x5f, 0xcb,
-	0x29, 0x4d, 0x59, 0x0a, 0x8e, 0x48, 0xd8, 0x47, 0xeb, 0x00, 0x4a, 0x79, 0x4d, 0xdc, 0x2b, 0x5a,
-	0x33, 0x95, 0x56, 0x99, 0xbf, 0x92, 0x02, 0xa9, 0xa6, 0x37, 0x22, 0x20, 0xad, 0x0e, 0x11, 0xa4,
-	0x96, 0xd7, 0x6a, 0x25, 0x39, 0x20, 0x82, 0xe0, 0xcf, 0x61, 0x41, 0xe7, 0xbe, 0xa6, 0x21, 0xda,
-	0x81, 0xa2, 0xab, 0xbe, 0x6a, 0xc6, 0xa6, 0xf9, 0xa2, 0xd2, 0x78, 0x54, 0x8f, 0x71, 0x44, 0x05,
-	0x3a, 0x91, 0x01, 0x7e, 0x0d, 0xd5, 0x48, 0x74, 0xec, 0xb5, 0xdd, 0xab, 0x90, 0x71, 0x0f, 0x6d,
-	0x41, 0x5e, 0xe6, 0x55, 0xb5, 0x67, 0x3a, 0x2b, 0x35, 0x5a, 0x83, 0x05, 0x36, 0xf4, 0xa9, 0xe5,
-	0x36, 0x4d, 0x59, 0x50, 0x2c, 0xc0, 0x47, 0xb0, 0x74, 0x48, 0x45, 0x5c, 0x93, 0x43, 0x7f, 0xbc,
-	0xa2, 0xa1, 0x40, 0x8f, 0xa1, 0x28, 0x2f, 0x9b, 0x75, 0x54, 0x74, 0xd3, 0x29, 0x0c, 0x88, 0x7f,
-	0xdc, 0x49, 0xee, 0x4b, 0xc7, 0xd1, 0x87, 0x93, 0x7c, 0xd9, 0xac, 0xe6, 0xf1, 0x57, 0xf0, 0x28,
-	0x8e, 0xd4, 0x9d, 0x3f, 0x4e, 0x72, 0xef, 0xb8, 0x0b, 0xab, 0x49, 0x84, 0xfd, 0x9f, 0x1c, 0x7a,
-	0xcd, 0x64, 0x8d, 0xf7, 0x89, 0x85, 0x2c, 0x28, 0x07, 0x91, 0xbf, 0x22, 0xc9, 0x74, 0xe2, 0x33,
-	0xee, 0xc3, 0xfa, 0x28, 0xe6, 0xfb, 0x64, 0x32, 0xe7, 0xcb, 0xf4, 0xbb, 0x01, 0x68, 0xf4, 0x52,
-	0x42, 0x9f, 0x7b, 0x21, 0x45, 0x47, 0x80, 0x64, 0x7c, 0xd5, 0x47, 0x09, 0x37, 0x9a, 0x47, 0x6b,
-	0x82, 0xc7, 0x98, 0x71, 0xa7, 0x3a, 0x18, 0xef, 0x81, 0x06, 0x94, 0x65, 0xa4, 0x80, 0x73, 0xa1,
-	0xf0, 0x57, 0x1a, 0x4f, 0x12, 0xff, 0x26, 0xeb, 0x79, 0xb4, 0x73, 0x46, 0x7c, 0x87, 0x73, 0xe1,
-	0x94, 0x06, 0xfa, 0x03, 0xff, 0x69, 0xc0, 0x72, 0x9a, 0xf3, 0x99, 0x65, 0xe5, 0x54, 0x6f, 0xde,
-	0xbf, 0x2c, 0x73, 0xce, 0xb2, 0x7e, 0x33, 0x60, 0xe3, 0x90, 0x8a, 0x53, 0x12, 0x8a, 0x63, 0xcf,
-	0x21, 0x5e, 0x8f, 0xce, 0x4d, 0xcc, 0x28, 0x05, 0xb9, 0x34, 0x05, 0x68, 0x05, 0x8a, 0x7e, 0x40,
-	0xbb, 0xec, 0x26, 0x9a, 0xd5, 0xe8, 0x84, 0x36, 0xa0, 0xa2, 0xbf, 0x5a, 0x97, 0x4c, 0x84, 0x6a,
-	0x52, 0x0b, 0x0e, 0x68, 0xd1, 0x3e, 0x13, 0x21, 0xfe, 0xcb, 0x80, 0xa5, 0xe6, 0xfc, 0xa3, 0x91,
-	0x0c, 0x73, 0xee, 0x8e, 0x61, 0x96, 0xe5, 0x0e, 0xa8, 0x20, 0x6a, 0x43, 0x14, 0xf4, 0x7a, 0x19,
-	0x9e, 0x53, 0x50, 0x8a, 0x69, 0x28, 0x7a, 0xce, 0x4e, 0xf2, 0xe5, 0x7c, 0xb5, 0x80, 0x4f, 0x60,
-	0xb9, 0x99, 0xc5, 0xe1, 0x7d, 0x1a, 0xe2, 0xd6, 0x80, 0xc7, 0xaf, 0x03, 0x26, 0xe8, 0xff, 0x8c,
-	0xd5, 0x1c, 0xc3, 0xba, 0x0d, 0x0f, 0xe9, 0x8d, 0x4f, 0xdb, 0xa2, 0x15, 0x43, 0xce, 0xab, 0x34,
-	0x8b, 0x5a, 0x3c, 0x64, 0x1f, 0x7f, 0x06, 0x2b, 0xe3, 0xf5, 0x45, 0x70, 0x47, 0xaf, 0xcb, 0x18,
-	0x1b, 0xbe, 0x4f, 0xe0, 0xc9, 0x21, 0x15, 0x69, 0xcc, 0x33, 0x71, 0xe1, 0x57, 0xf0, 0x6c, 0xdc,
-	0xe3, 0xbf, 0xe8, 0x41, 0x7c, 0x0e, 0xb5, 0xc9, 0x4a, 0x3e, 0x80, 0xb0, 0x6d, 0x58, 0x3c, 0xf6,
-	0x98, 0x64, 0xff, 0x0e, 0x40, 0x07, 0xf0, 0x30, 0x36, 0x8c, 0xf2, 0xed, 0x42, 0xa9, 0x1d, 0x50,
-	0x22, 0x68, 0x27, 0x5a, 0x38, 0xd3, 0xd3, 0x45, 0x76, 0x8d, 0xf7, 0x65, 0xa8, 0x7c, 0x1b, 0xd9,
-	0x9c, 0x11, 0x1f, 0x7d, 0x03, 0x25, 0x39, 0xa8, 0xf2, 0x71, 0x59, 0x4d, 0x9c, 0x27, 0x96, 0xbf,
-	0xb5, 0x96, 0xad, 0xd4, 0x85, 0xe0, 0x07, 0xe8, 0x8d, 0x7a, 0x31, 0xd2, 0xcb, 0x1e, 0x6d, 0x65,
-	0x39, 0x4d, 0xb0, 0x70, 0x67, 0xec, 0x53, 0x58, 0xd0, 0xb1, 0x65, 0x13, 0xae, 0x67, 0x18, 0x27,
-	0x5d, 0x6e, 0x7d, 0x3c, 0x4d, 0x1d, 0x47, 0xfb, 0x41, 0xbd, 0x92, 0xe3, 0xcf, 0x05, 0xda, 0xce,
-	0x76, 0x9c, 0xac, 0xf6, 0xee, 0x0c, 0xdf, 0x83, 0x95, 0x91, 0xe1, 0x9c, 0x5f, 0x04, 0x9c, 0x77,
-	0xe7, 0x4f, 0xb4, 0x34, 0x3e, 0x89, 0xf2, 0xc7, 0xc3, 0x03, 0x74, 0x6b, 0xa8, 0x0e, 0xcc, 0xdc,
-	0xad, 0x68, 0x27, 0x15, 0x7c, 0xd6, 0xfe, 0xb5, 0x26, 0x07, 0x1d, 0x1f, 0xfc, 0xf2, 0xf7, 0x3f,
-	0x7f, 0xe4, 0xbe, 0x44, 0x5f, 0xd8, 0xd7, 0xbb, 0x97, 0x54, 0x90, 0x5d, 0x7b, 0x40, 0xfc, 0xd0,
-	0x7e, 0xab, 0xdb, 0xf1, 0x9d, 0x2d, 0x1b, 0x3b, 0xb4, 0xdf, 0x0e, 0x67, 0xe1, 0x9d, 0xad, 0x17,
-	0xc3, 0x9e, 0x4b, 0x42, 0xd1, 0x62, 0x5e, 0x2b, 0x90, 0x99, 0x24, 0x5d, 0xcd, 0x2c, 0xba, 0x9a,
-	0xb3, 0xe9, 0x6a, 0x66, 0x5f, 0xe6, 0xaf, 0x06, 0x54, 0xc7, 0x07, 0x0e, 0x3d, 0x4b, 0xc1, 0xcc,
-	0x5a, 0x0b, 0x16, 0x9e, 0x65, 0x12, 0x45, 0x7f, 0xa9, 0xf0, 0x6e, 0xa1, 0xe7, 0xb3, 0xf0, 0xee,
-	0xb9, 0x44, 0xc8, 0xb1, 0xbc, 0x35, 0x14, 0xad, 0x53, 0x36, 0x0a, 0x7a, 0x39, 0x3d, 0xdf, 0xe4,
-	0xdd, 0xcf, 0x53, 0x9c, 0xad, 0x8a, 0xdb, 0x41, 0xdb, 0x73, 0x92, 0x81, 0xda, 0x50, 0x8a, 0x16,
-	0x04, 0xaa, 0x25, 0xf1, 0xd3, 0xcb, 0xc5, 0x7a, 0x9a, 0xa1, 0x89, 0x12, 0x3e, 0x57, 0x09, 0xd7,
-	0xf1, 0x6a, 0x76, 0xc2, 0x3d, 0xe6, 0x31, 0xd1, 0x78, 0x6f, 0x40, 0x75, 0x64, 0x7f, 0xa8, 0x55,
-	0x8e, 0xbe, 0xfb, 0xc0, 0x91, 0x9a, 0xd2, 0xe9, 0x0e, 0x54, 0x54, 0xfc, 0xa8, 0x95, 0x36, 0x12,
-	0xab, 0xcc, 0x17, 0xce, 0xda, 0x9c, 0x6e, 0x30, 0x6c, 0xa7, 0xfd, 0x73, 0x78, 0xda, 0xe6, 0x83,
-	0xba, 0xfe, 0x53, 0x51, 0x4f, 0xff, 0xd7, 0xd8, 0x5f, 0x1a, 0x41, 0xf6, 0xb5, 0xcf, 0x2e, 0xa4,
-	0xf0, 0xc2, 0x78, 0x63, 0xf5, 0x98, 0xe8, 0x5f, 0x5d, 0xd6, 0xdb, 0x7c, 0x60, 0x47, 0xff, 0x46,
-	0x86, 0x8e, 0x97, 0x45, 0xe5, 0xf9, 0xe9, 0xbf, 0x01, 0x00, 0x00, 0xff, 0xff, 0xdf, 0x22, 0xc9,
-	0xc2, 0xd9, 0x0c, 0x00, 0x00,
+	// 1262 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x58, 0x5f, 0x4f, 0x1b, 0x47,
+	0x10, 0xcf, 0xf9, 0xfc, 0x77, 0x4c, 0xc0, 0x59, 0xfe, 0xc4, 0x39, 0xa0, 0x90, 0x43, 0x88, 0x20,
+	0x24, 0x1b, 0x68, 0x9a, 0x07, 0x54, 0x55, 0x2d, 0x42, 0x25, 0x50, 0x40, 0xe8, 0xdc, 0x26, 0x52,
+	0x54, 0xc9, 0x5d, 0xec, 0xb5, 0x59, 0xc9, 0xbe, 0xbd, 0xde, 0x2d, 0x84, 0x12, 0xe5, 0xa5, 0x52,
+	0xdb, 0xa7, 0x3e, 0x34, 0x55, 0x9f, 0x2a, 0xf1, 0xde, 0x0f, 0x91, 0x4f, 0xd1, 0xaf, 0xd0, 0x0f,
+	0x52, 0xed, 0xee, 0x9d, 0x7d, 0x3e, 0x9f, 0xff, 0x14, 0x9a, 0x37, 0xdf, 0xcc, 0xce, 0xcc, 0x6f,
+	0x67, 0x7e, 0x33, 0xb3, 0x32, 0xcc, 0x71, 0x97, 0xb6, 0x5a, 0x14, 0xdb, 0xd5, 0x36, 0x76, 0xaa,
+	0xd8, 0xa1, 0x25, 0xc7, 0x65, 0x9c, 0xa1, 0x6c, 0x20, 0x37, 0x26, 0x83, 0x5f, 0x4a, 0x63, 0x2c,
+	0x34, 0x19, 0x6b, 0xb6, 0x48, 0x19, 0x3b, 0xb4, 0x8c, 0x6d, 0x9b, 0x71, 0xcc, 0x29, 0xb3, 0x3d,
+	0xa5, 0x35, 0xaf, 0x21, 0x73, 0x8c, 0x9d, 0x23, 0x82, 0x1b, 0x68, 0x06, 0x52, 0xd4, 0xae, 0x93,
+	0xab, 0xa2, 0xb6, 0xac, 0x3d, 0x99, 0xb0, 0xd4, 0x07, 0x9a, 0x87, 0x5c, 0x8b, 0xe0, 0x46, 0xf5,
+	0x1c, 0x7b, 0xe7, 0xc5, 0x84, 0xd4, 0x64, 0x85, 0xe0, 0x39, 0xf6, 0xce, 0xd1, 0x22, 0x80, 0x54,
+	0x5e, 0xe2, 0xd6, 0x05, 0x29, 0xea, 0x52, 0x2b, 0x8f, 0xbf, 0x10, 0x02, 0xa1, 0x26, 0x57, 0xdc,
+	0xc5, 0xd5, 0x3a, 0xe6, 0xb8, 0x98, 0x54, 0x6a, 0x29, 0xd9, 0xc3, 0x1c, 0x9b, 0xcf, 0x20, 0xa7,
+	0x62, 0x5f, 0x12, 0x0f, 0xad, 0x43, 0xba, 0x25, 0x7f, 0x15, 0xb5, 0x65, 0xfd, 0x49, 0x7e, 0xfb,
+	0x41, 0xa9, 0x73, 0x0f, 0x1f, 0xa0, 0xe5, 0x1f, 0x30, 0x5f, 0x42, 0xc1, 0x17, 0x1d, 0xd8, 0xb5,
+	0xd6, 0x85, 0x47, 0x99, 0x8d, 0x56, 0x21, 0x29, 0xe2, 0x4a, 0xec, 0xb1, 0xc6, 0x52, 0x8d, 0x16,
+	0x20, 0x47, 0x03, 0x9b, 0x62, 0x62, 0x59, 0x17, 0x80, 0x3a, 0x02, 0xb3, 0x0d, 0xd3, 0xfb, 0x84,
+	0x77, 0x30, 0x59, 0xe4, 0xfb, 0x0b, 0xe2, 0x71, 0x34, 0x0b, 0x69, 0x91, 0x6c, 0x5a, 0x97, 0xde,
+	0x75, 0x2b, 0xd5, 0xc6, 0xce, 0x41, 0xbd, 0x9b, 0x2f, 0xe5, 0x47, 0x7d, 0xa0, 0x15, 0xb8, 0x5f,
+	0x63, 0x6d, 0x07, 0xd7, 0x78, 0xd5, 0x71, 0x19, 0x6b, 0xc8, 0x6b, 0x67, 0xad, 0x09, 0x5f, 0x78,
+	0x2a, 0x64, 0x87, 0xc9, 0xac, 0x5e, 0x48, 0x9a, 0x9f, 0xc3, 0x83, 0x4e, 0xb8, 0xc6, 0xf8, 0xc1,
+	0xba, 0xc5, 0x31, 0x1b, 0x30, 0xdf, 0xf5, 0xb0, 0xfb, 0x83, 0x45, 0x2e, 0xa9, 0xb8, 0xc8, 0x6d,
+	0x7c, 0x21, 0x03, 0xb2, 0xae, 0x6f, 0x2f, 0x2b, 0xa9, 0x5b, 0x9d, 0x6f, 0xf3, 0x57, 0x0d, 0x16,
+	0xc3, 0x99, 0xb9, 0x4d, 0x28, 0x7d, 0xac, 0x50, 0x63, 0xe5, 0xcf, 0x7c, 0xa7, 0x01, 0x0a, 0xa7,
+	0xce, 0x73, 0x98, 0xed, 0x11, 0xf4, 0x1c, 0x90, 0x00, 0x21, 0x29, 0xd9, 0x2d, 0xb3, 0xa2, 0x84,
+	0xd1, 0x47, 0x89, 0x0e, 0x79, 0xac, 0x42, 0x3b, 0x22, 0x41, 0xdb, 0x90, 0x15, 0x9e, 0x5c, 0xc6,
+	0xb8, 0xcc, 0x52, 0x7e, 0xfb, 0x61, 0xd7, 0xbe, 0x42, 0x9b, 0x36, 0xa9, 0x1f, 0x63, 0xc7, 0x62,
+	0x8c, 0x5b, 0x99, 0xb6, 0xfa, 0x61, 0xbe, 0xd7, 0x60, 0xa6, 0x97, 0x3e, 0x43, 0x61, 0x25, 0x96,
+	0xf5, 0x3b, 0xc1, 0xd2, 0xc7, 0x83, 0x85, 0xd6, 0x60, 0xea, 0x0c, 0xf3, 0xda, 0x79, 0x28, 0x74,
+	0x52, 0x16, 0x63, 0x52, 0x8a, 0x3b, 0xce, 0x45, 0x91, 0x97, 0xf6, 0x09, 0x3f, 0xc2, 0x1e, 0x3f,
+	0xb0, 0x2d, 0x6c, 0x37, 0xc9, 0xd8, 0x65, 0x0e, 0x17, 0x34, 0x11, 0x29, 0xe8, 0x1c, 0xa4, 0x1d,
+	0x97, 0x34, 0xe8, 0x95, 0x3f, 0x1f, 0xfc, 0x2f, 0xb4, 0x04, 0x79, 0xf5, 0xab, 0x7a, 0x46, 0xb9,
+	0x27, 0xcb, 0x9c, 0xb2, 0x40, 0x89, 0x76, 0x29, 0xf7, 0xcc, 0x5f, 0x34, 0x98, 0x39, 0xa2, 0xde,
+	0xd8, 0xfd, 0x38, 0x0c, 0xc4, 0x12, 0xe4, 0x3d, 0x8e, 0x5d, 0x5e, 0x55, 0x6c, 0x54, 0x48, 0x40,
+	0x8a, 0x0e, 0x82, 0x31, 0xe7, 0xe0, 0x26, 0xa9, 0x7a, 0xf4, 0x9a, 0xf8, 0x58, 0xb2, 0x42, 0x50,
+	0xa1, 0xd7, 0xc4, 0xfc, 0x43, 0x83, 0xd9, 0x08, 0x12, 0xbf, 0xb4, 0xe3, 0x4f, 0x2d, 0x31, 0x0c,
+	0x6d, 0x72, 0x15, 0x20, 0x50, 0xad, 0x97, 0x13, 0x12, 0x05, 0xe0, 0x16, 0xa5, 0x35, 0x9d, 0x60,
+	0x80, 0xec, 0xd1, 0xc6, 0xa8, 0x01, 0xb2, 0x02, 0xf7, 0x1b, 0x2e, 0x6b, 0x57, 0x23, 0x29, 0x9a,
+	0x10, 0x42, 0x2b, 0x94, 0x26, 0xce, 0xaa, 0x91, 0xde, 0x04, 0xce, 0x82, 0x03, 0xe6, 0x6f, 0x1a,
+	0xe4, 0xfd, 0x8b, 0x89, 0x98, 0x03, 0x76, 0xc6, 0x27, 0x90, 0x65, 0xad, 0xba, 0x24, 0xbc, 0xdf,
+	0x3d, 0xc3, 0x68, 0x9e, 0x61, 0xad, 0xba, 0x90, 0x08, 0x33, 0x9b, 0xbc, 0x56, 0x66, 0xfa, 0x68,
+	0x33, 0x9b, 0xbc, 0x16, 0x12, 0xf3, 0xaf, 0xce, 0x30, 0x50, 0x69, 0xf0, 0x4b, 0xf3, 0x14, 0x72,
+	0xea, 0xc2, 0x22, 0xa3, 0xda, 0xf0, 0x8c, 0x66, 0x65, 0x16, 0x44, 0xb7, 0x6c, 0x42, 0x86, 0x33,
+	0x65, 0x33, 0xa2, 0xef, 0xd3, 0x9c, 0x49, 0x8b, 0x0d, 0x48, 0xd5, 0x69, 0xa3, 0xe1, 0x15, 0x75,
+	0xc9, 0x80, 0xd9, 0x3e, 0xc8, 0x12, 0x95, 0x3a, 0x63, 0xfe, 0xa9, 0xc1, 0x74, 0x65, 0xfc, 0x15,
+	0xd3, 0xa5, 0x57, 0x62, 0x14, 0xbd, 0x0c, 0xc8, 0xb6, 0x09, 0xc7, 0x72, 0xd3, 0xa6, 0xd4, 0x9a,
+	0x0e, 0xbe, 0x7b, 0x3a, 0x23, 0xdd, 0xdb, 0x19, 0x6a, 0x15, 0x1d, 0x26, 0xb3, 0xc9, 0x42, 0xca,
+	0x3c, 0x84, 0x99, 0x4a, 0xdc, 0x00, 0xbb, 0xcd, 0x34, 0xbc, 0xd1, 0x60, 0xf6, 0xa5, 0x4b, 0x39,
+	0xf9, 0xc0, 0x77, 0xd5, 0x23, 0x77, 0x5d, 0x83, 0x29, 0x72, 0xe5, 0x90, 0x1a, 0xef, 0xd2, 0x38,
+	0x29, 0xc3, 0x4c, 0x2a, 0x71, 0x87, 0xca, 0x4f, 0x61, 0x2e, 0x8a, 0xcf, 0xbf, 0x6e, 0x38, 0x5d,
+	0x5a, 0x64, 0x13, 0x6e, 0xc2, 0xc3, 0x7d, 0xc2, 0x7b, 0xef, 0x3c, 0xf4, 0x5e, 0xe6, 0x0b, 0x78,
+	0x1c, 0xb5, 0xf8, 0x3f, 0xe6, 0xaa, 0x79, 0x02, 0xc5, 0x7e, 0x24, 0x77, 0x28, 0xd8, 0x1a, 0x4c,
+	0x1e, 0xd8, 0x54, 0x54, 0x7f, 0xc4, 0x85, 0xf6, 0x60, 0xaa, 0x73, 0xd0, 0x8f, 0xb7, 0x05, 0x99,
+	0x9a, 0x4b, 0x30, 0x27, 0xf5, 0x51, 0x9d, 0x16, 0x9c, 0xdb, 0x7e, 0x07, 0x90, 0xff, 0xda, 0x3f,
+	0x73, 0x8c, 0x1d, 0xf4, 0x25, 0x64, 0xc4, 0xf2, 0x11, 0x73, 0x60, 0xbe, 0x6b, 0xdc, 0xf7, 0x3e,
+	0x32, 0x16, 0xe2, 0x95, 0x0a, 0x88, 0x79, 0x0f, 0xbd, 0x92, 0x33, 0xb1, 0xf7, 0x3d, 0x84, 0x56,
+	0xe3, 0x8c, 0xfa, 0xaa, 0x30, 0xd2, 0xf7, 0x11, 0xe4, 0x94, 0x6f, 0x39, 0xcf, 0x63, 0x0e, 0x77,
+	0x59, 0x6e, 0x7c, 0x34, 0x48, 0xdd, 0xf1, 0xf6, 0x9d, 0x7c, 0x6d, 0x46, 0x1f, 0x54, 0x68, 0x2d,
+	0xde, 0xb0, 0x1f, 0xed, 0xe8, 0x08, 0xdf, 0x82, 0x11, 0x13, 0xe1, 0x84, 0xc9, 0x47, 0xd4, 0xf8,
+	0x81, 0xa6, 0xa3, 0x9d, 0x28, 0x1e, 0xe1, 0xf7, 0xd0, 0x8d, 0x06, 0xc5, 0x41, 0xef, 0x05, 0xb4,
+	0xde, 0xe3, 0x7c, 0xd8, 0x9b, 0xc2, 0xe8, 0x6f, 0x74, 0x73, 0xef, 0xc7, 0xbf, 0xff, 0xf9, 0x3d,
+	0xf1, 0x19, 0xfa, 0xb4, 0x7c, 0xb9, 0x75, 0x46, 0x38, 0xde, 0x2a, 0xb7, 0xb1, 0xe3, 0x95, 0xdf,
+	0x28, 0x3a, 0xbe, 0x2d, 0x0b, 0x62, 0x7b, 0xe5, 0x37, 0x41, 0x2f, 0xbc, 0x2d, 0xab, 0xc1, 0xb0,
+	0xd3, 0xc2, 0x9e, 0x58, 0xb0, 0x55, 0x57, 0x44, 0x42, 0x3f, 0x69, 0x00, 0x62, 0x6d, 0xfb, 0x05,
+	0x0b, 0xe5, 0x2b, 0xee, 0x59, 0x61, 0x2c, 0x0d, 0xd4, 0xfb, 0x09, 0x7d, 0x26, 0x51, 0x6d, 0xa2,
+	0xd2, 0x7f, 0x43, 0x85, 0xbe, 0x02, 0xe8, 0xee, 0xa7, 0x7e, 0x76, 0x87, 0x96, 0xb7, 0xb1, 0x10,
+	0xaf, 0x0c, 0x2a, 0xba, 0xa9, 0x09, 0x0e, 0x56, 0xe2, 0x38, 0x58, 0x19, 0xce, 0xc1, 0x4a, 0x3c,
+	0x43, 0x7e, 0xd6, 0xa0, 0x10, 0x9d, 0x22, 0xe8, 0x71, 0x0f, 0x88, 0xb8, 0x59, 0x67, 0x98, 0xc3,
+	0x8e, 0xf8, 0xde, 0x37, 0x64, 0xba, 0x56, 0xd1, 0xca, 0xb0, 0x74, 0xed, 0xb4, 0x30, 0x17, 0xb3,
+	0xe6, 0x46, 0x03, 0x23, 0xea, 0x29, 0x44, 0xa7, 0x8d, 0xc1, 0xf1, 0xfa, 0x09, 0x35, 0x0e, 0xb8,
+	0xb2, 0x04, 0xb7, 0x8e, 0xd6, 0xc6, 0xac, 0x25, 0xaa, 0x41, 0xc6, 0x9f, 0x7a, 0xa8, 0xd8, 0xf5,
+	0xdf, 0x3b, 0x31, 0x8d, 0x47, 0x31, 0x1a, 0x3f, 0xe0, 0x8a, 0x0c, 0xb8, 0x68, 0xce, 0xc7, 0x07,
+	0xdc, 0xa1, 0x36, 0xe5, 0xdb, 0xef, 0x35, 0x28, 0x84, 0x86, 0xa2, 0xdc, 0x4f, 0xe8, 0x9b, 0x3b,
+	0xce, 0x89, 0x01, 0xed, 0x6b, 0x41, 0x5e, 0xfa, 0x57, 0x02, 0x14, 0x62, 0x7f, 0xec, 0xda, 0x36,
+	0x96, 0x07, 0x1f, 0x08, 0xe8, 0xb4, 0x7b, 0x02, 0x8f, 0x6a, 0xac, 0x5d, 0x52, 0xff, 0x38, 0x94,
+	0x7a, 0xff, 0x88, 0xd8, 0x9d, 0x0e, 0xdd, 0xec, 0x0b, 0x87, 0x9e, 0x0a, 0xe1, 0xa9, 0xf6, 0xca,
+	0x68, 0x52, 0x7e, 0x7e, 0x71, 0x56, 0xaa, 0xb1, 0x76, 0x59, 0x19, 0x96, 0x03, 0xc3, 0xb3, 0xb4,
+	0xb4, 0xfc, 0xf8, 0xdf, 0x01, 0x00, 0xb4, 0xbb, 0xab, 0x23, 0xf6, 0x10, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1107,6 +1468,14 @@ type TrillianMapClient interface {
 	GetLeavesByRevisionNoProof(ctx context.Context, in *GetMapLeavesByRevisionRequest, opts ...grpc.CallOption) (*MapLeaves, error)
 	// GetLastInRangeByRevision returns the last leaf in a requested range.
 	GetLastInRangeByRevision(ctx context.Context, in *GetLastInRangeByRevisionRequest, opts ...grpc.CallOption) (*MapLeaf, error)
+	// ListLeaves returns the leaves of the map at a revision in increasing
+	// index order, one page at a time. It allows mirrors and auditors to
+	// download the full contents of a map and recompute its root.
+	ListLeaves(ctx context.Context, in *ListMapLeavesRequest, opts ...grpc.CallOption) (*ListMapLeavesResponse, error)
+	// GetMapDiff streams the leaves whose values changed between two revisions
+	// of the map, in increasing index order. Each leaf comes with inclusion
+	// proofs against the map roots at both revisions.
+	GetMapDiff(ctx context.Context, in *GetMapDiffRequest, opts ...grpc.CallOption) (TrillianMap_GetMapDiffClient, error)
 	// SetLeaves sets the values for the provided leaves, and returns the new map
 	// root if successful. Note that if a SetLeaves request fails for a
 	// server-side reason (i.e. not an invalid request), the API user is required
@@ -1179,6 +1548,47 @@ func (c *trillianMapClient) GetLastInRangeByRevision(ctx context.Context, in *Ge
 	return out, nil
 }
 
+func (c *trillianMapClient) ListLeaves(ctx context.Context, in *ListMapLeavesRequest, opts ...grpc.CallOption) (*ListMapLeavesResponse, error) {
+	out := new(ListMapLeavesResponse)
+	err := c.cc.Invoke(ctx, "/trillian.TrillianMap/ListLeaves", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trillianMapClient) GetMapDiff(ctx context.Context, in *GetMapDiffRequest, opts ...grpc.CallOption) (TrillianMap_GetMapDiffClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TrillianMap_serviceDesc.Streams[0], "/trillian.TrillianMap/GetMapDiff", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &trillianMapGetMapDiffClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TrillianMap_GetMapDiffClient interface {
+	Recv() (*GetMapDiffResponse, error)
+	grpc.ClientStream
+}
+
+type trillianMapGetMapDiffClient struct {
+	grpc.ClientStream
+}
+
+func (x *trillianMapGetMapDiffClient) Recv() (*GetMapDiffResponse, error) {
+	m := new(GetMapDiffResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *trillianMapClient) SetLeaves(ctx context.Context, in *SetMapLeavesRequest, opts ...grpc.CallOption) (*SetMapLeavesResponse, error) {
 	out := new(SetMapLeavesResponse)
 	err := c.cc.Invoke(ctx, "/trillian.TrillianMap/SetLeaves", in, out, opts...)
@@ -1229,6 +1639,14 @@ type TrillianMapServer interface {
 	GetLeavesByRevisionNoProof(context.Context, *GetMapLeavesByRevisionRequest) (*MapLeaves, error)
 	// GetLastInRangeByRevision returns the last leaf in a requested range.
 	GetLastInRangeByRevision(context.Context, *GetLastInRangeByRevisionRequest) (*MapLeaf, error)
+	// ListLeaves returns the leaves of the map at a revision in increasing
+	// index order, one page at a time. It allows mirrors and auditors to
+	// download the full contents of a map and recompute its root.
+	ListLeaves(context.Context, *ListMapLeavesRequest) (*ListMapLeavesResponse, error)
+	// GetMapDiff streams the leaves whose values changed between two revisions
+	// of the map, in increasing index order. Each leaf comes with inclusion
+	// proofs against the map roots at both revisions.
+	GetMapDiff(*GetMapDiffRequest, TrillianMap_GetMapDiffServer) error
 	// SetLeaves sets the values for the provided leaves, and returns the new map
 	// root if successful. Note that if a SetLeaves request fails for a
 	// server-side reason (i.e. not an invalid request), the API user is required
@@ -1261,6 +1679,12 @@ func (*UnimplementedTrillianMapServer) GetLeavesByRevisionNoProof(ctx context.Co
 func (*UnimplementedTrillianMapServer) GetLastInRangeByRevision(ctx context.Context, req *GetLastInRangeByRevisionRequest) (*MapLeaf, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetLastInRangeByRevision not implemented")
 }
+func (*UnimplementedTrillianMapServer) ListLeaves(ctx context.Context, req *ListMapLeavesRequest) (*ListMapLeavesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLeaves not implemented")
+}
+func (*UnimplementedTrillianMapServer) GetMapDiff(req *GetMapDiffRequest, srv TrillianMap_GetMapDiffServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetMapDiff not implemented")
+}
 func (*UnimplementedTrillianMapServer) SetLeaves(ctx context.Context, req *SetMapLeavesRequest) (*SetMapLeavesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SetLeaves not implemented")
 }
@@ -1386,6 +1810,45 @@ func _TrillianMap_GetLastInRangeByRevision_Handler(srv interface{}, ctx context.
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TrillianMap_ListLeaves_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMapLeavesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrillianMapServer).ListLeaves(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/trillian.TrillianMap/ListLeaves",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrillianMapServer).ListLeaves(ctx, req.(*ListMapLeavesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrillianMap_GetMapDiff_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetMapDiffRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TrillianMapServer).GetMapDiff(m, &trillianMapGetMapDiffServer{stream})
+}
+
+type TrillianMap_GetMapDiffServer interface {
+	Send(*GetMapDiffResponse) error
+	grpc.ServerStream
+}
+
+type trillianMapGetMapDiffServer struct {
+	grpc.ServerStream
+}
+
+func (x *trillianMapGetMapDiffServer) Send(m *GetMapDiffResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 func _TrillianMap_SetLeaves_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SetMapLeavesRequest)
 	if err := dec(in); err != nil {
@@ -1486,6 +1949,10 @@ var _TrillianMap_serviceDesc = grpc.ServiceDesc{
 			MethodName: "GetLastInRangeByRevision",
 			Handler:    _TrillianMap_GetLastInRangeByRevision_Handler,
 		},
+		{
+			MethodName: "ListLeaves",
+			Handler:    _TrillianMap_ListLeaves_Handler,
+		},
 		{
 			MethodName: "SetLeaves",
 			Handler:    _TrillianMap_SetLeaves_Handler,
@@ -1503,7 +1970,13 @@ var _TrillianMap_serviceDesc = grpc.ServiceDesc{
 			Handler:    _TrillianMap_InitMap_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GetMapDiff",
+			Handler:       _TrillianMap_GetMapDiff_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "trillian_map_api.proto",
 }
 
@@ -107,3 +107,36 @@ func ParseKeyHint(hint []byte) (int64, error) {
 	}
 	return keyID, nil
 }
+
+// SerializeKeyVersionHint returns a key hint identifying version keyID of the
+// signing key of tree treeID. The hint for key version zero is the same as
+// SerializeKeyHint(treeID); for later versions, keyID is appended to it as a
+// big endian uint64.
+func SerializeKeyVersionHint(treeID, keyID int64) []byte {
+	hint := SerializeKeyHint(treeID)
+	if keyID == 0 {
+		return hint
+	}
+	return append(hint, SerializeKeyHint(keyID)...)
+}
+
+// ParseKeyVersionHint converts a key hint produced by SerializeKeyVersionHint
+// (or SerializeKeyHint) into a tree ID and key ID.
+func ParseKeyVersionHint(hint []byte) (int64, int64, error) {
+	switch len(hint) {
+	case 8:
+		treeID, err := ParseKeyHint(hint)
+		return treeID, 0, err
+	case 16:
+		treeID, err := ParseKeyHint(hint[:8])
+		if err != nil {
+			return 0, 0, err
+		}
+		keyID, err := ParseKeyHint(hint[8:])
+		if err != nil {
+			return 0, 0, err
+		}
+		return treeID, keyID, nil
+	}
+	return 0, 0, fmt.Errorf("hint is %v bytes, want %v or %v", len(hint), 8, 16)
+}
@@ -122,3 +122,35 @@ func TestKeyHint(t *testing.T) {
 		}
 	}
 }
+
+func TestKeyVersionHint(t *testing.T) {
+	for _, tc := range []struct {
+		hint       []byte
+		wantTreeID int64
+		wantKeyID  int64
+		errStr     string
+	}{
+		{hint: SerializeKeyVersionHint(4, 0), wantTreeID: 4},
+		{hint: SerializeKeyHint(4), wantTreeID: 4},
+		{hint: SerializeKeyVersionHint(4, 2), wantTreeID: 4, wantKeyID: 2},
+		{hint: SerializeKeyVersionHint(3561657513447883733, 7), wantTreeID: 3561657513447883733, wantKeyID: 7},
+		{hint: []byte{0, 0, 0, 0, 0, 0, 0, 4, 0, 0, 0, 0, 0, 0, 0, 1}, wantTreeID: 4, wantKeyID: 1},
+		{hint: []byte{0, 0, 0, 0, 0, 0, 0, 4, 0xff, 0, 0, 0, 0, 0, 0, 1}, errStr: "is negative"},
+		{hint: []byte{0, 0, 0, 0, 0, 0, 0, 4, 0}, errStr: "9 bytes, want 8 or 16"},
+	} {
+		treeID, keyID, err := ParseKeyVersionHint(tc.hint)
+		if len(tc.errStr) > 0 {
+			if err == nil || !strings.Contains(err.Error(), tc.errStr) {
+				t.Errorf("ParseKeyVersionHint(%v): %v, want err containing %s", tc.hint, err, tc.errStr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseKeyVersionHint(%v): %v", tc.hint, err)
+			continue
+		}
+		if treeID != tc.wantTreeID || keyID != tc.wantKeyID {
+			t.Errorf("ParseKeyVersionHint(%v): %v, %v, want: %v, %v", tc.hint, treeID, keyID, tc.wantTreeID, tc.wantKeyID)
+		}
+	}
+}
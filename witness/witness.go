@@ -0,0 +1,121 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package witness implements a log witness. A witness watches a Trillian log,
+// and cosigns each log root that it has verified to be consistent with all
+// the roots of the log it has seen before. The log serves the cosignatures
+// alongside its roots, so clients can require roots to be cosigned by enough
+// independent witnesses (see client.WitnessVerifier). A log that shows
+// different views of itself to different parties can't then get both views
+// cosigned by the same honest witness.
+package witness
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"fmt"
+	"sync"
+
+	"github.com/google/trillian"
+	"github.com/google/trillian/client"
+	"github.com/google/trillian/types"
+
+	tcrypto "github.com/google/trillian/crypto"
+)
+
+// Witness watches a single log, and cosigns the roots it has verified.
+type Witness struct {
+	name     string
+	logID    int64
+	client   trillian.TrillianLogClient
+	verifier *client.LogVerifier
+	signer   *tcrypto.Signer
+
+	// mu serializes updates, and guards trusted.
+	mu      sync.Mutex
+	trusted types.LogRootV1
+}
+
+// New returns a Witness called name, which watches the log with ID logID
+// through cl, verifies its roots with v, and cosigns them with signer.
+//
+// trusted is the latest root of the log that the witness has verified, e.g.
+// as saved by a previous run, or a zero root for a new witness, which trusts
+// the first root it sees. Witnesses must not forget the roots they have
+// cosigned, or they may be tricked into cosigning an inconsistent view.
+func New(name string, logID int64, cl trillian.TrillianLogClient, v *client.LogVerifier, signer crypto.Signer, trusted types.LogRootV1) *Witness {
+	return &Witness{
+		name:     name,
+		logID:    logID,
+		client:   cl,
+		verifier: v,
+		signer:   tcrypto.NewSigner(0, signer, crypto.SHA256),
+		trusted:  trusted,
+	}
+}
+
+// Trusted returns the latest log root verified by the witness.
+func (w *Witness) Trusted() types.LogRootV1 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.trusted
+}
+
+// Update fetches the latest root of the log and verifies it, including its
+// consistency with the trusted root. If it is valid, the root becomes the
+// trusted root, and is cosigned and sent back to the log. Update returns the
+// new trusted root; it is kept even if sending the cosignature fails.
+//
+// If persist is not nil, it is called with each new trusted root before the
+// root is cosigned, so that the witness doesn't forget a root it has cosigned
+// if it crashes. If persist fails, the root is neither trusted nor cosigned.
+func (w *Witness) Update(ctx context.Context, persist func(types.LogRootV1) error) (*types.LogRootV1, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	req := &trillian.GetLatestSignedLogRootRequest{
+		LogId:         w.logID,
+		FirstTreeSize: int64(w.trusted.TreeSize),
+	}
+	resp, err := w.client.GetLatestSignedLogRoot(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	slr := resp.GetSignedLogRoot()
+	root, err := w.verifier.VerifyRoot(&w.trusted, slr, resp.GetProof().GetHashes())
+	if err != nil {
+		return nil, fmt.Errorf("witness: log %d: %v", w.logID, err)
+	}
+	if persist != nil && (root.TreeSize != w.trusted.TreeSize || !bytes.Equal(root.RootHash, w.trusted.RootHash)) {
+		if err := persist(*root); err != nil {
+			return nil, fmt.Errorf("witness: log %d: failed to persist root: %v", w.logID, err)
+		}
+	}
+	w.trusted = *root
+
+	sig, err := w.signer.CosignLogRoot(w.logID, slr)
+	if err != nil {
+		return nil, fmt.Errorf("witness: log %d: failed to cosign root: %v", w.logID, err)
+	}
+	cosigReq := &trillian.AddLogRootCosignatureRequest{
+		LogId:         w.logID,
+		SignedLogRoot: slr,
+		Cosignature:   &trillian.Cosignature{Witness: w.name, Signature: sig},
+	}
+	if _, err := w.client.AddLogRootCosignature(ctx, cosigReq); err != nil {
+		return nil, fmt.Errorf("witness: log %d: failed to add cosignature: %v", w.logID, err)
+	}
+	return root, nil
+}
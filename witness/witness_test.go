@@ -0,0 +1,173 @@
+// Copyright 2019 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package witness
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/trillian"
+	"github.com/google/trillian/client"
+	"github.com/google/trillian/merkle"
+	"github.com/google/trillian/merkle/rfc6962"
+	"github.com/google/trillian/types"
+	"google.golang.org/grpc"
+
+	tcrypto "github.com/google/trillian/crypto"
+)
+
+const logID = 42
+
+// fakeLog is a TrillianLogClient serving the roots of an in-memory log, and
+// recording the cosignatures it is sent.
+type fakeLog struct {
+	trillian.TrillianLogClient
+	signer *tcrypto.Signer
+	tree   *merkle.InMemoryMerkleTree
+	// size is the tree size of the root served, if not zero.
+	size int64
+	// rootHash overrides the root hash served, if set.
+	rootHash []byte
+
+	cosigs []*trillian.AddLogRootCosignatureRequest
+}
+
+func (f *fakeLog) GetLatestSignedLogRoot(ctx context.Context, req *trillian.GetLatestSignedLogRootRequest, opts ...grpc.CallOption) (*trillian.GetLatestSignedLogRootResponse, error) {
+	size := f.tree.LeafCount()
+	if f.size != 0 {
+		size = f.size
+	}
+	rootHash := f.tree.RootAtSnapshot(size).Hash()
+	if f.rootHash != nil {
+		rootHash = f.rootHash
+	}
+	slr, err := f.signer.SignLogRoot(&types.LogRootV1{TreeSize: uint64(size), RootHash: rootHash})
+	if err != nil {
+		return nil, err
+	}
+	resp := &trillian.GetLatestSignedLogRootResponse{SignedLogRoot: slr}
+	if first := req.FirstTreeSize; first > 0 && first <= size {
+		resp.Proof = &trillian.Proof{}
+		for _, node := range f.tree.SnapshotConsistency(first, size) {
+			resp.Proof.Hashes = append(resp.Proof.Hashes, node.Value.Hash())
+		}
+	}
+	return resp, nil
+}
+
+func (f *fakeLog) AddLogRootCosignature(ctx context.Context, req *trillian.AddLogRootCosignatureRequest, opts ...grpc.CallOption) (*trillian.AddLogRootCosignatureResponse, error) {
+	f.cosigs = append(f.cosigs, req)
+	return &trillian.AddLogRootCosignatureResponse{}, nil
+}
+
+func (f *fakeLog) addLeaves(n int) {
+	for i := 0; i < n; i++ {
+		f.tree.AddLeaf([]byte(fmt.Sprintf("leaf %d", f.tree.LeafCount())))
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	ctx := context.Background()
+	logKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey(): %v", err)
+	}
+	witnessKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey(): %v", err)
+	}
+
+	log := &fakeLog{
+		signer: tcrypto.NewSigner(0, logKey, crypto.SHA256),
+		tree:   merkle.NewInMemoryMerkleTree(rfc6962.DefaultHasher),
+	}
+	verifier := client.NewLogVerifier(rfc6962.DefaultHasher, logKey.Public(), crypto.SHA256)
+	w := New("alice", logID, log, verifier, witnessKey, types.LogRootV1{})
+
+	// persisted is the latest root passed to the persist callback.
+	var persisted types.LogRootV1
+
+	// The tests run in order, as each update changes the trusted root.
+	for _, test := range []struct {
+		desc       string
+		add        int
+		size       int64
+		rootHash   []byte
+		persistErr error
+		wantErr    bool
+		wantSize   uint64
+	}{
+		{desc: "first", add: 3, wantSize: 3},
+		{desc: "grown", add: 5, wantSize: 8},
+		{desc: "unchanged", wantSize: 8},
+		{desc: "rollback", size: 5, wantErr: true, wantSize: 8},
+		{desc: "fork", add: 2, rootHash: []byte("fork"), wantErr: true, wantSize: 8},
+		{desc: "persistFails", persistErr: errors.New("disk full"), wantErr: true, wantSize: 8},
+		{desc: "recovered", wantSize: 10},
+	} {
+		log.addLeaves(test.add)
+		log.size, log.rootHash = test.size, test.rootHash
+		cosigs := len(log.cosigs)
+
+		persist := func(root types.LogRootV1) error {
+			if test.persistErr != nil {
+				return test.persistErr
+			}
+			persisted = root
+			return nil
+		}
+		root, err := w.Update(ctx, persist)
+		if gotErr := err != nil; gotErr != test.wantErr {
+			t.Errorf("%v: Update(): %v, wantErr %v", test.desc, err, test.wantErr)
+		}
+		if got := w.Trusted().TreeSize; got != test.wantSize {
+			t.Errorf("%v: Trusted().TreeSize=%d, want %d", test.desc, got, test.wantSize)
+		}
+		// Cosigned roots must have been persisted.
+		if got := persisted.TreeSize; got != test.wantSize {
+			t.Errorf("%v: persisted root of size %d, want %d", test.desc, got, test.wantSize)
+		}
+		if err != nil {
+			if got := len(log.cosigs); got != cosigs {
+				t.Errorf("%v: Update() failed but sent %d cosignatures", test.desc, got-cosigs)
+			}
+			continue
+		}
+
+		if got := len(log.cosigs); got != cosigs+1 {
+			t.Fatalf("%v: Update() sent %d cosignatures, want 1", test.desc, got-cosigs)
+		}
+		req := log.cosigs[len(log.cosigs)-1]
+		if got, want := req.Cosignature.Witness, "alice"; got != want {
+			t.Errorf("%v: cosignature by %q, want %q", test.desc, got, want)
+		}
+		gotRoot, err := tcrypto.VerifySignedLogRoot(logKey.Public(), crypto.SHA256, req.SignedLogRoot)
+		if err != nil {
+			t.Fatalf("%v: VerifySignedLogRoot(): %v", test.desc, err)
+		}
+		if gotRoot.TreeSize != root.TreeSize {
+			t.Errorf("%v: cosigned root of size %d, want %d", test.desc, gotRoot.TreeSize, root.TreeSize)
+		}
+		if err := tcrypto.VerifyCosignature(witnessKey.Public(), crypto.SHA256, logID, req.SignedLogRoot, req.Cosignature.Signature); err != nil {
+			t.Errorf("%v: VerifyCosignature(): %v", test.desc, err)
+		}
+	}
+}